@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+// NewQueryCmd returns the query commands of the LCP client
+func NewQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        lcptypes.ModuleName,
+		Short:                      "Querying commands for the LCP client",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+	cmd.AddCommand(
+		NewConsensusSignerCmd(),
+	)
+	return cmd
+}
+
+// NewConsensusSignerCmd returns the command to query the enclave key that signed the consensus state at the given height
+func NewConsensusSignerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "consensus-signer [client-id] [height]",
+		Short:   "Query the enclave key that signed the consensus state at the given height",
+		Example: fmt.Sprintf("%s query %s consensus-signer %s-0 0-100", "<appd>", lcptypes.ModuleName, lcptypes.ClientTypeLCP),
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			clientID := args[0]
+			if err := lcptypes.ValidateClientID(clientID); err != nil {
+				return err
+			}
+			height, err := clienttypes.ParseHeight(args[1])
+			if err != nil {
+				return err
+			}
+			bz, _, err := clientCtx.QueryStore(host.FullClientKey(clientID, lcptypes.ConsensusSignerKey(height)), ibcexported.StoreKey)
+			if err != nil {
+				return err
+			} else if len(bz) != common.AddressLength {
+				return fmt.Errorf("the signer is not recorded: client_id=%v height=%v", clientID, height)
+			}
+			return clientCtx.PrintString(common.BytesToAddress(bz).Hex() + "\n")
+		},
+	}
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
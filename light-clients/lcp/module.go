@@ -2,6 +2,7 @@ package lcp
 
 import (
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/spf13/cobra"
 
 	"cosmossdk.io/core/appmodule"
 
@@ -10,16 +11,18 @@ import (
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
 
+	"github.com/datachainlab/lcp-go/light-clients/lcp/client/cli"
 	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
 )
 
 var (
 	_ module.AppModuleBasic = (*AppModuleBasic)(nil)
 	_ appmodule.AppModule   = (*AppModule)(nil)
+	_ module.HasServices    = (*AppModule)(nil)
 )
 
 // AppModuleBasic defines the basic application module used by the lcp light client.
-// Only the RegisterInterfaces function needs to be implemented. All other function perform
+// Only the RegisterInterfaces and GetQueryCmd functions need to be implemented. All other function perform
 // a no-op.
 type AppModuleBasic struct{}
 
@@ -46,12 +49,34 @@ func (AppModuleBasic) RegisterInterfaces(registry codectypes.InterfaceRegistry)
 // RegisterGRPCGatewayRoutes performs a no-op.
 func (AppModuleBasic) RegisterGRPCGatewayRoutes(clientCtx client.Context, mux *runtime.ServeMux) {}
 
+// GetQueryCmd returns the query commands of the LCP client, e.g. the signer of a consensus state.
+func (AppModuleBasic) GetQueryCmd() *cobra.Command {
+	return cli.NewQueryCmd()
+}
+
 // AppModule is the application module for the LCP client module
 type AppModule struct {
 	AppModuleBasic
+
+	cdc           codec.BinaryCodec
+	storeProvider lcptypes.StoreProvider
 }
 
-// NewAppModule creates a new LCP client module
+// NewAppModule creates a new LCP client module without the query server
 func NewAppModule() AppModule {
 	return AppModule{}
 }
+
+// NewAppModuleWithQueryServer creates a new LCP client module with the query server.
+// `storeProvider` provides the client stores to the query server, e.g. the client keeper of ibc-go.
+func NewAppModuleWithQueryServer(cdc codec.BinaryCodec, storeProvider lcptypes.StoreProvider) AppModule {
+	return AppModule{cdc: cdc, storeProvider: storeProvider}
+}
+
+// RegisterServices registers the query server of the LCP clients if the module is created with NewAppModuleWithQueryServer
+func (am AppModule) RegisterServices(cfg module.Configurator) {
+	if am.storeProvider == nil {
+		return
+	}
+	lcptypes.RegisterQueryServer(cfg.QueryServer(), lcptypes.NewQueryServer(am.cdc, am.storeProvider))
+}
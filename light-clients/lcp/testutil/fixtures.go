@@ -0,0 +1,376 @@
+package testutil
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/json"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/sgx/dcap/dcaptest"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// RegisterEnclaveKeyFixture is a registration of the enclave key attested by a real AVR
+type RegisterEnclaveKeyFixture struct {
+	Message *lcptypes.RegisterEnclaveKeyMessage
+	// the enclave key attested by the AVR
+	EnclaveKey common.Address
+	// the timestamp of the AVR
+	AttestationTime time.Time
+	ISVSVN          uint16
+	// a client state accepting the registration
+	ClientState *lcptypes.ClientState
+}
+
+// testdataDir returns the directory of the AVR fixtures in the repository
+func testdataDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "testdata")
+}
+
+// LoadRegisterEnclaveKeyFixture loads the AVR fixture `name` in the testdata directory, e.g. "001-avr".
+// The fixtures are attested for debug enclaves, so the caller must allow them with ias.SetAllowDebugEnclaves.
+func LoadRegisterEnclaveKeyFixture(t testing.TB, name string) *RegisterEnclaveKeyFixture {
+	bz, err := os.ReadFile(filepath.Join(testdataDir(), name))
+	require.NoError(t, err)
+	var eavr struct {
+		AVR         string `json:"avr"`
+		Signature   []byte `json:"signature"`
+		SigningCert []byte `json:"signing_cert"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &eavr))
+	avr, err := ias.ParseAndValidateAVR([]byte(eavr.AVR))
+	require.NoError(t, err)
+	quote, err := avr.Quote()
+	require.NoError(t, err)
+	ek, _, err := ias.GetEKAndOperator(quote)
+	require.NoError(t, err)
+
+	return &RegisterEnclaveKeyFixture{
+		Message: &lcptypes.RegisterEnclaveKeyMessage{
+			Report:      []byte(eavr.AVR),
+			Signature:   eavr.Signature,
+			SigningCert: eavr.SigningCert,
+		},
+		EnclaveKey:      ek,
+		AttestationTime: avr.GetTimestamp(),
+		ISVSVN:          quote.Report.ISVSVN,
+		ClientState: &lcptypes.ClientState{
+			LatestHeight:         clienttypes.NewHeight(0, 1),
+			Mrenclave:            quote.Report.MRENCLAVE[:],
+			KeyExpiration:        3600,
+			AllowedQuoteStatuses: []string{avr.ISVEnclaveQuoteStatus.String()},
+			AllowedAdvisoryIds:   avr.AdvisoryIDs,
+		},
+	}
+}
+
+// NewDCAPRegisterEnclaveKeyMessage returns the registration of the enclave key attested by the DCAP attestation fixture `f`
+func NewDCAPRegisterEnclaveKeyMessage(f *dcaptest.Fixture) *lcptypes.DCAPRegisterEnclaveKeyMessage {
+	return &lcptypes.DCAPRegisterEnclaveKeyMessage{Attestation: lcptypes.DCAPAttestation{
+		Quote:                 f.Quote,
+		TcbInfo:               f.Collateral.TCBInfo,
+		TcbInfoIssuerChain:    f.Collateral.TCBInfoIssuerChain,
+		QeIdentity:            f.Collateral.QEIdentity,
+		QeIdentityIssuerChain: f.Collateral.QEIdentityIssuerChain,
+	}}
+}
+
+// TestEnclaveKey returns a fixed key that plays the enclave key in the tests.
+// Unlike the keys attested by the AVR fixtures, it can sign the messages, so it must be registered with Harness.SetEnclaveKey.
+func TestEnclaveKey(t testing.TB) *ecdsa.PrivateKey {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("lcp-go/light-clients/lcp/testutil")))
+	require.NoError(t, err)
+	return key
+}
+
+// StateIDAt returns the state ID that the messages built by NewUpdateClientMessage have at `height`.
+// The state ID at the zero height is zero.
+func StateIDAt(height clienttypes.Height) lcptypes.StateID {
+	if height.IsZero() {
+		return lcptypes.StateID{}
+	}
+	return lcptypes.StateID(crypto.Keccak256Hash([]byte("state/" + height.String())))
+}
+
+type abiHeight struct {
+	RevisionNumber uint64 `json:"revision_number"`
+	RevisionHeight uint64 `json:"revision_height"`
+}
+
+func newABIHeight(height clienttypes.Height) abiHeight {
+	return abiHeight{RevisionNumber: height.RevisionNumber, RevisionHeight: height.RevisionHeight}
+}
+
+var (
+	heightComponents = []abi.ArgumentMarshaling{
+		{Name: "revision_number", Type: "uint64"},
+		{Name: "revision_height", Type: "uint64"},
+	}
+	headeredMessageABI, _ = abi.NewType("tuple", "struct HeaderedMessage", []abi.ArgumentMarshaling{
+		{Name: "header", Type: "bytes32"},
+		{Name: "message", Type: "bytes"},
+	})
+	headeredMessageContextABI, _ = abi.NewType("tuple", "struct HeaderedMessageContext", []abi.ArgumentMarshaling{
+		{Name: "header", Type: "bytes32"},
+		{Name: "context_bytes", Type: "bytes"},
+	})
+	updateStateProxyMessageABI, _ = abi.NewType("tuple", "struct UpdateStateProxyMessage", []abi.ArgumentMarshaling{
+		{Name: "prev_height", Type: "tuple", Components: heightComponents},
+		{Name: "prev_state_id", Type: "bytes32"},
+		{Name: "post_height", Type: "tuple", Components: heightComponents},
+		{Name: "post_state_id", Type: "bytes32"},
+		{Name: "timestamp", Type: "uint128"},
+		{Name: "context", Type: "bytes"},
+		{Name: "emitted_states", Type: "tuple[]", Components: []abi.ArgumentMarshaling{
+			{Name: "height", Type: "tuple", Components: heightComponents},
+			{Name: "state", Type: "bytes"},
+		}},
+	})
+	misbehaviourProxyMessageABI, _ = abi.NewType("tuple", "struct MisbehaviourProxyMessage", []abi.ArgumentMarshaling{
+		{Name: "prev_states", Type: "tuple[]", Components: []abi.ArgumentMarshaling{
+			{Name: "height", Type: "tuple", Components: heightComponents},
+			{Name: "state_id", Type: "bytes32"},
+		}},
+		{Name: "context", Type: "bytes"},
+		{Name: "client_message", Type: "bytes"},
+	})
+	verifyMembershipProxyMessageABI, _ = abi.NewType("tuple", "struct VerifyMembershipProxyMessage", []abi.ArgumentMarshaling{
+		{Name: "prefix", Type: "bytes"},
+		{Name: "path", Type: "bytes"},
+		{Name: "value", Type: "bytes32"},
+		{Name: "height", Type: "tuple", Components: heightComponents},
+		{Name: "state_id", Type: "bytes32"},
+	})
+)
+
+// NewUpdateClientMessage returns an update of the client from `prev` to `post` with the empty validation context,
+// whose state IDs are given by StateIDAt. The message is signed with `keys` in order and a nil key leaves its signature empty.
+// The message is deterministic for the same arguments.
+func NewUpdateClientMessage(t testing.TB, prev, post clienttypes.Height, timestamp time.Time, keys ...*ecdsa.PrivateKey) *lcptypes.UpdateClientMessage {
+	return NewUpdateClientMessageWithStateIDs(t, prev, StateIDAt(prev), post, StateIDAt(post), timestamp, keys...)
+}
+
+// NewUpdateClientMessageWithStateIDs returns the same update as NewUpdateClientMessage but with the given state IDs
+func NewUpdateClientMessageWithStateIDs(t testing.TB, prev clienttypes.Height, prevStateID lcptypes.StateID, post clienttypes.Height, postStateID lcptypes.StateID, timestamp time.Time, keys ...*ecdsa.PrivateKey) *lcptypes.UpdateClientMessage {
+	return newUpdateClientMessage(t, prev, prevStateID, post, postStateID, timestamp, nil, keys...)
+}
+
+// NewInitialUpdateClientMessage returns the first update of the client initialized at the zero height, which emits an empty state at `post`
+func NewInitialUpdateClientMessage(t testing.TB, post clienttypes.Height, timestamp time.Time, keys ...*ecdsa.PrivateKey) *lcptypes.UpdateClientMessage {
+	var state codectypes.Any
+	bz, err := state.Marshal()
+	require.NoError(t, err)
+	return newUpdateClientMessage(t, clienttypes.ZeroHeight(), lcptypes.StateID{}, post, StateIDAt(post), timestamp, []emittedState{{Height: newABIHeight(post), State: bz}}, keys...)
+}
+
+type emittedState struct {
+	Height abiHeight `json:"height"`
+	State  []byte    `json:"state"`
+}
+
+func newUpdateClientMessage(t testing.TB, prev clienttypes.Height, prevStateID lcptypes.StateID, post clienttypes.Height, postStateID lcptypes.StateID, timestamp time.Time, emittedStates []emittedState, keys ...*ecdsa.PrivateKey) *lcptypes.UpdateClientMessage {
+	message, err := abi.Arguments{{Type: updateStateProxyMessageABI}}.Pack(struct {
+		PrevHeight    abiHeight      `json:"prev_height"`
+		PrevStateId   [32]byte       `json:"prev_state_id"`
+		PostHeight    abiHeight      `json:"post_height"`
+		PostStateId   [32]byte       `json:"post_state_id"`
+		Timestamp     *big.Int       `json:"timestamp"`
+		Context       []byte         `json:"context"`
+		EmittedStates []emittedState `json:"emitted_states"`
+	}{
+		PrevHeight:    newABIHeight(prev),
+		PrevStateId:   prevStateID,
+		PostHeight:    newABIHeight(post),
+		PostStateId:   postStateID,
+		Timestamp:     big.NewInt(timestamp.UnixNano()),
+		Context:       newEmptyContext(t),
+		EmittedStates: emittedStates,
+	})
+	require.NoError(t, err)
+	return newSignedUpdateClientMessage(t, lcptypes.LCPMessageTypeUpdateState, message, keys)
+}
+
+type prevState struct {
+	Height  abiHeight `json:"height"`
+	StateId [32]byte  `json:"state_id"`
+}
+
+// NewMisbehaviourMessage returns a misbehaviour of the origin chain with the empty validation context,
+// which is verified against the consensus states at `prevs` whose state IDs are given by StateIDAt.
+// The message is signed with `keys` in order and a nil key leaves its signature empty.
+func NewMisbehaviourMessage(t testing.TB, prevs []clienttypes.Height, keys ...*ecdsa.PrivateKey) *lcptypes.UpdateClientMessage {
+	prevStates := []prevState{}
+	for _, prev := range prevs {
+		prevStates = append(prevStates, prevState{Height: newABIHeight(prev), StateId: StateIDAt(prev)})
+	}
+	message, err := abi.Arguments{{Type: misbehaviourProxyMessageABI}}.Pack(struct {
+		PrevStates    []prevState `json:"prev_states"`
+		Context       []byte      `json:"context"`
+		ClientMessage []byte      `json:"client_message"`
+	}{
+		PrevStates:    prevStates,
+		Context:       newEmptyContext(t),
+		ClientMessage: []byte("misbehaviour"),
+	})
+	require.NoError(t, err)
+	return newSignedUpdateClientMessage(t, lcptypes.LCPMessageTypeMisbehaviour, message, keys)
+}
+
+func newEmptyContext(t testing.TB) []byte {
+	var contextHeader [32]byte
+	binary.BigEndian.PutUint16(contextHeader[:2], lcptypes.LCPMessageContextTypeEmpty)
+	context, err := abi.Arguments{{Type: headeredMessageContextABI}}.Pack(struct {
+		Header       [32]byte `json:"header"`
+		ContextBytes []byte   `json:"context_bytes"`
+	}{Header: contextHeader, ContextBytes: []byte{}})
+	require.NoError(t, err)
+	return context
+}
+
+func newSignedUpdateClientMessage(t testing.TB, messageType uint16, message []byte, keys []*ecdsa.PrivateKey) *lcptypes.UpdateClientMessage {
+	var header [32]byte
+	binary.BigEndian.PutUint16(header[:2], lcptypes.LCPMessageVersion)
+	binary.BigEndian.PutUint16(header[2:4], messageType)
+	proxyMessage, err := abi.Arguments{{Type: headeredMessageABI}}.Pack(struct {
+		Header  [32]byte `json:"header"`
+		Message []byte   `json:"message"`
+	}{Header: header, Message: message})
+	require.NoError(t, err)
+
+	msg := &lcptypes.UpdateClientMessage{ProxyMessage: proxyMessage}
+	commitment := crypto.Keccak256Hash(proxyMessage)
+	for _, key := range keys {
+		if key == nil {
+			msg.Signatures = append(msg.Signatures, nil)
+			continue
+		}
+		sig, err := crypto.Sign(commitment[:], key)
+		require.NoError(t, err)
+		msg.Signatures = append(msg.Signatures, sig)
+	}
+	return msg
+}
+
+// NewCommitmentProof returns a proof of the commitment to `value` at `path` under the IBC store prefix at `height`,
+// whose state ID is given by StateIDAt. The proof is signed with `keys` in order and a nil key leaves its signature empty.
+func NewCommitmentProof(t testing.TB, height clienttypes.Height, path string, value []byte, keys ...*ecdsa.PrivateKey) []byte {
+	return newStateCommitmentProof(t, height, path, crypto.Keccak256Hash(value), keys...)
+}
+
+// NewNonMembershipCommitmentProof returns a proof of the commitment to the absence of a value at `path` like NewCommitmentProof
+func NewNonMembershipCommitmentProof(t testing.TB, height clienttypes.Height, path string, keys ...*ecdsa.PrivateKey) []byte {
+	return newStateCommitmentProof(t, height, path, [32]byte{}, keys...)
+}
+
+// newStateCommitmentProof returns a proof of the commitment to `valueHash` at `path`. A zero hash means the absence of the value.
+func newStateCommitmentProof(t testing.TB, height clienttypes.Height, path string, valueHash [32]byte, keys ...*ecdsa.PrivateKey) []byte {
+	message, err := abi.Arguments{{Type: verifyMembershipProxyMessageABI}}.Pack(struct {
+		Prefix  []byte    `json:"prefix"`
+		Path    []byte    `json:"path"`
+		Value   [32]byte  `json:"value"`
+		Height  abiHeight `json:"height"`
+		StateId [32]byte  `json:"state_id"`
+	}{
+		Prefix:  []byte(exported.StoreKey),
+		Path:    []byte(path),
+		Value:   valueHash,
+		Height:  newABIHeight(height),
+		StateId: StateIDAt(height),
+	})
+	require.NoError(t, err)
+
+	var header [32]byte
+	binary.BigEndian.PutUint16(header[:2], lcptypes.LCPMessageVersion)
+	binary.BigEndian.PutUint16(header[2:4], lcptypes.LCPMessageTypeState)
+	headered, err := abi.Arguments{{Type: headeredMessageABI}}.Pack(struct {
+		Header  [32]byte `json:"header"`
+		Message []byte   `json:"message"`
+	}{Header: header, Message: message})
+	require.NoError(t, err)
+
+	commitment := crypto.Keccak256Hash(headered)
+	var signatures [][]byte
+	for _, key := range keys {
+		if key == nil {
+			signatures = append(signatures, []byte{})
+			continue
+		}
+		sig, err := crypto.Sign(commitment[:], key)
+		require.NoError(t, err)
+		signatures = append(signatures, sig)
+	}
+	proof, err := lcptypes.EthABIEncodeCommitmentProofs(&lcptypes.CommitmentProofs{Message: headered, Signatures: signatures})
+	require.NoError(t, err)
+	return proof
+}
+
+// NewConsensusState returns the consensus state that the update built by NewUpdateClientMessage stores at `height`
+func NewConsensusState(height clienttypes.Height, timestamp time.Time) *lcptypes.ConsensusState {
+	stateID := StateIDAt(height)
+	return &lcptypes.ConsensusState{StateId: stateID[:], Timestamp: uint64(timestamp.UnixNano())}
+}
+
+// SignUpdateOperatorsMessage sets the signatures of `keys` in order to `msg` for the client of DefaultClientID on `chainID`.
+// A nil key leaves its signature empty.
+func SignUpdateOperatorsMessage(t testing.TB, chainID string, msg *lcptypes.UpdateOperatorsMessage, keys ...*ecdsa.PrivateKey) *lcptypes.UpdateOperatorsMessage {
+	newOperators, err := msg.GetNewOperators()
+	require.NoError(t, err)
+	signBytes, err := lcptypes.ComputeEIP712CosmosUpdateOperators(
+		chainID,
+		[]byte(exported.StoreKey),
+		DefaultClientID,
+		msg.Nonce,
+		newOperators,
+		msg.NewOperatorsThresholdNumerator,
+		msg.NewOperatorsThresholdDenominator,
+	)
+	require.NoError(t, err)
+	msg.Signatures = signCommitment(t, crypto.Keccak256Hash(signBytes), keys)
+	return msg
+}
+
+// SignUpdateClientParamsMessage sets the signatures of `keys` in order to `msg` for the client of DefaultClientID on `chainID`.
+// A nil key leaves its signature empty.
+func SignUpdateClientParamsMessage(t testing.TB, chainID string, msg *lcptypes.UpdateClientParamsMessage, keys ...*ecdsa.PrivateKey) *lcptypes.UpdateClientParamsMessage {
+	signBytes, err := lcptypes.ComputeEIP712CosmosUpdateClientParams(
+		chainID,
+		[]byte(exported.StoreKey),
+		DefaultClientID,
+		msg.Nonce,
+		msg.NewAllowedQuoteStatuses,
+		msg.NewAllowedAdvisoryIds,
+		msg.NewKeyExpiration,
+	)
+	require.NoError(t, err)
+	msg.Signatures = signCommitment(t, crypto.Keccak256Hash(signBytes), keys)
+	return msg
+}
+
+// signCommitment returns the signatures of `keys` over `commitment` in order. A nil key results in an empty signature.
+func signCommitment(t testing.TB, commitment common.Hash, keys []*ecdsa.PrivateKey) [][]byte {
+	var signatures [][]byte
+	for _, key := range keys {
+		if key == nil {
+			signatures = append(signatures, nil)
+			continue
+		}
+		sig, err := crypto.Sign(commitment[:], key)
+		require.NoError(t, err)
+		signatures = append(signatures, sig)
+	}
+	return signatures
+}
@@ -0,0 +1,48 @@
+package testutil
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "rewrite the golden fixtures in the testdata directory")
+
+type goldenUpdateClientMessage struct {
+	Signer       string          `json:"signer"`
+	ProxyMessage hexutil.Bytes   `json:"proxy_message"`
+	Signatures   []hexutil.Bytes `json:"signatures"`
+}
+
+// TestUpdateClientMessageGolden ensures that NewUpdateClientMessage is deterministic.
+// Run with `-update-golden` to rewrite the fixture after changing the message format intentionally.
+func TestUpdateClientMessageGolden(t *testing.T) {
+	key := TestEnclaveKey(t)
+	msg := NewUpdateClientMessage(t, clienttypes.NewHeight(0, 1), clienttypes.NewHeight(0, 2), DefaultBlockTime, key)
+	actual := goldenUpdateClientMessage{
+		Signer:       crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		ProxyMessage: msg.ProxyMessage,
+	}
+	for _, sig := range msg.Signatures {
+		actual.Signatures = append(actual.Signatures, sig)
+	}
+
+	path := filepath.Join("testdata", "update_client_message.json")
+	if *updateGolden {
+		bz, err := json.MarshalIndent(actual, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, append(bz, '\n'), 0644))
+	}
+	bz, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var expected goldenUpdateClientMessage
+	require.NoError(t, json.Unmarshal(bz, &expected))
+	require.Equal(t, expected, actual)
+}
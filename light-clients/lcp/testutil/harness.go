@@ -0,0 +1,132 @@
+// Package testutil provides a test harness simulating the store of an LCP client on a host chain
+// and fixtures of the client messages.
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"cosmossdk.io/store/mem"
+	"cosmossdk.io/store/prefix"
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// DefaultClientID is the ID of the client whose store the harness simulates
+const DefaultClientID = lcptypes.ClientTypeLCP + "-0"
+
+// DefaultBlockTime is the initial block time of the harness
+var DefaultBlockTime = time.Unix(1700000000, 0).UTC()
+
+// Harness is an in-memory client store of an LCP client wired with a codec and a context as the 02-client module does.
+// The store is prefixed with the client ID so that the client ID can be inferred from the store.
+type Harness struct {
+	t testing.TB
+
+	Ctx   sdk.Context
+	Cdc   codec.BinaryCodec
+	Store storetypes.KVStore
+}
+
+// NewHarness returns a harness with an empty client store at DefaultBlockTime
+func NewHarness(t testing.TB) *Harness {
+	return &Harness{
+		t:     t,
+		Ctx:   sdk.Context{}.WithEventManager(sdk.NewEventManager()).WithBlockTime(DefaultBlockTime),
+		Cdc:   NewCodec(),
+		Store: prefix.NewStore(mem.NewStore(), host.FullClientKey(DefaultClientID, nil)),
+	}
+}
+
+// NewCodec returns a codec with the interfaces of the 02-client module and the LCP client registered
+func NewCodec() codec.BinaryCodec {
+	registry := codectypes.NewInterfaceRegistry()
+	clienttypes.RegisterInterfaces(registry)
+	lcptypes.RegisterInterfaces(registry)
+	return codec.NewProtoCodec(registry)
+}
+
+// SetBlockTime sets the block time of the context
+func (h *Harness) SetBlockTime(blockTime time.Time) {
+	h.Ctx = h.Ctx.WithBlockTime(blockTime)
+}
+
+// AdvanceBlockTime advances the block time of the context by `d`
+func (h *Harness) AdvanceBlockTime(d time.Duration) {
+	h.SetBlockTime(h.Ctx.BlockTime().Add(d))
+}
+
+// Initialize stores the client state and the consensus state at its latest height
+func (h *Harness) Initialize(clientState *lcptypes.ClientState, consensusState *lcptypes.ConsensusState) {
+	h.SetClientState(clientState)
+	h.SetConsensusState(clientState.LatestHeight, consensusState)
+}
+
+// SetClientState stores the client state
+func (h *Harness) SetClientState(clientState *lcptypes.ClientState) {
+	h.Store.Set(host.ClientStateKey(), clienttypes.MustMarshalClientState(h.Cdc, clientState))
+}
+
+// ClientState returns the stored client state
+func (h *Harness) ClientState() *lcptypes.ClientState {
+	bz := h.Store.Get(host.ClientStateKey())
+	require.NotNil(h.t, bz, "client state does not exist")
+	clientState, err := clienttypes.UnmarshalClientState(h.Cdc, bz)
+	require.NoError(h.t, err)
+	return clientState.(*lcptypes.ClientState)
+}
+
+// SetConsensusState stores the consensus state at `height`
+func (h *Harness) SetConsensusState(height exported.Height, consensusState *lcptypes.ConsensusState) {
+	h.Store.Set(host.ConsensusStateKey(height), clienttypes.MustMarshalConsensusState(h.Cdc, consensusState))
+}
+
+// ConsensusState returns the stored consensus state at `height`
+func (h *Harness) ConsensusState(height exported.Height) *lcptypes.ConsensusState {
+	consensusState, err := lcptypes.GetConsensusState(h.Store, h.Cdc, height)
+	require.NoError(h.t, err)
+	return consensusState
+}
+
+// SetEnclaveKey registers the enclave key expiring at `expiredAt` and bound to `operator`
+func (h *Harness) SetEnclaveKey(ek common.Address, expiredAt time.Time, operator common.Address) {
+	require.NoError(h.t, h.ClientState().SetEKInfo(h.Store, ek, operator, expiredAt))
+}
+
+// EnclaveKeyInfo returns the info of the enclave key or nil if the key is not registered
+func (h *Harness) EnclaveKeyInfo(ek common.Address) *lcptypes.EKInfo {
+	info, err := h.ClientState().GetEKInfo(h.Store, ek)
+	require.NoError(h.t, err)
+	return info
+}
+
+// VerifyClientMessage verifies the message with the stored client state
+func (h *Harness) VerifyClientMessage(msg exported.ClientMessage) error {
+	return h.ClientState().VerifyClientMessage(h.Ctx, h.Cdc, h.Store, msg)
+}
+
+// UpdateState applies the message to the store without the verification
+func (h *Harness) UpdateState(msg exported.ClientMessage) []exported.Height {
+	return h.ClientState().UpdateState(h.Ctx, h.Cdc, h.Store, msg)
+}
+
+// Update verifies the message and applies it to the store as the 02-client module does
+func (h *Harness) Update(msg exported.ClientMessage) error {
+	if err := h.VerifyClientMessage(msg); err != nil {
+		return err
+	}
+	if h.ClientState().CheckForMisbehaviour(h.Ctx, h.Cdc, h.Store, msg) {
+		h.ClientState().UpdateStateOnMisbehaviour(h.Ctx, h.Cdc, h.Store, msg)
+		return nil
+	}
+	h.UpdateState(msg)
+	return nil
+}
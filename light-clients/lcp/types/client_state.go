@@ -2,19 +2,21 @@ package types
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
 
 	errorsmod "cosmossdk.io/errors"
 	storetypes "cosmossdk.io/store/types"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
 	commitmenttypes "github.com/cosmos/ibc-go/v8/modules/core/23-commitment/types"
 	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
 	"github.com/cosmos/ibc-go/v8/modules/core/exported"
-	"github.com/ethereum/go-ethereum/common"
+	"github.com/datachainlab/lcp-go/sgx/dcap"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -24,6 +26,9 @@ const (
 	MrenclaveSize = 32
 )
 
+// Stale is the status of a client which has not been updated within the max update gap
+const Stale exported.Status = "Stale"
+
 var _ exported.ClientState = (*ClientState)(nil)
 
 func (cs ClientState) Validate() error {
@@ -33,7 +38,19 @@ func (cs ClientState) Validate() error {
 	if l := len(cs.Mrenclave); l != MrenclaveSize {
 		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "`Mrenclave` length must be %v, but got %v", MrenclaveSize, l)
 	}
-	return nil
+	if err := ValidateQuotePolicy(cs.AllowedQuoteStatuses, cs.AllowedAdvisoryIds); err != nil {
+		return errorsmod.Wrap(clienttypes.ErrInvalidClient, err.Error())
+	}
+	if _, err := dcap.NewRootCertPool(cs.DcapRootCerts); err != nil {
+		return errorsmod.Wrap(clienttypes.ErrInvalidClient, err.Error())
+	}
+	if err := cs.validateAllowedMessageVersions(); err != nil {
+		return errorsmod.Wrap(clienttypes.ErrInvalidClient, err.Error())
+	}
+	if cs.MinimumIsvSvn > math.MaxUint16 {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "`MinimumIsvSvn` must be less than or equal to %v, but got %v", math.MaxUint16, cs.MinimumIsvSvn)
+	}
+	return cs.validateOperators()
 }
 
 func (cs ClientState) ClientType() string {
@@ -50,7 +67,7 @@ func (cs ClientState) GetTimestampAtHeight(
 	cdc codec.BinaryCodec,
 	height exported.Height,
 ) (uint64, error) {
-	consState, err := GetConsensusState(clientStore, cdc, height)
+	consState, err := newClientStore(clientStore, cdc).GetConsensusState(height)
 	if err != nil {
 		return 0, err
 	}
@@ -60,9 +77,9 @@ func (cs ClientState) GetTimestampAtHeight(
 // Initialization function
 // Clients must validate the initial consensus state, and may store any client-specific metadata
 // necessary for correct light client operation
-func (cs ClientState) Initialize(_ sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, consensusState exported.ConsensusState) error {
+func (cs ClientState) Initialize(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, consensusState exported.ConsensusState) error {
 	if err := cs.Validate(); err != nil {
-		return nil
+		return err
 	}
 	if !cs.LatestHeight.IsZero() {
 		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "`LatestHeight` must be zero height")
@@ -71,36 +88,47 @@ func (cs ClientState) Initialize(_ sdk.Context, cdc codec.BinaryCodec, clientSto
 	if !ok {
 		return errorsmod.Wrapf(clienttypes.ErrInvalidConsensus, "unexpected consensus state type: expected=%T got=%T", &ConsensusState{}, consensusState)
 	}
+	if !consState.IsZero() {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidConsensus, "the consensus state at the zero height must be the zero consensus state: state_id=%v timestamp=%v", HexBytes(consState.StateId), consState.Timestamp)
+	}
 
 	if cs.OperatorsNonce != 0 {
 		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "`OperatorsNonce` must be zero")
 	}
-	if len(cs.Operators) != 0 && (cs.OperatorsThresholdNumerator == 0 || cs.OperatorsThresholdDenominator == 0) {
-		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "`OperatorsThresholdNumerator` and `OperatorsThresholdDenominator` must be non-zero")
-	}
-	var zeroAddr common.Address
-	operators := cs.GetOperators()
-	for i, op := range operators {
-		if op == zeroAddr {
-			return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "operator address cannot be empty")
-		}
-		// check if the operator is ordered correctly
-		if i > 0 && bytes.Compare(operators[i-1].Bytes(), op.Bytes()) > 0 {
-			return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "operator addresses must be ordered: %v > %v", operators[i-1].String(), op.String())
-		}
-	}
-	if cs.OperatorsThresholdNumerator > cs.OperatorsThresholdDenominator {
-		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "`OperatorsThresholdNumerator` must be less than or equal to `OperatorsThresholdDenominator`")
-	}
 
-	setClientState(clientStore, cdc, &cs)
-	setConsensusState(clientStore, cdc, consState, cs.GetLatestHeight())
+	store := newClientStore(clientStore, cdc)
+	store.SetClientState(&cs)
+	store.SetConsensusState(cs.GetLatestHeight(), consState)
+	// the gap until the first update is measured from the creation
+	store.SetLastUpdateTime(ctx.BlockTime())
 	return nil
 }
 
 // Status function
 // Clients must return their status. Only Active clients are allowed to process packets.
+// The client is Frozen once a misbehaviour is submitted, and only the substitution of the client can make it Active again.
+// The Stale condition is not reported here because the 02-client module rejects the updates of a client
+// which is not Active, so a stale client could never be updated again. Use StatusWithStaleness instead.
 func (cs ClientState) Status(ctx sdk.Context, clientStore storetypes.KVStore, cdc codec.BinaryCodec) exported.Status {
+	if cs.Frozen {
+		return exported.Frozen
+	}
+	return exported.Active
+}
+
+// StatusWithStaleness returns Stale if the max update gap has elapsed since the latest update of the client, otherwise Status.
+// It is intended for the modules and the contracts which stop relying on the client when it is not updated in time, e.g. circuit breakers.
+func (cs ClientState) StatusWithStaleness(ctx sdk.Context, clientStore storetypes.KVStore, cdc codec.BinaryCodec) exported.Status {
+	if status := cs.Status(ctx, clientStore, cdc); status != exported.Active {
+		return status
+	}
+	lastUpdateTime, ok := newClientStore(clientStore, cdc).GetLastUpdateTime()
+	if !ok {
+		return exported.Active
+	}
+	if _, exceeded := cs.updateGapExceeded(lastUpdateTime, ctx.BlockTime()); exceeded {
+		return Stale
+	}
 	return exported.Active
 }
 
@@ -109,11 +137,71 @@ func (cs ClientState) ExportMetadata(_ storetypes.KVStore) []exported.GenesisMet
 	panic("not implemented") // TODO: Implement
 }
 
+// CheckSubstituteAndUpdateState replaces the frozen client with the substitute client, which is the only way to unfreeze the client.
+// The substitute must be an active LCP client trusting the same enclave with the same key expiration and the same operators.
+// The client state, the consensus state at the latest height and the enclave keys of the substitute are copied into the subject,
+// and the enclave keys registered in the subject are removed because they may have signed the misbehaviour.
 func (cs ClientState) CheckSubstituteAndUpdateState(
 	ctx sdk.Context, cdc codec.BinaryCodec, subjectClientStore,
 	substituteClientStore storetypes.KVStore, substituteClient exported.ClientState,
 ) error {
-	panic("not implemented") // TODO: Implement
+	substitute, ok := substituteClient.(*ClientState)
+	if !ok {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "invalid substitute client type: expected=%T actual=%T", &ClientState{}, substituteClient)
+	}
+	if !cs.Frozen {
+		return errorsmod.Wrap(clienttypes.ErrInvalidSubstitute, "the subject client is not frozen")
+	} else if substitute.Frozen {
+		return errorsmod.Wrap(clienttypes.ErrInvalidSubstitute, "the substitute client is frozen")
+	}
+	if !bytes.Equal(cs.Mrenclave, substitute.Mrenclave) {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidSubstitute, "mrenclave mismatch: subject=%v substitute=%v", HexBytes(cs.Mrenclave), HexBytes(substitute.Mrenclave))
+	}
+	if cs.KeyExpiration != substitute.KeyExpiration {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidSubstitute, "key expiration mismatch: subject=%v substitute=%v", cs.KeyExpiration, substitute.KeyExpiration)
+	}
+	if !slices.EqualFunc(cs.Operators, substitute.Operators, bytes.Equal) ||
+		cs.OperatorsThresholdNumerator != substitute.OperatorsThresholdNumerator ||
+		cs.OperatorsThresholdDenominator != substitute.OperatorsThresholdDenominator {
+		return errorsmod.Wrap(clienttypes.ErrInvalidSubstitute, "operators mismatch")
+	}
+
+	subject, src := newClientStore(subjectClientStore, cdc), newClientStore(substituteClientStore, cdc)
+	height := substitute.LatestHeight
+	consensusState, err := src.GetConsensusState(height)
+	if err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidSubstitute, "failed to get the consensus state of the substitute: height=%v %v", height, err)
+	}
+	subjectKeys, err := subject.GetEnclaveKeys()
+	if err != nil {
+		return err
+	}
+	substituteKeys, err := src.GetEnclaveKeys()
+	if err != nil {
+		return err
+	}
+	for _, k := range subjectKeys {
+		subject.DeleteEnclaveKey(k.EnclaveKey, k.Info)
+	}
+	for _, k := range substituteKeys {
+		subject.SetEnclaveKeyInfo(k.EnclaveKey, k.Info)
+	}
+
+	subject.SetConsensusState(height, consensusState)
+	if processedTime, ok := src.GetProcessedTime(height); ok {
+		subject.SetProcessedTime(height, processedTime)
+	}
+	if processedHeight, ok := src.GetProcessedHeight(height); ok {
+		subject.SetProcessedHeight(height, processedHeight)
+	}
+	if signer, ok := src.GetConsensusSigner(height); ok {
+		subject.SetConsensusSigner(height, signer)
+	}
+	if lastUpdateTime, ok := src.GetLastUpdateTime(); ok {
+		subject.SetLastUpdateTime(lastUpdateTime)
+	}
+	subject.SetClientState(substitute)
+	return nil
 }
 
 func (cs ClientState) VerifyUpgradeAndUpdateState(
@@ -142,63 +230,35 @@ func (cs ClientState) VerifyMembership(
 	path exported.Path,
 	value []byte,
 ) error {
-	if err := verifyDelayPeriodPassed(ctx, clientStore, height, delayTimePeriod, delayBlockPeriod); err != nil {
-		return err
-	}
-
-	merklePath := path.(commitmenttypes.MerklePath)
-	if l := len(merklePath.KeyPath); l != 2 {
-		panic(fmt.Errorf("invalid KeyPath length: %v", l))
+	if cs.Frozen {
+		return errorsmod.Wrap(clienttypes.ErrClientFrozen, "the client is frozen by a misbehaviour")
 	}
-	prefixBytes := []byte(merklePath.KeyPath[0])
-	commitmentPath := []byte(merklePath.KeyPath[1])
+	prefixBytes, commitmentPath := splitMerklePath(path)
 
 	// NOTE: lcp-client-go does not yet support the consensus state verification,
 	// so skip a verification if the path represents the consensus state
 	// "clients/{client_id}/consensusStates/{height}"
 	parts := strings.Split(string(commitmentPath), "/")
 	if len(parts) == 4 && parts[0] == string(host.KeyClientStorePrefix) && parts[2] == host.KeyConsensusStatePrefix {
-		return nil
+		return verifyDelayPeriodPassed(ctx, clientStore, height, delayTimePeriod, delayBlockPeriod)
 	}
 
-	if cs.GetLatestHeight().LT(height) {
-		return errorsmod.Wrapf(
-			sdkerrors.ErrInvalidHeight,
-			"client state height < proof height (%d < %d), please ensure the client has been updated", cs.GetLatestHeight(), height,
-		)
-	}
-	consensusState, err := GetConsensusState(clientStore, cdc, height)
-	if err != nil {
-		return errorsmod.Wrapf(clienttypes.ErrConsensusStateNotFound, "please ensure the proof was constructed against a height that exists on the client: err=%v", err)
-	}
-	commitmentProofs, err := EthABIDecodeCommitmentProofs(proof)
+	consensusState, err := cs.getProofConsensusState(clientStore, cdc, height)
 	if err != nil {
 		return err
 	}
-	m, err := commitmentProofs.GetMessage()
-	if err != nil {
+	if err := verifyDelayPeriodPassed(ctx, clientStore, height, delayTimePeriod, delayBlockPeriod); err != nil {
 		return err
 	}
-	msg, err := m.GetVerifyMembershipProxyMessage()
+	commitmentProofs, msg, err := decodeCommitmentProof(proof)
 	if err != nil {
 		return err
 	}
-	hashedValue := crypto.Keccak256Hash(value)
-
-	if !height.EQ(msg.Height) {
-		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid height: expected=%v got=%v", height, msg.Height)
-	}
-	if !bytes.Equal(prefixBytes, msg.Prefix) {
-		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid prefix: expected=%v got=%v", prefixBytes, msg.Prefix)
-	}
-	if !bytes.Equal(commitmentPath, msg.Path) {
-		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid path: expected=%v got=%v", string(commitmentPath), string(msg.Path))
-	}
-	if hashedValue != msg.Value {
-		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid value: expected=%X got=%X", hashedValue[:], msg.Value)
+	if err := cs.ValidateProxyMessageVersion(commitmentProofs.Message); err != nil {
+		return err
 	}
-	if !msg.StateID.EqualBytes(consensusState.StateId) {
-		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid state ID: expected=%v got=%v", consensusState.StateId, msg.StateID)
+	if err := VerifyMembershipCommitment(msg, height, prefixBytes, commitmentPath, value, consensusState.StateId); err != nil {
+		return err
 	}
 
 	commitment := crypto.Keccak256Hash(commitmentProofs.Message)
@@ -217,7 +277,62 @@ func (cs ClientState) VerifyNonMembership(
 	proof []byte,
 	path exported.Path,
 ) error {
-	return cs.VerifyMembership(ctx, clientStore, cdc, height, delayTimePeriod, delayBlockPeriod, proof, path, []byte{})
+	if cs.Frozen {
+		return errorsmod.Wrap(clienttypes.ErrClientFrozen, "the client is frozen by a misbehaviour")
+	}
+	prefixBytes, commitmentPath := splitMerklePath(path)
+	consensusState, err := cs.getProofConsensusState(clientStore, cdc, height)
+	if err != nil {
+		return err
+	}
+	if err := verifyDelayPeriodPassed(ctx, clientStore, height, delayTimePeriod, delayBlockPeriod); err != nil {
+		return err
+	}
+	commitmentProofs, msg, err := decodeNonMembershipCommitmentProof(proof)
+	if err != nil {
+		return err
+	}
+	if err := cs.ValidateProxyMessageVersion(commitmentProofs.Message); err != nil {
+		return err
+	}
+	if err := VerifyNonMembershipCommitment(msg, height, prefixBytes, commitmentPath, consensusState.StateId); err != nil {
+		return err
+	}
+
+	commitment := crypto.Keccak256Hash(commitmentProofs.Message)
+	return cs.VerifySignatures(ctx, clientStore, commitment, commitmentProofs.Signatures)
+}
+
+// splitMerklePath returns the prefix and the commitment path of `path`
+func splitMerklePath(path exported.Path) (prefix []byte, commitmentPath []byte) {
+	merklePath := path.(commitmenttypes.MerklePath)
+	if l := len(merklePath.KeyPath); l != 2 {
+		panic(fmt.Errorf("invalid KeyPath length: %v", l))
+	}
+	return []byte(merklePath.KeyPath[0]), []byte(merklePath.KeyPath[1])
+}
+
+// getProofConsensusState returns the consensus state at the proof height `height`.
+// A proof can be verified at any height whose consensus state is stored, e.g. a height older than the latest height
+// or a height updated out of order, so the latest height is only used to describe the error.
+// The caller must check that the state ID committed by the proof matches the one of the returned consensus state.
+func (cs ClientState) getProofConsensusState(clientStore storetypes.KVStore, cdc codec.BinaryCodec, height exported.Height) (*ConsensusState, error) {
+	consensusState, err := newClientStore(clientStore, cdc).GetConsensusState(height)
+	if err == nil {
+		return consensusState, nil
+	} else if !errors.Is(err, clienttypes.ErrConsensusStateNotFound) {
+		return nil, err
+	}
+	if cs.GetLatestHeight().LT(height) {
+		return nil, errorsmod.Wrapf(
+			clienttypes.ErrConsensusStateNotFound,
+			"client state height < proof height (%d < %d), please ensure the client has been updated", cs.GetLatestHeight(), height,
+		)
+	}
+	return nil, errorsmod.Wrapf(
+		clienttypes.ErrConsensusStateNotFound,
+		"no consensus state at the proof height %d (latest height %d), it has been pruned or never stored; please ensure the proof was constructed against a height that exists on the client", height, cs.GetLatestHeight(),
+	)
 }
 
 // verifyDelayPeriodPassed will ensure that at least delayTimePeriod amount of time and delayBlockPeriod number of blocks have passed
@@ -225,7 +340,7 @@ func (cs ClientState) VerifyNonMembership(
 func verifyDelayPeriodPassed(ctx sdk.Context, store storetypes.KVStore, proofHeight exported.Height, delayTimePeriod, delayBlockPeriod uint64) error {
 	if delayTimePeriod != 0 {
 		// check that executing chain's timestamp has passed consensusState's processed time + delay time period
-		processedTime, ok := GetProcessedTime(store, proofHeight)
+		processedTime, ok := newClientStore(store, nil).GetProcessedTime(proofHeight)
 		if !ok {
 			return errorsmod.Wrapf(ErrProcessedTimeNotFound, "processed time not found for height: %s", proofHeight)
 		}
@@ -243,7 +358,7 @@ func verifyDelayPeriodPassed(ctx sdk.Context, store storetypes.KVStore, proofHei
 
 	if delayBlockPeriod != 0 {
 		// check that executing chain's height has passed consensusState's processed height + delay block period
-		processedHeight, ok := GetProcessedHeight(store, proofHeight)
+		processedHeight, ok := newClientStore(store, nil).GetProcessedHeight(proofHeight)
 		if !ok {
 			return errorsmod.Wrapf(ErrProcessedHeightNotFound, "processed height not found for height: %s", proofHeight)
 		}
@@ -0,0 +1,127 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v8/modules/core/23-commitment/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/light-clients/lcp/testutil"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyNonMembership(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	height := clienttypes.NewHeight(0, 10)
+	path := "receipts/ports/transfer/channels/channel-0/sequences/1"
+	merklePath := commitmenttypes.NewMerklePath(exported.StoreKey, path)
+
+	h := testutil.NewHarness(t)
+	h.Initialize(&lcptypes.ClientState{LatestHeight: height, KeyExpiration: 3600}, testutil.NewConsensusState(height, h.Ctx.BlockTime()))
+	h.SetEnclaveKey(ek, h.Ctx.BlockTime().Add(time.Hour), common.Address{})
+	verify := func(height exported.Height, proof []byte) error {
+		return h.ClientState().VerifyNonMembership(h.Ctx, h.Store, h.Cdc, height, 0, 0, proof, merklePath)
+	}
+
+	require.NoError(t, verify(height, testutil.NewNonMembershipCommitmentProof(t, height, path, key)))
+	// a membership commitment of an empty value does not prove the absence
+	require.ErrorContains(t, verify(height, testutil.NewCommitmentProof(t, height, path, []byte{}, key)), "unexpected value of non-membership message")
+	// the proof height is newer than the client
+	next := clienttypes.NewHeight(0, 11)
+	require.ErrorContains(t, verify(next, testutil.NewNonMembershipCommitmentProof(t, next, path, key)), "please ensure the client has been updated")
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	require.Error(t, verify(height, testutil.NewNonMembershipCommitmentProof(t, height, path, other)))
+}
+
+func TestVerifyMembershipAtHistoricalHeights(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	path := "commitments/ports/transfer/channels/channel-0/sequences/1"
+	value := []byte("commitment")
+	merklePath := commitmenttypes.NewMerklePath(exported.StoreKey, path)
+	height := func(h uint64) clienttypes.Height {
+		return clienttypes.NewHeight(0, h)
+	}
+
+	newClient := func(t *testing.T) *testutil.Harness {
+		h := testutil.NewHarness(t)
+		h.Initialize(&lcptypes.ClientState{LatestHeight: height(10), KeyExpiration: 3600}, testutil.NewConsensusState(height(10), h.Ctx.BlockTime()))
+		h.SetEnclaveKey(ek, h.Ctx.BlockTime().Add(time.Hour), common.Address{})
+		// the client is updated to 20, and then to 15 out of order
+		require.NoError(t, h.Update(testutil.NewUpdateClientMessage(t, height(10), height(20), h.Ctx.BlockTime(), key)))
+		require.NoError(t, h.Update(testutil.NewUpdateClientMessage(t, height(10), height(15), h.Ctx.BlockTime(), key)))
+		require.Equal(t, height(20), h.ClientState().LatestHeight)
+		return h
+	}
+	verifyMembership := func(h *testutil.Harness, height clienttypes.Height, proof []byte) error {
+		return h.ClientState().VerifyMembership(h.Ctx, h.Store, h.Cdc, height, 0, 0, proof, merklePath, value)
+	}
+	verifyNonMembership := func(h *testutil.Harness, height clienttypes.Height, proof []byte) error {
+		return h.ClientState().VerifyNonMembership(h.Ctx, h.Store, h.Cdc, height, 0, 0, proof, merklePath)
+	}
+
+	t.Run("stored heights", func(t *testing.T) {
+		h := newClient(t)
+		for _, proofHeight := range []clienttypes.Height{height(10), height(15), height(20)} {
+			require.NoError(t, verifyMembership(h, proofHeight, testutil.NewCommitmentProof(t, proofHeight, path, value, key)), proofHeight)
+			require.NoError(t, verifyNonMembership(h, proofHeight, testutil.NewNonMembershipCommitmentProof(t, proofHeight, path, key)), proofHeight)
+		}
+	})
+
+	t.Run("height without a consensus state", func(t *testing.T) {
+		h := newClient(t)
+		// the height between the stored heights
+		err := verifyMembership(h, height(12), testutil.NewCommitmentProof(t, height(12), path, value, key))
+		require.ErrorIs(t, err, clienttypes.ErrConsensusStateNotFound)
+		require.ErrorContains(t, err, "pruned or never stored")
+		// the height newer than the latest height
+		err = verifyNonMembership(h, height(21), testutil.NewNonMembershipCommitmentProof(t, height(21), path, key))
+		require.ErrorIs(t, err, clienttypes.ErrConsensusStateNotFound)
+		require.ErrorContains(t, err, "please ensure the client has been updated")
+	})
+
+	t.Run("pruned height", func(t *testing.T) {
+		h := newClient(t)
+		h.Store.Delete(host.ConsensusStateKey(height(15)))
+		err := verifyMembership(h, height(15), testutil.NewCommitmentProof(t, height(15), path, value, key))
+		require.ErrorIs(t, err, clienttypes.ErrConsensusStateNotFound)
+		require.ErrorContains(t, err, "pruned or never stored")
+		// the other heights are still available
+		require.NoError(t, verifyMembership(h, height(10), testutil.NewCommitmentProof(t, height(10), path, value, key)))
+	})
+
+	t.Run("consensus state newer than the latest height", func(t *testing.T) {
+		h := newClient(t)
+		h.SetConsensusState(height(25), testutil.NewConsensusState(height(25), h.Ctx.BlockTime()))
+		require.NoError(t, verifyMembership(h, height(25), testutil.NewCommitmentProof(t, height(25), path, value, key)))
+	})
+
+	t.Run("state ID mismatch", func(t *testing.T) {
+		h := newClient(t)
+		// the consensus state at 15 is replaced with the one of another state
+		h.SetConsensusState(height(15), testutil.NewConsensusState(height(16), h.Ctx.BlockTime()))
+		err := verifyMembership(h, height(15), testutil.NewCommitmentProof(t, height(15), path, value, key))
+		require.ErrorIs(t, err, lcptypes.ErrInvalidStateCommitment)
+		require.ErrorContains(t, err, "invalid state ID")
+		err = verifyNonMembership(h, height(15), testutil.NewNonMembershipCommitmentProof(t, height(15), path, key))
+		require.ErrorIs(t, err, lcptypes.ErrInvalidStateCommitment)
+		// the proof at a height committing to the state of another height
+		err = verifyMembership(h, height(20), testutil.NewCommitmentProof(t, height(10), path, value, key))
+		require.ErrorIs(t, err, lcptypes.ErrInvalidStateCommitment)
+		require.ErrorContains(t, err, "invalid height")
+	})
+
+	t.Run("delay period of a height without a consensus state", func(t *testing.T) {
+		h := newClient(t)
+		err := h.ClientState().VerifyMembership(h.Ctx, h.Store, h.Cdc, height(12), 1, 1, testutil.NewCommitmentProof(t, height(12), path, value, key), merklePath, value)
+		require.ErrorIs(t, err, clienttypes.ErrConsensusStateNotFound)
+	})
+}
@@ -0,0 +1,446 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// enclaveKeyInfoSize is the size of the stored enclave key info: expiredAt(8 bytes) + operator(20 bytes)
+const enclaveKeyInfoSize = 8 + common.AddressLength
+
+// clientStore wraps the client prefixed store and centralizes the key encoding and the value serialization.
+// The keys and the stored byte layouts are compatible with the ones written by the previous versions.
+//
+// Layout:
+// - "clientState": ClientState (Any), which also holds the nonce of the latest operators update
+// - "consensusStates/{height}": ConsensusState (Any)
+// - "consensusStates/{height}/processedTime": big endian uint64 (unix nanoseconds)
+// - "consensusStates/{height}/processedHeight": height string
+// - "consensusStates/{height}/signer": enclave key address (20 bytes)
+// - "lastUpdateTime": big endian uint64 (unix nanoseconds)
+// - "aux/enclave_keys/{checksummed address}": big endian uint64 expiredAt (unix seconds) || operator address
+// - "aux/enclave_keys_by_expiry/{big endian uint64 expiredAt}{address}": address, the index of the enclave keys ordered by the expiration
+// - "aux/enclave_keys_pruned/{checksummed address}": big endian uint64 removeAt (unix seconds), the marker of an enclave key pruned after the expiration
+// - "aux/enclave_keys_pruned_by_expiry/{big endian uint64 removeAt}{address}": address, the index of the markers ordered by the time to remove them
+//
+// The enclave keys stored by the previous versions have no index entry. They are still readable, but they are not pruned.
+type clientStore struct {
+	store storetypes.KVStore
+	// cdc is only required for the client state and the consensus states
+	cdc codec.BinaryCodec
+}
+
+func newClientStore(store storetypes.KVStore, cdc codec.BinaryCodec) clientStore {
+	return clientStore{store: store, cdc: cdc}
+}
+
+// GetClientState returns the client state. An error is returned if the client state does not exist.
+func (s clientStore) GetClientState() (*ClientState, error) {
+	bz := s.store.Get(host.ClientStateKey())
+	if bz == nil {
+		return nil, errorsmod.Wrap(clienttypes.ErrClientNotFound, "client state does not exist")
+	}
+	clientStateI, err := clienttypes.UnmarshalClientState(s.cdc, bz)
+	if err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidClient, "unmarshal error: %v", err)
+	}
+	clientState, ok := clientStateI.(*ClientState)
+	if !ok {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidClient, "invalid client type %T, expected %T", clientStateI, &ClientState{})
+	}
+	return clientState, nil
+}
+
+// SetClientState stores the client state
+func (s clientStore) SetClientState(clientState *ClientState) {
+	s.store.Set(host.ClientStateKey(), clienttypes.MustMarshalClientState(s.cdc, clientState))
+}
+
+// SetFrozen stores the client state with the frozen flag set
+func (s clientStore) SetFrozen(clientState ClientState) {
+	clientState.Frozen = true
+	s.SetClientState(&clientState)
+}
+
+// GetNonce returns the nonce of the latest operators update
+func (s clientStore) GetNonce() (uint64, error) {
+	clientState, err := s.GetClientState()
+	if err != nil {
+		return 0, err
+	}
+	return clientState.OperatorsNonce, nil
+}
+
+// IncrementNonce consumes `nonce` as the nonce of an operators update and returns the stored client state with the nonce set.
+// The caller applies the update to the returned client state and stores it with SetClientState.
+// The nonce is checked against the stored client state rather than the one the message was verified with,
+// so only one of the messages with the same nonce is applied even if they were verified against the same client state.
+func (s clientStore) IncrementNonce(nonce uint64) (*ClientState, error) {
+	clientState, err := s.GetClientState()
+	if err != nil {
+		return nil, err
+	}
+	if next := clientState.NextOperatorsNonce(); nonce != next {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid nonce: expected=%v actual=%v", next, nonce)
+	}
+	clientState.OperatorsNonce = nonce
+	return clientState, nil
+}
+
+// GetConsensusState returns the consensus state at the given height.
+// An error is returned if the consensus state does not exist.
+func (s clientStore) GetConsensusState(height exported.Height) (*ConsensusState, error) {
+	bz := s.store.Get(host.ConsensusStateKey(height))
+	if bz == nil {
+		return nil, errorsmod.Wrapf(
+			clienttypes.ErrConsensusStateNotFound,
+			"consensus state does not exist for height %s", height,
+		)
+	}
+	return s.unmarshalConsensusState(bz)
+}
+
+func (s clientStore) unmarshalConsensusState(bz []byte) (*ConsensusState, error) {
+	consensusStateI, err := clienttypes.UnmarshalConsensusState(s.cdc, bz)
+	if err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidConsensus, "unmarshal error: %v", err)
+	}
+	consensusState, ok := consensusStateI.(*ConsensusState)
+	if !ok {
+		return nil, errorsmod.Wrapf(
+			clienttypes.ErrInvalidConsensus,
+			"invalid consensus type %T, expected %T", consensusStateI, &ConsensusState{},
+		)
+	}
+	return consensusState, nil
+}
+
+// SetConsensusState stores the consensus state at the given height
+func (s clientStore) SetConsensusState(height exported.Height, consensusState *ConsensusState) {
+	s.store.Set(host.ConsensusStateKey(height), clienttypes.MustMarshalConsensusState(s.cdc, consensusState))
+}
+
+// IterateConsensusStates calls `cb` for each consensus state in the lexical order of the keys until `cb` returns true.
+// The processed time, height and signer entries under the consensus state keys are skipped.
+func (s clientStore) IterateConsensusStates(cb func(height clienttypes.Height, consensusState *ConsensusState) (stop bool)) error {
+	prefix := []byte(host.KeyConsensusStatePrefix + "/")
+	iterator := storetypes.KVStorePrefixIterator(s.store, prefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		suffix := string(iterator.Key()[len(prefix):])
+		if strings.Contains(suffix, "/") {
+			continue
+		}
+		height, err := clienttypes.ParseHeight(suffix)
+		if err != nil {
+			return fmt.Errorf("invalid consensus state key: key=%s %w", iterator.Key(), err)
+		}
+		consensusState, err := s.unmarshalConsensusState(iterator.Value())
+		if err != nil {
+			return err
+		}
+		if cb(height, consensusState) {
+			break
+		}
+	}
+	return nil
+}
+
+// GetProcessedTime returns the time (in nanoseconds) at which the consensus state at the given height was processed
+func (s clientStore) GetProcessedTime(height exported.Height) (uint64, bool) {
+	bz := s.store.Get(ProcessedTimeKey(height))
+	if bz == nil {
+		return 0, false
+	}
+	return sdk.BigEndianToUint64(bz), true
+}
+
+// SetProcessedTime stores the time (in nanoseconds) at which the consensus state at the given height was processed
+func (s clientStore) SetProcessedTime(height exported.Height, timeNs uint64) {
+	s.store.Set(ProcessedTimeKey(height), sdk.Uint64ToBigEndian(timeNs))
+}
+
+// DeleteProcessedTime deletes the processed time for the given height
+func (s clientStore) DeleteProcessedTime(height exported.Height) {
+	s.store.Delete(ProcessedTimeKey(height))
+}
+
+// GetProcessedHeight returns the height at which the consensus state at the given height was processed
+func (s clientStore) GetProcessedHeight(height exported.Height) (exported.Height, bool) {
+	bz := s.store.Get(ProcessedHeightKey(height))
+	if bz == nil {
+		return nil, false
+	}
+	processedHeight, err := clienttypes.ParseHeight(string(bz))
+	if err != nil {
+		return nil, false
+	}
+	return processedHeight, true
+}
+
+// SetProcessedHeight stores the height at which the consensus state at the given height was processed
+func (s clientStore) SetProcessedHeight(consHeight, processedHeight exported.Height) {
+	s.store.Set(ProcessedHeightKey(consHeight), []byte(processedHeight.String()))
+}
+
+// DeleteProcessedHeight deletes the processed height for the given height
+func (s clientStore) DeleteProcessedHeight(height exported.Height) {
+	s.store.Delete(ProcessedHeightKey(height))
+}
+
+// GetConsensusSigner returns the address of the enclave key that signed the consensus state at the given height
+func (s clientStore) GetConsensusSigner(height exported.Height) (common.Address, bool) {
+	bz := s.store.Get(ConsensusSignerKey(height))
+	if len(bz) != common.AddressLength {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(bz), true
+}
+
+// SetConsensusSigner stores the address of the enclave key that signed the consensus state at the given height
+func (s clientStore) SetConsensusSigner(height exported.Height, signer common.Address) {
+	s.store.Set(ConsensusSignerKey(height), signer.Bytes())
+}
+
+// GetLastUpdateTime returns the block time of the latest update
+func (s clientStore) GetLastUpdateTime() (time.Time, bool) {
+	bz := s.store.Get(KeyLastUpdateTime)
+	if bz == nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(sdk.BigEndianToUint64(bz))), true
+}
+
+// SetLastUpdateTime stores the block time of the latest update
+func (s clientStore) SetLastUpdateTime(blockTime time.Time) {
+	s.store.Set(KeyLastUpdateTime, sdk.Uint64ToBigEndian(uint64(blockTime.UnixNano())))
+}
+
+// HasEnclaveKey returns true if the enclave key is registered
+func (s clientStore) HasEnclaveKey(ek common.Address) bool {
+	return s.store.Has(enclaveKeyPath(ek))
+}
+
+// GetEnclaveKeyInfo returns the info of the enclave key. If the key is not registered, it returns nil.
+func (s clientStore) GetEnclaveKeyInfo(ek common.Address) (*EKInfo, error) {
+	bz := s.store.Get(enclaveKeyPath(ek))
+	if bz == nil {
+		return nil, nil
+	}
+	return unmarshalEKInfo(bz)
+}
+
+func unmarshalEKInfo(bz []byte) (*EKInfo, error) {
+	if len(bz) != enclaveKeyInfoSize {
+		return nil, fmt.Errorf("invalid enclave key info: expected=%v actual=%v", enclaveKeyInfoSize, len(bz))
+	}
+	return &EKInfo{
+		ExpiredAt: sdk.BigEndianToUint64(bz[:8]),
+		Operator:  common.BytesToAddress(bz[8:]),
+	}, nil
+}
+
+// SetEnclaveKeyInfo stores the expiration and the operator of the enclave key and indexes the key by the expiration
+func (s clientStore) SetEnclaveKeyInfo(ek common.Address, info EKInfo) {
+	if prev, err := s.GetEnclaveKeyInfo(ek); err == nil && prev != nil && prev.ExpiredAt != info.ExpiredAt {
+		s.store.Delete(enclaveKeyExpiryIndexPath(prev.ExpiredAt, ek))
+	}
+	s.store.Set(enclaveKeyPath(ek), append(sdk.Uint64ToBigEndian(info.ExpiredAt), info.Operator.Bytes()...))
+	s.store.Set(enclaveKeyExpiryIndexPath(info.ExpiredAt, ek), ek.Bytes())
+}
+
+// registeredEnclaveKey is an entry of the enclave keys in the client store
+type registeredEnclaveKey struct {
+	EnclaveKey common.Address
+	Info       EKInfo
+}
+
+// GetEnclaveKeys returns all the registered enclave keys in the lexical order of the entries
+func (s clientStore) GetEnclaveKeys() ([]registeredEnclaveKey, error) {
+	iterator := storetypes.KVStorePrefixIterator(s.store, []byte(EnclaveKeyPrefix))
+	defer iterator.Close()
+	var keys []registeredEnclaveKey
+	for ; iterator.Valid(); iterator.Next() {
+		ek, info, err := ParseEnclaveKeyEntry(iterator.Key(), iterator.Value())
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, registeredEnclaveKey{EnclaveKey: ek, Info: *info})
+	}
+	return keys, nil
+}
+
+// DeleteEnclaveKey deletes the enclave key registered with `info` and its index entry
+func (s clientStore) DeleteEnclaveKey(ek common.Address, info EKInfo) {
+	s.store.Delete(enclaveKeyExpiryIndexPath(info.ExpiredAt, ek))
+	s.store.Delete(enclaveKeyPath(ek))
+}
+
+// prunedEnclaveKey is an enclave key deleted by PruneExpiredEnclaveKeys
+type prunedEnclaveKey struct {
+	EnclaveKey common.Address
+	ExpiredAt  uint64
+}
+
+// PruneExpiredEnclaveKeys deletes at most `limit` enclave keys expired before `cutoff` in the order of the expiration.
+// Only the keys indexed by the expiration are pruned.
+// A marker of each pruned key is kept until `retention` after the expiration of the key, so that the key is not registered again meanwhile.
+// The markers whose retention has ended before `cutoff` are also deleted, at most `limit` of them, so the store stays bounded.
+func (s clientStore) PruneExpiredEnclaveKeys(cutoff time.Time, limit int, retention time.Duration) ([]prunedEnclaveKey, error) {
+	markers, err := s.collectExpiredEntries(prunedEnclaveKeyExpiryIndexPrefix, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range markers {
+		s.store.Delete(prunedEnclaveKeyExpiryIndexPath(m.At, m.EnclaveKey))
+		if bz := s.store.Get(prunedEnclaveKeyPath(m.EnclaveKey)); bz != nil && sdk.BigEndianToUint64(bz) == m.At {
+			s.store.Delete(prunedEnclaveKeyPath(m.EnclaveKey))
+		}
+	}
+
+	candidates, err := s.collectExpiredEntries(enclaveKeyExpiryIndexPrefix, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	var pruned []prunedEnclaveKey
+	for _, c := range candidates {
+		s.store.Delete(enclaveKeyExpiryIndexPath(c.At, c.EnclaveKey))
+		info, err := s.GetEnclaveKeyInfo(c.EnclaveKey)
+		if err != nil {
+			return nil, err
+		} else if info == nil || info.ExpiredAt != c.At {
+			// the index entry is stale
+			continue
+		}
+		s.store.Delete(enclaveKeyPath(c.EnclaveKey))
+		s.setPrunedEnclaveKeyMarker(c.EnclaveKey, uint64(time.Unix(int64(c.At), 0).Add(retention).Unix()))
+		pruned = append(pruned, prunedEnclaveKey{EnclaveKey: c.EnclaveKey, ExpiredAt: c.At})
+	}
+	return pruned, nil
+}
+
+// expiryIndexEntry is an entry of an index of the enclave keys ordered by a time
+type expiryIndexEntry struct {
+	EnclaveKey common.Address
+	// unix seconds
+	At uint64
+}
+
+// collectExpiredEntries returns at most `limit` entries of the index under `prefix` whose times are before `cutoff` in the order of the times.
+// The entries are deleted by the caller after the iteration because the store must not be modified during it.
+func (s clientStore) collectExpiredEntries(prefix string, cutoff time.Time, limit int) ([]expiryIndexEntry, error) {
+	var entries []expiryIndexEntry
+	iterator := storetypes.KVStorePrefixIterator(s.store, []byte(prefix))
+	defer iterator.Close()
+	for ; iterator.Valid() && len(entries) < limit; iterator.Next() {
+		suffix := iterator.Key()[len(prefix):]
+		if len(suffix) != 8+common.AddressLength {
+			return nil, fmt.Errorf("invalid index entry: key=%x", iterator.Key())
+		}
+		at := sdk.BigEndianToUint64(suffix[:8])
+		if !time.Unix(int64(at), 0).Before(cutoff) {
+			break
+		}
+		entries = append(entries, expiryIndexEntry{EnclaveKey: common.BytesToAddress(suffix[8:]), At: at})
+	}
+	return entries, nil
+}
+
+// setPrunedEnclaveKeyMarker stores the marker of the pruned enclave key `ek` to be removed at `removeAt`
+func (s clientStore) setPrunedEnclaveKeyMarker(ek common.Address, removeAt uint64) {
+	if bz := s.store.Get(prunedEnclaveKeyPath(ek)); bz != nil {
+		s.store.Delete(prunedEnclaveKeyExpiryIndexPath(sdk.BigEndianToUint64(bz), ek))
+	}
+	s.store.Set(prunedEnclaveKeyPath(ek), sdk.Uint64ToBigEndian(removeAt))
+	s.store.Set(prunedEnclaveKeyExpiryIndexPath(removeAt, ek), ek.Bytes())
+}
+
+// IsEnclaveKeyPruned returns true if the enclave key has been pruned by PruneExpiredEnclaveKeys and its marker is not removed yet
+func (s clientStore) IsEnclaveKeyPruned(ek common.Address) bool {
+	return s.store.Has(prunedEnclaveKeyPath(ek))
+}
+
+// GetEnclaveKeyExpiration returns the expiration time of the enclave key.
+// If the key is not registered, `found` is false.
+func (s clientStore) GetEnclaveKeyExpiration(ek common.Address) (expiredAt time.Time, found bool, err error) {
+	info, err := s.GetEnclaveKeyInfo(ek)
+	if err != nil || info == nil {
+		return time.Time{}, false, err
+	}
+	return time.Unix(int64(info.ExpiredAt), 0), true, nil
+}
+
+// GetOperatorBinding returns the operator bound to the enclave key.
+// If the key is not registered, `found` is false.
+func (s clientStore) GetOperatorBinding(ek common.Address) (operator common.Address, found bool, err error) {
+	info, err := s.GetEnclaveKeyInfo(ek)
+	if err != nil || info == nil {
+		return common.Address{}, false, err
+	}
+	return info.Operator, true, nil
+}
+
+// SetOperatorBinding binds the operator to the registered enclave key without changing its expiration
+func (s clientStore) SetOperatorBinding(ek, operator common.Address) error {
+	info, err := s.GetEnclaveKeyInfo(ek)
+	if err != nil {
+		return err
+	} else if info == nil {
+		return fmt.Errorf("enclave key '%v' not found", ek)
+	}
+	info.Operator = operator
+	s.SetEnclaveKeyInfo(ek, *info)
+	return nil
+}
+
+// EnclaveKeyPrefix is the prefix of the enclave key entries in the client store.
+// The relayers can list the registered enclave keys by querying the client store with this prefix and decoding the entries with ParseEnclaveKeyEntry.
+const EnclaveKeyPrefix = "aux/enclave_keys/"
+
+func enclaveKeyPath(key common.Address) []byte {
+	return []byte(EnclaveKeyPrefix + key.Hex())
+}
+
+// ParseEnclaveKeyEntry decodes an entry of the client store under EnclaveKeyPrefix.
+// `key` may be relative to the client store or to EnclaveKeyPrefix.
+func ParseEnclaveKeyEntry(key, value []byte) (common.Address, *EKInfo, error) {
+	hex := strings.TrimPrefix(string(key), EnclaveKeyPrefix)
+	if !common.IsHexAddress(hex) {
+		return common.Address{}, nil, fmt.Errorf("invalid enclave key entry: key=%q", key)
+	}
+	info, err := unmarshalEKInfo(value)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return common.HexToAddress(hex), info, nil
+}
+
+const enclaveKeyExpiryIndexPrefix = "aux/enclave_keys_by_expiry/"
+
+func enclaveKeyExpiryIndexPath(expiredAt uint64, key common.Address) []byte {
+	path := append([]byte(enclaveKeyExpiryIndexPrefix), sdk.Uint64ToBigEndian(expiredAt)...)
+	return append(path, key.Bytes()...)
+}
+
+const prunedEnclaveKeyPrefix = "aux/enclave_keys_pruned/"
+
+func prunedEnclaveKeyPath(key common.Address) []byte {
+	return []byte(prunedEnclaveKeyPrefix + key.Hex())
+}
+
+const prunedEnclaveKeyExpiryIndexPrefix = "aux/enclave_keys_pruned_by_expiry/"
+
+func prunedEnclaveKeyExpiryIndexPath(removeAt uint64, key common.Address) []byte {
+	path := append([]byte(prunedEnclaveKeyExpiryIndexPrefix), sdk.Uint64ToBigEndian(removeAt)...)
+	return append(path, key.Bytes()...)
+}
@@ -0,0 +1,350 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"cosmossdk.io/store/mem"
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClientStore(t *testing.T) clientStore {
+	registry := codectypes.NewInterfaceRegistry()
+	clienttypes.RegisterInterfaces(registry)
+	RegisterInterfaces(registry)
+	return newClientStore(mem.NewStore(), codec.NewProtoCodec(registry))
+}
+
+func TestClientStoreClientState(t *testing.T) {
+	s := newTestClientStore(t)
+
+	_, err := s.GetClientState()
+	require.ErrorIs(t, err, clienttypes.ErrClientNotFound)
+	_, err = s.GetNonce()
+	require.ErrorIs(t, err, clienttypes.ErrClientNotFound)
+
+	cs := ClientState{
+		LatestHeight:   clienttypes.NewHeight(0, 1),
+		Mrenclave:      make([]byte, MrenclaveSize),
+		KeyExpiration:  3600,
+		OperatorsNonce: 2,
+	}
+	s.SetClientState(&cs)
+	// the value is the client state wrapped in Any
+	require.Equal(t, clienttypes.MustMarshalClientState(s.cdc, &cs), s.store.Get(host.ClientStateKey()))
+
+	stored, err := s.GetClientState()
+	require.NoError(t, err)
+	require.Equal(t, cs, *stored)
+	nonce, err := s.GetNonce()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), nonce)
+
+	s.SetFrozen(cs)
+	require.False(t, cs.Frozen, "the given client state must not be modified")
+	stored, err = s.GetClientState()
+	require.NoError(t, err)
+	require.True(t, stored.Frozen)
+}
+
+func TestClientStoreConsensusStates(t *testing.T) {
+	s := newTestClientStore(t)
+
+	_, err := s.GetConsensusState(clienttypes.NewHeight(0, 1))
+	require.ErrorIs(t, err, clienttypes.ErrConsensusStateNotFound)
+
+	heights := []clienttypes.Height{
+		clienttypes.NewHeight(0, 1),
+		clienttypes.NewHeight(0, 2),
+		clienttypes.NewHeight(1, 1),
+	}
+	for i, h := range heights {
+		s.SetConsensusState(h, &ConsensusState{StateId: []byte{byte(i)}, Timestamp: uint64(i)})
+		// the processed time, height and signer must be skipped by the iteration
+		s.SetProcessedTime(h, uint64(i))
+		s.SetProcessedHeight(h, clienttypes.NewHeight(0, uint64(i)))
+		s.SetConsensusSigner(h, common.BytesToAddress([]byte{byte(i)}))
+	}
+	for i, h := range heights {
+		consensusState, err := s.GetConsensusState(h)
+		require.NoError(t, err)
+		require.Equal(t, &ConsensusState{StateId: []byte{byte(i)}, Timestamp: uint64(i)}, consensusState)
+		require.Equal(t, clienttypes.MustMarshalConsensusState(s.cdc, consensusState), s.store.Get(host.ConsensusStateKey(h)))
+	}
+
+	var iterated []clienttypes.Height
+	require.NoError(t, s.IterateConsensusStates(func(height clienttypes.Height, consensusState *ConsensusState) bool {
+		iterated = append(iterated, height)
+		return false
+	}))
+	require.Equal(t, heights, iterated)
+
+	iterated = nil
+	require.NoError(t, s.IterateConsensusStates(func(height clienttypes.Height, consensusState *ConsensusState) bool {
+		iterated = append(iterated, height)
+		return true
+	}))
+	require.Equal(t, heights[:1], iterated)
+
+	// a consensus state of another client type is rejected
+	s.store.Set(host.ConsensusStateKey(heights[0]), []byte{0xff})
+	_, err = s.GetConsensusState(heights[0])
+	require.ErrorIs(t, err, clienttypes.ErrInvalidConsensus)
+	require.ErrorIs(t, s.IterateConsensusStates(func(clienttypes.Height, *ConsensusState) bool { return false }), clienttypes.ErrInvalidConsensus)
+}
+
+func TestClientStoreProcessedTimeAndHeight(t *testing.T) {
+	s := newTestClientStore(t)
+	height := clienttypes.NewHeight(0, 10)
+
+	_, ok := s.GetProcessedTime(height)
+	require.False(t, ok)
+	_, ok = s.GetProcessedHeight(height)
+	require.False(t, ok)
+
+	s.SetProcessedTime(height, 1234)
+	s.SetProcessedHeight(height, clienttypes.NewHeight(1, 20))
+	// the byte layouts are compatible with the previous versions
+	require.Equal(t, sdk.Uint64ToBigEndian(1234), s.store.Get([]byte("consensusStates/0-10/processedTime")))
+	require.Equal(t, []byte("1-20"), s.store.Get([]byte("consensusStates/0-10/processedHeight")))
+
+	processedTime, ok := s.GetProcessedTime(height)
+	require.True(t, ok)
+	require.Equal(t, uint64(1234), processedTime)
+	processedHeight, ok := s.GetProcessedHeight(height)
+	require.True(t, ok)
+	require.Equal(t, clienttypes.NewHeight(1, 20), processedHeight)
+
+	// the exported functions share the same layout
+	processedTime, ok = GetProcessedTime(s.store, height)
+	require.True(t, ok)
+	require.Equal(t, uint64(1234), processedTime)
+
+	s.DeleteProcessedTime(height)
+	s.DeleteProcessedHeight(height)
+	_, ok = s.GetProcessedTime(height)
+	require.False(t, ok)
+	_, ok = s.GetProcessedHeight(height)
+	require.False(t, ok)
+
+	// an invalid height is treated as not found
+	s.store.Set(ProcessedHeightKey(height), []byte("invalid"))
+	_, ok = s.GetProcessedHeight(height)
+	require.False(t, ok)
+}
+
+func TestClientStoreEnclaveKeys(t *testing.T) {
+	s := newTestClientStore(t)
+	ek := common.HexToAddress("0xcb96F8d6C2d543102184d679D7829b39434E4EEc")
+	operator := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	expiredAt := time.Unix(1700000000, 0)
+
+	require.False(t, s.HasEnclaveKey(ek))
+	info, err := s.GetEnclaveKeyInfo(ek)
+	require.NoError(t, err)
+	require.Nil(t, info)
+	_, found, err := s.GetEnclaveKeyExpiration(ek)
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = s.GetOperatorBinding(ek)
+	require.NoError(t, err)
+	require.False(t, found)
+	require.Error(t, s.SetOperatorBinding(ek, operator))
+
+	s.SetEnclaveKeyInfo(ek, EKInfo{ExpiredAt: uint64(expiredAt.Unix()), Operator: operator})
+	// the key and the byte layout are compatible with the previous versions
+	require.Equal(t,
+		append(sdk.Uint64ToBigEndian(uint64(expiredAt.Unix())), operator.Bytes()...),
+		s.store.Get([]byte("aux/enclave_keys/0xcb96F8d6C2d543102184d679D7829b39434E4EEc")),
+	)
+
+	require.True(t, s.HasEnclaveKey(ek))
+	info, err = s.GetEnclaveKeyInfo(ek)
+	require.NoError(t, err)
+	require.Equal(t, &EKInfo{ExpiredAt: uint64(expiredAt.Unix()), Operator: operator}, info)
+	exp, found, err := s.GetEnclaveKeyExpiration(ek)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, expiredAt, exp)
+
+	newOperator := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	require.NoError(t, s.SetOperatorBinding(ek, newOperator))
+	op, found, err := s.GetOperatorBinding(ek)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, newOperator, op)
+	// the expiration is kept
+	exp, _, err = s.GetEnclaveKeyExpiration(ek)
+	require.NoError(t, err)
+	require.Equal(t, expiredAt, exp)
+
+	// the ClientState methods share the same layout
+	var cs ClientState
+	require.True(t, cs.Contains(s.store, ek))
+	info, err = cs.GetEKInfo(s.store, ek)
+	require.NoError(t, err)
+	require.Equal(t, newOperator, info.Operator)
+
+	// a corrupted entry is rejected
+	s.store.Set(enclaveKeyPath(ek), []byte{0x01})
+	_, err = s.GetEnclaveKeyInfo(ek)
+	require.Error(t, err)
+	_, _, err = s.GetEnclaveKeyExpiration(ek)
+	require.Error(t, err)
+	_, _, err = s.GetOperatorBinding(ek)
+	require.Error(t, err)
+	require.Error(t, s.SetOperatorBinding(ek, operator))
+}
+
+func TestClientStorePruneExpiredEnclaveKeys(t *testing.T) {
+	s := newTestClientStore(t)
+	base := time.Unix(1700000000, 0)
+	keys := []common.Address{
+		common.HexToAddress("0x0000000000000000000000000000000000000001"),
+		common.HexToAddress("0x0000000000000000000000000000000000000002"),
+		common.HexToAddress("0x0000000000000000000000000000000000000003"),
+	}
+	// registered in the reverse order of the expiration
+	for i := len(keys) - 1; i >= 0; i-- {
+		s.SetEnclaveKeyInfo(keys[i], EKInfo{ExpiredAt: uint64(base.Add(time.Duration(i) * time.Hour).Unix())})
+	}
+	require.Equal(t, keys[0].Bytes(), s.store.Get(append(append([]byte("aux/enclave_keys_by_expiry/"), sdk.Uint64ToBigEndian(uint64(base.Unix()))...), keys[0].Bytes()...)))
+
+	// a key stored by the previous versions has no index entry
+	legacy := common.HexToAddress("0x0000000000000000000000000000000000000004")
+	s.store.Set(enclaveKeyPath(legacy), append(sdk.Uint64ToBigEndian(uint64(base.Unix())), common.Address{}.Bytes()...))
+
+	// the keys are pruned in the order of the expiration up to the limit
+	cutoff := base.Add(90 * time.Minute)
+	pruned, err := s.PruneExpiredEnclaveKeys(cutoff, 1, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []prunedEnclaveKey{{EnclaveKey: keys[0], ExpiredAt: uint64(base.Unix())}}, pruned)
+	require.False(t, s.HasEnclaveKey(keys[0]))
+	pruned, err = s.PruneExpiredEnclaveKeys(cutoff, 10, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []prunedEnclaveKey{{EnclaveKey: keys[1], ExpiredAt: uint64(base.Add(time.Hour).Unix())}}, pruned)
+	require.True(t, s.HasEnclaveKey(keys[2]))
+	pruned, err = s.PruneExpiredEnclaveKeys(cutoff, 10, time.Hour)
+	require.NoError(t, err)
+	require.Empty(t, pruned)
+
+	// the legacy key is kept and still readable
+	require.True(t, s.HasEnclaveKey(legacy))
+	var cs ClientState
+	active, err := cs.IsActiveKey(s.store, legacy, base)
+	require.NoError(t, err)
+	require.True(t, active)
+	active, err = cs.IsActiveKey(s.store, legacy, base.Add(time.Second))
+	require.NoError(t, err)
+	require.False(t, active)
+	active, err = cs.IsActiveKey(s.store, keys[0], base)
+	require.NoError(t, err)
+	require.False(t, active, "a pruned key is not active")
+
+	// the legacy key is indexed once its info is stored again
+	require.NoError(t, s.SetOperatorBinding(legacy, common.HexToAddress("0x1111111111111111111111111111111111111111")))
+	pruned, err = s.PruneExpiredEnclaveKeys(cutoff, 10, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []prunedEnclaveKey{{EnclaveKey: legacy, ExpiredAt: uint64(base.Unix())}}, pruned)
+
+	// the index entry of the previous expiration is replaced
+	s.SetEnclaveKeyInfo(keys[2], EKInfo{ExpiredAt: uint64(base.Unix())})
+	s.SetEnclaveKeyInfo(keys[2], EKInfo{ExpiredAt: uint64(base.Add(3 * time.Hour).Unix())})
+	pruned, err = s.PruneExpiredEnclaveKeys(base.Add(3*time.Hour), 10, time.Hour)
+	require.NoError(t, err)
+	require.Empty(t, pruned)
+	require.True(t, s.HasEnclaveKey(keys[2]))
+}
+
+func TestClientStorePruneEnclaveKeyMarkers(t *testing.T) {
+	s := newTestClientStore(t)
+	base := time.Unix(1700000000, 0)
+	retention := 2 * time.Hour
+	countAux := func() int {
+		iterator := storetypes.KVStorePrefixIterator(s.store, []byte("aux/"))
+		defer iterator.Close()
+		var n int
+		for ; iterator.Valid(); iterator.Next() {
+			n++
+		}
+		return n
+	}
+	// pruneAll prunes the entries before `cutoff` with the limit until nothing is deleted
+	pruneAll := func(cutoff time.Time) {
+		for {
+			prev := countAux()
+			pruned, err := s.PruneExpiredEnclaveKeys(cutoff, 10, retention)
+			require.NoError(t, err)
+			if len(pruned) == 0 && countAux() == prev {
+				return
+			}
+		}
+	}
+
+	// a key which never expires within the test
+	active := common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff")
+	s.SetEnclaveKeyInfo(active, EKInfo{ExpiredAt: uint64(base.Add(24 * time.Hour).Unix())})
+	baseline := countAux()
+
+	// the keys rotated every minute
+	var keys []common.Address
+	for i := 0; i < 35; i++ {
+		k := common.BigToAddress(big.NewInt(int64(i + 1)))
+		s.SetEnclaveKeyInfo(k, EKInfo{ExpiredAt: uint64(base.Add(time.Duration(i) * time.Minute).Unix())})
+		keys = append(keys, k)
+	}
+
+	// the pruned keys are replaced with the markers
+	pruneAll(base.Add(time.Hour))
+	for _, k := range keys {
+		require.False(t, s.HasEnclaveKey(k))
+		require.True(t, s.IsEnclaveKeyPruned(k))
+	}
+	require.Equal(t, baseline+2*len(keys), countAux())
+
+	// the markers are removed after the retention
+	pruneAll(base.Add(time.Hour + retention))
+	for _, k := range keys {
+		require.False(t, s.IsEnclaveKeyPruned(k))
+	}
+	require.Equal(t, baseline, countAux())
+	require.True(t, s.HasEnclaveKey(active))
+}
+
+func TestParseEnclaveKeyEntry(t *testing.T) {
+	s := newTestClientStore(t)
+	ek := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	info := EKInfo{ExpiredAt: 1700000000, Operator: common.HexToAddress("0x0000000000000000000000000000000000000002")}
+	s.SetEnclaveKeyInfo(ek, info)
+
+	// the prefix query does not return the entries of the expiry index
+	iterator := storetypes.KVStorePrefixIterator(s.store, []byte(EnclaveKeyPrefix))
+	defer iterator.Close()
+	var n int
+	for ; iterator.Valid(); iterator.Next() {
+		n++
+		addr, parsed, err := ParseEnclaveKeyEntry(iterator.Key(), iterator.Value())
+		require.NoError(t, err)
+		require.Equal(t, ek, addr)
+		require.Equal(t, info, *parsed)
+
+		// the key relative to the prefix is also accepted
+		addr, _, err = ParseEnclaveKeyEntry(iterator.Key()[len(EnclaveKeyPrefix):], iterator.Value())
+		require.NoError(t, err)
+		require.Equal(t, ek, addr)
+	}
+	require.Equal(t, 1, n)
+
+	_, _, err := ParseEnclaveKeyEntry([]byte(EnclaveKeyPrefix+"invalid"), make([]byte, 28))
+	require.Error(t, err)
+	_, _, err = ParseEnclaveKeyEntry(enclaveKeyPath(ek), []byte{0x01})
+	require.Error(t, err)
+}
@@ -20,6 +20,8 @@ func RegisterInterfaces(registry codectypes.InterfaceRegistry) {
 		(*exported.ClientMessage)(nil),
 		&UpdateClientMessage{},
 		&RegisterEnclaveKeyMessage{},
+		&DCAPRegisterEnclaveKeyMessage{},
 		&UpdateOperatorsMessage{},
+		&UpdateClientParamsMessage{},
 	)
 }
@@ -1,6 +1,12 @@
 package types
 
-import "github.com/cosmos/ibc-go/v8/modules/core/exported"
+import (
+	"fmt"
+
+	errorsmod "cosmossdk.io/errors"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+)
 
 var _ exported.ConsensusState = (*ConsensusState)(nil)
 
@@ -13,6 +19,31 @@ func (cs ConsensusState) GetTimestamp() uint64 {
 	return cs.Timestamp
 }
 
+// IsZero returns true if the consensus state is the zero consensus state, i.e. it has neither a state ID nor a timestamp.
+// The zero consensus state is only stored by Initialize at the zero height because the client has no state of the ELC yet.
+func (cs ConsensusState) IsZero() bool {
+	return len(cs.StateId) == 0 && cs.Timestamp == 0
+}
+
+// ValidateBasic returns an error if the consensus state is neither the zero consensus state nor has a valid state ID
 func (cs ConsensusState) ValidateBasic() error {
+	if cs.IsZero() {
+		return nil
+	}
+	if err := ValidateStateID(cs.StateId); err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidConsensus, "invalid state ID: %v", err)
+	}
+	return nil
+}
+
+// ValidateStateID returns an error if `bz` is not a 32-byte non-zero state ID
+func ValidateStateID(bz []byte) error {
+	var id StateID
+	if len(bz) != len(id) {
+		return fmt.Errorf("state ID must be %v bytes: actual=%v", len(id), len(bz))
+	}
+	if StateID(bz).IsZero() {
+		return fmt.Errorf("state ID must be non-zero")
+	}
 	return nil
 }
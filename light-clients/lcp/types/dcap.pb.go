@@ -0,0 +1,727 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: ibc/lightclients/lcp/v1/dcap.proto
+
+package types
+
+import (
+	fmt "fmt"
+	_ "github.com/cosmos/gogoproto/gogoproto"
+	proto "github.com/cosmos/gogoproto/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// DCAPAttestation is the DCAP attestation of an enclave key with the collateral issued by Intel PCS to verify it
+type DCAPAttestation struct {
+	// the SGX ECDSA quote (version 3) whose certification data is the PCK certificate chain
+	Quote []byte `protobuf:"bytes,1,opt,name=quote,proto3" json:"quote,omitempty"`
+	// the response body of the TCB info of the platform's FMSPC
+	TcbInfo []byte `protobuf:"bytes,2,opt,name=tcb_info,json=tcbInfo,proto3" json:"tcb_info,omitempty"`
+	// the PEM-encoded certificate chain of the TCB info signature
+	TcbInfoIssuerChain []byte `protobuf:"bytes,3,opt,name=tcb_info_issuer_chain,json=tcbInfoIssuerChain,proto3" json:"tcb_info_issuer_chain,omitempty"`
+	// the response body of the identity of the quoting enclave
+	QeIdentity []byte `protobuf:"bytes,4,opt,name=qe_identity,json=qeIdentity,proto3" json:"qe_identity,omitempty"`
+	// the PEM-encoded certificate chain of the QE identity signature
+	QeIdentityIssuerChain []byte `protobuf:"bytes,5,opt,name=qe_identity_issuer_chain,json=qeIdentityIssuerChain,proto3" json:"qe_identity_issuer_chain,omitempty"`
+}
+
+func (m *DCAPAttestation) Reset()         { *m = DCAPAttestation{} }
+func (m *DCAPAttestation) String() string { return proto.CompactTextString(m) }
+func (*DCAPAttestation) ProtoMessage()    {}
+func (*DCAPAttestation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6fa6164b8b50eec0, []int{0}
+}
+func (m *DCAPAttestation) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DCAPAttestation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DCAPAttestation.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DCAPAttestation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DCAPAttestation.Merge(m, src)
+}
+func (m *DCAPAttestation) XXX_Size() int {
+	return m.Size()
+}
+func (m *DCAPAttestation) XXX_DiscardUnknown() {
+	xxx_messageInfo_DCAPAttestation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DCAPAttestation proto.InternalMessageInfo
+
+type DCAPRegisterEnclaveKeyMessage struct {
+	Attestation       DCAPAttestation `protobuf:"bytes,1,opt,name=attestation,proto3" json:"attestation"`
+	OperatorSignature []byte          `protobuf:"bytes,2,opt,name=operator_signature,json=operatorSignature,proto3" json:"operator_signature,omitempty"`
+}
+
+func (m *DCAPRegisterEnclaveKeyMessage) Reset()         { *m = DCAPRegisterEnclaveKeyMessage{} }
+func (m *DCAPRegisterEnclaveKeyMessage) String() string { return proto.CompactTextString(m) }
+func (*DCAPRegisterEnclaveKeyMessage) ProtoMessage()    {}
+func (*DCAPRegisterEnclaveKeyMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6fa6164b8b50eec0, []int{1}
+}
+func (m *DCAPRegisterEnclaveKeyMessage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DCAPRegisterEnclaveKeyMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DCAPRegisterEnclaveKeyMessage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DCAPRegisterEnclaveKeyMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DCAPRegisterEnclaveKeyMessage.Merge(m, src)
+}
+func (m *DCAPRegisterEnclaveKeyMessage) XXX_Size() int {
+	return m.Size()
+}
+func (m *DCAPRegisterEnclaveKeyMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_DCAPRegisterEnclaveKeyMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DCAPRegisterEnclaveKeyMessage proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*DCAPAttestation)(nil), "ibc.lightclients.lcp.v1.DCAPAttestation")
+	proto.RegisterType((*DCAPRegisterEnclaveKeyMessage)(nil), "ibc.lightclients.lcp.v1.DCAPRegisterEnclaveKeyMessage")
+}
+
+func init() {
+	proto.RegisterFile("ibc/lightclients/lcp/v1/dcap.proto", fileDescriptor_6fa6164b8b50eec0)
+}
+
+var fileDescriptor_6fa6164b8b50eec0 = []byte{
+	// 370 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0x41, 0x6b, 0x1a, 0x41,
+	0x14, 0xc7, 0x77, 0x5b, 0x6d, 0xcb, 0x58, 0x28, 0x1d, 0x94, 0x6e, 0x0b, 0x5d, 0x8b, 0x27, 0x2f,
+	0xee, 0x62, 0x0b, 0xcd, 0x59, 0x4d, 0x0e, 0x12, 0x02, 0x62, 0x72, 0xca, 0x65, 0x99, 0x1d, 0x9f,
+	0xe3, 0xc0, 0x66, 0x66, 0xdd, 0x79, 0x2b, 0xf8, 0x2d, 0xf2, 0x11, 0xf2, 0x71, 0xbc, 0xc5, 0x63,
+	0x4e, 0x21, 0xd1, 0x2f, 0x12, 0x76, 0x54, 0xdc, 0x04, 0x72, 0xdb, 0x9d, 0xdf, 0x8f, 0x07, 0xff,
+	0xff, 0x7b, 0xa4, 0x25, 0x63, 0x1e, 0x26, 0x52, 0xcc, 0x90, 0x27, 0x12, 0x14, 0x9a, 0x30, 0xe1,
+	0x69, 0xb8, 0xe8, 0x86, 0x13, 0xce, 0xd2, 0x20, 0xcd, 0x34, 0x6a, 0xfa, 0x43, 0xc6, 0x3c, 0x28,
+	0x3b, 0x41, 0xc2, 0xd3, 0x60, 0xd1, 0xfd, 0x55, 0x17, 0x5a, 0x68, 0xeb, 0x84, 0xc5, 0xd7, 0x4e,
+	0x6f, 0xdd, 0xbb, 0xe4, 0xdb, 0xe9, 0xa0, 0x37, 0xea, 0x21, 0x82, 0x41, 0x86, 0x52, 0x2b, 0x5a,
+	0x27, 0xd5, 0x79, 0xae, 0x11, 0x3c, 0xf7, 0x8f, 0xdb, 0xfe, 0x3a, 0xde, 0xfd, 0xd0, 0x9f, 0xe4,
+	0x0b, 0xf2, 0x38, 0x92, 0x6a, 0xaa, 0xbd, 0x0f, 0x16, 0x7c, 0x46, 0x1e, 0x0f, 0xd5, 0x54, 0xd3,
+	0x2e, 0x69, 0x1c, 0x50, 0x24, 0x8d, 0xc9, 0x21, 0x8b, 0xf8, 0x8c, 0x49, 0xe5, 0x7d, 0xb4, 0x1e,
+	0xdd, 0x7b, 0x43, 0x8b, 0x06, 0x05, 0xa1, 0x4d, 0x52, 0x9b, 0x43, 0x24, 0x27, 0xa0, 0x50, 0xe2,
+	0xd2, 0xab, 0x58, 0x91, 0xcc, 0x61, 0xb8, 0x7f, 0xa1, 0x27, 0xc4, 0x2b, 0x09, 0xaf, 0xc7, 0x56,
+	0xad, 0xdd, 0x38, 0xda, 0xa5, 0xc9, 0xad, 0x3b, 0x97, 0xfc, 0x2e, 0x12, 0x8d, 0x41, 0x48, 0x83,
+	0x90, 0x9d, 0x29, 0x9e, 0xb0, 0x05, 0x9c, 0xc3, 0xf2, 0x02, 0x8c, 0x61, 0x02, 0xe8, 0x88, 0xd4,
+	0xd8, 0x31, 0xae, 0x4d, 0x59, 0xfb, 0xdb, 0x0e, 0xde, 0x29, 0x2e, 0x78, 0x53, 0x4f, 0xbf, 0xb2,
+	0x7a, 0x6c, 0x3a, 0xe3, 0xf2, 0x08, 0xda, 0x21, 0x54, 0xa7, 0x90, 0x31, 0xd4, 0x59, 0x64, 0xa4,
+	0x50, 0x0c, 0xf3, 0x0c, 0xf6, 0x2d, 0x7d, 0x3f, 0x90, 0xcb, 0x03, 0xe8, 0x5f, 0xad, 0x9e, 0x7d,
+	0x67, 0xb5, 0xf1, 0xdd, 0xf5, 0xc6, 0x77, 0x9f, 0x36, 0xbe, 0x7b, 0xbb, 0xf5, 0x9d, 0xf5, 0xd6,
+	0x77, 0x1e, 0xb6, 0xbe, 0x73, 0xfd, 0x5f, 0x48, 0x9c, 0xe5, 0x71, 0xc0, 0xf5, 0x4d, 0x38, 0x61,
+	0xc8, 0x6c, 0xe6, 0x84, 0xc5, 0xc5, 0xb2, 0x3b, 0x42, 0xef, 0x0e, 0xa0, 0x53, 0xbe, 0x00, 0x5c,
+	0xa6, 0x60, 0xe2, 0x4f, 0x76, 0xa3, 0xff, 0x5e, 0x06, 0x00, 0xd3, 0x05, 0xfd, 0x6f, 0x26, 0x02,
+	0x00, 0x00,
+}
+
+func (m *DCAPAttestation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DCAPAttestation) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DCAPAttestation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.QeIdentityIssuerChain) > 0 {
+		i -= len(m.QeIdentityIssuerChain)
+		copy(dAtA[i:], m.QeIdentityIssuerChain)
+		i = encodeVarintDcap(dAtA, i, uint64(len(m.QeIdentityIssuerChain)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.QeIdentity) > 0 {
+		i -= len(m.QeIdentity)
+		copy(dAtA[i:], m.QeIdentity)
+		i = encodeVarintDcap(dAtA, i, uint64(len(m.QeIdentity)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.TcbInfoIssuerChain) > 0 {
+		i -= len(m.TcbInfoIssuerChain)
+		copy(dAtA[i:], m.TcbInfoIssuerChain)
+		i = encodeVarintDcap(dAtA, i, uint64(len(m.TcbInfoIssuerChain)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.TcbInfo) > 0 {
+		i -= len(m.TcbInfo)
+		copy(dAtA[i:], m.TcbInfo)
+		i = encodeVarintDcap(dAtA, i, uint64(len(m.TcbInfo)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Quote) > 0 {
+		i -= len(m.Quote)
+		copy(dAtA[i:], m.Quote)
+		i = encodeVarintDcap(dAtA, i, uint64(len(m.Quote)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DCAPRegisterEnclaveKeyMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DCAPRegisterEnclaveKeyMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DCAPRegisterEnclaveKeyMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.OperatorSignature) > 0 {
+		i -= len(m.OperatorSignature)
+		copy(dAtA[i:], m.OperatorSignature)
+		i = encodeVarintDcap(dAtA, i, uint64(len(m.OperatorSignature)))
+		i--
+		dAtA[i] = 0x12
+	}
+	{
+		size, err := m.Attestation.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintDcap(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintDcap(dAtA []byte, offset int, v uint64) int {
+	offset -= sovDcap(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *DCAPAttestation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Quote)
+	if l > 0 {
+		n += 1 + l + sovDcap(uint64(l))
+	}
+	l = len(m.TcbInfo)
+	if l > 0 {
+		n += 1 + l + sovDcap(uint64(l))
+	}
+	l = len(m.TcbInfoIssuerChain)
+	if l > 0 {
+		n += 1 + l + sovDcap(uint64(l))
+	}
+	l = len(m.QeIdentity)
+	if l > 0 {
+		n += 1 + l + sovDcap(uint64(l))
+	}
+	l = len(m.QeIdentityIssuerChain)
+	if l > 0 {
+		n += 1 + l + sovDcap(uint64(l))
+	}
+	return n
+}
+
+func (m *DCAPRegisterEnclaveKeyMessage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Attestation.Size()
+	n += 1 + l + sovDcap(uint64(l))
+	l = len(m.OperatorSignature)
+	if l > 0 {
+		n += 1 + l + sovDcap(uint64(l))
+	}
+	return n
+}
+
+func sovDcap(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozDcap(x uint64) (n int) {
+	return sovDcap(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *DCAPAttestation) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcap
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DCAPAttestation: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DCAPAttestation: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Quote", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcap
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcap
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDcap
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Quote = append(m.Quote[:0], dAtA[iNdEx:postIndex]...)
+			if m.Quote == nil {
+				m.Quote = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TcbInfo", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcap
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcap
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDcap
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TcbInfo = append(m.TcbInfo[:0], dAtA[iNdEx:postIndex]...)
+			if m.TcbInfo == nil {
+				m.TcbInfo = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TcbInfoIssuerChain", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcap
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcap
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDcap
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TcbInfoIssuerChain = append(m.TcbInfoIssuerChain[:0], dAtA[iNdEx:postIndex]...)
+			if m.TcbInfoIssuerChain == nil {
+				m.TcbInfoIssuerChain = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QeIdentity", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcap
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcap
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDcap
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QeIdentity = append(m.QeIdentity[:0], dAtA[iNdEx:postIndex]...)
+			if m.QeIdentity == nil {
+				m.QeIdentity = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QeIdentityIssuerChain", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcap
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcap
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDcap
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QeIdentityIssuerChain = append(m.QeIdentityIssuerChain[:0], dAtA[iNdEx:postIndex]...)
+			if m.QeIdentityIssuerChain == nil {
+				m.QeIdentityIssuerChain = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcap(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthDcap
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DCAPRegisterEnclaveKeyMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDcap
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DCAPRegisterEnclaveKeyMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DCAPRegisterEnclaveKeyMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attestation", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcap
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDcap
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthDcap
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Attestation.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperatorSignature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDcap
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthDcap
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDcap
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperatorSignature = append(m.OperatorSignature[:0], dAtA[iNdEx:postIndex]...)
+			if m.OperatorSignature == nil {
+				m.OperatorSignature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDcap(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthDcap
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipDcap(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowDcap
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowDcap
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowDcap
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthDcap
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupDcap
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthDcap
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthDcap        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowDcap          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupDcap = fmt.Errorf("proto: unexpected end of group")
+)
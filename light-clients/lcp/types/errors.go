@@ -13,4 +13,5 @@ var (
 	ErrDelayPeriodNotPassed        = errorsmod.Register(ModuleName, 6, "packet-specified delay period has not been reached")
 	ErrInvalidMisbehaviour         = errorsmod.Register(ModuleName, 7, "invalid misbehaviour")
 	ErrRetrieveClientID            = errorsmod.Register(ModuleName, 8, "failed to retrieve client id")
+	ErrUnsupportedMessageVersion   = errorsmod.Register(ModuleName, 9, "unsupported proxy message version")
 )
@@ -6,9 +6,21 @@ const (
 	AttributeKeyExpiredAt       = "expired_at"
 	AttributeKeyOperator        = "operator"
 
+	EventTypePruneEnclaveKey = "prune_enclave_key"
+
 	EventTypeUpdateOperators         = "update_operators"
 	AttributeKeyNonce                = "nonce"
 	AttributeKeyNewOperators         = "new_operators"
 	AttributeKeyThresholdNumerator   = "threshold_numerator"
 	AttributeKeyThresholdDenominator = "threshold_denominator"
+
+	EventTypeUpdateClientParams      = "update_client_params"
+	AttributeKeyAllowedQuoteStatuses = "allowed_quote_statuses"
+	AttributeKeyAllowedAdvisoryIDs   = "allowed_advisory_ids"
+	AttributeKeyKeyExpiration        = "key_expiration"
+
+	EventTypeUpdateGapExceeded = "update_gap_exceeded"
+	AttributeKeyLastUpdateTime = "last_update_time"
+	AttributeKeyUpdateGap      = "update_gap"
+	AttributeKeyMaxUpdateGap   = "max_update_gap"
 )
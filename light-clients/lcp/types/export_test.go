@@ -0,0 +1,4 @@
+package types
+
+// RecoverSigner exports recoverSigner for the tests in the types_test package
+var RecoverSigner = recoverSigner
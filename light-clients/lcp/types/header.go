@@ -4,11 +4,45 @@ import (
 	"fmt"
 
 	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/sgx/dcap"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// MaxProxyMessageSize is the maximum size of the proxy message of an UpdateClientMessage.
+// The size is checked before the message is decoded, so an oversized message is rejected without the cost to decode it.
+//
+// An update state message is about 500 bytes plus the emitted states, which are only included in the first update and
+// are a few KB even for the client states of Ethereum with the sync committee. The largest messages are the misbehaviours
+// carrying the conflicting headers of the origin chain, e.g. about 130 KB for two Tendermint headers with the validator
+// sets of 180 validators. The maximum keeps about twice the margin over them.
+const MaxProxyMessageSize = 256 * 1024
+
+// The maximum sizes of the fields of a RegisterEnclaveKeyMessage, which are checked before the AVR and the certificate are parsed.
+//
+// An AVR of IAS is about 1.2 KB with 8 advisory IDs, and each additional advisory ID adds about 20 bytes, so the AVR listing
+// MaxAVRAdvisoryIDs advisory IDs is about 6.2 KB. The signature is 256 bytes for the RSA-2048 key of the Intel Report Signing
+// Certificate, which is a DER certificate of about 1.2 KB. The maximums keep a margin for a signing key of RSA-4096.
+// The message carries only the signing certificate, and ias.VerifyReport accepts only the chain of it and the root certificate,
+// so the depth of the certificate chain is always 2.
+const (
+	MaxAVRReportSize      = 8 * 1024
+	MaxAVRSignatureSize   = 512
+	MaxAVRSigningCertSize = 4 * 1024
+	// MaxAVRAdvisoryIDs is the maximum number of the advisory IDs in an AVR, which is checked before the quote policy.
+	// A quote policy allows at most MaxAllowedAdvisoryIDs advisory IDs anyway.
+	MaxAVRAdvisoryIDs = MaxAllowedAdvisoryIDs
+)
+
 type ProxyMessage interface{}
 
+// ValidateProxyMessageSize returns an error if the size of the proxy message exceeds MaxProxyMessageSize
+func ValidateProxyMessageSize(bz []byte) error {
+	if l := len(bz); l > MaxProxyMessageSize {
+		return fmt.Errorf("proxy message is too large: max=%v actual=%v", MaxProxyMessageSize, l)
+	}
+	return nil
+}
+
 var _ exported.ClientMessage = (*UpdateClientMessage)(nil)
 
 func (UpdateClientMessage) ClientType() string {
@@ -36,13 +70,13 @@ func (ucm UpdateClientMessage) ValidateBasic() error {
 }
 
 func (ucm UpdateClientMessage) GetProxyMessage() (ProxyMessage, error) {
+	if err := ValidateProxyMessageSize(ucm.ProxyMessage); err != nil {
+		return nil, err
+	}
 	m, err := EthABIDecodeHeaderedProxyMessage(ucm.ProxyMessage)
 	if err != nil {
 		return nil, err
 	}
-	if m.Version != LCPMessageVersion {
-		return nil, fmt.Errorf("unexpected commitment version: expected=%v actual=%v", LCPMessageVersion, m.Version)
-	}
 	if m.Type == LCPMessageTypeUpdateState {
 		return m.GetUpdateStateProxyMessage()
 	} else if m.Type == LCPMessageTypeMisbehaviour {
@@ -58,10 +92,49 @@ func (RegisterEnclaveKeyMessage) ClientType() string {
 	return ClientTypeLCP
 }
 
-func (RegisterEnclaveKeyMessage) ValidateBasic() error {
+// ValidateBasic returns an error if a field of the message is empty or exceeds its maximum size
+func (m RegisterEnclaveKeyMessage) ValidateBasic() error {
+	for _, f := range []struct {
+		name string
+		bz   []byte
+		max  int
+	}{
+		{"report", m.Report, MaxAVRReportSize},
+		{"signature", m.Signature, MaxAVRSignatureSize},
+		{"signing certificate", m.SigningCert, MaxAVRSigningCertSize},
+	} {
+		if l := len(f.bz); l == 0 {
+			return fmt.Errorf("%v cannot be empty", f.name)
+		} else if l > f.max {
+			return fmt.Errorf("%v is too large: max=%v actual=%v", f.name, f.max, l)
+		}
+	}
+	return nil
+}
+
+var _ exported.ClientMessage = (*DCAPRegisterEnclaveKeyMessage)(nil)
+
+func (DCAPRegisterEnclaveKeyMessage) ClientType() string {
+	return ClientTypeLCP
+}
+
+func (m DCAPRegisterEnclaveKeyMessage) ValidateBasic() error {
+	if len(m.Attestation.Quote) == 0 {
+		return fmt.Errorf("quote cannot be empty")
+	}
 	return nil
 }
 
+// GetCollateral returns the collateral to verify the quote of the attestation
+func (a DCAPAttestation) GetCollateral() dcap.Collateral {
+	return dcap.Collateral{
+		TCBInfo:               a.TcbInfo,
+		TCBInfoIssuerChain:    a.TcbInfoIssuerChain,
+		QEIdentity:            a.QeIdentity,
+		QEIdentityIssuerChain: a.QeIdentityIssuerChain,
+	}
+}
+
 var _ exported.ClientMessage = (*UpdateOperatorsMessage)(nil)
 
 func (UpdateOperatorsMessage) ClientType() string {
@@ -94,3 +167,27 @@ func (m UpdateOperatorsMessage) ValidateBasic() error {
 	}
 	return nil
 }
+
+var _ exported.ClientMessage = (*UpdateClientParamsMessage)(nil)
+
+func (UpdateClientParamsMessage) ClientType() string {
+	return ClientTypeLCP
+}
+
+func (m UpdateClientParamsMessage) ValidateBasic() error {
+	if m.NewKeyExpiration == 0 {
+		return fmt.Errorf("new key expiration cannot be zero")
+	}
+	if err := ValidateQuotePolicy(m.NewAllowedQuoteStatuses, m.NewAllowedAdvisoryIds); err != nil {
+		return err
+	}
+	for _, s := range m.NewAllowedQuoteStatuses {
+		if NormalizeQuoteStatus(s) == QuoteOK {
+			return fmt.Errorf("quote status %v is always allowed and cannot be included", QuoteOK)
+		}
+	}
+	if len(m.Signatures) == 0 {
+		return fmt.Errorf("signatures cannot be empty")
+	}
+	return nil
+}
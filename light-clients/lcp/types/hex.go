@@ -0,0 +1,40 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// HexBytes is a byte slice rendered as a 0x-prefixed hex string in the errors, the logs and the JSON outputs.
+// It is used for the values that operators compare with hex strings, e.g. MRENCLAVE, state IDs, enclave key addresses and signatures.
+type HexBytes []byte
+
+var (
+	_ fmt.Stringer     = HexBytes(nil)
+	_ json.Marshaler   = HexBytes(nil)
+	_ json.Unmarshaler = (*HexBytes)(nil)
+)
+
+func (b HexBytes) String() string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func (b HexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+// UnmarshalJSON decodes a hex string with or without the 0x prefix
+func (b *HexBytes) UnmarshalJSON(bz []byte) error {
+	var s string
+	if err := json.Unmarshal(bz, &s); err != nil {
+		return err
+	}
+	decoded, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid hex string: value=%q %w", s, err)
+	}
+	*b = decoded
+	return nil
+}
@@ -0,0 +1,31 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHexBytes(t *testing.T) {
+	require := require.New(t)
+
+	b := lcptypes.HexBytes{0x01, 0xab, 0xff}
+	require.Equal("0x01abff", b.String())
+	require.Equal("0x", lcptypes.HexBytes(nil).String())
+
+	bz, err := json.Marshal(b)
+	require.NoError(err)
+	require.Equal(`"0x01abff"`, string(bz))
+
+	var decoded lcptypes.HexBytes
+	require.NoError(json.Unmarshal(bz, &decoded))
+	require.Equal(b, decoded)
+	// the prefix is optional
+	require.NoError(json.Unmarshal([]byte(`"01abff"`), &decoded))
+	require.Equal(b, decoded)
+
+	require.ErrorContains(json.Unmarshal([]byte(`"0xzz"`), &decoded), "invalid hex string")
+	require.Error(json.Unmarshal([]byte(`1`), &decoded))
+}
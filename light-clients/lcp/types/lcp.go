@@ -22,6 +22,8 @@ const (
 	QuoteConfigurationNeeded               = "CONFIGURATION_NEEDED"
 	QuoteSwHardeningNeeded                 = "SW_HARDENING_NEEDED"
 	QuoteConfigurationAndSwHardeningNeeded = "CONFIGURATION_AND_SW_HARDENING_NEEDED"
+	// QuoteOutOfDateConfigurationNeeded is only reported by the DCAP attestations, see dcap.TCBStatus.QuoteStatus
+	QuoteOutOfDateConfigurationNeeded = "OUT_OF_DATE_CONFIGURATION_NEEDED"
 
 	ChainTypeEVM    ChainType = 1
 	ChainTypeCosmos ChainType = 2
@@ -41,6 +43,19 @@ var (
 		},
 	}
 
+	DCAPRegisterEnclaveKeyTypes = apitypes.Types{
+		"EIP712Domain": []apitypes.Type{
+			{Name: "name", Type: "string"},
+			{Name: "version", Type: "string"},
+			{Name: "chainId", Type: "uint256"},
+			{Name: "verifyingContract", Type: "address"},
+			{Name: "salt", Type: "bytes32"},
+		},
+		"DCAPRegisterEnclaveKey": []apitypes.Type{
+			{Name: "quote", Type: "bytes"},
+		},
+	}
+
 	UpdateOperatorsTypes = apitypes.Types{
 		"EIP712Domain": []apitypes.Type{
 			{Name: "name", Type: "string"},
@@ -57,6 +72,23 @@ var (
 			{Name: "thresholdDenominator", Type: "uint64"},
 		},
 	}
+
+	UpdateClientParamsTypes = apitypes.Types{
+		"EIP712Domain": []apitypes.Type{
+			{Name: "name", Type: "string"},
+			{Name: "version", Type: "string"},
+			{Name: "chainId", Type: "uint256"},
+			{Name: "verifyingContract", Type: "address"},
+			{Name: "salt", Type: "bytes32"},
+		},
+		"UpdateClientParams": []apitypes.Type{
+			{Name: "clientId", Type: "string"},
+			{Name: "nonce", Type: "uint64"},
+			{Name: "allowedQuoteStatuses", Type: "string[]"},
+			{Name: "allowedAdvisoryIds", Type: "string[]"},
+			{Name: "keyExpiration", Type: "uint64"},
+		},
+	}
 )
 
 type ChainType uint16
@@ -97,6 +129,17 @@ func GetRegisterEnclaveKeyTypedData(avr string) apitypes.TypedData {
 	}
 }
 
+func GetDCAPRegisterEnclaveKeyTypedData(quote []byte) apitypes.TypedData {
+	return apitypes.TypedData{
+		PrimaryType: "DCAPRegisterEnclaveKey",
+		Types:       DCAPRegisterEnclaveKeyTypes,
+		Domain:      LCPClientDomain(0, common.Address{}, common.Hash{}),
+		Message: apitypes.TypedDataMessage{
+			"quote": quote,
+		},
+	}
+}
+
 func GetUpdateOperatorsTypedData(
 	chainId int64,
 	verifyingContract common.Address,
@@ -125,6 +168,37 @@ func GetUpdateOperatorsTypedData(
 	}
 }
 
+func GetUpdateClientParamsTypedData(
+	chainId int64,
+	verifyingContract common.Address,
+	salt common.Hash,
+	clientID string,
+	nonce uint64,
+	allowedQuoteStatuses []string,
+	allowedAdvisoryIDs []string,
+	keyExpiration uint64,
+) apitypes.TypedData {
+	// the nil slices are replaced with the empty ones to be encoded as the empty arrays
+	if allowedQuoteStatuses == nil {
+		allowedQuoteStatuses = []string{}
+	}
+	if allowedAdvisoryIDs == nil {
+		allowedAdvisoryIDs = []string{}
+	}
+	return apitypes.TypedData{
+		PrimaryType: "UpdateClientParams",
+		Types:       UpdateClientParamsTypes,
+		Domain:      LCPClientDomain(chainId, verifyingContract, salt),
+		Message: apitypes.TypedDataMessage{
+			"clientId":             clientID,
+			"nonce":                fmt.Sprint(nonce),
+			"allowedQuoteStatuses": allowedQuoteStatuses,
+			"allowedAdvisoryIds":   allowedAdvisoryIDs,
+			"keyExpiration":        fmt.Sprint(keyExpiration),
+		},
+	}
+}
+
 func ComputeEIP712RegisterEnclaveKey(report string) ([]byte, error) {
 	_, raw, err := apitypes.TypedDataAndHash(GetRegisterEnclaveKeyTypedData(report))
 	if err != nil {
@@ -141,6 +215,22 @@ func ComputeEIP712RegisterEnclaveKeyHash(report string) (common.Hash, error) {
 	return crypto.Keccak256Hash(bz), nil
 }
 
+func ComputeEIP712DCAPRegisterEnclaveKey(quote []byte) ([]byte, error) {
+	_, raw, err := apitypes.TypedDataAndHash(GetDCAPRegisterEnclaveKeyTypedData(quote))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(raw), nil
+}
+
+func ComputeEIP712DCAPRegisterEnclaveKeyHash(quote []byte) (common.Hash, error) {
+	bz, err := ComputeEIP712DCAPRegisterEnclaveKey(quote)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(bz), nil
+}
+
 func ComputeEIP712UpdateOperators(
 	chainId int64,
 	verifyingContract common.Address,
@@ -160,6 +250,25 @@ func ComputeEIP712UpdateOperators(
 	return []byte(raw), nil
 }
 
+func ComputeEIP712UpdateClientParams(
+	chainId int64,
+	verifyingContract common.Address,
+	salt common.Hash,
+	clientID string,
+	nonce uint64,
+	allowedQuoteStatuses []string,
+	allowedAdvisoryIDs []string,
+	keyExpiration uint64,
+) ([]byte, error) {
+	_, raw, err := apitypes.TypedDataAndHash(
+		GetUpdateClientParamsTypedData(chainId, verifyingContract, salt, clientID, nonce, allowedQuoteStatuses, allowedAdvisoryIDs, keyExpiration),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(raw), nil
+}
+
 func RecoverAddress(commitment [32]byte, signature []byte) (common.Address, error) {
 	if l := len(signature); l != 65 {
 		return common.Address{}, fmt.Errorf("invalid signature length: expected=%v actual=%v", 65, l)
@@ -202,3 +311,15 @@ func ComputeEIP712CosmosUpdateOperators(
 ) ([]byte, error) {
 	return ComputeEIP712UpdateOperators(0, common.Address{}, ComputeCosmosChainSalt(chainID, prefix), clientID, nonce, newOperators, newOperatorThresholdNumerator, newOperatorThresholdDenominator)
 }
+
+func ComputeEIP712CosmosUpdateClientParams(
+	chainID string,
+	prefix []byte,
+	clientID string,
+	nonce uint64,
+	allowedQuoteStatuses []string,
+	allowedAdvisoryIDs []string,
+	keyExpiration uint64,
+) ([]byte, error) {
+	return ComputeEIP712UpdateClientParams(0, common.Address{}, ComputeCosmosChainSalt(chainID, prefix), clientID, nonce, allowedQuoteStatuses, allowedAdvisoryIDs, keyExpiration)
+}
@@ -156,6 +156,18 @@ type ClientState struct {
 	OperatorsNonce                uint64   `protobuf:"varint,8,opt,name=operators_nonce,json=operatorsNonce,proto3" json:"operators_nonce,omitempty"`
 	OperatorsThresholdNumerator   uint64   `protobuf:"varint,9,opt,name=operators_threshold_numerator,json=operatorsThresholdNumerator,proto3" json:"operators_threshold_numerator,omitempty"`
 	OperatorsThresholdDenominator uint64   `protobuf:"varint,10,opt,name=operators_threshold_denominator,json=operatorsThresholdDenominator,proto3" json:"operators_threshold_denominator,omitempty"`
+	// the maximum allowed gap in seconds between the block times of consecutive updates
+	// if zero, the gap is not bounded
+	MaxUpdateGap uint64 `protobuf:"varint,11,opt,name=max_update_gap,json=maxUpdateGap,proto3" json:"max_update_gap,omitempty"`
+	// the DER-encoded root certificates of Intel PCS that the PCK certificates and the collateral of DCAP attestations must chain to
+	// if empty, the registration of enclave keys with DCAP attestations is disabled
+	DcapRootCerts [][]byte `protobuf:"bytes,12,rep,name=dcap_root_certs,json=dcapRootCerts,proto3" json:"dcap_root_certs,omitempty"`
+	// the proxy message versions that the client accepts
+	// if empty, only the current version is accepted
+	AllowedMessageVersions []uint32 `protobuf:"varint,13,rep,packed,name=allowed_message_versions,json=allowedMessageVersions,proto3" json:"allowed_message_versions,omitempty"`
+	// the minimum ISV SVN of the enclave that the registered enclave keys must be attested by
+	// if zero, the ISV SVN is not constrained
+	MinimumIsvSvn uint32 `protobuf:"varint,14,opt,name=minimum_isv_svn,json=minimumIsvSvn,proto3" json:"minimum_isv_svn,omitempty"`
 }
 
 func (m *ClientState) Reset()         { *m = ClientState{} }
@@ -241,49 +253,56 @@ func init() {
 func init() { proto.RegisterFile("ibc/lightclients/lcp/v1/lcp.proto", fileDescriptor_69f4c398e914fe8d) }
 
 var fileDescriptor_69f4c398e914fe8d = []byte{
-	// 670 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x5d, 0x4f, 0x13, 0x4d,
-	0x18, 0x6d, 0xa1, 0x7c, 0x0d, 0x85, 0x37, 0xef, 0x48, 0xb0, 0xa0, 0x2c, 0x50, 0x62, 0xe4, 0x86,
-	0xad, 0xa8, 0xf1, 0x5e, 0x10, 0x63, 0x63, 0xc0, 0xb8, 0xe0, 0x0d, 0x37, 0x9b, 0xe9, 0xee, 0xe3,
-	0x76, 0xc2, 0xee, 0xcc, 0x3a, 0x33, 0x2d, 0xd4, 0xff, 0x60, 0xe2, 0x7f, 0xf0, 0xcf, 0x70, 0xc9,
-	0xa5, 0x57, 0x46, 0xe1, 0xd6, 0x1f, 0x61, 0xe6, 0x63, 0xbb, 0xf5, 0xab, 0x5e, 0xb5, 0x73, 0xce,
-	0x99, 0x93, 0x7d, 0x9e, 0x73, 0x32, 0x68, 0x93, 0x76, 0xa2, 0x56, 0x4a, 0x93, 0xae, 0x8a, 0x52,
-	0x0a, 0x4c, 0xc9, 0x56, 0x1a, 0xe5, 0xad, 0xfe, 0xae, 0xfe, 0xf1, 0x73, 0xc1, 0x15, 0xc7, 0xb7,
-	0x69, 0x27, 0xf2, 0x47, 0x25, 0xbe, 0xe6, 0xfa, 0xbb, 0xab, 0x4b, 0x09, 0x4f, 0xb8, 0xd1, 0xb4,
-	0xf4, 0x3f, 0x2b, 0x5f, 0x5d, 0xd7, 0x8e, 0x11, 0x17, 0xd0, 0xb2, 0x72, 0x6d, 0x66, 0xff, 0x59,
-	0x41, 0xf3, 0x14, 0xdd, 0x7a, 0x93, 0xc7, 0x44, 0xc1, 0xbe, 0x41, 0x0f, 0x41, 0x4a, 0x92, 0x00,
-	0xde, 0x42, 0x0b, 0xb9, 0xe0, 0x17, 0x83, 0x30, 0xb3, 0x40, 0xa3, 0xba, 0x51, 0xdd, 0xae, 0x07,
-	0x75, 0x03, 0x16, 0x22, 0x0f, 0x21, 0x49, 0x13, 0x46, 0x54, 0x4f, 0x80, 0x6c, 0x4c, 0x6c, 0x4c,
-	0x6e, 0xd7, 0x83, 0x11, 0xa4, 0xf9, 0xa9, 0x8a, 0x56, 0x02, 0x48, 0xa8, 0x54, 0x20, 0x0e, 0x58,
-	0x94, 0x92, 0x3e, 0xbc, 0x84, 0xe1, 0xed, 0x65, 0x34, 0x2d, 0x20, 0xe7, 0x42, 0x39, 0x6f, 0x77,
-	0xc2, 0x77, 0xd1, 0xdc, 0xd0, 0xa3, 0x31, 0x61, 0xa8, 0x12, 0xc0, 0x9b, 0xa8, 0xae, 0x0f, 0x94,
-	0x25, 0x61, 0x04, 0x42, 0x35, 0x26, 0x8d, 0x60, 0xde, 0x61, 0xfb, 0x20, 0x14, 0xde, 0x41, 0x98,
-	0xe7, 0x20, 0x88, 0xe2, 0x22, 0x2c, 0x9d, 0x6a, 0x46, 0xf8, 0x7f, 0xc1, 0x1c, 0x17, 0x44, 0xf3,
-	0xc3, 0x04, 0x5a, 0xb6, 0x2b, 0x78, 0xe5, 0x38, 0x59, 0x7c, 0xe2, 0x12, 0x9a, 0x62, 0x9c, 0x45,
-	0x76, 0xfa, 0x5a, 0x60, 0x0f, 0x7a, 0x37, 0x0c, 0xce, 0xc3, 0xc2, 0xa9, 0x98, 0xbc, 0xce, 0xe0,
-	0x7c, 0xe8, 0x80, 0xdb, 0x68, 0xf3, 0x27, 0x51, 0xa8, 0xba, 0x02, 0x64, 0x97, 0xa7, 0x71, 0xc8,
-	0x7a, 0x99, 0x05, 0xcd, 0xc7, 0xd7, 0x02, 0x6f, 0xf4, 0xe2, 0x49, 0x21, 0x3b, 0x2a, 0x54, 0xf8,
-	0x10, 0x6d, 0xfd, 0xcd, 0x2a, 0x06, 0xc6, 0x33, 0xca, 0x8c, 0x59, 0xcd, 0x98, 0x6d, 0xfc, 0xd1,
-	0xec, 0x59, 0xa9, 0xfb, 0x25, 0xb5, 0xa9, 0xdf, 0x52, 0xfb, 0x3e, 0x89, 0xe6, 0x6d, 0x19, 0x8e,
-	0x15, 0x51, 0xa0, 0xf3, 0xc8, 0x04, 0xd8, 0xf8, 0x5c, 0x54, 0x25, 0x80, 0xef, 0xa1, 0xc5, 0x33,
-	0x18, 0x84, 0x70, 0x91, 0x53, 0x41, 0x14, 0xe5, 0xcc, 0x44, 0x56, 0x0b, 0x16, 0xce, 0x60, 0x70,
-	0x30, 0x04, 0x75, 0xd8, 0x6f, 0x05, 0x7f, 0x0f, 0xcc, 0xcc, 0x3c, 0x1b, 0xb8, 0x13, 0x3e, 0x40,
-	0x0b, 0x29, 0x51, 0x20, 0x55, 0xd8, 0x05, 0x5d, 0x6a, 0x33, 0xc5, 0xfc, 0xc3, 0x55, 0x5f, 0xd7,
-	0x5c, 0xf7, 0xd6, 0x77, 0x6d, 0xed, 0xef, 0xfa, 0x2f, 0x8c, 0x62, 0xaf, 0x76, 0xf9, 0x65, 0xbd,
-	0x12, 0xd4, 0xed, 0x35, 0x8b, 0xe1, 0xc7, 0x68, 0x99, 0xa4, 0x29, 0x3f, 0x87, 0x38, 0x7c, 0xd7,
-	0xe3, 0x0a, 0x42, 0xa9, 0x88, 0xea, 0x49, 0x37, 0xdf, 0x5c, 0xb0, 0xe4, 0xd8, 0xd7, 0x9a, 0x3c,
-	0x76, 0x1c, 0x7e, 0x80, 0x0a, 0x3c, 0x24, 0x71, 0x9f, 0x4a, 0x2e, 0x06, 0x21, 0x8d, 0x65, 0x63,
-	0xda, 0xdc, 0xc1, 0x8e, 0x7b, 0xea, 0xa8, 0x76, 0x2c, 0xf5, 0x2e, 0xca, 0xd8, 0x67, 0xcc, 0xea,
-	0x4a, 0x00, 0xdf, 0x47, 0xff, 0x95, 0x21, 0xd9, 0xe2, 0xcc, 0x9a, 0x65, 0x2c, 0x0e, 0xe1, 0x23,
-	0xd3, 0xa0, 0x3d, 0xb4, 0x36, 0xbe, 0x18, 0x73, 0xe6, 0xda, 0x1d, 0x3e, 0xa6, 0x15, 0xcf, 0xd1,
-	0xfa, 0xbf, 0x1a, 0x81, 0x8c, 0xcb, 0x1a, 0x1f, 0x57, 0x87, 0x66, 0x1b, 0x2d, 0xee, 0x73, 0x26,
-	0x81, 0xc9, 0x9e, 0xb4, 0x81, 0xaf, 0xa0, 0x59, 0xbd, 0x3e, 0x08, 0x69, 0xec, 0xf2, 0x9e, 0x31,
-	0xe7, 0x76, 0xac, 0xe7, 0x57, 0x34, 0x03, 0xa9, 0x48, 0x96, 0xbb, 0xa0, 0x4b, 0x60, 0xef, 0xe4,
-	0xf2, 0x9b, 0x57, 0xb9, 0xbc, 0xf6, 0xaa, 0x57, 0xd7, 0x5e, 0xf5, 0xeb, 0xb5, 0x57, 0xfd, 0x78,
-	0xe3, 0x55, 0xae, 0x6e, 0xbc, 0xca, 0xe7, 0x1b, 0xaf, 0x72, 0xfa, 0x24, 0xa1, 0xaa, 0xdb, 0xeb,
-	0xf8, 0x11, 0xcf, 0x5a, 0x31, 0x51, 0x24, 0xea, 0x12, 0xca, 0x52, 0xd2, 0xd1, 0x8f, 0xdb, 0x4e,
-	0xc2, 0xed, 0xbb, 0xb7, 0x33, 0xfa, 0xf0, 0xa9, 0x41, 0x0e, 0xb2, 0x33, 0x6d, 0x1e, 0xaa, 0x47,
-	0x3f, 0x02, 0x00, 0x00, 0xff, 0xff, 0x3b, 0xb1, 0x44, 0x28, 0x1d, 0x05, 0x00, 0x00,
+	// 772 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x5d, 0x53, 0xe4, 0x44,
+	0x14, 0x9d, 0x81, 0x81, 0x85, 0x26, 0x99, 0x2d, 0x5b, 0x0a, 0xb3, 0xe8, 0x86, 0x81, 0xf5, 0x83,
+	0x17, 0x66, 0x44, 0x2d, 0xcb, 0x57, 0x41, 0xd4, 0x29, 0x6b, 0xd7, 0x32, 0xac, 0x3e, 0xec, 0x4b,
+	0x57, 0x4f, 0x72, 0xcd, 0x74, 0x6d, 0xd2, 0x1d, 0xbb, 0x3b, 0x81, 0xf1, 0x3f, 0x58, 0xe5, 0x0f,
+	0xf0, 0xcd, 0x3f, 0xc3, 0x23, 0x8f, 0x3e, 0x59, 0x0a, 0x7f, 0xc4, 0xea, 0x8f, 0x4c, 0xf0, 0x8b,
+	0x7d, 0x62, 0xfa, 0xdc, 0x73, 0x0f, 0xb9, 0xf7, 0x9c, 0x6e, 0xb4, 0xcf, 0x66, 0xe9, 0xa4, 0x60,
+	0xf9, 0x5c, 0xa7, 0x05, 0x03, 0xae, 0xd5, 0xa4, 0x48, 0xab, 0x49, 0x73, 0x6c, 0xfe, 0x8c, 0x2b,
+	0x29, 0xb4, 0xc0, 0x6f, 0xb0, 0x59, 0x3a, 0xbe, 0x4b, 0x19, 0x9b, 0x5a, 0x73, 0xbc, 0xbb, 0x9d,
+	0x8b, 0x5c, 0x58, 0xce, 0xc4, 0xfc, 0x72, 0xf4, 0xdd, 0x3d, 0xa3, 0x98, 0x0a, 0x09, 0x13, 0x47,
+	0x37, 0x62, 0xee, 0x97, 0x23, 0x1c, 0xbc, 0x40, 0xaf, 0x7f, 0x5b, 0x65, 0x54, 0xc3, 0xa9, 0x45,
+	0x9f, 0x82, 0x52, 0x34, 0x07, 0xfc, 0x04, 0x85, 0x95, 0x14, 0x97, 0x0b, 0x52, 0x3a, 0x20, 0xea,
+	0x8f, 0xfa, 0x87, 0x41, 0x12, 0x58, 0xb0, 0x25, 0xc5, 0x08, 0x29, 0x96, 0x73, 0xaa, 0x6b, 0x09,
+	0x2a, 0x5a, 0x19, 0xad, 0x1e, 0x06, 0xc9, 0x1d, 0xe4, 0xe0, 0xd7, 0x3e, 0x7a, 0x94, 0x40, 0xce,
+	0x94, 0x06, 0x79, 0xc6, 0xd3, 0x82, 0x36, 0xf0, 0x15, 0x2c, 0xbb, 0x77, 0xd0, 0xba, 0x84, 0x4a,
+	0x48, 0xed, 0xb5, 0xfd, 0x09, 0xbf, 0x85, 0x36, 0x97, 0x1a, 0xd1, 0x8a, 0x2d, 0x75, 0x00, 0xde,
+	0x47, 0x81, 0x39, 0x30, 0x9e, 0x93, 0x14, 0xa4, 0x8e, 0x56, 0x2d, 0x61, 0xcb, 0x63, 0xa7, 0x20,
+	0x35, 0x3e, 0x42, 0x58, 0x54, 0x20, 0xa9, 0x16, 0x92, 0x74, 0x4a, 0x03, 0x4b, 0x7c, 0xad, 0xad,
+	0x9c, 0xb7, 0x85, 0x83, 0x9f, 0x56, 0xd0, 0x8e, 0x5b, 0xc1, 0xd7, 0xbe, 0xa6, 0xda, 0x4f, 0xdc,
+	0x46, 0x6b, 0x5c, 0xf0, 0xd4, 0x4d, 0x3f, 0x48, 0xdc, 0xc1, 0xec, 0x86, 0xc3, 0x05, 0x69, 0x95,
+	0xda, 0xc9, 0x03, 0x0e, 0x17, 0x4b, 0x05, 0x3c, 0x45, 0xfb, 0x7f, 0x23, 0x11, 0x3d, 0x97, 0xa0,
+	0xe6, 0xa2, 0xc8, 0x08, 0xaf, 0x4b, 0x07, 0xda, 0x8f, 0x1f, 0x24, 0xf1, 0xdd, 0xc6, 0xe7, 0x2d,
+	0xed, 0x59, 0xcb, 0xc2, 0x4f, 0xd1, 0x93, 0xff, 0x93, 0xca, 0x80, 0x8b, 0x92, 0x71, 0x2b, 0x36,
+	0xb0, 0x62, 0xa3, 0xff, 0x14, 0xfb, 0xac, 0xe3, 0xfd, 0xc3, 0xb5, 0xb5, 0x7f, 0xb9, 0xf6, 0xcb,
+	0x1a, 0xda, 0x72, 0x61, 0x38, 0xd7, 0x54, 0x83, 0xf1, 0xa3, 0x94, 0xe0, 0xec, 0xf3, 0x56, 0x75,
+	0x00, 0x7e, 0x07, 0x0d, 0x5f, 0xc2, 0x82, 0xc0, 0x65, 0xc5, 0x24, 0xd5, 0x4c, 0x70, 0x6b, 0xd9,
+	0x20, 0x09, 0x5f, 0xc2, 0xe2, 0x6c, 0x09, 0x1a, 0xb3, 0xbf, 0x97, 0xe2, 0x47, 0xe0, 0x76, 0xe6,
+	0x8d, 0xc4, 0x9f, 0xf0, 0x19, 0x0a, 0x0b, 0xaa, 0x41, 0x69, 0x32, 0x07, 0x13, 0x6a, 0x3b, 0xc5,
+	0xd6, 0x07, 0xbb, 0x63, 0x13, 0x73, 0x93, 0xdb, 0xb1, 0x4f, 0x6b, 0x73, 0x3c, 0xfe, 0xd2, 0x32,
+	0x4e, 0x06, 0x57, 0xbf, 0xef, 0xf5, 0x92, 0xc0, 0xb5, 0x39, 0x0c, 0x7f, 0x84, 0x76, 0x68, 0x51,
+	0x88, 0x0b, 0xc8, 0xc8, 0x0f, 0xb5, 0xd0, 0x40, 0x94, 0xa6, 0xba, 0x56, 0x7e, 0xbe, 0xcd, 0x64,
+	0xdb, 0x57, 0xbf, 0x31, 0xc5, 0x73, 0x5f, 0xc3, 0xef, 0xa3, 0x16, 0x27, 0x34, 0x6b, 0x98, 0x12,
+	0x72, 0x41, 0x58, 0xa6, 0xa2, 0x75, 0xdb, 0x83, 0x7d, 0xed, 0x53, 0x5f, 0x9a, 0x66, 0xca, 0xec,
+	0xa2, 0xb3, 0xfd, 0x81, 0x5d, 0x5d, 0x07, 0xe0, 0xf7, 0xd0, 0xc3, 0xce, 0x24, 0x17, 0x9c, 0x0d,
+	0xbb, 0x8c, 0xe1, 0x12, 0x7e, 0x66, 0x13, 0x74, 0x82, 0x1e, 0xdf, 0x1f, 0x8c, 0x4d, 0xdb, 0xf6,
+	0xa6, 0xb8, 0x27, 0x15, 0x9f, 0xa3, 0xbd, 0x57, 0x25, 0x02, 0x59, 0x95, 0xc7, 0xe2, 0xde, 0x38,
+	0xbc, 0x8d, 0x86, 0x25, 0xbd, 0x24, 0xb5, 0xbd, 0x01, 0x24, 0xa7, 0x55, 0xb4, 0x65, 0xdb, 0x82,
+	0x92, 0x5e, 0xba, 0x6b, 0xf1, 0x05, 0xad, 0xf0, 0xbb, 0xe8, 0x61, 0x96, 0xd2, 0x8a, 0x48, 0x21,
+	0xb4, 0xbd, 0x78, 0x2a, 0x0a, 0xec, 0xf8, 0xa1, 0x81, 0x13, 0x21, 0xb4, 0xb9, 0x7a, 0x0a, 0x7f,
+	0x82, 0xa2, 0x76, 0xa5, 0xfe, 0xe5, 0x20, 0x0d, 0x48, 0xc5, 0x04, 0x57, 0x51, 0x38, 0x5a, 0x3d,
+	0x0c, 0x93, 0xd6, 0x28, 0x7f, 0xc7, 0xbe, 0xf3, 0x55, 0xf3, 0x1f, 0x4a, 0xc6, 0x59, 0x59, 0x97,
+	0x84, 0xa9, 0x86, 0xa8, 0x86, 0x47, 0xc3, 0x51, 0xff, 0x30, 0x4c, 0x42, 0x0f, 0x4f, 0x55, 0x73,
+	0xde, 0xf0, 0x83, 0x29, 0x1a, 0x9e, 0x0a, 0xae, 0x80, 0xab, 0x5a, 0xb9, 0x80, 0x3e, 0x42, 0x1b,
+	0xc6, 0x6e, 0x20, 0x2c, 0xf3, 0xf9, 0x7c, 0x60, 0xcf, 0xd3, 0xcc, 0xf8, 0xa5, 0x59, 0x09, 0x4a,
+	0xd3, 0xb2, 0xf2, 0xc1, 0xec, 0x80, 0x93, 0xe7, 0x57, 0x7f, 0xc6, 0xbd, 0xab, 0x9b, 0xb8, 0x7f,
+	0x7d, 0x13, 0xf7, 0xff, 0xb8, 0x89, 0xfb, 0x3f, 0xdf, 0xc6, 0xbd, 0xeb, 0xdb, 0xb8, 0xf7, 0xdb,
+	0x6d, 0xdc, 0x7b, 0xf1, 0x71, 0xce, 0xf4, 0xbc, 0x9e, 0x8d, 0x53, 0x51, 0x4e, 0x32, 0xaa, 0x69,
+	0x3a, 0xa7, 0x8c, 0x17, 0x74, 0x66, 0x1e, 0xe3, 0xa3, 0x5c, 0xb8, 0x77, 0xfa, 0xe8, 0xee, 0x43,
+	0xad, 0x17, 0x15, 0xa8, 0xd9, 0xba, 0x7d, 0x58, 0x3f, 0xfc, 0x6b, 0x00, 0xc8, 0xc7, 0x8e, 0xb6,
+	0xcd, 0x05, 0x00, 0x00,
 }
 
 func (m *UpdateClientMessage) Marshal() (dAtA []byte, err error) {
@@ -452,6 +471,43 @@ func (m *ClientState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.MinimumIsvSvn != 0 {
+		i = encodeVarintLcp(dAtA, i, uint64(m.MinimumIsvSvn))
+		i--
+		dAtA[i] = 0x70
+	}
+	if len(m.AllowedMessageVersions) > 0 {
+		dAtA2 := make([]byte, len(m.AllowedMessageVersions)*10)
+		var j1 int
+		for _, num := range m.AllowedMessageVersions {
+			for num >= 1<<7 {
+				dAtA2[j1] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j1++
+			}
+			dAtA2[j1] = uint8(num)
+			j1++
+		}
+		i -= j1
+		copy(dAtA[i:], dAtA2[:j1])
+		i = encodeVarintLcp(dAtA, i, uint64(j1))
+		i--
+		dAtA[i] = 0x6a
+	}
+	if len(m.DcapRootCerts) > 0 {
+		for iNdEx := len(m.DcapRootCerts) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.DcapRootCerts[iNdEx])
+			copy(dAtA[i:], m.DcapRootCerts[iNdEx])
+			i = encodeVarintLcp(dAtA, i, uint64(len(m.DcapRootCerts[iNdEx])))
+			i--
+			dAtA[i] = 0x62
+		}
+	}
+	if m.MaxUpdateGap != 0 {
+		i = encodeVarintLcp(dAtA, i, uint64(m.MaxUpdateGap))
+		i--
+		dAtA[i] = 0x58
+	}
 	if m.OperatorsThresholdDenominator != 0 {
 		i = encodeVarintLcp(dAtA, i, uint64(m.OperatorsThresholdDenominator))
 		i--
@@ -694,6 +750,25 @@ func (m *ClientState) Size() (n int) {
 	if m.OperatorsThresholdDenominator != 0 {
 		n += 1 + sovLcp(uint64(m.OperatorsThresholdDenominator))
 	}
+	if m.MaxUpdateGap != 0 {
+		n += 1 + sovLcp(uint64(m.MaxUpdateGap))
+	}
+	if len(m.DcapRootCerts) > 0 {
+		for _, b := range m.DcapRootCerts {
+			l = len(b)
+			n += 1 + l + sovLcp(uint64(l))
+		}
+	}
+	if len(m.AllowedMessageVersions) > 0 {
+		l = 0
+		for _, e := range m.AllowedMessageVersions {
+			l += sovLcp(uint64(e))
+		}
+		n += 1 + sovLcp(uint64(l)) + l
+	}
+	if m.MinimumIsvSvn != 0 {
+		n += 1 + sovLcp(uint64(m.MinimumIsvSvn))
+	}
 	return n
 }
 
@@ -1480,6 +1555,152 @@ func (m *ClientState) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxUpdateGap", wireType)
+			}
+			m.MaxUpdateGap = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLcp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxUpdateGap |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DcapRootCerts", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLcp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthLcp
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLcp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DcapRootCerts = append(m.DcapRootCerts, make([]byte, postIndex-iNdEx))
+			copy(m.DcapRootCerts[len(m.DcapRootCerts)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType == 0 {
+				var v uint32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowLcp
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.AllowedMessageVersions = append(m.AllowedMessageVersions, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowLcp
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthLcp
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthLcp
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.AllowedMessageVersions) == 0 {
+					m.AllowedMessageVersions = make([]uint32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowLcp
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.AllowedMessageVersions = append(m.AllowedMessageVersions, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedMessageVersions", wireType)
+			}
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinimumIsvSvn", wireType)
+			}
+			m.MinimumIsvSvn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLcp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinimumIsvSvn |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLcp(dAtA[iNdEx:])
@@ -0,0 +1,177 @@
+package types
+
+import (
+	errorsmod "cosmossdk.io/errors"
+	storetypes "cosmossdk.io/store/types"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+)
+
+// StoreProvider provides the client prefixed store of a client. The client keeper of ibc-go satisfies it.
+type StoreProvider interface {
+	ClientStore(ctx sdk.Context, clientID string) storetypes.KVStore
+}
+
+// LightClientModule is a standalone adapter that exposes the LCP client with the interface of a light client module, i.e. by the client ID.
+// It loads the client state from the client store and delegates to the methods of ClientState.
+// The ibc-go v8 used by this repository has no client router, so the module is not registered on any router
+// and the core IBC handlers keep calling the methods of ClientState. A host must call the module directly.
+type LightClientModule struct {
+	cdc           codec.BinaryCodec
+	storeProvider StoreProvider
+}
+
+// NewLightClientModule creates a new light client module of the LCP client
+func NewLightClientModule(cdc codec.BinaryCodec, storeProvider StoreProvider) LightClientModule {
+	return LightClientModule{cdc: cdc, storeProvider: storeProvider}
+}
+
+// ClientType returns the client type that the module is registered under
+func (LightClientModule) ClientType() string {
+	return ClientTypeLCP
+}
+
+// Initialize unmarshals the client state and the consensus state, and initializes the client
+func (l LightClientModule) Initialize(ctx sdk.Context, clientID string, clientStateBz, consensusStateBz []byte) error {
+	var clientState ClientState
+	if err := l.cdc.Unmarshal(clientStateBz, &clientState); err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "failed to unmarshal client state: %v", err)
+	}
+	var consensusState ConsensusState
+	if err := l.cdc.Unmarshal(consensusStateBz, &consensusState); err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidConsensus, "failed to unmarshal consensus state: %v", err)
+	}
+	return clientState.Initialize(ctx, l.cdc, l.storeProvider.ClientStore(ctx, clientID), &consensusState)
+}
+
+// VerifyClientMessage verifies `clientMsg` against the client state
+func (l LightClientModule) VerifyClientMessage(ctx sdk.Context, clientID string, clientMsg exported.ClientMessage) error {
+	clientStore := l.storeProvider.ClientStore(ctx, clientID)
+	clientState, err := newClientStore(clientStore, l.cdc).GetClientState()
+	if err != nil {
+		return errorsmod.Wrap(err, clientID)
+	}
+	return clientState.VerifyClientMessage(ctx, l.cdc, clientStore, clientMsg)
+}
+
+// CheckForMisbehaviour returns true if `clientMsg` is a misbehaviour. It panics if the client does not exist.
+func (l LightClientModule) CheckForMisbehaviour(ctx sdk.Context, clientID string, clientMsg exported.ClientMessage) bool {
+	clientStore := l.storeProvider.ClientStore(ctx, clientID)
+	return l.mustGetClientState(clientStore, clientID).CheckForMisbehaviour(ctx, l.cdc, clientStore, clientMsg)
+}
+
+// UpdateStateOnMisbehaviour freezes the client. It panics if the client does not exist.
+func (l LightClientModule) UpdateStateOnMisbehaviour(ctx sdk.Context, clientID string, clientMsg exported.ClientMessage) {
+	clientStore := l.storeProvider.ClientStore(ctx, clientID)
+	l.mustGetClientState(clientStore, clientID).UpdateStateOnMisbehaviour(ctx, l.cdc, clientStore, clientMsg)
+}
+
+// UpdateState updates the client with the verified `clientMsg`. It panics if the client does not exist.
+func (l LightClientModule) UpdateState(ctx sdk.Context, clientID string, clientMsg exported.ClientMessage) []exported.Height {
+	clientStore := l.storeProvider.ClientStore(ctx, clientID)
+	return l.mustGetClientState(clientStore, clientID).UpdateState(ctx, l.cdc, clientStore, clientMsg)
+}
+
+// VerifyMembership verifies the existence of `value` at `path` at `height`
+func (l LightClientModule) VerifyMembership(
+	ctx sdk.Context,
+	clientID string,
+	height exported.Height,
+	delayTimePeriod uint64,
+	delayBlockPeriod uint64,
+	proof []byte,
+	path exported.Path,
+	value []byte,
+) error {
+	clientStore := l.storeProvider.ClientStore(ctx, clientID)
+	clientState, err := newClientStore(clientStore, l.cdc).GetClientState()
+	if err != nil {
+		return errorsmod.Wrap(err, clientID)
+	}
+	return clientState.VerifyMembership(ctx, clientStore, l.cdc, height, delayTimePeriod, delayBlockPeriod, proof, path, value)
+}
+
+// VerifyNonMembership verifies the absence of `path` at `height`
+func (l LightClientModule) VerifyNonMembership(
+	ctx sdk.Context,
+	clientID string,
+	height exported.Height,
+	delayTimePeriod uint64,
+	delayBlockPeriod uint64,
+	proof []byte,
+	path exported.Path,
+) error {
+	clientStore := l.storeProvider.ClientStore(ctx, clientID)
+	clientState, err := newClientStore(clientStore, l.cdc).GetClientState()
+	if err != nil {
+		return errorsmod.Wrap(err, clientID)
+	}
+	return clientState.VerifyNonMembership(ctx, clientStore, l.cdc, height, delayTimePeriod, delayBlockPeriod, proof, path)
+}
+
+// Status returns the status of the client, or Unknown if the client does not exist
+func (l LightClientModule) Status(ctx sdk.Context, clientID string) exported.Status {
+	clientStore := l.storeProvider.ClientStore(ctx, clientID)
+	clientState, err := newClientStore(clientStore, l.cdc).GetClientState()
+	if err != nil {
+		return exported.Unknown
+	}
+	return clientState.Status(ctx, clientStore, l.cdc)
+}
+
+// LatestHeight returns the latest height of the client, or the zero height if the client does not exist
+func (l LightClientModule) LatestHeight(ctx sdk.Context, clientID string) exported.Height {
+	clientState, err := newClientStore(l.storeProvider.ClientStore(ctx, clientID), l.cdc).GetClientState()
+	if err != nil {
+		return clienttypes.ZeroHeight()
+	}
+	return clientState.GetLatestHeight()
+}
+
+// TimestampAtHeight returns the timestamp of the consensus state at `height`
+func (l LightClientModule) TimestampAtHeight(ctx sdk.Context, clientID string, height exported.Height) (uint64, error) {
+	clientStore := l.storeProvider.ClientStore(ctx, clientID)
+	clientState, err := newClientStore(clientStore, l.cdc).GetClientState()
+	if err != nil {
+		return 0, errorsmod.Wrap(err, clientID)
+	}
+	return clientState.GetTimestampAtHeight(ctx, clientStore, l.cdc, height)
+}
+
+// RecoverClient replaces the frozen client with the substitute client. See ClientState.CheckSubstituteAndUpdateState.
+func (l LightClientModule) RecoverClient(ctx sdk.Context, clientID, substituteClientID string) error {
+	clientStore := l.storeProvider.ClientStore(ctx, clientID)
+	clientState, err := newClientStore(clientStore, l.cdc).GetClientState()
+	if err != nil {
+		return errorsmod.Wrap(err, clientID)
+	}
+	substituteClientStore := l.storeProvider.ClientStore(ctx, substituteClientID)
+	substituteClientState, err := newClientStore(substituteClientStore, l.cdc).GetClientState()
+	if err != nil {
+		return errorsmod.Wrap(err, substituteClientID)
+	}
+	return clientState.CheckSubstituteAndUpdateState(ctx, l.cdc, clientStore, substituteClientStore, substituteClientState)
+}
+
+// VerifyUpgradeAndUpdateState returns an error because the LCP client does not support the upgrades
+func (l LightClientModule) VerifyUpgradeAndUpdateState(
+	ctx sdk.Context,
+	clientID string,
+	newClient []byte,
+	newConsState []byte,
+	upgradeClientProof,
+	upgradeConsensusStateProof []byte,
+) error {
+	return errorsmod.Wrapf(sdkerrors.ErrNotSupported, "the LCP client does not support the client upgrades: client_id=%v", clientID)
+}
+
+func (l LightClientModule) mustGetClientState(clientStore storetypes.KVStore, clientID string) *ClientState {
+	clientState, err := newClientStore(clientStore, l.cdc).GetClientState()
+	if err != nil {
+		panic(errorsmod.Wrap(err, clientID))
+	}
+	return clientState
+}
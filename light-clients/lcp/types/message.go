@@ -95,7 +95,12 @@ var (
 type StateID [32]byte
 
 func (id StateID) String() string {
-	return fmt.Sprintf("0x%x", id[:])
+	return HexBytes(id[:]).String()
+}
+
+// IsZero returns true if all bytes of the state ID are zero. The ELC never commits to such a state.
+func (id StateID) IsZero() bool {
+	return id == StateID{}
 }
 
 func (id StateID) EqualBytes(bz []byte) bool {
@@ -269,6 +274,15 @@ type ELCVerifyMembershipMessage struct {
 	StateID StateID
 }
 
+// ELCVerifyNonMembershipMessage is the message of the ELC's VerifyNonMembership.
+// It is encoded as a VerifyMembershipMessage whose value is zero, which means the absence of the value.
+type ELCVerifyNonMembershipMessage struct {
+	Prefix  []byte
+	Path    []byte
+	Height  clienttypes.Height
+	StateID StateID
+}
+
 type CommitmentProofs struct {
 	Message    []byte
 	Signatures [][]byte
@@ -285,9 +299,6 @@ type HeaderedProxyMessage struct {
 }
 
 func (c HeaderedProxyMessage) GetUpdateStateProxyMessage() (*UpdateStateProxyMessage, error) {
-	if c.Version != LCPMessageVersion {
-		return nil, fmt.Errorf("unexpected commitment version: expected=%v actual=%v", LCPMessageVersion, c.Version)
-	}
 	if c.Type != LCPMessageTypeUpdateState {
 		return nil, fmt.Errorf("unexpected commitment type: expected=%v actual=%v", LCPMessageTypeUpdateState, c.Type)
 	}
@@ -295,9 +306,6 @@ func (c HeaderedProxyMessage) GetUpdateStateProxyMessage() (*UpdateStateProxyMes
 }
 
 func (c HeaderedProxyMessage) GetMisbehaviourProxyMessage() (*MisbehaviourProxyMessage, error) {
-	if c.Version != LCPMessageVersion {
-		return nil, fmt.Errorf("unexpected commitment version: expected=%v actual=%v", LCPMessageVersion, c.Version)
-	}
 	if c.Type != LCPMessageTypeMisbehaviour {
 		return nil, fmt.Errorf("unexpected commitment type: expected=%v actual=%v", LCPMessageTypeMisbehaviour, c.Type)
 	}
@@ -305,15 +313,28 @@ func (c HeaderedProxyMessage) GetMisbehaviourProxyMessage() (*MisbehaviourProxyM
 }
 
 func (c HeaderedProxyMessage) GetVerifyMembershipProxyMessage() (*ELCVerifyMembershipMessage, error) {
-	if c.Version != LCPMessageVersion {
-		return nil, fmt.Errorf("unexpected commitment version: expected=%v actual=%v", LCPMessageVersion, c.Version)
-	}
 	if c.Type != LCPMessageTypeState {
 		return nil, fmt.Errorf("unexpected commitment type: expected=%v actual=%v", LCPMessageTypeState, c.Type)
 	}
 	return EthABIDecodeVerifyMembershipProxyMessage(c.Message)
 }
 
+func (c HeaderedProxyMessage) GetVerifyNonMembershipProxyMessage() (*ELCVerifyNonMembershipMessage, error) {
+	msg, err := c.GetVerifyMembershipProxyMessage()
+	if err != nil {
+		return nil, err
+	}
+	if msg.Value != [32]byte{} {
+		return nil, fmt.Errorf("unexpected value of non-membership message: expected=zero actual=%v", HexBytes(msg.Value[:]))
+	}
+	return &ELCVerifyNonMembershipMessage{
+		Prefix:  msg.Prefix,
+		Path:    msg.Path,
+		Height:  msg.Height,
+		StateID: msg.StateID,
+	}, nil
+}
+
 func EthABIEncodeCommitmentProofs(p *CommitmentProofs) ([]byte, error) {
 	packer := abi.Arguments{
 		{Type: commitmentProofsABI},
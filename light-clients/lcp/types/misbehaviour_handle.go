@@ -29,13 +29,17 @@ func (cs ClientState) CheckForMisbehaviour(ctx sdk.Context, cdc codec.BinaryCode
 }
 
 func (cs ClientState) verifyMisbehaviour(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, msg *UpdateClientMessage, pmsg *MisbehaviourProxyMessage) error {
+	if len(pmsg.PrevStates) == 0 {
+		return errorsmod.Wrap(ErrInvalidMisbehaviour, "no previous state")
+	}
+	store := newClientStore(clientStore, cdc)
 	for _, state := range pmsg.PrevStates {
-		cons, err := GetConsensusState(clientStore, cdc, state.Height)
+		cons, err := store.GetConsensusState(state.Height)
 		if err != nil {
 			return err
 		}
 		if !bytes.Equal(cons.StateId, state.StateID[:]) {
-			return errorsmod.Wrapf(ErrInvalidMisbehaviour, "unexpected StateID: expected=%v actual=%v", cons.StateId, state.StateID)
+			return errorsmod.Wrapf(ErrInvalidMisbehaviour, "unexpected StateID: expected=%v actual=%v", HexBytes(cons.StateId), state.StateID)
 		}
 	}
 	if err := pmsg.Context.Validate(ctx.BlockTime()); err != nil {
@@ -0,0 +1,123 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v8/modules/core/23-commitment/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/light-clients/lcp/testutil"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitMisbehaviour(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	prev, post := clienttypes.NewHeight(0, 1), clienttypes.NewHeight(0, 2)
+	path := "commitments/ports/transfer/channels/channel-0/sequences/1"
+	value := []byte("commitment")
+
+	h := testutil.NewHarness(t)
+	h.Initialize(&lcptypes.ClientState{LatestHeight: prev, KeyExpiration: 3600}, testutil.NewConsensusState(prev, testutil.DefaultBlockTime))
+	misbehaviour := testutil.NewMisbehaviourMessage(t, []clienttypes.Height{prev}, key)
+	require.True(t, h.ClientState().CheckForMisbehaviour(h.Ctx, h.Cdc, h.Store, misbehaviour))
+	require.False(t, h.ClientState().CheckForMisbehaviour(h.Ctx, h.Cdc, h.Store, testutil.NewUpdateClientMessage(t, prev, post, h.Ctx.BlockTime(), key)))
+
+	// the signer is not an active enclave key
+	require.ErrorContains(t, h.Update(misbehaviour), "not found")
+	h.SetEnclaveKey(ek, h.Ctx.BlockTime().Add(time.Hour), common.Address{})
+	// the previous states must match the consensus states
+	require.ErrorContains(t, h.Update(testutil.NewMisbehaviourMessage(t, []clienttypes.Height{post}, key)), "consensus state not found")
+	h.SetConsensusState(post, testutil.NewConsensusState(clienttypes.NewHeight(0, 3), h.Ctx.BlockTime()))
+	require.ErrorIs(t, h.Update(testutil.NewMisbehaviourMessage(t, []clienttypes.Height{post}, key)), lcptypes.ErrInvalidMisbehaviour)
+	require.ErrorIs(t, h.Update(testutil.NewMisbehaviourMessage(t, nil, key)), lcptypes.ErrInvalidMisbehaviour)
+	require.Equal(t, exported.Active, h.ClientState().Status(h.Ctx, h.Store, h.Cdc))
+
+	require.NoError(t, h.Update(misbehaviour))
+	cs := h.ClientState()
+	require.True(t, cs.Frozen)
+	require.Equal(t, exported.Frozen, cs.Status(h.Ctx, h.Store, h.Cdc))
+
+	// the frozen client rejects the updates and the proofs
+	update := testutil.NewUpdateClientMessage(t, prev, post, h.Ctx.BlockTime(), key)
+	require.ErrorIs(t, h.VerifyClientMessage(update), clienttypes.ErrClientFrozen)
+	require.ErrorIs(t, h.VerifyClientMessage(misbehaviour), clienttypes.ErrClientFrozen)
+	require.Panics(t, func() { h.UpdateState(update) })
+	merklePath := commitmenttypes.NewMerklePath(exported.StoreKey, path)
+	err := cs.VerifyMembership(h.Ctx, h.Store, h.Cdc, prev, 0, 0, testutil.NewCommitmentProof(t, prev, path, value, key), merklePath, value)
+	require.ErrorIs(t, err, clienttypes.ErrClientFrozen)
+	err = cs.VerifyNonMembership(h.Ctx, h.Store, h.Cdc, prev, 0, 0, testutil.NewNonMembershipCommitmentProof(t, prev, path, key), merklePath)
+	require.ErrorIs(t, err, clienttypes.ErrClientFrozen)
+}
+
+func TestCheckSubstituteAndUpdateState(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	substituteKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	substituteEK := crypto.PubkeyToAddress(substituteKey.PublicKey)
+	prev, post := clienttypes.NewHeight(0, 1), clienttypes.NewHeight(0, 10)
+	mrenclave := []byte{0x01}
+
+	newSubject := func(t *testing.T) *testutil.Harness {
+		h := testutil.NewHarness(t)
+		h.Initialize(&lcptypes.ClientState{Mrenclave: mrenclave, LatestHeight: prev, KeyExpiration: 3600}, testutil.NewConsensusState(prev, testutil.DefaultBlockTime))
+		h.SetEnclaveKey(ek, h.Ctx.BlockTime().Add(time.Hour), common.Address{})
+		require.NoError(t, h.Update(testutil.NewMisbehaviourMessage(t, []clienttypes.Height{prev}, key)))
+		return h
+	}
+	newSubstitute := func(t *testing.T, cs *lcptypes.ClientState) *testutil.Harness {
+		h := testutil.NewHarness(t)
+		h.Initialize(cs, testutil.NewConsensusState(cs.LatestHeight, testutil.DefaultBlockTime))
+		h.SetEnclaveKey(substituteEK, h.Ctx.BlockTime().Add(2*time.Hour), common.Address{})
+		return h
+	}
+	substitute := func(subject, substitute *testutil.Harness) error {
+		return subject.ClientState().CheckSubstituteAndUpdateState(subject.Ctx, subject.Cdc, subject.Store, substitute.Store, substitute.ClientState())
+	}
+
+	t.Run("replace", func(t *testing.T) {
+		require := require.New(t)
+		subject := newSubject(t)
+		src := newSubstitute(t, &lcptypes.ClientState{Mrenclave: mrenclave, LatestHeight: post, KeyExpiration: 3600})
+		require.NoError(substitute(subject, src))
+
+		cs := subject.ClientState()
+		require.False(cs.Frozen)
+		require.Equal(post, cs.LatestHeight)
+		require.Equal(exported.Active, cs.Status(subject.Ctx, subject.Store, subject.Cdc))
+		require.Equal(testutil.NewConsensusState(post, testutil.DefaultBlockTime), subject.ConsensusState(post))
+		// the keys of the subject may have signed the misbehaviour
+		require.Nil(subject.EnclaveKeyInfo(ek))
+		require.NotNil(subject.EnclaveKeyInfo(substituteEK))
+		next := clienttypes.NewHeight(0, 11)
+		require.Error(subject.VerifyClientMessage(testutil.NewUpdateClientMessage(t, post, next, subject.Ctx.BlockTime(), key)))
+		require.NoError(subject.Update(testutil.NewUpdateClientMessage(t, post, next, subject.Ctx.BlockTime(), substituteKey)))
+		require.Equal(next, subject.ClientState().LatestHeight)
+	})
+
+	t.Run("invalid substitute", func(t *testing.T) {
+		require := require.New(t)
+		// the subject is not frozen
+		subject := testutil.NewHarness(t)
+		subject.Initialize(&lcptypes.ClientState{Mrenclave: mrenclave, LatestHeight: prev, KeyExpiration: 3600}, testutil.NewConsensusState(prev, testutil.DefaultBlockTime))
+		src := newSubstitute(t, &lcptypes.ClientState{Mrenclave: mrenclave, LatestHeight: post, KeyExpiration: 3600})
+		require.ErrorIs(substitute(subject, src), clienttypes.ErrInvalidSubstitute)
+
+		for name, cs := range map[string]*lcptypes.ClientState{
+			"frozen":         {Mrenclave: mrenclave, LatestHeight: post, KeyExpiration: 3600, Frozen: true},
+			"mrenclave":      {Mrenclave: []byte{0x02}, LatestHeight: post, KeyExpiration: 3600},
+			"key_expiration": {Mrenclave: mrenclave, LatestHeight: post, KeyExpiration: 7200},
+			"operators":      {Mrenclave: mrenclave, LatestHeight: post, KeyExpiration: 3600, Operators: [][]byte{ek.Bytes()}, OperatorsThresholdNumerator: 1, OperatorsThresholdDenominator: 1},
+		} {
+			subject := newSubject(t)
+			require.ErrorIs(substitute(subject, newSubstitute(t, cs)), clienttypes.ErrInvalidSubstitute, name)
+			require.True(subject.ClientState().Frozen, name)
+			require.NotNil(subject.EnclaveKeyInfo(ek), name)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+
+	errorsmod "cosmossdk.io/errors"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Fraction is a fraction of the operators, e.g. the threshold of the signatures
+type Fraction struct {
+	Numerator   uint64 `json:"numerator"`
+	Denominator uint64 `json:"denominator"`
+}
+
+// IsZero returns true if both the numerator and the denominator are zero
+func (f Fraction) IsZero() bool {
+	return f.Numerator == 0 && f.Denominator == 0
+}
+
+func (f Fraction) String() string {
+	return fmt.Sprintf("%v/%v", f.Numerator, f.Denominator)
+}
+
+// HasOperators returns true if the client is operated by the operators.
+// If false, the client is permissionless and any enclave key can be registered.
+// A nil client state has no operators.
+func (cs *ClientState) HasOperators() bool {
+	return cs != nil && len(cs.Operators) > 0
+}
+
+// GetOperators returns the addresses of the operators in the order stored in the client state.
+// A nil or permissionless client state returns nil.
+func (cs *ClientState) GetOperators() []common.Address {
+	if cs == nil {
+		return nil
+	}
+	var operators []common.Address
+	for _, op := range cs.Operators {
+		operators = append(operators, common.BytesToAddress(op))
+	}
+	return operators
+}
+
+// OperatorsThreshold returns the threshold of the operators' signatures.
+// A nil client state returns the zero fraction, which is also the threshold of a permissionless client.
+func (cs *ClientState) OperatorsThreshold() Fraction {
+	if cs == nil {
+		return Fraction{}
+	}
+	return Fraction{Numerator: cs.OperatorsThresholdNumerator, Denominator: cs.OperatorsThresholdDenominator}
+}
+
+// validateOperators validates the operators and their threshold with the same rules as the Solidity client:
+//   - no operators means that the client is permissionless, and the threshold must be 0/0
+//   - otherwise, the threshold must satisfy 0 < numerator <= denominator,
+//     and the operators must be non-zero 20-byte addresses in ascending order without duplicates
+func (cs ClientState) validateOperators() error {
+	if len(cs.Operators) == 0 {
+		if !cs.OperatorsThreshold().IsZero() {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "the operators threshold must be 0/0 if the operators are empty(=permissionless), but got %v", cs.OperatorsThreshold())
+		}
+		return nil
+	}
+	if cs.OperatorsThresholdNumerator == 0 || cs.OperatorsThresholdDenominator == 0 {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "`OperatorsThresholdNumerator` and `OperatorsThresholdDenominator` must be non-zero if the operators are set")
+	}
+	if cs.OperatorsThresholdNumerator > cs.OperatorsThresholdDenominator {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "`OperatorsThresholdNumerator` must be less than or equal to `OperatorsThresholdDenominator`")
+	}
+	var zeroAddr common.Address
+	for i, op := range cs.Operators {
+		if len(op) != common.AddressLength {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "operator address length must be %v, but got %v", common.AddressLength, len(op))
+		}
+		if common.BytesToAddress(op) == zeroAddr {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "operator address cannot be empty")
+		}
+		// check if the operator is ordered correctly
+		if i > 0 && bytes.Compare(cs.Operators[i-1], op) >= 0 {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidClient, "operator addresses must be ordered in ascending order without duplicates: %v >= %v", HexBytes(cs.Operators[i-1]), HexBytes(op))
+		}
+	}
+	return nil
+}
+
+// NextOperatorsNonce returns the nonce that the next operators update must have.
+// The nonce of a newly created client is zero, so a nil client state returns 1.
+func (cs *ClientState) NextOperatorsNonce() uint64 {
+	if cs == nil {
+		return 1
+	}
+	return cs.OperatorsNonce + 1
+}
+
+// ContainsOperator returns true if `operator` is included in `operators`
+func ContainsOperator(operators []common.Address, operator common.Address) bool {
+	for _, op := range operators {
+		if op == operator {
+			return true
+		}
+	}
+	return false
+}
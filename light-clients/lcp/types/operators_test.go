@@ -0,0 +1,109 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientStateOperatorsAccessors(t *testing.T) {
+	op1 := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	op2 := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	var cases = []struct {
+		name         string
+		clientState  *ClientState
+		hasOperators bool
+		operators    []common.Address
+		threshold    Fraction
+		nextNonce    uint64
+	}{
+		{
+			name:        "nil",
+			clientState: nil,
+			nextNonce:   1,
+		},
+		{
+			name:        "zero value",
+			clientState: &ClientState{},
+			nextNonce:   1,
+		},
+		{
+			name: "populated",
+			clientState: &ClientState{
+				Operators:                     [][]byte{op1.Bytes(), op2.Bytes()},
+				OperatorsNonce:                3,
+				OperatorsThresholdNumerator:   1,
+				OperatorsThresholdDenominator: 2,
+			},
+			hasOperators: true,
+			operators:    []common.Address{op1, op2},
+			threshold:    Fraction{Numerator: 1, Denominator: 2},
+			nextNonce:    4,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			require.Equal(c.hasOperators, c.clientState.HasOperators())
+			require.Equal(c.operators, c.clientState.GetOperators())
+			require.Equal(c.threshold, c.clientState.OperatorsThreshold())
+			require.Equal(c.threshold.IsZero(), !c.hasOperators)
+			require.Equal(c.nextNonce, c.clientState.NextOperatorsNonce())
+			for _, op := range c.operators {
+				require.True(ContainsOperator(c.clientState.GetOperators(), op))
+			}
+			require.False(ContainsOperator(c.clientState.GetOperators(), common.Address{}))
+		})
+	}
+}
+
+func TestFractionString(t *testing.T) {
+	require.Equal(t, "0/0", Fraction{}.String())
+	require.Equal(t, "2/3", Fraction{Numerator: 2, Denominator: 3}.String())
+}
+
+func TestClientStateValidateOperators(t *testing.T) {
+	op1 := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	op2 := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	var cases = []struct {
+		name        string
+		operators   [][]byte
+		numerator   uint64
+		denominator uint64
+		valid       bool
+	}{
+		{name: "permissionless with 0/0", valid: true},
+		{name: "permissionless with 0/1", denominator: 1},
+		{name: "permissionless with 1/0", numerator: 1},
+		{name: "permissionless with 1/1", numerator: 1, denominator: 1},
+		{name: "operators with 0/0", operators: [][]byte{op1.Bytes()}},
+		{name: "operators with 0/1", operators: [][]byte{op1.Bytes()}, denominator: 1},
+		{name: "operators with 1/0", operators: [][]byte{op1.Bytes()}, numerator: 1},
+		{name: "operators with 1/1", operators: [][]byte{op1.Bytes()}, numerator: 1, denominator: 1, valid: true},
+		{name: "operators with 1/2", operators: [][]byte{op1.Bytes(), op2.Bytes()}, numerator: 1, denominator: 2, valid: true},
+		{name: "operators with 2/1", operators: [][]byte{op1.Bytes(), op2.Bytes()}, numerator: 2, denominator: 1},
+		{name: "zero address operator", operators: [][]byte{common.Address{}.Bytes()}, numerator: 1, denominator: 1},
+		{name: "short operator address", operators: [][]byte{op1.Bytes()[1:]}, numerator: 1, denominator: 1},
+		{name: "unordered operators", operators: [][]byte{op2.Bytes(), op1.Bytes()}, numerator: 1, denominator: 1},
+		{name: "duplicated operators", operators: [][]byte{op1.Bytes(), op1.Bytes()}, numerator: 1, denominator: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cs := ClientState{
+				Mrenclave:                     make([]byte, MrenclaveSize),
+				KeyExpiration:                 3600,
+				Operators:                     c.operators,
+				OperatorsThresholdNumerator:   c.numerator,
+				OperatorsThresholdDenominator: c.denominator,
+			}
+			if err := cs.Validate(); c.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,1638 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: ibc/lightclients/lcp/v1/query.proto
+
+package types
+
+import (
+	context "context"
+	fmt "fmt"
+	_ "github.com/cosmos/gogoproto/gogoproto"
+	grpc1 "github.com/cosmos/gogoproto/grpc"
+	proto "github.com/cosmos/gogoproto/proto"
+	types "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type QueryConsensusStateIDRequest struct {
+	ClientId string       `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Height   types.Height `protobuf:"bytes,2,opt,name=height,proto3" json:"height"`
+}
+
+func (m *QueryConsensusStateIDRequest) Reset()         { *m = QueryConsensusStateIDRequest{} }
+func (m *QueryConsensusStateIDRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryConsensusStateIDRequest) ProtoMessage()    {}
+func (*QueryConsensusStateIDRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c5fc6ad6bf0baf1b, []int{0}
+}
+func (m *QueryConsensusStateIDRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryConsensusStateIDRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryConsensusStateIDRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryConsensusStateIDRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryConsensusStateIDRequest.Merge(m, src)
+}
+func (m *QueryConsensusStateIDRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryConsensusStateIDRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryConsensusStateIDRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryConsensusStateIDRequest proto.InternalMessageInfo
+
+type QueryConsensusStateIDResponse struct {
+	StateId []byte `protobuf:"bytes,1,opt,name=state_id,json=stateId,proto3" json:"state_id,omitempty"`
+	// the timestamp of the consensus state in unix nanoseconds
+	Timestamp uint64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+}
+
+func (m *QueryConsensusStateIDResponse) Reset()         { *m = QueryConsensusStateIDResponse{} }
+func (m *QueryConsensusStateIDResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryConsensusStateIDResponse) ProtoMessage()    {}
+func (*QueryConsensusStateIDResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c5fc6ad6bf0baf1b, []int{1}
+}
+func (m *QueryConsensusStateIDResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryConsensusStateIDResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryConsensusStateIDResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryConsensusStateIDResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryConsensusStateIDResponse.Merge(m, src)
+}
+func (m *QueryConsensusStateIDResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryConsensusStateIDResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryConsensusStateIDResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryConsensusStateIDResponse proto.InternalMessageInfo
+
+type QueryVerifyCommitmentProofRequest struct {
+	ClientId string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// the commitment proof in the form of the proofs of VerifyMembership and VerifyNonMembership of the client
+	Proof []byte `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
+}
+
+func (m *QueryVerifyCommitmentProofRequest) Reset()         { *m = QueryVerifyCommitmentProofRequest{} }
+func (m *QueryVerifyCommitmentProofRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryVerifyCommitmentProofRequest) ProtoMessage()    {}
+func (*QueryVerifyCommitmentProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c5fc6ad6bf0baf1b, []int{2}
+}
+func (m *QueryVerifyCommitmentProofRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryVerifyCommitmentProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryVerifyCommitmentProofRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryVerifyCommitmentProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryVerifyCommitmentProofRequest.Merge(m, src)
+}
+func (m *QueryVerifyCommitmentProofRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryVerifyCommitmentProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryVerifyCommitmentProofRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryVerifyCommitmentProofRequest proto.InternalMessageInfo
+
+type QueryVerifyCommitmentProofResponse struct {
+	Prefix []byte `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Path   []byte `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// the keccak256 hash of the value, or zero if the commitment is of the absence of the value
+	Value   []byte       `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Height  types.Height `protobuf:"bytes,4,opt,name=height,proto3" json:"height"`
+	StateId []byte       `protobuf:"bytes,5,opt,name=state_id,json=stateId,proto3" json:"state_id,omitempty"`
+}
+
+func (m *QueryVerifyCommitmentProofResponse) Reset()         { *m = QueryVerifyCommitmentProofResponse{} }
+func (m *QueryVerifyCommitmentProofResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryVerifyCommitmentProofResponse) ProtoMessage()    {}
+func (*QueryVerifyCommitmentProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c5fc6ad6bf0baf1b, []int{3}
+}
+func (m *QueryVerifyCommitmentProofResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryVerifyCommitmentProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryVerifyCommitmentProofResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryVerifyCommitmentProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryVerifyCommitmentProofResponse.Merge(m, src)
+}
+func (m *QueryVerifyCommitmentProofResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryVerifyCommitmentProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryVerifyCommitmentProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryVerifyCommitmentProofResponse proto.InternalMessageInfo
+
+type QueryOperatorsNonceRequest struct {
+	ClientId string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+}
+
+func (m *QueryOperatorsNonceRequest) Reset()         { *m = QueryOperatorsNonceRequest{} }
+func (m *QueryOperatorsNonceRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryOperatorsNonceRequest) ProtoMessage()    {}
+func (*QueryOperatorsNonceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c5fc6ad6bf0baf1b, []int{4}
+}
+func (m *QueryOperatorsNonceRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryOperatorsNonceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryOperatorsNonceRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryOperatorsNonceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryOperatorsNonceRequest.Merge(m, src)
+}
+func (m *QueryOperatorsNonceRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryOperatorsNonceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryOperatorsNonceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryOperatorsNonceRequest proto.InternalMessageInfo
+
+type QueryOperatorsNonceResponse struct {
+	// the nonce of the latest UpdateOperators or UpdateClientParams message applied to the client, or zero if none is applied
+	Nonce     uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	NextNonce uint64 `protobuf:"varint,2,opt,name=next_nonce,json=nextNonce,proto3" json:"next_nonce,omitempty"`
+}
+
+func (m *QueryOperatorsNonceResponse) Reset()         { *m = QueryOperatorsNonceResponse{} }
+func (m *QueryOperatorsNonceResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryOperatorsNonceResponse) ProtoMessage()    {}
+func (*QueryOperatorsNonceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c5fc6ad6bf0baf1b, []int{5}
+}
+func (m *QueryOperatorsNonceResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryOperatorsNonceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryOperatorsNonceResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryOperatorsNonceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryOperatorsNonceResponse.Merge(m, src)
+}
+func (m *QueryOperatorsNonceResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryOperatorsNonceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryOperatorsNonceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryOperatorsNonceResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*QueryConsensusStateIDRequest)(nil), "ibc.lightclients.lcp.v1.QueryConsensusStateIDRequest")
+	proto.RegisterType((*QueryConsensusStateIDResponse)(nil), "ibc.lightclients.lcp.v1.QueryConsensusStateIDResponse")
+	proto.RegisterType((*QueryVerifyCommitmentProofRequest)(nil), "ibc.lightclients.lcp.v1.QueryVerifyCommitmentProofRequest")
+	proto.RegisterType((*QueryVerifyCommitmentProofResponse)(nil), "ibc.lightclients.lcp.v1.QueryVerifyCommitmentProofResponse")
+	proto.RegisterType((*QueryOperatorsNonceRequest)(nil), "ibc.lightclients.lcp.v1.QueryOperatorsNonceRequest")
+	proto.RegisterType((*QueryOperatorsNonceResponse)(nil), "ibc.lightclients.lcp.v1.QueryOperatorsNonceResponse")
+}
+
+func init() {
+	proto.RegisterFile("ibc/lightclients/lcp/v1/query.proto", fileDescriptor_c5fc6ad6bf0baf1b)
+}
+
+var fileDescriptor_c5fc6ad6bf0baf1b = []byte{
+	// 527 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0x8e, 0x69, 0x52, 0x9a, 0xa1, 0x42, 0x68, 0x15, 0x20, 0xb8, 0xad, 0x5b, 0xcc, 0xa5, 0x97,
+	0xae, 0x95, 0x16, 0x2a, 0x7e, 0x6e, 0x2d, 0x07, 0x72, 0xe1, 0xc7, 0xa0, 0x0a, 0x71, 0xa9, 0x1c,
+	0x67, 0x93, 0xac, 0x64, 0x7b, 0xb7, 0xde, 0x75, 0xd4, 0x88, 0x13, 0x6f, 0xd0, 0x3b, 0x8f, 0xc2,
+	0x0b, 0xe4, 0xd8, 0x23, 0x27, 0x04, 0xc9, 0x8b, 0xa0, 0xfd, 0x41, 0x24, 0x28, 0xb5, 0x1a, 0x6e,
+	0x3b, 0xb3, 0xdf, 0x7c, 0xdf, 0xec, 0xb7, 0xa3, 0x81, 0x47, 0xb4, 0x13, 0x07, 0x09, 0xed, 0x0f,
+	0x64, 0x9c, 0x50, 0x92, 0x49, 0x11, 0x24, 0x31, 0x0f, 0x86, 0xad, 0xe0, 0xac, 0x20, 0xf9, 0x08,
+	0xf3, 0x9c, 0x49, 0x86, 0xee, 0xd3, 0x4e, 0x8c, 0x67, 0x41, 0x38, 0x89, 0x39, 0x1e, 0xb6, 0xdc,
+	0x46, 0x9f, 0xf5, 0x99, 0xc6, 0x04, 0xea, 0x64, 0xe0, 0xee, 0xb6, 0xe2, 0x8c, 0x59, 0x4e, 0x02,
+	0x03, 0x57, 0x74, 0xe6, 0x64, 0x00, 0x7e, 0x01, 0x9b, 0xef, 0x14, 0xfd, 0x31, 0xcb, 0x04, 0xc9,
+	0x44, 0x21, 0xde, 0xcb, 0x48, 0x92, 0xf6, 0xcb, 0x90, 0x9c, 0x15, 0x44, 0x48, 0xb4, 0x01, 0x75,
+	0x83, 0x3f, 0xa5, 0xdd, 0xa6, 0xb3, 0xe3, 0xec, 0xd6, 0xc3, 0x35, 0x93, 0x68, 0x77, 0xd1, 0x53,
+	0x58, 0x1d, 0x10, 0xd5, 0x4b, 0xf3, 0xc6, 0x8e, 0xb3, 0x7b, 0x6b, 0xdf, 0xc5, 0xaa, 0x3b, 0x25,
+	0x87, 0xad, 0xc8, 0xb0, 0x85, 0x5f, 0x69, 0xc4, 0x51, 0x75, 0xfc, 0x63, 0xbb, 0x12, 0x5a, 0xbc,
+	0xff, 0x11, 0xb6, 0xae, 0x90, 0x15, 0x5c, 0xa5, 0xd0, 0x03, 0x58, 0x13, 0x2a, 0xf5, 0x47, 0x76,
+	0x3d, 0xbc, 0xa9, 0xe3, 0x76, 0x17, 0x6d, 0x42, 0x5d, 0xd2, 0x94, 0x08, 0x19, 0xa5, 0x5c, 0x0b,
+	0x57, 0xc3, 0xbf, 0x09, 0xff, 0x04, 0x1e, 0x6a, 0xe6, 0x13, 0x92, 0xd3, 0xde, 0xe8, 0x98, 0xa5,
+	0x29, 0x95, 0x29, 0xc9, 0xe4, 0xdb, 0x9c, 0xb1, 0xde, 0xb5, 0x5e, 0xd5, 0x80, 0x1a, 0x57, 0x60,
+	0xcd, 0xbd, 0x1e, 0x9a, 0xc0, 0xff, 0xe6, 0x80, 0x5f, 0x46, 0x6c, 0xfb, 0xbe, 0x07, 0xab, 0x3c,
+	0x27, 0x3d, 0x7a, 0x6e, 0xbb, 0xb6, 0x11, 0x42, 0x50, 0xe5, 0x91, 0x1c, 0x58, 0x4e, 0x7d, 0x56,
+	0x42, 0xc3, 0x28, 0x29, 0x48, 0x73, 0xc5, 0x08, 0xe9, 0x60, 0xc6, 0xd4, 0xea, 0x72, 0xa6, 0xce,
+	0x79, 0x56, 0x9b, 0xf3, 0xcc, 0x7f, 0x06, 0xae, 0x6e, 0xfe, 0x0d, 0x27, 0x79, 0x24, 0x59, 0x2e,
+	0x5e, 0xb3, 0x2c, 0x26, 0xd7, 0xb1, 0xc3, 0x0f, 0x61, 0x63, 0x61, 0xa9, 0x7d, 0x70, 0x03, 0x6a,
+	0x99, 0x4a, 0xe8, 0xba, 0x6a, 0x68, 0x02, 0xb4, 0x05, 0x90, 0x91, 0x73, 0x79, 0x6a, 0xae, 0xec,
+	0x27, 0xa9, 0x8c, 0x2e, 0xde, 0xff, 0xba, 0x02, 0x35, 0x4d, 0x8a, 0xbe, 0x38, 0x70, 0xe7, 0xdf,
+	0x21, 0x40, 0x4f, 0xf0, 0x15, 0x53, 0x8e, 0xcb, 0x66, 0xd5, 0x3d, 0x5c, 0xb6, 0xcc, 0x3e, 0xe1,
+	0xc2, 0x81, 0xbb, 0x0b, 0x7f, 0x15, 0x3d, 0x2f, 0x67, 0x2c, 0x9b, 0x31, 0xf7, 0xc5, 0x7f, 0xd5,
+	0xda, 0x96, 0x3e, 0xc3, 0xed, 0x79, 0xbf, 0xd1, 0x41, 0x39, 0xdd, 0xc2, 0x8f, 0x75, 0x1f, 0x2f,
+	0x57, 0x64, 0xc4, 0x8f, 0x3e, 0x8c, 0x7f, 0x79, 0x95, 0xf1, 0xc4, 0x73, 0x2e, 0x27, 0x9e, 0xf3,
+	0x73, 0xe2, 0x39, 0x17, 0x53, 0xaf, 0x72, 0x39, 0xf5, 0x2a, 0xdf, 0xa7, 0x5e, 0xe5, 0xd3, 0x61,
+	0x9f, 0xca, 0x41, 0xd1, 0xc1, 0x31, 0x4b, 0x83, 0x6e, 0x24, 0xa3, 0x78, 0x10, 0xd1, 0x2c, 0x89,
+	0x3a, 0x6a, 0x5b, 0xed, 0xf5, 0x99, 0xd9, 0x60, 0x7b, 0xb3, 0x2b, 0x4c, 0x8e, 0x38, 0x11, 0x9d,
+	0x55, 0xbd, 0x70, 0x0e, 0x7e, 0x0f, 0x00, 0xc3, 0xf7, 0x47, 0xaa, 0xe7, 0x04, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// QueryClient is the client API for Query service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type QueryClient interface {
+	// ConsensusStateID returns the state ID of the consensus state of the client at the height
+	ConsensusStateID(ctx context.Context, in *QueryConsensusStateIDRequest, opts ...grpc.CallOption) (*QueryConsensusStateIDResponse, error)
+	// VerifyCommitmentProof verifies that the commitment proof is signed by the enclave keys of the client
+	// and commits to the state of the consensus state of the client, and returns the commitment
+	VerifyCommitmentProof(ctx context.Context, in *QueryVerifyCommitmentProofRequest, opts ...grpc.CallOption) (*QueryVerifyCommitmentProofResponse, error)
+	// OperatorsNonce returns the nonce of the latest operators update of the client and the one the next update must have
+	OperatorsNonce(ctx context.Context, in *QueryOperatorsNonceRequest, opts ...grpc.CallOption) (*QueryOperatorsNonceResponse, error)
+}
+
+type queryClient struct {
+	cc grpc1.ClientConn
+}
+
+func NewQueryClient(cc grpc1.ClientConn) QueryClient {
+	return &queryClient{cc}
+}
+
+func (c *queryClient) ConsensusStateID(ctx context.Context, in *QueryConsensusStateIDRequest, opts ...grpc.CallOption) (*QueryConsensusStateIDResponse, error) {
+	out := new(QueryConsensusStateIDResponse)
+	err := c.cc.Invoke(ctx, "/ibc.lightclients.lcp.v1.Query/ConsensusStateID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) VerifyCommitmentProof(ctx context.Context, in *QueryVerifyCommitmentProofRequest, opts ...grpc.CallOption) (*QueryVerifyCommitmentProofResponse, error) {
+	out := new(QueryVerifyCommitmentProofResponse)
+	err := c.cc.Invoke(ctx, "/ibc.lightclients.lcp.v1.Query/VerifyCommitmentProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryClient) OperatorsNonce(ctx context.Context, in *QueryOperatorsNonceRequest, opts ...grpc.CallOption) (*QueryOperatorsNonceResponse, error) {
+	out := new(QueryOperatorsNonceResponse)
+	err := c.cc.Invoke(ctx, "/ibc.lightclients.lcp.v1.Query/OperatorsNonce", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// QueryServer is the server API for Query service.
+type QueryServer interface {
+	// ConsensusStateID returns the state ID of the consensus state of the client at the height
+	ConsensusStateID(context.Context, *QueryConsensusStateIDRequest) (*QueryConsensusStateIDResponse, error)
+	// VerifyCommitmentProof verifies that the commitment proof is signed by the enclave keys of the client
+	// and commits to the state of the consensus state of the client, and returns the commitment
+	VerifyCommitmentProof(context.Context, *QueryVerifyCommitmentProofRequest) (*QueryVerifyCommitmentProofResponse, error)
+	// OperatorsNonce returns the nonce of the latest operators update of the client and the one the next update must have
+	OperatorsNonce(context.Context, *QueryOperatorsNonceRequest) (*QueryOperatorsNonceResponse, error)
+}
+
+// UnimplementedQueryServer can be embedded to have forward compatible implementations.
+type UnimplementedQueryServer struct {
+}
+
+func (*UnimplementedQueryServer) ConsensusStateID(ctx context.Context, req *QueryConsensusStateIDRequest) (*QueryConsensusStateIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConsensusStateID not implemented")
+}
+func (*UnimplementedQueryServer) VerifyCommitmentProof(ctx context.Context, req *QueryVerifyCommitmentProofRequest) (*QueryVerifyCommitmentProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyCommitmentProof not implemented")
+}
+func (*UnimplementedQueryServer) OperatorsNonce(ctx context.Context, req *QueryOperatorsNonceRequest) (*QueryOperatorsNonceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OperatorsNonce not implemented")
+}
+
+func RegisterQueryServer(s grpc1.Server, srv QueryServer) {
+	s.RegisterService(&_Query_serviceDesc, srv)
+}
+
+func _Query_ConsensusStateID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryConsensusStateIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).ConsensusStateID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ibc.lightclients.lcp.v1.Query/ConsensusStateID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).ConsensusStateID(ctx, req.(*QueryConsensusStateIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_VerifyCommitmentProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryVerifyCommitmentProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).VerifyCommitmentProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ibc.lightclients.lcp.v1.Query/VerifyCommitmentProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).VerifyCommitmentProof(ctx, req.(*QueryVerifyCommitmentProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Query_OperatorsNonce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryOperatorsNonceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServer).OperatorsNonce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ibc.lightclients.lcp.v1.Query/OperatorsNonce",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServer).OperatorsNonce(ctx, req.(*QueryOperatorsNonceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Query_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "ibc.lightclients.lcp.v1.Query",
+	HandlerType: (*QueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ConsensusStateID",
+			Handler:    _Query_ConsensusStateID_Handler,
+		},
+		{
+			MethodName: "VerifyCommitmentProof",
+			Handler:    _Query_VerifyCommitmentProof_Handler,
+		},
+		{
+			MethodName: "OperatorsNonce",
+			Handler:    _Query_OperatorsNonce_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "ibc/lightclients/lcp/v1/query.proto",
+}
+
+func (m *QueryConsensusStateIDRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryConsensusStateIDRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryConsensusStateIDRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Height.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintQuery(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if len(m.ClientId) > 0 {
+		i -= len(m.ClientId)
+		copy(dAtA[i:], m.ClientId)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.ClientId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryConsensusStateIDResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryConsensusStateIDResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryConsensusStateIDResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Timestamp != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.StateId) > 0 {
+		i -= len(m.StateId)
+		copy(dAtA[i:], m.StateId)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.StateId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryVerifyCommitmentProofRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryVerifyCommitmentProofRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryVerifyCommitmentProofRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Proof) > 0 {
+		i -= len(m.Proof)
+		copy(dAtA[i:], m.Proof)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Proof)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ClientId) > 0 {
+		i -= len(m.ClientId)
+		copy(dAtA[i:], m.ClientId)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.ClientId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryVerifyCommitmentProofResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryVerifyCommitmentProofResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryVerifyCommitmentProofResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.StateId) > 0 {
+		i -= len(m.StateId)
+		copy(dAtA[i:], m.StateId)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.StateId)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	{
+		size, err := m.Height.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintQuery(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x22
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Prefix) > 0 {
+		i -= len(m.Prefix)
+		copy(dAtA[i:], m.Prefix)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Prefix)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryOperatorsNonceRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryOperatorsNonceRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryOperatorsNonceRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ClientId) > 0 {
+		i -= len(m.ClientId)
+		copy(dAtA[i:], m.ClientId)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.ClientId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryOperatorsNonceResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryOperatorsNonceResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryOperatorsNonceResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.NextNonce != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.NextNonce))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Nonce != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Nonce))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
+	offset -= sovQuery(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *QueryConsensusStateIDRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ClientId)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = m.Height.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	return n
+}
+
+func (m *QueryConsensusStateIDResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StateId)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovQuery(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *QueryVerifyCommitmentProofRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ClientId)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Proof)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryVerifyCommitmentProofResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Prefix)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = m.Height.Size()
+	n += 1 + l + sovQuery(uint64(l))
+	l = len(m.StateId)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryOperatorsNonceRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ClientId)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryOperatorsNonceResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Nonce != 0 {
+		n += 1 + sovQuery(uint64(m.Nonce))
+	}
+	if m.NextNonce != 0 {
+		n += 1 + sovQuery(uint64(m.NextNonce))
+	}
+	return n
+}
+
+func sovQuery(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozQuery(x uint64) (n int) {
+	return sovQuery(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *QueryConsensusStateIDRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryConsensusStateIDRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryConsensusStateIDRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Height.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryConsensusStateIDResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryConsensusStateIDResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryConsensusStateIDResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateId = append(m.StateId[:0], dAtA[iNdEx:postIndex]...)
+			if m.StateId == nil {
+				m.StateId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryVerifyCommitmentProofRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryVerifyCommitmentProofRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryVerifyCommitmentProofRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Proof", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Proof = append(m.Proof[:0], dAtA[iNdEx:postIndex]...)
+			if m.Proof == nil {
+				m.Proof = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryVerifyCommitmentProofResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryVerifyCommitmentProofResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryVerifyCommitmentProofResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Prefix", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Prefix = append(m.Prefix[:0], dAtA[iNdEx:postIndex]...)
+			if m.Prefix == nil {
+				m.Prefix = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = append(m.Path[:0], dAtA[iNdEx:postIndex]...)
+			if m.Path == nil {
+				m.Path = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Height.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StateId = append(m.StateId[:0], dAtA[iNdEx:postIndex]...)
+			if m.StateId == nil {
+				m.StateId = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryOperatorsNonceRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryOperatorsNonceRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryOperatorsNonceRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryOperatorsNonceResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryOperatorsNonceResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryOperatorsNonceResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
+			}
+			m.Nonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Nonce |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NextNonce", wireType)
+			}
+			m.NextNonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NextNonce |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipQuery(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthQuery
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupQuery
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthQuery
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthQuery        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowQuery          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupQuery = fmt.Errorf("proto: unexpected end of group")
+)
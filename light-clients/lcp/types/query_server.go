@@ -0,0 +1,112 @@
+package types
+
+import (
+	"context"
+
+	errorsmod "cosmossdk.io/errors"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ QueryServer = (*queryServer)(nil)
+
+// queryServer serves the queries of the LCP clients from the client stores. It never writes to the stores.
+type queryServer struct {
+	cdc           codec.BinaryCodec
+	storeProvider StoreProvider
+}
+
+// NewQueryServer creates a new query server of the LCP clients
+func NewQueryServer(cdc codec.BinaryCodec, storeProvider StoreProvider) QueryServer {
+	return queryServer{cdc: cdc, storeProvider: storeProvider}
+}
+
+// ConsensusStateID implements QueryServer
+func (q queryServer) ConsensusStateID(goCtx context.Context, req *QueryConsensusStateIDRequest) (*QueryConsensusStateIDResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	store, _, err := q.clientStore(sdk.UnwrapSDKContext(goCtx), req.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	consensusState, err := store.GetConsensusState(req.Height)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &QueryConsensusStateIDResponse{StateId: consensusState.StateId, Timestamp: consensusState.Timestamp}, nil
+}
+
+// VerifyCommitmentProof implements QueryServer
+func (q queryServer) VerifyCommitmentProof(goCtx context.Context, req *QueryVerifyCommitmentProofRequest) (*QueryVerifyCommitmentProofResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	ctx := sdk.UnwrapSDKContext(goCtx)
+	store, clientState, err := q.clientStore(ctx, req.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	commitmentProofs, msg, err := decodeCommitmentProof(req.Proof)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, errorsmod.Wrap(ErrInvalidStateCommitment, err.Error()).Error())
+	}
+	if err := clientState.ValidateProxyMessageVersion(commitmentProofs.Message); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := VerifyEnclaveKeySignatures(*clientState, func(ek common.Address) (*EKInfo, error) {
+		return clientState.GetEKInfo(store.store, ek)
+	}, ctx.BlockTime(), crypto.Keccak256Hash(commitmentProofs.Message), commitmentProofs.Signatures); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	consensusState, err := store.GetConsensusState(msg.Height)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if !msg.StateID.EqualBytes(consensusState.StateId) {
+		return nil, status.Error(codes.InvalidArgument, errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid state ID: expected=%v got=%v", HexBytes(consensusState.StateId), msg.StateID).Error())
+	}
+	return &QueryVerifyCommitmentProofResponse{
+		Prefix:  msg.Prefix,
+		Path:    msg.Path,
+		Value:   msg.Value[:],
+		Height:  msg.Height,
+		StateId: msg.StateID[:],
+	}, nil
+}
+
+// OperatorsNonce implements QueryServer
+func (q queryServer) OperatorsNonce(goCtx context.Context, req *QueryOperatorsNonceRequest) (*QueryOperatorsNonceResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "empty request")
+	}
+	store, _, err := q.clientStore(sdk.UnwrapSDKContext(goCtx), req.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := store.GetNonce()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &QueryOperatorsNonceResponse{Nonce: nonce, NextNonce: nonce + 1}, nil
+}
+
+// clientStore returns the store and the state of the LCP client `clientID`
+func (q queryServer) clientStore(ctx sdk.Context, clientID string) (clientStore, *ClientState, error) {
+	if err := ValidateClientID(clientID); err != nil {
+		return clientStore{}, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	store := newClientStore(q.storeProvider.ClientStore(ctx, clientID), q.cdc)
+	clientState, err := store.GetClientState()
+	if errorsmod.IsOf(err, clienttypes.ErrClientNotFound) {
+		return clientStore{}, nil, status.Error(codes.NotFound, errorsmod.Wrap(err, clientID).Error())
+	} else if err != nil {
+		return clientStore{}, nil, status.Error(codes.InvalidArgument, errorsmod.Wrap(err, clientID).Error())
+	}
+	return store, clientState, nil
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+const (
+	// MaxAllowedQuoteStatuses is the maximum number of the allowed quote statuses
+	MaxAllowedQuoteStatuses = 16
+	// MaxAllowedAdvisoryIDs is the maximum number of the allowed advisory IDs, which bounds the cost to verify an AVR
+	MaxAllowedAdvisoryIDs = 256
+)
+
+// NormalizeQuoteStatus returns the quote status without the leading and trailing spaces in upper case, e.g. "GROUP_OUT_OF_DATE"
+func NormalizeQuoteStatus(status string) string {
+	return strings.ToUpper(strings.TrimSpace(status))
+}
+
+// NormalizeAdvisoryID returns the advisory ID without the leading and trailing spaces in upper case
+// as Intel publishes them, e.g. "INTEL-SA-00334"
+func NormalizeAdvisoryID(id string) string {
+	return strings.ToUpper(strings.TrimSpace(id))
+}
+
+// ValidateQuotePolicy validates the allowed quote statuses and advisory IDs:
+// the numbers of them must not exceed the maximums, and no entry may be empty after the normalization
+func ValidateQuotePolicy(allowedQuoteStatuses, allowedAdvisoryIDs []string) error {
+	if l := len(allowedQuoteStatuses); l > MaxAllowedQuoteStatuses {
+		return fmt.Errorf("too many allowed quote statuses: max=%v actual=%v", MaxAllowedQuoteStatuses, l)
+	}
+	if l := len(allowedAdvisoryIDs); l > MaxAllowedAdvisoryIDs {
+		return fmt.Errorf("too many allowed advisory IDs: max=%v actual=%v", MaxAllowedAdvisoryIDs, l)
+	}
+	for i, s := range allowedQuoteStatuses {
+		if NormalizeQuoteStatus(s) == "" {
+			return fmt.Errorf("allowed quote status must be non-empty: index=%v value=%q", i, s)
+		}
+	}
+	for i, id := range allowedAdvisoryIDs {
+		if NormalizeAdvisoryID(id) == "" {
+			return fmt.Errorf("allowed advisory ID must be non-empty: index=%v value=%q", i, id)
+		}
+	}
+	return nil
+}
+
+// IsAllowedQuoteStatus returns true if `status` is "OK" or included in `allowed` after the normalization
+func IsAllowedQuoteStatus(allowed []string, status string) bool {
+	status = NormalizeQuoteStatus(status)
+	if status == QuoteOK {
+		return true
+	}
+	for _, s := range allowed {
+		if NormalizeQuoteStatus(s) == status {
+			return true
+		}
+	}
+	return false
+}
+
+// AreAllowedAdvisoryIDs returns true if all of `ids` are included in `allowed` after the normalization
+func AreAllowedAdvisoryIDs(allowed []string, ids []string) bool {
+	if len(ids) == 0 {
+		return true
+	}
+	set := mapset.NewThreadUnsafeSet[string]()
+	for _, id := range allowed {
+		set.Add(NormalizeAdvisoryID(id))
+	}
+	for _, id := range ids {
+		if !set.Contains(NormalizeAdvisoryID(id)) {
+			return false
+		}
+	}
+	return true
+}
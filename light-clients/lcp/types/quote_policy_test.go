@@ -0,0 +1,80 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotePolicyNormalization(t *testing.T) {
+	cs := ClientState{
+		AllowedQuoteStatuses: []string{" group_out_of_date", "SW_Hardening_Needed "},
+		AllowedAdvisoryIds:   []string{"INTEL-SA-00334 ", "intel-sa-00219"},
+	}
+	var statusCases = []struct {
+		status  string
+		allowed bool
+	}{
+		{QuoteOK, true},
+		{"GROUP_OUT_OF_DATE", true},
+		{"SW_HARDENING_NEEDED", true},
+		{"CONFIGURATION_NEEDED", false},
+	}
+	for _, c := range statusCases {
+		require.Equal(t, c.allowed, cs.isAllowedStatus(c.status), c.status)
+	}
+	var advisoryCases = []struct {
+		ids     []string
+		allowed bool
+	}{
+		{nil, true},
+		{[]string{"INTEL-SA-00334"}, true},
+		{[]string{"INTEL-SA-00334", "INTEL-SA-00219"}, true},
+		{[]string{"intel-sa-00334 "}, true},
+		{[]string{"INTEL-SA-00334", "INTEL-SA-00615"}, false},
+	}
+	for _, c := range advisoryCases {
+		require.Equal(t, c.allowed, cs.isAllowedAdvisoryIDs(c.ids), c.ids)
+	}
+}
+
+func TestValidateQuotePolicy(t *testing.T) {
+	var tooManyAdvisoryIDs []string
+	for i := 0; i <= MaxAllowedAdvisoryIDs; i++ {
+		tooManyAdvisoryIDs = append(tooManyAdvisoryIDs, fmt.Sprintf("INTEL-SA-%05d", i))
+	}
+	var cases = []struct {
+		name        string
+		statuses    []string
+		advisoryIDs []string
+		// expected substring of the error. if empty, the policy is valid
+		err string
+	}{
+		{"empty", nil, nil, ""},
+		{"padded and mixed-case", []string{" Group_Out_Of_Date "}, []string{"intel-sa-00334 "}, ""},
+		{"max advisory IDs", nil, tooManyAdvisoryIDs[:MaxAllowedAdvisoryIDs], ""},
+		{"too many advisory IDs", nil, tooManyAdvisoryIDs, "too many allowed advisory IDs"},
+		{"too many statuses", make([]string, MaxAllowedQuoteStatuses+1), nil, "too many allowed quote statuses"},
+		{"blank status", []string{" "}, nil, "allowed quote status must be non-empty"},
+		{"blank advisory ID", nil, []string{"INTEL-SA-00334", ""}, "allowed advisory ID must be non-empty: index=1"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateQuotePolicy(c.statuses, c.advisoryIDs)
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+
+	// the client state and the message to update the params are validated with the same rules
+	cs := ClientState{KeyExpiration: 1, Mrenclave: make([]byte, MrenclaveSize), AllowedAdvisoryIds: tooManyAdvisoryIDs}
+	require.ErrorContains(t, cs.Validate(), "too many allowed advisory IDs")
+	msg := UpdateClientParamsMessage{NewKeyExpiration: 1, NewAllowedAdvisoryIds: tooManyAdvisoryIDs, Signatures: [][]byte{{}}}
+	require.ErrorContains(t, msg.ValidateBasic(), "too many allowed advisory IDs")
+	msg = UpdateClientParamsMessage{NewKeyExpiration: 1, NewAllowedQuoteStatuses: []string{" ok"}, Signatures: [][]byte{{}}}
+	require.ErrorContains(t, msg.ValidateBasic(), "is always allowed")
+}
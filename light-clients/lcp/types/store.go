@@ -3,15 +3,16 @@ package types
 import (
 	"reflect"
 	"strings"
+	"time"
 
 	errorsmod "cosmossdk.io/errors"
 	storeprefix "cosmossdk.io/store/prefix"
 	storetypes "cosmossdk.io/store/types"
 	"github.com/cosmos/cosmos-sdk/codec"
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
 	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
 	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 var (
@@ -19,47 +20,16 @@ var (
 	KeyProcessedTime = []byte("/processedTime")
 	// KeyProcessedHeight is appended to consensus state key to store the processed height
 	KeyProcessedHeight = []byte("/processedHeight")
+	// KeyConsensusSigner is appended to consensus state key to store the address of the enclave key that signed the update
+	KeyConsensusSigner = []byte("/signer")
+	// KeyLastUpdateTime is the key under which the block time of the latest update is stored
+	KeyLastUpdateTime = []byte("lastUpdateTime")
 )
 
-// setClientState stores the client state
-func setClientState(clientStore storetypes.KVStore, cdc codec.BinaryCodec, clientState *ClientState) {
-	key := host.ClientStateKey()
-	val := clienttypes.MustMarshalClientState(cdc, clientState)
-	clientStore.Set(key, val)
-}
-
-// setConsensusState stores the consensus state at the given height.
-func setConsensusState(clientStore storetypes.KVStore, cdc codec.BinaryCodec, consensusState *ConsensusState, height exported.Height) {
-	key := host.ConsensusStateKey(height)
-	val := clienttypes.MustMarshalConsensusState(cdc, consensusState)
-	clientStore.Set(key, val)
-}
-
 // GetConsensusState retrieves the consensus state from the client prefixed
 // store. An error is returned if the consensus state does not exist.
 func GetConsensusState(store storetypes.KVStore, cdc codec.BinaryCodec, height exported.Height) (*ConsensusState, error) {
-	bz := store.Get(host.ConsensusStateKey(height))
-	if bz == nil {
-		return nil, errorsmod.Wrapf(
-			clienttypes.ErrConsensusStateNotFound,
-			"consensus state does not exist for height %s", height,
-		)
-	}
-
-	consensusStateI, err := clienttypes.UnmarshalConsensusState(cdc, bz)
-	if err != nil {
-		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidConsensus, "unmarshal error: %v", err)
-	}
-
-	consensusState, ok := consensusStateI.(*ConsensusState)
-	if !ok {
-		return nil, errorsmod.Wrapf(
-			clienttypes.ErrInvalidConsensus,
-			"invalid consensus type %T, expected %T", consensusState, &ConsensusState{},
-		)
-	}
-
-	return consensusState, nil
+	return newClientStore(store, cdc).GetConsensusState(height)
 }
 
 // ProcessedTimeKey returns the key under which the processed time will be stored in the client store.
@@ -71,26 +41,13 @@ func ProcessedTimeKey(height exported.Height) []byte {
 // This is useful when validating whether a packet has reached the time specified delay period in the tendermint client's
 // verification functions
 func SetProcessedTime(clientStore storetypes.KVStore, height exported.Height, timeNs uint64) {
-	key := ProcessedTimeKey(height)
-	val := sdk.Uint64ToBigEndian(timeNs)
-	clientStore.Set(key, val)
+	newClientStore(clientStore, nil).SetProcessedTime(height, timeNs)
 }
 
 // GetProcessedTime gets the time (in nanoseconds) at which this chain received and processed a tendermint header.
 // This is used to validate that a received packet has passed the time delay period.
 func GetProcessedTime(clientStore storetypes.KVStore, height exported.Height) (uint64, bool) {
-	key := ProcessedTimeKey(height)
-	bz := clientStore.Get(key)
-	if bz == nil {
-		return 0, false
-	}
-	return sdk.BigEndianToUint64(bz), true
-}
-
-// deleteProcessedTime deletes the processedTime for a given height
-func deleteProcessedTime(clientStore storetypes.KVStore, height exported.Height) {
-	key := ProcessedTimeKey(height)
-	clientStore.Delete(key)
+	return newClientStore(clientStore, nil).GetProcessedTime(height)
 }
 
 // ProcessedHeightKey returns the key under which the processed height will be stored in the client store.
@@ -102,30 +59,30 @@ func ProcessedHeightKey(height exported.Height) []byte {
 // This is useful when validating whether a packet has reached the specified block delay period in the tendermint client's
 // verification functions
 func SetProcessedHeight(clientStore storetypes.KVStore, consHeight, processedHeight exported.Height) {
-	key := ProcessedHeightKey(consHeight)
-	val := []byte(processedHeight.String())
-	clientStore.Set(key, val)
+	newClientStore(clientStore, nil).SetProcessedHeight(consHeight, processedHeight)
 }
 
 // GetProcessedHeight gets the height at which this chain received and processed a tendermint header.
 // This is used to validate that a received packet has passed the block delay period.
 func GetProcessedHeight(clientStore storetypes.KVStore, height exported.Height) (exported.Height, bool) {
-	key := ProcessedHeightKey(height)
-	bz := clientStore.Get(key)
-	if bz == nil {
-		return nil, false
-	}
-	processedHeight, err := clienttypes.ParseHeight(string(bz))
-	if err != nil {
-		return nil, false
-	}
-	return processedHeight, true
+	return newClientStore(clientStore, nil).GetProcessedHeight(height)
+}
+
+// ConsensusSignerKey returns the key under which the signer of the consensus state will be stored in the client store.
+func ConsensusSignerKey(height exported.Height) []byte {
+	return append(host.ConsensusStateKey(height), KeyConsensusSigner...)
+}
+
+// GetConsensusSigner gets the address of the enclave key that signed the update creating the consensus state at the given height.
+// The signer is not recorded for the consensus states created by the client creation or by the previous versions.
+func GetConsensusSigner(clientStore storetypes.KVStore, height exported.Height) (common.Address, bool) {
+	return newClientStore(clientStore, nil).GetConsensusSigner(height)
 }
 
-// deleteProcessedHeight deletes the processedHeight for a given height
-func deleteProcessedHeight(clientStore storetypes.KVStore, height exported.Height) {
-	key := ProcessedHeightKey(height)
-	clientStore.Delete(key)
+// GetLastUpdateTime gets the block time of the latest update of the client, or of its creation if it has never been updated.
+// The time is not recorded for the clients which have not been updated since the previous versions.
+func GetLastUpdateTime(clientStore storetypes.KVStore) (time.Time, bool) {
+	return newClientStore(clientStore, nil).GetLastUpdateTime()
 }
 
 // getClientID extracts and validates the clientID from the clientStore's prefix.
@@ -0,0 +1,46 @@
+package types_test
+
+import (
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/light-clients/lcp/testutil"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyRegisterEnclaveKeyMinimumISVSVN(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	// the key is attested by the fixture whose ISV SVN is zero
+	fixture := testutil.LoadRegisterEnclaveKeyFixture(t, "001-avr")
+	require.Equal(t, uint16(0), fixture.ISVSVN)
+	h := testutil.NewHarness(t)
+	h.SetBlockTime(fixture.AttestationTime)
+
+	var cases = []struct {
+		minimum uint16
+		ok      bool
+	}{
+		{0, true},
+		{1, false},
+		{2, false},
+	}
+	for _, c := range cases {
+		cs := *fixture.ClientState
+		cs.MinimumIsvSvn = uint32(c.minimum)
+		h.SetClientState(&cs)
+		err := h.VerifyClientMessage(fixture.Message)
+		if c.ok {
+			require.NoError(t, err, "minimum=%v", c.minimum)
+		} else {
+			require.ErrorIs(t, err, clienttypes.ErrInvalidHeader, "minimum=%v", c.minimum)
+			require.ErrorContains(t, err, "the ISV SVN is lower than the minimum")
+		}
+	}
+
+	cs := *fixture.ClientState
+	cs.MinimumIsvSvn = 1 << 16
+	require.ErrorContains(t, cs.Validate(), "`MinimumIsvSvn` must be less than or equal to 65535")
+}
@@ -11,14 +11,19 @@ import (
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
-	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
 	"github.com/cosmos/ibc-go/v8/modules/core/exported"
 	"github.com/datachainlab/lcp-go/sgx/ias"
-	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+const (
+	// EnclaveKeyPruningGracePeriod is the period after the expiration of an enclave key until it is pruned from the client store
+	EnclaveKeyPruningGracePeriod = 7 * 24 * time.Hour
+	// MaxPrunedEnclaveKeysPerRegistration is the maximum number of the expired enclave keys pruned on each registration of an enclave key
+	MaxPrunedEnclaveKeysPerRegistration = 10
+)
+
 type EKInfo struct {
 	ExpiredAt uint64
 	Operator  common.Address
@@ -33,12 +38,18 @@ func (ei EKInfo) IsMatchOperator(operator common.Address) bool {
 }
 
 func (cs ClientState) VerifyClientMessage(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) error {
+	if cs.Frozen {
+		return errorsmod.Wrap(clienttypes.ErrClientFrozen, "the client is frozen by a misbehaviour")
+	}
 	switch clientMsg := clientMsg.(type) {
 	case *UpdateClientMessage:
 		pmsg, err := clientMsg.GetProxyMessage()
 		if err != nil {
 			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message: %v", err)
 		}
+		if err := cs.ValidateProxyMessageVersion(clientMsg.ProxyMessage); err != nil {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message: %v", err)
+		}
 		if err := cs.VerifySignatures(ctx, clientStore, crypto.Keccak256Hash(clientMsg.ProxyMessage), clientMsg.Signatures); err != nil {
 			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, err.Error())
 		}
@@ -52,94 +63,69 @@ func (cs ClientState) VerifyClientMessage(ctx sdk.Context, cdc codec.BinaryCodec
 		}
 	case *RegisterEnclaveKeyMessage:
 		return cs.verifyRegisterEnclaveKey(ctx, clientStore, clientMsg)
+	case *DCAPRegisterEnclaveKeyMessage:
+		return cs.verifyDCAPRegisterEnclaveKey(ctx, clientStore, clientMsg)
 	case *UpdateOperatorsMessage:
 		return cs.verifyUpdateOperators(ctx, clientStore, clientMsg)
+	case *UpdateClientParamsMessage:
+		return cs.verifyUpdateClientParams(ctx, clientStore, clientMsg)
 	default:
 		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "unknown client message %T", clientMsg)
 	}
 }
 
+// UpdateStateOnMisbehaviour freezes the client. The client is never unfrozen except by CheckSubstituteAndUpdateState.
 func (cs ClientState) UpdateStateOnMisbehaviour(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, msg exported.ClientMessage) {
-	cs.Frozen = true
-	clientStore.Set(host.ClientStateKey(), clienttypes.MustMarshalClientState(cdc, &cs))
+	newClientStore(clientStore, cdc).SetFrozen(cs)
 }
 
 func (cs ClientState) verifyUpdateClient(ctx sdk.Context, cdc codec.BinaryCodec, store storetypes.KVStore, msg *UpdateClientMessage, pmsg *UpdateStateProxyMessage) error {
-	if cs.LatestHeight.IsZero() {
-		if len(pmsg.EmittedStates) == 0 {
-			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message %v: `NewState` must be non-nil", msg)
-		}
-	} else {
+	var prevConsensusState *ConsensusState
+	if !cs.LatestHeight.IsZero() {
 		if pmsg.PrevHeight == nil || pmsg.PrevStateID == nil {
 			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message %v: `PrevHeight` and `PrevStateID` must be non-nil", msg)
 		}
-		prevConsensusState, err := GetConsensusState(store, cdc, pmsg.PrevHeight)
+		var err error
+		prevConsensusState, err = newClientStore(store, cdc).GetConsensusState(pmsg.PrevHeight)
 		if err != nil {
 			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to get consensus state: %v", err)
 		}
-		if !bytes.Equal(prevConsensusState.StateId, pmsg.PrevStateID[:]) {
-			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "unexpected StateID: expected=%v actual=%v", prevConsensusState.StateId, pmsg.PrevStateID[:])
-		}
 	}
-
-	if err := pmsg.Context.Validate(ctx.BlockTime()); err != nil {
-		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid context: %v", err)
-	}
-
-	return nil
+	return verifyUpdateStateLinkage(prevConsensusState, msg, pmsg, ctx.BlockTime())
 }
 
 func (cs ClientState) verifyRegisterEnclaveKey(ctx sdk.Context, store storetypes.KVStore, message *RegisterEnclaveKeyMessage) error {
-	// TODO define error types
-
-	if err := ias.VerifyReport(message.Report, message.Signature, message.SigningCert, ctx.BlockTime()); err != nil {
-		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message: message=%v, err=%v", message, err)
-	}
-	avr, err := ias.ParseAndValidateAVR(message.Report)
-	if err != nil {
-		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid AVR: report=%v err=%v", message.Report, err)
-	}
-	quoteStatus := avr.ISVEnclaveQuoteStatus.String()
-	if quoteStatus == QuoteOK {
-		if len(avr.AdvisoryIDs) != 0 {
-			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "advisory IDs should be empty when status is OK: actual=%v", avr.AdvisoryIDs)
-		}
-	} else {
-		if !cs.isAllowedStatus(quoteStatus) {
-			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "disallowed quote status exists: allowed=%v actual=%v", cs.AllowedQuoteStatuses, quoteStatus)
-		}
-		if !cs.isAllowedAdvisoryIDs(avr.AdvisoryIDs) {
-			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "disallowed advisory ID(s) exists: allowed=%v actual=%v", cs.AllowedAdvisoryIds, avr.AdvisoryIDs)
-		}
+	if err := message.ValidateBasic(); err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message: %v", err)
 	}
-	quote, err := avr.Quote()
+	key, err := VerifyRegisterEnclaveKeyAVR(cs.GetEnclaveKeyParams(), message, ctx.BlockTime())
 	if err != nil {
 		return err
 	}
-	if !bytes.Equal(cs.Mrenclave, quote.Report.MRENCLAVE[:]) {
-		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid AVR: mrenclave mismatch: expected=%v actual=%v", cs.Mrenclave, quote.Report.MRENCLAVE[:])
-	}
-	var operator common.Address
-	if len(message.OperatorSignature) > 0 {
-		commitment, err := ComputeEIP712RegisterEnclaveKeyHash(string(message.Report))
-		if err != nil {
-			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to compute commitment: %v", err)
-		}
-		operator, err = RecoverAddress(commitment, message.OperatorSignature)
-		if err != nil {
-			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to recover operator address: %v", err)
-		}
+	return cs.verifyAttestedEnclaveKeyNotConflicted(store, key, ctx.BlockTime())
+}
+
+func (cs ClientState) verifyDCAPRegisterEnclaveKey(ctx sdk.Context, store storetypes.KVStore, message *DCAPRegisterEnclaveKeyMessage) error {
+	if err := message.ValidateBasic(); err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message: %v", err)
 	}
-	ek, expectedOperator, err := ias.GetEKAndOperator(quote)
+	key, err := VerifyDCAPRegisterEnclaveKey(cs.GetEnclaveKeyParams(), message, ctx.BlockTime())
 	if err != nil {
-		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to get enclave key and operator: %v", err)
+		return err
 	}
-	if (expectedOperator != common.Address{}) && operator != expectedOperator {
-		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid operator: expected=%v actual=%v", expectedOperator, operator)
+	return cs.verifyAttestedEnclaveKeyNotConflicted(store, key, ctx.BlockTime())
+}
+
+// verifyAttestedEnclaveKeyNotConflicted returns an error if `key` is already registered with another operator or expiration,
+// if it would expire before the prune cutoff at `now`, or if it has been pruned after the expiration recently.
+// The attestation time of a DCAP attestation is the issue date of the collateral, which is not bound to the quote,
+// so a pruned key could otherwise be registered again with a fresh expiration.
+func (cs ClientState) verifyAttestedEnclaveKeyNotConflicted(store storetypes.KVStore, key *AttestedEnclaveKey, now time.Time) error {
+	if err := verifyEnclaveKeyNotPruned(store, key.EnclaveKey, key.ExpiredAt, now); err != nil {
+		return errorsmod.Wrap(clienttypes.ErrInvalidHeader, err.Error())
 	}
-	expiredAt := avr.GetTimestamp().Add(cs.getKeyExpiration())
-	if cs.Contains(store, ek) {
-		if err := cs.ensureEKInfoMatch(store, ek, operator, expiredAt); err != nil {
+	if cs.Contains(store, key.EnclaveKey) {
+		if err := cs.ensureEKInfoMatch(store, key.EnclaveKey, key.Operator, key.ExpiredAt); err != nil {
 			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid enclave key info: %v", err)
 		}
 	}
@@ -150,14 +136,14 @@ func (cs ClientState) verifyUpdateOperators(ctx sdk.Context, store storetypes.KV
 	if err := message.ValidateBasic(); err != nil {
 		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message: %v", err)
 	}
-	if len(cs.Operators) == 0 {
+	if !cs.HasOperators() {
 		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "permissionless operators")
 	}
 	clientID, err := getClientID(store)
 	if err != nil {
 		return err
 	}
-	nextNonce := cs.OperatorsNonce + 1
+	nextNonce := cs.NextOperatorsNonce()
 	if message.Nonce != nextNonce {
 		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid nonce: expected=%v actual=%v clientID=%v", nextNonce, message.Nonce, clientID)
 	}
@@ -170,9 +156,9 @@ func (cs ClientState) verifyUpdateOperators(ctx sdk.Context, store storetypes.KV
 		if op == zeroAddr {
 			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid operator: operator address must not be zero: clientID=%v", clientID)
 		}
-		// check if the operators are ordered
-		if i > 0 && bytes.Compare(newOperators[i-1].Bytes(), op.Bytes()) > 0 {
-			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "operator addresses must be ordered: clientID=%v op0=%v op1=%v", clientID, newOperators[i-1].String(), op.String())
+		// check if the operators are ordered without duplicates
+		if i > 0 && bytes.Compare(newOperators[i-1].Bytes(), op.Bytes()) >= 0 {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "operator addresses must be ordered in ascending order without duplicates: clientID=%v op0=%v op1=%v", clientID, newOperators[i-1].String(), op.String())
 		}
 	}
 	signBytes, err := ComputeEIP712CosmosUpdateOperators(
@@ -187,13 +173,55 @@ func (cs ClientState) verifyUpdateOperators(ctx sdk.Context, store storetypes.KV
 	if err != nil {
 		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to compute sign bytes: err=%v clientID=%v", err, clientID)
 	}
-	commitment := crypto.Keccak256Hash(signBytes)
+	return cs.verifyOperatorsQuorum(crypto.Keccak256Hash(signBytes), message.Signatures, clientID)
+}
+
+// verifyUpdateClientParams verifies that the operators of the quorum signed the new parameters with the next operators nonce.
+// The nonce is shared with UpdateOperatorsMessage, so a signed message cannot be replayed after either message is applied.
+func (cs ClientState) verifyUpdateClientParams(ctx sdk.Context, store storetypes.KVStore, message *UpdateClientParamsMessage) error {
+	if err := message.ValidateBasic(); err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message: %v", err)
+	}
+	if !cs.HasOperators() {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "permissionless operators")
+	}
+	clientID, err := getClientID(store)
+	if err != nil {
+		return err
+	}
+	nextNonce := cs.NextOperatorsNonce()
+	if message.Nonce != nextNonce {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid nonce: expected=%v actual=%v clientID=%v", nextNonce, message.Nonce, clientID)
+	}
+	signBytes, err := ComputeEIP712CosmosUpdateClientParams(
+		ctx.ChainID(),
+		[]byte(exported.StoreKey),
+		clientID,
+		message.Nonce,
+		message.NewAllowedQuoteStatuses,
+		message.NewAllowedAdvisoryIds,
+		message.NewKeyExpiration,
+	)
+	if err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to compute sign bytes: err=%v clientID=%v", err, clientID)
+	}
+	return cs.verifyOperatorsQuorum(crypto.Keccak256Hash(signBytes), message.Signatures, clientID)
+}
+
+// verifyOperatorsQuorum verifies that the operators of the threshold signed `commitment`.
+// `signatures` must have a slot per operator in the order of the operators, and an empty slot means that the operator did not sign.
+// Since the signature in each slot must be recovered to the operator of the slot, an operator is counted at most once.
+func (cs ClientState) verifyOperatorsQuorum(commitment common.Hash, signatures [][]byte, clientID string) error {
+	operators := cs.GetOperators()
+	if opNum, sigNum := len(operators), len(signatures); opNum != sigNum {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid signature length: expected=%v actual=%v clientID=%v", opNum, sigNum, clientID)
+	}
 	var success uint64 = 0
-	for i, op := range cs.GetOperators() {
-		if len(message.Signatures[i]) == 0 {
+	for i, op := range operators {
+		if len(signatures[i]) == 0 {
 			continue
 		}
-		addr, err := RecoverAddress(commitment, message.Signatures[i])
+		addr, err := RecoverAddress(commitment, signatures[i])
 		if err != nil {
 			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to recover operator address: err=%v clientID=%v", err, clientID)
 		}
@@ -202,13 +230,16 @@ func (cs ClientState) verifyUpdateOperators(ctx sdk.Context, store storetypes.KV
 		}
 		success++
 	}
-	if success*cs.OperatorsThresholdDenominator < cs.OperatorsThresholdDenominator*uint64(len(cs.Operators)) {
-		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "insufficient signatures: expected=%v actual=%v clientID=%v", cs.OperatorsThresholdDenominator, success, clientID)
+	if success*cs.OperatorsThresholdDenominator < cs.OperatorsThresholdNumerator*uint64(len(operators)) {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "insufficient signatures: threshold=%v/%v operators=%v actual=%v clientID=%v", cs.OperatorsThresholdNumerator, cs.OperatorsThresholdDenominator, len(operators), success, clientID)
 	}
 	return nil
 }
 
 func (cs ClientState) UpdateState(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, clientMsg exported.ClientMessage) []exported.Height {
+	if cs.Frozen {
+		panic(errorsmod.Wrap(clienttypes.ErrClientFrozen, "the client is frozen by a misbehaviour"))
+	}
 	switch clientMsg := clientMsg.(type) {
 	case *UpdateClientMessage:
 		pmsg, err := clientMsg.GetProxyMessage()
@@ -217,30 +248,78 @@ func (cs ClientState) UpdateState(ctx sdk.Context, cdc codec.BinaryCodec, client
 		}
 		switch pmsg := pmsg.(type) {
 		case *UpdateStateProxyMessage:
-			return cs.updateClient(cdc, clientStore, pmsg)
+			signer, err := recoverSigner(crypto.Keccak256Hash(clientMsg.ProxyMessage), clientMsg.Signatures)
+			if err != nil {
+				panic(errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to recover the signer: %v", err))
+			}
+			return cs.updateClient(ctx, cdc, clientStore, pmsg, signer)
 		default:
 			panic(errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "unexpected message type: %T", pmsg))
 		}
 	case *RegisterEnclaveKeyMessage:
 		return cs.registerEnclaveKey(ctx, clientStore, clientMsg)
+	case *DCAPRegisterEnclaveKeyMessage:
+		return cs.registerDCAPEnclaveKey(ctx, clientStore, clientMsg)
 	case *UpdateOperatorsMessage:
 		return cs.updateOperators(ctx, cdc, clientStore, clientMsg)
+	case *UpdateClientParamsMessage:
+		return cs.updateClientParams(ctx, cdc, clientStore, clientMsg)
 	default:
 		panic(errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "unknown client message %T", clientMsg))
 	}
 }
 
-func (cs ClientState) updateClient(cdc codec.BinaryCodec, clientStore storetypes.KVStore, msg *UpdateStateProxyMessage) []exported.Height {
+func (cs ClientState) updateClient(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, msg *UpdateStateProxyMessage, signer common.Address) []exported.Height {
 	if cs.LatestHeight.LT(msg.PostHeight) {
 		cs.LatestHeight = msg.PostHeight
 	}
 	consensusState := ConsensusState{StateId: msg.PostStateID[:], Timestamp: msg.Timestamp.Uint64()}
 
-	setClientState(clientStore, cdc, &cs)
-	setConsensusState(clientStore, cdc, &consensusState, msg.PostHeight)
+	store := newClientStore(clientStore, cdc)
+	if lastUpdateTime, ok := store.GetLastUpdateTime(); ok {
+		// the update is applied even if the gap is exceeded because rejecting it would keep the client stale
+		if gap, exceeded := cs.updateGapExceeded(lastUpdateTime, ctx.BlockTime()); exceeded {
+			ctx.EventManager().EmitEvent(
+				sdk.NewEvent(
+					EventTypeUpdateGapExceeded,
+					sdk.NewAttribute(AttributeKeyLastUpdateTime, lastUpdateTime.UTC().String()),
+					sdk.NewAttribute(AttributeKeyUpdateGap, gap.String()),
+					sdk.NewAttribute(AttributeKeyMaxUpdateGap, cs.GetMaxUpdateGap().String()),
+				),
+			)
+		}
+	}
+	store.SetClientState(&cs)
+	store.SetConsensusState(msg.PostHeight, &consensusState)
+	store.SetConsensusSigner(msg.PostHeight, signer)
+	store.SetLastUpdateTime(ctx.BlockTime())
 	return nil
 }
 
+// GetMaxUpdateGap returns the maximum allowed gap between the block times of consecutive updates.
+// Zero means that the gap is not bounded.
+func (cs ClientState) GetMaxUpdateGap() time.Duration {
+	return time.Duration(cs.MaxUpdateGap) * time.Second
+}
+
+// updateGapExceeded returns the gap between `lastUpdateTime` and `blockTime` and whether it exceeds the max update gap
+func (cs ClientState) updateGapExceeded(lastUpdateTime, blockTime time.Time) (time.Duration, bool) {
+	gap := blockTime.Sub(lastUpdateTime)
+	return gap, cs.MaxUpdateGap != 0 && gap > cs.GetMaxUpdateGap()
+}
+
+// recoverSigner returns the enclave key that signed the commitment.
+// If the client has the operators, the key of the first operator who signed is returned.
+func recoverSigner(commitment [32]byte, signatures [][]byte) (common.Address, error) {
+	for _, sig := range signatures {
+		if len(sig) == 0 {
+			continue
+		}
+		return RecoverAddress(commitment, sig)
+	}
+	return common.Address{}, fmt.Errorf("no signature found")
+}
+
 func (cs ClientState) registerEnclaveKey(ctx sdk.Context, clientStore storetypes.KVStore, message *RegisterEnclaveKeyMessage) []exported.Height {
 	avr, err := ias.ParseAndValidateAVR(message.Report)
 	if err != nil {
@@ -265,7 +344,24 @@ func (cs ClientState) registerEnclaveKey(ctx sdk.Context, clientStore storetypes
 			panic(errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to recover operator address: %v", err))
 		}
 	}
-	expiredAt := avr.GetTimestamp().Add(cs.getKeyExpiration())
+	return cs.setAttestedEnclaveKey(ctx, clientStore, ek, operator, cs.KeyExpiredAt(avr.GetTimestamp()))
+}
+
+// registerDCAPEnclaveKey registers the enclave key attested by the DCAP attestation of `message`,
+// which is verified again at the block time because the verification result is not kept from VerifyClientMessage
+func (cs ClientState) registerDCAPEnclaveKey(ctx sdk.Context, clientStore storetypes.KVStore, message *DCAPRegisterEnclaveKeyMessage) []exported.Height {
+	key, err := VerifyDCAPRegisterEnclaveKey(cs.GetEnclaveKeyParams(), message, ctx.BlockTime())
+	if err != nil {
+		panic(err)
+	}
+	return cs.setAttestedEnclaveKey(ctx, clientStore, key.EnclaveKey, key.Operator, key.ExpiredAt)
+}
+
+// setAttestedEnclaveKey stores the info of the attested enclave key `ek` if it is not registered yet
+func (cs ClientState) setAttestedEnclaveKey(ctx sdk.Context, clientStore storetypes.KVStore, ek, operator common.Address, expiredAt time.Time) []exported.Height {
+	if err := verifyEnclaveKeyNotPruned(clientStore, ek, expiredAt, ctx.BlockTime()); err != nil {
+		panic(errorsmod.Wrap(clienttypes.ErrInvalidHeader, err.Error()))
+	}
 	if cs.Contains(clientStore, ek) {
 		if err := cs.ensureEKInfoMatch(clientStore, ek, operator, expiredAt); err != nil {
 			panic(err)
@@ -284,15 +380,56 @@ func (cs ClientState) registerEnclaveKey(ctx sdk.Context, clientStore storetypes
 	if err := cs.SetEKInfo(clientStore, ek, operator, expiredAt); err != nil {
 		panic(err)
 	}
+	cs.pruneExpiredEnclaveKeys(ctx, clientStore)
+	return nil
+}
+
+// verifyEnclaveKeyNotPruned returns an error if the enclave key `ek` attested to expire at `expiredAt` would be pruned at `now`,
+// or if the marker of the key pruned before remains
+func verifyEnclaveKeyNotPruned(store storetypes.KVStore, ek common.Address, expiredAt, now time.Time) error {
+	if cutoff := enclaveKeyPruneCutoff(now); expiredAt.Before(cutoff) {
+		return fmt.Errorf("the enclave key expires before the prune cutoff: enclave_key=%v expired_at=%v cutoff=%v", ek, expiredAt, cutoff)
+	}
+	if newClientStore(store, nil).IsEnclaveKeyPruned(ek) {
+		return fmt.Errorf("the enclave key has been expired and pruned: enclave_key=%v", ek)
+	}
 	return nil
 }
 
+// enclaveKeyPruneCutoff returns the time before which the expired enclave keys are pruned at `now`
+func enclaveKeyPruneCutoff(now time.Time) time.Time {
+	return now.Add(-EnclaveKeyPruningGracePeriod)
+}
+
+// pruneExpiredEnclaveKeys deletes the enclave keys expired before the grace period.
+// The markers of the pruned keys are kept for another key lifetime, and the ones kept longer are deleted.
+// The number of the deleted entries is bounded to keep the gas cost of a registration constant.
+func (cs ClientState) pruneExpiredEnclaveKeys(ctx sdk.Context, clientStore storetypes.KVStore) {
+	pruned, err := newClientStore(clientStore, nil).PruneExpiredEnclaveKeys(enclaveKeyPruneCutoff(ctx.BlockTime()), MaxPrunedEnclaveKeysPerRegistration, time.Duration(cs.KeyExpiration)*time.Second)
+	if err != nil {
+		panic(err)
+	}
+	for _, p := range pruned {
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				EventTypePruneEnclaveKey,
+				sdk.NewAttribute(AttributeKeyEnclaveKey, p.EnclaveKey.Hex()),
+				sdk.NewAttribute(AttributeKeyExpiredAt, time.Unix(int64(p.ExpiredAt), 0).String()),
+			),
+		)
+	}
+}
+
 func (cs ClientState) updateOperators(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, message *UpdateOperatorsMessage) []exported.Height {
-	cs.Operators = message.NewOperators
-	cs.OperatorsThresholdNumerator = message.NewOperatorsThresholdNumerator
-	cs.OperatorsThresholdDenominator = message.NewOperatorsThresholdDenominator
-	cs.OperatorsNonce = message.Nonce
-	setClientState(clientStore, cdc, &cs)
+	store := newClientStore(clientStore, cdc)
+	clientState, err := store.IncrementNonce(message.Nonce)
+	if err != nil {
+		panic(err)
+	}
+	clientState.Operators = message.NewOperators
+	clientState.OperatorsThresholdNumerator = message.NewOperatorsThresholdNumerator
+	clientState.OperatorsThresholdDenominator = message.NewOperatorsThresholdDenominator
+	store.SetClientState(clientState)
 
 	newOperators, err := message.GetNewOperators()
 	if err != nil {
@@ -314,22 +451,55 @@ func (cs ClientState) updateOperators(ctx sdk.Context, cdc codec.BinaryCodec, cl
 	return nil
 }
 
+// updateClientParams replaces the parameters to verify the AVR of the enclave keys.
+// The enclave keys registered before the update are not affected:
+// the expiration of each key is fixed at its registration, and the new key expiration applies to the keys registered afterwards.
+func (cs ClientState) updateClientParams(ctx sdk.Context, cdc codec.BinaryCodec, clientStore storetypes.KVStore, message *UpdateClientParamsMessage) []exported.Height {
+	store := newClientStore(clientStore, cdc)
+	clientState, err := store.IncrementNonce(message.Nonce)
+	if err != nil {
+		panic(err)
+	}
+	clientState.AllowedQuoteStatuses = message.NewAllowedQuoteStatuses
+	clientState.AllowedAdvisoryIds = message.NewAllowedAdvisoryIds
+	clientState.KeyExpiration = message.NewKeyExpiration
+	store.SetClientState(clientState)
+
+	allowedQuoteStatusesJSON, err := json.Marshal(message.NewAllowedQuoteStatuses)
+	if err != nil {
+		panic(err)
+	}
+	allowedAdvisoryIDsJSON, err := json.Marshal(message.NewAllowedAdvisoryIds)
+	if err != nil {
+		panic(err)
+	}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			EventTypeUpdateClientParams,
+			sdk.NewAttribute(AttributeKeyNonce, fmt.Sprint(message.Nonce)),
+			sdk.NewAttribute(AttributeKeyAllowedQuoteStatuses, string(allowedQuoteStatusesJSON)),
+			sdk.NewAttribute(AttributeKeyAllowedAdvisoryIDs, string(allowedAdvisoryIDsJSON)),
+			sdk.NewAttribute(AttributeKeyKeyExpiration, fmt.Sprint(message.NewKeyExpiration)),
+		),
+	)
+	return nil
+}
+
 func (cs ClientState) Contains(clientStore storetypes.KVStore, ek common.Address) bool {
-	return clientStore.Has(enclaveKeyPath(ek))
+	return newClientStore(clientStore, nil).HasEnclaveKey(ek)
 }
 
 func (cs ClientState) GetEKInfo(clientStore storetypes.KVStore, ek common.Address) (*EKInfo, error) {
-	if !cs.Contains(clientStore, ek) {
-		return nil, nil
-	}
-	bz := clientStore.Get(enclaveKeyPath(ek))
-	if len(bz) != (8 + 20) {
-		return nil, fmt.Errorf("invalid enclave key info: expected=%v actual=%v", 8+20, len(bz))
+	return newClientStore(clientStore, nil).GetEnclaveKeyInfo(ek)
+}
+
+// IsActiveKey returns true if the enclave key is registered and not expired at `blockTime`
+func (cs ClientState) IsActiveKey(clientStore storetypes.KVStore, ek common.Address, blockTime time.Time) (bool, error) {
+	ekInfo, err := cs.GetEKInfo(clientStore, ek)
+	if err != nil || ekInfo == nil {
+		return false, err
 	}
-	return &EKInfo{
-		ExpiredAt: sdk.BigEndianToUint64(bz[:8]),
-		Operator:  common.BytesToAddress(bz[8:]),
-	}, nil
+	return !ekInfo.IsExpired(blockTime), nil
 }
 
 func (cs ClientState) ensureEKInfoMatch(clientStore storetypes.KVStore, ek common.Address, operator common.Address, expiredAt time.Time) error {
@@ -350,96 +520,29 @@ func (cs ClientState) ensureEKInfoMatch(clientStore storetypes.KVStore, ek commo
 }
 
 func (cs ClientState) SetEKInfo(clientStore storetypes.KVStore, ek, operator common.Address, expiredAt time.Time) error {
-	clientStore.Set(enclaveKeyPath(ek), append(sdk.Uint64ToBigEndian(uint64(expiredAt.Unix())), operator.Bytes()...))
+	newClientStore(clientStore, nil).SetEnclaveKeyInfo(ek, EKInfo{ExpiredAt: uint64(expiredAt.Unix()), Operator: operator})
 	return nil
 }
 
 func (cs ClientState) VerifySignatures(ctx sdk.Context, clientStore storetypes.KVStore, commitment [32]byte, signatures [][]byte) error {
-	operators := cs.GetOperators()
-	sigNum := len(signatures)
-	opNum := len(operators)
-	if opNum == 0 {
-		if sigNum != 1 {
-			return fmt.Errorf("invalid signature length: expected=%v actual=%v", 1, sigNum)
-		}
-		ek, err := RecoverAddress(commitment, signatures[0])
-		if err != nil {
-			return err
-		}
-		ekInfo, err := cs.GetEKInfo(clientStore, ek)
-		if err != nil {
-			return err
-		} else if ekInfo == nil {
-			return fmt.Errorf("enclave key '%v' not found", ek)
-		} else if ekInfo.IsExpired(ctx.BlockTime()) {
-			return fmt.Errorf("enclave key '%v' is expired", ek)
-		}
-		return nil
-	} else if opNum != sigNum {
-		return fmt.Errorf("invalid signature length: expected=%v actual=%v", opNum, sigNum)
-	}
-
-	var success uint64 = 0
-	for i, op := range operators {
-		if len(signatures[i]) == 0 {
-			continue
-		}
-		ek, err := RecoverAddress(commitment, signatures[i])
-		if err != nil {
-			return err
-		}
-		ekInfo, err := cs.GetEKInfo(clientStore, ek)
-		if err != nil {
-			return err
-		} else if ekInfo == nil {
-			return fmt.Errorf("enclave key '%v' not found", ek)
-		} else if ekInfo.IsExpired(ctx.BlockTime()) {
-			return fmt.Errorf("enclave key '%v' is expired", ek)
-		} else if !ekInfo.IsMatchOperator(op) {
-			return fmt.Errorf("enclave key '%v' operator mismatch: expected=%v actual=%v", ek, op, ekInfo.Operator)
-		}
-		success++
-	}
-
-	if success*cs.OperatorsThresholdDenominator < cs.OperatorsThresholdDenominator*uint64(opNum) {
-		return fmt.Errorf("insufficient signatures: expected=%v actual=%v", cs.OperatorsThresholdDenominator, success)
-	}
-
-	return nil
+	return VerifyEnclaveKeySignatures(cs, func(ek common.Address) (*EKInfo, error) {
+		return cs.GetEKInfo(clientStore, ek)
+	}, ctx.BlockTime(), commitment, signatures)
 }
 
-func (cs ClientState) GetOperators() []common.Address {
-	var operators []common.Address
-	for _, op := range cs.Operators {
-		operators = append(operators, common.BytesToAddress(op))
-	}
-	return operators
+// KeyExpiredAt returns the expiration that the client records on the registration of an enclave key attested at `attestationTime`
+func (cs ClientState) KeyExpiredAt(attestationTime time.Time) time.Time {
+	return keyExpiredAt(cs.KeyExpiration, attestationTime)
 }
 
-func (cs ClientState) getKeyExpiration() time.Duration {
-	return time.Duration(cs.KeyExpiration) * time.Second
+func keyExpiredAt(keyExpiration uint64, attestationTime time.Time) time.Time {
+	return attestationTime.Add(time.Duration(keyExpiration) * time.Second)
 }
 
 func (cs ClientState) isAllowedStatus(status string) bool {
-	if status == QuoteOK {
-		return true
-	}
-	for _, s := range cs.AllowedQuoteStatuses {
-		if status == s {
-			return true
-		}
-	}
-	return false
+	return IsAllowedQuoteStatus(cs.AllowedQuoteStatuses, status)
 }
 
 func (cs ClientState) isAllowedAdvisoryIDs(advIDs []string) bool {
-	if len(advIDs) == 0 {
-		return true
-	}
-	set := mapset.NewThreadUnsafeSet(cs.AllowedAdvisoryIds...)
-	return set.Contains(advIDs...)
-}
-
-func enclaveKeyPath(key common.Address) []byte {
-	return []byte("aux/enclave_keys/" + key.Hex())
+	return AreAllowedAdvisoryIDs(cs.AllowedAdvisoryIds, advIDs)
 }
@@ -0,0 +1,482 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: ibc/lightclients/lcp/v1/update_client_params.proto
+
+package types
+
+import (
+	fmt "fmt"
+	_ "github.com/cosmos/gogoproto/gogoproto"
+	proto "github.com/cosmos/gogoproto/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+// UpdateClientParamsMessage replaces the parameters to verify the AVR of the enclave keys.
+// The message must be signed by the operators with the next operators nonce.
+// The new parameters are applied to the enclave keys registered after the update,
+// and the enclave keys registered before the update are kept valid until their original expiration.
+type UpdateClientParamsMessage struct {
+	Nonce                   uint64   `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	NewAllowedQuoteStatuses []string `protobuf:"bytes,2,rep,name=new_allowed_quote_statuses,json=newAllowedQuoteStatuses,proto3" json:"new_allowed_quote_statuses,omitempty"`
+	NewAllowedAdvisoryIds   []string `protobuf:"bytes,3,rep,name=new_allowed_advisory_ids,json=newAllowedAdvisoryIds,proto3" json:"new_allowed_advisory_ids,omitempty"`
+	// the key expiration in seconds for the enclave keys registered after the update
+	NewKeyExpiration uint64   `protobuf:"varint,4,opt,name=new_key_expiration,json=newKeyExpiration,proto3" json:"new_key_expiration,omitempty"`
+	Signatures       [][]byte `protobuf:"bytes,5,rep,name=signatures,proto3" json:"signatures,omitempty"`
+}
+
+func (m *UpdateClientParamsMessage) Reset()         { *m = UpdateClientParamsMessage{} }
+func (m *UpdateClientParamsMessage) String() string { return proto.CompactTextString(m) }
+func (*UpdateClientParamsMessage) ProtoMessage()    {}
+func (*UpdateClientParamsMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4991e31cd8032bdc, []int{0}
+}
+func (m *UpdateClientParamsMessage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UpdateClientParamsMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UpdateClientParamsMessage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UpdateClientParamsMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateClientParamsMessage.Merge(m, src)
+}
+func (m *UpdateClientParamsMessage) XXX_Size() int {
+	return m.Size()
+}
+func (m *UpdateClientParamsMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateClientParamsMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateClientParamsMessage proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*UpdateClientParamsMessage)(nil), "ibc.lightclients.lcp.v1.UpdateClientParamsMessage")
+}
+
+func init() {
+	proto.RegisterFile("ibc/lightclients/lcp/v1/update_client_params.proto", fileDescriptor_4991e31cd8032bdc)
+}
+
+var fileDescriptor_4991e31cd8032bdc = []byte{
+	// 335 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x91, 0xb1, 0x4e, 0xf3, 0x30,
+	0x14, 0x85, 0x93, 0xbf, 0xed, 0x2f, 0x61, 0x31, 0xa0, 0xa8, 0xa8, 0xa1, 0x83, 0x55, 0x31, 0x75,
+	0xa0, 0x89, 0x0a, 0x12, 0x0c, 0x4c, 0x05, 0x31, 0x20, 0x84, 0x04, 0x05, 0x16, 0x96, 0xc8, 0x71,
+	0xae, 0x52, 0x0b, 0xd7, 0x36, 0xb1, 0xd3, 0x90, 0xb7, 0xe0, 0xb1, 0x3a, 0x76, 0x64, 0x84, 0xf6,
+	0x19, 0xd8, 0x51, 0x9c, 0x0a, 0xb2, 0xd9, 0xe7, 0x9c, 0xef, 0xea, 0xea, 0x1e, 0x74, 0xcc, 0x62,
+	0x1a, 0x72, 0x96, 0xce, 0x0c, 0xe5, 0x0c, 0x84, 0xd1, 0x21, 0xa7, 0x2a, 0x5c, 0x8c, 0xc3, 0x5c,
+	0x25, 0xc4, 0x40, 0x54, 0xab, 0x91, 0x22, 0x19, 0x99, 0xeb, 0x40, 0x65, 0xd2, 0x48, 0xaf, 0xc7,
+	0x62, 0x1a, 0x34, 0x99, 0x80, 0x53, 0x15, 0x2c, 0xc6, 0xfd, 0x6e, 0x2a, 0x53, 0x69, 0x33, 0x61,
+	0xf5, 0xaa, 0xe3, 0x87, 0xdf, 0x2e, 0x3a, 0x78, 0xb2, 0xd3, 0x2e, 0x6d, 0xfc, 0xce, 0xce, 0xba,
+	0x05, 0xad, 0x49, 0x0a, 0x5e, 0x17, 0x75, 0x84, 0x14, 0x14, 0x7c, 0x77, 0xe0, 0x0e, 0xdb, 0xd3,
+	0xfa, 0xe3, 0x9d, 0xa3, 0xbe, 0x80, 0x22, 0x22, 0x9c, 0xcb, 0x02, 0x92, 0xe8, 0x35, 0x97, 0x06,
+	0x22, 0x6d, 0x88, 0xc9, 0x35, 0x68, 0xff, 0xdf, 0xa0, 0x35, 0xdc, 0x99, 0xf6, 0x04, 0x14, 0x93,
+	0x3a, 0x70, 0x5f, 0xf9, 0x0f, 0x5b, 0xdb, 0x3b, 0x43, 0x7e, 0x13, 0x26, 0xc9, 0x82, 0x69, 0x99,
+	0x95, 0x11, 0x4b, 0xb4, 0xdf, 0xb2, 0xe8, 0xfe, 0x1f, 0x3a, 0xd9, 0xba, 0xd7, 0x89, 0xf6, 0x8e,
+	0x90, 0x57, 0x81, 0x2f, 0x50, 0x46, 0xf0, 0xa6, 0x58, 0x46, 0x0c, 0x93, 0xc2, 0x6f, 0xdb, 0xc5,
+	0xf6, 0x04, 0x14, 0x37, 0x50, 0x5e, 0xfd, 0xea, 0x1e, 0x46, 0x48, 0xb3, 0x54, 0x10, 0x93, 0x67,
+	0xa0, 0xfd, 0xce, 0xa0, 0x35, 0xdc, 0x9d, 0x36, 0x94, 0x8b, 0xc7, 0xe5, 0x17, 0x76, 0x96, 0x6b,
+	0xec, 0xae, 0xd6, 0xd8, 0xfd, 0x5c, 0x63, 0xf7, 0x7d, 0x83, 0x9d, 0xd5, 0x06, 0x3b, 0x1f, 0x1b,
+	0xec, 0x3c, 0x9f, 0xa6, 0xcc, 0xcc, 0xf2, 0x38, 0xa0, 0x72, 0x1e, 0x26, 0xc4, 0x10, 0x3a, 0x23,
+	0x4c, 0x70, 0x12, 0x57, 0xf7, 0x1f, 0xa5, 0xb2, 0xee, 0x64, 0xd4, 0x2c, 0xc5, 0x94, 0x0a, 0x74,
+	0xfc, 0xdf, 0x1e, 0xf5, 0xe4, 0x67, 0x00, 0x39, 0x8a, 0xe2, 0x1f, 0xb9, 0x01, 0x00, 0x00,
+}
+
+func (m *UpdateClientParamsMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateClientParamsMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateClientParamsMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Signatures) > 0 {
+		for iNdEx := len(m.Signatures) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Signatures[iNdEx])
+			copy(dAtA[i:], m.Signatures[iNdEx])
+			i = encodeVarintUpdateClientParams(dAtA, i, uint64(len(m.Signatures[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.NewKeyExpiration != 0 {
+		i = encodeVarintUpdateClientParams(dAtA, i, uint64(m.NewKeyExpiration))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.NewAllowedAdvisoryIds) > 0 {
+		for iNdEx := len(m.NewAllowedAdvisoryIds) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.NewAllowedAdvisoryIds[iNdEx])
+			copy(dAtA[i:], m.NewAllowedAdvisoryIds[iNdEx])
+			i = encodeVarintUpdateClientParams(dAtA, i, uint64(len(m.NewAllowedAdvisoryIds[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.NewAllowedQuoteStatuses) > 0 {
+		for iNdEx := len(m.NewAllowedQuoteStatuses) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.NewAllowedQuoteStatuses[iNdEx])
+			copy(dAtA[i:], m.NewAllowedQuoteStatuses[iNdEx])
+			i = encodeVarintUpdateClientParams(dAtA, i, uint64(len(m.NewAllowedQuoteStatuses[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Nonce != 0 {
+		i = encodeVarintUpdateClientParams(dAtA, i, uint64(m.Nonce))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintUpdateClientParams(dAtA []byte, offset int, v uint64) int {
+	offset -= sovUpdateClientParams(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *UpdateClientParamsMessage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Nonce != 0 {
+		n += 1 + sovUpdateClientParams(uint64(m.Nonce))
+	}
+	if len(m.NewAllowedQuoteStatuses) > 0 {
+		for _, s := range m.NewAllowedQuoteStatuses {
+			l = len(s)
+			n += 1 + l + sovUpdateClientParams(uint64(l))
+		}
+	}
+	if len(m.NewAllowedAdvisoryIds) > 0 {
+		for _, s := range m.NewAllowedAdvisoryIds {
+			l = len(s)
+			n += 1 + l + sovUpdateClientParams(uint64(l))
+		}
+	}
+	if m.NewKeyExpiration != 0 {
+		n += 1 + sovUpdateClientParams(uint64(m.NewKeyExpiration))
+	}
+	if len(m.Signatures) > 0 {
+		for _, b := range m.Signatures {
+			l = len(b)
+			n += 1 + l + sovUpdateClientParams(uint64(l))
+		}
+	}
+	return n
+}
+
+func sovUpdateClientParams(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozUpdateClientParams(x uint64) (n int) {
+	return sovUpdateClientParams(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *UpdateClientParamsMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowUpdateClientParams
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateClientParamsMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateClientParamsMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
+			}
+			m.Nonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpdateClientParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Nonce |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewAllowedQuoteStatuses", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpdateClientParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUpdateClientParams
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpdateClientParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NewAllowedQuoteStatuses = append(m.NewAllowedQuoteStatuses, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewAllowedAdvisoryIds", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpdateClientParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthUpdateClientParams
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpdateClientParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NewAllowedAdvisoryIds = append(m.NewAllowedAdvisoryIds, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewKeyExpiration", wireType)
+			}
+			m.NewKeyExpiration = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpdateClientParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NewKeyExpiration |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signatures", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowUpdateClientParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthUpdateClientParams
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthUpdateClientParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signatures = append(m.Signatures, make([]byte, postIndex-iNdEx))
+			copy(m.Signatures[len(m.Signatures)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipUpdateClientParams(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthUpdateClientParams
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipUpdateClientParams(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowUpdateClientParams
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowUpdateClientParams
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowUpdateClientParams
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthUpdateClientParams
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupUpdateClientParams
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthUpdateClientParams
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthUpdateClientParams        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowUpdateClientParams          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupUpdateClientParams = fmt.Errorf("proto: unexpected end of group")
+)
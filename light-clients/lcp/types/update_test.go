@@ -0,0 +1,806 @@
+package types_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/light-clients/lcp/testutil"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/sgx/dcap/dcaptest"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterEnclaveKey(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	fixture := testutil.LoadRegisterEnclaveKeyFixture(t, "001-avr")
+	h := testutil.NewHarness(t)
+	h.SetClientState(fixture.ClientState)
+	h.SetBlockTime(fixture.AttestationTime)
+
+	require.Nil(t, h.EnclaveKeyInfo(fixture.EnclaveKey))
+	require.NoError(t, h.Update(fixture.Message))
+	info := h.EnclaveKeyInfo(fixture.EnclaveKey)
+	require.NotNil(t, info)
+	require.Equal(t, uint64(fixture.AttestationTime.Add(time.Hour).Unix()), info.ExpiredAt)
+	require.Equal(t, common.Address{}, info.Operator)
+}
+
+func TestRegisterDCAPEnclaveKey(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	issueDate := testutil.DefaultBlockTime.Add(-time.Hour).UTC()
+	mrenclave := [32]byte{0x01}
+	f := dcaptest.NewFixture(t, dcaptest.Params{Mrenclave: mrenclave, EnclaveKey: ek, IssueDate: issueDate})
+	msg := testutil.NewDCAPRegisterEnclaveKeyMessage(f)
+	prev, post := clienttypes.NewHeight(0, 1), clienttypes.NewHeight(0, 2)
+	cs := &lcptypes.ClientState{
+		Mrenclave:     mrenclave[:],
+		LatestHeight:  prev,
+		KeyExpiration: 3600 * 2,
+	}
+
+	// the DCAP attestations are disabled
+	h := testutil.NewHarness(t)
+	h.Initialize(cs, testutil.NewConsensusState(prev, testutil.DefaultBlockTime))
+	require.ErrorContains(t, h.Update(msg), "DCAP attestations are disabled")
+
+	cs.DcapRootCerts = [][]byte{f.RootCert}
+	h = testutil.NewHarness(t)
+	h.Initialize(cs, testutil.NewConsensusState(prev, testutil.DefaultBlockTime))
+	require.NoError(t, h.Update(msg))
+	info := h.EnclaveKeyInfo(ek)
+	require.NotNil(t, info)
+	require.Equal(t, uint64(issueDate.Add(2*time.Hour).Unix()), info.ExpiredAt)
+	require.Equal(t, common.Address{}, info.Operator)
+	// the registration is idempotent
+	require.NoError(t, h.Update(msg))
+
+	// the key signs the updates
+	require.NoError(t, h.Update(testutil.NewUpdateClientMessage(t, prev, post, h.Ctx.BlockTime(), key)))
+	require.Equal(t, post, h.ClientState().LatestHeight)
+}
+
+func TestRegisterEnclaveKeyPrunesExpiredKeys(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	issueDate := testutil.DefaultBlockTime.Add(-time.Hour).UTC()
+	mrenclave := [32]byte{0x01}
+	f := dcaptest.NewFixture(t, dcaptest.Params{Mrenclave: mrenclave, EnclaveKey: ek, IssueDate: issueDate})
+	prev := clienttypes.NewHeight(0, 1)
+	h := testutil.NewHarness(t)
+	h.Initialize(&lcptypes.ClientState{
+		Mrenclave:     mrenclave[:],
+		LatestHeight:  prev,
+		KeyExpiration: 3600 * 2,
+		DcapRootCerts: [][]byte{f.RootCert},
+	}, testutil.NewConsensusState(prev, testutil.DefaultBlockTime))
+
+	// the keys expired before the grace period are pruned up to the limit
+	var expired []common.Address
+	for i := 0; i < lcptypes.MaxPrunedEnclaveKeysPerRegistration+2; i++ {
+		k := common.BigToAddress(big.NewInt(int64(i + 1)))
+		h.SetEnclaveKey(k, h.Ctx.BlockTime().Add(-lcptypes.EnclaveKeyPruningGracePeriod-time.Duration(len(expired)+1)*time.Minute), common.Address{})
+		expired = append(expired, k)
+	}
+	// a key expired within the grace period is kept
+	recent := common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff")
+	h.SetEnclaveKey(recent, h.Ctx.BlockTime().Add(-time.Minute), common.Address{})
+
+	require.NoError(t, h.Update(testutil.NewDCAPRegisterEnclaveKeyMessage(f)))
+	var pruned int
+	for _, event := range h.Ctx.EventManager().Events() {
+		if event.Type == lcptypes.EventTypePruneEnclaveKey {
+			pruned++
+		}
+	}
+	require.Equal(t, lcptypes.MaxPrunedEnclaveKeysPerRegistration, pruned)
+	// the keys expired earliest are pruned first
+	for i, k := range expired {
+		require.Equal(t, i < 2, h.EnclaveKeyInfo(k) != nil, "index=%v", i)
+	}
+	require.NotNil(t, h.EnclaveKeyInfo(recent))
+	active, err := h.ClientState().IsActiveKey(h.Store, ek, h.Ctx.BlockTime())
+	require.NoError(t, err)
+	require.True(t, active)
+}
+
+func TestRegisterPrunedEnclaveKey(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	mrenclave := [32]byte{0x01}
+	issueDate := testutil.DefaultBlockTime.Add(-time.Hour).UTC()
+	// the key is pruned at `prunedAt` by the registration of another key
+	prunedAt := testutil.DefaultBlockTime.Add(2*time.Hour + lcptypes.EnclaveKeyPruningGracePeriod + time.Minute)
+	f := dcaptest.NewFixture(t, dcaptest.Params{Mrenclave: mrenclave, EnclaveKey: ek, IssueDate: issueDate})
+	other := dcaptest.NewFixture(t, dcaptest.Params{Mrenclave: mrenclave, EnclaveKey: common.HexToAddress("0x01"), IssueDate: prunedAt.Add(-time.Hour).UTC()})
+	// the same quote with the collateral issued after the pruning
+	fresh := dcaptest.NewFixture(t, dcaptest.Params{Mrenclave: mrenclave, EnclaveKey: ek, IssueDate: prunedAt.Add(-time.Hour).UTC()})
+	// another key attested so long ago that it would expire before the prune cutoff
+	stale := dcaptest.NewFixture(t, dcaptest.Params{Mrenclave: mrenclave, EnclaveKey: common.HexToAddress("0x02"), IssueDate: prunedAt.Add(-lcptypes.EnclaveKeyPruningGracePeriod - 3*time.Hour).UTC()})
+	prev := clienttypes.NewHeight(0, 1)
+	h := testutil.NewHarness(t)
+	h.Initialize(&lcptypes.ClientState{
+		Mrenclave:     mrenclave[:],
+		LatestHeight:  prev,
+		KeyExpiration: 3600 * 2,
+		DcapRootCerts: [][]byte{f.RootCert, other.RootCert, fresh.RootCert, stale.RootCert},
+	}, testutil.NewConsensusState(prev, testutil.DefaultBlockTime))
+
+	require.NoError(t, h.Update(testutil.NewDCAPRegisterEnclaveKeyMessage(f)))
+	require.NotNil(t, h.EnclaveKeyInfo(ek))
+
+	h.SetBlockTime(prunedAt)
+	// the expired key is not registered again until it is pruned
+	require.ErrorContains(t, h.VerifyClientMessage(testutil.NewDCAPRegisterEnclaveKeyMessage(fresh)), "invalid enclave key info")
+	require.NoError(t, h.Update(testutil.NewDCAPRegisterEnclaveKeyMessage(other)))
+	require.Nil(t, h.EnclaveKeyInfo(ek))
+
+	// the pruned key is not registered again while its marker remains
+	err := h.VerifyClientMessage(testutil.NewDCAPRegisterEnclaveKeyMessage(fresh))
+	require.ErrorContains(t, err, "the enclave key has been expired and pruned")
+	require.Panics(t, func() { h.UpdateState(testutil.NewDCAPRegisterEnclaveKeyMessage(fresh)) })
+	require.Nil(t, h.EnclaveKeyInfo(ek))
+
+	// a key whose attested lifetime ends before the prune cutoff is rejected without any marker
+	err = h.VerifyClientMessage(testutil.NewDCAPRegisterEnclaveKeyMessage(stale))
+	require.ErrorContains(t, err, "the enclave key expires before the prune cutoff")
+	require.Panics(t, func() { h.UpdateState(testutil.NewDCAPRegisterEnclaveKeyMessage(stale)) })
+}
+
+func TestVerifyUpdateClient(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	prev, post := clienttypes.NewHeight(0, 1), clienttypes.NewHeight(0, 2)
+
+	h := testutil.NewHarness(t)
+	h.Initialize(&lcptypes.ClientState{LatestHeight: prev, KeyExpiration: 3600}, testutil.NewConsensusState(prev, testutil.DefaultBlockTime))
+	msg := testutil.NewUpdateClientMessage(t, prev, post, h.Ctx.BlockTime(), key)
+
+	// the key is not registered
+	require.ErrorContains(t, h.Update(msg), "not found")
+
+	h.SetEnclaveKey(ek, h.Ctx.BlockTime().Add(time.Hour), common.Address{})
+	require.NoError(t, h.Update(msg))
+	require.Equal(t, post, h.ClientState().LatestHeight)
+	require.Equal(t, testutil.NewConsensusState(post, h.Ctx.BlockTime()), h.ConsensusState(post))
+
+	// the key is expired
+	h.AdvanceBlockTime(2 * time.Hour)
+	require.ErrorContains(t, h.VerifyClientMessage(testutil.NewUpdateClientMessage(t, post, clienttypes.NewHeight(0, 3), h.Ctx.BlockTime(), key)), "expired")
+}
+
+func TestVerifyUpdateClientStateIDs(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	zero, prev, post := clienttypes.ZeroHeight(), clienttypes.NewHeight(0, 1), clienttypes.NewHeight(0, 2)
+	newHarness := func(latestHeight clienttypes.Height, consensusState *lcptypes.ConsensusState) *testutil.Harness {
+		h := testutil.NewHarness(t)
+		h.Initialize(&lcptypes.ClientState{LatestHeight: latestHeight, KeyExpiration: 3600}, consensusState)
+		h.SetEnclaveKey(ek, h.Ctx.BlockTime().Add(time.Hour), common.Address{})
+		return h
+	}
+	validPrevStateID := testutil.StateIDAt(prev)
+
+	var cases = []struct {
+		name        string
+		latest      clienttypes.Height
+		prevState   *lcptypes.ConsensusState
+		prev        clienttypes.Height
+		prevStateID lcptypes.StateID
+		postStateID lcptypes.StateID
+		// expected substring of the error. if empty, the update is valid
+		err string
+	}{
+		{"valid", prev, testutil.NewConsensusState(prev, testutil.DefaultBlockTime), prev, validPrevStateID, testutil.StateIDAt(post), ""},
+		{"zero post state ID", prev, testutil.NewConsensusState(prev, testutil.DefaultBlockTime), prev, validPrevStateID, lcptypes.StateID{}, "`PostStateID` must be non-zero"},
+		{"zero post state ID on the first update", zero, &lcptypes.ConsensusState{}, zero, lcptypes.StateID{}, lcptypes.StateID{}, "`PostStateID` must be non-zero"},
+		// the zero state ID is decoded as the absent one
+		{"zero prev state ID", prev, testutil.NewConsensusState(prev, testutil.DefaultBlockTime), prev, lcptypes.StateID{}, testutil.StateIDAt(post), "must be non-nil"},
+		{"truncated stored state ID", prev, &lcptypes.ConsensusState{StateId: validPrevStateID[:31], Timestamp: 1}, prev, validPrevStateID, testutil.StateIDAt(post), "state ID must be 32 bytes: actual=31"},
+		{"zero stored consensus state", prev, &lcptypes.ConsensusState{}, prev, validPrevStateID, testutil.StateIDAt(post), "state ID must be 32 bytes: actual=0"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := newHarness(c.latest, c.prevState)
+			err := h.VerifyClientMessage(testutil.NewUpdateClientMessageWithStateIDs(t, c.prev, c.prevStateID, post, c.postStateID, h.Ctx.BlockTime(), key))
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestConsensusStateValidateBasic(t *testing.T) {
+	stateID := testutil.StateIDAt(clienttypes.NewHeight(0, 1))
+	require.NoError(t, lcptypes.ConsensusState{}.ValidateBasic())
+	require.NoError(t, lcptypes.ConsensusState{StateId: stateID[:], Timestamp: 1}.ValidateBasic())
+	require.ErrorContains(t, lcptypes.ConsensusState{StateId: make([]byte, 32), Timestamp: 1}.ValidateBasic(), "must be non-zero")
+	require.ErrorContains(t, lcptypes.ConsensusState{StateId: stateID[:31], Timestamp: 1}.ValidateBasic(), "must be 32 bytes")
+	// the zero consensus state has no timestamp either
+	require.ErrorContains(t, lcptypes.ConsensusState{Timestamp: 1}.ValidateBasic(), "must be 32 bytes")
+
+	// only the zero consensus state is accepted on the creation of the client
+	cs := lcptypes.ClientState{KeyExpiration: 3600, Mrenclave: make([]byte, lcptypes.MrenclaveSize)}
+	h := testutil.NewHarness(t)
+	require.NoError(t, cs.Initialize(h.Ctx, h.Cdc, h.Store, &lcptypes.ConsensusState{}))
+	for _, consState := range []*lcptypes.ConsensusState{
+		{StateId: stateID[:], Timestamp: 1},
+		{StateId: stateID[:31]},
+		{StateId: make([]byte, 32)},
+	} {
+		h := testutil.NewHarness(t)
+		require.ErrorContains(t, cs.Initialize(h.Ctx, h.Cdc, h.Store, consState), "must be the zero consensus state")
+	}
+}
+
+func TestUpdateClientMessageSize(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	valid := testutil.NewUpdateClientMessage(t, clienttypes.NewHeight(0, 1), clienttypes.NewHeight(0, 2), testutil.DefaultBlockTime, key)
+	require.NoError(t, valid.ValidateBasic())
+
+	// the message at the maximum is decoded, and the trailing bytes are ignored by the ABI decoder
+	atMax := &lcptypes.UpdateClientMessage{ProxyMessage: make([]byte, lcptypes.MaxProxyMessageSize), Signatures: valid.Signatures}
+	copy(atMax.ProxyMessage, valid.ProxyMessage)
+	require.NoError(t, atMax.ValidateBasic())
+
+	overMax := &lcptypes.UpdateClientMessage{ProxyMessage: make([]byte, lcptypes.MaxProxyMessageSize+1), Signatures: valid.Signatures}
+	copy(overMax.ProxyMessage, valid.ProxyMessage)
+	require.ErrorContains(t, overMax.ValidateBasic(), "proxy message is too large")
+
+	h := testutil.NewHarness(t)
+	h.SetClientState(&lcptypes.ClientState{KeyExpiration: 3600})
+	require.ErrorContains(t, h.VerifyClientMessage(overMax), "proxy message is too large")
+}
+
+func TestRegisterEnclaveKeyMessageSize(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	fixture := testutil.LoadRegisterEnclaveKeyFixture(t, "001-avr")
+	valid := fixture.Message
+	require.NoError(t, valid.ValidateBasic())
+	withField := func(f func(m *lcptypes.RegisterEnclaveKeyMessage)) *lcptypes.RegisterEnclaveKeyMessage {
+		m := *valid
+		f(&m)
+		return &m
+	}
+	var cases = []struct {
+		name string
+		msg  *lcptypes.RegisterEnclaveKeyMessage
+		err  string
+	}{
+		{"empty report", withField(func(m *lcptypes.RegisterEnclaveKeyMessage) { m.Report = nil }), "report cannot be empty"},
+		{"large report", withField(func(m *lcptypes.RegisterEnclaveKeyMessage) { m.Report = make([]byte, lcptypes.MaxAVRReportSize+1) }), "report is too large"},
+		{"large signature", withField(func(m *lcptypes.RegisterEnclaveKeyMessage) {
+			m.Signature = make([]byte, lcptypes.MaxAVRSignatureSize+1)
+		}), "signature is too large"},
+		{"empty signing certificate", withField(func(m *lcptypes.RegisterEnclaveKeyMessage) { m.SigningCert = nil }), "signing certificate cannot be empty"},
+		{"large signing certificate", withField(func(m *lcptypes.RegisterEnclaveKeyMessage) {
+			m.SigningCert = make([]byte, lcptypes.MaxAVRSigningCertSize+1)
+		}), "signing certificate is too large"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.ErrorContains(t, c.msg.ValidateBasic(), c.err)
+			h := testutil.NewHarness(t)
+			h.SetClientState(fixture.ClientState)
+			h.SetBlockTime(fixture.AttestationTime)
+			require.ErrorContains(t, h.VerifyClientMessage(c.msg), c.err)
+		})
+	}
+
+	// an oversized report is rejected without reading it, so the cost does not depend on its size
+	allocs := func(size int) float64 {
+		m := withField(func(m *lcptypes.RegisterEnclaveKeyMessage) { m.Report = make([]byte, size) })
+		return testing.AllocsPerRun(10, func() { _ = m.ValidateBasic() })
+	}
+	require.Equal(t, allocs(lcptypes.MaxAVRReportSize+1), allocs(64<<20))
+}
+
+// FuzzRegisterEnclaveKey verifies the registrations with arbitrary AVRs and certificates as the 02-client module does.
+// The fuzzer cannot forge the signature of IAS, so the AVR is also decoded as the verification does after the signature is verified.
+func FuzzRegisterEnclaveKey(f *testing.F) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	fixture := testutil.LoadRegisterEnclaveKeyFixture(f, "001-avr")
+	msg := fixture.Message
+	f.Add(msg.Report, msg.Signature, msg.SigningCert)
+	f.Add([]byte(`{"advisoryIDs":[`+strings.Repeat(`"INTEL-SA-00000",`, 1024)+`"INTEL-SA-00000"]}`), msg.Signature, msg.SigningCert)
+	f.Add(msg.Report, msg.Signature, append(append([]byte{}, msg.SigningCert...), msg.SigningCert...))
+	f.Add([]byte(`{}`), []byte{0x00}, []byte{0x30, 0x82, 0xff, 0xff})
+	f.Fuzz(func(t *testing.T, report, signature, signingCert []byte) {
+		h := testutil.NewHarness(t)
+		h.SetClientState(fixture.ClientState)
+		h.SetBlockTime(fixture.AttestationTime)
+		m := &lcptypes.RegisterEnclaveKeyMessage{Report: report, Signature: signature, SigningCert: signingCert}
+		err := h.Update(m)
+		if len(report) > lcptypes.MaxAVRReportSize || len(signature) > lcptypes.MaxAVRSignatureSize || len(signingCert) > lcptypes.MaxAVRSigningCertSize {
+			require.ErrorContains(t, err, "too large")
+		}
+
+		avr, err := ias.ParseAndValidateAVR(report)
+		if err != nil {
+			return
+		}
+		avr.GetTimestamp()
+		if quote, err := avr.Quote(); err == nil {
+			_, _, _ = ias.GetEKAndOperator(quote)
+		}
+	})
+}
+
+func TestUpdateStateRecordsConsensusSigner(t *testing.T) {
+	h := testutil.NewHarness(t)
+	h.SetClientState(&lcptypes.ClientState{})
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < 3; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+
+	var cases = []struct {
+		height clienttypes.Height
+		keys   []*ecdsa.PrivateKey
+		signer *ecdsa.PrivateKey
+	}{
+		{clienttypes.NewHeight(0, 1), []*ecdsa.PrivateKey{keys[0]}, keys[0]},
+		{clienttypes.NewHeight(0, 2), []*ecdsa.PrivateKey{keys[0]}, keys[0]},
+		// the key is rotated
+		{clienttypes.NewHeight(0, 3), []*ecdsa.PrivateKey{keys[1]}, keys[1]},
+		{clienttypes.NewHeight(0, 4), []*ecdsa.PrivateKey{keys[2]}, keys[2]},
+		// the first operator did not sign
+		{clienttypes.NewHeight(0, 5), []*ecdsa.PrivateKey{nil, keys[1], keys[2]}, keys[1]},
+	}
+	prev := clienttypes.Height{}
+	for _, c := range cases {
+		h.UpdateState(testutil.NewUpdateClientMessage(t, prev, c.height, h.Ctx.BlockTime(), c.keys...))
+		prev = c.height
+	}
+
+	for _, c := range cases {
+		signer, found := lcptypes.GetConsensusSigner(h.Store, c.height)
+		require.True(t, found, "height=%v", c.height)
+		require.Equal(t, crypto.PubkeyToAddress(c.signer.PublicKey), signer, "height=%v", c.height)
+		// only the address is stored in addition to the consensus state
+		require.Len(t, h.Store.Get(lcptypes.ConsensusSignerKey(c.height)), common.AddressLength)
+	}
+	_, found := lcptypes.GetConsensusSigner(h.Store, clienttypes.NewHeight(0, 6))
+	require.False(t, found)
+	_, found = lcptypes.GetConsensusSigner(testutil.NewHarness(t).Store, clienttypes.NewHeight(0, 1))
+	require.False(t, found)
+}
+
+func TestUpdateGapTracking(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	h := testutil.NewHarness(t)
+	cs := lcptypes.ClientState{KeyExpiration: 3600, Mrenclave: make([]byte, lcptypes.MrenclaveSize), MaxUpdateGap: 3600}
+	require.NoError(t, cs.Initialize(h.Ctx, h.Cdc, h.Store, &lcptypes.ConsensusState{}))
+	lastUpdateTime, found := lcptypes.GetLastUpdateTime(h.Store)
+	require.True(t, found)
+	require.True(t, h.Ctx.BlockTime().Equal(lastUpdateTime))
+
+	gapExceededEvents := func() []sdk.Event {
+		var events []sdk.Event
+		for _, e := range h.Ctx.EventManager().Events() {
+			if e.Type == lcptypes.EventTypeUpdateGapExceeded {
+				events = append(events, e)
+			}
+		}
+		return events
+	}
+
+	var cases = []struct {
+		name string
+		// the block time elapsed since the previous update
+		elapsed time.Duration
+		// the status just before the update
+		status   exported.Status
+		exceeded bool
+	}{
+		{"first update", 30 * time.Minute, exported.Active, false},
+		{"within the gap", 59 * time.Minute, exported.Active, false},
+		{"exactly the gap", time.Hour, exported.Active, false},
+		{"exceeded", 2 * time.Hour, lcptypes.Stale, true},
+		{"recovered", 10 * time.Minute, exported.Active, false},
+		{"exceeded again", time.Hour + time.Second, lcptypes.Stale, true},
+	}
+	prev := clienttypes.Height{}
+	var exceeded int
+	for i, c := range cases {
+		h.AdvanceBlockTime(c.elapsed)
+		require.Equal(t, c.status, h.ClientState().StatusWithStaleness(h.Ctx, h.Store, h.Cdc), c.name)
+		// the stale condition never blocks the updates
+		require.Equal(t, exported.Active, h.ClientState().Status(h.Ctx, h.Store, h.Cdc), c.name)
+
+		post := clienttypes.NewHeight(0, uint64(i+1))
+		h.UpdateState(testutil.NewUpdateClientMessage(t, prev, post, h.Ctx.BlockTime(), key))
+		prev = post
+
+		lastUpdateTime, found := lcptypes.GetLastUpdateTime(h.Store)
+		require.True(t, found, c.name)
+		require.True(t, h.Ctx.BlockTime().Equal(lastUpdateTime), c.name)
+		require.Equal(t, exported.Active, h.ClientState().StatusWithStaleness(h.Ctx, h.Store, h.Cdc), c.name)
+
+		events := gapExceededEvents()
+		if c.exceeded {
+			exceeded++
+		}
+		require.Len(t, events, exceeded, c.name)
+		if c.exceeded {
+			attrs := events[len(events)-1].Attributes
+			require.Equal(t, lcptypes.AttributeKeyUpdateGap, attrs[1].Key)
+			require.Equal(t, c.elapsed.String(), attrs[1].Value, c.name)
+			require.Equal(t, time.Hour.String(), attrs[2].Value, c.name)
+		}
+	}
+}
+
+func TestUpdateGapUnbounded(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	h := testutil.NewHarness(t)
+	// the client is created by a previous version, so the time of the latest update is not recorded
+	h.SetClientState(&lcptypes.ClientState{})
+	require.Equal(t, exported.Active, h.ClientState().StatusWithStaleness(h.Ctx, h.Store, h.Cdc))
+
+	h.UpdateState(testutil.NewUpdateClientMessage(t, clienttypes.Height{}, clienttypes.NewHeight(0, 1), h.Ctx.BlockTime(), key))
+	_, found := lcptypes.GetLastUpdateTime(h.Store)
+	require.True(t, found)
+
+	h.AdvanceBlockTime(24 * 365 * time.Hour)
+	require.Equal(t, exported.Active, h.ClientState().StatusWithStaleness(h.Ctx, h.Store, h.Cdc))
+	h.UpdateState(testutil.NewUpdateClientMessage(t, clienttypes.NewHeight(0, 1), clienttypes.NewHeight(0, 2), h.Ctx.BlockTime(), key))
+	require.Empty(t, h.Ctx.EventManager().Events())
+}
+
+func TestRecoverSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	commitment := crypto.Keccak256Hash([]byte("message"))
+	sig, err := crypto.Sign(commitment[:], key)
+	require.NoError(t, err)
+
+	signer, err := lcptypes.RecoverSigner(commitment, [][]byte{nil, {}, sig})
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+
+	_, err = lcptypes.RecoverSigner(commitment, [][]byte{nil})
+	require.Error(t, err)
+	_, err = lcptypes.RecoverSigner(commitment, [][]byte{{0x01}})
+	require.Error(t, err)
+}
+
+// newOperatorKeys returns `n` keys ordered by their addresses as the operators of a client state must be
+func newOperatorKeys(t *testing.T, n int) []*ecdsa.PrivateKey {
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(crypto.PubkeyToAddress(keys[i].PublicKey).Bytes(), crypto.PubkeyToAddress(keys[j].PublicKey).Bytes()) < 0
+	})
+	return keys
+}
+
+func operatorsOf(keys []*ecdsa.PrivateKey) [][]byte {
+	var operators [][]byte
+	for _, key := range keys {
+		operators = append(operators, crypto.PubkeyToAddress(key.PublicKey).Bytes())
+	}
+	return operators
+}
+
+func newUpdateClientParamsMessage(nonce uint64) *lcptypes.UpdateClientParamsMessage {
+	return &lcptypes.UpdateClientParamsMessage{
+		Nonce:                   nonce,
+		NewAllowedQuoteStatuses: []string{lcptypes.QuoteSwHardeningNeeded},
+		NewAllowedAdvisoryIds:   []string{"INTEL-SA-00334"},
+		NewKeyExpiration:        60,
+	}
+}
+
+func TestVerifyUpdateClientParamsQuorum(t *testing.T) {
+	ops := newOperatorKeys(t, 3)
+	other := newOperatorKeys(t, 1)[0]
+
+	var cases = []struct {
+		name string
+		keys []*ecdsa.PrivateKey
+		err  string
+	}{
+		{"all operators", []*ecdsa.PrivateKey{ops[0], ops[1], ops[2]}, ""},
+		{"quorum", []*ecdsa.PrivateKey{ops[0], nil, ops[2]}, ""},
+		{"below quorum", []*ecdsa.PrivateKey{nil, ops[1], nil}, "insufficient signatures"},
+		{"no signature", []*ecdsa.PrivateKey{nil, nil, nil}, "insufficient signatures"},
+		{"wrong order", []*ecdsa.PrivateKey{ops[1], ops[0], ops[2]}, "invalid operator"},
+		{"not an operator", []*ecdsa.PrivateKey{ops[0], other, ops[2]}, "invalid operator"},
+		{"missing signature slot", []*ecdsa.PrivateKey{ops[0], ops[1]}, "invalid signature length"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := testutil.NewHarness(t)
+			h.SetClientState(&lcptypes.ClientState{
+				KeyExpiration:                 3600,
+				Operators:                     operatorsOf(ops),
+				OperatorsThresholdNumerator:   2,
+				OperatorsThresholdDenominator: 3,
+			})
+			msg := testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), newUpdateClientParamsMessage(1), c.keys...)
+			err := h.Update(msg)
+			if c.err != "" {
+				require.ErrorContains(t, err, c.err)
+				require.Equal(t, uint64(3600), h.ClientState().KeyExpiration)
+				return
+			}
+			require.NoError(t, err)
+			cs := h.ClientState()
+			require.Equal(t, msg.NewAllowedQuoteStatuses, cs.AllowedQuoteStatuses)
+			require.Equal(t, msg.NewAllowedAdvisoryIds, cs.AllowedAdvisoryIds)
+			require.Equal(t, msg.NewKeyExpiration, cs.KeyExpiration)
+			require.Equal(t, msg.Nonce, cs.OperatorsNonce)
+		})
+	}
+
+	t.Run("tampered params", func(t *testing.T) {
+		h := testutil.NewHarness(t)
+		h.SetClientState(&lcptypes.ClientState{KeyExpiration: 3600, Operators: operatorsOf(ops[:1]), OperatorsThresholdNumerator: 1, OperatorsThresholdDenominator: 1})
+		msg := testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), newUpdateClientParamsMessage(1), ops[0])
+		msg.NewKeyExpiration = 3600 * 24 * 365
+		require.ErrorContains(t, h.VerifyClientMessage(msg), "invalid operator")
+	})
+
+	t.Run("permissionless operators", func(t *testing.T) {
+		h := testutil.NewHarness(t)
+		h.SetClientState(&lcptypes.ClientState{KeyExpiration: 3600})
+		msg := testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), newUpdateClientParamsMessage(1), ops[0])
+		require.ErrorContains(t, h.VerifyClientMessage(msg), "permissionless operators")
+	})
+}
+
+func newUpdateOperatorsMessage(nonce uint64, operators [][]byte, numerator, denominator uint64) *lcptypes.UpdateOperatorsMessage {
+	return &lcptypes.UpdateOperatorsMessage{
+		Nonce:                            nonce,
+		NewOperators:                     operators,
+		NewOperatorsThresholdNumerator:   numerator,
+		NewOperatorsThresholdDenominator: denominator,
+	}
+}
+
+func TestVerifyUpdateOperatorsQuorum(t *testing.T) {
+	ops := newOperatorKeys(t, 3)
+	other := newOperatorKeys(t, 1)[0]
+	newOps := newOperatorKeys(t, 2)
+
+	var cases = []struct {
+		name                   string
+		numerator, denominator uint64
+		keys                   []*ecdsa.PrivateKey
+		err                    string
+	}{
+		{"2-of-3 with 3 signatures", 2, 3, []*ecdsa.PrivateKey{ops[0], ops[1], ops[2]}, ""},
+		{"2-of-3 with exactly 2 signatures", 2, 3, []*ecdsa.PrivateKey{ops[0], nil, ops[2]}, ""},
+		{"2-of-3 with 1 signature", 2, 3, []*ecdsa.PrivateKey{nil, ops[1], nil}, "insufficient signatures"},
+		{"2-of-3 with no signature", 2, 3, []*ecdsa.PrivateKey{nil, nil, nil}, "insufficient signatures"},
+		{"1-of-3 with 1 signature", 1, 3, []*ecdsa.PrivateKey{nil, ops[1], nil}, ""},
+		// a half of 3 operators requires 2 signatures
+		{"1-of-2 with 1 signature", 1, 2, []*ecdsa.PrivateKey{nil, ops[1], nil}, "insufficient signatures"},
+		{"3-of-3 with 2 signatures", 3, 3, []*ecdsa.PrivateKey{ops[0], ops[1], nil}, "insufficient signatures"},
+		{"signature from a non-operator", 2, 3, []*ecdsa.PrivateKey{ops[0], other, ops[2]}, "invalid operator"},
+		{"signatures from non-operators only", 1, 3, []*ecdsa.PrivateKey{other, nil, nil}, "invalid operator"},
+		// an operator cannot be counted twice by signing in the slot of another operator
+		{"duplicate signature of an operator", 2, 3, []*ecdsa.PrivateKey{ops[0], ops[0], nil}, "invalid operator"},
+		{"wrong order", 2, 3, []*ecdsa.PrivateKey{ops[1], ops[0], ops[2]}, "invalid operator"},
+		{"missing signature slot", 2, 3, []*ecdsa.PrivateKey{ops[0], ops[1]}, "invalid signature length"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := testutil.NewHarness(t)
+			h.SetClientState(&lcptypes.ClientState{
+				KeyExpiration:                 3600,
+				Operators:                     operatorsOf(ops),
+				OperatorsThresholdNumerator:   c.numerator,
+				OperatorsThresholdDenominator: c.denominator,
+			})
+			msg := testutil.SignUpdateOperatorsMessage(t, h.Ctx.ChainID(), newUpdateOperatorsMessage(1, operatorsOf(newOps), 1, 2), c.keys...)
+			err := h.Update(msg)
+			if c.err != "" {
+				require.ErrorContains(t, err, c.err)
+				require.Equal(t, operatorsOf(ops), h.ClientState().Operators)
+				require.Equal(t, uint64(0), h.ClientState().OperatorsNonce)
+				return
+			}
+			require.NoError(t, err)
+			cs := h.ClientState()
+			require.Equal(t, operatorsOf(newOps), cs.Operators)
+			require.Equal(t, lcptypes.Fraction{Numerator: 1, Denominator: 2}, cs.OperatorsThreshold())
+			require.Equal(t, uint64(1), cs.OperatorsNonce)
+		})
+	}
+
+	t.Run("new operators", func(t *testing.T) {
+		for _, c := range []struct {
+			name      string
+			operators [][]byte
+			err       string
+		}{
+			{"unordered", [][]byte{operatorsOf(newOps)[1], operatorsOf(newOps)[0]}, "must be ordered"},
+			{"duplicated", [][]byte{operatorsOf(newOps)[0], operatorsOf(newOps)[0]}, "must be ordered"},
+			{"zero address", [][]byte{common.Address{}.Bytes()}, "must not be zero"},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				h := testutil.NewHarness(t)
+				h.SetClientState(&lcptypes.ClientState{KeyExpiration: 3600, Operators: operatorsOf(ops[:1]), OperatorsThresholdNumerator: 1, OperatorsThresholdDenominator: 1})
+				msg := testutil.SignUpdateOperatorsMessage(t, h.Ctx.ChainID(), newUpdateOperatorsMessage(1, c.operators, 1, 1), ops[0])
+				require.ErrorContains(t, h.VerifyClientMessage(msg), c.err)
+			})
+		}
+	})
+
+	t.Run("nonce", func(t *testing.T) {
+		h := testutil.NewHarness(t)
+		h.SetClientState(&lcptypes.ClientState{KeyExpiration: 3600, Operators: operatorsOf(ops[:1]), OperatorsThresholdNumerator: 1, OperatorsThresholdDenominator: 1})
+		require.ErrorContains(t, h.VerifyClientMessage(testutil.SignUpdateOperatorsMessage(t, h.Ctx.ChainID(), newUpdateOperatorsMessage(2, operatorsOf(newOps), 1, 2), ops[0])), "invalid nonce")
+		first := testutil.SignUpdateOperatorsMessage(t, h.Ctx.ChainID(), newUpdateOperatorsMessage(1, operatorsOf(newOps), 1, 2), ops[0])
+		require.NoError(t, h.Update(first))
+		// the applied message cannot be replayed
+		require.ErrorContains(t, h.VerifyClientMessage(first), "invalid nonce")
+		// the new operators sign the next update
+		require.NoError(t, h.Update(testutil.SignUpdateOperatorsMessage(t, h.Ctx.ChainID(), newUpdateOperatorsMessage(2, operatorsOf(ops[:1]), 1, 1), newOps[0], nil)))
+		require.Equal(t, operatorsOf(ops[:1]), h.ClientState().Operators)
+		require.Equal(t, uint64(2), h.ClientState().OperatorsNonce)
+	})
+}
+
+func TestUpdateClientParamsNonceReplay(t *testing.T) {
+	ops := newOperatorKeys(t, 1)
+	h := testutil.NewHarness(t)
+	h.SetClientState(&lcptypes.ClientState{KeyExpiration: 3600, Operators: operatorsOf(ops), OperatorsThresholdNumerator: 1, OperatorsThresholdDenominator: 1})
+
+	first := testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), newUpdateClientParamsMessage(1), ops[0])
+	// the nonce must be the next one
+	require.ErrorContains(t, h.VerifyClientMessage(testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), newUpdateClientParamsMessage(2), ops[0])), "invalid nonce")
+	require.NoError(t, h.Update(first))
+	require.Equal(t, uint64(1), h.ClientState().OperatorsNonce)
+
+	// the applied message cannot be replayed
+	require.ErrorContains(t, h.VerifyClientMessage(first), "invalid nonce")
+
+	// the nonce is shared with the update of the operators
+	second := testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), newUpdateClientParamsMessage(2), ops[0])
+	h.UpdateState(&lcptypes.UpdateOperatorsMessage{
+		Nonce:                            2,
+		NewOperators:                     operatorsOf(ops),
+		NewOperatorsThresholdNumerator:   1,
+		NewOperatorsThresholdDenominator: 1,
+	})
+	require.ErrorContains(t, h.VerifyClientMessage(second), "invalid nonce")
+	require.NoError(t, h.Update(testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), newUpdateClientParamsMessage(3), ops[0])))
+}
+
+func TestUpdateOperatorsSameNonceInBlock(t *testing.T) {
+	ops := newOperatorKeys(t, 1)
+	newOps := newOperatorKeys(t, 2)
+	newHarness := func() *testutil.Harness {
+		h := testutil.NewHarness(t)
+		h.SetClientState(&lcptypes.ClientState{KeyExpiration: 3600, Operators: operatorsOf(ops), OperatorsThresholdNumerator: 1, OperatorsThresholdDenominator: 1})
+		return h
+	}
+
+	t.Run("update operators", func(t *testing.T) {
+		h := newHarness()
+		first := testutil.SignUpdateOperatorsMessage(t, h.Ctx.ChainID(), newUpdateOperatorsMessage(1, operatorsOf(newOps[:1]), 1, 1), ops[0])
+		second := testutil.SignUpdateOperatorsMessage(t, h.Ctx.ChainID(), newUpdateOperatorsMessage(1, operatorsOf(newOps[1:]), 1, 1), ops[0])
+		// both messages are verified against the client state before either is applied
+		cs := h.ClientState()
+		require.NoError(t, cs.VerifyClientMessage(h.Ctx, h.Cdc, h.Store, first))
+		require.NoError(t, cs.VerifyClientMessage(h.Ctx, h.Cdc, h.Store, second))
+
+		cs.UpdateState(h.Ctx, h.Cdc, h.Store, first)
+		require.PanicsWithError(t, "invalid nonce: expected=2 actual=1: invalid client header", func() {
+			cs.UpdateState(h.Ctx, h.Cdc, h.Store, second)
+		})
+		stored := h.ClientState()
+		require.Equal(t, operatorsOf(newOps[:1]), stored.Operators)
+		require.Equal(t, uint64(1), stored.OperatorsNonce)
+	})
+
+	t.Run("update operators and client params", func(t *testing.T) {
+		h := newHarness()
+		first := testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), newUpdateClientParamsMessage(1), ops[0])
+		second := testutil.SignUpdateOperatorsMessage(t, h.Ctx.ChainID(), newUpdateOperatorsMessage(1, operatorsOf(newOps), 1, 2), ops[0])
+		cs := h.ClientState()
+		require.NoError(t, cs.VerifyClientMessage(h.Ctx, h.Cdc, h.Store, first))
+		require.NoError(t, cs.VerifyClientMessage(h.Ctx, h.Cdc, h.Store, second))
+
+		cs.UpdateState(h.Ctx, h.Cdc, h.Store, first)
+		require.Panics(t, func() {
+			cs.UpdateState(h.Ctx, h.Cdc, h.Store, second)
+		})
+		stored := h.ClientState()
+		require.Equal(t, operatorsOf(ops), stored.Operators)
+		require.Equal(t, newUpdateClientParamsMessage(1).NewKeyExpiration, stored.KeyExpiration)
+		require.Equal(t, uint64(1), stored.OperatorsNonce)
+	})
+
+	t.Run("sequential", func(t *testing.T) {
+		// the second message is rejected by the verification if it is verified after the first one is applied
+		h := newHarness()
+		require.NoError(t, h.Update(testutil.SignUpdateOperatorsMessage(t, h.Ctx.ChainID(), newUpdateOperatorsMessage(1, operatorsOf(newOps[:1]), 1, 1), ops[0])))
+		require.ErrorContains(t, h.Update(testutil.SignUpdateOperatorsMessage(t, h.Ctx.ChainID(), newUpdateOperatorsMessage(1, operatorsOf(newOps[1:]), 1, 1), ops[0])), "invalid nonce")
+		require.Equal(t, operatorsOf(newOps[:1]), h.ClientState().Operators)
+	})
+}
+
+func TestUpdateClientParamsKeepsRegisteredKeys(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	ops := newOperatorKeys(t, 1)
+	fixture := testutil.LoadRegisterEnclaveKeyFixture(t, "001-avr")
+	cs := fixture.ClientState
+	cs.Operators = operatorsOf(ops)
+	cs.OperatorsThresholdNumerator = 1
+	cs.OperatorsThresholdDenominator = 1
+	h := testutil.NewHarness(t)
+	h.SetClientState(cs)
+	h.SetBlockTime(fixture.AttestationTime)
+	require.NoError(t, h.Update(fixture.Message))
+	registered := h.EnclaveKeyInfo(fixture.EnclaveKey)
+	require.NotNil(t, registered)
+
+	// a key that can sign the updates registered with the same expiration
+	key := testutil.TestEnclaveKey(t)
+	h.SetEnclaveKey(crypto.PubkeyToAddress(key.PublicKey), h.Ctx.BlockTime().Add(time.Hour), crypto.PubkeyToAddress(ops[0].PublicKey))
+	prev := clienttypes.NewHeight(0, 1)
+	h.SetConsensusState(prev, testutil.NewConsensusState(prev, h.Ctx.BlockTime()))
+
+	// shorten the expiration and disallow the quote status and the advisory IDs of the registered key
+	require.NoError(t, h.Update(testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), &lcptypes.UpdateClientParamsMessage{
+		Nonce:            1,
+		NewKeyExpiration: 60,
+	}, ops[0])))
+
+	// the registered key keeps its expiration
+	require.Equal(t, registered, h.EnclaveKeyInfo(fixture.EnclaveKey))
+	h.AdvanceBlockTime(10 * time.Minute)
+	post := clienttypes.NewHeight(0, 2)
+	require.NoError(t, h.Update(testutil.NewUpdateClientMessage(t, prev, post, h.Ctx.BlockTime(), key)))
+
+	// the new parameters apply to the keys registered after the update
+	h = testutil.NewHarness(t)
+	h.SetClientState(cs)
+	h.SetBlockTime(fixture.AttestationTime)
+	require.NoError(t, h.Update(testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), &lcptypes.UpdateClientParamsMessage{
+		Nonce:            1,
+		NewKeyExpiration: 60,
+	}, ops[0])))
+	if fixture.ClientState.AllowedQuoteStatuses[0] != lcptypes.QuoteOK {
+		require.ErrorContains(t, h.VerifyClientMessage(fixture.Message), "disallowed quote status")
+	}
+	require.NoError(t, h.Update(testutil.SignUpdateClientParamsMessage(t, h.Ctx.ChainID(), &lcptypes.UpdateClientParamsMessage{
+		Nonce:                   2,
+		NewAllowedQuoteStatuses: fixture.ClientState.AllowedQuoteStatuses,
+		NewAllowedAdvisoryIds:   fixture.ClientState.AllowedAdvisoryIds,
+		NewKeyExpiration:        60,
+	}, ops[0])))
+	require.NoError(t, h.Update(fixture.Message))
+	require.Equal(t, uint64(fixture.AttestationTime.Add(time.Minute).Unix()), h.EnclaveKeyInfo(fixture.EnclaveKey).ExpiredAt)
+}
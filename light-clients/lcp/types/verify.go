@@ -0,0 +1,370 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/sgx/dcap"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	oias "github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+)
+
+// The functions in this file verify the messages and the proofs of the LCP client with plain inputs,
+// so that off-chain verifiers can run the same verification as the ClientState methods without an SDK context or a KVStore.
+
+// EnclaveKeyLookup returns the info of the registered enclave key `ek` or nil if the key is not registered
+type EnclaveKeyLookup func(ek common.Address) (*EKInfo, error)
+
+// AttestedEnclaveKey is an enclave key attested by a verified AVR or DCAP quote
+type AttestedEnclaveKey struct {
+	EnclaveKey common.Address
+	// the zero address if the registration is not signed by an operator
+	Operator  common.Address
+	ExpiredAt time.Time
+}
+
+// EnclaveKeyParams are the parameters of the client to verify the attestation of an enclave key
+type EnclaveKeyParams struct {
+	Mrenclave            []byte
+	AllowedQuoteStatuses []string
+	AllowedAdvisoryIds   []string
+	// in seconds
+	KeyExpiration uint64
+	// the DER-encoded root certificates of the DCAP attestations
+	DCAPRootCerts [][]byte
+	// the minimum ISV SVN of the enclave, or zero if it is not constrained
+	MinimumISVSVN uint16
+}
+
+// GetEnclaveKeyParams returns the parameters of the client to verify the attestation of an enclave key
+func (cs ClientState) GetEnclaveKeyParams() EnclaveKeyParams {
+	return EnclaveKeyParams{
+		Mrenclave:            cs.Mrenclave,
+		AllowedQuoteStatuses: cs.AllowedQuoteStatuses,
+		AllowedAdvisoryIds:   cs.AllowedAdvisoryIds,
+		KeyExpiration:        cs.KeyExpiration,
+		DCAPRootCerts:        cs.DcapRootCerts,
+		MinimumISVSVN:        uint16(cs.MinimumIsvSvn),
+	}
+}
+
+// VerifyEnclaveKeySignatures verifies that `signatures` of `commitment` satisfy the operators of `cs` with the enclave keys given by `lookup`.
+// The keys must not be expired at `now`.
+func VerifyEnclaveKeySignatures(cs ClientState, lookup EnclaveKeyLookup, now time.Time, commitment [32]byte, signatures [][]byte) error {
+	operators := cs.GetOperators()
+	sigNum := len(signatures)
+	opNum := len(operators)
+	if opNum == 0 {
+		if sigNum != 1 {
+			return fmt.Errorf("invalid signature length: expected=%v actual=%v", 1, sigNum)
+		}
+		ek, err := RecoverAddress(commitment, signatures[0])
+		if err != nil {
+			return err
+		}
+		ekInfo, err := lookup(ek)
+		if err != nil {
+			return err
+		} else if ekInfo == nil {
+			return fmt.Errorf("enclave key '%v' not found", ek)
+		} else if ekInfo.IsExpired(now) {
+			return fmt.Errorf("enclave key '%v' is expired", ek)
+		}
+		return nil
+	} else if opNum != sigNum {
+		return fmt.Errorf("invalid signature length: expected=%v actual=%v", opNum, sigNum)
+	}
+
+	var success uint64 = 0
+	for i, op := range operators {
+		if len(signatures[i]) == 0 {
+			continue
+		}
+		ek, err := RecoverAddress(commitment, signatures[i])
+		if err != nil {
+			return err
+		}
+		ekInfo, err := lookup(ek)
+		if err != nil {
+			return err
+		} else if ekInfo == nil {
+			return fmt.Errorf("enclave key '%v' not found", ek)
+		} else if ekInfo.IsExpired(now) {
+			return fmt.Errorf("enclave key '%v' is expired", ek)
+		} else if !ekInfo.IsMatchOperator(op) {
+			return fmt.Errorf("enclave key '%v' operator mismatch: expected=%v actual=%v", ek, op, ekInfo.Operator)
+		}
+		success++
+	}
+
+	if success*cs.OperatorsThresholdDenominator < cs.OperatorsThresholdNumerator*uint64(opNum) {
+		return fmt.Errorf("insufficient signatures: threshold=%v/%v operators=%v actual=%v", cs.OperatorsThresholdNumerator, cs.OperatorsThresholdDenominator, opNum, success)
+	}
+
+	return nil
+}
+
+// VerifyUpdateClientSignatureAndLinkage verifies that `msg` is an update of the state signed by the enclave keys of `cs` given by `lookup`
+// and that it follows `prevConsensus`, i.e. the consensus state of the client at the previous height of the update.
+// `prevConsensus` must be nil if and only if the client is not activated yet, i.e. the latest height of `cs` is zero.
+// `now` is the time to check the expiration of the keys and the validation context of the message.
+func VerifyUpdateClientSignatureAndLinkage(prevConsensus *ConsensusState, msg *UpdateClientMessage, cs ClientState, lookup EnclaveKeyLookup, now time.Time) (*UpdateStateProxyMessage, error) {
+	pmsg, err := msg.GetProxyMessage()
+	if err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message: %v", err)
+	}
+	if err := cs.ValidateProxyMessageVersion(msg.ProxyMessage); err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message: %v", err)
+	}
+	if err := VerifyEnclaveKeySignatures(cs, lookup, now, crypto.Keccak256Hash(msg.ProxyMessage), msg.Signatures); err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, err.Error())
+	}
+	updateState, ok := pmsg.(*UpdateStateProxyMessage)
+	if !ok {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "unexpected message type: %T", pmsg)
+	}
+	if err := verifyUpdateStateLinkage(prevConsensus, msg, updateState, now); err != nil {
+		return nil, err
+	}
+	return updateState, nil
+}
+
+// verifyUpdateStateLinkage verifies that `pmsg` follows `prevConsensus` or initializes the state if `prevConsensus` is nil
+func verifyUpdateStateLinkage(prevConsensus *ConsensusState, msg *UpdateClientMessage, pmsg *UpdateStateProxyMessage, now time.Time) error {
+	if pmsg.PostStateID.IsZero() {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message %v: `PostStateID` must be non-zero", msg)
+	}
+	if pmsg.PrevStateID != nil && pmsg.PrevStateID.IsZero() {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message %v: `PrevStateID` must be non-zero", msg)
+	}
+	if prevConsensus == nil {
+		if len(pmsg.EmittedStates) == 0 {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message %v: `NewState` must be non-nil", msg)
+		}
+	} else {
+		if pmsg.PrevHeight == nil || pmsg.PrevStateID == nil {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message %v: `PrevHeight` and `PrevStateID` must be non-nil", msg)
+		}
+		if err := ValidateStateID(prevConsensus.StateId); err != nil {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid consensus state: height=%v %v", pmsg.PrevHeight, err)
+		}
+		if !bytes.Equal(prevConsensus.StateId, pmsg.PrevStateID[:]) {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "unexpected StateID: expected=%v actual=%v", HexBytes(prevConsensus.StateId), pmsg.PrevStateID)
+		}
+	}
+
+	if err := pmsg.Context.Validate(now); err != nil {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid context: %v", err)
+	}
+	return nil
+}
+
+// VerifyRegisterEnclaveKeyAVR verifies the AVR of `msg` with `params` at `now` and returns the enclave key attested by it.
+// It does not check whether the key is already registered.
+func VerifyRegisterEnclaveKeyAVR(params EnclaveKeyParams, msg *RegisterEnclaveKeyMessage, now time.Time) (*AttestedEnclaveKey, error) {
+	if err := ias.VerifyReport(msg.Report, msg.Signature, msg.SigningCert, now); err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid message: message=%v, err=%v", msg, err)
+	}
+	avr, err := ias.ParseAndValidateAVR(msg.Report)
+	if err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid AVR: report=%v err=%v", msg.Report, err)
+	}
+	if l := len(avr.AdvisoryIDs); l > MaxAVRAdvisoryIDs {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "too many advisory IDs in AVR: max=%v actual=%v", MaxAVRAdvisoryIDs, l)
+	}
+	if err := verifyQuotePolicy(params, avr.ISVEnclaveQuoteStatus.String(), avr.AdvisoryIDs); err != nil {
+		return nil, err
+	}
+	quote, err := avr.Quote()
+	if err != nil {
+		return nil, err
+	}
+	return verifyAttestedEnclaveKey(params, quote, func() (common.Hash, error) {
+		return ComputeEIP712RegisterEnclaveKeyHash(string(msg.Report))
+	}, msg.OperatorSignature, avr.GetTimestamp())
+}
+
+// VerifyDCAPRegisterEnclaveKey verifies the DCAP attestation of `msg` with `params` at `now` and returns the enclave key attested by it.
+// The quote and the collateral must chain to the DCAP root certificates of `params`. As the quote carries no timestamp,
+// the issue date of the TCB info is used as the attestation time.
+// It does not check whether the key is already registered or has been pruned, though a pruned key must not be registered again
+// because the same quote paired with a fresher collateral would give it a new expiration.
+func VerifyDCAPRegisterEnclaveKey(params EnclaveKeyParams, msg *DCAPRegisterEnclaveKeyMessage, now time.Time) (*AttestedEnclaveKey, error) {
+	if len(params.DCAPRootCerts) == 0 {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "DCAP attestations are disabled: the client has no DCAP root certificates")
+	}
+	roots, err := dcap.NewRootCertPool(params.DCAPRootCerts)
+	if err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid DCAP root certificates: %v", err)
+	}
+	vq, err := dcap.VerifyQuote(msg.Attestation.Quote, msg.Attestation.GetCollateral(), roots, now)
+	if err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid DCAP attestation: %v", err)
+	}
+	// the debug enclaves are rejected as ias.ParseAndValidateAVR does for the EPID attestations
+	if ias.IsDebugEnclave(vq.Quote.ISVEnclaveQuote()) && !ias.IsAllowDebugEnclaves() {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid DCAP attestation: debug enclave is not allowed: debug=true")
+	}
+	quoteStatus, err := vq.TCBStatus.QuoteStatus()
+	if err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid DCAP attestation: %v", err)
+	}
+	if err := verifyQuotePolicy(params, quoteStatus, vq.AdvisoryIDs); err != nil {
+		return nil, err
+	}
+	return verifyAttestedEnclaveKey(params, vq.Quote.ISVEnclaveQuote(), func() (common.Hash, error) {
+		return ComputeEIP712DCAPRegisterEnclaveKeyHash(msg.Attestation.Quote)
+	}, msg.OperatorSignature, vq.TCBInfo.IssueDate)
+}
+
+// verifyQuotePolicy returns an error if the quote status or the advisory IDs of an attestation are not allowed by `params`
+func verifyQuotePolicy(params EnclaveKeyParams, quoteStatus string, advisoryIDs []string) error {
+	if quoteStatus == QuoteOK {
+		if len(advisoryIDs) != 0 {
+			return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "advisory IDs should be empty when status is OK: actual=%v", advisoryIDs)
+		}
+		return nil
+	}
+	if !IsAllowedQuoteStatus(params.AllowedQuoteStatuses, quoteStatus) {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "disallowed quote status exists: allowed=%v actual=%v", params.AllowedQuoteStatuses, quoteStatus)
+	}
+	if !AreAllowedAdvisoryIDs(params.AllowedAdvisoryIds, advisoryIDs) {
+		return errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "disallowed advisory ID(s) exists: allowed=%v actual=%v", params.AllowedAdvisoryIds, advisoryIDs)
+	}
+	return nil
+}
+
+// verifyAttestedEnclaveKey verifies the enclave of the attested `quote` with `params` and returns the enclave key in the report data.
+// If `operatorSignature` is given, it must be a signature of the commitment by the operator in the report data.
+func verifyAttestedEnclaveKey(params EnclaveKeyParams, quote *oias.Quote, commitment func() (common.Hash, error), operatorSignature []byte, attestationTime time.Time) (*AttestedEnclaveKey, error) {
+	if !bytes.Equal(params.Mrenclave, quote.Report.MRENCLAVE[:]) {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid AVR: mrenclave mismatch: expected=%v actual=%v", HexBytes(params.Mrenclave), HexBytes(quote.Report.MRENCLAVE[:]))
+	}
+	if err := ias.CheckISVSVN(quote, params.MinimumISVSVN); err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid AVR: %v", err)
+	}
+	var operator common.Address
+	if len(operatorSignature) > 0 {
+		commitment, err := commitment()
+		if err != nil {
+			return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to compute commitment: %v", err)
+		}
+		operator, err = RecoverAddress(commitment, operatorSignature)
+		if err != nil {
+			return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to recover operator address: %v", err)
+		}
+	}
+	ek, expectedOperator, err := ias.GetEKAndOperator(quote)
+	if err != nil {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "failed to get enclave key and operator: %v", err)
+	}
+	if (expectedOperator != common.Address{}) && operator != expectedOperator {
+		return nil, errorsmod.Wrapf(clienttypes.ErrInvalidHeader, "invalid operator: expected=%v actual=%v", expectedOperator, operator)
+	}
+	return &AttestedEnclaveKey{
+		EnclaveKey: ek,
+		Operator:   operator,
+		ExpiredAt:  keyExpiredAt(params.KeyExpiration, attestationTime),
+	}, nil
+}
+
+// VerifyCommitmentProof verifies that `proof` is a state commitment signed by `expectedSigner` and returns the commitment.
+// The caller is responsible for checking the height, the path, the value and the state ID of the commitment, e.g. with VerifyMembershipCommitment,
+// and the version of the commitment with ClientState.ValidateProxyMessageVersion.
+func VerifyCommitmentProof(proof []byte, expectedSigner common.Address) (*ELCVerifyMembershipMessage, error) {
+	commitmentProofs, msg, err := decodeCommitmentProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	commitment := crypto.Keccak256Hash(commitmentProofs.Message)
+	var signers []common.Address
+	for _, sig := range commitmentProofs.Signatures {
+		if len(sig) == 0 {
+			continue
+		}
+		signer, err := RecoverAddress(commitment, sig)
+		if err != nil {
+			return nil, errorsmod.Wrapf(ErrInvalidStateCommitment, "failed to recover the signer: signature=%v %v", HexBytes(sig), err)
+		} else if signer == expectedSigner {
+			return msg, nil
+		}
+		signers = append(signers, signer)
+	}
+	return nil, errorsmod.Wrapf(ErrInvalidStateCommitment, "no signature of the expected signer: expected=%v actual=%v", expectedSigner, signers)
+}
+
+// VerifyMembershipCommitment verifies that `msg` commits to `value` at `path` under `prefix` in the state `stateID` at `height`
+func VerifyMembershipCommitment(msg *ELCVerifyMembershipMessage, height exported.Height, prefix, path, value, stateID []byte) error {
+	hashedValue := crypto.Keccak256Hash(value)
+	if !height.EQ(msg.Height) {
+		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid height: expected=%v got=%v", height, msg.Height)
+	}
+	if !bytes.Equal(prefix, msg.Prefix) {
+		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid prefix: expected=%v got=%v", prefix, msg.Prefix)
+	}
+	if !bytes.Equal(path, msg.Path) {
+		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid path: expected=%v got=%v", string(path), string(msg.Path))
+	}
+	if hashedValue != msg.Value {
+		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid value: expected=%v got=%v", HexBytes(hashedValue[:]), HexBytes(msg.Value[:]))
+	}
+	if !msg.StateID.EqualBytes(stateID) {
+		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid state ID: height=%v expected=%v got=%v", height, HexBytes(stateID), msg.StateID)
+	}
+	return nil
+}
+
+// VerifyNonMembershipCommitment verifies that `msg` commits to the absence of a value at `path` under `prefix` in the state `stateID` at `height`
+func VerifyNonMembershipCommitment(msg *ELCVerifyNonMembershipMessage, height exported.Height, prefix, path, stateID []byte) error {
+	if !height.EQ(msg.Height) {
+		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid height: expected=%v got=%v", height, msg.Height)
+	}
+	if !bytes.Equal(prefix, msg.Prefix) {
+		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid prefix: expected=%v got=%v", prefix, msg.Prefix)
+	}
+	if !bytes.Equal(path, msg.Path) {
+		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid path: expected=%v got=%v", string(path), string(msg.Path))
+	}
+	if !msg.StateID.EqualBytes(stateID) {
+		return errorsmod.Wrapf(ErrInvalidStateCommitment, "invalid state ID: height=%v expected=%v got=%v", height, HexBytes(stateID), msg.StateID)
+	}
+	return nil
+}
+
+func decodeNonMembershipCommitmentProof(proof []byte) (*CommitmentProofs, *ELCVerifyNonMembershipMessage, error) {
+	commitmentProofs, err := EthABIDecodeCommitmentProofs(proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := commitmentProofs.GetMessage()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, err := m.GetVerifyNonMembershipProxyMessage()
+	if err != nil {
+		return nil, nil, err
+	}
+	return commitmentProofs, msg, nil
+}
+
+func decodeCommitmentProof(proof []byte) (*CommitmentProofs, *ELCVerifyMembershipMessage, error) {
+	commitmentProofs, err := EthABIDecodeCommitmentProofs(proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := commitmentProofs.GetMessage()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, err := m.GetVerifyMembershipProxyMessage()
+	if err != nil {
+		return nil, nil, err
+	}
+	return commitmentProofs, msg, nil
+}
@@ -0,0 +1,267 @@
+package types_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/light-clients/lcp/testutil"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/sgx/dcap"
+	"github.com/datachainlab/lcp-go/sgx/dcap/dcaptest"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// the tests in this file verify the messages as an off-chain verifier does, i.e. without an SDK context or a KVStore
+
+func TestVerifyUpdateClientSignatureAndLinkage(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	now := testutil.DefaultBlockTime
+	prev, post := clienttypes.NewHeight(0, 1), clienttypes.NewHeight(0, 2)
+	cs := lcptypes.ClientState{LatestHeight: prev, KeyExpiration: 3600}
+	// the registered keys collected by the verifier
+	keys := map[common.Address]*lcptypes.EKInfo{
+		ek: {ExpiredAt: uint64(now.Add(time.Hour).Unix())},
+	}
+	lookup := func(ek common.Address) (*lcptypes.EKInfo, error) {
+		return keys[ek], nil
+	}
+	msg := testutil.NewUpdateClientMessage(t, prev, post, now, key)
+
+	updateState, err := lcptypes.VerifyUpdateClientSignatureAndLinkage(testutil.NewConsensusState(prev, now), msg, cs, lookup, now)
+	require.NoError(t, err)
+	require.Equal(t, post, updateState.PostHeight)
+	require.Equal(t, testutil.StateIDAt(post), updateState.PostStateID)
+
+	// the message does not follow the previous consensus state
+	_, err = lcptypes.VerifyUpdateClientSignatureAndLinkage(testutil.NewConsensusState(clienttypes.NewHeight(0, 5), now), msg, cs, lookup, now)
+	expectedStateID, actualStateID := testutil.StateIDAt(clienttypes.NewHeight(0, 5)), testutil.StateIDAt(prev)
+	require.ErrorContains(t, err, fmt.Sprintf("unexpected StateID: expected=%v actual=%v", lcptypes.HexBytes(expectedStateID[:]), lcptypes.HexBytes(actualStateID[:])))
+	// the message does not initialize the client
+	_, err = lcptypes.VerifyUpdateClientSignatureAndLinkage(nil, msg, lcptypes.ClientState{KeyExpiration: 3600}, lookup, now)
+	require.ErrorContains(t, err, "`NewState` must be non-nil")
+	// the key is expired
+	_, err = lcptypes.VerifyUpdateClientSignatureAndLinkage(testutil.NewConsensusState(prev, now), msg, cs, lookup, now.Add(2*time.Hour))
+	require.ErrorContains(t, err, "expired")
+	// the message is signed by an unknown key
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = lcptypes.VerifyUpdateClientSignatureAndLinkage(testutil.NewConsensusState(prev, now), testutil.NewUpdateClientMessage(t, prev, post, now, other), cs, lookup, now)
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestVerifyEnclaveKeySignaturesThreshold(t *testing.T) {
+	now := testutil.DefaultBlockTime
+	ops := newOperatorKeys(t, 3)
+	// the enclave keys registered by each operator
+	eks := newOperatorKeys(t, 3)
+	keys := make(map[common.Address]*lcptypes.EKInfo)
+	for i, ek := range eks {
+		keys[crypto.PubkeyToAddress(ek.PublicKey)] = &lcptypes.EKInfo{
+			ExpiredAt: uint64(now.Add(time.Hour).Unix()),
+			Operator:  crypto.PubkeyToAddress(ops[i].PublicKey),
+		}
+	}
+	lookup := func(ek common.Address) (*lcptypes.EKInfo, error) {
+		return keys[ek], nil
+	}
+	commitment := crypto.Keccak256Hash([]byte("commitment"))
+
+	var cases = []struct {
+		name                   string
+		numerator, denominator uint64
+		signed                 []bool
+		ok                     bool
+	}{
+		{"2-of-3 with 3 signatures", 2, 3, []bool{true, true, true}, true},
+		{"2-of-3 with 2 signatures", 2, 3, []bool{true, false, true}, true},
+		{"2-of-3 with 1 signature", 2, 3, []bool{false, true, false}, false},
+		{"1-of-3 with 1 signature", 1, 3, []bool{false, false, true}, true},
+		{"1-of-3 with no signature", 1, 3, []bool{false, false, false}, false},
+		{"3-of-3 with 2 signatures", 3, 3, []bool{true, true, false}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cs := lcptypes.ClientState{
+				KeyExpiration:                 3600,
+				Operators:                     operatorsOf(ops),
+				OperatorsThresholdNumerator:   c.numerator,
+				OperatorsThresholdDenominator: c.denominator,
+			}
+			var signatures [][]byte
+			for i, signed := range c.signed {
+				if !signed {
+					signatures = append(signatures, nil)
+					continue
+				}
+				sig, err := crypto.Sign(commitment[:], eks[i])
+				require.NoError(t, err)
+				signatures = append(signatures, sig)
+			}
+			err := lcptypes.VerifyEnclaveKeySignatures(cs, lookup, now, commitment, signatures)
+			if c.ok {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, "insufficient signatures")
+			}
+		})
+	}
+}
+
+func TestVerifyRegisterEnclaveKeyAVR(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	fixture := testutil.LoadRegisterEnclaveKeyFixture(t, "001-avr")
+	params := fixture.ClientState.GetEnclaveKeyParams()
+	key, err := lcptypes.VerifyRegisterEnclaveKeyAVR(params, fixture.Message, fixture.AttestationTime)
+	require.NoError(t, err)
+	require.Equal(t, fixture.EnclaveKey, key.EnclaveKey)
+	require.Equal(t, common.Address{}, key.Operator)
+	require.Equal(t, fixture.AttestationTime.Add(time.Hour), key.ExpiredAt)
+	require.Equal(t, fixture.ClientState.KeyExpiredAt(fixture.AttestationTime), key.ExpiredAt)
+
+	params.Mrenclave = make([]byte, lcptypes.MrenclaveSize)
+	_, err = lcptypes.VerifyRegisterEnclaveKeyAVR(params, fixture.Message, fixture.AttestationTime)
+	require.ErrorContains(t, err, "mrenclave mismatch: expected=0x"+strings.Repeat("00", lcptypes.MrenclaveSize)+" actual=0x")
+}
+
+func TestVerifyDCAPRegisterEnclaveKey(t *testing.T) {
+	require := require.New(t)
+	issueDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	operatorKey, err := crypto.GenerateKey()
+	require.NoError(err)
+	operator := crypto.PubkeyToAddress(operatorKey.PublicKey)
+	params := dcaptest.Params{
+		Mrenclave:   [32]byte{0x01},
+		EnclaveKey:  common.HexToAddress("0x01"),
+		Operator:    operator,
+		TCBStatus:   dcap.TCBStatusSWHardeningNeeded,
+		AdvisoryIDs: []string{"INTEL-SA-00615"},
+		IssueDate:   issueDate,
+	}
+	f := dcaptest.NewFixture(t, params)
+	msg := testutil.NewDCAPRegisterEnclaveKeyMessage(f)
+	commitment, err := lcptypes.ComputeEIP712DCAPRegisterEnclaveKeyHash(f.Quote)
+	require.NoError(err)
+	msg.OperatorSignature, err = crypto.Sign(commitment[:], operatorKey)
+	require.NoError(err)
+	cs := lcptypes.ClientState{
+		Mrenclave:            params.Mrenclave[:],
+		KeyExpiration:        3600,
+		AllowedQuoteStatuses: []string{lcptypes.QuoteSwHardeningNeeded},
+		AllowedAdvisoryIds:   []string{"INTEL-SA-00615"},
+		DcapRootCerts:        [][]byte{f.RootCert},
+	}
+	now := issueDate.Add(time.Hour)
+
+	key, err := lcptypes.VerifyDCAPRegisterEnclaveKey(cs.GetEnclaveKeyParams(), msg, now)
+	require.NoError(err)
+	require.Equal(params.EnclaveKey, key.EnclaveKey)
+	require.Equal(operator, key.Operator)
+	// the attestation time is the issue date of the TCB info
+	require.Equal(issueDate.Add(time.Hour), key.ExpiredAt)
+
+	// the DCAP attestations are disabled without the root certificates
+	disabled := cs
+	disabled.DcapRootCerts = nil
+	_, err = lcptypes.VerifyDCAPRegisterEnclaveKey(disabled.GetEnclaveKeyParams(), msg, now)
+	require.ErrorContains(err, "DCAP attestations are disabled")
+
+	// the TCB status is mapped to the quote status
+	strict := cs
+	strict.AllowedQuoteStatuses = nil
+	_, err = lcptypes.VerifyDCAPRegisterEnclaveKey(strict.GetEnclaveKeyParams(), msg, now)
+	require.ErrorContains(err, "disallowed quote status exists: allowed=[] actual=SW_HARDENING_NEEDED")
+	strict = cs
+	strict.AllowedAdvisoryIds = nil
+	_, err = lcptypes.VerifyDCAPRegisterEnclaveKey(strict.GetEnclaveKeyParams(), msg, now)
+	require.ErrorContains(err, "disallowed advisory ID(s) exists")
+
+	// the enclave must match
+	other := cs
+	other.Mrenclave = make([]byte, lcptypes.MrenclaveSize)
+	_, err = lcptypes.VerifyDCAPRegisterEnclaveKey(other.GetEnclaveKeyParams(), msg, now)
+	require.ErrorContains(err, "mrenclave mismatch")
+
+	// the operator must match the one in the report data
+	wrongOperator := *msg
+	wrongOperator.OperatorSignature, err = crypto.Sign(commitment[:], testutil.TestEnclaveKey(t))
+	require.NoError(err)
+	_, err = lcptypes.VerifyDCAPRegisterEnclaveKey(cs.GetEnclaveKeyParams(), &wrongOperator, now)
+	require.ErrorContains(err, "invalid operator")
+
+	// the collateral must be valid at `now`
+	_, err = lcptypes.VerifyDCAPRegisterEnclaveKey(cs.GetEnclaveKeyParams(), msg, issueDate.AddDate(0, 1, 0))
+	require.ErrorContains(err, "invalid DCAP attestation")
+
+	// the debug enclaves are rejected unless they are allowed
+	debugParams := params
+	debugParams.Debug = true
+	debugParams.Operator = common.Address{}
+	debug := dcaptest.NewFixture(t, debugParams)
+	debugMsg := testutil.NewDCAPRegisterEnclaveKeyMessage(debug)
+	debugCS := cs
+	debugCS.DcapRootCerts = [][]byte{debug.RootCert}
+	_, err = lcptypes.VerifyDCAPRegisterEnclaveKey(debugCS.GetEnclaveKeyParams(), debugMsg, now)
+	require.ErrorContains(err, "debug enclave is not allowed")
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	_, err = lcptypes.VerifyDCAPRegisterEnclaveKey(debugCS.GetEnclaveKeyParams(), debugMsg, now)
+	require.NoError(err)
+}
+
+func TestVerifyCommitmentProof(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	ek := crypto.PubkeyToAddress(key.PublicKey)
+	height := clienttypes.NewHeight(0, 10)
+	path, value := "commitments/ports/transfer/channels/channel-0/sequences/1", []byte("value")
+	stateID := testutil.StateIDAt(height)
+
+	proof := testutil.NewCommitmentProof(t, height, path, value, key)
+	msg, err := lcptypes.VerifyCommitmentProof(proof, ek)
+	require.NoError(t, err)
+	require.NoError(t, lcptypes.VerifyMembershipCommitment(msg, height, []byte(exported.StoreKey), []byte(path), value, stateID[:]))
+	require.ErrorIs(t, lcptypes.VerifyMembershipCommitment(msg, height, []byte(exported.StoreKey), []byte(path), []byte("other"), stateID[:]), lcptypes.ErrInvalidStateCommitment)
+	require.ErrorIs(t, lcptypes.VerifyMembershipCommitment(msg, clienttypes.NewHeight(0, 11), []byte(exported.StoreKey), []byte(path), value, stateID[:]), lcptypes.ErrInvalidStateCommitment)
+
+	// one of the operators signed
+	_, err = lcptypes.VerifyCommitmentProof(testutil.NewCommitmentProof(t, height, path, value, nil, key), ek)
+	require.NoError(t, err)
+
+	other, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = lcptypes.VerifyCommitmentProof(testutil.NewCommitmentProof(t, height, path, value, other), ek)
+	require.ErrorIs(t, err, lcptypes.ErrInvalidStateCommitment)
+}
+
+func TestVerifyNonMembershipCommitment(t *testing.T) {
+	key := testutil.TestEnclaveKey(t)
+	height := clienttypes.NewHeight(0, 10)
+	path := "receipts/ports/transfer/channels/channel-0/sequences/1"
+	stateID := testutil.StateIDAt(height)
+
+	getMessage := func(proof []byte) (*lcptypes.ELCVerifyNonMembershipMessage, error) {
+		cp, err := lcptypes.EthABIDecodeCommitmentProofs(proof)
+		require.NoError(t, err)
+		m, err := cp.GetMessage()
+		require.NoError(t, err)
+		return m.GetVerifyNonMembershipProxyMessage()
+	}
+	msg, err := getMessage(testutil.NewNonMembershipCommitmentProof(t, height, path, key))
+	require.NoError(t, err)
+	require.NoError(t, lcptypes.VerifyNonMembershipCommitment(msg, height, []byte(exported.StoreKey), []byte(path), stateID[:]))
+	require.ErrorIs(t, lcptypes.VerifyNonMembershipCommitment(msg, clienttypes.NewHeight(0, 11), []byte(exported.StoreKey), []byte(path), stateID[:]), lcptypes.ErrInvalidStateCommitment)
+	require.ErrorIs(t, lcptypes.VerifyNonMembershipCommitment(msg, height, []byte(exported.StoreKey), []byte("receipts/other"), stateID[:]), lcptypes.ErrInvalidStateCommitment)
+
+	// a membership commitment does not prove the absence
+	_, err = getMessage(testutil.NewCommitmentProof(t, height, path, []byte{}, key))
+	require.ErrorContains(t, err, "unexpected value of non-membership message")
+}
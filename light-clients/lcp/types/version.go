@@ -0,0 +1,52 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	errorsmod "cosmossdk.io/errors"
+)
+
+// AcceptedMessageVersions returns the proxy message versions that the client accepts in ascending order.
+// If `AllowedMessageVersions` is empty, only `LCPMessageVersion` is accepted.
+func (cs ClientState) AcceptedMessageVersions() []uint16 {
+	if len(cs.AllowedMessageVersions) == 0 {
+		return []uint16{LCPMessageVersion}
+	}
+	versions := make([]uint16, 0, len(cs.AllowedMessageVersions))
+	for _, v := range cs.AllowedMessageVersions {
+		if !slices.Contains(versions, uint16(v)) {
+			versions = append(versions, uint16(v))
+		}
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// IsAllowedMessageVersion returns true if the client accepts the given proxy message version
+func (cs ClientState) IsAllowedMessageVersion(version uint16) bool {
+	return slices.Contains(cs.AcceptedMessageVersions(), version)
+}
+
+// ValidateProxyMessageVersion returns an error if the client does not accept the version of the headered proxy message `proxyMessage`
+func (cs ClientState) ValidateProxyMessageVersion(proxyMessage []byte) error {
+	m, err := EthABIDecodeHeaderedProxyMessage(proxyMessage)
+	if err != nil {
+		return err
+	}
+	if !cs.IsAllowedMessageVersion(m.Version) {
+		return errorsmod.Wrapf(ErrUnsupportedMessageVersion, "allowed=%v actual=%v", cs.AcceptedMessageVersions(), m.Version)
+	}
+	return nil
+}
+
+// validateAllowedMessageVersions returns an error if `AllowedMessageVersions` contains a version that cannot be carried by a proxy message
+func (cs ClientState) validateAllowedMessageVersions() error {
+	for _, v := range cs.AllowedMessageVersions {
+		if v == 0 || v > math.MaxUint16 {
+			return fmt.Errorf("`AllowedMessageVersions` must be in the range [1, %v], but got %v", math.MaxUint16, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/require"
+)
+
+type testHeight struct {
+	RevisionNumber uint64 `json:"revision_number"`
+	RevisionHeight uint64 `json:"revision_height"`
+}
+
+// newTestUpdateClientMessage returns an UpdateClientMessage with an UpdateState proxy message of the given version
+func newTestUpdateClientMessage(t *testing.T, version uint16) UpdateClientMessage {
+	var contextHeader [32]byte
+	binary.BigEndian.PutUint16(contextHeader[:2], LCPMessageContextTypeEmpty)
+	context, err := abi.Arguments{{Type: headeredMessageContextABI}}.Pack(struct {
+		Header       [32]byte `json:"header"`
+		ContextBytes []byte   `json:"context_bytes"`
+	}{Header: contextHeader, ContextBytes: []byte{}})
+	require.NoError(t, err)
+
+	message, err := abi.Arguments{{Type: updateStateProxyMessageABI}}.Pack(struct {
+		PrevHeight    testHeight `json:"prev_height"`
+		PrevStateId   [32]byte   `json:"prev_state_id"`
+		PostHeight    testHeight `json:"post_height"`
+		PostStateId   [32]byte   `json:"post_state_id"`
+		Timestamp     *big.Int   `json:"timestamp"`
+		Context       []byte     `json:"context"`
+		EmittedStates []struct {
+			Height testHeight `json:"height"`
+			State  []byte     `json:"state"`
+		} `json:"emitted_states"`
+	}{
+		PrevHeight:  testHeight{RevisionNumber: 0, RevisionHeight: 1},
+		PrevStateId: [32]byte{1},
+		PostHeight:  testHeight{RevisionNumber: 0, RevisionHeight: 2},
+		PostStateId: [32]byte{2},
+		Timestamp:   big.NewInt(1),
+		Context:     context,
+	})
+	require.NoError(t, err)
+
+	var header [32]byte
+	binary.BigEndian.PutUint16(header[:2], version)
+	binary.BigEndian.PutUint16(header[2:4], LCPMessageTypeUpdateState)
+	bz, err := abi.Arguments{{Type: headeredMessageABI}}.Pack(struct {
+		Header  [32]byte `json:"header"`
+		Message []byte   `json:"message"`
+	}{Header: header, Message: message})
+	require.NoError(t, err)
+	return UpdateClientMessage{ProxyMessage: bz}
+}
+
+func TestAllowedMessageVersions(t *testing.T) {
+	v1 := newTestUpdateClientMessage(t, 1)
+	v2 := newTestUpdateClientMessage(t, 2)
+
+	cases := []struct {
+		name     string
+		allowed  []uint32
+		v1Passes bool
+		v2Passes bool
+	}{
+		{"default", nil, true, false},
+		{"v1 and v2", []uint32{2, 1}, true, true},
+		{"v2 only", []uint32{2}, false, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cs := ClientState{AllowedMessageVersions: c.allowed}
+			for _, m := range []struct {
+				msg    UpdateClientMessage
+				passes bool
+			}{{v1, c.v1Passes}, {v2, c.v2Passes}} {
+				// the version is checked against the client state, not by the stateless validation
+				require.NoError(t, m.msg.ValidateBasic())
+				err := cs.ValidateProxyMessageVersion(m.msg.ProxyMessage)
+				if m.passes {
+					require.NoError(t, err)
+				} else {
+					require.ErrorIs(t, err, ErrUnsupportedMessageVersion)
+				}
+			}
+		})
+	}
+	require.Equal(t, []uint16{LCPMessageVersion}, ClientState{}.AcceptedMessageVersions())
+	require.Equal(t, []uint16{1, 2}, ClientState{AllowedMessageVersions: []uint32{2, 1, 2}}.AcceptedMessageVersions())
+
+	for _, v := range []uint32{0, 1 << 16} {
+		require.ErrorContains(t, ClientState{AllowedMessageVersions: []uint32{1, v}}.validateAllowedMessageVersions(), "`AllowedMessageVersions` must be in the range")
+	}
+}
@@ -0,0 +1,135 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// ActivateClientResult is the result of the activation of the LCP client
+type ActivateClientResult struct {
+	ELCClientID string `json:"elc_client_id"`
+	ClientID    string `json:"client_id"`
+	// the latest height of the LCP client on the counterparty chain before the activation
+	InitialHeight clienttypes.Height `json:"initial_height"`
+	// true if the updates of an interrupted activation are submitted instead of new updates
+	Resumed bool `json:"resumed"`
+	// true if the updates are submitted with the registration of the enclave key
+	Bundled bool `json:"bundled"`
+	// the number of the updates submitted by this run
+	Submitted int `json:"submitted"`
+	// the number of the updates of the activation applied to the LCP client
+	Applied int `json:"applied"`
+	// the number of the updates not applied yet, which a next run resumes from
+	Pending int `json:"pending"`
+	// the latest height of the LCP client after the activation
+	LatestHeight clienttypes.Height `json:"latest_height"`
+}
+
+// activateClientCheckpoint is persisted before the updates of the activation are submitted.
+// If the submission is interrupted after some of the updates are applied, the ELC client is ahead of the LCP client
+// and the updates generated again would not follow the latest height of the LCP client,
+// so a next activation submits the pending updates of the checkpoint instead.
+type activateClientCheckpoint struct {
+	ELCClientID string                          `json:"elc_client_id"`
+	ClientID    string                          `json:"client_id"`
+	Updates     []*lcptypes.UpdateClientMessage `json:"updates"`
+}
+
+// pendingUpdates returns the updates whose post heights are higher than `clientHeight`
+func (c *activateClientCheckpoint) pendingUpdates(clientHeight ibcexported.Height) []core.Header {
+	var pending []core.Header
+	for _, update := range c.Updates {
+		if update.GetHeight().GT(clientHeight) {
+			pending = append(pending, update)
+		}
+	}
+	return pending
+}
+
+// resumeActivateClient submits the pending updates of the interrupted activation if any.
+// It returns false if there is no activation to resume, and the checkpoint of another client or of a completed activation is discarded.
+func (pr *Prover) resumeActivateClient(ctx context.Context, pathEnd *core.PathEnd, counterparty core.FinalityAwareChain, result *ActivateClientResult) (bool, error) {
+	checkpoint, err := pr.loadActivateClientCheckpoint(ctx)
+	if err != nil {
+		return false, err
+	} else if checkpoint == nil {
+		return false, nil
+	}
+	logger := pr.getLogger()
+	if checkpoint.ELCClientID != pr.GetELCClientID() || checkpoint.ClientID != pathEnd.ClientID {
+		logger.Warn("discard the checkpoint of the activation of another client", "checkpoint_elc_client_id", checkpoint.ELCClientID, "checkpoint_client_id", checkpoint.ClientID)
+		return false, pr.removeActivateClientCheckpoint(ctx)
+	}
+	pending := checkpoint.pendingUpdates(result.InitialHeight)
+	if len(pending) == 0 {
+		logger.Info("discard the checkpoint of the completed activation", "client_latest_height", result.InitialHeight)
+		return false, pr.removeActivateClientCheckpoint(ctx)
+	}
+	logger.Info("resume the interrupted activation", "elc_client_id", pr.GetELCClientID(), "applied", len(checkpoint.Updates)-len(pending), "pending", len(pending))
+	result.Resumed = true
+	return true, pr.submitActivateClientUpdates(ctx, pathEnd, counterparty, checkpoint, pending, result)
+}
+
+// checkActivateClientFollowable returns an error if the updates generated from the ELC client would not follow the latest height of the LCP client,
+// i.e. the ELC client has been updated beyond the LCP client and there is no interrupted activation to resume.
+func (pr *Prover) checkActivateClientFollowable(ctx context.Context, clientHeight clienttypes.Height) error {
+	if clientHeight.IsZero() {
+		return nil
+	}
+	elcHeight, err := pr.queryELCLatestHeight(ctx, pr.GetELCClientID())
+	if err != nil {
+		return err
+	} else if elcHeight.GT(clientHeight) {
+		return fmt.Errorf("the ELC client has been updated beyond the LCP client and no interrupted activation can be resumed: elc_client_id=%v elc_latest_height=%v client_latest_height=%v", pr.GetELCClientID(), elcHeight, clientHeight)
+	}
+	return nil
+}
+
+// submitActivateClientUpdates submits `updates`, which are the pending ones of `checkpoint`, to the LCP client on `counterparty`.
+// The checkpoint is persisted before the submission and removed after all the updates are applied.
+// If the submission fails, the updates applied are counted with the latest height of the LCP client so that a next run resumes from the pending ones.
+func (pr *Prover) submitActivateClientUpdates(ctx context.Context, pathEnd *core.PathEnd, counterparty core.FinalityAwareChain, checkpoint *activateClientCheckpoint, updates []core.Header, result *ActivateClientResult) error {
+	if err := pr.saveActivateClientCheckpoint(ctx, checkpoint); err != nil {
+		return err
+	}
+	signer, err := counterparty.GetAddress()
+	if err != nil {
+		return err
+	}
+	var messages []ibcexported.ClientMessage
+	for _, update := range updates {
+		messages = append(messages, update)
+	}
+	msgs, err := pr.encodeClientMessages(pathEnd.ClientID, signer, messages...)
+	if err != nil {
+		return err
+	}
+
+	lastHeight := updates[len(updates)-1].GetHeight()
+	result.Submitted = len(updates)
+	_, applied, err := pr.sendMsgsWithRetry(counterparty, "activate_client", msgs, pr.clientUpdatedFunc(counterparty, lastHeight))
+	pr.reportValidationContextPrediction(ctx, counterparty)
+	if err != nil {
+		clientHeight, qerr := pr.queryCounterpartyClientHeight(ctx, counterparty)
+		if qerr != nil {
+			return fmt.Errorf("the activation is interrupted and the applied updates are unknown: %w", err)
+		}
+		pending := checkpoint.pendingUpdates(clientHeight)
+		result.Applied, result.Pending, result.LatestHeight = len(checkpoint.Updates)-len(pending), len(pending), clientHeight
+		if len(pending) > 0 {
+			return fmt.Errorf("the activation is interrupted: applied=%v pending=%v client_latest_height=%v; rerun it to resume from the pending updates: %w", result.Applied, result.Pending, clientHeight, err)
+		}
+		// the failed attempt has been applied although the submission is not confirmed
+		applied = true
+	}
+	if applied {
+		pr.getLogger().Info("the LCP client has been activated by a failed attempt", "elc_client_id", pr.GetELCClientID())
+	}
+	result.Applied, result.Pending, result.LatestHeight = len(checkpoint.Updates), 0, clienttypes.NewHeight(lastHeight.GetRevisionNumber(), lastHeight.GetRevisionHeight())
+	return pr.removeActivateClientCheckpoint(ctx)
+}
@@ -0,0 +1,158 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeActivateClient(t *testing.T) {
+	const elcClientID = "07-tendermint-0"
+	newProver := func(t *testing.T) *Prover {
+		key, eki := newTestEnclaveKey(t)
+		service := &mockLCPService{t: t, key: key, clients: map[string]*lcptypes.ClientState{
+			elcClientID: {LatestHeight: clienttypes.NewHeight(0, 5)},
+		}}
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = t.TempDir()
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.ElcClientId = elcClientID
+		pr.originProver = mockOverlappingOriginProver{
+			mockSelfTestOriginProver: mockSelfTestOriginProver{latestHeight: clienttypes.NewHeight(0, 8)},
+			headers:                  []core.Header{newTestOriginHeader(t, 6), newTestOriginHeader(t, 7), newTestOriginHeader(t, 8)},
+		}
+		pr.lcpServiceClient = LCPServiceClient{ELCMsgClient: service, ELCQueryClient: service}
+		pr.activeEnclaveKey = eki
+		require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr
+	}
+	// newActivation generates the updates from the height 5 to 8 and returns the checkpoint of them
+	newActivation := func(t *testing.T, pr *Prover, counterparty *mockCounterparty) (*activateClientCheckpoint, []core.Header) {
+		updates, err := pr.setupHeadersForUpdate(context.TODO(), counterparty, mockHeader{height: clienttypes.NewHeight(0, 8)})
+		require.NoError(t, err)
+		require.Len(t, updates, 3)
+		checkpoint := &activateClientCheckpoint{ELCClientID: elcClientID, ClientID: counterparty.Path().ClientID}
+		for _, update := range updates {
+			checkpoint.Updates = append(checkpoint.Updates, update.(*lcptypes.UpdateClientMessage))
+		}
+		return checkpoint, updates
+	}
+	newCounterparty := func() *mockCounterparty {
+		cp := newMockCounterparty(clienttypes.NewHeight(0, 5))
+		cp.latestHeight = clienttypes.NewHeight(0, 10)
+		cp.clientState = &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, 5)}
+		return cp
+	}
+
+	t.Run("mid-batch failure and resume", func(t *testing.T) {
+		require := require.New(t)
+		pr := newProver(t)
+		cp := newCounterparty()
+		checkpoint, updates := newActivation(t, pr, cp)
+
+		// the first update is applied before the submission fails
+		cp.sendMsgsErr = func(msgs []sdk.Msg) error {
+			cp.clientState.LatestHeight = clienttypes.NewHeight(0, 6)
+			return errors.New("out of gas")
+		}
+		result := &ActivateClientResult{InitialHeight: clienttypes.NewHeight(0, 5)}
+		err := pr.submitActivateClientUpdates(context.TODO(), cp.Path(), cp, checkpoint, updates, result)
+		require.ErrorContains(err, "applied=1 pending=2")
+		require.ErrorContains(err, "out of gas")
+		require.Equal(3, result.Submitted)
+		require.Equal(1, result.Applied)
+		require.Equal(2, result.Pending)
+		require.Equal(clienttypes.NewHeight(0, 6), result.LatestHeight)
+
+		// the ELC client is ahead of the LCP client, so new updates cannot be generated
+		require.ErrorContains(pr.checkActivateClientFollowable(context.TODO(), cp.clientState.LatestHeight), "elc_latest_height=0-8 client_latest_height=0-6")
+
+		// the rerun submits only the pending updates
+		cp.sendMsgsErr = nil
+		result = &ActivateClientResult{InitialHeight: cp.clientState.LatestHeight}
+		resumed, err := pr.resumeActivateClient(context.TODO(), cp.Path(), cp, result)
+		require.NoError(err)
+		require.True(resumed)
+		require.True(result.Resumed)
+		require.Equal(2, result.Submitted)
+		require.Equal(3, result.Applied)
+		require.Equal(0, result.Pending)
+		require.Equal(clienttypes.NewHeight(0, 8), result.LatestHeight)
+		require.Len(cp.sentMsgs, 1)
+		require.Len(cp.sentMsgs[0], 2)
+		for i, msg := range cp.sentMsgs[0] {
+			var message *lcptypes.UpdateClientMessage
+			require.NoError(pr.codec.UnpackAny(msg.(*clienttypes.MsgUpdateClient).ClientMessage, &message))
+			require.Equal(updates[i+1], message)
+		}
+
+		// the checkpoint is removed after the activation completes
+		checkpoint, err = pr.loadActivateClientCheckpoint(context.TODO())
+		require.NoError(err)
+		require.Nil(checkpoint)
+		resumed, err = pr.resumeActivateClient(context.TODO(), cp.Path(), cp, &ActivateClientResult{})
+		require.NoError(err)
+		require.False(resumed)
+	})
+
+	t.Run("failure applied by the counterparty", func(t *testing.T) {
+		require := require.New(t)
+		pr := newProver(t)
+		cp := newCounterparty()
+		checkpoint, updates := newActivation(t, pr, cp)
+
+		cp.sendMsgsErr = func(msgs []sdk.Msg) error {
+			cp.clientState.LatestHeight = clienttypes.NewHeight(0, 8)
+			return errors.New("timed out waiting for the tx")
+		}
+		result := &ActivateClientResult{}
+		require.NoError(pr.submitActivateClientUpdates(context.TODO(), cp.Path(), cp, checkpoint, updates, result))
+		require.Equal(3, result.Applied)
+		require.Equal(0, result.Pending)
+		checkpoint, err := pr.loadActivateClientCheckpoint(context.TODO())
+		require.NoError(err)
+		require.Nil(checkpoint)
+	})
+
+	t.Run("checkpoint of a completed activation", func(t *testing.T) {
+		require := require.New(t)
+		pr := newProver(t)
+		cp := newCounterparty()
+		checkpoint, _ := newActivation(t, pr, cp)
+		require.NoError(pr.saveActivateClientCheckpoint(context.TODO(), checkpoint))
+
+		// the LCP client has been updated by another process
+		resumed, err := pr.resumeActivateClient(context.TODO(), cp.Path(), cp, &ActivateClientResult{InitialHeight: clienttypes.NewHeight(0, 8)})
+		require.NoError(err)
+		require.False(resumed)
+		require.Zero(cp.sendMsgsCalls)
+		checkpoint, err = pr.loadActivateClientCheckpoint(context.TODO())
+		require.NoError(err)
+		require.Nil(checkpoint)
+	})
+
+	t.Run("checkpoint of another client", func(t *testing.T) {
+		require := require.New(t)
+		pr := newProver(t)
+		cp := newCounterparty()
+		checkpoint, _ := newActivation(t, pr, cp)
+		checkpoint.ClientID = "lcp-client-1"
+		require.NoError(pr.saveActivateClientCheckpoint(context.TODO(), checkpoint))
+
+		resumed, err := pr.resumeActivateClient(context.TODO(), cp.Path(), cp, &ActivateClientResult{InitialHeight: clienttypes.NewHeight(0, 5)})
+		require.NoError(err)
+		require.False(resumed)
+		require.Zero(cp.sendMsgsCalls)
+		checkpoint, err = pr.loadActivateClientCheckpoint(context.TODO())
+		require.NoError(err)
+		require.Nil(checkpoint)
+	})
+}
@@ -0,0 +1,269 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+const (
+	DefaultAlertDedupInterval  = 10 * 60 // seconds
+	DefaultAlertWebhookTimeout = 10 * time.Second
+)
+
+// AlertCondition is a critical condition of the prover that operators should be notified of
+type AlertCondition string
+
+const (
+	// no enclave key in the LCP service is eligible to be registered
+	AlertNoEligibleEnclaveKey AlertCondition = "no_eligible_enclave_key"
+	// the tx registering an enclave key failed
+	AlertRegistrationFailed AlertCondition = "registration_failed"
+	// the state of the ELC diverges from the one of the counterparty LCP client
+	AlertStateDivergence AlertCondition = "state_divergence"
+	// the counterparty LCP client is frozen or has not been updated within the key expiration
+	AlertCounterpartyClientInactive AlertCondition = "counterparty_client_inactive"
+	// the trusting period of the validation context ends soon
+	AlertValidationContextExpiring AlertCondition = "validation_context_expiring"
+	// the latest height of the counterparty LCP client is lower than the last observed one, e.g. after a rollback of the counterparty chain
+	AlertCounterpartyClientHeightRegressed AlertCondition = "counterparty_client_height_regressed"
+	// the timestamp of an update emitted by the ELC is lower than the one of a lower height
+	AlertTimestampRegressed AlertCondition = "timestamp_regressed"
+	// the quote status or the advisory IDs of a fresh attestation of the enclave drift from the ones of the active enclave key
+	AlertAttestationPolicyDrift AlertCondition = "attestation_policy_drift"
+	// the key expiration of the config differs from the one of the counterparty LCP client
+	AlertKeyExpirationMismatch AlertCondition = "key_expiration_mismatch"
+	// the clock of the relayer host is skewed from the one of the counterparty chain beyond the threshold
+	AlertClockSkewed AlertCondition = "clock_skewed"
+	// an enclave key registration has been unfinalized longer than expected, e.g. the counterparty chain halts
+	AlertUnfinalizedKeyStuck AlertCondition = "unfinalized_key_stuck"
+)
+
+// Alert is a notification of a critical condition
+type Alert struct {
+	Condition AlertCondition `json:"condition"`
+	ChainID   string         `json:"chain_id"`
+	// the subject of the condition, e.g. a client ID. It is used for the deduplication with the condition and the chain ID.
+	Subject   string            `json:"subject"`
+	Message   string            `json:"message"`
+	Details   map[string]string `json:"details,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+	// the version of lcp-go which emitted the alert
+	RelayerVersion string `json:"relayer_version"`
+}
+
+func (a Alert) dedupKey() string {
+	return fmt.Sprintf("%v/%v/%v", a.Condition, a.ChainID, a.Subject)
+}
+
+// Notifier delivers alerts
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// LogNotifier emits alerts to the log as structured records
+type LogNotifier struct {
+	logger *log.RelayLogger
+}
+
+var _ Notifier = (*LogNotifier)(nil)
+
+func NewLogNotifier(logger *log.RelayLogger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, alert Alert) error {
+	n.logger.Warn("alert", "condition", alert.Condition, "chain_id", alert.ChainID, "subject", alert.Subject, "message", alert.Message, "details", alert.Details)
+	return nil
+}
+
+// defaultAlertPayloadTemplate renders the alert as JSON
+const defaultAlertPayloadTemplate = `{{ json . }}`
+
+// WebhookNotifier posts alerts to a URL as JSON payloads rendered by a template
+type WebhookNotifier struct {
+	url      string
+	template *template.Template
+	client   *http.Client
+}
+
+var _ Notifier = (*WebhookNotifier)(nil)
+
+// NewWebhookNotifier returns a notifier posting to `url`.
+// `payloadTemplate` is a Go text/template executed with the Alert, and the `json` function is available to encode values.
+// If `payloadTemplate` is empty, the alert itself is encoded as JSON.
+func NewWebhookNotifier(url string, payloadTemplate string, timeout time.Duration) (*WebhookNotifier, error) {
+	tmpl, err := parseAlertPayloadTemplate(payloadTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookNotifier{
+		url:      url,
+		template: tmpl,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func parseAlertPayloadTemplate(payloadTemplate string) (*template.Template, error) {
+	if payloadTemplate == "" {
+		payloadTemplate = defaultAlertPayloadTemplate
+	}
+	tmpl, err := template.New("alert").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			bz, err := json.Marshal(v)
+			return string(bz), err
+		},
+	}).Parse(payloadTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the alert payload template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	var buf bytes.Buffer
+	if err := n.template.Execute(&buf, alert); err != nil {
+		return fmt.Errorf("failed to render the alert payload: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return fmt.Errorf("the rendered alert payload is not a valid JSON: payload=%s", buf.String())
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post the alert: url=%v %w", n.url, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: url=%v status=%v", n.url, res.StatusCode)
+	}
+	return nil
+}
+
+// alerter delivers alerts to the notifiers in the background and suppresses the same alert within the dedup interval.
+// A delivery failure is only logged so that it never affects the relay.
+type alerter struct {
+	notifiers     []Notifier
+	dedupInterval time.Duration
+	logger        *log.RelayLogger
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+	wg       sync.WaitGroup
+}
+
+func newAlerter(logger *log.RelayLogger, dedupInterval time.Duration, notifiers ...Notifier) *alerter {
+	return &alerter{
+		notifiers:     notifiers,
+		dedupInterval: dedupInterval,
+		logger:        logger,
+		lastSent:      make(map[string]time.Time),
+	}
+}
+
+// send delivers the alert unless the same alert was sent within the dedup interval.
+// It returns false if the alert is suppressed.
+func (a *alerter) send(alert Alert) bool {
+	key := alert.dedupKey()
+	a.mu.Lock()
+	if last, ok := a.lastSent[key]; ok && alert.Timestamp.Sub(last) < a.dedupInterval {
+		a.mu.Unlock()
+		return false
+	}
+	a.lastSent[key] = alert.Timestamp
+	a.mu.Unlock()
+
+	for _, n := range a.notifiers {
+		a.wg.Add(1)
+		go func(n Notifier) {
+			defer a.wg.Done()
+			if err := n.Notify(context.Background(), alert); err != nil {
+				a.logger.Warn("failed to deliver the alert", "condition", alert.Condition, "subject", alert.Subject, "error", err)
+			}
+		}(n)
+	}
+	return true
+}
+
+// wait waits for the in-flight deliveries
+func (a *alerter) wait() {
+	a.wg.Wait()
+}
+
+func newProverAlerter(config ProverConfig) (*alerter, error) {
+	logger := log.GetLogger().WithModule(ModuleName)
+	notifiers := []Notifier{NewLogNotifier(logger)}
+	if config.AlertWebhookUrl != "" {
+		n, err := NewWebhookNotifier(config.AlertWebhookUrl, config.AlertPayloadTemplate, DefaultAlertWebhookTimeout)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, n)
+	}
+	return newAlerter(logger, config.GetAlertDedupInterval(), notifiers...), nil
+}
+
+// alert notifies the operators of the condition. `details` are key-value pairs.
+func (pr *Prover) alert(condition AlertCondition, subject string, message string, details ...interface{}) {
+	alert := Alert{
+		Condition:      condition,
+		Subject:        subject,
+		Message:        message,
+		Details:        make(map[string]string),
+		Timestamp:      time.Now(),
+		RelayerVersion: GetBuildInfo().String(),
+	}
+	if pr.originChain != nil {
+		alert.ChainID = pr.originChain.ChainID()
+	}
+	for i := 0; i+1 < len(details); i += 2 {
+		alert.Details[fmt.Sprint(details[i])] = fmt.Sprint(details[i+1])
+	}
+	if pr.alerter == nil {
+		pr.getLogger().Warn("alert", "condition", condition, "subject", subject, "message", message, "details", alert.Details)
+		return
+	}
+	pr.alerter.send(alert)
+}
+
+// counterpartyClientID returns the ID of the LCP client on the counterparty chain if the relay info is set
+func (pr *Prover) counterpartyClientID() string {
+	if pr.counterparty == nil {
+		return ""
+	}
+	return pr.counterparty.Path().ClientID
+}
+
+// checkValidationContextExpiry emits an alert if the trusting period of the validation context of the update ends within the margin.
+// After the end, the update can no longer be verified on the counterparty chain.
+func (pr *Prover) checkValidationContextExpiry(msg *lcptypes.HeaderedProxyMessage, now time.Time) {
+	if msg.Type != lcptypes.LCPMessageTypeUpdateState {
+		return
+	}
+	usm, err := msg.GetUpdateStateProxyMessage()
+	if err != nil {
+		return
+	}
+	vc, ok := usm.Context.(*lcptypes.TrustingPeriodValidationContext)
+	if !ok || !vc.TrustingPeriod.IsInt64() {
+		return
+	}
+	trustingPeriod := time.Duration(vc.TrustingPeriod.Int64())
+	end := vc.TrustedStateTimestamp.Add(trustingPeriod)
+	if remaining := end.Sub(now); remaining < pr.config.GetAlertValidationContextMargin(trustingPeriod) {
+		pr.alert(AlertValidationContextExpiring, pr.GetELCClientID(), "the trusting period of the validation context ends soon",
+			"trusting_period_end", end, "remaining", remaining, "post_height", usm.PostHeight)
+	}
+}
@@ -0,0 +1,143 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/require"
+)
+
+// newAlertCaptureServer returns a server capturing the posted payloads
+func newAlertCaptureServer(t *testing.T, status int) (*httptest.Server, func() [][]byte) {
+	var (
+		mu       sync.Mutex
+		payloads [][]byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bz, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		mu.Lock()
+		payloads = append(payloads, bz)
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() [][]byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([][]byte{}, payloads...)
+	}
+}
+
+func newTestAlert(subject string, timestamp time.Time) Alert {
+	return Alert{
+		Condition: AlertNoEligibleEnclaveKey,
+		ChainID:   "origin",
+		Subject:   subject,
+		Message:   "message",
+		Details:   map[string]string{"key": "value"},
+		Timestamp: timestamp,
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	alert := newTestAlert("subject", time.Unix(1700000000, 0).UTC())
+
+	t.Run("default payload", func(t *testing.T) {
+		srv, payloads := newAlertCaptureServer(t, http.StatusOK)
+		n, err := NewWebhookNotifier(srv.URL, "", time.Second)
+		require.NoError(t, err)
+		require.NoError(t, n.Notify(context.Background(), alert))
+		require.Len(t, payloads(), 1)
+		var actual Alert
+		require.NoError(t, json.Unmarshal(payloads()[0], &actual))
+		require.Equal(t, alert, actual)
+	})
+	t.Run("custom template", func(t *testing.T) {
+		srv, payloads := newAlertCaptureServer(t, http.StatusOK)
+		n, err := NewWebhookNotifier(srv.URL, `{"text": {{ json (printf "[%s] %s: %s" .ChainID .Condition .Message) }}}`, time.Second)
+		require.NoError(t, err)
+		require.NoError(t, n.Notify(context.Background(), alert))
+		require.JSONEq(t, `{"text": "[origin] no_eligible_enclave_key: message"}`, string(payloads()[0]))
+	})
+	t.Run("invalid JSON payload", func(t *testing.T) {
+		srv, payloads := newAlertCaptureServer(t, http.StatusOK)
+		n, err := NewWebhookNotifier(srv.URL, `{"text": {{ .Message }}}`, time.Second)
+		require.NoError(t, err)
+		require.Error(t, n.Notify(context.Background(), alert))
+		require.Empty(t, payloads())
+	})
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := NewWebhookNotifier("http://localhost", `{{ .Message`, time.Second)
+		require.Error(t, err)
+	})
+	t.Run("error status", func(t *testing.T) {
+		srv, _ := newAlertCaptureServer(t, http.StatusInternalServerError)
+		n, err := NewWebhookNotifier(srv.URL, "", time.Second)
+		require.NoError(t, err)
+		require.Error(t, n.Notify(context.Background(), alert))
+	})
+}
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(context.Context, Alert) error {
+	return errors.New("failed to deliver")
+}
+
+func TestAlerterDedup(t *testing.T) {
+	require.NoError(t, log.InitLogger("DEBUG", "text", "stdout"))
+	srv, payloads := newAlertCaptureServer(t, http.StatusOK)
+	n, err := NewWebhookNotifier(srv.URL, "", time.Second)
+	require.NoError(t, err)
+	// a failing notifier does not affect the others
+	a := newAlerter(log.GetLogger(), time.Minute, failingNotifier{}, n)
+
+	now := time.Unix(1700000000, 0)
+	require.True(t, a.send(newTestAlert("a", now)))
+	require.False(t, a.send(newTestAlert("a", now.Add(30*time.Second))))
+	require.True(t, a.send(newTestAlert("b", now.Add(30*time.Second))))
+	require.True(t, a.send(newTestAlert("a", now.Add(time.Minute))))
+	a.wait()
+
+	var subjects []string
+	for _, bz := range payloads() {
+		var alert Alert
+		require.NoError(t, json.Unmarshal(bz, &alert))
+		subjects = append(subjects, alert.Subject)
+	}
+	require.ElementsMatch(t, []string{"a", "b", "a"}, subjects)
+}
+
+func TestAlertNoEligibleEnclaveKey(t *testing.T) {
+	srv, payloads := newAlertCaptureServer(t, http.StatusOK)
+	pr := newTestProver(t)
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	pr.config.AlertWebhookUrl = srv.URL
+	alerter, err := newProverAlerter(pr.config)
+	require.NoError(t, err)
+	pr.alerter = alerter
+
+	// the mock LCP service has no keys
+	for i := 0; i < 2; i++ {
+		_, err = pr.selectNewEnclaveKey(context.Background())
+		require.Error(t, err)
+	}
+	pr.alerter.wait()
+
+	// the second alert is suppressed
+	require.Len(t, payloads(), 1)
+	var alert Alert
+	require.NoError(t, json.Unmarshal(payloads()[0], &alert))
+	require.Equal(t, AlertNoEligibleEnclaveKey, alert.Condition)
+	require.Equal(t, "origin", alert.ChainID)
+}
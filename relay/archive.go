@@ -0,0 +1,246 @@
+package relay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const archivedProofFileExt = ".json"
+
+// ErrArchivedProofMismatch is returned if the archived proof does not commit to the archived path, value or height
+var ErrArchivedProofMismatch = errors.New("archived proof mismatch")
+
+// ArchivedProof is a record of a proof generated by ProveState
+type ArchivedProof struct {
+	ELCClientID string             `json:"elc_client_id"`
+	Path        string             `json:"path"`
+	Value       hexutil.Bytes      `json:"value"`
+	ProofHeight clienttypes.Height `json:"proof_height"`
+	// ABI-encoded CommitmentProofs
+	Proof     hexutil.Bytes `json:"proof"`
+	Signer    hexutil.Bytes `json:"signer"`
+	Timestamp time.Time     `json:"timestamp"`
+	// the version of lcp-go which generated the proof
+	RelayerVersion string `json:"relayer_version,omitempty"`
+}
+
+// proofArchive stores the archived proofs as files in a directory.
+// The file names start with the timestamp so that the lexical order is the chronological order.
+type proofArchive struct {
+	dir        string
+	maxEntries uint64
+	retention  time.Duration
+}
+
+func newProofArchive(dir string, maxEntries uint64, retention time.Duration) *proofArchive {
+	return &proofArchive{
+		dir:        dir,
+		maxEntries: maxEntries,
+		retention:  retention,
+	}
+}
+
+// put writes the proof into the archive and removes the proofs exceeding the retention limits
+func (a *proofArchive) put(p *ArchivedProof) (string, error) {
+	if err := os.MkdirAll(a.dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create the proof archive directory: dir=%v %w", a.dir, err)
+	}
+	bz, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the archived proof: %w", err)
+	}
+	// the hash suffix avoids collisions between proofs archived at the same time
+	name := fmt.Sprintf("%020d-%x%s", p.Timestamp.UnixNano(), crypto.Keccak256(bz)[:4], archivedProofFileExt)
+	path := filepath.Join(a.dir, name)
+	if err := os.WriteFile(path, bz, 0600); err != nil {
+		return "", fmt.Errorf("failed to write the archived proof: path=%v %w", path, err)
+	}
+	if err := a.rotate(p.Timestamp); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// list returns the file names of the archived proofs in chronological order
+func (a *proofArchive) list() ([]string, error) {
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read the proof archive directory: dir=%v %w", a.dir, err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Type().IsRegular() && strings.HasSuffix(e.Name(), archivedProofFileExt) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// archivedProofTime returns the time when the proof was archived, which is encoded in the file name
+func archivedProofTime(name string) (time.Time, error) {
+	prefix, _, ok := strings.Cut(name, "-")
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid archived proof file name: name=%v", name)
+	}
+	nanos, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid archived proof file name: name=%v %w", name, err)
+	}
+	return time.Unix(0, nanos), nil
+}
+
+// remove removes the archived proofs of `names`
+func (a *proofArchive) remove(names []string) error {
+	for _, name := range names {
+		if err := os.Remove(filepath.Join(a.dir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove the archived proof: name=%v %w", name, err)
+		}
+	}
+	return nil
+}
+
+// rotate removes the proofs older than the retention period and the oldest proofs exceeding the maximum number of entries
+func (a *proofArchive) rotate(now time.Time) error {
+	names, err := a.list()
+	if err != nil {
+		return err
+	}
+	var expired int
+	if a.retention > 0 {
+		threshold := fmt.Sprintf("%020d", now.Add(-a.retention).UnixNano())
+		expired = sort.Search(len(names), func(i int) bool {
+			return names[i] >= threshold
+		})
+	}
+	if a.maxEntries > 0 && uint64(len(names)-expired) > a.maxEntries {
+		expired = len(names) - int(a.maxEntries)
+	}
+	return a.remove(names[:expired])
+}
+
+// LoadArchivedProof loads the archived proof from the file
+func LoadArchivedProof(path string) (*ArchivedProof, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the archived proof: path=%v %w", path, err)
+	}
+	var p ArchivedProof
+	if err := json.Unmarshal(bz, &p); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the archived proof: path=%v %w", path, err)
+	}
+	return &p, nil
+}
+
+// ReplayResult is a result of the replay verification of an archived proof
+type ReplayResult struct {
+	Signers   []common.Address   `json:"signers"`
+	Version   uint16             `json:"version"`
+	Prefix    string             `json:"prefix"`
+	Path      string             `json:"path"`
+	ValueHash hexutil.Bytes      `json:"value_hash"`
+	Height    clienttypes.Height `json:"height"`
+	StateID   string             `json:"state_id"`
+}
+
+// ReplayArchivedProof verifies the signatures of the archived proof
+// and checks that the message commits to the archived path, value and height.
+func ReplayArchivedProof(p *ArchivedProof) (*ReplayResult, error) {
+	cp, err := lcptypes.EthABIDecodeCommitmentProofs(p.Proof)
+	if err != nil {
+		return nil, err
+	}
+	if len(cp.Signatures) == 0 {
+		return nil, fmt.Errorf("%w: no signatures", ErrInvalidEnclaveSignature)
+	}
+	var signers []common.Address
+	for i, sig := range cp.Signatures {
+		addr, err := lcptypes.VerifySignature(cp.Message, sig)
+		if err != nil {
+			return nil, fmt.Errorf("%w: index=%v %v", ErrInvalidEnclaveSignature, i, err)
+		}
+		signers = append(signers, addr)
+	}
+	if expected := common.BytesToAddress(p.Signer); signers[0] != expected {
+		return nil, fmt.Errorf("%w: expected=%v actual=%v", ErrInvalidEnclaveSignature, expected.Hex(), signers[0].Hex())
+	}
+	headered, err := cp.GetMessage()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := headered.GetVerifyMembershipProxyMessage()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(msg.Prefix, []byte(exported.StoreKey)) || string(msg.Path) != p.Path {
+		return nil, fmt.Errorf("%w: prefix=%s path=%s expected_path=%v", ErrArchivedProofMismatch, msg.Prefix, msg.Path, p.Path)
+	}
+	// a proof of an empty value is a non-membership proof, which commits to the zero hash
+	expectedValueHash := common.Hash{}
+	if len(p.Value) > 0 {
+		expectedValueHash = crypto.Keccak256Hash(p.Value)
+	}
+	if expectedValueHash != msg.Value {
+		return nil, fmt.Errorf("%w: value_hash=%v expected_value_hash=%v", ErrArchivedProofMismatch, lcptypes.HexBytes(msg.Value[:]), expectedValueHash)
+	}
+	if !msg.Height.EQ(p.ProofHeight) {
+		return nil, fmt.Errorf("%w: height=%v expected_height=%v", ErrArchivedProofMismatch, msg.Height, p.ProofHeight)
+	}
+	return &ReplayResult{
+		Signers:   signers,
+		Version:   headered.Version,
+		Prefix:    string(msg.Prefix),
+		Path:      string(msg.Path),
+		ValueHash: msg.Value[:],
+		Height:    msg.Height,
+		StateID:   msg.StateID.String(),
+	}, nil
+}
+
+func (pr *Prover) proofArchive() *proofArchive {
+	if pr.config.ProofArchiveDir == "" {
+		return nil
+	}
+	dir := pr.config.ProofArchiveDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(pr.homePath, dir)
+	}
+	return newProofArchive(dir, pr.config.ProofArchiveMaxEntries, time.Duration(pr.config.ProofArchiveRetention)*time.Second)
+}
+
+// archiveProof archives the proof if the proof archive is enabled.
+// An error is returned only if ProofArchiveFailClosed is true.
+func (pr *Prover) archiveProof(p *ArchivedProof) error {
+	archive := pr.proofArchive()
+	if archive == nil {
+		return nil
+	}
+	path, err := archive.put(p)
+	if err != nil {
+		if pr.config.ProofArchiveFailClosed {
+			return err
+		}
+		pr.getLogger().Warn("failed to archive the proof", "path", p.Path, "proof_height", p.ProofHeight, "error", err)
+		return nil
+	}
+	pr.getLogger().Debug("archived the proof", "file", path)
+	return nil
+}
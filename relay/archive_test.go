@@ -0,0 +1,181 @@
+package relay
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type testABIHeight struct {
+	RevisionNumber uint64 `json:"revision_number"`
+	RevisionHeight uint64 `json:"revision_height"`
+}
+
+// newTestVerifyMembershipMessage returns an ABI-encoded headered VerifyMembership message
+func newTestVerifyMembershipMessage(t *testing.T, path string, value []byte, height clienttypes.Height) []byte {
+	return newTestStateCommitmentMessage(t, path, crypto.Keccak256Hash(value), height)
+}
+
+// newTestVerifyNonMembershipMessage returns an ABI-encoded headered VerifyNonMembership message, whose value is zero
+func newTestVerifyNonMembershipMessage(t *testing.T, path string, height clienttypes.Height) []byte {
+	return newTestStateCommitmentMessage(t, path, [32]byte{}, height)
+}
+
+func newTestStateCommitmentMessage(t *testing.T, path string, valueHash [32]byte, height clienttypes.Height) []byte {
+	heightComponents := []abi.ArgumentMarshaling{
+		{Name: "revision_number", Type: "uint64"},
+		{Name: "revision_height", Type: "uint64"},
+	}
+	messageABI, err := abi.NewType("tuple", "struct VerifyMembershipMessage", []abi.ArgumentMarshaling{
+		{Name: "prefix", Type: "bytes"},
+		{Name: "path", Type: "bytes"},
+		{Name: "value", Type: "bytes32"},
+		{Name: "height", Type: "tuple", Components: heightComponents},
+		{Name: "state_id", Type: "bytes32"},
+	})
+	require.NoError(t, err)
+	message, err := abi.Arguments{{Type: messageABI}}.Pack(struct {
+		Prefix  []byte        `json:"prefix"`
+		Path    []byte        `json:"path"`
+		Value   [32]byte      `json:"value"`
+		Height  testABIHeight `json:"height"`
+		StateId [32]byte      `json:"state_id"`
+	}{
+		Prefix: []byte(exported.StoreKey),
+		Path:   []byte(path),
+		Value:  valueHash,
+		Height: testABIHeight{RevisionNumber: height.RevisionNumber, RevisionHeight: height.RevisionHeight},
+	})
+	require.NoError(t, err)
+
+	headeredABI, err := abi.NewType("tuple", "struct HeaderedMessage", []abi.ArgumentMarshaling{
+		{Name: "header", Type: "bytes32"},
+		{Name: "message", Type: "bytes"},
+	})
+	require.NoError(t, err)
+	var header [32]byte
+	binary.BigEndian.PutUint16(header[:2], lcptypes.LCPMessageVersion)
+	binary.BigEndian.PutUint16(header[2:4], lcptypes.LCPMessageTypeState)
+	bz, err := abi.Arguments{{Type: headeredABI}}.Pack(struct {
+		Header  [32]byte `json:"header"`
+		Message []byte   `json:"message"`
+	}{Header: header, Message: message})
+	require.NoError(t, err)
+	return bz
+}
+
+func newTestArchivedProof(t *testing.T, key *ecdsa.PrivateKey, timestamp time.Time) *ArchivedProof {
+	path, value, height := "commitments/ports/transfer/channels/channel-0/sequences/1", []byte("value"), clienttypes.NewHeight(0, 100)
+	message := newTestVerifyMembershipMessage(t, path, value, height)
+	signature, err := crypto.Sign(crypto.Keccak256(message), key)
+	require.NoError(t, err)
+	proof, err := lcptypes.EthABIEncodeCommitmentProofs(&lcptypes.CommitmentProofs{
+		Message:    message,
+		Signatures: [][]byte{signature},
+	})
+	require.NoError(t, err)
+	return &ArchivedProof{
+		ELCClientID: "07-tendermint-0",
+		Path:        path,
+		Value:       value,
+		ProofHeight: height,
+		Proof:       proof,
+		Signer:      crypto.PubkeyToAddress(key.PublicKey).Bytes(),
+		Timestamp:   timestamp,
+	}
+}
+
+func TestProofArchiveRotation(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+
+	var cases = []struct {
+		name       string
+		maxEntries uint64
+		retention  time.Duration
+		expected   int
+	}{
+		{"unlimited", 0, 0, 5},
+		{"max entries", 3, 0, 3},
+		{"retention", 0, 150 * time.Second, 3},
+		{"max entries within retention", 1, 150 * time.Second, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			archive := newProofArchive(t.TempDir(), c.maxEntries, c.retention)
+			var files []string
+			// archive a proof every minute
+			for i := 0; i < 5; i++ {
+				file, err := archive.put(newTestArchivedProof(t, key, now.Add(time.Duration(i)*time.Minute)))
+				require.NoError(t, err)
+				files = append(files, filepath.Base(file))
+			}
+			names, err := archive.list()
+			require.NoError(t, err)
+			// the newest proofs remain
+			require.Equal(t, files[len(files)-c.expected:], names)
+		})
+	}
+}
+
+func TestReplayArchivedProof(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	archive := newProofArchive(t.TempDir(), 0, 0)
+	file, err := archive.put(newTestArchivedProof(t, key, time.Now()))
+	require.NoError(t, err)
+
+	p, err := LoadArchivedProof(file)
+	require.NoError(t, err)
+	res, err := ReplayArchivedProof(p)
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), res.Signers[0])
+	require.Equal(t, p.Path, res.Path)
+	require.Equal(t, p.ProofHeight, res.Height)
+
+	t.Run("unexpected signer", func(t *testing.T) {
+		other, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		p := *p
+		p.Signer = crypto.PubkeyToAddress(other.PublicKey).Bytes()
+		_, err = ReplayArchivedProof(&p)
+		require.ErrorIs(t, err, ErrInvalidEnclaveSignature)
+	})
+	t.Run("tampered value", func(t *testing.T) {
+		p := *p
+		p.Value = []byte("tampered")
+		_, err := ReplayArchivedProof(&p)
+		require.ErrorIs(t, err, ErrArchivedProofMismatch)
+	})
+	t.Run("tampered height", func(t *testing.T) {
+		p := *p
+		p.ProofHeight = clienttypes.NewHeight(0, 101)
+		_, err := ReplayArchivedProof(&p)
+		require.ErrorIs(t, err, ErrArchivedProofMismatch)
+	})
+}
+
+func TestArchiveProofFailOpen(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	// a regular file in place of the archive directory makes the archiving fail
+	dir := filepath.Join(t.TempDir(), "archive")
+	require.NoError(t, os.WriteFile(dir, nil, 0600))
+
+	pr := newTestProver(t)
+	pr.config.ProofArchiveDir = dir
+	require.NoError(t, pr.archiveProof(newTestArchivedProof(t, key, time.Now())))
+	pr.config.ProofArchiveFailClosed = true
+	require.Error(t, pr.archiveProof(newTestArchivedProof(t, key, time.Now())))
+}
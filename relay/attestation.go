@@ -0,0 +1,279 @@
+package relay
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/datachainlab/lcp-go/sgx/dcap"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/common"
+	oias "github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+)
+
+// AttestationType is the type of the attestation of an enclave key in the LCP service
+type AttestationType string
+
+const (
+	// AttestationTypeIAS is the EPID attestation with the AVR of IAS
+	AttestationTypeIAS AttestationType = "ias"
+	// AttestationTypeDCAP is the DCAP attestation with the ECDSA quote and the collateral of Intel PCS
+	AttestationTypeDCAP AttestationType = "dcap"
+)
+
+// ErrDCAPAttestationDisabled is returned if an enclave key has a DCAP attestation, but no DCAP root certificates are configured
+var ErrDCAPAttestationDisabled = errors.New("DCAP attestations are disabled: no DCAP root certificates are configured")
+
+// GetAttestationType returns the type of the attestation of `eki`.
+// The LCP service sets the AVR of an EPID attestation to Report,
+// and the encoded lcptypes.DCAPAttestation of a DCAP attestation to Extension.
+func GetAttestationType(eki *enclave.EnclaveKeyInfo) (AttestationType, error) {
+	switch {
+	case eki.Report != "":
+		return AttestationTypeIAS, nil
+	case len(eki.Extension) > 0:
+		return AttestationTypeDCAP, nil
+	default:
+		return "", fmt.Errorf("the enclave key has no attestation: enclave_key=%v", lcptypes.HexBytes(eki.EnclaveKeyAddress))
+	}
+}
+
+// attestation is the attestation of an enclave key verified by the prover
+type attestation struct {
+	Type  AttestationType
+	Quote *oias.Quote
+	// the quote status in the vocabulary of IAS, see dcap.TCBStatus.QuoteStatus for the DCAP attestations
+	QuoteStatus string
+	// sorted in ascending order
+	AdvisoryIDs []string
+	// the attestation time from which the LCP client computes the expiration of the key
+	AttestationTime time.Time
+
+	report string
+	// the attestation of the AttestationTypeDCAP type
+	dcap *lcptypes.DCAPAttestation
+}
+
+// verifyAttestation verifies the attestation of `eki` at `now`.
+// The verified AVRs of the EPID attestations are cached.
+func (pr *Prover) verifyAttestation(eki *enclave.EnclaveKeyInfo, now time.Time) (*attestation, error) {
+	typ, err := GetAttestationType(eki)
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case AttestationTypeIAS:
+		att, err := pr.avrCache.verify(eki, now)
+		if err != nil {
+			return nil, err
+		}
+		// the revocation is checked every time because the CRL may be refreshed
+		if err := pr.checkSigningCertRevocation(eki, now); err != nil {
+			return nil, fmt.Errorf("failed to check the revocation of the signing certificate: %w", err)
+		}
+		return att, nil
+	default:
+		a, err := decodeDCAPAttestation(eki)
+		if err != nil {
+			return nil, err
+		}
+		return pr.verifyDCAPAttestation(a, now)
+	}
+}
+
+// verifyAttestationAtAttestationTime verifies the attestation of `eki` at its attestation time, i.e. regardless of the expiration of the collateral.
+// The AVR of an EPID attestation is only parsed.
+func (pr *Prover) verifyAttestationAtAttestationTime(eki *enclave.EnclaveKeyInfo) (*attestation, error) {
+	typ, err := GetAttestationType(eki)
+	if err != nil {
+		return nil, err
+	} else if typ == AttestationTypeIAS {
+		return parseIASAttestation(eki)
+	}
+	a, err := decodeDCAPAttestation(eki)
+	if err != nil {
+		return nil, err
+	}
+	tcbInfo, err := dcap.UnsafeDecodeTCBInfo(a.TcbInfo)
+	if err != nil {
+		return nil, err
+	}
+	return pr.verifyDCAPAttestation(a, tcbInfo.IssueDate)
+}
+
+func parseIASAttestation(eki *enclave.EnclaveKeyInfo) (*attestation, error) {
+	avr, err := ias.ParseAndValidateAVR([]byte(eki.Report))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse and validate AVR: %w", err)
+	}
+	quote, err := avr.Quote()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quote from AVR: %w", err)
+	}
+	advisoryIDs := slices.Clone(avr.AdvisoryIDs)
+	sort.Strings(advisoryIDs)
+	return &attestation{
+		Type:            AttestationTypeIAS,
+		Quote:           quote,
+		QuoteStatus:     avr.ISVEnclaveQuoteStatus.String(),
+		AdvisoryIDs:     advisoryIDs,
+		AttestationTime: avr.GetTimestamp(),
+		report:          eki.Report,
+	}, nil
+}
+
+func (pr *Prover) verifyDCAPAttestation(a *lcptypes.DCAPAttestation, now time.Time) (*attestation, error) {
+	rootCerts, err := pr.dcapRootCerts()
+	if err != nil {
+		return nil, err
+	} else if len(rootCerts) == 0 {
+		return nil, ErrDCAPAttestationDisabled
+	}
+	roots, err := dcap.NewRootCertPool(rootCerts)
+	if err != nil {
+		return nil, err
+	}
+	vq, err := dcap.VerifyQuote(a.Quote, a.GetCollateral(), roots, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the DCAP attestation: %w", err)
+	}
+	quoteStatus, err := vq.TCBStatus.QuoteStatus()
+	if err != nil {
+		return nil, err
+	}
+	return &attestation{
+		Type:            AttestationTypeDCAP,
+		Quote:           vq.Quote.ISVEnclaveQuote(),
+		QuoteStatus:     quoteStatus,
+		AdvisoryIDs:     vq.AdvisoryIDs,
+		AttestationTime: vq.TCBInfo.IssueDate,
+		dcap:            a,
+	}, nil
+}
+
+func decodeDCAPAttestation(eki *enclave.EnclaveKeyInfo) (*lcptypes.DCAPAttestation, error) {
+	var a lcptypes.DCAPAttestation
+	if err := a.Unmarshal(eki.Extension); err != nil {
+		return nil, fmt.Errorf("failed to decode the DCAP attestation: enclave_key=%v %w", lcptypes.HexBytes(eki.EnclaveKeyAddress), err)
+	}
+	return &a, nil
+}
+
+// operatorCommitment returns the commitment that the operator signs to register the key
+func (a *attestation) operatorCommitment() (common.Hash, error) {
+	if a.Type == AttestationTypeDCAP {
+		return lcptypes.ComputeEIP712DCAPRegisterEnclaveKeyHash(a.dcap.Quote)
+	}
+	return lcptypes.ComputeEIP712RegisterEnclaveKeyHash(a.report)
+}
+
+// eip712PrimaryType returns the primary type of the EIP712 typed data that the operator signs to register the key
+func (a *attestation) eip712PrimaryType() string {
+	if a.Type == AttestationTypeDCAP {
+		return "DCAPRegisterEnclaveKey"
+	}
+	return "RegisterEnclaveKey"
+}
+
+// registerEnclaveKeyMessage returns the client message to register the key with the attestation
+func (a *attestation) registerEnclaveKeyMessage(eki *enclave.EnclaveKeyInfo, operatorSignature []byte) ibcexported.ClientMessage {
+	if a.Type == AttestationTypeDCAP {
+		return &lcptypes.DCAPRegisterEnclaveKeyMessage{
+			Attestation:       *a.dcap,
+			OperatorSignature: operatorSignature,
+		}
+	}
+	return &lcptypes.RegisterEnclaveKeyMessage{
+		Report:            []byte(eki.Report),
+		Signature:         eki.Signature,
+		SigningCert:       eki.SigningCert,
+		OperatorSignature: operatorSignature,
+	}
+}
+
+// onChainAttestationTime returns the attestation time of `eki` from which the LCP client computes the expiration of the key:
+// the timestamp of the AVR, or the issue date of the TCB info because a DCAP quote carries no timestamp
+func onChainAttestationTime(eki *enclave.EnclaveKeyInfo) (time.Time, error) {
+	typ, err := GetAttestationType(eki)
+	if err != nil {
+		return time.Time{}, err
+	} else if typ == AttestationTypeIAS {
+		avr, err := ias.ParseAndValidateAVR([]byte(eki.Report))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse the AVR of the enclave key: %w", err)
+		}
+		return avr.GetTimestamp(), nil
+	}
+	a, err := decodeDCAPAttestation(eki)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tcbInfo, err := dcap.UnsafeDecodeTCBInfo(a.TcbInfo)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return tcbInfo.IssueDate, nil
+}
+
+// localAttestationTime returns the attestation time of `eki` from which the prover computes the rotation of the key.
+// It is the attestation time reported by the LCP service except for the DCAP attestations,
+// whose attestation time on-chain can be much earlier than the generation of the quote.
+func localAttestationTime(eki *enclave.EnclaveKeyInfo) (time.Time, error) {
+	if typ, err := GetAttestationType(eki); err == nil && typ == AttestationTypeDCAP {
+		return onChainAttestationTime(eki)
+	}
+	return time.Unix(int64(eki.AttestationTime), 0), nil
+}
+
+// getMrenclave returns the MRENCLAVE of the enclave that attested `eki` without verifying the attestation
+func getMrenclave(eki *enclave.EnclaveKeyInfo) ([]byte, error) {
+	typ, err := GetAttestationType(eki)
+	if err != nil {
+		return nil, err
+	} else if typ == AttestationTypeIAS {
+		return getMrenclaveFromReport(eki.Report)
+	}
+	a, err := decodeDCAPAttestation(eki)
+	if err != nil {
+		return nil, err
+	}
+	q, err := dcap.ParseQuote(a.Quote)
+	if err != nil {
+		return nil, err
+	}
+	return q.ISVReport.MRENCLAVE[:], nil
+}
+
+// dcapRootCerts returns the DER-encoded root certificates in the PEM files of DcapRootCertPaths
+func (pr *Prover) dcapRootCerts() ([][]byte, error) {
+	var ders [][]byte
+	for _, path := range pr.config.DcapRootCertPaths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(pr.homePath, path)
+		}
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the DCAP root certificate: %w", err)
+		}
+		for {
+			var block *pem.Block
+			if block, bz = pem.Decode(bz); block == nil {
+				break
+			} else if block.Type == "CERTIFICATE" {
+				ders = append(ders, block.Bytes)
+			}
+		}
+	}
+	if _, err := dcap.NewRootCertPool(ders); err != nil {
+		return nil, err
+	}
+	return ders, nil
+}
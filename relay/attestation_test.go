@@ -0,0 +1,112 @@
+package relay
+
+import (
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/datachainlab/lcp-go/sgx/dcap"
+	"github.com/datachainlab/lcp-go/sgx/dcap/dcaptest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDCAPEnclaveKeyInfo(t *testing.T, f *dcaptest.Fixture, enclaveKey common.Address) *enclave.EnclaveKeyInfo {
+	a := lcptypes.DCAPAttestation{
+		Quote:                 f.Quote,
+		TcbInfo:               f.Collateral.TCBInfo,
+		TcbInfoIssuerChain:    f.Collateral.TCBInfoIssuerChain,
+		QeIdentity:            f.Collateral.QEIdentity,
+		QeIdentityIssuerChain: f.Collateral.QEIdentityIssuerChain,
+	}
+	bz, err := a.Marshal()
+	require.NoError(t, err)
+	return &enclave.EnclaveKeyInfo{
+		EnclaveKeyAddress: enclaveKey.Bytes(),
+		AttestationTime:   uint64(time.Now().Unix()),
+		Extension:         bz,
+	}
+}
+
+func writeTestDCAPRootCert(t *testing.T, pr *Prover, f *dcaptest.Fixture) {
+	pr.homePath = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(pr.homePath, "dcap_root.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: f.RootCert}), 0o600))
+	pr.config.DcapRootCertPaths = []string{"dcap_root.pem"}
+}
+
+func TestGetAttestationType(t *testing.T) {
+	require := require.New(t)
+	typ, err := GetAttestationType(loadTestEnclaveKeyInfo(t))
+	require.NoError(err)
+	require.Equal(AttestationTypeIAS, typ)
+
+	f := dcaptest.NewFixture(t, dcaptest.Params{})
+	typ, err = GetAttestationType(newTestDCAPEnclaveKeyInfo(t, f, common.HexToAddress("0x01")))
+	require.NoError(err)
+	require.Equal(AttestationTypeDCAP, typ)
+
+	_, err = GetAttestationType(&enclave.EnclaveKeyInfo{EnclaveKeyAddress: common.HexToAddress("0x01").Bytes()})
+	require.ErrorContains(err, "no attestation")
+}
+
+func TestVerifyDCAPAttestation(t *testing.T) {
+	require := require.New(t)
+	issueDate := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	mrenclave := [32]byte{0x01}
+	f := dcaptest.NewFixture(t, dcaptest.Params{
+		Mrenclave:   mrenclave,
+		EnclaveKey:  common.HexToAddress("0x01"),
+		ISVSVN:      2,
+		TCBStatus:   dcap.TCBStatusSWHardeningNeeded,
+		AdvisoryIDs: []string{"INTEL-SA-00615", "INTEL-SA-00334"},
+		IssueDate:   issueDate,
+	})
+	eki := newTestDCAPEnclaveKeyInfo(t, f, common.HexToAddress("0x01"))
+	pr := newTestProver(t)
+
+	_, err := pr.verifyAttestation(eki, time.Now())
+	require.ErrorIs(err, ErrDCAPAttestationDisabled)
+
+	writeTestDCAPRootCert(t, pr, f)
+	att, err := pr.verifyAttestation(eki, time.Now())
+	require.NoError(err)
+	require.Equal(AttestationTypeDCAP, att.Type)
+	require.Equal("SW_HARDENING_NEEDED", att.QuoteStatus)
+	require.Equal([]string{"INTEL-SA-00334", "INTEL-SA-00615"}, att.AdvisoryIDs)
+	require.Equal(issueDate, att.AttestationTime)
+	require.Equal(uint16(2), att.Quote.Report.ISVSVN)
+
+	// the collateral has expired, but the attestation is still valid at the attestation time
+	_, err = pr.verifyAttestation(eki, issueDate.AddDate(0, 0, 31))
+	require.ErrorContains(err, "expired")
+	att, err = pr.verifyAttestationAtAttestationTime(eki)
+	require.NoError(err)
+	require.Equal("SW_HARDENING_NEEDED", att.QuoteStatus)
+
+	// both of the attestation times are the issue date of the TCB info
+	onChain, err := onChainAttestationTime(eki)
+	require.NoError(err)
+	require.Equal(issueDate, onChain)
+	local, err := localAttestationTime(eki)
+	require.NoError(err)
+	require.Equal(issueDate, local)
+
+	got, err := getMrenclave(eki)
+	require.NoError(err)
+	require.Equal(mrenclave[:], got)
+
+	msg, ok := att.registerEnclaveKeyMessage(eki, []byte{0x01}).(*lcptypes.DCAPRegisterEnclaveKeyMessage)
+	require.True(ok)
+	require.Equal(f.Quote, msg.Attestation.Quote)
+	require.Equal([]byte{0x01}, msg.OperatorSignature)
+
+	// another root does not verify the attestation
+	other := dcaptest.NewFixture(t, dcaptest.Params{})
+	writeTestDCAPRootCert(t, pr, other)
+	_, err = pr.verifyAttestation(eki, time.Now())
+	require.ErrorContains(err, "failed to verify the DCAP attestation")
+}
@@ -0,0 +1,98 @@
+package relay
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+)
+
+// avrCacheTTL is the period for which a verified AVR is reused without verifying its signature again
+const avrCacheTTL = 10 * time.Minute
+
+// sharedAVRCache is shared by the provers of all the paths because the LCP services typically return the same enclave keys
+var sharedAVRCache = newAVRCache(avrCacheTTL)
+
+// avrCacheEntry is an AVR whose signature has been verified
+type avrCacheEntry struct {
+	attestation *attestation
+	// the entry is not used after this time
+	expiresAt time.Time
+	// the validity period of the signing certificate, outside which the verification must be done again
+	notBefore time.Time
+	notAfter  time.Time
+}
+
+// avrCache caches the AVRs whose signatures have been verified, keyed by the hash of the report, the signature and the signing certificate.
+// The key also reflects whether the debug enclaves are allowed, on which the validation of the AVR depends.
+// The failures of the verification are never cached because they may depend on the verification time, e.g. the expiry of the certificate.
+// It is safe for concurrent use.
+type avrCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]avrCacheEntry
+}
+
+func newAVRCache(ttl time.Duration) *avrCache {
+	return &avrCache{ttl: ttl, entries: make(map[[sha256.Size]byte]avrCacheEntry)}
+}
+
+func avrCacheKey(eki *enclave.EnclaveKeyInfo) [sha256.Size]byte {
+	h := sha256.New()
+	fmt.Fprintf(h, "%v:", ias.IsAllowDebugEnclaves())
+	for _, bz := range [][]byte{[]byte(eki.Report), eki.Signature, eki.SigningCert} {
+		// the length prefix separates the fields
+		fmt.Fprintf(h, "%d:", len(bz))
+		h.Write(bz)
+	}
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// verify returns the attestation of the AVR of `eki` after verifying its signature at `now`, or the cached one if it has been verified.
+// If the cache is nil, the signature is always verified.
+func (c *avrCache) verify(eki *enclave.EnclaveKeyInfo, now time.Time) (*attestation, error) {
+	if c == nil {
+		return verifyIASAttestation(eki, now)
+	}
+	key := avrCacheKey(eki)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) && !now.Before(entry.notBefore) && !now.After(entry.notAfter) {
+		return entry.attestation, nil
+	}
+
+	att, err := verifyIASAttestation(eki, now)
+	if err != nil {
+		return nil, err
+	}
+	// the certificate has been parsed by the verification
+	cert, err := x509.ParseCertificate(eki.SigningCert)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = avrCacheEntry{attestation: att, expiresAt: now.Add(c.ttl), notBefore: cert.NotBefore, notAfter: cert.NotAfter}
+	return att, nil
+}
+
+// verifyIASAttestation verifies the signature of the AVR of `eki` at `now` and parses it
+func verifyIASAttestation(eki *enclave.EnclaveKeyInfo, now time.Time) (*attestation, error) {
+	if err := ias.VerifyReport([]byte(eki.Report), eki.Signature, eki.SigningCert, now); err != nil {
+		return nil, fmt.Errorf("failed to verify AVR signature: %w", err)
+	}
+	return parseIASAttestation(eki)
+}
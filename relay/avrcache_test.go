@@ -0,0 +1,116 @@
+package relay
+
+import (
+	"crypto/x509"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAVRCache(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	eki := loadTestEnclaveKeyInfo(t)
+	cert, err := x509.ParseCertificate(eki.SigningCert)
+	require.NoError(t, err)
+	now := time.Now()
+
+	t.Run("hit", func(t *testing.T) {
+		require := require.New(t)
+		cache := newAVRCache(time.Minute)
+		att, err := cache.verify(eki, now)
+		require.NoError(err)
+		cached, err := cache.verify(eki, now.Add(time.Second))
+		require.NoError(err)
+		require.Same(att, cached)
+
+		// the entry expires after the TTL
+		verified, err := cache.verify(eki, now.Add(time.Minute))
+		require.NoError(err)
+		require.NotSame(att, verified)
+	})
+
+	t.Run("changed signature", func(t *testing.T) {
+		require := require.New(t)
+		cache := newAVRCache(time.Minute)
+		_, err := cache.verify(eki, now)
+		require.NoError(err)
+
+		tampered := *eki
+		tampered.Signature = slices.Clone(eki.Signature)
+		tampered.Signature[0] ^= 0xff
+		_, err = cache.verify(&tampered, now)
+		require.ErrorContains(err, "failed to verify AVR signature")
+	})
+
+	t.Run("verification time", func(t *testing.T) {
+		require := require.New(t)
+		cache := newAVRCache(time.Hour)
+		// the failure after the expiry of the certificate is not cached
+		_, err := cache.verify(eki, cert.NotAfter.Add(time.Second))
+		require.Error(err)
+		att, err := cache.verify(eki, now)
+		require.NoError(err)
+
+		// the cached entry is not used outside the validity period of the certificate
+		_, err = cache.verify(eki, cert.NotAfter.Add(time.Second))
+		require.Error(err)
+		cached, err := cache.verify(eki, now)
+		require.NoError(err)
+		require.Same(att, cached)
+	})
+
+	t.Run("debug enclaves disallowed", func(t *testing.T) {
+		require := require.New(t)
+		cache := newAVRCache(time.Minute)
+		_, err := cache.verify(eki, now)
+		require.NoError(err)
+		ias.UnsetAllowDebugEnclaves()
+		defer ias.SetAllowDebugEnclaves()
+		_, err = cache.verify(eki, now)
+		require.ErrorContains(err, "debug enclave is not allowed")
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		cache := newAVRCache(time.Minute)
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := cache.verify(eki, now)
+				require.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		require.Len(t, cache.entries, 1)
+	})
+}
+
+func BenchmarkVerifyAttestation(b *testing.B) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	eki := loadTestEnclaveKeyInfo(&testing.T{})
+	now := time.Now()
+	for _, c := range []struct {
+		name  string
+		cache *avrCache
+	}{
+		{"uncached", nil},
+		{"cached", newAVRCache(time.Hour)},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			pr := &Prover{avrCache: c.cache}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := pr.verifyAttestation(eki, now); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
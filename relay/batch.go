@@ -0,0 +1,187 @@
+package relay
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	BatchOpCreateELC = "create-elc"
+	BatchOpUpdateELC = "update-elc"
+	BatchOpQueryELC  = "query-elc"
+)
+
+// BatchFile is a list of ELC maintenance operations.
+// It can be written in either YAML or JSON.
+type BatchFile struct {
+	// the maximum number of provers processed concurrently
+	// if zero, the operations are processed sequentially
+	Concurrency uint             `json:"concurrency,omitempty"`
+	Operations  []BatchOperation `json:"operations"`
+}
+
+// BatchOperation is an operation for an ELC client
+type BatchOperation struct {
+	// one of "create-elc", "update-elc" and "query-elc"
+	Op string `json:"op"`
+	// the path name in the relayer's config
+	Path string `json:"path"`
+	// if true, the prover of the source chain of the path is used
+	Src bool `json:"src,omitempty"`
+	// if empty, the ELC client ID in the prover's config is used
+	ELCClientID string `json:"elc_client_id,omitempty"`
+	// the height of the origin chain for "create-elc"
+	// 0 means the latest height
+	Height uint64 `json:"height,omitempty"`
+	// the revision number of Height for "create-elc"
+	// if nil, the current revision of the origin chain is used
+	RevisionNumber *uint64 `json:"revision_number,omitempty"`
+}
+
+// HeightSpec returns the height of the origin chain for "create-elc"
+func (op BatchOperation) HeightSpec() HeightSpec {
+	return HeightSpec{RevisionNumber: op.RevisionNumber, RevisionHeight: op.Height}
+}
+
+func (op BatchOperation) Validate() error {
+	switch op.Op {
+	case BatchOpCreateELC, BatchOpUpdateELC, BatchOpQueryELC:
+	default:
+		return fmt.Errorf("unknown op: %v", op.Op)
+	}
+	if op.Path == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	if op.Op != BatchOpCreateELC && op.Height != 0 {
+		return fmt.Errorf("height is only available for %v", BatchOpCreateELC)
+	}
+	if op.RevisionNumber != nil && op.Height == 0 {
+		return fmt.Errorf("revision_number requires height")
+	}
+	return nil
+}
+
+// LoadBatchFile loads and validates the batch file
+func LoadBatchFile(path string) (*BatchFile, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file: path=%v %w", path, err)
+	}
+	var file BatchFile
+	if err := yaml.UnmarshalStrict(bz, &file); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch file: path=%v %w", path, err)
+	}
+	for i, op := range file.Operations {
+		if err := op.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid operation: index=%v %w", i, err)
+		}
+	}
+	return &file, nil
+}
+
+// BatchReport is a summarized result of the batch operations
+type BatchReport struct {
+	Total     int                `json:"total"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Results   []*BatchItemResult `json:"results"`
+}
+
+// BatchItemResult is a result of a batch operation
+type BatchItemResult struct {
+	Index       int         `json:"index"`
+	Op          string      `json:"op"`
+	Path        string      `json:"path"`
+	ELCClientID string      `json:"elc_client_id"`
+	Success     bool        `json:"success"`
+	Result      interface{} `json:"result,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// ProverResolver returns the prover for the given operation
+type ProverResolver func(op BatchOperation) (*Prover, error)
+
+// RunBatch executes the operations and returns the report.
+// A failed operation does not stop the subsequent ones.
+// The ELC clients of the same prover share its enclave key and persisted state,
+// so the operations for the same prover are executed sequentially in the given order,
+// and up to `concurrency` provers are processed concurrently.
+func RunBatch(ops []BatchOperation, concurrency uint, resolve ProverResolver) *BatchReport {
+	results := make([]*BatchItemResult, len(ops))
+
+	// group the operations by the prover
+	var (
+		provers []*Prover
+		groups  = make(map[*Prover][]int)
+	)
+	for i, op := range ops {
+		results[i] = &BatchItemResult{Index: i, Op: op.Op, Path: op.Path, ELCClientID: op.ELCClientID}
+		pr, err := resolve(op)
+		if err != nil {
+			results[i].Error = fmt.Sprintf("failed to resolve the prover: %v", err)
+			continue
+		}
+		if results[i].ELCClientID == "" {
+			results[i].ELCClientID = pr.GetELCClientID()
+		}
+		if _, ok := groups[pr]; !ok {
+			provers = append(provers, pr)
+		}
+		groups[pr] = append(groups[pr], i)
+	}
+
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+	for _, pr := range provers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(pr *Prover, indices []int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for _, i := range indices {
+				res, err := pr.runBatchOperation(ops[i], results[i].ELCClientID)
+				if err != nil {
+					pr.getLogger().Error("batch operation failed", err, "index", i, "op", ops[i].Op, "elc_client_id", results[i].ELCClientID)
+					results[i].Error = err.Error()
+					continue
+				}
+				results[i].Success = true
+				results[i].Result = res
+			}
+		}(pr, groups[pr])
+	}
+	wg.Wait()
+
+	report := &BatchReport{Total: len(ops), Results: results}
+	for _, res := range results {
+		if res.Success {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+	return report
+}
+
+func (pr *Prover) runBatchOperation(op BatchOperation, elcClientID string) (interface{}, error) {
+	switch op.Op {
+	case BatchOpCreateELC:
+		return pr.doCreateELC(elcClientID, op.HeightSpec())
+	case BatchOpUpdateELC:
+		return pr.doUpdateELC(elcClientID, nil)
+	case BatchOpQueryELC:
+		return pr.doQueryELC(elcClientID)
+	default:
+		return nil, fmt.Errorf("unknown op: %v", op.Op)
+	}
+}
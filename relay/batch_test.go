@@ -0,0 +1,113 @@
+package relay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBatch(t *testing.T) {
+	require := require.New(t)
+	newProver := func(chainID string, clients map[string]*elc.QueryClientResponse) *Prover {
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = t.TempDir()
+		pr.originChain = &mockCounterparty{chainID: chainID}
+		pr.config.ElcClientId = "07-tendermint-0"
+		pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}}
+		pr.lcpServiceClient.ELCQueryClient = mockELCQueryClient{clients: clients}
+		require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr
+	}
+	provers := map[string]*Prover{
+		"path-0": newProver("origin-0", map[string]*elc.QueryClientResponse{
+			"07-tendermint-0": {
+				Found:          true,
+				ClientState:    &codectypes.Any{TypeUrl: "/ibc.lightclients.tendermint.v1.ClientState"},
+				ConsensusState: &codectypes.Any{},
+			},
+		}),
+		"path-1": newProver("origin-1", nil),
+	}
+	ops := []BatchOperation{
+		{Op: BatchOpQueryELC, Path: "path-0"},
+		// the client does not exist in the ELC
+		{Op: BatchOpUpdateELC, Path: "path-0", ELCClientID: "07-tendermint-1"},
+		{Op: BatchOpQueryELC, Path: "path-0", ELCClientID: "07-tendermint-0"},
+		{Op: BatchOpQueryELC, Path: "path-1"},
+		{Op: BatchOpQueryELC, Path: "unknown"},
+	}
+	report := RunBatch(ops, 2, func(op BatchOperation) (*Prover, error) {
+		pr, ok := provers[op.Path]
+		if !ok {
+			return nil, fmt.Errorf("path not found: %v", op.Path)
+		}
+		return pr, nil
+	})
+	require.Equal(5, report.Total)
+	require.Equal(3, report.Succeeded)
+	require.Equal(2, report.Failed)
+
+	require.True(report.Results[0].Success)
+	require.Equal("07-tendermint-0", report.Results[0].ELCClientID)
+	require.True(report.Results[0].Result.(*QueryELCResult).Found)
+	require.False(report.Results[1].Success)
+	require.Contains(report.Results[1].Error, "client not found")
+	// the failure does not stop the subsequent operations
+	require.True(report.Results[2].Success)
+	require.True(report.Results[3].Success)
+	require.False(report.Results[3].Result.(*QueryELCResult).Found)
+	require.False(report.Results[4].Success)
+	require.Contains(report.Results[4].Error, "failed to resolve the prover")
+}
+
+func TestLoadBatchFile(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "batch.yaml")
+	require.NoError(os.WriteFile(path, []byte(`
+concurrency: 2
+operations:
+  - op: create-elc
+    path: path-0
+    height: 100
+  - op: update-elc
+    path: path-1
+    src: true
+    elc_client_id: 07-tendermint-1
+`), 0600))
+	file, err := LoadBatchFile(path)
+	require.NoError(err)
+	require.Equal(uint(2), file.Concurrency)
+	require.Equal([]BatchOperation{
+		{Op: BatchOpCreateELC, Path: "path-0", Height: 100},
+		{Op: BatchOpUpdateELC, Path: "path-1", Src: true, ELCClientID: "07-tendermint-1"},
+	}, file.Operations)
+
+	path = filepath.Join(dir, "batch.json")
+	require.NoError(os.WriteFile(path, []byte(`{"operations":[{"op":"query-elc","path":"path-0"}]}`), 0600))
+	file, err = LoadBatchFile(path)
+	require.NoError(err)
+	require.Len(file.Operations, 1)
+
+	require.NoError(os.WriteFile(path, []byte(`{"operations":[{"op":"remove-elc","path":"path-0"}]}`), 0600))
+	_, err = LoadBatchFile(path)
+	require.ErrorContains(err, "unknown op")
+
+	require.NoError(os.WriteFile(path, []byte(`{"operations":[{"op":"create-elc","path":"path-0","height":100,"revision_number":1}]}`), 0600))
+	file, err = LoadBatchFile(path)
+	require.NoError(err)
+	require.Equal(NewHeightSpec(clienttypes.NewHeight(1, 100)), file.Operations[0].HeightSpec())
+
+	require.NoError(os.WriteFile(path, []byte(`{"operations":[{"op":"create-elc","path":"path-0","revision_number":1}]}`), 0600))
+	_, err = LoadBatchFile(path)
+	require.ErrorContains(err, "revision_number requires height")
+}
@@ -0,0 +1,304 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// BootstrapStatus is the status of a step of the bootstrap
+type BootstrapStatus string
+
+const (
+	BootstrapDone BootstrapStatus = "done"
+	// the step was completed before the bootstrap
+	BootstrapAlreadyDone BootstrapStatus = "already_done"
+	BootstrapFailed      BootstrapStatus = "failed"
+	// the step is not executed because a previous step failed
+	BootstrapPending BootstrapStatus = "pending"
+)
+
+const (
+	BootstrapStepCreateELC          = "create_elc"
+	BootstrapStepCreateClient       = "create_client"
+	BootstrapStepRegisterEnclaveKey = "register_enclave_key"
+	BootstrapStepActivateClient     = "activate_client"
+	BootstrapStepVerify             = "verify"
+)
+
+// bootstrapSteps is the order of the steps of the bootstrap
+var bootstrapSteps = []string{
+	BootstrapStepCreateELC,
+	BootstrapStepCreateClient,
+	BootstrapStepRegisterEnclaveKey,
+	BootstrapStepActivateClient,
+	BootstrapStepVerify,
+}
+
+// BootstrapStep is the result of a step of the bootstrap
+type BootstrapStep struct {
+	Name    string            `json:"name"`
+	Status  BootstrapStatus   `json:"status"`
+	Error   string            `json:"error,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// BootstrapResult is the report of the bootstrap
+type BootstrapResult struct {
+	ELCClientID string           `json:"elc_client_id"`
+	Completed   bool             `json:"completed"`
+	Steps       []*BootstrapStep `json:"steps"`
+}
+
+// Step returns the result of the step with the given name or nil
+func (r *BootstrapResult) Step(name string) *BootstrapStep {
+	for _, step := range r.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	return nil
+}
+
+// BootstrapProgressFunc is called after each step of the bootstrap finishes
+type BootstrapProgressFunc func(*BootstrapStep)
+
+// bootstrapCheckpoint is persisted when the bootstrap fails so that it can be resumed
+type bootstrapCheckpoint struct {
+	// the steps completed before the failure
+	Completed  []string  `json:"completed"`
+	FailedStep string    `json:"failed_step"`
+	Error      string    `json:"error"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+func (c *bootstrapCheckpoint) isCompleted(step string) bool {
+	for _, s := range c.Completed {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+// bootstrapActions are the operations of the bootstrap that involve the relayer config in addition to the prover
+type bootstrapActions struct {
+	// createClient creates the LCP client on the counterparty chain and records its ID in the path of the counterparty
+	createClient func() error
+	// activateClient makes the LCP client on the counterparty chain synchronise with the latest header of the origin chain
+	activateClient func() error
+}
+
+// doBootstrap sets up the ELC client and the LCP client on `counterparty` from scratch:
+// it creates the ELC client, creates the LCP client, registers an enclave key, activates the client and verifies the result.
+// Each step is skipped if it is already done, so the bootstrap can be run on a partially set up path.
+// If a step fails, the bootstrap stops and persists a checkpoint; a next bootstrap requires `resume` to continue from it.
+func (pr *Prover) doBootstrap(ctx context.Context, counterparty core.FinalityAwareChain, actions bootstrapActions, resume bool, progress BootstrapProgressFunc) (*BootstrapResult, error) {
+	checkpoint, err := pr.loadBootstrapCheckpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint != nil && !resume {
+		return nil, fmt.Errorf("the previous bootstrap failed: step=%v error=%v failed_at=%v; resume it with --resume", checkpoint.FailedStep, checkpoint.Error, checkpoint.FailedAt)
+	} else if checkpoint == nil {
+		checkpoint = &bootstrapCheckpoint{}
+	}
+
+	checks := map[string]func() (bool, error){
+		BootstrapStepCreateELC: func() (bool, error) {
+			if pr.GetELCClientID() == "" {
+				// the ID is generated by the creation
+				return false, nil
+			}
+			res, err := pr.lcpServiceClient.Client(ctx, &elc.QueryClientRequest{ClientId: pr.GetELCClientID()})
+			if err != nil {
+				return false, err
+			}
+			return res.Found, nil
+		},
+		BootstrapStepCreateClient: func() (bool, error) {
+			cs, err := pr.queryBootstrapClientState(ctx, counterparty)
+			if err != nil {
+				return false, err
+			}
+			return cs != nil, nil
+		},
+		BootstrapStepRegisterEnclaveKey: func() (bool, error) {
+			needed, err := pr.loadEKIAndCheckUpdateNeeded(ctx, counterparty)
+			if err != nil {
+				return false, err
+			}
+			return !needed, nil
+		},
+		BootstrapStepActivateClient: func() (bool, error) {
+			cs, err := pr.queryBootstrapClientState(ctx, counterparty)
+			if err != nil {
+				return false, err
+			}
+			return cs != nil && !cs.LatestHeight.IsZero(), nil
+		},
+		// the verification is always executed
+		BootstrapStepVerify: func() (bool, error) {
+			return false, nil
+		},
+	}
+	runs := map[string]func(details map[string]string) error{
+		BootstrapStepCreateELC: func(details map[string]string) error {
+			elcClientID, err := pr.ensureELCClientID(ctx)
+			if err != nil {
+				return err
+			}
+			res, err := pr.doCreateELC(elcClientID, LatestHeightSpec)
+			if err != nil {
+				return err
+			}
+			if res.Message != nil {
+				details["height"] = res.Message.PostHeight.String()
+			}
+			return nil
+		},
+		BootstrapStepCreateClient: func(details map[string]string) error {
+			if err := actions.createClient(); err != nil {
+				return err
+			}
+			cs, err := pr.queryBootstrapClientState(ctx, counterparty)
+			if err != nil {
+				return err
+			} else if cs == nil {
+				return fmt.Errorf("the LCP client is not found on the counterparty chain after the creation: chain_id=%v", counterparty.ChainID())
+			}
+			return nil
+		},
+		BootstrapStepRegisterEnclaveKey: func(details map[string]string) error {
+			return pr.UpdateEKIfNeeded(ctx, counterparty)
+		},
+		BootstrapStepActivateClient: func(details map[string]string) error {
+			return actions.activateClient()
+		},
+		BootstrapStepVerify: func(details map[string]string) error {
+			return pr.verifyBootstrap(ctx, counterparty, details)
+		},
+	}
+
+	result := &BootstrapResult{Completed: true}
+	for _, name := range bootstrapSteps {
+		step := &BootstrapStep{Name: name, Details: make(map[string]string)}
+		result.Steps = append(result.Steps, step)
+		if !result.Completed {
+			step.Status = BootstrapPending
+			continue
+		}
+		err := func() error {
+			if checkpoint.isCompleted(name) {
+				step.Status = BootstrapAlreadyDone
+				step.Details["source"] = "checkpoint"
+				return nil
+			}
+			if done, err := checks[name](); err != nil {
+				return fmt.Errorf("failed to check the step: %w", err)
+			} else if done {
+				step.Status = BootstrapAlreadyDone
+				return nil
+			}
+			if err := runs[name](step.Details); err != nil {
+				return err
+			}
+			step.Status = BootstrapDone
+			return nil
+		}()
+		if err != nil {
+			pr.getLogger().Error("bootstrap step failed", err, "step", name, "elc_client_id", pr.GetELCClientID())
+			step.Status, step.Error, result.Completed = BootstrapFailed, err.Error(), false
+			checkpoint.FailedStep, checkpoint.Error, checkpoint.FailedAt = name, err.Error(), time.Now().UTC()
+			if err := pr.saveBootstrapCheckpoint(ctx, checkpoint); err != nil {
+				return nil, err
+			}
+		} else {
+			pr.getLogger().Info("bootstrap step finished", "step", name, "status", step.Status, "elc_client_id", pr.GetELCClientID())
+			if name != BootstrapStepVerify && !checkpoint.isCompleted(name) {
+				checkpoint.Completed = append(checkpoint.Completed, name)
+			}
+		}
+		if progress != nil {
+			progress(step)
+		}
+	}
+	// the ID may have been generated by the creation of the ELC client
+	result.ELCClientID = pr.GetELCClientID()
+	if result.Completed {
+		if err := pr.removeBootstrapCheckpoint(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// queryBootstrapClientState returns the LCP client state on the counterparty chain or nil if the client does not exist yet
+func (pr *Prover) queryBootstrapClientState(ctx context.Context, counterparty core.FinalityAwareChain) (*lcptypes.ClientState, error) {
+	if counterparty.Path().ClientID == "" {
+		return nil, nil
+	}
+	cpQueryHeight, err := counterparty.LatestHeight()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	res, err := counterparty.QueryClientState(core.NewQueryContext(ctx, cpQueryHeight))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the client state on the counterparty chain: client_id=%v %w", counterparty.Path().ClientID, err)
+	}
+	var cs ibcexported.ClientState
+	if err := pr.codec.UnpackAny(res.ClientState, &cs); err != nil {
+		return nil, fmt.Errorf("failed to unpack client state: %w", err)
+	}
+	clientState, ok := cs.(*lcptypes.ClientState)
+	if !ok {
+		return nil, fmt.Errorf("the client on the counterparty chain is not an LCP client: client_id=%v type=%T", counterparty.Path().ClientID, cs)
+	}
+	return clientState, nil
+}
+
+// verifyBootstrap compares the ELC client with the LCP client on the counterparty chain
+func (pr *Prover) verifyBootstrap(ctx context.Context, counterparty core.FinalityAwareChain, details map[string]string) error {
+	res, err := pr.lcpServiceClient.Client(ctx, &elc.QueryClientRequest{ClientId: pr.GetELCClientID()})
+	if err != nil {
+		return err
+	} else if !res.Found {
+		return fmt.Errorf("the ELC client is not found: elc_client_id=%v", pr.GetELCClientID())
+	}
+	var elcClientState ibcexported.ClientState
+	if err := pr.codec.UnpackAny(res.ClientState, &elcClientState); err != nil {
+		return fmt.Errorf("failed to unpack the ELC client state: %w", err)
+	}
+	elcHeight := clienttypes.NewHeight(elcClientState.GetLatestHeight().GetRevisionNumber(), elcClientState.GetLatestHeight().GetRevisionHeight())
+
+	cs, err := pr.queryBootstrapClientState(ctx, counterparty)
+	if err != nil {
+		return err
+	} else if cs == nil {
+		return fmt.Errorf("the LCP client is not found on the counterparty chain: chain_id=%v", counterparty.ChainID())
+	}
+	details["client_id"] = counterparty.Path().ClientID
+	details["client_height"] = cs.LatestHeight.String()
+	details["elc_height"] = elcHeight.String()
+
+	if !bytes.Equal(cs.Mrenclave, pr.config.GetMrenclave()) {
+		return fmt.Errorf("mrenclave mismatch: expected=%v actual=%v", lcptypes.HexBytes(pr.config.GetMrenclave()), lcptypes.HexBytes(cs.Mrenclave))
+	}
+	if cs.LatestHeight.IsZero() {
+		return fmt.Errorf("the LCP client is not activated: client_id=%v", counterparty.Path().ClientID)
+	}
+	if elcHeight.LT(cs.LatestHeight) {
+		return fmt.Errorf("the ELC client is behind the LCP client: elc_height=%v client_height=%v", elcHeight, cs.LatestHeight)
+	}
+	if pr.activeEnclaveKey == nil {
+		return fmt.Errorf("no enclave key is registered")
+	}
+	return nil
+}
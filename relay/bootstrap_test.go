@@ -0,0 +1,224 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+// mockBootstrapCounterparty is a counterparty chain whose LCP client is created and activated by the bootstrap
+type mockBootstrapCounterparty struct {
+	*mockCounterparty
+	// empty until the client is created
+	clientID string
+}
+
+func (c *mockBootstrapCounterparty) Path() *core.PathEnd {
+	return &core.PathEnd{ChainID: c.chainID, ClientID: c.clientID}
+}
+
+// bootstrapFixture is the state of a path shared by the bootstraps in a test
+type bootstrapFixture struct {
+	pr            *Prover
+	service       *mockLCPService
+	counterparty  *mockBootstrapCounterparty
+	actions       bootstrapActions
+	createCalls   int
+	activateCalls int
+	activateErr   error
+	mrenclave     []byte
+	originHeight  clienttypes.Height
+}
+
+func newBootstrapFixture(t *testing.T) *bootstrapFixture {
+	eki := loadTestEnclaveKeyInfo(t)
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	f := &bootstrapFixture{
+		service:      &mockLCPService{t: t, key: key, clients: make(map[string]*lcptypes.ClientState)},
+		counterparty: &mockBootstrapCounterparty{mockCounterparty: newMockCounterparty(clienttypes.NewHeight(0, 100))},
+		originHeight: clienttypes.NewHeight(0, 10),
+	}
+	f.counterparty.latestHeight = clienttypes.NewHeight(0, 100)
+	// the registrations are included in the finalized block immediately
+	f.counterparty.sendMsgsErr = func(msgs []sdk.Msg) error {
+		for i := range msgs {
+			f.counterparty.msgResults[(&tendermint.MsgID{TxHash: "0x01", MsgIndex: uint32(i)}).String()] = mockMsgResult{height: clienttypes.NewHeight(0, 100), success: true}
+		}
+		return nil
+	}
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.homePath = t.TempDir()
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	pr.originProver = mockSelfTestOriginProver{latestHeight: f.originHeight}
+	pr.config.ElcClientId = "elc-client-0"
+	pr.config.AllowedQuoteStatuses = []string{"GROUP_OUT_OF_DATE"}
+	pr.config.AllowedAdvisoryIds = []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-00334", "INTEL-SA-00477", "INTEL-SA-00614", "INTEL-SA-00615", "INTEL-SA-00617", "INTEL-SA-00828"}
+	pr.config.AllowDebugEnclaveKeys = true
+	pr.config.Mrenclave = testMrenclave(t, eki)
+	pr.lcpServiceClient = LCPServiceClient{
+		ELCMsgClient:       f.service,
+		ELCQueryClient:     f.service,
+		EnclaveQueryClient: mockEnclaveQueryClient{availableKeys: []*enclave.EnclaveKeyInfo{eki}},
+	}
+	require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+	f.pr = pr
+	f.mrenclave = pr.config.GetMrenclave()
+	f.actions = bootstrapActions{
+		createClient: func() error {
+			f.createCalls++
+			f.createClient()
+			return nil
+		},
+		activateClient: func() error {
+			f.activateCalls++
+			if f.activateErr != nil {
+				return f.activateErr
+			}
+			f.activateClient()
+			return nil
+		},
+	}
+	return f
+}
+
+func (f *bootstrapFixture) createClient() {
+	f.counterparty.clientID = "lcp-client-0"
+	f.counterparty.clientState = &lcptypes.ClientState{Mrenclave: f.mrenclave, KeyExpiration: 3600}
+}
+
+func (f *bootstrapFixture) activateClient() {
+	f.counterparty.clientState.LatestHeight = f.originHeight
+}
+
+func (f *bootstrapFixture) bootstrap(resume bool) (*BootstrapResult, error) {
+	return f.pr.doBootstrap(context.TODO(), f.counterparty, f.actions, resume, nil)
+}
+
+func requireBootstrapStatuses(t *testing.T, result *BootstrapResult, statuses map[string]BootstrapStatus) {
+	require.Len(t, result.Steps, len(bootstrapSteps))
+	for i, step := range result.Steps {
+		require.Equal(t, bootstrapSteps[i], step.Name)
+		require.Equal(t, statuses[step.Name], step.Status, step.Name)
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	t.Run("fresh", func(t *testing.T) {
+		require := require.New(t)
+		f := newBootstrapFixture(t)
+		result, err := f.bootstrap(false)
+		require.NoError(err)
+		require.True(result.Completed)
+		requireBootstrapStatuses(t, result, map[string]BootstrapStatus{
+			BootstrapStepCreateELC:          BootstrapDone,
+			BootstrapStepCreateClient:       BootstrapDone,
+			BootstrapStepRegisterEnclaveKey: BootstrapDone,
+			BootstrapStepActivateClient:     BootstrapDone,
+			BootstrapStepVerify:             BootstrapDone,
+		})
+		require.Contains(f.service.clients, "elc-client-0")
+		require.Equal(1, f.createCalls)
+		require.Equal(1, f.activateCalls)
+		require.Equal("0-10", result.Step(BootstrapStepVerify).Details["client_height"])
+		require.Equal("0-10", result.Step(BootstrapStepVerify).Details["elc_height"])
+
+		// the bootstrap on the fully completed setup only verifies it
+		result, err = f.bootstrap(false)
+		require.NoError(err)
+		require.True(result.Completed)
+		requireBootstrapStatuses(t, result, map[string]BootstrapStatus{
+			BootstrapStepCreateELC:          BootstrapAlreadyDone,
+			BootstrapStepCreateClient:       BootstrapAlreadyDone,
+			BootstrapStepRegisterEnclaveKey: BootstrapAlreadyDone,
+			BootstrapStepActivateClient:     BootstrapAlreadyDone,
+			BootstrapStepVerify:             BootstrapDone,
+		})
+		require.Equal(1, f.createCalls)
+		require.Equal(1, f.activateCalls)
+	})
+
+	t.Run("partially completed", func(t *testing.T) {
+		require := require.New(t)
+		f := newBootstrapFixture(t)
+		// the ELC and the client are created, but no key is registered and the client is not activated
+		_, err := f.pr.doCreateELC(f.pr.config.ElcClientId, LatestHeightSpec)
+		require.NoError(err)
+		f.createClient()
+
+		result, err := f.bootstrap(false)
+		require.NoError(err)
+		require.True(result.Completed)
+		requireBootstrapStatuses(t, result, map[string]BootstrapStatus{
+			BootstrapStepCreateELC:          BootstrapAlreadyDone,
+			BootstrapStepCreateClient:       BootstrapAlreadyDone,
+			BootstrapStepRegisterEnclaveKey: BootstrapDone,
+			BootstrapStepActivateClient:     BootstrapDone,
+			BootstrapStepVerify:             BootstrapDone,
+		})
+		require.Equal(0, f.createCalls)
+		require.Equal(1, f.activateCalls)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		require := require.New(t)
+		f := newBootstrapFixture(t)
+		f.activateErr = fmt.Errorf("no available updates")
+		var progress []string
+		result, err := f.pr.doBootstrap(context.TODO(), f.counterparty, f.actions, false, func(step *BootstrapStep) {
+			progress = append(progress, fmt.Sprintf("%v:%v", step.Name, step.Status))
+		})
+		require.NoError(err)
+		require.False(result.Completed)
+		requireBootstrapStatuses(t, result, map[string]BootstrapStatus{
+			BootstrapStepCreateELC:          BootstrapDone,
+			BootstrapStepCreateClient:       BootstrapDone,
+			BootstrapStepRegisterEnclaveKey: BootstrapDone,
+			BootstrapStepActivateClient:     BootstrapFailed,
+			BootstrapStepVerify:             BootstrapPending,
+		})
+		require.Equal("no available updates", result.Step(BootstrapStepActivateClient).Error)
+		// the pending step is not reported as the progress
+		require.Equal([]string{"create_elc:done", "create_client:done", "register_enclave_key:done", "activate_client:failed"}, progress)
+
+		// the checkpoint must be resumed explicitly
+		_, err = f.bootstrap(false)
+		require.ErrorContains(err, "step=activate_client error=no available updates")
+
+		f.activateErr = nil
+		result, err = f.bootstrap(true)
+		require.NoError(err)
+		require.True(result.Completed)
+		requireBootstrapStatuses(t, result, map[string]BootstrapStatus{
+			BootstrapStepCreateELC:          BootstrapAlreadyDone,
+			BootstrapStepCreateClient:       BootstrapAlreadyDone,
+			BootstrapStepRegisterEnclaveKey: BootstrapAlreadyDone,
+			BootstrapStepActivateClient:     BootstrapDone,
+			BootstrapStepVerify:             BootstrapDone,
+		})
+		require.Equal("checkpoint", result.Step(BootstrapStepCreateELC).Details["source"])
+		require.Equal(1, f.createCalls)
+		require.Equal(2, f.activateCalls)
+
+		// the checkpoint is removed after the completion
+		checkpoint, err := f.pr.loadBootstrapCheckpoint(context.TODO())
+		require.NoError(err)
+		require.Nil(checkpoint)
+	})
+}
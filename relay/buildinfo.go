@@ -0,0 +1,119 @@
+package relay
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	lcpGoModulePath = "github.com/datachainlab/lcp-go"
+
+	// the gRPC metadata keys attached to the calls to the LCP service
+	metadataKeyRelayerVersion = "x-lcp-go-version"
+	metadataKeyRelayerCommit  = "x-lcp-go-commit"
+)
+
+// Version is the version of lcp-go built into the relayer.
+// It can be set at build time with `-ldflags "-X github.com/datachainlab/lcp-go/relay.Version=<version>"`.
+// If empty, the version is resolved from the build info of the binary.
+var Version = ""
+
+// BuildInfo is the version information of lcp-go built into the relayer
+type BuildInfo struct {
+	// the module version of lcp-go, e.g. "v0.2.12". "(devel)" if lcp-go is the main module built from the source.
+	Version string `json:"version"`
+	// the VCS revision of the binary. It is empty if the binary is not built in a repository.
+	Commit string `json:"commit,omitempty"`
+	// true if the working tree had local modifications
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+var (
+	buildInfoOnce sync.Once
+	buildInfo     BuildInfo
+)
+
+// GetBuildInfo returns the version information of lcp-go built into the relayer
+func GetBuildInfo() BuildInfo {
+	buildInfoOnce.Do(func() {
+		buildInfo = readBuildInfo(Version)
+	})
+	return buildInfo
+}
+
+func readBuildInfo(version string) BuildInfo {
+	info := BuildInfo{Version: "unknown"}
+	bi, ok := debug.ReadBuildInfo()
+	if ok {
+		info.GoVersion = bi.GoVersion
+		if bi.Main.Path == lcpGoModulePath {
+			info.Version = bi.Main.Version
+		}
+		for _, dep := range bi.Deps {
+			if dep.Path != lcpGoModulePath {
+				continue
+			} else if dep.Replace != nil && dep.Replace.Version != "" {
+				info.Version = dep.Replace.Version
+			} else {
+				info.Version = dep.Version
+			}
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Commit = s.Value
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	if version != "" {
+		info.Version = version
+	}
+	return info
+}
+
+// String returns the version with the short commit hash
+func (bi BuildInfo) String() string {
+	s := bi.Version
+	if bi.Commit != "" {
+		commit := bi.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		s += "+" + commit
+		if bi.Modified {
+			s += "-dirty"
+		}
+	}
+	return s
+}
+
+// userAgent returns the user agent of the calls to the LCP service
+func (bi BuildInfo) userAgent() string {
+	return "lcp-go/" + bi.String()
+}
+
+// lcpServiceDialOptions returns the options to attach the version of the relayer to the calls to the LCP service
+func lcpServiceDialOptions(bi BuildInfo) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithUserAgent(bi.userAgent()),
+		grpc.WithChainUnaryInterceptor(buildInfoUnaryClientInterceptor(bi)),
+	}
+}
+
+// buildInfoUnaryClientInterceptor attaches the version of the relayer to the metadata of every outgoing call
+func buildInfoUnaryClientInterceptor(bi BuildInfo) grpc.UnaryClientInterceptor {
+	kv := []string{metadataKeyRelayerVersion, bi.Version}
+	if bi.Commit != "" {
+		kv = append(kv, metadataKeyRelayerCommit, bi.Commit)
+	}
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(metadata.AppendToOutgoingContext(ctx, kv...), method, req, reply, cc, opts...)
+	}
+}
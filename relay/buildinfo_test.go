@@ -0,0 +1,70 @@
+package relay
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// metadataRecordingELCQueryServer records the metadata of the incoming calls
+type metadataRecordingELCQueryServer struct {
+	elc.UnimplementedQueryServer
+	received chan metadata.MD
+}
+
+func (s *metadataRecordingELCQueryServer) Client(ctx context.Context, in *elc.QueryClientRequest) (*elc.QueryClientResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.received <- md
+	return &elc.QueryClientResponse{Found: false}, nil
+}
+
+func TestLCPServiceDialOptionsAttachBuildInfo(t *testing.T) {
+	require := require.New(t)
+	bi := BuildInfo{Version: "v1.2.3", Commit: "0123456789abcdef0123", GoVersion: "go1.22"}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	srv := &metadataRecordingELCQueryServer{received: make(chan metadata.MD, 1)}
+	elc.RegisterQueryServer(server, srv)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.Dial(
+		"bufnet",
+		append([]grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		}, lcpServiceDialOptions(bi)...)...,
+	)
+	require.NoError(err)
+	defer conn.Close()
+
+	client := NewLCPServiceClient(conn)
+	_, err = client.Client(context.TODO(), &elc.QueryClientRequest{ClientId: "07-tendermint-0"})
+	require.NoError(err)
+
+	md := <-srv.received
+	require.Equal([]string{"v1.2.3"}, md.Get(metadataKeyRelayerVersion))
+	require.Equal([]string{"0123456789abcdef0123"}, md.Get(metadataKeyRelayerCommit))
+	userAgent := md.Get("user-agent")
+	require.Len(userAgent, 1)
+	require.True(strings.HasPrefix(userAgent[0], "lcp-go/v1.2.3+0123456789ab "), userAgent[0])
+}
+
+func TestBuildInfoString(t *testing.T) {
+	require.Equal(t, "v1.2.3", BuildInfo{Version: "v1.2.3"}.String())
+	require.Equal(t, "v1.2.3+0123456789ab", BuildInfo{Version: "v1.2.3", Commit: "0123456789abcdef"}.String())
+	require.Equal(t, "v1.2.3+0123456789ab-dirty", BuildInfo{Version: "v1.2.3", Commit: "0123456789abcdef", Modified: true}.String())
+	// the version set at build time takes precedence
+	require.Equal(t, "v9.9.9", readBuildInfo("v9.9.9").Version)
+}
@@ -0,0 +1,94 @@
+package relay
+
+import (
+	"sync"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+const (
+	// DefaultFinalizedHeaderCacheTTL is the lifetime of a cached latest finalized header of the counterparty chain.
+	// It is intended to share the header between code paths within the same relay iteration.
+	DefaultFinalizedHeaderCacheTTL = 3 * time.Second
+)
+
+// finalizedHeaderCache is a short-lived cache for the latest finalized header of the counterparty chain
+type finalizedHeaderCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	chainID   string
+	header    core.Header
+	fetchedAt time.Time
+	// now is overridable for testing
+	now func() time.Time
+}
+
+func newFinalizedHeaderCache(ttl time.Duration) *finalizedHeaderCache {
+	return &finalizedHeaderCache{ttl: ttl, now: time.Now}
+}
+
+// get returns the cached header of the given chain if it is not expired.
+// Otherwise, it fetches the latest finalized header from the chain and caches it.
+func (c *finalizedHeaderCache) get(chain core.FinalityAwareChain) (core.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	chainID := chain.ChainID()
+	if c.header != nil && c.chainID == chainID && now.Sub(c.fetchedAt) < c.ttl {
+		return c.header, nil
+	}
+	header, err := chain.GetLatestFinalizedHeader()
+	if err != nil {
+		return nil, err
+	}
+	c.chainID, c.header, c.fetchedAt = chainID, header, now
+	return header, nil
+}
+
+// invalidate discards the cached header
+func (c *finalizedHeaderCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.chainID, c.header, c.fetchedAt = "", nil, time.Time{}
+}
+
+// initialLightClientStateCache memoizes the initial states of the origin light client created at a requested height.
+// Some origin provers perform expensive historical queries to create them, so the retries to create an ELC reuse the states.
+// The states are invalidated explicitly once they are consumed.
+type initialLightClientStateCache struct {
+	mu             sync.Mutex
+	height         clienttypes.Height
+	clientState    ibcexported.ClientState
+	consensusState ibcexported.ConsensusState
+}
+
+// get returns the memoized states if they were created at `height`.
+// Otherwise, it creates the states with `prover` and memoizes them in place of the previous ones.
+// A nil height selects the latest finalized height, which changes over time, so the states for it are not memoized.
+func (c *initialLightClientStateCache) get(prover core.Prover, height ibcexported.Height) (ibcexported.ClientState, ibcexported.ConsensusState, error) {
+	if height == nil {
+		return prover.CreateInitialLightClientState(nil)
+	}
+	h := clienttypes.NewHeight(height.GetRevisionNumber(), height.GetRevisionHeight())
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clientState != nil && c.height.EQ(h) {
+		return c.clientState, c.consensusState, nil
+	}
+	clientState, consensusState, err := prover.CreateInitialLightClientState(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.height, c.clientState, c.consensusState = h, clientState, consensusState
+	return clientState, consensusState, nil
+}
+
+// invalidate discards the memoized states
+func (c *initialLightClientStateCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.height, c.clientState, c.consensusState = clienttypes.Height{}, nil, nil
+}
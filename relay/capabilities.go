@@ -0,0 +1,167 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceCapability is an RPC of the LCP service that the prover may call
+type ServiceCapability string
+
+const (
+	CapabilityCreateClient         ServiceCapability = "elc_create_client"
+	CapabilityUpdateClient         ServiceCapability = "elc_update_client"
+	CapabilityAggregateMessages    ServiceCapability = "elc_aggregate_messages"
+	CapabilityVerifyMembership     ServiceCapability = "elc_verify_membership"
+	CapabilityVerifyNonMembership  ServiceCapability = "elc_verify_non_membership"
+	CapabilityQueryClient          ServiceCapability = "elc_query_client"
+	CapabilityAvailableEnclaveKeys ServiceCapability = "enclave_available_enclave_keys"
+	CapabilityEnclaveKey           ServiceCapability = "enclave_enclave_key"
+)
+
+// ErrMissingServiceCapabilities is returned if the LCP service does not support the RPCs required by the config
+var ErrMissingServiceCapabilities = errors.New("the LCP service does not support the required capabilities")
+
+// ErrUnsupportedServiceCapability is returned if an optional feature is used, but the LCP service does not support it
+var ErrUnsupportedServiceCapability = errors.New("the LCP service does not support the capability")
+
+// serviceCapabilityProbe probes the support of a capability by calling the RPC with an empty request.
+// The LCP service rejects the request without any side effect, and an older service returns Unimplemented for an unknown RPC.
+type serviceCapabilityProbe struct {
+	capability ServiceCapability
+	// returns true if the capability is required by the config
+	required func(config ProverConfig) bool
+	probe    func(ctx context.Context, client LCPServiceClient) error
+}
+
+func alwaysRequired(ProverConfig) bool { return true }
+
+var serviceCapabilityProbes = []serviceCapabilityProbe{
+	{CapabilityCreateClient, alwaysRequired, func(ctx context.Context, c LCPServiceClient) error {
+		_, err := c.CreateClient(ctx, &elc.MsgCreateClient{})
+		return err
+	}},
+	{CapabilityUpdateClient, alwaysRequired, func(ctx context.Context, c LCPServiceClient) error {
+		_, err := c.UpdateClient(ctx, &elc.MsgUpdateClient{})
+		return err
+	}},
+	{CapabilityAggregateMessages, func(config ProverConfig) bool { return config.MessageAggregation }, func(ctx context.Context, c LCPServiceClient) error {
+		_, err := c.AggregateMessages(ctx, &elc.MsgAggregateMessages{})
+		return err
+	}},
+	{CapabilityVerifyMembership, alwaysRequired, func(ctx context.Context, c LCPServiceClient) error {
+		_, err := c.VerifyMembership(ctx, &elc.MsgVerifyMembership{})
+		return err
+	}},
+	// the proofs of the absence of the values are unavailable without it, but the other proofs are still generated
+	{CapabilityVerifyNonMembership, func(ProverConfig) bool { return false }, func(ctx context.Context, c LCPServiceClient) error {
+		_, err := c.VerifyNonMembership(ctx, &elc.MsgVerifyNonMembership{})
+		return err
+	}},
+	{CapabilityQueryClient, alwaysRequired, func(ctx context.Context, c LCPServiceClient) error {
+		_, err := c.Client(ctx, &elc.QueryClientRequest{})
+		return err
+	}},
+	{CapabilityAvailableEnclaveKeys, alwaysRequired, func(ctx context.Context, c LCPServiceClient) error {
+		_, err := c.AvailableEnclaveKeys(ctx, &enclave.QueryAvailableEnclaveKeysRequest{})
+		return err
+	}},
+	{CapabilityEnclaveKey, func(ProverConfig) bool { return false }, func(ctx context.Context, c LCPServiceClient) error {
+		_, err := c.EnclaveKey(ctx, &enclave.QueryEnclaveKeyRequest{})
+		return err
+	}},
+}
+
+// ServiceCapabilities is the result of the discovery of the capabilities of the LCP service
+type ServiceCapabilities struct {
+	Supported   []ServiceCapability `json:"supported"`
+	Unsupported []ServiceCapability `json:"unsupported"`
+	// the unsupported capabilities required by the config
+	Missing []ServiceCapability `json:"missing"`
+}
+
+// Has returns true if the LCP service supports `capability`
+func (c *ServiceCapabilities) Has(capability ServiceCapability) bool {
+	for _, s := range c.Supported {
+		if s == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// discoverServiceCapabilities probes the capabilities of the LCP service and caches the result.
+// It returns ErrMissingServiceCapabilities listing the missing ones if the service does not support the capabilities required by the config.
+func (pr *Prover) discoverServiceCapabilities(ctx context.Context) (*ServiceCapabilities, error) {
+	var caps ServiceCapabilities
+	for _, p := range serviceCapabilityProbes {
+		supported, err := probeServiceCapability(ctx, pr.config.GetDialTimeout(), pr.lcpServiceClient, p)
+		if err != nil {
+			return nil, err
+		}
+		if supported {
+			caps.Supported = append(caps.Supported, p.capability)
+			continue
+		}
+		caps.Unsupported = append(caps.Unsupported, p.capability)
+		if p.required(pr.config) {
+			caps.Missing = append(caps.Missing, p.capability)
+		}
+	}
+	for _, s := range [][]ServiceCapability{caps.Supported, caps.Unsupported, caps.Missing} {
+		sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	}
+	pr.serviceCapabilities = &caps
+	if len(caps.Missing) > 0 {
+		return &caps, fmt.Errorf("%w: missing=%v", ErrMissingServiceCapabilities, caps.Missing)
+	} else if len(caps.Unsupported) > 0 {
+		pr.getLogger().Warn("the LCP service does not support the optional capabilities, so the features depending on them are disabled", "unsupported", caps.Unsupported)
+	}
+	return &caps, nil
+}
+
+// probeServiceCapability returns true if the RPC of `p` is implemented by the LCP service.
+// It returns an error if the support cannot be determined, e.g. the service is unreachable.
+func probeServiceCapability(ctx context.Context, timeout time.Duration, client LCPServiceClient, p serviceCapabilityProbe) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := p.probe(ctx, client)
+	switch status.Code(err) {
+	case codes.OK:
+		return true, nil
+	case codes.Unimplemented:
+		return false, nil
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return false, fmt.Errorf("failed to probe the capability of the LCP service: capability=%v %w", p.capability, err)
+	default:
+		// the RPC is implemented, but it rejected the empty request
+		return true, nil
+	}
+}
+
+// checkServiceCapability returns ErrUnsupportedServiceCapability if the LCP service is known not to support `capability`.
+// The capability is assumed to be supported if the capabilities have not been discovered.
+func (pr *Prover) checkServiceCapability(capability ServiceCapability) error {
+	if pr.serviceCapabilities == nil || pr.serviceCapabilities.Has(capability) {
+		return nil
+	}
+	return fmt.Errorf("%w: capability=%v", ErrUnsupportedServiceCapability, capability)
+}
+
+// doServiceCapabilities discovers the capabilities of the LCP service.
+// Unlike SetupForRelay, the missing capabilities are reported in the result rather than as an error.
+func (pr *Prover) doServiceCapabilities(ctx context.Context) (*ServiceCapabilities, error) {
+	caps, err := pr.discoverServiceCapabilities(ctx)
+	if err != nil && !errors.Is(err, ErrMissingServiceCapabilities) {
+		return nil, err
+	}
+	return caps, nil
+}
@@ -0,0 +1,133 @@
+package relay
+
+import (
+	"context"
+	"testing"
+
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockCapabilityService is an LCP service that does not implement the RPCs of `unimplemented`.
+// The implemented RPCs reject the empty requests of the probes like the actual service.
+type mockCapabilityService struct {
+	unimplemented map[ServiceCapability]bool
+	// if not nil, all the RPCs fail with it
+	err error
+}
+
+func (s *mockCapabilityService) call(capability ServiceCapability) error {
+	if s.err != nil {
+		return s.err
+	} else if s.unimplemented[capability] {
+		return status.Errorf(codes.Unimplemented, "unknown method %v", capability)
+	}
+	return status.Error(codes.InvalidArgument, "invalid request")
+}
+
+func (s *mockCapabilityService) CreateClient(ctx context.Context, in *elc.MsgCreateClient, opts ...grpc.CallOption) (*elc.MsgCreateClientResponse, error) {
+	return nil, s.call(CapabilityCreateClient)
+}
+
+func (s *mockCapabilityService) UpdateClient(ctx context.Context, in *elc.MsgUpdateClient, opts ...grpc.CallOption) (*elc.MsgUpdateClientResponse, error) {
+	return nil, s.call(CapabilityUpdateClient)
+}
+
+func (s *mockCapabilityService) AggregateMessages(ctx context.Context, in *elc.MsgAggregateMessages, opts ...grpc.CallOption) (*elc.MsgAggregateMessagesResponse, error) {
+	return nil, s.call(CapabilityAggregateMessages)
+}
+
+func (s *mockCapabilityService) VerifyMembership(ctx context.Context, in *elc.MsgVerifyMembership, opts ...grpc.CallOption) (*elc.MsgVerifyMembershipResponse, error) {
+	return nil, s.call(CapabilityVerifyMembership)
+}
+
+func (s *mockCapabilityService) VerifyNonMembership(ctx context.Context, in *elc.MsgVerifyNonMembership, opts ...grpc.CallOption) (*elc.MsgVerifyNonMembershipResponse, error) {
+	return nil, s.call(CapabilityVerifyNonMembership)
+}
+
+func (s *mockCapabilityService) Client(ctx context.Context, in *elc.QueryClientRequest, opts ...grpc.CallOption) (*elc.QueryClientResponse, error) {
+	return nil, s.call(CapabilityQueryClient)
+}
+
+func (s *mockCapabilityService) AvailableEnclaveKeys(ctx context.Context, in *enclave.QueryAvailableEnclaveKeysRequest, opts ...grpc.CallOption) (*enclave.QueryAvailableEnclaveKeysResponse, error) {
+	return nil, s.call(CapabilityAvailableEnclaveKeys)
+}
+
+func (s *mockCapabilityService) EnclaveKey(ctx context.Context, in *enclave.QueryEnclaveKeyRequest, opts ...grpc.CallOption) (*enclave.QueryEnclaveKeyResponse, error) {
+	return nil, s.call(CapabilityEnclaveKey)
+}
+
+func TestDiscoverServiceCapabilities(t *testing.T) {
+	var cases = []struct {
+		name          string
+		unimplemented []ServiceCapability
+		aggregation   bool
+		unsupported   []ServiceCapability
+		missing       []ServiceCapability
+	}{
+		{"all supported", nil, true, nil, nil},
+		{"optional unsupported", []ServiceCapability{CapabilityVerifyNonMembership, CapabilityEnclaveKey}, false, []ServiceCapability{CapabilityVerifyNonMembership, CapabilityEnclaveKey}, nil},
+		{"aggregation unsupported and disabled", []ServiceCapability{CapabilityAggregateMessages}, false, []ServiceCapability{CapabilityAggregateMessages}, nil},
+		{"aggregation unsupported and enabled", []ServiceCapability{CapabilityAggregateMessages}, true, []ServiceCapability{CapabilityAggregateMessages}, []ServiceCapability{CapabilityAggregateMessages}},
+		{"required unsupported", []ServiceCapability{CapabilityQueryClient, CapabilityUpdateClient}, false, []ServiceCapability{CapabilityQueryClient, CapabilityUpdateClient}, []ServiceCapability{CapabilityQueryClient, CapabilityUpdateClient}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			pr.config.MessageAggregation = c.aggregation
+			service := &mockCapabilityService{unimplemented: map[ServiceCapability]bool{}}
+			for _, u := range c.unimplemented {
+				service.unimplemented[u] = true
+			}
+			// the probes see the same errors as the other calls of the prover
+			pr.lcpServiceClient = LCPServiceClient{ELCMsgClient: service, ELCQueryClient: service, EnclaveQueryClient: service}.withErrorClassification()
+
+			caps, err := pr.discoverServiceCapabilities(context.TODO())
+			if len(c.missing) == 0 {
+				require.NoError(err)
+			} else {
+				require.ErrorIs(err, ErrMissingServiceCapabilities)
+				for _, m := range c.missing {
+					require.ErrorContains(err, string(m))
+				}
+			}
+			require.Equal(c.unsupported, caps.Unsupported)
+			require.Equal(c.missing, caps.Missing)
+			require.Len(caps.Supported, len(serviceCapabilityProbes)-len(c.unsupported))
+			require.Equal(caps, pr.serviceCapabilities)
+			for _, u := range c.unsupported {
+				require.ErrorIs(pr.checkServiceCapability(u), ErrUnsupportedServiceCapability)
+			}
+			for _, s := range caps.Supported {
+				require.NoError(pr.checkServiceCapability(s))
+			}
+		})
+	}
+}
+
+func TestDiscoverServiceCapabilitiesUnavailable(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	service := &mockCapabilityService{err: status.Error(codes.Unavailable, "connection refused")}
+	pr.lcpServiceClient = LCPServiceClient{ELCMsgClient: service, ELCQueryClient: service, EnclaveQueryClient: service}
+	_, err := pr.discoverServiceCapabilities(context.TODO())
+	require.ErrorContains(err, "connection refused")
+	// the capabilities are still unknown, so nothing is gated
+	require.Nil(pr.serviceCapabilities)
+	require.NoError(pr.checkServiceCapability(CapabilityVerifyNonMembership))
+}
+
+func TestDoServiceCapabilitiesReportsMissing(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	service := &mockCapabilityService{unimplemented: map[ServiceCapability]bool{CapabilityCreateClient: true}}
+	pr.lcpServiceClient = LCPServiceClient{ELCMsgClient: service, ELCQueryClient: service, EnclaveQueryClient: service}
+	caps, err := pr.doServiceCapabilities(context.TODO())
+	require.NoError(err)
+	require.Equal([]ServiceCapability{CapabilityCreateClient}, caps.Missing)
+}
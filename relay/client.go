@@ -18,10 +18,11 @@ type LCPServiceClient struct {
 	EnclaveQueryClient
 }
 
-func NewLCPServiceClient(conn *grpc.ClientConn) LCPServiceClient {
+// NewLCPServiceClient returns the client of the LCP service whose errors are classified with ServiceErrorCode
+func NewLCPServiceClient(conn grpc.ClientConnInterface) LCPServiceClient {
 	return LCPServiceClient{
 		ELCMsgClient:       elc.NewMsgClient(conn),
 		ELCQueryClient:     elc.NewQueryClient(conn),
 		EnclaveQueryClient: enclave.NewQueryClient(conn),
-	}
+	}.withErrorClassification()
 }
@@ -0,0 +1,185 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// the sources of the clocks compared with the clock of the relayer host
+const (
+	clockSourceCounterparty = "counterparty"
+)
+
+// clockSource is a clock compared with the clock of the relayer host
+type clockSource interface {
+	name() string
+	// now returns the current time of the source
+	now(ctx context.Context) (time.Time, error)
+}
+
+// counterpartyClock is the clock of the counterparty chain, i.e. the timestamp of its latest block.
+// It lags behind the relayer host by up to the block interval even if both clocks are synchronized.
+type counterpartyClock struct {
+	chain core.Chain
+}
+
+var _ clockSource = counterpartyClock{}
+
+func (c counterpartyClock) name() string {
+	return clockSourceCounterparty
+}
+
+func (c counterpartyClock) now(context.Context) (time.Time, error) {
+	height, err := c.chain.LatestHeight()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get the latest height: %w", err)
+	}
+	t, err := c.chain.Timestamp(height)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get the timestamp of the latest block: height=%v %w", height, err)
+	}
+	return t, nil
+}
+
+// ClockSkew is the skew of the clock of the relayer host from the one of a source
+type ClockSkew struct {
+	Source string `json:"source"`
+	// Skew is the time of the relayer host minus the time of the source, i.e. positive if the relayer host is ahead
+	Skew       time.Duration `json:"skew"`
+	MeasuredAt time.Time     `json:"measured_at"`
+}
+
+// clockSkewState is the state of the periodic measurement of the clock skews
+type clockSkewState struct {
+	lastChecked time.Time
+
+	mu sync.Mutex
+	// the last measured skews by source
+	skews map[string]ClockSkew
+	// registers the gauge of the skews once
+	registerOnce sync.Once
+}
+
+func (s *clockSkewState) get(source string) (ClockSkew, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	skew, ok := s.skews[source]
+	return skew, ok
+}
+
+func (s *clockSkewState) set(skew ClockSkew) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.skews == nil {
+		s.skews = make(map[string]ClockSkew)
+	}
+	s.skews[skew.Source] = skew
+}
+
+func (s *clockSkewState) snapshot() []ClockSkew {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	skews := make([]ClockSkew, 0, len(s.skews))
+	for _, skew := range s.skews {
+		skews = append(skews, skew)
+	}
+	return skews
+}
+
+// clockSources returns the sources of the clocks compared with the relayer host.
+// The LCP service does not expose its clock through the RPCs, so only the counterparty chain is compared for now.
+func (pr *Prover) clockSources(counterparty core.Chain) []clockSource {
+	return []clockSource{counterpartyClock{chain: counterparty}}
+}
+
+// checkClockSkewPeriodically measures the clock skews if ClockSkewCheckInterval has elapsed since the last measurement.
+// A failure of the measurement is only logged because the skews are diagnostic.
+func (pr *Prover) checkClockSkewPeriodically(ctx context.Context, counterparty core.Chain, now time.Time) {
+	interval := time.Duration(pr.config.ClockSkewCheckInterval) * time.Second
+	if interval == 0 {
+		return
+	} else if !pr.clockSkew.lastChecked.IsZero() && now.Sub(pr.clockSkew.lastChecked) < interval {
+		return
+	}
+	pr.clockSkew.lastChecked = now
+	pr.checkClockSkews(ctx, pr.clockSources(counterparty), now)
+}
+
+// checkClockSkews measures the skews of the clock of the relayer host at `now` from the clocks of `sources`.
+// It warns and alerts if the absolute value of a skew exceeds ClockSkewThreshold.
+func (pr *Prover) checkClockSkews(ctx context.Context, sources []clockSource, now time.Time) []ClockSkew {
+	pr.clockSkew.registerOnce.Do(pr.registerClockSkewGauge)
+	threshold := pr.config.GetClockSkewThreshold()
+	var skews []ClockSkew
+	for _, source := range sources {
+		t, err := source.now(ctx)
+		if err != nil {
+			pr.getLogger().Warn("failed to measure the clock skew", "source", source.name(), "error", err)
+			continue
+		}
+		skew := ClockSkew{Source: source.name(), Skew: now.Sub(t), MeasuredAt: now}
+		pr.clockSkew.set(skew)
+		skews = append(skews, skew)
+		if skew.Skew > threshold || skew.Skew < -threshold {
+			pr.getLogger().Warn("the clock of the relayer host is skewed", "source", skew.Source, "skew", skew.Skew, "threshold", threshold)
+			pr.alert(AlertClockSkewed, skew.Source, "the clock of the relayer host is skewed beyond the threshold",
+				"skew", skew.Skew,
+				"threshold", threshold,
+				"host_time", now.UTC(),
+				"source_time", t.UTC(),
+			)
+		} else {
+			pr.getLogger().Info("measured the clock skew", "source", skew.Source, "skew", skew.Skew)
+		}
+	}
+	return skews
+}
+
+// correctClockSkew returns `t` corrected by the skew measured against the counterparty chain if ClockSkewCorrection is set.
+// It returns `t` as it is if the skew has not been measured yet.
+func (pr *Prover) correctClockSkew(t time.Time) time.Time {
+	if !pr.config.ClockSkewCorrection {
+		return t
+	}
+	skew, ok := pr.clockSkew.get(clockSourceCounterparty)
+	if !ok {
+		return t
+	}
+	return t.Add(-skew.Skew)
+}
+
+// registerClockSkewGauge exports the last measured skews as a gauge with the metrics sink of the prover
+func (pr *Prover) registerClockSkewGauge() {
+	meter := pr.meter()
+	gauge, err := meter.Float64ObservableGauge(
+		"lcp.clock_skew",
+		metric.WithUnit("s"),
+		metric.WithDescription("skew of the clock of the relayer host from the clock of the source, which is positive if the relayer host is ahead"),
+	)
+	if err != nil {
+		pr.getLogger().Warn("failed to create the gauge of the clock skews", "error", err)
+		return
+	}
+	var chainID string
+	if pr.originChain != nil {
+		chainID = pr.originChain.ChainID()
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, skew := range pr.clockSkew.snapshot() {
+			o.ObserveFloat64(gauge, skew.Skew.Seconds(), metric.WithAttributes(
+				attribute.String("chain_id", chainID),
+				attribute.String("source", skew.Source),
+			))
+		}
+		return nil
+	}, gauge)
+	if err != nil {
+		pr.getLogger().Warn("failed to register the callback of the clock skews", "error", err)
+	}
+}
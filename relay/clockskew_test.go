@@ -0,0 +1,104 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/stretchr/testify/require"
+)
+
+// mockClockSource is a clock which returns `t` or `err`
+type mockClockSource struct {
+	sourceName string
+	t          time.Time
+	err        error
+}
+
+func (s mockClockSource) name() string {
+	return s.sourceName
+}
+
+func (s mockClockSource) now(context.Context) (time.Time, error) {
+	return s.t, s.err
+}
+
+func TestCheckClockSkews(t *testing.T) {
+	require := require.New(t)
+	srv, payloads := newAlertCaptureServer(t, http.StatusOK)
+	pr := newTestProver(t)
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	pr.config.AlertWebhookUrl = srv.URL
+	alerter, err := newProverAlerter(pr.config)
+	require.NoError(err)
+	pr.alerter = alerter
+
+	now := time.Unix(1_700_000_000, 0)
+	skews := pr.checkClockSkews(context.TODO(), []clockSource{
+		// the block time lags behind within the threshold
+		mockClockSource{sourceName: clockSourceCounterparty, t: now.Add(-6 * time.Second)},
+		// the relayer host is behind the source beyond the threshold
+		mockClockSource{sourceName: "skewed", t: now.Add(2 * time.Minute)},
+		mockClockSource{sourceName: "unreachable", err: errors.New("connection refused")},
+	}, now)
+	require.Equal([]ClockSkew{
+		{Source: clockSourceCounterparty, Skew: 6 * time.Second, MeasuredAt: now},
+		{Source: "skewed", Skew: -2 * time.Minute, MeasuredAt: now},
+	}, skews)
+	require.ElementsMatch(skews, pr.clockSkew.snapshot())
+
+	pr.alerter.wait()
+	require.Len(payloads(), 1)
+	var alert Alert
+	require.NoError(json.Unmarshal(payloads()[0], &alert))
+	require.Equal(AlertClockSkewed, alert.Condition)
+	require.Equal("skewed", alert.Subject)
+	require.Equal("-2m0s", alert.Details["skew"])
+	require.Equal("1m0s", alert.Details["threshold"])
+}
+
+func TestCheckClockSkewPeriodically(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	pr.config.ClockSkewCheckInterval = 60
+	now := time.Unix(1_700_000_000, 0)
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 1))
+	cp.blockTime = now.Add(-5 * time.Second)
+
+	pr.checkClockSkewPeriodically(context.TODO(), cp, now)
+	skew, ok := pr.clockSkew.get(clockSourceCounterparty)
+	require.True(ok)
+	require.Equal(5*time.Second, skew.Skew)
+
+	// the interval has not elapsed yet
+	cp.blockTime = now.Add(50 * time.Second)
+	pr.checkClockSkewPeriodically(context.TODO(), cp, now.Add(59*time.Second))
+	skew, _ = pr.clockSkew.get(clockSourceCounterparty)
+	require.Equal(5*time.Second, skew.Skew)
+	pr.checkClockSkewPeriodically(context.TODO(), cp, now.Add(time.Minute))
+	skew, _ = pr.clockSkew.get(clockSourceCounterparty)
+	require.Equal(10*time.Second, skew.Skew)
+}
+
+func TestCheckEKIUpdateNeededWithClockSkewCorrection(t *testing.T) {
+	attestationTime := time.Unix(1_700_000_000, 0)
+	eki := &enclave.EnclaveKeyInfo{EnclaveKeyAddress: make([]byte, 20), AttestationTime: uint64(attestationTime.Unix())}
+	// the key is rotated at 30 minutes after the attestation, and the relayer host is 15 minutes behind the counterparty chain
+	now := attestationTime.Add(20 * time.Minute)
+	counterpartyTime := now.Add(15 * time.Minute)
+
+	for _, correction := range []bool{false, true} {
+		pr := newTestProver(t)
+		pr.config.ClockSkewCorrection = correction
+		// the correction is not applied until the skew is measured
+		require.False(t, pr.checkEKIUpdateNeeded(context.TODO(), now, eki))
+
+		pr.checkClockSkews(context.TODO(), []clockSource{mockClockSource{sourceName: clockSourceCounterparty, t: counterpartyTime}}, now)
+		require.Equal(t, correction, pr.checkEKIUpdateNeeded(context.TODO(), now, eki), "correction=%v", correction)
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/client/flags"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/hyperledger-labs/yui-relayer/config"
 	"github.com/hyperledger-labs/yui-relayer/core"
 	"github.com/spf13/cobra"
@@ -25,6 +27,21 @@ const (
 	flagThresholdNumerator      = "threshold_numerator"
 	flagThresholdDenominator    = "threshold_denominator"
 	flagPermissionlessOperators = "permissionless_operators"
+	flagRehearse                = "rehearse"
+	flagRehearseDir             = "rehearse_dir"
+	flagConcurrency             = "concurrency"
+	flagAcknowledgeRegression   = "acknowledge_height_regression"
+	flagAllowedQuoteStatuses    = "allowed_quote_statuses"
+	flagAllowedAdvisoryIDs      = "allowed_advisory_ids"
+	flagKeyExpiration           = "key_expiration"
+	flagFromHeight              = "from_height"
+	flagToHeight                = "to_height"
+	flagBatchSize               = "batch_size"
+	flagQuiet                   = "quiet"
+	flagResume                  = "resume"
+	flagRevisionNumber          = "revision_number"
+	flagDryRun                  = "dry_run"
+	flagOperatorSignatures      = "operator_signatures"
 )
 
 func LCPCmd(ctx *config.Context) *cobra.Command {
@@ -38,12 +55,31 @@ func LCPCmd(ctx *config.Context) *cobra.Command {
 		updateELCCmd(ctx),
 		restoreELCCmd(ctx),
 		queryELCCmd(ctx),
+		queryRegisteredEnclaveKeysCmd(ctx),
+		queryOperatorsCmd(ctx),
+		exportVerifiedStatesCmd(ctx),
+		batchCmd(ctx),
+		replayProofCmd(ctx),
+		selfTestCmd(ctx),
+		showConfigCmd(ctx),
+		originProverStatusCmd(ctx),
+		serviceCapabilitiesCmd(ctx),
+		queryStatsCmd(ctx),
+		queryQueueCmd(ctx),
+		ackPolicyDriftCmd(ctx),
+		versionCmd(),
 		flags.LineBreak,
+		bootstrapCmd(ctx),
 		availableEnclaveKeysCmd(ctx),
+		enclaveKeyExpirationCmd(ctx),
 		updateEnclaveKeyCmd(ctx),
 		activateClientCmd(ctx),
 		removeEnclaveKeyInfoCmd(ctx),
+		migrateHomeCmd(ctx),
+		gcCmd(ctx),
+		acknowledgeHeightRegressionCmd(ctx),
 		updateOperatorsCmd(ctx),
+		updateClientParamsCmd(ctx),
 	)
 
 	return cmd
@@ -65,7 +101,7 @@ func availableEnclaveKeysCmd(ctx *config.Context) *cobra.Command {
 			} else {
 				target = c[dst]
 			}
-			prover := target.Prover.(*Prover)
+			prover := interactiveProver(target)
 			ekis, err := prover.doAvailableEnclaveKeys(context.TODO())
 			if err != nil {
 				return err
@@ -81,10 +117,638 @@ func availableEnclaveKeysCmd(ctx *config.Context) *cobra.Command {
 	return srcFlag(cmd)
 }
 
+func enclaveKeyExpirationCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enclave-key-expiration [path]",
+		Short: "Show the expiration of the active enclave key on the LCP client and the one assumed by the relayer with the effective key expiration",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var (
+				target   *core.ProvableChain
+				verifier *core.ProvableChain
+			)
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+				verifier = c[dst]
+			} else {
+				target = c[dst]
+				verifier = c[src]
+			}
+			prover := interactiveProver(target)
+			res, err := prover.doEnclaveKeyExpiration(context.TODO(), verifier)
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(res)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return srcFlag(cmd)
+}
+
 func updateEnclaveKeyCmd(ctx *config.Context) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "update-enclave-key [path]",
-		Short: "Register an enclave key into the LCP client",
+		Use:   "update-enclave-key [path]",
+		Short: "Register an enclave key into the LCP client",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var (
+				target   *core.ProvableChain
+				verifier *core.ProvableChain
+			)
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+				verifier = c[dst]
+			} else {
+				target = c[dst]
+				verifier = c[src]
+			}
+			prover := interactiveProver(target)
+			return runWithRehearsal(prover, func() error {
+				return prover.UpdateEKIfNeeded(context.TODO(), verifier)
+			})
+		},
+	}
+	return rehearseFlag(srcFlag(cmd))
+}
+
+func activateClientCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "activate-client [path]",
+		Short: "Activate the LCP client",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			path, err := ctx.Config.Paths.Get(args[0])
+			if err != nil {
+				return err
+			}
+			var (
+				pathEnd      *core.PathEnd
+				target       *core.ProvableChain
+				counterparty *core.ProvableChain
+			)
+			if viper.GetBool(flagSrc) {
+				pathEnd = path.Dst
+				target, counterparty = c[src], c[dst]
+			} else {
+				pathEnd = path.Src
+				target, counterparty = c[dst], c[src]
+			}
+			return runWithRehearsal(interactiveProver(target), func() error {
+				res, err := activateClient(pathEnd, target, counterparty, viper.GetDuration(flagRetryInterval), viper.GetUint(flagRetryMaxAttempts), viper.GetBool(flagAcknowledgeRegression))
+				if res != nil {
+					// the result is printed even if the activation is interrupted to show the updates a next run resumes from
+					bz, merr := json.Marshal(res)
+					if merr != nil {
+						return merr
+					}
+					fmt.Println(string(bz))
+				}
+				return err
+			})
+		},
+	}
+	return acknowledgeRegressionFlag(rehearseFlag(retryMaxAttemptsFlag(retryIntervalFlag(srcFlag(cmd)))))
+}
+
+func createELCCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-elc [path]",
+		Short: "Create ELC client",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var target *core.ProvableChain
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+			} else {
+				target = c[dst]
+			}
+			prover := interactiveProver(target)
+			// an empty ID generates a new one
+			elcClientID := viper.GetString(flagELCClientID)
+			if elcClientID != "" {
+				if err := validateUserELCClientID(elcClientID); err != nil {
+					return err
+				}
+			}
+			height, err := heightSpecFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			out, err := prover.doCreateELC(elcClientID, height)
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	cmd = elcClientIDFlag(heightSpecFlags(srcFlag(cmd)))
+	cmd.MarkFlagRequired(flagELCClientID)
+	return cmd
+}
+
+func updateELCCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-elc [path]",
+		Short: "Update ELC client",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var (
+				target *core.ProvableChain
+			)
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+			} else {
+				target = c[dst]
+			}
+			prover := interactiveProver(target)
+			var elcClientID string
+			if id := viper.GetString(flagELCClientID); id != "" {
+				elcClientID = id
+			} else {
+				elcClientID = prover.GetELCClientID()
+			}
+			var progress UpdateELCProgressFunc
+			if !viper.GetBool(flagQuiet) {
+				// print the progress to stderr to keep the result on stdout parsable
+				progress = func(p UpdateELCProgress) {
+					fmt.Fprintf(os.Stderr, "applied %v/%v: height=%v state_id=%v elapsed=%v\n", p.Applied, p.Total, p.Height, p.StateID, p.Elapsed.Round(time.Millisecond))
+				}
+			}
+			out, err := prover.doUpdateELC(elcClientID, progress)
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return quietFlag(elcClientIDFlag(srcFlag(cmd)))
+}
+
+func queryELCCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-elc [path]",
+		Short: "Query ELC client",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var target *core.ProvableChain
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+			} else {
+				target = c[dst]
+			}
+			prover := interactiveProver(target)
+			var elcClientID string
+			if id := viper.GetString(flagELCClientID); id != "" {
+				elcClientID = id
+			} else {
+				elcClientID = prover.GetELCClientID()
+			}
+			out, err := prover.doQueryELC(elcClientID)
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return elcClientIDFlag(srcFlag(cmd))
+}
+
+func queryRegisteredEnclaveKeysCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-registered-enclave-keys [path]",
+		Short: "List the enclave keys registered in the LCP client and their expirations",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var (
+				target   *core.ProvableChain
+				verifier *core.ProvableChain
+			)
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+				verifier = c[dst]
+			} else {
+				target = c[dst]
+				verifier = c[src]
+			}
+			prover := interactiveProver(target)
+			out, err := prover.doQueryRegisteredEnclaveKeys(verifier)
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return srcFlag(cmd)
+}
+
+func queryOperatorsCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-operators [path]",
+		Short: "Query the operators of the LCP client and the nonce of their updates",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var (
+				target   *core.ProvableChain
+				verifier *core.ProvableChain
+			)
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+				verifier = c[dst]
+			} else {
+				target = c[dst]
+				verifier = c[src]
+			}
+			prover := interactiveProver(target)
+			out, err := prover.doQueryOperators(verifier)
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return srcFlag(cmd)
+}
+
+func bootstrapCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap [path]",
+		Short: "Set up the ELC client and the LCP client: create the ELC, create the client, register an enclave key and activate the client",
+		Long: `Set up the ELC client and the LCP client in order: create the ELC, create the client, register an enclave key and activate the client.
+The steps already done are skipped, and the result is verified at the end.
+If a step fails, the bootstrap stops with a checkpoint; resume it with --resume after fixing the cause.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pathName := args[0]
+			c, src, dst, err := ctx.Config.ChainsFromPath(pathName)
+			if err != nil {
+				return err
+			}
+			path, err := ctx.Config.Paths.Get(pathName)
+			if err != nil {
+				return err
+			}
+			var (
+				pathEnd      *core.PathEnd
+				target       *core.ProvableChain
+				counterparty *core.ProvableChain
+			)
+			if viper.GetBool(flagSrc) {
+				pathEnd = path.Dst
+				target, counterparty = c[src], c[dst]
+			} else {
+				pathEnd = path.Src
+				target, counterparty = c[dst], c[src]
+			}
+			prover := interactiveProver(target)
+			actions := bootstrapActions{
+				createClient: func() error {
+					// the clients of both directions are created if they do not exist, as the relayer's path setup does
+					return core.CreateClients(pathName, c[src], c[dst], nil, nil)
+				},
+				activateClient: func() error {
+					_, err := activateClient(pathEnd, target, counterparty, viper.GetDuration(flagRetryInterval), viper.GetUint(flagRetryMaxAttempts), false)
+					return err
+				},
+			}
+			var progress BootstrapProgressFunc
+			if !viper.GetBool(flagQuiet) {
+				// print the progress to stderr to keep the result on stdout parsable
+				progress = func(step *BootstrapStep) {
+					if step.Error != "" {
+						fmt.Fprintf(os.Stderr, "%v: %v: %v\n", step.Name, step.Status, step.Error)
+					} else {
+						fmt.Fprintf(os.Stderr, "%v: %v\n", step.Name, step.Status)
+					}
+				}
+			}
+			out, err := prover.doBootstrap(context.TODO(), counterparty, actions, viper.GetBool(flagResume), progress)
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			if !out.Completed {
+				return fmt.Errorf("bootstrap failed; resume it with --resume after fixing the cause: elc_client_id=%v", out.ELCClientID)
+			}
+			return nil
+		},
+	}
+	return resumeFlag(quietFlag(retryMaxAttemptsFlag(retryIntervalFlag(srcFlag(cmd)))))
+}
+
+func selfTestCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self-test [path]",
+		Short: "Run a self-test of the prover against a temporary ELC client without touching the counterparty chain",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var target *core.ProvableChain
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+			} else {
+				target = c[dst]
+			}
+			prover := interactiveProver(target)
+			var elcClientID string
+			if id := viper.GetString(flagELCClientID); id != "" {
+				elcClientID = id
+			} else {
+				elcClientID = newSelfTestELCClientID(time.Now())
+			}
+			out := prover.doSelfTest(context.TODO(), elcClientID)
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			if !out.Passed {
+				return fmt.Errorf("self-test failed: elc_client_id=%v", elcClientID)
+			}
+			return nil
+		},
+	}
+	return elcClientIDFlag(srcFlag(cmd))
+}
+
+func showConfigCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show-config [path]",
+		Short: "Show the effective prover config with the credentials redacted",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var target *core.ProvableChain
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+			} else {
+				target = c[dst]
+			}
+			prover := interactiveProver(target)
+			out, err := prover.doShowConfig()
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return srcFlag(cmd)
+}
+
+func originProverStatusCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "origin-prover-status [path]",
+		Short: "Get the latest finalized header from each origin prover and show the health of them",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var target *core.ProvableChain
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+			} else {
+				target = c[dst]
+			}
+			prover := interactiveProver(target)
+			provers := prover.originProvers()
+			provers.Probe()
+			bz, err := json.Marshal(provers.Statuses())
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return srcFlag(cmd)
+}
+
+func serviceCapabilitiesCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service-capabilities [path]",
+		Short: "Probe the LCP service and show the supported capabilities",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var target *core.ProvableChain
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+			} else {
+				target = c[dst]
+			}
+			prover := interactiveProver(target)
+			caps, err := prover.doServiceCapabilities(context.TODO())
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(caps)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return srcFlag(cmd)
+}
+
+func queryStatsCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-stats [path]",
+		Short: "Show the stats of the paths persisted in the prover home",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var target *core.ProvableChain
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+			} else {
+				target = c[dst]
+			}
+			prover := interactiveProver(target)
+			out, err := prover.doQueryStats()
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return srcFlag(cmd)
+}
+
+func queryQueueCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query-queue [path]",
+		Short: "Show the msgs waiting in the submission queue in the order of the submission",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var target *core.ProvableChain
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+			} else {
+				target = c[dst]
+			}
+			prover := interactiveProver(target)
+			out, err := prover.doQueryQueue()
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return srcFlag(cmd)
+}
+
+func ackPolicyDriftCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ack-policy-drift [path]",
+		Short: "Acknowledge the drift of the attestation policy to resume the proof generation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var target *core.ProvableChain
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+			} else {
+				target = c[dst]
+			}
+			prover := interactiveProver(target)
+			out, err := prover.doAcknowledgePolicyDrift(time.Now())
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(out)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+	return srcFlag(cmd)
+}
+
+func versionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Show the version of lcp-go built into the relayer",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := json.Marshal(GetBuildInfo())
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
+		},
+	}
+}
+
+func restoreELCCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore-elc [path]",
+		Short: "Restore ELC client state with the latest height of the LCP Client on the counterparty chain",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
@@ -102,73 +766,115 @@ func updateEnclaveKeyCmd(ctx *config.Context) *cobra.Command {
 				target = c[dst]
 				verifier = c[src]
 			}
-			prover := target.Prover.(*Prover)
-			return prover.UpdateEKIfNeeded(context.TODO(), verifier)
+			prover := interactiveProver(target)
+			var elcClientID string
+			if id := viper.GetString(flagELCClientID); id != "" {
+				elcClientID = id
+			} else {
+				elcClientID = prover.GetELCClientID()
+			}
+			return prover.restoreELC(context.TODO(), verifier, elcClientID, viper.GetUint64(flagHeight))
 		},
 	}
-	return srcFlag(cmd)
+	return elcClientIDFlag(heightFlag(srcFlag(cmd)))
 }
 
-func activateClientCmd(ctx *config.Context) *cobra.Command {
+func exportVerifiedStatesCmd(ctx *config.Context) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "activate-client [path]",
-		Short: "Activate the LCP client",
-		Args:  cobra.ExactArgs(1),
+		Use:   "export-verified-states [path] [output]",
+		Short: "Export the states verified by the LCP client on the counterparty chain as JSON Lines",
+		Long:  "Export the states verified by the LCP client on the counterparty chain as JSON Lines. If the checkpoint of the output exists, the interrupted export is resumed.",
+		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
 			if err != nil {
 				return err
 			}
-			path, err := ctx.Config.Paths.Get(args[0])
-			if err != nil {
-				return err
-			}
 			var (
-				pathEnd      *core.PathEnd
 				target       *core.ProvableChain
 				counterparty *core.ProvableChain
 			)
 			if viper.GetBool(flagSrc) {
-				pathEnd = path.Dst
 				target, counterparty = c[src], c[dst]
 			} else {
-				pathEnd = path.Src
 				target, counterparty = c[dst], c[src]
 			}
-			return activateClient(pathEnd, target, counterparty, viper.GetDuration(flagRetryInterval), viper.GetUint(flagRetryMaxAttempts))
+			prover := interactiveProver(target)
+			res, err := prover.doExportVerifiedStates(context.TODO(), counterparty, viper.GetUint64(flagFromHeight), viper.GetUint64(flagToHeight), args[1], viper.GetUint64(flagBatchSize))
+			if err != nil {
+				return err
+			}
+			bz, err := json.Marshal(res)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
 		},
 	}
-	return retryMaxAttemptsFlag(retryIntervalFlag(srcFlag(cmd)))
+	return heightRangeFlags(srcFlag(cmd))
 }
 
-func createELCCmd(ctx *config.Context) *cobra.Command {
+func batchCmd(ctx *config.Context) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "create-elc [path]",
-		Short: "Create ELC client",
+		Use:   "batch [file]",
+		Short: "Execute ELC operations listed in a YAML or JSON file",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			file, err := LoadBatchFile(args[0])
 			if err != nil {
 				return err
 			}
-			var target *core.ProvableChain
-			if viper.GetBool(flagSrc) {
-				target = c[src]
-			} else {
-				target = c[dst]
+			concurrency := file.Concurrency
+			if cmd.Flags().Changed(flagConcurrency) {
+				concurrency = viper.GetUint(flagConcurrency)
 			}
-			prover := target.Prover.(*Prover)
-			var elcClientID string
-			if viper.GetString(flagELCClientID) != "" {
-				elcClientID = viper.GetString(flagELCClientID)
-			} else {
-				elcClientID = prover.config.ElcClientId
+			report := RunBatch(file.Operations, concurrency, func(op BatchOperation) (*Prover, error) {
+				c, src, dst, err := ctx.Config.ChainsFromPath(op.Path)
+				if err != nil {
+					return nil, err
+				}
+				var target *core.ProvableChain
+				if op.Src {
+					target = c[src]
+				} else {
+					target = c[dst]
+				}
+				prover, ok := target.Prover.(*Prover)
+				if !ok {
+					return nil, fmt.Errorf("the prover is not an LCP prover: chain_id=%v", target.ChainID())
+				}
+				return prover, nil
+			})
+			bz, err := json.Marshal(report)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			if report.Failed > 0 {
+				return fmt.Errorf("%v of %v operations failed", report.Failed, report.Total)
+			}
+			return nil
+		},
+	}
+	return concurrencyFlag(cmd)
+}
+
+func replayProofCmd(ctx *config.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "replay-proof [file]",
+		Short: "Re-verify the signatures of an archived proof and decode its message",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := LoadArchivedProof(args[0])
+			if err != nil {
+				return err
 			}
-			out, err := prover.doCreateELC(elcClientID, viper.GetUint64(flagHeight))
+			res, err := ReplayArchivedProof(p)
 			if err != nil {
 				return err
 			}
-			bz, err := json.Marshal(out)
+			bz, err := json.Marshal(res)
 			if err != nil {
 				return err
 			}
@@ -176,55 +882,35 @@ func createELCCmd(ctx *config.Context) *cobra.Command {
 			return nil
 		},
 	}
-	cmd = elcClientIDFlag(heightFlag(srcFlag(cmd)))
-	cmd.MarkFlagRequired(flagELCClientID)
-	return cmd
 }
 
-func updateELCCmd(ctx *config.Context) *cobra.Command {
+func removeEnclaveKeyInfoCmd(ctx *config.Context) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "update-elc [path]",
-		Short: "Update ELC client",
+		Use:   "remove-eki [path]",
+		Short: "Remove finalized and unfinalized EKIs in the relayer's home directory",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
 			if err != nil {
 				return err
 			}
-			var (
-				target *core.ProvableChain
-			)
+			var target *core.ProvableChain
 			if viper.GetBool(flagSrc) {
 				target = c[src]
 			} else {
 				target = c[dst]
 			}
-			prover := target.Prover.(*Prover)
-			var elcClientID string
-			if id := viper.GetString(flagELCClientID); id != "" {
-				elcClientID = id
-			} else {
-				elcClientID = prover.config.ElcClientId
-			}
-			out, err := prover.doUpdateELC(elcClientID)
-			if err != nil {
-				return err
-			}
-			bz, err := json.Marshal(out)
-			if err != nil {
-				return err
-			}
-			fmt.Println(string(bz))
-			return nil
+			prover := interactiveProver(target)
+			return prover.removeEnclaveKeyInfos(context.TODO())
 		},
 	}
-	return elcClientIDFlag(srcFlag(cmd))
+	return srcFlag(cmd)
 }
 
-func queryELCCmd(ctx *config.Context) *cobra.Command {
+func gcCmd(ctx *config.Context) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "query-elc [path]",
-		Short: "Query ELC client",
+		Use:   "gc [path]",
+		Short: "Remove the artifacts in the relayer's home directory which the retention policies do not retain",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
@@ -237,14 +923,8 @@ func queryELCCmd(ctx *config.Context) *cobra.Command {
 			} else {
 				target = c[dst]
 			}
-			prover := target.Prover.(*Prover)
-			var elcClientID string
-			if id := viper.GetString(flagELCClientID); id != "" {
-				elcClientID = id
-			} else {
-				elcClientID = prover.config.ElcClientId
-			}
-			out, err := prover.doQueryELC(elcClientID)
+			prover := interactiveProver(target)
+			out, err := prover.doGC(context.TODO(), time.Now(), viper.GetBool(flagDryRun))
 			if err != nil {
 				return err
 			}
@@ -256,61 +936,76 @@ func queryELCCmd(ctx *config.Context) *cobra.Command {
 			return nil
 		},
 	}
-	return elcClientIDFlag(srcFlag(cmd))
+	return dryRunFlag(srcFlag(cmd))
 }
 
-func restoreELCCmd(ctx *config.Context) *cobra.Command {
+func migrateHomeCmd(ctx *config.Context) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "restore-elc [path]",
-		Short: "Restore ELC client state with the latest height of the LCP Client on the counterparty chain",
+		Use:   "migrate-home [path]",
+		Short: "Switch the relayer's home directory to the enclave mode of the prover config, removing the EKIs of the previous mode",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := ctx.Config.Paths.Get(args[0])
+			if err != nil {
+				return err
+			}
+			// the provers are initialized before the command runs, so the check must be skipped before SetRelayInfo in ChainsFromPath
+			for _, chainID := range []string{path.Src.ChainID, path.Dst.ChainID} {
+				chain, err := ctx.Config.GetChain(chainID)
+				if err != nil {
+					return err
+				}
+				if pr, ok := chain.Prover.(*Prover); ok {
+					pr.SetSkipEnclaveModeCheck(true)
+				}
+			}
 			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
 			if err != nil {
 				return err
 			}
-			var (
-				target   *core.ProvableChain
-				verifier *core.ProvableChain
-			)
+			var target *core.ProvableChain
 			if viper.GetBool(flagSrc) {
 				target = c[src]
-				verifier = c[dst]
 			} else {
 				target = c[dst]
-				verifier = c[src]
 			}
-			prover := target.Prover.(*Prover)
-			var elcClientID string
-			if id := viper.GetString(flagELCClientID); id != "" {
-				elcClientID = id
-			} else {
-				elcClientID = prover.config.ElcClientId
+			prover := interactiveProver(target)
+			res, err := prover.doMigrateHome(context.TODO())
+			if err != nil {
+				return err
 			}
-			return prover.restoreELC(context.TODO(), verifier, elcClientID, viper.GetUint64(flagHeight))
+			bz, err := json.Marshal(res)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(bz))
+			return nil
 		},
 	}
-	return elcClientIDFlag(heightFlag(srcFlag(cmd)))
+	return srcFlag(cmd)
 }
 
-func removeEnclaveKeyInfoCmd(ctx *config.Context) *cobra.Command {
+func acknowledgeHeightRegressionCmd(ctx *config.Context) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "remove-eki [path]",
-		Short: "Remove finalized and unfinalized EKIs in the relayer's home directory",
+		Use:   "acknowledge-height-regression [path]",
+		Short: "Acknowledge the regression of the counterparty LCP client's latest height and continue the relay from the current height",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
 			if err != nil {
 				return err
 			}
-			var target *core.ProvableChain
+			var (
+				target       *core.ProvableChain
+				counterparty *core.ProvableChain
+			)
 			if viper.GetBool(flagSrc) {
-				target = c[src]
+				target, counterparty = c[src], c[dst]
 			} else {
-				target = c[dst]
+				target, counterparty = c[dst], c[src]
 			}
-			prover := target.Prover.(*Prover)
-			return prover.removeEnclaveKeyInfos(context.TODO())
+			prover := interactiveProver(target)
+			return prover.acknowledgeCounterpartyClientHeightRegression(context.TODO(), counterparty)
 		},
 	}
 	return srcFlag(cmd)
@@ -319,7 +1014,7 @@ func removeEnclaveKeyInfoCmd(ctx *config.Context) *cobra.Command {
 func updateOperatorsCmd(ctx *config.Context) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "update-operators [path]",
-		Short: "Update operators",
+		Short: "Rotate the operators of the LCP client on the counterparty chain, signing with the local operator signer and the given signatures",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
@@ -337,7 +1032,7 @@ func updateOperatorsCmd(ctx *config.Context) *cobra.Command {
 				target = c[dst]
 				counterparty = c[src]
 			}
-			prover := target.Prover.(*Prover)
+			prover := interactiveProver(target)
 
 			newOperators := viper.GetStringSlice(flagNewOperators)
 			viper.GetBool(flagPermissionlessOperators)
@@ -347,35 +1042,137 @@ func updateOperatorsCmd(ctx *config.Context) *cobra.Command {
 				return fmt.Errorf("both new operators and permissionless operators cannot be provided")
 			}
 			var newOpAddrs []common.Address
-			for _, op := range newOperators {
-				if !common.IsHexAddress(op) {
-					return fmt.Errorf("invalid operator address: %s", op)
+			for i, op := range newOperators {
+				addr, err := parseHexAddress(op)
+				if err != nil {
+					return fmt.Errorf("invalid operator address: index=%v value=%q %w", i, op, err)
 				}
-				newOpAddrs = append(newOpAddrs, common.HexToAddress(op))
+				newOpAddrs = append(newOpAddrs, addr)
 			}
-			threshold := Fraction{
-				Numerator:   viper.GetUint64(flagThresholdNumerator),
-				Denominator: viper.GetUint64(flagThresholdDenominator),
+			var signatures [][]byte
+			for i, s := range viper.GetStringSlice(flagOperatorSignatures) {
+				var sig []byte
+				if s != "" {
+					if sig, err = hexutil.Decode(s); err != nil {
+						return fmt.Errorf("invalid operator signature: index=%v value=%q %w", i, s, err)
+					}
+				}
+				signatures = append(signatures, sig)
 			}
-			nonce := viper.GetUint64(flagNonce)
-			return prover.updateOperators(counterparty, nonce, newOpAddrs, threshold)
+			return runWithRehearsal(prover, func() error {
+				out, err := prover.doUpdateOperators(counterparty, newOpAddrs, viper.GetUint64(flagThresholdNumerator), viper.GetUint64(flagThresholdDenominator), signatures)
+				if err != nil {
+					return err
+				}
+				bz, err := json.Marshal(out)
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(bz))
+				if out.Status == "failed" {
+					return fmt.Errorf("the msg execution failed: msg_id=%v", out.MsgID)
+				}
+				return nil
+			})
 		},
 	}
-	cmd = thresholdFlag(
-		nonceFlag(
+	cmd = rehearseFlag(thresholdFlag(
+		operatorSignaturesFlag(
 			permissionlessOperatorsFlag(
 				newOperatorsFlag(
 					srcFlag(cmd),
 				),
 			),
 		),
-	)
+	))
 	cmd.MarkFlagRequired(flagThresholdNumerator)
 	cmd.MarkFlagRequired(flagThresholdDenominator)
+	return cmd
+}
+
+func updateClientParamsCmd(ctx *config.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-client-params [path]",
+		Short: "Update the allowed quote statuses, the allowed advisory IDs and the key expiration of the LCP client",
+		Long:  "Update the allowed quote statuses, the allowed advisory IDs and the key expiration of the LCP client. The enclave keys already registered in the client are not affected, and the new key expiration applies to the keys registered afterwards.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, src, dst, err := ctx.Config.ChainsFromPath(args[0])
+			if err != nil {
+				return err
+			}
+			var (
+				target       *core.ProvableChain
+				counterparty *core.ProvableChain
+			)
+			if viper.GetBool(flagSrc) {
+				target = c[src]
+				counterparty = c[dst]
+			} else {
+				target = c[dst]
+				counterparty = c[src]
+			}
+			prover := interactiveProver(target)
+
+			nonce := viper.GetUint64(flagNonce)
+			allowedQuoteStatuses := viper.GetStringSlice(flagAllowedQuoteStatuses)
+			allowedAdvisoryIDs := viper.GetStringSlice(flagAllowedAdvisoryIDs)
+			keyExpiration := viper.GetUint64(flagKeyExpiration)
+			return runWithRehearsal(prover, func() error {
+				return prover.doUpdateClientParams(counterparty, nonce, allowedQuoteStatuses, allowedAdvisoryIDs, keyExpiration)
+			})
+		},
+	}
+	cmd = rehearseFlag(clientParamsFlag(
+		nonceFlag(
+			srcFlag(cmd),
+		),
+	))
+	cmd.MarkFlagRequired(flagKeyExpiration)
 	cmd.MarkFlagRequired(flagNonce)
 	return cmd
 }
 
+// interactiveProver returns the prover of the chain for an interactive command.
+// The prover bypasses the rate limit of the LCP service as the command issues only a few calls.
+func interactiveProver(chain *core.ProvableChain) *Prover {
+	prover := chain.Prover.(*Prover)
+	prover.bypassRateLimit()
+	return prover
+}
+
+// runWithRehearsal runs `fn` in the rehearsal mode if the rehearse flag is set,
+// and prints the rehearsal report.
+func runWithRehearsal(prover *Prover, fn func() error) error {
+	if !viper.GetBool(flagRehearse) {
+		return fn()
+	}
+	outputDir := viper.GetString(flagRehearseDir)
+	if outputDir == "" {
+		dir, err := os.MkdirTemp("", "lcp-rehearsal-out-")
+		if err != nil {
+			return err
+		}
+		outputDir = dir
+	}
+	if err := prover.StartRehearsal(outputDir); err != nil {
+		return err
+	}
+	err := fn()
+	report, finishErr := prover.FinishRehearsal()
+	if err != nil {
+		return err
+	} else if finishErr != nil {
+		return finishErr
+	}
+	bz, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bz))
+	return nil
+}
+
 func srcFlag(cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().BoolP(flagSrc, "", true, "a boolean value whether src is the target chain")
 	if err := viper.BindPFlag(flagSrc, cmd.Flags().Lookup(flagSrc)); err != nil {
@@ -392,6 +1189,40 @@ func heightFlag(cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+// heightSpecFlags adds the flags of a height of the origin chain.
+// The flags are not bound to viper because "height" of the other commands is an integer.
+func heightSpecFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().StringP(flagHeight, "", "", `a height of the origin chain: "{revision height}", "{revision number}-{revision height}" or "{revision number}/{revision height}". If empty, the latest height is used`)
+	cmd.Flags().Uint64P(flagRevisionNumber, "", 0, "a revision number of the height. If not given, the current revision of the origin chain is used")
+	return cmd
+}
+
+// heightSpecFromFlags returns the height given by the flags added by heightSpecFlags
+func heightSpecFromFlags(cmd *cobra.Command) (HeightSpec, error) {
+	s, err := cmd.Flags().GetString(flagHeight)
+	if err != nil {
+		return HeightSpec{}, err
+	}
+	height, err := ParseHeightSpec(s)
+	if err != nil {
+		return HeightSpec{}, err
+	}
+	if !cmd.Flags().Changed(flagRevisionNumber) {
+		return height, nil
+	}
+	if height.IsLatest() {
+		return HeightSpec{}, fmt.Errorf("--%v requires --%v", flagRevisionNumber, flagHeight)
+	} else if height.RevisionNumber != nil {
+		return HeightSpec{}, fmt.Errorf("--%v cannot be used with a height which has a revision number: height=%q", flagRevisionNumber, s)
+	}
+	revisionNumber, err := cmd.Flags().GetUint64(flagRevisionNumber)
+	if err != nil {
+		return HeightSpec{}, err
+	}
+	height.RevisionNumber = &revisionNumber
+	return height, nil
+}
+
 func retryIntervalFlag(cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().DurationP(flagRetryInterval, "", time.Second, "a retry interval duration")
 	if err := viper.BindPFlag(flagRetryInterval, cmd.Flags().Lookup(flagRetryInterval)); err != nil {
@@ -416,6 +1247,22 @@ func elcClientIDFlag(cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+func resumeFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().BoolP(flagResume, "", false, "resume the bootstrap from the checkpoint of the failed one")
+	if err := viper.BindPFlag(flagResume, cmd.Flags().Lookup(flagResume)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func quietFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().BoolP(flagQuiet, "", false, "suppress the progress output")
+	if err := viper.BindPFlag(flagQuiet, cmd.Flags().Lookup(flagQuiet)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
 func newOperatorsFlag(cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().StringSliceP(flagNewOperators, "", nil, "new operator addresses")
 	if err := viper.BindPFlag(flagNewOperators, cmd.Flags().Lookup(flagNewOperators)); err != nil {
@@ -424,6 +1271,14 @@ func newOperatorsFlag(cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+func operatorSignaturesFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().StringSliceP(flagOperatorSignatures, "", nil, "hex-encoded signatures of the current operators in the order of the operators of the client, with empty ones for the operators who do not sign")
+	if err := viper.BindPFlag(flagOperatorSignatures, cmd.Flags().Lookup(flagOperatorSignatures)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
 func nonceFlag(cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().Uint64P(flagNonce, "", 0, "a nonce")
 	if err := viper.BindPFlag(flagNonce, cmd.Flags().Lookup(flagNonce)); err != nil {
@@ -444,6 +1299,22 @@ func thresholdFlag(cmd *cobra.Command) *cobra.Command {
 	return cmd
 }
 
+func clientParamsFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().StringSliceP(flagAllowedQuoteStatuses, "", nil, "new allowed quote statuses")
+	cmd.Flags().StringSliceP(flagAllowedAdvisoryIDs, "", nil, "new allowed advisory IDs")
+	cmd.Flags().Uint64P(flagKeyExpiration, "", 0, "a new key expiration in seconds for the enclave keys registered after the update")
+	if err := viper.BindPFlag(flagAllowedQuoteStatuses, cmd.Flags().Lookup(flagAllowedQuoteStatuses)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagAllowedAdvisoryIDs, cmd.Flags().Lookup(flagAllowedAdvisoryIDs)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagKeyExpiration, cmd.Flags().Lookup(flagKeyExpiration)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
 func permissionlessOperatorsFlag(cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().BoolP(flagPermissionlessOperators, "", false, "a boolean value whether the new operators are permissionless")
 	if err := viper.BindPFlag(flagPermissionlessOperators, cmd.Flags().Lookup(flagPermissionlessOperators)); err != nil {
@@ -451,3 +1322,51 @@ func permissionlessOperatorsFlag(cmd *cobra.Command) *cobra.Command {
 	}
 	return cmd
 }
+
+func rehearseFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().BoolP(flagRehearse, "", false, "a boolean value whether to write the msgs into files instead of submitting them")
+	cmd.Flags().StringP(flagRehearseDir, "", "", "a directory to write the msgs in the rehearsal mode (default: a temporary directory)")
+	if err := viper.BindPFlag(flagRehearse, cmd.Flags().Lookup(flagRehearse)); err != nil {
+		panic(err)
+	}
+	if err := viper.BindPFlag(flagRehearseDir, cmd.Flags().Lookup(flagRehearseDir)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func dryRunFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().BoolP(flagDryRun, "", false, "a boolean value whether to only list the entries to be removed")
+	if err := viper.BindPFlag(flagDryRun, cmd.Flags().Lookup(flagDryRun)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func acknowledgeRegressionFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().BoolP(flagAcknowledgeRegression, "", false, "a boolean value whether to acknowledge the regression of the counterparty LCP client's latest height")
+	if err := viper.BindPFlag(flagAcknowledgeRegression, cmd.Flags().Lookup(flagAcknowledgeRegression)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func heightRangeFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Uint64P(flagFromHeight, "", 1, "the first revision height to export")
+	cmd.Flags().Uint64P(flagToHeight, "", 0, "the last revision height to export (default: the latest height of the client)")
+	cmd.Flags().Uint64P(flagBatchSize, "", DefaultExportBatchSize, "the number of heights queried between the checkpoints")
+	for _, name := range []string{flagFromHeight, flagToHeight, flagBatchSize} {
+		if err := viper.BindPFlag(name, cmd.Flags().Lookup(name)); err != nil {
+			panic(err)
+		}
+	}
+	return cmd
+}
+
+func concurrencyFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().UintP(flagConcurrency, "", 0, "the maximum number of provers processed concurrently (overrides the batch file)")
+	if err := viper.BindPFlag(flagConcurrency, cmd.Flags().Lookup(flagConcurrency)); err != nil {
+		panic(err)
+	}
+	return cmd
+}
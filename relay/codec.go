@@ -1,16 +1,96 @@
 package relay
 
 import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
 	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/gogoproto/proto"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
 	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
 	"github.com/hyperledger-labs/yui-relayer/core"
 )
 
 // RegisterInterfaces register the module interfaces to protobuf Any.
+// It registers every type the prover packs or unpacks by itself:
+// the client state, the consensus state and the client messages of the LCP client, the msgs of the IBC client carrying them, and the prover config.
+// The states of the origin chain carried by the ELC protos are registered by the module of the origin chain.
 func RegisterInterfaces(registry codectypes.InterfaceRegistry) {
+	clienttypes.RegisterInterfaces(registry)
 	lcptypes.RegisterInterfaces(registry)
 	registry.RegisterImplementations(
 		(*core.ProverConfig)(nil),
 		&ProverConfig{},
 	)
 }
+
+// ensureInterfacesRegistered registers the module interfaces to `registry` even if they are already registered by the relayer.
+// Registering the same type twice is a no-op, but the registry panics if another type is registered with the same type URL, so the panic is returned as an error.
+func ensureInterfacesRegistered(registry codectypes.InterfaceRegistry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to register the interfaces of the LCP prover: %v", r)
+		}
+	}()
+	RegisterInterfaces(registry)
+	return nil
+}
+
+// checkCodec packs every type registered by RegisterInterfaces into Any and unpacks it with `cdc`.
+// It returns an error with the type URL of the first type the codec cannot unpack, so a misconfigured codec fails at the startup instead of in the middle of the relay.
+func checkCodec(cdc codec.ProtoCodecMarshaler) error {
+	clientState, consensusState := &lcptypes.ClientState{}, &lcptypes.ConsensusState{}
+	updateClientMessage := &lcptypes.UpdateClientMessage{}
+	cases := []struct {
+		msg    proto.Message
+		unpack func(packed *codectypes.Any) error
+	}{
+		{clientState, unpackAs[ibcexported.ClientState](cdc)},
+		{consensusState, unpackAs[ibcexported.ConsensusState](cdc)},
+		{updateClientMessage, unpackAs[ibcexported.ClientMessage](cdc)},
+		{&lcptypes.RegisterEnclaveKeyMessage{}, unpackAs[ibcexported.ClientMessage](cdc)},
+		{&lcptypes.DCAPRegisterEnclaveKeyMessage{}, unpackAs[ibcexported.ClientMessage](cdc)},
+		{&lcptypes.UpdateOperatorsMessage{}, unpackAs[ibcexported.ClientMessage](cdc)},
+		{&lcptypes.UpdateClientParamsMessage{}, unpackAs[ibcexported.ClientMessage](cdc)},
+		{&ProverConfig{}, unpackAs[core.ProverConfig](cdc)},
+	}
+	for _, c := range cases {
+		packed, err := codectypes.NewAnyWithValue(c.msg)
+		if err != nil {
+			return fmt.Errorf("failed to pack %v: %w", sdk.MsgTypeURL(c.msg), err)
+		}
+		if err := c.unpack(packed); err != nil {
+			return fmt.Errorf("the codec cannot unpack %v: %w", packed.TypeUrl, err)
+		}
+	}
+
+	// the msgs are also queued and rehearsed in JSON
+	createClient, err := clienttypes.NewMsgCreateClient(clientState, consensusState, "")
+	if err != nil {
+		return fmt.Errorf("failed to pack %v: %w", sdk.MsgTypeURL(&clienttypes.MsgCreateClient{}), err)
+	}
+	updateClient, err := clienttypes.NewMsgUpdateClient("", updateClientMessage, "")
+	if err != nil {
+		return fmt.Errorf("failed to pack %v: %w", sdk.MsgTypeURL(&clienttypes.MsgUpdateClient{}), err)
+	}
+	for _, msg := range []sdk.Msg{createClient, updateClient} {
+		bz, err := cdc.MarshalInterfaceJSON(msg)
+		if err != nil {
+			return fmt.Errorf("the codec cannot marshal %v: %w", sdk.MsgTypeURL(msg), err)
+		}
+		var decoded sdk.Msg
+		if err := cdc.UnmarshalInterfaceJSON(bz, &decoded); err != nil {
+			return fmt.Errorf("the codec cannot unmarshal %v: %w", sdk.MsgTypeURL(msg), err)
+		}
+	}
+	return nil
+}
+
+func unpackAs[T any](cdc codec.ProtoCodecMarshaler) func(packed *codectypes.Any) error {
+	return func(packed *codectypes.Any) error {
+		var v T
+		return cdc.UnpackAny(packed, &v)
+	}
+}
@@ -0,0 +1,40 @@
+package relay
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCodec(t *testing.T) {
+	t.Run("registered", func(t *testing.T) {
+		require := require.New(t)
+		registry := codectypes.NewInterfaceRegistry()
+		require.NoError(ensureInterfacesRegistered(registry))
+		require.NoError(checkCodec(codec.NewProtoCodec(registry)))
+		// the relayer registers the interfaces by the module too
+		require.NoError(ensureInterfacesRegistered(registry))
+		require.NoError(checkCodec(codec.NewProtoCodec(registry)))
+	})
+
+	t.Run("missing types", func(t *testing.T) {
+		require := require.New(t)
+		registry := codectypes.NewInterfaceRegistry()
+		clienttypes.RegisterInterfaces(registry)
+		err := checkCodec(codec.NewProtoCodec(registry))
+		require.ErrorContains(err, "/ibc.lightclients.lcp.v1.ClientState")
+
+		// the client messages are missing
+		registry = codectypes.NewInterfaceRegistry()
+		clienttypes.RegisterInterfaces(registry)
+		registry.RegisterImplementations((*ibcexported.ClientState)(nil), &lcptypes.ClientState{})
+		registry.RegisterImplementations((*ibcexported.ConsensusState)(nil), &lcptypes.ConsensusState{})
+		err = checkCodec(codec.NewProtoCodec(registry))
+		require.ErrorContains(err, "/ibc.lightclients.lcp.v1.UpdateClientMessage")
+	})
+}
@@ -0,0 +1,92 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"golang.org/x/sync/errgroup"
+)
+
+// updateELCClient applies `headers` to the ELC client `elcClientID` with the active enclave key.
+// Up to MaxConcurrentUpdates requests are sent to the LCP service in flight, but `onResponse` is called in the order of `headers`.
+// If the request of the i-th header or `onResponse` for it fails, the responses of the later headers are discarded
+// and no more requests are sent.
+func (pr *Prover) updateELCClient(ctx context.Context, elcClientID string, headers []core.Header, includeState bool, onResponse func(i int, res *elc.MsgUpdateClientResponse) error) (err error) {
+	start := time.Now()
+	defer func() {
+		pr.recordELCUpdateDuration(ctx, start, err)
+	}()
+	msgs := make([]*elc.MsgUpdateClient, len(headers))
+	for i, h := range headers {
+		anyHeader, err := clienttypes.PackClientMessage(h)
+		if err != nil {
+			return fmt.Errorf("failed to pack header: i=%v header=%v %w", i, h, err)
+		}
+		msgs[i] = &elc.MsgUpdateClient{
+			ClientId:     elcClientID,
+			Header:       anyHeader,
+			IncludeState: includeState,
+			Signer:       pr.activeEnclaveKey.EnclaveKeyAddress,
+		}
+	}
+
+	var (
+		mu        sync.Mutex
+		responses = make([]*elc.MsgUpdateClientResponse, len(msgs))
+		errs      = make([]error, len(msgs))
+		// the index of the next response passed to `onResponse`
+		next int
+		// the lowest index of the failures
+		failed = len(msgs)
+	)
+	// deliver passes the responses to `onResponse` as long as the preceding ones have been passed
+	deliver := func() {
+		for ; next < failed && responses[next] != nil; next++ {
+			if err := onResponse(next, responses[next]); err != nil {
+				errs[next], failed = err, next
+			}
+		}
+	}
+	var g errgroup.Group
+	g.SetLimit(pr.config.GetMaxConcurrentUpdates())
+	for i, m := range msgs {
+		i, m := i, m
+		mu.Lock()
+		stopped := failed < i
+		mu.Unlock()
+		if stopped {
+			break
+		}
+		g.Go(func() error {
+			// a preceding request may have failed while waiting for the slot
+			mu.Lock()
+			stopped := failed < i
+			mu.Unlock()
+			if stopped {
+				return nil
+			}
+			res, err := pr.lcpServiceClient.UpdateClient(ctx, m)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if i < failed {
+					errs[i], failed = fmt.Errorf("failed to update ELC: i=%v elc_client_id=%v height=%v %w", i, elcClientID, headers[i].GetHeight(), err), i
+				}
+				return nil
+			}
+			responses[i] = res
+			deliver()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if failed < len(msgs) {
+		return errs[failed]
+	}
+	return nil
+}
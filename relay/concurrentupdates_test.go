@@ -0,0 +1,179 @@
+package relay
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// mockLatencyLCPService is an LCP service which takes `latency(height)` to apply a header at `height`
+type mockLatencyLCPService struct {
+	elc.MsgClient
+	key     *ecdsa.PrivateKey
+	latency func(height uint64) time.Duration
+	// the height of the header whose request fails
+	failAt uint64
+
+	mu       sync.Mutex
+	inFlight int
+	// the maximum number of the requests in flight
+	maxInFlight int
+	requested   []uint64
+}
+
+func (s *mockLatencyLCPService) UpdateClient(ctx context.Context, in *elc.MsgUpdateClient, opts ...grpc.CallOption) (*elc.MsgUpdateClientResponse, error) {
+	var header lcptypes.UpdateClientMessage
+	if err := header.Unmarshal(in.Header.Value); err != nil {
+		return nil, err
+	}
+	height := header.GetHeight().GetRevisionHeight()
+	s.mu.Lock()
+	s.inFlight++
+	if s.inFlight > s.maxInFlight {
+		s.maxInFlight = s.inFlight
+	}
+	s.requested = append(s.requested, height)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	time.Sleep(s.latency(height))
+	if height == s.failAt {
+		return nil, fmt.Errorf("failed to apply the header: height=%v", height)
+	}
+	message, err := encodeTestUpdateStateMessage(clienttypes.NewHeight(0, height-1), clienttypes.NewHeight(0, height))
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(crypto.Keccak256(message), s.key)
+	if err != nil {
+		return nil, err
+	}
+	return &elc.MsgUpdateClientResponse{Message: message, Signature: signature}, nil
+}
+
+func newLatencyTestProver(tb testing.TB, maxConcurrentUpdates uint32, latency func(height uint64) time.Duration) (*Prover, *mockLatencyLCPService) {
+	key, err := crypto.GenerateKey()
+	require.NoError(tb, err)
+	service := &mockLatencyLCPService{key: key, latency: latency}
+	pr := &Prover{
+		config:           ProverConfig{MaxConcurrentUpdates: maxConcurrentUpdates},
+		lcpServiceClient: LCPServiceClient{ELCMsgClient: service},
+		activeEnclaveKey: &enclave.EnclaveKeyInfo{EnclaveKeyAddress: crypto.PubkeyToAddress(key.PublicKey).Bytes()},
+	}
+	return pr, service
+}
+
+func newLatencyTestHeaders(tb testing.TB, n int) []core.Header {
+	headers, err := newTestOriginHeaders(clienttypes.NewHeight(0, uint64(n)), n)
+	require.NoError(tb, err)
+	return headers
+}
+
+// postHeights returns the post heights of the responses passed to `onResponse` of updateELCClient
+type postHeights []uint64
+
+func (h *postHeights) onResponse(i int, res *elc.MsgUpdateClientResponse) error {
+	msg, err := decodeUpdateStateProxyMessage(res.Message)
+	if err != nil {
+		return err
+	}
+	if expected := len(*h); i != expected {
+		return fmt.Errorf("unexpected index: expected=%v actual=%v", expected, i)
+	}
+	*h = append(*h, msg.PostHeight.RevisionHeight)
+	return nil
+}
+
+func TestUpdateELCClientConcurrently(t *testing.T) {
+	// the later headers are applied faster, so the responses arrive in the reverse order
+	reversed := func(height uint64) time.Duration {
+		return time.Duration(20-height) * time.Millisecond
+	}
+	var expected []uint64
+	for h := uint64(1); h <= 16; h++ {
+		expected = append(expected, h)
+	}
+
+	t.Run("sequential by default", func(t *testing.T) {
+		require := require.New(t)
+		pr, service := newLatencyTestProver(t, 0, reversed)
+		var heights postHeights
+		require.NoError(pr.updateELCClient(context.TODO(), "07-tendermint-0", newLatencyTestHeaders(t, 16), false, heights.onResponse))
+		require.Equal(expected, []uint64(heights))
+		require.Equal(1, service.maxInFlight)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		require := require.New(t)
+		pr, service := newLatencyTestProver(t, 4, reversed)
+		var heights postHeights
+		require.NoError(pr.updateELCClient(context.TODO(), "07-tendermint-0", newLatencyTestHeaders(t, 16), false, heights.onResponse))
+		// the responses are reassembled in the order of the headers
+		require.Equal(expected, []uint64(heights))
+		require.Greater(service.maxInFlight, 1)
+		require.LessOrEqual(service.maxInFlight, 4)
+	})
+
+	t.Run("failure of a request", func(t *testing.T) {
+		require := require.New(t)
+		pr, service := newLatencyTestProver(t, 4, func(uint64) time.Duration { return 5 * time.Millisecond })
+		service.failAt = 6
+		var heights postHeights
+		err := pr.updateELCClient(context.TODO(), "07-tendermint-0", newLatencyTestHeaders(t, 16), false, heights.onResponse)
+		require.ErrorContains(err, "failed to update ELC: i=5")
+		// the responses after the failure are discarded
+		require.Equal([]uint64{1, 2, 3, 4, 5}, []uint64(heights))
+		// no more requests are sent after the failure
+		require.Less(len(service.requested), 16)
+	})
+
+	t.Run("failure of a response", func(t *testing.T) {
+		require := require.New(t)
+		pr, _ := newLatencyTestProver(t, 4, reversed)
+		errInvalid := errors.New("invalid response")
+		var heights postHeights
+		err := pr.updateELCClient(context.TODO(), "07-tendermint-0", newLatencyTestHeaders(t, 16), false, func(i int, res *elc.MsgUpdateClientResponse) error {
+			if i == 3 {
+				return errInvalid
+			}
+			return heights.onResponse(i, res)
+		})
+		require.ErrorIs(err, errInvalid)
+		require.Equal([]uint64{1, 2, 3}, []uint64(heights))
+	})
+}
+
+func BenchmarkUpdateELCClient(b *testing.B) {
+	const numHeaders = 64
+	latency := func(uint64) time.Duration { return 2 * time.Millisecond }
+	headers := newLatencyTestHeaders(b, numHeaders)
+	for _, n := range []uint32{1, 4, 16} {
+		b.Run(fmt.Sprintf("max_concurrent_updates=%v", n), func(b *testing.B) {
+			pr, _ := newLatencyTestProver(b, n, latency)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var heights postHeights
+				if err := pr.updateELCClient(context.TODO(), "07-tendermint-0", headers, false, heights.onResponse); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
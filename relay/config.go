@@ -3,6 +3,10 @@ package relay
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,11 +15,22 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/hyperledger-labs/yui-relayer/core"
 	"github.com/hyperledger-labs/yui-relayer/signer"
+	oias "github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
 )
 
 const (
-	DefaultDialTimeout                 = 20 // seconds
-	DefaultMessageAggregationBatchSize = 8
+	DefaultDialTimeout                      = 20  // seconds
+	DefaultReconnectTimeout                 = 60  // seconds
+	DefaultClockSkewThreshold               = 60  // seconds
+	DefaultProveStateTimeout                = 60  // seconds
+	DefaultUpdateClientTimeout              = 300 // seconds
+	DefaultMessageAggregationBatchSize      = 8
+	DefaultUnfinalizedKeyWatchdogMultiplier = 10
+)
+
+const (
+	SeverityError = "error"
+	SeverityWarn  = "warn"
 )
 
 var _ core.ProverConfig = (*ProverConfig)(nil)
@@ -29,6 +44,11 @@ func (cfg *ProverConfig) UnpackInterfaces(unpacker codectypes.AnyUnpacker) error
 	if err := unpacker.UnpackAny(cfg.OriginProver, new(core.ProverConfig)); err != nil {
 		return err
 	}
+	for _, fallback := range cfg.FallbackOriginProvers {
+		if err := unpacker.UnpackAny(fallback, new(core.ProverConfig)); err != nil {
+			return err
+		}
+	}
 	if cfg.OperatorSigner != nil {
 		if err := unpacker.UnpackAny(cfg.OperatorSigner, new(signer.SignerConfig)); err != nil {
 			return err
@@ -41,7 +61,7 @@ func (pc ProverConfig) Build(chain core.Chain) (core.Prover, error) {
 	if err := pc.Validate(); err != nil {
 		return nil, err
 	}
-	prover, err := pc.OriginProver.GetCachedValue().(core.ProverConfig).Build(chain)
+	prover, err := pc.buildOriginProvers(chain)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +76,104 @@ func (pc ProverConfig) GetDialTimeout() time.Duration {
 	}
 }
 
+func (pc ProverConfig) GetReconnectTimeout() time.Duration {
+	if pc.LcpServiceReconnectTimeout == 0 {
+		return DefaultReconnectTimeout * time.Second
+	} else {
+		return time.Duration(pc.LcpServiceReconnectTimeout) * time.Second
+	}
+}
+
+func (pc ProverConfig) GetProveStateTimeout() time.Duration {
+	if pc.ProveStateTimeout == 0 {
+		return DefaultProveStateTimeout * time.Second
+	} else {
+		return time.Duration(pc.ProveStateTimeout) * time.Second
+	}
+}
+
+func (pc ProverConfig) GetUpdateClientTimeout() time.Duration {
+	if pc.UpdateClientTimeout == 0 {
+		return DefaultUpdateClientTimeout * time.Second
+	} else {
+		return time.Duration(pc.UpdateClientTimeout) * time.Second
+	}
+}
+
+func (pc ProverConfig) GetIASCRLRefreshInterval() time.Duration {
+	if pc.IasCrlRefreshInterval == 0 {
+		return DefaultIASCRLRefreshInterval * time.Second
+	} else {
+		return time.Duration(pc.IasCrlRefreshInterval) * time.Second
+	}
+}
+
+func (pc ProverConfig) GetAlertDedupInterval() time.Duration {
+	if pc.AlertDedupInterval == 0 {
+		return DefaultAlertDedupInterval * time.Second
+	} else {
+		return time.Duration(pc.AlertDedupInterval) * time.Second
+	}
+}
+
+func (pc ProverConfig) GetSharedRegistrationTimeout() time.Duration {
+	if pc.SharedRegistrationTimeout == 0 {
+		return DefaultSharedRegistrationTimeout * time.Second
+	} else {
+		return time.Duration(pc.SharedRegistrationTimeout) * time.Second
+	}
+}
+
+// GetAlertValidationContextMargin returns the margin before the end of the trusting period to emit an alert
+func (pc ProverConfig) GetAlertValidationContextMargin(trustingPeriod time.Duration) time.Duration {
+	if pc.AlertValidationContextMargin == 0 {
+		return trustingPeriod / 10
+	} else {
+		return time.Duration(pc.AlertValidationContextMargin) * time.Second
+	}
+}
+
+func (pc ProverConfig) GetValidationContextSafetyMargin() time.Duration {
+	return time.Duration(pc.ValidationContextSafetyMargin) * time.Second
+}
+
+func (pc ProverConfig) GetELCClientTypeMismatchSeverity() string {
+	if pc.ElcClientTypeMismatchSeverity == "" {
+		return SeverityError
+	}
+	return pc.ElcClientTypeMismatchSeverity
+}
+
+func (pc ProverConfig) GetClockSkewThreshold() time.Duration {
+	if pc.ClockSkewThreshold == 0 {
+		return DefaultClockSkewThreshold * time.Second
+	}
+	return time.Duration(pc.ClockSkewThreshold) * time.Second
+}
+
+// GetRefreshThresholdRate returns the fraction of the lifetime after which the counterparty LCP client is refreshed.
+// If it is not set, 1/2 is returned.
+func (pc ProverConfig) GetRefreshThresholdRate() Fraction {
+	if pc.RefreshThresholdRate.Numerator == 0 && pc.RefreshThresholdRate.Denominator == 0 {
+		return Fraction{Numerator: 1, Denominator: 2}
+	}
+	return pc.RefreshThresholdRate
+}
+
+func (pc ProverConfig) GetUnfinalizedKeyWatchdogMultiplier() uint64 {
+	if pc.UnfinalizedKeyWatchdogMultiplier == 0 {
+		return DefaultUnfinalizedKeyWatchdogMultiplier
+	}
+	return pc.UnfinalizedKeyWatchdogMultiplier
+}
+
+func (pc ProverConfig) GetTimestampRegressionSeverity() string {
+	if pc.TimestampRegressionSeverity == "" {
+		return SeverityError
+	}
+	return pc.TimestampRegressionSeverity
+}
+
 func (pc ProverConfig) GetMrenclave() []byte {
 	mrenclave, err := decodeMrenclaveHex(pc.Mrenclave)
 	if err != nil {
@@ -72,6 +190,44 @@ func (pc ProverConfig) GetMessageAggregationBatchSize() uint64 {
 	}
 }
 
+// GetMaxConcurrentUpdates returns the maximum number of ELC's UpdateClient requests in flight to the LCP service
+func (pc ProverConfig) GetMaxConcurrentUpdates() int {
+	if pc.MaxConcurrentUpdates == 0 {
+		return 1
+	}
+	return int(pc.MaxConcurrentUpdates)
+}
+
+// GetCounterpartyMessageVersions returns the proxy message versions that the counterparty LCP client is configured to accept
+func (pc ProverConfig) GetCounterpartyMessageVersions() []uint16 {
+	if len(pc.CounterpartyMessageVersions) == 0 {
+		return []uint16{lcptypes.LCPMessageVersion}
+	}
+	versions := make([]uint16, len(pc.CounterpartyMessageVersions))
+	for i, v := range pc.CounterpartyMessageVersions {
+		versions[i] = uint16(v)
+	}
+	return versions
+}
+
+// FindQuotePolicyOverride returns the override of the quote policy for the LCP client `clientID` on the counterparty chain `chainID`.
+// An override specifying the client ID takes precedence over the one for the whole chain.
+// If no override matches, it returns nil and the global values should be used.
+func (pc ProverConfig) FindQuotePolicyOverride(chainID, clientID string) *QuotePolicyOverride {
+	var found *QuotePolicyOverride
+	for i, o := range pc.QuotePolicyOverrides {
+		if o.CounterpartyChainId != chainID {
+			continue
+		}
+		if o.CounterpartyClientId == clientID {
+			return &pc.QuotePolicyOverrides[i]
+		} else if o.CounterpartyClientId == "" {
+			found = &pc.QuotePolicyOverrides[i]
+		}
+	}
+	return found
+}
+
 func (pc ProverConfig) ChainType() lcptypes.ChainType {
 	switch pc.OperatorsEip712Params.(type) {
 	case *ProverConfig_OperatorsEip712EvmChainParams:
@@ -88,8 +244,17 @@ func (pc ProverConfig) Validate() error {
 	if err := pc.OriginProver.GetCachedValue().(core.ProverConfig).Validate(); err != nil {
 		return fmt.Errorf("failed to validate the origin prover's config: %v", err)
 	}
+	if err := pc.validateFallbackOriginProvers(); err != nil {
+		return err
+	}
 
 	// lcp prover config validation
+	if err := pc.validateAddresses(); err != nil {
+		return err
+	}
+	if err := pc.validateLcpServiceTLS(); err != nil {
+		return err
+	}
 	mrenclave, err := decodeMrenclaveHex(pc.Mrenclave)
 	if err != nil {
 		return err
@@ -100,32 +265,122 @@ func (pc ProverConfig) Validate() error {
 	if pc.KeyExpiration == 0 {
 		return fmt.Errorf("KeyExpiration must be greater than 0")
 	}
+	if err := pc.validateElcClientID(); err != nil {
+		return err
+	}
+	if err := pc.validateRefreshThresholdRate(); err != nil {
+		return err
+	}
+	if pc.IsDebugEnclave && !pc.AllowDebugEnclaveKeys {
+		return fmt.Errorf("AllowDebugEnclaveKeys must be true if IsDebugEnclave is true")
+	}
+	if pc.MinimumIsvSvn > math.MaxUint16 {
+		return fmt.Errorf("MinimumIsvSvn must be less than or equal to %v, but got %v", math.MaxUint16, pc.MinimumIsvSvn)
+	}
+	if err := pc.validateQuotePolicies(); err != nil {
+		return err
+	}
+	if err := pc.validateTxOptions(); err != nil {
+		return err
+	}
+	if err := pc.validateRetentionPolicies(); err != nil {
+		return err
+	}
+	if pc.TxRetryPolicy != nil {
+		if err := pc.TxRetryPolicy.Validate(); err != nil {
+			return fmt.Errorf("TxRetryPolicy: %v", err)
+		}
+	}
+	if err := pc.validateFinalityTracker(); err != nil {
+		return err
+	}
+	if err := pc.validateMetricsSink(); err != nil {
+		return err
+	}
+	if s := pc.ElcClientTypeMismatchSeverity; s != "" && s != SeverityError && s != SeverityWarn {
+		return fmt.Errorf("ElcClientTypeMismatchSeverity must be either %q or %q, but got %q", SeverityError, SeverityWarn, s)
+	}
+	if s := pc.TimestampRegressionSeverity; s != "" && s != SeverityError && s != SeverityWarn {
+		return fmt.Errorf("TimestampRegressionSeverity must be either %q or %q, but got %q", SeverityError, SeverityWarn, s)
+	}
+	for _, v := range pc.CounterpartyMessageVersions {
+		if v == 0 || v > math.MaxUint16 {
+			return fmt.Errorf("CounterpartyMessageVersions must be in the range [1, %v], but got %v", math.MaxUint16, v)
+		}
+	}
+	for name, rl := range map[string]*RateLimit{
+		"UpdateClientRateLimit":     pc.UpdateClientRateLimit,
+		"VerifyMembershipRateLimit": pc.VerifyMembershipRateLimit,
+		"QueryRateLimit":            pc.QueryRateLimit,
+	} {
+		if rl == nil {
+			continue
+		} else if err := rl.Validate(); err != nil {
+			return fmt.Errorf("%v: %v", name, err)
+		}
+	}
+	if pc.IasCrlUrl != "" {
+		if u, err := url.Parse(pc.IasCrlUrl); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("IasCrlUrl must be a valid http(s) URL: %v", pc.IasCrlUrl)
+		}
+	}
+	if pc.ProofArchiveDir == "" && (pc.ProofArchiveMaxEntries != 0 || pc.ProofArchiveRetention != 0 || pc.ProofArchiveFailClosed) {
+		return fmt.Errorf("ProofArchiveDir must be set if the other proof archive options are set")
+	}
+	if pc.SharedRegistrationPath == "" && (pc.SharedRegistrationTimeout != 0 || pc.InstanceId != "") {
+		return fmt.Errorf("SharedRegistrationPath must be set if the other shared registration options are set")
+	}
+	if pc.DiagnosticsAddress != "" {
+		if _, port, err := net.SplitHostPort(pc.DiagnosticsAddress); err != nil {
+			return fmt.Errorf("DiagnosticsAddress must be in the form of host:port: value=%q %v", pc.DiagnosticsAddress, err)
+		} else if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("DiagnosticsAddress has an invalid port: value=%q", pc.DiagnosticsAddress)
+		}
+	}
+	if pc.AlertWebhookUrl != "" {
+		if u, err := url.Parse(pc.AlertWebhookUrl); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("AlertWebhookUrl must be a valid http(s) URL: %v", pc.AlertWebhookUrl)
+		}
+	}
+	if _, err := parseAlertPayloadTemplate(pc.AlertPayloadTemplate); err != nil {
+		return fmt.Errorf("AlertPayloadTemplate is invalid: %v", err)
+	}
+	if pc.HaltOnAttestationPolicyDrift && pc.AttestationPolicyCheckInterval == 0 {
+		return fmt.Errorf("AttestationPolicyCheckInterval must be set if HaltOnAttestationPolicyDrift is true")
+	}
+	if pc.ClockSkewCorrection && pc.ClockSkewCheckInterval == 0 {
+		return fmt.Errorf("ClockSkewCheckInterval must be set if ClockSkewCorrection is true")
+	}
 	if pc.MessageAggregation && pc.MessageAggregationBatchSize == 1 {
 		return fmt.Errorf("MessageAggregationBatchSize must be greater than 1 if MessageAggregation is true and MessageAggregationBatchSize is set")
 	}
+	if pc.OperatorEip1271 != nil && len(pc.Operators) == 0 {
+		return fmt.Errorf("OperatorEip1271 must not be set if Operators is empty(=permissionless)")
+	}
 	if l := len(pc.Operators); l > 1 {
 		return fmt.Errorf("Operators: currently only one or zero(=permissionless) operator is supported, but got %v", l)
+	} else if err := pc.validateOperatorsThreshold(); err != nil {
+		return err
 	} else if l == 0 {
 		return nil
 	}
 
 	// ----- operators config validation -----
 
-	if pc.OperatorSigner == nil {
+	if pc.OperatorEip1271 != nil {
+		if err := pc.validateOperatorEIP1271(); err != nil {
+			return err
+		}
+	} else if pc.OperatorSigner == nil {
 		return fmt.Errorf("OperatorSigner must be set if Operators or OperatorsEip712Params is set")
-	}
-	{
+	} else {
 		signerConfig, ok := pc.OperatorSigner.GetCachedValue().(signer.SignerConfig)
 		if !ok {
 			return fmt.Errorf("failed to cast OperatorSigner's config: %T", pc.OperatorSigner.GetCachedValue())
 		} else if err := signerConfig.Validate(); err != nil {
 			return fmt.Errorf("failed to validate the OperatorSigner's config: %v", err)
 		}
-		signer, err := signerConfig.Build()
-		if err != nil {
-			return fmt.Errorf("failed to build the OperatorSigner: %v", err)
-		}
-		addr, err := NewEIP712Signer(signer).GetSignerAddress()
+		addr, err := getSignerConfigAddress(signerConfig)
 		if err != nil {
 			return fmt.Errorf("failed to get the OperatorSigner's address: %v", err)
 		}
@@ -143,9 +398,6 @@ func (pc ProverConfig) Validate() error {
 			if params.OperatorsEip712EvmChainParams.ChainId == 0 {
 				return fmt.Errorf("OperatorsEip712EvmChainParams.ChainId must be set")
 			}
-			if !common.IsHexAddress(params.OperatorsEip712EvmChainParams.VerifyingContractAddress) {
-				return fmt.Errorf("OperatorsEip712EvmChainParams.VerifyingContractAddress must be a valid hex address")
-			}
 		case *ProverConfig_OperatorsEip712CosmosChainParams:
 			if params.OperatorsEip712CosmosChainParams.ChainId == "" {
 				return fmt.Errorf("OperatorsEip712CosmosChainParams.ChainId must be set")
@@ -160,6 +412,54 @@ func (pc ProverConfig) Validate() error {
 	return nil
 }
 
+// validateOperatorEIP1271 validates the config of the operator signatures approved by a contract wallet.
+// Only the LCP contract on EVM host chains can verify them with EIP-1271.
+func (pc ProverConfig) validateOperatorEIP1271() error {
+	if pc.OperatorSigner != nil {
+		return fmt.Errorf("OperatorSigner and OperatorEip1271 must not be set at the same time")
+	}
+	if _, ok := pc.OperatorsEip712Params.(*ProverConfig_OperatorsEip712EvmChainParams); !ok {
+		return fmt.Errorf("OperatorEip1271 is only supported on EVM host chains: OperatorsEip712EvmChainParams must be set")
+	}
+	if err := pc.OperatorEip1271.Validate(); err != nil {
+		return fmt.Errorf("failed to validate OperatorEip1271: %v", err)
+	}
+	op, err := decodeOperatorAddress(pc.Operators[0])
+	if err != nil {
+		return fmt.Errorf("failed to decode operator address: %v", err)
+	}
+	if wallet := common.HexToAddress(pc.OperatorEip1271.WalletAddress); wallet != op {
+		return fmt.Errorf("OperatorEip1271's wallet address must be equal to the first operator's address: %v != %v", wallet, op)
+	}
+	return nil
+}
+
+// validateOperatorsThreshold validates the threshold with the same rules as the LCP client:
+// it must not be set if the operators are empty(=permissionless), and otherwise it must be unset(=1/1) or satisfy 0 < numerator <= denominator
+func (pc ProverConfig) validateOperatorsThreshold() error {
+	t := pc.OperatorsThreshold
+	if t.Numerator == 0 && t.Denominator == 0 {
+		return nil
+	} else if len(pc.Operators) == 0 {
+		return fmt.Errorf("OperatorsThreshold must not be set if Operators is empty(=permissionless), but got %v/%v", t.Numerator, t.Denominator)
+	} else if t.Numerator == 0 || t.Denominator == 0 || t.Numerator > t.Denominator {
+		return fmt.Errorf("OperatorsThreshold must satisfy 0 < numerator <= denominator, but got %v/%v", t.Numerator, t.Denominator)
+	}
+	return nil
+}
+
+// validateRefreshThresholdRate validates that the rate is unset(=1/2) or satisfies 0 < numerator < denominator.
+// A rate of 1 or more would refresh the client only after the consensus state or the key expires.
+func (pc ProverConfig) validateRefreshThresholdRate() error {
+	r := pc.RefreshThresholdRate
+	if r.Numerator == 0 && r.Denominator == 0 {
+		return nil
+	} else if r.Numerator == 0 || r.Numerator >= r.Denominator {
+		return fmt.Errorf("RefreshThresholdRate must satisfy 0 < numerator < denominator, but got %v/%v", r.Numerator, r.Denominator)
+	}
+	return nil
+}
+
 func decodeMrenclaveHex(s string) ([]byte, error) {
 	trimmed := strings.ToLower(strings.TrimPrefix(s, "0x"))
 	bz, err := hex.DecodeString(trimmed)
@@ -168,3 +468,126 @@ func decodeMrenclaveHex(s string) ([]byte, error) {
 	}
 	return bz, nil
 }
+
+// validateAddresses validates the fields holding addresses
+func (pc ProverConfig) validateAddresses() error {
+	if err := validateServiceAddress(pc.LcpServiceAddress); err != nil {
+		return fmt.Errorf("LcpServiceAddress is invalid: value=%q %w", pc.LcpServiceAddress, err)
+	}
+	for i, op := range pc.Operators {
+		addr, err := parseHexAddress(op)
+		if err != nil {
+			return fmt.Errorf("Operators[%v] is invalid: value=%q %w", i, op, err)
+		} else if addr == (common.Address{}) {
+			return fmt.Errorf("Operators[%v] must not be the zero address: value=%q", i, op)
+		}
+	}
+	if params, ok := pc.OperatorsEip712Params.(*ProverConfig_OperatorsEip712EvmChainParams); ok {
+		v := params.OperatorsEip712EvmChainParams.VerifyingContractAddress
+		addr, err := parseHexAddress(v)
+		if err != nil {
+			return fmt.Errorf("OperatorsEip712EvmChainParams.VerifyingContractAddress is invalid: value=%q %w", v, err)
+		} else if addr == (common.Address{}) {
+			return fmt.Errorf("OperatorsEip712EvmChainParams.VerifyingContractAddress must not be the zero address: value=%q", v)
+		}
+	}
+	return nil
+}
+
+// validateQuotePolicies validates the global quote policy and its overrides
+func (pc ProverConfig) validateQuotePolicies() error {
+	if err := validateQuotePolicy(pc.AllowedQuoteStatuses, pc.AllowedAdvisoryIds); err != nil {
+		return err
+	}
+	type target struct{ chainID, clientID string }
+	seen := make(map[target]struct{})
+	for i, o := range pc.QuotePolicyOverrides {
+		if o.CounterpartyChainId == "" {
+			return fmt.Errorf("QuotePolicyOverrides[%v]: CounterpartyChainId must be set", i)
+		}
+		t := target{o.CounterpartyChainId, o.CounterpartyClientId}
+		if _, ok := seen[t]; ok {
+			return fmt.Errorf("QuotePolicyOverrides[%v]: duplicate override: counterparty_chain_id=%q counterparty_client_id=%q", i, t.chainID, t.clientID)
+		}
+		seen[t] = struct{}{}
+		if err := validateQuotePolicy(o.AllowedQuoteStatuses, o.AllowedAdvisoryIds); err != nil {
+			return fmt.Errorf("QuotePolicyOverrides[%v]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateQuotePolicy validates the allowed quote statuses and advisory IDs
+// The values are normalized in the same way as the LCP client, which compares them with the AVR after the normalization.
+func validateQuotePolicy(statuses, advisoryIDs []string) error {
+	for i, status := range statuses {
+		var s oias.ISVEnclaveQuoteStatus
+		if err := s.UnmarshalText([]byte(lcptypes.NormalizeQuoteStatus(status))); err != nil {
+			return fmt.Errorf("AllowedQuoteStatuses[%v] is invalid: value=%q %w", i, status, err)
+		}
+	}
+	for i, id := range advisoryIDs {
+		if lcptypes.NormalizeAdvisoryID(id) == "" {
+			return fmt.Errorf("AllowedAdvisoryIds[%v] must be non-empty: value=%q", i, id)
+		}
+	}
+	return lcptypes.ValidateQuotePolicy(statuses, advisoryIDs)
+}
+
+// parseHexAddress parses a hex address strictly unlike common.HexToAddress.
+// If the address contains both upper and lower case letters, it must be a valid EIP-55 checksum address.
+func parseHexAddress(s string) (common.Address, error) {
+	trimmed := strings.TrimPrefix(s, "0x")
+	if l := len(trimmed); l != 2*common.AddressLength {
+		return common.Address{}, fmt.Errorf("invalid address length: expected=%v actual=%v", 2*common.AddressLength, l)
+	}
+	if _, err := hex.DecodeString(trimmed); err != nil {
+		return common.Address{}, fmt.Errorf("invalid hex string: %w", err)
+	}
+	addr := common.HexToAddress(trimmed)
+	if strings.ToLower(trimmed) != trimmed && strings.ToUpper(trimmed) != trimmed && addr.Hex() != "0x"+trimmed {
+		return common.Address{}, fmt.Errorf("invalid checksum: expected=%v", addr.Hex())
+	}
+	return addr, nil
+}
+
+// validateServiceAddress validates the gRPC target of the LCP service.
+// The supported formats are "host:port", "dns:///host:port" and "unix:///path".
+func validateServiceAddress(s string) error {
+	if s == "" {
+		return fmt.Errorf("must not be empty")
+	} else if strings.TrimSpace(s) != s {
+		return fmt.Errorf("must not contain leading or trailing spaces")
+	}
+	if !strings.Contains(s, "://") {
+		return validateHostPort(s)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "dns":
+		return validateHostPort(strings.TrimPrefix(u.Path, "/"))
+	case "unix":
+		if u.Path == "" {
+			return fmt.Errorf("unix socket path must not be empty")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported scheme: %v", u.Scheme)
+	}
+}
+
+func validateHostPort(s string) error {
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return err
+	} else if host == "" {
+		return fmt.Errorf("host must not be empty")
+	}
+	if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+		return fmt.Errorf("invalid port: %v", port)
+	}
+	return nil
+}
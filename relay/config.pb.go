@@ -4,6 +4,7 @@
 package relay
 
 import (
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	types "github.com/cosmos/cosmos-sdk/codec/types"
 	_ "github.com/cosmos/gogoproto/gogoproto"
@@ -26,20 +27,115 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type ProverConfig struct {
 	OriginProver *types.Any `protobuf:"bytes,1,opt,name=origin_prover,json=originProver,proto3" json:"origin_prover,omitempty"`
+	// the origin provers of the same origin chain with different endpoints, which are used if `origin_prover` fails
+	// they are tried in this order, but the prover that succeeded most recently is tried first
+	FallbackOriginProvers []*types.Any `protobuf:"bytes,49,rep,name=fallback_origin_provers,json=fallbackOriginProvers,proto3" json:"fallback_origin_provers,omitempty"`
 	// hex string
 	LcpServiceAddress string `protobuf:"bytes,2,opt,name=lcp_service_address,json=lcpServiceAddress,proto3" json:"lcp_service_address,omitempty"`
 	// unit: seconds
+	// the deadline of each attempt to connect to the LCP service
 	LcpServiceDialTimeout uint64 `protobuf:"varint,3,opt,name=lcp_service_dial_timeout,json=lcpServiceDialTimeout,proto3" json:"lcp_service_dial_timeout,omitempty"`
+	// unit: seconds
+	// the deadline of re-dialing the LCP service after the connection is lost, e.g. the service is restarted
+	// if zero, the default value is used
+	LcpServiceReconnectTimeout uint64 `protobuf:"varint,63,opt,name=lcp_service_reconnect_timeout,json=lcpServiceReconnectTimeout,proto3" json:"lcp_service_reconnect_timeout,omitempty"`
+	// if true, the connection to the LCP service is secured with TLS
+	LcpServiceTlsEnabled bool `protobuf:"varint,67,opt,name=lcp_service_tls_enabled,json=lcpServiceTlsEnabled,proto3" json:"lcp_service_tls_enabled,omitempty"`
+	// the PEM file of the CA certificates that the certificate of the LCP service must chain to
+	// if empty, the root CAs of the host are used
+	// unlike the other paths, a relative path of the TLS files is resolved from the working directory
+	// because the connection is created before the relayer's home directory is known
+	LcpServiceTlsCaCert string `protobuf:"bytes,68,opt,name=lcp_service_tls_ca_cert,json=lcpServiceTlsCaCert,proto3" json:"lcp_service_tls_ca_cert,omitempty"`
+	// the PEM files of the client certificate and its private key for mTLS
+	// they must be set together, and if empty, no client certificate is presented
+	LcpServiceClientCert string `protobuf:"bytes,69,opt,name=lcp_service_client_cert,json=lcpServiceClientCert,proto3" json:"lcp_service_client_cert,omitempty"`
+	LcpServiceClientKey  string `protobuf:"bytes,70,opt,name=lcp_service_client_key,json=lcpServiceClientKey,proto3" json:"lcp_service_client_key,omitempty"`
+	// if not empty, the certificate of the LCP service is verified against this name instead of the host of lcp_service_address
+	LcpServiceTlsServerNameOverride string `protobuf:"bytes,71,opt,name=lcp_service_tls_server_name_override,json=lcpServiceTlsServerNameOverride,proto3" json:"lcp_service_tls_server_name_override,omitempty"`
+	// unit: seconds
+	// the deadline of ProveState including the calls to the LCP service
+	// if zero, the default value is used
+	ProveStateTimeout uint64 `protobuf:"varint,34,opt,name=prove_state_timeout,json=proveStateTimeout,proto3" json:"prove_state_timeout,omitempty"`
+	// unit: seconds
+	// the deadline of SetupHeadersForUpdate including the calls to the LCP service
+	// if zero, the default value is used
+	UpdateClientTimeout uint64 `protobuf:"varint,35,opt,name=update_client_timeout,json=updateClientTimeout,proto3" json:"update_client_timeout,omitempty"`
+	// client-side rate limits of the calls to the LCP service per RPC class
+	// "update_client": UpdateClient and AggregateMessages
+	// "verify_membership": VerifyMembership and VerifyNonMembership
+	// "query": the queries of the ELC clients and the enclave keys
+	// if not set, the calls of the class are not limited
+	// the interactive CLI commands except `batch` bypass the limits
+	UpdateClientRateLimit     *RateLimit `protobuf:"bytes,36,opt,name=update_client_rate_limit,json=updateClientRateLimit,proto3" json:"update_client_rate_limit,omitempty"`
+	VerifyMembershipRateLimit *RateLimit `protobuf:"bytes,37,opt,name=verify_membership_rate_limit,json=verifyMembershipRateLimit,proto3" json:"verify_membership_rate_limit,omitempty"`
+	QueryRateLimit            *RateLimit `protobuf:"bytes,38,opt,name=query_rate_limit,json=queryRateLimit,proto3" json:"query_rate_limit,omitempty"`
 	// hex string
 	Mrenclave            string   `protobuf:"bytes,4,opt,name=mrenclave,proto3" json:"mrenclave,omitempty"`
 	AllowedQuoteStatuses []string `protobuf:"bytes,5,rep,name=allowed_quote_statuses,json=allowedQuoteStatuses,proto3" json:"allowed_quote_statuses,omitempty"`
 	AllowedAdvisoryIds   []string `protobuf:"bytes,6,rep,name=allowed_advisory_ids,json=allowedAdvisoryIds,proto3" json:"allowed_advisory_ids,omitempty"`
+	// overrides of the allowed quote statuses and advisory IDs for specific counterparties
+	// if no override matches the counterparty, the above values are used
+	QuotePolicyOverrides []QuotePolicyOverride `protobuf:"bytes,33,rep,name=quote_policy_overrides,json=quotePolicyOverrides,proto3" json:"quote_policy_overrides"`
+	// unit: seconds
+	KeyExpiration uint64 `protobuf:"varint,7,opt,name=key_expiration,json=keyExpiration,proto3" json:"key_expiration,omitempty"`
+	// if true, the key expiration of the counterparty LCP client is used to rotate the enclave keys instead of `key_expiration`
+	// once the client state is queried. `key_expiration` is still used for the LCP clients created by the prover
+	// a mismatch between them is alerted regardless of this setting
+	PreferOnChainKeyExpiration bool `protobuf:"varint,58,opt,name=prefer_on_chain_key_expiration,json=preferOnChainKeyExpiration,proto3" json:"prefer_on_chain_key_expiration,omitempty"`
 	// unit: seconds
-	KeyExpiration               uint64 `protobuf:"varint,7,opt,name=key_expiration,json=keyExpiration,proto3" json:"key_expiration,omitempty"`
-	ElcClientId                 string `protobuf:"bytes,8,opt,name=elc_client_id,json=elcClientId,proto3" json:"elc_client_id,omitempty"`
-	MessageAggregation          bool   `protobuf:"varint,9,opt,name=message_aggregation,json=messageAggregation,proto3" json:"message_aggregation,omitempty"`
-	MessageAggregationBatchSize uint64 `protobuf:"varint,10,opt,name=message_aggregation_batch_size,json=messageAggregationBatchSize,proto3" json:"message_aggregation_batch_size,omitempty"`
-	IsDebugEnclave              bool   `protobuf:"varint,11,opt,name=is_debug_enclave,json=isDebugEnclave,proto3" json:"is_debug_enclave,omitempty"`
+	// the maximum gap between the block times of consecutive updates recorded in the LCP client created by the prover
+	// the counterparty can detect the staleness of the client with it. if zero, the gap is not bounded
+	MaxUpdateGap uint64 `protobuf:"varint,48,opt,name=max_update_gap,json=maxUpdateGap,proto3" json:"max_update_gap,omitempty"`
+	// the fraction of the lifetime after which the LCP client on the counterparty chain is refreshed even if the origin prover does not require it
+	// the lifetime of the latest consensus state is the max update gap of the client, or its key expiration if the gap is not bounded,
+	// and the one of the active enclave key is the key expiration of the client since the attestation time
+	// if zero, 1/2 is used. the value must be less than 1
+	RefreshThresholdRate        Fraction `protobuf:"bytes,80,opt,name=refresh_threshold_rate,json=refreshThresholdRate,proto3" json:"refresh_threshold_rate"`
+	ElcClientId                 string   `protobuf:"bytes,8,opt,name=elc_client_id,json=elcClientId,proto3" json:"elc_client_id,omitempty"`
+	MessageAggregation          bool     `protobuf:"varint,9,opt,name=message_aggregation,json=messageAggregation,proto3" json:"message_aggregation,omitempty"`
+	MessageAggregationBatchSize uint64   `protobuf:"varint,10,opt,name=message_aggregation_batch_size,json=messageAggregationBatchSize,proto3" json:"message_aggregation_batch_size,omitempty"`
+	// the maximum number of ELC's UpdateClient requests in flight to the LCP service while applying the headers of a long catch-up
+	// the resulting messages are kept in the order of the headers
+	// this must be set only if the ELC can apply each header independently of the preceding ones in the same batch,
+	// e.g. every header is verified against a trusted height already stored in the ELC client
+	// if zero, the requests are sent sequentially
+	MaxConcurrentUpdates uint32 `protobuf:"varint,57,opt,name=max_concurrent_updates,json=maxConcurrentUpdates,proto3" json:"max_concurrent_updates,omitempty"`
+	IsDebugEnclave       bool   `protobuf:"varint,11,opt,name=is_debug_enclave,json=isDebugEnclave,proto3" json:"is_debug_enclave,omitempty"`
+	// if true, the enclave keys of debug-mode enclaves are allowed to be selected and registered
+	// this must be set explicitly if is_debug_enclave is true
+	AllowDebugEnclaveKeys bool `protobuf:"varint,19,opt,name=allow_debug_enclave_keys,json=allowDebugEnclaveKeys,proto3" json:"allow_debug_enclave_keys,omitempty"`
+	// the minimum ISV SVN of the enclave that the enclave keys are attested by
+	// it is set to `minimum_isv_svn` of the LCP client created by the prover
+	// if zero, the ISV SVN is not constrained
+	MinimumIsvSvn uint32 `protobuf:"varint,30,opt,name=minimum_isv_svn,json=minimumIsvSvn,proto3" json:"minimum_isv_svn,omitempty"`
+	// the PEM files of the root certificates of Intel PCS that the DCAP attestations must chain to
+	// the LCP clients created by the prover accept the DCAP attestations chaining to them
+	// if empty, the enclave keys with DCAP attestations are not selected
+	// a relative path is resolved from the relayer's home directory
+	DcapRootCertPaths []string `protobuf:"bytes,53,rep,name=dcap_root_cert_paths,json=dcapRootCertPaths,proto3" json:"dcap_root_cert_paths,omitempty"`
+	// severity when the ELC's origin client type differs from the recorded one
+	// "error" (default) or "warn"
+	ElcClientTypeMismatchSeverity string `protobuf:"bytes,18,opt,name=elc_client_type_mismatch_severity,json=elcClientTypeMismatchSeverity,proto3" json:"elc_client_type_mismatch_severity,omitempty"`
+	// severity when the timestamp of an update goes backwards from the previous update in the batch
+	// or the latest consensus state of the counterparty LCP client
+	// "error" (default): the updates are not submitted, or "warn"
+	TimestampRegressionSeverity string `protobuf:"bytes,42,opt,name=timestamp_regression_severity,json=timestampRegressionSeverity,proto3" json:"timestamp_regression_severity,omitempty"`
+	// if true, the registration of a new enclave key and the first update signed by the key are submitted in a single tx
+	// it falls back to separate txs if the counterparty chain cannot process them in order
+	BundleRegisterEnclaveKey bool `protobuf:"varint,20,opt,name=bundle_register_enclave_key,json=bundleRegisterEnclaveKey,proto3" json:"bundle_register_enclave_key,omitempty"`
+	// proxy message versions that the counterparty LCP client accepts
+	// they are set to `allowed_message_versions` of the LCP client created by the prover,
+	// and assumed until the client state of the counterparty is queried, which takes precedence afterwards
+	// if empty, only the current version is assumed to be accepted
+	CounterpartyMessageVersions []uint32 `protobuf:"varint,21,rep,packed,name=counterparty_message_versions,json=counterpartyMessageVersions,proto3" json:"counterparty_message_versions,omitempty"`
+	// if true, the validation contexts of the updates are evaluated before the submission against the estimated next block time of the counterparty chain
+	// the estimation is the timestamp of the latest block plus the average block time
+	// the updates predicted to be rejected are not submitted, and they are generated again from a fresher origin header
+	ValidationContextPreflight bool `protobuf:"varint,55,opt,name=validation_context_preflight,json=validationContextPreflight,proto3" json:"validation_context_preflight,omitempty"`
+	// unit: seconds
+	// the updates must pass the validation at any time within this margin after the estimated next block time
+	// it covers the delay of the inclusion and the error of the estimation
+	ValidationContextSafetyMargin uint64 `protobuf:"varint,56,opt,name=validation_context_safety_margin,json=validationContextSafetyMargin,proto3" json:"validation_context_safety_margin,omitempty"`
 	// --- Operator Config --- //
 	// if empty, any operator is allowed (default)
 	// otherwise, only operators in this list are allowed
@@ -49,6 +145,146 @@ type ProverConfig struct {
 	OperatorsThreshold Fraction `protobuf:"bytes,13,opt,name=operators_threshold,json=operatorsThreshold,proto3" json:"operators_threshold"`
 	// signer for eip712 commitment
 	OperatorSigner *types.Any `protobuf:"bytes,14,opt,name=operator_signer,json=operatorSigner,proto3" json:"operator_signer,omitempty"`
+	// if set, the operator signatures are approved by the contract wallet on the EVM host chain with EIP-1271
+	// instead of being signed with operator_signer
+	OperatorEip1271 *EIP1271OperatorConfig `protobuf:"bytes,43,opt,name=operator_eip1271,json=operatorEip1271,proto3" json:"operator_eip1271,omitempty"`
+	// --- Revocation Config --- //
+	// if not empty, the report signing certificate is checked against the CRL fetched from this URL
+	IasCrlUrl string `protobuf:"bytes,15,opt,name=ias_crl_url,json=iasCrlUrl,proto3" json:"ias_crl_url,omitempty"`
+	// unit: seconds
+	IasCrlRefreshInterval uint64 `protobuf:"varint,16,opt,name=ias_crl_refresh_interval,json=iasCrlRefreshInterval,proto3" json:"ias_crl_refresh_interval,omitempty"`
+	// if true, the revocation check is skipped when the CRL cannot be fetched
+	IasCrlFailOpen bool `protobuf:"varint,17,opt,name=ias_crl_fail_open,json=iasCrlFailOpen,proto3" json:"ias_crl_fail_open,omitempty"`
+	// --- Proof Archive Config --- //
+	// if not empty, the proofs generated by ProveState are archived in this directory
+	// a relative path is resolved from the relayer's home directory
+	ProofArchiveDir string `protobuf:"bytes,22,opt,name=proof_archive_dir,json=proofArchiveDir,proto3" json:"proof_archive_dir,omitempty"`
+	// the maximum number of archived proofs
+	// if zero, the number is unlimited
+	ProofArchiveMaxEntries uint64 `protobuf:"varint,23,opt,name=proof_archive_max_entries,json=proofArchiveMaxEntries,proto3" json:"proof_archive_max_entries,omitempty"`
+	// unit: seconds
+	// if zero, the archived proofs are never removed by age
+	ProofArchiveRetention uint64 `protobuf:"varint,24,opt,name=proof_archive_retention,json=proofArchiveRetention,proto3" json:"proof_archive_retention,omitempty"`
+	// if true, ProveState fails if the proof cannot be archived
+	ProofArchiveFailClosed bool `protobuf:"varint,25,opt,name=proof_archive_fail_closed,json=proofArchiveFailClosed,proto3" json:"proof_archive_fail_closed,omitempty"`
+	// --- Alert Config --- //
+	// if not empty, alerts on critical conditions are posted to this URL
+	// the alerts are always emitted to the log regardless of this setting
+	AlertWebhookUrl string `protobuf:"bytes,26,opt,name=alert_webhook_url,json=alertWebhookUrl,proto3" json:"alert_webhook_url,omitempty"`
+	// Go text/template that renders the JSON payload of the webhook
+	// if empty, the default payload is used
+	AlertPayloadTemplate string `protobuf:"bytes,27,opt,name=alert_payload_template,json=alertPayloadTemplate,proto3" json:"alert_payload_template,omitempty"`
+	// unit: seconds
+	// the same alert is not emitted again within this interval
+	AlertDedupInterval uint64 `protobuf:"varint,28,opt,name=alert_dedup_interval,json=alertDedupInterval,proto3" json:"alert_dedup_interval,omitempty"`
+	// unit: seconds
+	// an alert is emitted if the trusting period of the validation context ends within this margin
+	// if zero, a tenth of the trusting period is used
+	AlertValidationContextMargin uint64 `protobuf:"varint,29,opt,name=alert_validation_context_margin,json=alertValidationContextMargin,proto3" json:"alert_validation_context_margin,omitempty"`
+	// --- Attestation Policy Watch Config --- //
+	// unit: seconds
+	// if not zero, the fresh attestations of the enclave keys in the LCP service are compared with the one of the active enclave key at this interval
+	// an alert is emitted if the quote status or the advisory IDs drift from the ones of the active enclave key
+	AttestationPolicyCheckInterval uint64 `protobuf:"varint,51,opt,name=attestation_policy_check_interval,json=attestationPolicyCheckInterval,proto3" json:"attestation_policy_check_interval,omitempty"`
+	// if true, ProveState fails after a drift is detected until the drift is acknowledged with the `ack-policy-drift` command
+	HaltOnAttestationPolicyDrift bool `protobuf:"varint,52,opt,name=halt_on_attestation_policy_drift,json=haltOnAttestationPolicyDrift,proto3" json:"halt_on_attestation_policy_drift,omitempty"`
+	// --- Clock Skew Config --- //
+	// unit: seconds
+	// if not zero, the clock of the relayer host is compared with the timestamp of the latest block of the counterparty chain at this interval
+	// the measured skews are logged and exported as metrics
+	ClockSkewCheckInterval uint64 `protobuf:"varint,64,opt,name=clock_skew_check_interval,json=clockSkewCheckInterval,proto3" json:"clock_skew_check_interval,omitempty"`
+	// unit: seconds
+	// a warning and an alert are emitted if the absolute value of a measured skew exceeds this threshold
+	// if zero, the default value is used
+	ClockSkewThreshold uint64 `protobuf:"varint,65,opt,name=clock_skew_threshold,json=clockSkewThreshold,proto3" json:"clock_skew_threshold,omitempty"`
+	// if true, the time used to decide the rotation of the enclave key is corrected by the skew measured against the counterparty chain,
+	// on which the LCP client checks the expiration of the key
+	// it requires clock_skew_check_interval to be set
+	ClockSkewCorrection bool `protobuf:"varint,66,opt,name=clock_skew_correction,json=clockSkewCorrection,proto3" json:"clock_skew_correction,omitempty"`
+	// --- GC Config --- //
+	// the retention policies of the artifacts accumulating in the relayer's home directory, which are applied by the `gc` command
+	// an artifact without a policy is never removed, except that the proof archive falls back to proof_archive_max_entries and proof_archive_retention
+	// the entries referenced by the current state, e.g. the registration of the active enclave key, are never removed
+	RetentionPolicies []RetentionPolicy `protobuf:"bytes,59,rep,name=retention_policies,json=retentionPolicies,proto3" json:"retention_policies"`
+	// unit: seconds
+	// if not zero, the retention policies are also applied at this interval while relaying
+	GcInterval uint64 `protobuf:"varint,60,opt,name=gc_interval,json=gcInterval,proto3" json:"gc_interval,omitempty"`
+	// --- Shared Registration Config --- //
+	// if not empty, the enclave key registrations are recorded in this file
+	// so that the other relayer instances for the same LCP client wait for them instead of registering another key
+	// the file should be on a storage shared with the other instances
+	// a relative path is resolved from the relayer's home directory
+	SharedRegistrationPath string `protobuf:"bytes,39,opt,name=shared_registration_path,json=sharedRegistrationPath,proto3" json:"shared_registration_path,omitempty"`
+	// unit: seconds
+	// a registration by another instance is considered pending until it is finalized or this timeout elapses
+	// if zero, the default value is used
+	SharedRegistrationTimeout uint64 `protobuf:"varint,40,opt,name=shared_registration_timeout,json=sharedRegistrationTimeout,proto3" json:"shared_registration_timeout,omitempty"`
+	// the identifier of this instance in the shared registration file
+	// if empty, the hostname and the home directory are used
+	InstanceId string `protobuf:"bytes,41,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	// --- Submission Queue Config --- //
+	// if true, the msgs that the prover submits, e.g. the enclave key registrations and the operator updates, are persisted in a queue
+	// in the relayer's home directory and submitted in the order of their dependencies, e.g. an operator update before the registrations
+	// the msgs left in the queue by a crash are submitted before the next msgs
+	SubmissionQueue bool `protobuf:"varint,61,opt,name=submission_queue,json=submissionQueue,proto3" json:"submission_queue,omitempty"`
+	// the maximum number of the msgs in a tx batching the compatible queued msgs. if zero, the number is not limited
+	SubmissionQueueMaxMsgsPerTx uint32 `protobuf:"varint,62,opt,name=submission_queue_max_msgs_per_tx,json=submissionQueueMaxMsgsPerTx,proto3" json:"submission_queue_max_msgs_per_tx,omitempty"`
+	// --- Diagnostics Config --- //
+	// if not empty, the diagnostics HTTP server listens on this address ("host:port") while relaying
+	// it serves pprof, expvar and the goroutine dump, so it should be bound to a loopback or private address
+	DiagnosticsAddress string `protobuf:"bytes,44,opt,name=diagnostics_address,json=diagnosticsAddress,proto3" json:"diagnostics_address,omitempty"`
+	// --- Metrics Config --- //
+	// the sink exporting the metrics of the prover
+	// "relayer": recorded with the global meter provider, which is exported by the telemetry config of the relayer if enabled
+	// "noop": not recorded
+	// "prometheus": served from metrics_address in the Prometheus text format while relaying
+	// "pushgateway": pushed to metrics_pushgateway_url periodically while relaying
+	// if empty, "relayer" is used
+	MetricsSink string `protobuf:"bytes,75,opt,name=metrics_sink,json=metricsSink,proto3" json:"metrics_sink,omitempty"`
+	// the address ("host:port") on which the "prometheus" sink serves /metrics
+	MetricsAddress string `protobuf:"bytes,76,opt,name=metrics_address,json=metricsAddress,proto3" json:"metrics_address,omitempty"`
+	// the URL of the Prometheus pushgateway to which the "pushgateway" sink pushes the metrics
+	// the metrics are grouped by the chain ID of the origin chain as the "instance" label
+	MetricsPushgatewayUrl string `protobuf:"bytes,77,opt,name=metrics_pushgateway_url,json=metricsPushgatewayUrl,proto3" json:"metrics_pushgateway_url,omitempty"`
+	// the job name of the metrics pushed to the pushgateway
+	// if empty, the default value is used
+	MetricsPushgatewayJob string `protobuf:"bytes,78,opt,name=metrics_pushgateway_job,json=metricsPushgatewayJob,proto3" json:"metrics_pushgateway_job,omitempty"`
+	// unit: seconds
+	// the interval to push the metrics to the pushgateway
+	// if zero, the default value is used
+	MetricsPushInterval uint64 `protobuf:"varint,79,opt,name=metrics_push_interval,json=metricsPushInterval,proto3" json:"metrics_push_interval,omitempty"`
+	// --- Tx Options Config --- //
+	// options of the txs that the prover submits to the counterparty chain per message type
+	// they are applied only if the counterparty chain supports them, otherwise they are ignored with a warning
+	TxOptions []TxOptions `protobuf:"bytes,45,rep,name=tx_options,json=txOptions,proto3" json:"tx_options"`
+	// the retry policy of the txs registering an enclave key and activating the client on a transient failure
+	// e.g. a sequence mismatch or a full mempool. the failures rejected by the LCP client are not retried
+	// if not set, the txs are not retried
+	TxRetryPolicy *TxRetryPolicy `protobuf:"bytes,54,opt,name=tx_retry_policy,json=txRetryPolicy,proto3" json:"tx_retry_policy,omitempty"`
+	// --- Finality Tracker Config --- //
+	// the name of the tracker deciding the inclusion, the success and the finality of the msgs that the prover submits
+	// if empty, "default" is used, which compares the block including a msg with the latest finalized header of the counterparty chain
+	// a custom tracker must be registered with relay.RegisterFinalityTracker before the relayer starts
+	FinalityTracker string `protobuf:"bytes,46,opt,name=finality_tracker,json=finalityTracker,proto3" json:"finality_tracker,omitempty"`
+	// the number of blocks on top of the block including a msg to consider the msg finalized
+	// it is used only by the "confirmations" tracker
+	FinalityConfirmations uint64 `protobuf:"varint,47,opt,name=finality_confirmations,json=finalityConfirmations,proto3" json:"finality_confirmations,omitempty"`
+	// the number of blocks on top of the block including an enclave key registration required in addition to the finality of the tracker
+	// the registered key is promoted to finalized only after both are met. if zero, only the finality of the tracker is required
+	ConfirmationsRequired uint64 `protobuf:"varint,50,opt,name=confirmations_required,json=confirmationsRequired,proto3" json:"confirmations_required,omitempty"`
+	// unit: seconds
+	// the expected time from the inclusion of an enclave key registration to its finality
+	// if zero, it is estimated as the average block time of the counterparty chain times the number of the confirmations for the "confirmations" tracker,
+	// and the watchdog of the unfinalized registrations is disabled for the other trackers, whose finality time is unknown
+	ExpectedFinalizationTime uint64 `protobuf:"varint,72,opt,name=expected_finalization_time,json=expectedFinalizationTime,proto3" json:"expected_finalization_time,omitempty"`
+	// an enclave key registration is considered stuck if it has been unfinalized longer than expected_finalization_time times this multiplier,
+	// e.g. the counterparty chain halts after the registration is included
+	// a stuck registration is alerted and reported as degraded by the health of the diagnostics server
+	// if zero, the default value is used
+	UnfinalizedKeyWatchdogMultiplier uint64 `protobuf:"varint,73,opt,name=unfinalized_key_watchdog_multiplier,json=unfinalizedKeyWatchdogMultiplier,proto3" json:"unfinalized_key_watchdog_multiplier,omitempty"`
+	// if true, a stuck registration that was executed successfully is saved as finalized and its finality is no longer tracked
+	// enable this only on the chains where the risk that the registration is reorganized after that is acceptable
+	FinalizeStuckUnfinalizedKey bool `protobuf:"varint,74,opt,name=finalize_stuck_unfinalized_key,json=finalizeStuckUnfinalizedKey,proto3" json:"finalize_stuck_unfinalized_key,omitempty"`
 	// eip712 params
 	//
 	// Types that are valid to be assigned to OperatorsEip712Params:
@@ -173,6 +409,271 @@ func (m *Fraction) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Fraction proto.InternalMessageInfo
 
+type RateLimit struct {
+	// the average number of calls allowed per second
+	CallsPerSecond float64 `protobuf:"fixed64,1,opt,name=calls_per_second,json=callsPerSecond,proto3" json:"calls_per_second,omitempty"`
+	// the maximum number of calls allowed in a burst
+	// if zero, 1 is used
+	Burst uint32 `protobuf:"varint,2,opt,name=burst,proto3" json:"burst,omitempty"`
+}
+
+func (m *RateLimit) Reset()         { *m = RateLimit{} }
+func (m *RateLimit) String() string { return proto.CompactTextString(m) }
+func (*RateLimit) ProtoMessage()    {}
+func (*RateLimit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2e6956e1b8ef896e, []int{2}
+}
+func (m *RateLimit) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RateLimit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RateLimit.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RateLimit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RateLimit.Merge(m, src)
+}
+func (m *RateLimit) XXX_Size() int {
+	return m.Size()
+}
+func (m *RateLimit) XXX_DiscardUnknown() {
+	xxx_messageInfo_RateLimit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RateLimit proto.InternalMessageInfo
+
+type TxRetryPolicy struct {
+	// the maximum number of retries after the first attempt
+	MaxRetries uint32 `protobuf:"varint,1,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
+	// unit: milliseconds
+	// the interval before the first retry, which is doubled on each subsequent retry
+	// if zero, the default value is used
+	RetryInterval uint64 `protobuf:"varint,2,opt,name=retry_interval,json=retryInterval,proto3" json:"retry_interval,omitempty"`
+}
+
+func (m *TxRetryPolicy) Reset()         { *m = TxRetryPolicy{} }
+func (m *TxRetryPolicy) String() string { return proto.CompactTextString(m) }
+func (*TxRetryPolicy) ProtoMessage()    {}
+func (*TxRetryPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2e6956e1b8ef896e, []int{3}
+}
+func (m *TxRetryPolicy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TxRetryPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TxRetryPolicy.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TxRetryPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxRetryPolicy.Merge(m, src)
+}
+func (m *TxRetryPolicy) XXX_Size() int {
+	return m.Size()
+}
+func (m *TxRetryPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxRetryPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxRetryPolicy proto.InternalMessageInfo
+
+type QuotePolicyOverride struct {
+	// chain ID of the counterparty chain
+	CounterpartyChainId string `protobuf:"bytes,1,opt,name=counterparty_chain_id,json=counterpartyChainId,proto3" json:"counterparty_chain_id,omitempty"`
+	// client ID of the LCP client on the counterparty chain
+	// if empty, the override applies to all clients on the counterparty chain
+	CounterpartyClientId string `protobuf:"bytes,2,opt,name=counterparty_client_id,json=counterpartyClientId,proto3" json:"counterparty_client_id,omitempty"`
+	// these values replace the global ones as a whole, i.e. an empty list allows only "OK" and no advisory IDs
+	AllowedQuoteStatuses []string `protobuf:"bytes,3,rep,name=allowed_quote_statuses,json=allowedQuoteStatuses,proto3" json:"allowed_quote_statuses,omitempty"`
+	AllowedAdvisoryIds   []string `protobuf:"bytes,4,rep,name=allowed_advisory_ids,json=allowedAdvisoryIds,proto3" json:"allowed_advisory_ids,omitempty"`
+}
+
+func (m *QuotePolicyOverride) Reset()         { *m = QuotePolicyOverride{} }
+func (m *QuotePolicyOverride) String() string { return proto.CompactTextString(m) }
+func (*QuotePolicyOverride) ProtoMessage()    {}
+func (*QuotePolicyOverride) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2e6956e1b8ef896e, []int{4}
+}
+func (m *QuotePolicyOverride) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QuotePolicyOverride) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QuotePolicyOverride.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QuotePolicyOverride) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotePolicyOverride.Merge(m, src)
+}
+func (m *QuotePolicyOverride) XXX_Size() int {
+	return m.Size()
+}
+func (m *QuotePolicyOverride) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotePolicyOverride.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotePolicyOverride proto.InternalMessageInfo
+
+type RetentionPolicy struct {
+	// "proof_archive" or "unfinalized_eki"
+	Artifact string `protobuf:"bytes,1,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	// unit: seconds
+	// the entries older than this are removed. if zero, the entries are not removed by age
+	MaxAge uint64 `protobuf:"varint,2,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	// the oldest entries exceeding this number are removed. if zero, the entries are not removed by count
+	MaxCount uint64 `protobuf:"varint,3,opt,name=max_count,json=maxCount,proto3" json:"max_count,omitempty"`
+}
+
+func (m *RetentionPolicy) Reset()         { *m = RetentionPolicy{} }
+func (m *RetentionPolicy) String() string { return proto.CompactTextString(m) }
+func (*RetentionPolicy) ProtoMessage()    {}
+func (*RetentionPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2e6956e1b8ef896e, []int{5}
+}
+func (m *RetentionPolicy) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RetentionPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RetentionPolicy.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RetentionPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetentionPolicy.Merge(m, src)
+}
+func (m *RetentionPolicy) XXX_Size() int {
+	return m.Size()
+}
+func (m *RetentionPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetentionPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetentionPolicy proto.InternalMessageInfo
+
+type TxOptions struct {
+	// "register_enclave_key" or "activate_client"
+	// the options of "register_enclave_key" also apply to the registration bundled with the first updates
+	MsgType string `protobuf:"bytes,1,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`
+	// the multiplier of the fee estimated by the counterparty chain
+	// if zero, the default of the counterparty chain is used
+	FeeMultiplier float64 `protobuf:"fixed64,2,opt,name=fee_multiplier,json=feeMultiplier,proto3" json:"fee_multiplier,omitempty"`
+	// a hint of the priority of the tx, e.g. the priority fee
+	// the interpretation depends on the counterparty chain
+	Priority string `protobuf:"bytes,3,opt,name=priority,proto3" json:"priority,omitempty"`
+	// the memo of the tx, e.g. to identify the LCP maintenance txs for accounting
+	Memo string `protobuf:"bytes,4,opt,name=memo,proto3" json:"memo,omitempty"`
+}
+
+func (m *TxOptions) Reset()         { *m = TxOptions{} }
+func (m *TxOptions) String() string { return proto.CompactTextString(m) }
+func (*TxOptions) ProtoMessage()    {}
+func (*TxOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2e6956e1b8ef896e, []int{6}
+}
+func (m *TxOptions) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TxOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TxOptions.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TxOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxOptions.Merge(m, src)
+}
+func (m *TxOptions) XXX_Size() int {
+	return m.Size()
+}
+func (m *TxOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxOptions proto.InternalMessageInfo
+
+type EIP1271OperatorConfig struct {
+	// hex address of the contract wallet, e.g. a Safe, holding the operator identity
+	// this must be equal to the first operator
+	WalletAddress string `protobuf:"bytes,1,opt,name=wallet_address,json=walletAddress,proto3" json:"wallet_address,omitempty"`
+	// the RPC endpoint of the EVM host chain to poll the approvals of the wallet
+	RpcUrl string `protobuf:"bytes,2,opt,name=rpc_url,json=rpcUrl,proto3" json:"rpc_url,omitempty"`
+	// unit: seconds
+	// if zero, the default value is used
+	PollInterval uint64 `protobuf:"varint,3,opt,name=poll_interval,json=pollInterval,proto3" json:"poll_interval,omitempty"`
+	// unit: seconds
+	// the deadline to wait for an approval
+	// if zero, the default value is used
+	ApprovalTimeout uint64 `protobuf:"varint,4,opt,name=approval_timeout,json=approvalTimeout,proto3" json:"approval_timeout,omitempty"`
+}
+
+func (m *EIP1271OperatorConfig) Reset()         { *m = EIP1271OperatorConfig{} }
+func (m *EIP1271OperatorConfig) String() string { return proto.CompactTextString(m) }
+func (*EIP1271OperatorConfig) ProtoMessage()    {}
+func (*EIP1271OperatorConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2e6956e1b8ef896e, []int{7}
+}
+func (m *EIP1271OperatorConfig) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *EIP1271OperatorConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_EIP1271OperatorConfig.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *EIP1271OperatorConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EIP1271OperatorConfig.Merge(m, src)
+}
+func (m *EIP1271OperatorConfig) XXX_Size() int {
+	return m.Size()
+}
+func (m *EIP1271OperatorConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_EIP1271OperatorConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EIP1271OperatorConfig proto.InternalMessageInfo
+
 type EIP712EVMChainParams struct {
 	ChainId                  uint64 `protobuf:"varint,1,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
 	VerifyingContractAddress string `protobuf:"bytes,2,opt,name=verifying_contract_address,json=verifyingContractAddress,proto3" json:"verifying_contract_address,omitempty"`
@@ -182,7 +683,7 @@ func (m *EIP712EVMChainParams) Reset()         { *m = EIP712EVMChainParams{} }
 func (m *EIP712EVMChainParams) String() string { return proto.CompactTextString(m) }
 func (*EIP712EVMChainParams) ProtoMessage()    {}
 func (*EIP712EVMChainParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2e6956e1b8ef896e, []int{2}
+	return fileDescriptor_2e6956e1b8ef896e, []int{8}
 }
 func (m *EIP712EVMChainParams) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -220,7 +721,7 @@ func (m *EIP712CosmosChainParams) Reset()         { *m = EIP712CosmosChainParams
 func (m *EIP712CosmosChainParams) String() string { return proto.CompactTextString(m) }
 func (*EIP712CosmosChainParams) ProtoMessage()    {}
 func (*EIP712CosmosChainParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2e6956e1b8ef896e, []int{3}
+	return fileDescriptor_2e6956e1b8ef896e, []int{9}
 }
 func (m *EIP712CosmosChainParams) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -252,6 +753,12 @@ var xxx_messageInfo_EIP712CosmosChainParams proto.InternalMessageInfo
 func init() {
 	proto.RegisterType((*ProverConfig)(nil), "relayer.provers.lcp.config.ProverConfig")
 	proto.RegisterType((*Fraction)(nil), "relayer.provers.lcp.config.Fraction")
+	proto.RegisterType((*RateLimit)(nil), "relayer.provers.lcp.config.RateLimit")
+	proto.RegisterType((*TxRetryPolicy)(nil), "relayer.provers.lcp.config.TxRetryPolicy")
+	proto.RegisterType((*QuotePolicyOverride)(nil), "relayer.provers.lcp.config.QuotePolicyOverride")
+	proto.RegisterType((*RetentionPolicy)(nil), "relayer.provers.lcp.config.RetentionPolicy")
+	proto.RegisterType((*TxOptions)(nil), "relayer.provers.lcp.config.TxOptions")
+	proto.RegisterType((*EIP1271OperatorConfig)(nil), "relayer.provers.lcp.config.EIP1271OperatorConfig")
 	proto.RegisterType((*EIP712EVMChainParams)(nil), "relayer.provers.lcp.config.EIP712EVMChainParams")
 	proto.RegisterType((*EIP712CosmosChainParams)(nil), "relayer.provers.lcp.config.EIP712CosmosChainParams")
 }
@@ -261,55 +768,169 @@ func init() {
 }
 
 var fileDescriptor_2e6956e1b8ef896e = []byte{
-	// 766 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x41, 0x6f, 0x1b, 0x45,
-	0x14, 0xf6, 0xb6, 0x26, 0xb1, 0xc7, 0xb1, 0x81, 0x89, 0x69, 0x37, 0xa6, 0x6c, 0x17, 0x2b, 0x08,
-	0x5f, 0xd8, 0x6d, 0x53, 0xa4, 0x08, 0x09, 0x0e, 0xb6, 0x6b, 0x84, 0x11, 0x48, 0x61, 0x5d, 0x71,
-	0x80, 0xc3, 0x68, 0x3c, 0xfb, 0xbc, 0x1e, 0x75, 0x76, 0x67, 0x99, 0x59, 0x9b, 0xba, 0xe2, 0xca,
-	0x9d, 0x9f, 0x95, 0x63, 0x8f, 0x9c, 0x10, 0x24, 0xff, 0x03, 0xa1, 0x9d, 0x5d, 0xdb, 0x69, 0x9c,
-	0x84, 0x93, 0x3d, 0xdf, 0xf7, 0xbd, 0xef, 0x7d, 0x7e, 0xf3, 0x3c, 0xe8, 0x53, 0x05, 0x82, 0xae,
-	0x40, 0xf9, 0xa9, 0x92, 0x4b, 0x50, 0xda, 0x17, 0x2c, 0xf5, 0x99, 0x4c, 0x66, 0x3c, 0x2a, 0x3f,
-	0xbc, 0x54, 0xc9, 0x4c, 0xe2, 0x4e, 0x29, 0xf4, 0x4a, 0xa1, 0x27, 0x58, 0xea, 0x15, 0x8a, 0x4e,
-	0x3b, 0x92, 0x91, 0x34, 0x32, 0x3f, 0xff, 0x56, 0x54, 0x74, 0x8e, 0x22, 0x29, 0x23, 0x01, 0xbe,
-	0x39, 0x4d, 0x17, 0x33, 0x9f, 0x26, 0xab, 0x82, 0xea, 0xfe, 0xbb, 0x8f, 0x0e, 0xce, 0x8c, 0xcf,
-	0xd0, 0x38, 0xe0, 0x2f, 0x50, 0x53, 0x2a, 0x1e, 0xf1, 0x84, 0x14, 0xf6, 0xb6, 0xe5, 0x5a, 0xbd,
-	0xc6, 0x49, 0xdb, 0x2b, 0x3c, 0xbc, 0xb5, 0x87, 0xd7, 0x4f, 0x56, 0xc1, 0x41, 0x21, 0x2d, 0x0c,
-	0xb0, 0x87, 0x0e, 0x05, 0x4b, 0x89, 0x06, 0xb5, 0xe4, 0x0c, 0x08, 0x0d, 0x43, 0x05, 0x5a, 0xdb,
-	0xf7, 0x5c, 0xab, 0x57, 0x0f, 0xde, 0x17, 0x2c, 0x9d, 0x14, 0x4c, 0xbf, 0x20, 0xf0, 0x29, 0xb2,
-	0xaf, 0xea, 0x43, 0x4e, 0x05, 0xc9, 0x78, 0x0c, 0x72, 0x91, 0xd9, 0xf7, 0x5d, 0xab, 0x57, 0x0d,
-	0x3e, 0xd8, 0x16, 0x3d, 0xe7, 0x54, 0xbc, 0x28, 0x48, 0xfc, 0x08, 0xd5, 0x63, 0x05, 0x09, 0x13,
-	0x74, 0x09, 0x76, 0xd5, 0xd8, 0x6f, 0x01, 0xfc, 0x39, 0x7a, 0x40, 0x85, 0x90, 0xbf, 0x42, 0x48,
-	0x7e, 0x59, 0xc8, 0x0c, 0x88, 0xce, 0x68, 0xb6, 0xd0, 0xa0, 0xed, 0x77, 0xdc, 0xfb, 0xbd, 0x7a,
-	0xd0, 0x2e, 0xd9, 0x1f, 0x72, 0x72, 0x52, 0x72, 0xf8, 0x09, 0x5a, 0xe3, 0x84, 0x86, 0x4b, 0xae,
-	0xa5, 0x5a, 0x11, 0x1e, 0x6a, 0x7b, 0xcf, 0xd4, 0xe0, 0x92, 0xeb, 0x97, 0xd4, 0x38, 0xd4, 0xf8,
-	0x13, 0xd4, 0x7a, 0x09, 0x2b, 0x02, 0xaf, 0x52, 0xae, 0x68, 0xc6, 0x65, 0x62, 0xef, 0x9b, 0xd0,
-	0xcd, 0x97, 0xb0, 0x1a, 0x6d, 0x40, 0xdc, 0x45, 0x4d, 0x10, 0x8c, 0x30, 0xc1, 0x21, 0xc9, 0x08,
-	0x0f, 0xed, 0x9a, 0x09, 0xdc, 0x00, 0xc1, 0x86, 0x06, 0x1b, 0x87, 0xd8, 0x47, 0x87, 0x31, 0x68,
-	0x4d, 0x23, 0x20, 0x34, 0x8a, 0x14, 0x44, 0x85, 0x5f, 0xdd, 0xb5, 0x7a, 0xb5, 0x00, 0x97, 0x54,
-	0x7f, 0xcb, 0xe0, 0x21, 0x72, 0x6e, 0x28, 0x20, 0x53, 0x9a, 0xb1, 0x39, 0xd1, 0xfc, 0x35, 0xd8,
-	0xc8, 0x64, 0xf9, 0x70, 0xb7, 0x76, 0x90, 0x6b, 0x26, 0xfc, 0x35, 0xe0, 0x1e, 0x7a, 0x8f, 0x6b,
-	0x12, 0xc2, 0x74, 0x11, 0x91, 0xf5, 0x34, 0x1b, 0xa6, 0x65, 0x8b, 0xeb, 0xe7, 0x39, 0x3c, 0x2a,
-	0x47, 0xfa, 0x08, 0xd5, 0x65, 0x0a, 0x8a, 0x66, 0x52, 0x69, 0xfb, 0xc0, 0x4c, 0x64, 0x0b, 0xe0,
-	0x9f, 0xd1, 0xe1, 0xe6, 0x40, 0xb2, 0xb9, 0x02, 0x3d, 0x97, 0x22, 0xb4, 0x9b, 0x66, 0x71, 0x8e,
-	0xbd, 0xdb, 0xd7, 0xd5, 0xfb, 0x5a, 0x51, 0x66, 0x32, 0x55, 0xcf, 0xff, 0x7a, 0x5c, 0x09, 0xf0,
-	0xc6, 0xe6, 0xc5, 0xda, 0x05, 0x7f, 0x85, 0xde, 0x5d, 0xa3, 0x44, 0xf3, 0x28, 0x01, 0x65, 0xb7,
-	0xee, 0xd8, 0xc8, 0xd6, 0x5a, 0x3c, 0x31, 0x5a, 0xfc, 0x1b, 0xfa, 0x78, 0x9b, 0x0d, 0x78, 0x7a,
-	0xfa, 0xf4, 0x84, 0xc0, 0x32, 0x26, 0x6c, 0x4e, 0xf3, 0x15, 0xa7, 0x8a, 0xc6, 0xda, 0x7e, 0x6c,
-	0x0c, 0x9f, 0xdc, 0x95, 0x74, 0x34, 0x3e, 0x3b, 0x7d, 0x7a, 0x32, 0xfa, 0xf1, 0xfb, 0x61, 0x5e,
-	0x78, 0x66, 0xea, 0xbe, 0xa9, 0x04, 0x1f, 0x6d, 0xcc, 0x47, 0xc6, 0x7b, 0xb4, 0x8c, 0xaf, 0x08,
-	0xf0, 0xef, 0x16, 0x3a, 0xde, 0x69, 0xcf, 0xa4, 0x8e, 0xa5, 0x7e, 0x3b, 0x81, 0x6b, 0x12, 0x3c,
-	0xfb, 0xff, 0x04, 0x43, 0x53, 0xfc, 0x76, 0x08, 0xf7, 0x5a, 0x88, 0x1d, 0xcd, 0xe0, 0x08, 0x3d,
-	0xdc, 0x89, 0x51, 0x74, 0xee, 0x7e, 0x8b, 0x6a, 0xeb, 0x5b, 0xc8, 0xaf, 0x39, 0x59, 0xc4, 0x85,
-	0xce, 0xfc, 0xef, 0xab, 0xc1, 0x16, 0xc0, 0x2e, 0x6a, 0x84, 0x90, 0xc8, 0x98, 0x27, 0x86, 0xbf,
-	0x67, 0xf8, 0xab, 0x50, 0x57, 0xa2, 0xf6, 0x4d, 0x73, 0xc2, 0x47, 0xa8, 0x56, 0xfc, 0x5a, 0x1e,
-	0x96, 0xb6, 0xfb, 0xe6, 0x3c, 0x0e, 0xf1, 0x97, 0xa8, 0xb3, 0x04, 0xc5, 0x67, 0x2b, 0x9e, 0x44,
-	0x84, 0xc9, 0x24, 0xcb, 0xb3, 0x5c, 0x7b, 0x3a, 0xec, 0x8d, 0x62, 0x58, 0x0a, 0xca, 0x17, 0xa4,
-	0xfb, 0x1d, 0x7a, 0x78, 0xcb, 0x58, 0x76, 0x7a, 0xd6, 0xb7, 0x3d, 0x1f, 0xa0, 0xbd, 0x54, 0xc1,
-	0x8c, 0xbf, 0x2a, 0xfd, 0xcb, 0xd3, 0x60, 0x70, 0xfe, 0x8f, 0x53, 0x39, 0xbf, 0x70, 0xac, 0x37,
-	0x17, 0x8e, 0xf5, 0xf7, 0x85, 0x63, 0xfd, 0x71, 0xe9, 0x54, 0xde, 0x5c, 0x3a, 0x95, 0x3f, 0x2f,
-	0x9d, 0xca, 0x4f, 0xc7, 0x11, 0xcf, 0xe6, 0x8b, 0xa9, 0xc7, 0x64, 0xec, 0x87, 0x34, 0xa3, 0xc6,
-	0x4d, 0xd0, 0x69, 0xfe, 0x4e, 0x7f, 0x16, 0x49, 0xdf, 0x5c, 0xdd, 0x74, 0xcf, 0x6c, 0xe3, 0xb3,
-	0xff, 0x02, 0x00, 0x00, 0xff, 0xff, 0x7e, 0x81, 0x99, 0x27, 0xce, 0x05, 0x00, 0x00,
+	// 2577 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x59, 0xdd, 0x77, 0x1c, 0xb5,
+	0x15, 0xcf, 0x12, 0x93, 0xd8, 0x72, 0xd6, 0x76, 0xe4, 0x2f, 0xd9, 0x4e, 0x9c, 0x8d, 0x09, 0xe0,
+	0x40, 0xb1, 0x13, 0x07, 0x08, 0xb4, 0x40, 0xb1, 0x37, 0x0e, 0x38, 0x89, 0xb1, 0x59, 0x1b, 0x38,
+	0xa7, 0xed, 0xa9, 0xd0, 0xce, 0x68, 0x67, 0xd5, 0x9d, 0x19, 0x4d, 0x24, 0xcd, 0x7a, 0x97, 0xd3,
+	0x3e, 0xf6, 0xbd, 0xff, 0x45, 0xff, 0x15, 0x1e, 0x79, 0x6b, 0x9f, 0x7a, 0x5a, 0x78, 0xe8, 0xbf,
+	0xd1, 0xa3, 0xab, 0xf9, 0x5a, 0x7f, 0x71, 0xe8, 0x93, 0x77, 0xee, 0xfd, 0xfd, 0xae, 0xae, 0x74,
+	0x3f, 0xf4, 0x61, 0xf4, 0xa6, 0xe2, 0x21, 0x1b, 0x72, 0xb5, 0x99, 0x28, 0xd9, 0xe7, 0x4a, 0x6f,
+	0x86, 0x5e, 0xb2, 0xe9, 0xc9, 0xb8, 0x23, 0x82, 0xec, 0xcf, 0x46, 0xa2, 0xa4, 0x91, 0x78, 0x39,
+	0x03, 0x6e, 0x64, 0xc0, 0x8d, 0xd0, 0x4b, 0x36, 0x1c, 0x62, 0x79, 0x2e, 0x90, 0x81, 0x04, 0xd8,
+	0xa6, 0xfd, 0xe5, 0x18, 0xcb, 0x4b, 0x81, 0x94, 0x41, 0xc8, 0x37, 0xe1, 0xab, 0x9d, 0x76, 0x36,
+	0x59, 0x3c, 0x74, 0xaa, 0xb5, 0x7f, 0xbc, 0x81, 0x6e, 0x1c, 0x82, 0x9d, 0x26, 0x58, 0xc0, 0x1f,
+	0xa2, 0xba, 0x54, 0x22, 0x10, 0x31, 0x75, 0xe6, 0x49, 0xad, 0x51, 0x5b, 0x9f, 0xdc, 0x9a, 0xdb,
+	0x70, 0x36, 0x36, 0x72, 0x1b, 0x1b, 0xdb, 0xf1, 0xb0, 0x75, 0xc3, 0x41, 0x9d, 0x01, 0xfc, 0x02,
+	0x2d, 0x76, 0x58, 0x18, 0xb6, 0x99, 0xd7, 0xa3, 0x23, 0x36, 0x34, 0x79, 0xd8, 0xb8, 0x7a, 0xa1,
+	0x91, 0xf9, 0x9c, 0x74, 0x50, 0x31, 0xa6, 0xf1, 0x06, 0x9a, 0x0d, 0xbd, 0x84, 0x6a, 0xae, 0xfa,
+	0xc2, 0xe3, 0x94, 0xf9, 0xbe, 0xe2, 0x5a, 0x93, 0x57, 0x1a, 0xb5, 0xf5, 0x89, 0xd6, 0xcd, 0xd0,
+	0x4b, 0x8e, 0x9c, 0x66, 0xdb, 0x29, 0xf0, 0x63, 0x44, 0xaa, 0x78, 0x5f, 0xb0, 0x90, 0x1a, 0x11,
+	0x71, 0x99, 0x1a, 0x72, 0xb5, 0x51, 0x5b, 0x1f, 0x6b, 0xcd, 0x97, 0xa4, 0x27, 0x82, 0x85, 0xc7,
+	0x4e, 0x89, 0xb7, 0xd1, 0xed, 0x2a, 0x51, 0x71, 0x4f, 0xc6, 0x31, 0xf7, 0x4c, 0xc1, 0xfe, 0x2d,
+	0xb0, 0x97, 0x4b, 0x76, 0x2b, 0x87, 0xe4, 0x26, 0xde, 0x43, 0x8b, 0x55, 0x13, 0x26, 0xd4, 0x94,
+	0xc7, 0xac, 0x1d, 0x72, 0x9f, 0x34, 0x1b, 0xb5, 0xf5, 0xf1, 0xd6, 0x5c, 0x49, 0x3e, 0x0e, 0xf5,
+	0xae, 0xd3, 0xe1, 0x77, 0xcf, 0xd2, 0x3c, 0x46, 0x3d, 0xae, 0x0c, 0x79, 0x02, 0xd3, 0x9c, 0x1d,
+	0xa1, 0x35, 0x59, 0x93, 0xab, 0x33, 0x83, 0x79, 0xa1, 0xe0, 0xb1, 0x71, 0xac, 0x5d, 0x60, 0x55,
+	0x06, 0x6b, 0x82, 0x12, 0x68, 0x8f, 0xd0, 0xc2, 0x39, 0xb4, 0x1e, 0x1f, 0x92, 0xa7, 0xa7, 0xc7,
+	0x72, 0xac, 0xe7, 0x7c, 0x88, 0xf7, 0xd1, 0xbd, 0xd3, 0x1e, 0xda, 0xdf, 0x5c, 0xd1, 0x98, 0x45,
+	0x9c, 0xda, 0x48, 0x29, 0xe1, 0x73, 0xf2, 0x19, 0x98, 0xb8, 0x33, 0xe2, 0xee, 0x11, 0x00, 0xbf,
+	0x60, 0x11, 0x3f, 0xc8, 0x60, 0x36, 0xa6, 0x90, 0x11, 0x54, 0x1b, 0x66, 0x78, 0xb1, 0xc0, 0x6b,
+	0xb0, 0xc0, 0x37, 0x41, 0x75, 0x64, 0x35, 0xf9, 0xba, 0x6e, 0xa1, 0xf9, 0x34, 0xf1, 0x99, 0x29,
+	0xdc, 0xcd, 0x19, 0xaf, 0x01, 0x63, 0xd6, 0x29, 0x9d, 0xbb, 0x39, 0xe7, 0x8f, 0x88, 0x8c, 0x72,
+	0x94, 0xfd, 0x1d, 0x8a, 0x48, 0x18, 0x72, 0x0f, 0x72, 0xf9, 0xf5, 0x8d, 0x8b, 0x2b, 0x68, 0xa3,
+	0xc5, 0x0c, 0x7f, 0x61, 0xc1, 0xad, 0xf9, 0xaa, 0xf5, 0x42, 0x8c, 0x3b, 0xe8, 0x56, 0x9f, 0x2b,
+	0xd1, 0x19, 0xd2, 0x88, 0x47, 0x6d, 0xae, 0x74, 0x57, 0x24, 0xd5, 0x31, 0x5e, 0xff, 0x25, 0x63,
+	0x2c, 0x39, 0x53, 0xfb, 0x85, 0xa5, 0x72, 0x9c, 0x03, 0x34, 0xf3, 0x32, 0xe5, 0x6a, 0x58, 0xb5,
+	0xfd, 0xc6, 0x2f, 0xb1, 0x3d, 0x05, 0xf4, 0xd2, 0xe0, 0x2d, 0x34, 0x11, 0x29, 0x1e, 0x7b, 0x21,
+	0xeb, 0x73, 0x32, 0x06, 0x01, 0x2b, 0x05, 0xf8, 0x5d, 0xb4, 0xc0, 0xc2, 0x50, 0x9e, 0x70, 0x9f,
+	0xbe, 0x4c, 0xa5, 0x71, 0x21, 0x4a, 0x35, 0xd7, 0xe4, 0xd5, 0xc6, 0x55, 0x9b, 0x54, 0x99, 0xf6,
+	0x4b, 0xab, 0x3c, 0xca, 0x74, 0xf8, 0x01, 0xca, 0xe5, 0x94, 0xf9, 0x7d, 0xa1, 0xa5, 0x1a, 0x52,
+	0xe1, 0x6b, 0x72, 0x0d, 0x38, 0x38, 0xd3, 0x6d, 0x67, 0xaa, 0x3d, 0x5f, 0xe3, 0x1e, 0x5a, 0x70,
+	0xf6, 0x13, 0x19, 0x0a, 0x6f, 0x58, 0xa4, 0x90, 0x26, 0x77, 0xa1, 0x47, 0x6c, 0x5e, 0x36, 0x39,
+	0x18, 0xfc, 0x10, 0x88, 0x79, 0x4e, 0xed, 0x8c, 0x7d, 0xff, 0xaf, 0x3b, 0x57, 0x5a, 0x73, 0x2f,
+	0xcf, 0xaa, 0x34, 0x7e, 0x1d, 0x4d, 0xf5, 0xf8, 0x90, 0xf2, 0x41, 0x22, 0x14, 0x33, 0x42, 0xc6,
+	0xe4, 0x3a, 0x24, 0x4e, 0xbd, 0xc7, 0x87, 0xbb, 0x85, 0x10, 0xef, 0xa0, 0xd5, 0x44, 0xf1, 0x0e,
+	0x57, 0x54, 0xc6, 0xd4, 0xeb, 0x32, 0x11, 0xd3, 0x53, 0xb4, 0x5f, 0x43, 0x15, 0x2f, 0x3b, 0xd4,
+	0x41, 0xdc, 0xb4, 0x98, 0xe7, 0x23, 0x36, 0xee, 0xa1, 0xa9, 0x88, 0x0d, 0x68, 0x96, 0x7a, 0x01,
+	0x4b, 0xc8, 0x03, 0x18, 0xea, 0x46, 0xc4, 0x06, 0x5f, 0x81, 0xf0, 0x33, 0x96, 0xe0, 0x6f, 0xd1,
+	0x82, 0xe2, 0x1d, 0xc5, 0x75, 0x97, 0x9a, 0xae, 0xfd, 0x23, 0x43, 0x1f, 0x02, 0x4c, 0x0e, 0x21,
+	0xb4, 0xf7, 0x2e, 0x9b, 0xfd, 0x53, 0xc5, 0x3c, 0x3b, 0x56, 0x3e, 0xe5, 0xcc, 0xd2, 0x71, 0x6e,
+	0xc8, 0x86, 0x1a, 0xaf, 0xa1, 0x3a, 0x0f, 0xbd, 0x3c, 0xf7, 0x85, 0x4f, 0xc6, 0x21, 0xd2, 0x93,
+	0x3c, 0xf4, 0x5c, 0x26, 0xef, 0xf9, 0x78, 0x13, 0xcd, 0x46, 0x5c, 0x6b, 0x16, 0x70, 0xca, 0x82,
+	0x40, 0xf1, 0xc0, 0x4d, 0x72, 0x02, 0x26, 0x89, 0x33, 0xd5, 0x76, 0xa9, 0xc1, 0x4d, 0xb4, 0x7a,
+	0x0e, 0x81, 0xb6, 0x99, 0xf1, 0xba, 0x54, 0x8b, 0xef, 0x38, 0x41, 0x30, 0xd9, 0x95, 0xb3, 0xdc,
+	0x1d, 0x8b, 0x39, 0x12, 0xdf, 0x41, 0x86, 0xd9, 0x15, 0xf2, 0x64, 0xec, 0xa5, 0x4a, 0x59, 0xef,
+	0xdc, 0x62, 0x69, 0xf2, 0x61, 0xa3, 0xb6, 0x5e, 0x6f, 0xcd, 0x45, 0x6c, 0xd0, 0x2c, 0x94, 0x6e,
+	0xcd, 0x34, 0x5e, 0x47, 0x33, 0x42, 0x53, 0x9f, 0xb7, 0xd3, 0x80, 0xe6, 0xc9, 0x3b, 0x09, 0x8e,
+	0x4e, 0x09, 0xfd, 0xc4, 0x8a, 0x77, 0xb3, 0x0c, 0x7e, 0x8c, 0x08, 0xe4, 0xdb, 0x28, 0xd8, 0x46,
+	0x52, 0x93, 0x59, 0x60, 0xcc, 0x83, 0xbe, 0x4a, 0x7a, 0xce, 0x87, 0x1a, 0xbf, 0x81, 0xa6, 0x23,
+	0x11, 0x8b, 0x28, 0x8d, 0xa8, 0xd0, 0x7d, 0xaa, 0xfb, 0x31, 0x59, 0x05, 0x8f, 0xea, 0x99, 0x78,
+	0x4f, 0xf7, 0x8f, 0xfa, 0x31, 0xde, 0x44, 0x73, 0xbe, 0xc7, 0x12, 0xaa, 0xa4, 0x74, 0xfd, 0x96,
+	0x26, 0xcc, 0x74, 0x35, 0x79, 0x0f, 0x92, 0xfd, 0xa6, 0xd5, 0xb5, 0xa4, 0x84, 0x6e, 0x7b, 0x68,
+	0x15, 0xf8, 0x73, 0x74, 0xb7, 0x12, 0x0b, 0x33, 0x4c, 0x38, 0x8d, 0x84, 0x8e, 0xdc, 0xaa, 0x71,
+	0x5b, 0xfa, 0x66, 0x48, 0x30, 0xc4, 0xe7, 0x76, 0x11, 0x9f, 0xe3, 0x61, 0xc2, 0xf7, 0x33, 0xd4,
+	0x51, 0x06, 0xc2, 0x3b, 0xe8, 0xb6, 0x6d, 0x7d, 0xda, 0xb0, 0x28, 0xa1, 0x8a, 0x07, 0x76, 0xc7,
+	0xb3, 0x11, 0x28, 0xac, 0xbc, 0x05, 0x56, 0x56, 0x0a, 0x50, 0xab, 0xc0, 0x14, 0x36, 0x3e, 0x46,
+	0x2b, 0xed, 0x34, 0xf6, 0x43, 0xbb, 0xc5, 0x05, 0x42, 0x1b, 0xae, 0xaa, 0x6b, 0x44, 0xe6, 0x60,
+	0x89, 0x88, 0x83, 0xb4, 0x32, 0x44, 0xb9, 0x4c, 0xd6, 0x05, 0x4f, 0xa6, 0xb1, 0xe1, 0x2a, 0x61,
+	0xca, 0x0c, 0x69, 0x16, 0x6a, 0x6a, 0xb3, 0x54, 0xc8, 0x58, 0x93, 0xf9, 0xc6, 0xd5, 0xf5, 0x7a,
+	0x6b, 0xa5, 0x0a, 0xda, 0x77, 0x98, 0xaf, 0x33, 0x08, 0xfe, 0x14, 0xdd, 0xea, 0xb3, 0x50, 0xf8,
+	0x2e, 0x7d, 0x3c, 0x19, 0x1b, 0x3e, 0x30, 0xd4, 0x56, 0x55, 0x28, 0x82, 0xae, 0x21, 0x8f, 0x5d,
+	0x99, 0x95, 0x98, 0xa6, 0x83, 0x1c, 0xe6, 0x08, 0xfc, 0x19, 0x6a, 0x9c, 0x63, 0x41, 0xb3, 0x0e,
+	0xb7, 0x2e, 0x31, 0x15, 0x88, 0x98, 0x7c, 0x00, 0xb9, 0x78, 0xfb, 0x8c, 0x95, 0x23, 0x40, 0xed,
+	0x03, 0xc8, 0x76, 0x43, 0x99, 0x70, 0xc5, 0x8c, 0x54, 0x9a, 0xdc, 0x80, 0x08, 0x96, 0x02, 0xfc,
+	0x7b, 0x34, 0x5b, 0x7c, 0x94, 0x95, 0x4a, 0xea, 0xbf, 0xb8, 0x48, 0x71, 0x61, 0xa6, 0x28, 0x53,
+	0xfc, 0x31, 0x9a, 0xce, 0xa5, 0x54, 0x8b, 0x20, 0xe6, 0x8a, 0x4c, 0x5d, 0x72, 0xc8, 0x9a, 0xca,
+	0xc1, 0x47, 0x80, 0xc5, 0x7f, 0x40, 0x33, 0x05, 0x9d, 0x8b, 0xe4, 0xe1, 0xd6, 0xe3, 0x87, 0xe4,
+	0x6d, 0xe0, 0x3f, 0xbc, 0xcc, 0xb1, 0xdd, 0xbd, 0x43, 0x0b, 0x3d, 0xc8, 0xa8, 0xee, 0xb8, 0xd7,
+	0x2a, 0x3c, 0xd9, 0x75, 0x96, 0xf0, 0x2a, 0x9a, 0x14, 0x4c, 0x53, 0x4f, 0x85, 0x34, 0x55, 0x21,
+	0x99, 0x76, 0xfb, 0x84, 0x60, 0xba, 0xa9, 0xc2, 0xaf, 0x54, 0x68, 0xab, 0x2c, 0xd7, 0xe7, 0x9d,
+	0x4c, 0xd8, 0x78, 0xf7, 0x59, 0x48, 0x66, 0xdc, 0x31, 0xcb, 0x81, 0x5b, 0x4e, 0xbb, 0x97, 0x29,
+	0xf1, 0x7d, 0x74, 0x33, 0x27, 0x76, 0x98, 0x08, 0xa9, 0x4c, 0x78, 0x4c, 0x6e, 0x66, 0x95, 0x0c,
+	0x8c, 0xa7, 0x4c, 0x84, 0x07, 0x09, 0x8f, 0xf1, 0x5b, 0xc8, 0x9e, 0x05, 0x64, 0x87, 0x32, 0xe5,
+	0x75, 0x45, 0xdf, 0x1e, 0xe6, 0x14, 0x59, 0x00, 0x4f, 0xa6, 0x41, 0xb1, 0xed, 0xe4, 0x4f, 0x84,
+	0xc2, 0x1f, 0xa2, 0xa5, 0x51, 0xac, 0xed, 0x31, 0x3c, 0x36, 0x4a, 0x70, 0x4d, 0x16, 0xc1, 0xa1,
+	0x85, 0x2a, 0x67, 0x9f, 0x0d, 0x76, 0x9d, 0x16, 0xbf, 0x8f, 0x16, 0x47, 0xa9, 0x8a, 0x1b, 0x1e,
+	0x43, 0x2b, 0x24, 0x6e, 0x26, 0x55, 0x62, 0x2b, 0x57, 0x9e, 0x1d, 0x12, 0xe6, 0xe3, 0x85, 0x52,
+	0x73, 0x9f, 0x2c, 0xc1, 0x8c, 0x46, 0x86, 0xb4, 0xf3, 0x6a, 0x82, 0xd6, 0xce, 0x8c, 0x85, 0xb6,
+	0x73, 0x9c, 0xf0, 0x76, 0x57, 0xca, 0x1e, 0xac, 0xf1, 0xb2, 0x9b, 0x19, 0x28, 0xbe, 0x71, 0x72,
+	0xbb, 0xd2, 0xb0, 0x23, 0xbb, 0x2e, 0x33, 0x0c, 0x25, 0xf3, 0xa9, 0xe1, 0x51, 0x12, 0xda, 0xbd,
+	0x62, 0xc5, 0x1d, 0xf3, 0x40, 0x7b, 0xe8, 0x94, 0xc7, 0x99, 0xce, 0xed, 0xc8, 0x96, 0xe5, 0x73,
+	0x3f, 0x4d, 0xca, 0xd8, 0xdc, 0x82, 0x19, 0x61, 0xd0, 0x3d, 0xb1, 0xaa, 0x22, 0x30, 0xbb, 0xe8,
+	0x8e, 0x63, 0x9c, 0x53, 0x58, 0x59, 0x45, 0xdd, 0x06, 0xf2, 0x2d, 0x80, 0x7d, 0x7d, 0xba, 0xac,
+	0xb2, 0x82, 0xda, 0x43, 0x77, 0x99, 0x31, 0xb6, 0xfb, 0x80, 0x85, 0x6c, 0x7b, 0xf7, 0xba, 0xdc,
+	0xeb, 0x95, 0x5e, 0x3c, 0x02, 0x43, 0xab, 0x15, 0xa0, 0xdb, 0xb2, 0x9b, 0x16, 0x56, 0x78, 0xf4,
+	0x14, 0x35, 0xba, 0x2c, 0x34, 0x76, 0x37, 0x3e, 0xc7, 0xa4, 0xaf, 0x44, 0xc7, 0x90, 0x77, 0x61,
+	0x9d, 0x6f, 0x59, 0xdc, 0x41, 0xbc, 0x7d, 0xda, 0xde, 0x13, 0x8b, 0xb1, 0x81, 0xf2, 0x42, 0xe9,
+	0xf5, 0xa8, 0xee, 0xf1, 0x93, 0xd3, 0xae, 0x7c, 0xea, 0x72, 0x03, 0x00, 0x47, 0x3d, 0x7e, 0x32,
+	0xea, 0xc2, 0x03, 0x34, 0x57, 0xa1, 0x96, 0x1d, 0x60, 0xdb, 0x2d, 0x63, 0xc1, 0x2a, 0xab, 0x7a,
+	0x0b, 0xcd, 0x57, 0x07, 0x93, 0x4a, 0x71, 0x68, 0x04, 0x64, 0x07, 0x3c, 0x9d, 0x2d, 0x07, 0x2a,
+	0x54, 0xf8, 0x5b, 0x84, 0x8b, 0x9c, 0x73, 0xd3, 0xb3, 0x59, 0xfb, 0x1b, 0x38, 0x08, 0xbd, 0x7d,
+	0xe9, 0x29, 0x2f, 0x67, 0xb9, 0xe9, 0x66, 0xcd, 0xe6, 0xa6, 0x1a, 0x11, 0xdb, 0x1c, 0xbf, 0x83,
+	0x26, 0x03, 0xaf, 0x9c, 0xf4, 0x47, 0xe0, 0x3e, 0x0a, 0xbc, 0x62, 0xa2, 0x1f, 0x20, 0xa2, 0xbb,
+	0x4c, 0x71, 0x3f, 0xdb, 0x15, 0x54, 0xb6, 0xd6, 0xcc, 0x74, 0xc9, 0x9b, 0x90, 0x67, 0x0b, 0x4e,
+	0xdf, 0xaa, 0xa8, 0xed, 0xf6, 0x86, 0x3f, 0x41, 0x2b, 0xe7, 0x31, 0xf3, 0x23, 0xfa, 0x3a, 0x0c,
+	0xb5, 0x74, 0x96, 0x9c, 0x1f, 0xd4, 0xef, 0xa0, 0x49, 0x11, 0x6b, 0xc3, 0x62, 0x8f, 0xdb, 0x73,
+	0xca, 0x7d, 0x18, 0x0c, 0xe5, 0xa2, 0x3d, 0x1f, 0xdf, 0x47, 0x33, 0x3a, 0x6d, 0x47, 0xc2, 0x6d,
+	0x75, 0x2f, 0x53, 0x9e, 0x72, 0xf2, 0x31, 0x2c, 0xe6, 0x74, 0x29, 0xff, 0xd2, 0x8a, 0xf1, 0x2e,
+	0x6a, 0x9c, 0x86, 0x42, 0x23, 0x88, 0x74, 0xa0, 0x69, 0xc2, 0x15, 0x35, 0x03, 0xf2, 0x09, 0xec,
+	0xe9, 0x2b, 0xa7, 0xa8, 0xfb, 0x6c, 0xb0, 0xaf, 0x03, 0x7d, 0xc8, 0xd5, 0xf1, 0xc0, 0x1e, 0x8c,
+	0x7c, 0xc1, 0x82, 0x58, 0x6a, 0x23, 0x3c, 0x5d, 0xdc, 0x39, 0x7f, 0x05, 0xae, 0xe1, 0x8a, 0x2a,
+	0xbf, 0x74, 0xde, 0x45, 0x37, 0x22, 0x6e, 0x94, 0x05, 0x6b, 0x11, 0xf7, 0xc8, 0x73, 0x77, 0xd8,
+	0xca, 0x64, 0x47, 0x22, 0xee, 0xe1, 0x37, 0xd1, 0x74, 0x0e, 0xc9, 0xed, 0xbd, 0x00, 0xd4, 0x54,
+	0x26, 0xce, 0x6d, 0xbd, 0x8f, 0x16, 0x73, 0x60, 0x92, 0xea, 0x6e, 0xc0, 0x0c, 0x3f, 0x61, 0x43,
+	0xe8, 0x10, 0xfb, 0x40, 0x98, 0xcf, 0xd4, 0x87, 0xa5, 0xd6, 0xf6, 0x89, 0x0b, 0x78, 0x7f, 0x92,
+	0x6d, 0xf2, 0xc5, 0x45, 0xbc, 0x67, 0xb2, 0x6d, 0x13, 0xb6, 0xca, 0x2b, 0x93, 0xe4, 0xc0, 0x5d,
+	0xae, 0x2a, 0xac, 0x22, 0x5b, 0x9e, 0x21, 0x64, 0x06, 0x54, 0x26, 0x06, 0x76, 0xfc, 0x77, 0x20,
+	0x51, 0x2f, 0xbd, 0x8e, 0x1c, 0x0f, 0x0e, 0x1c, 0x38, 0x4b, 0xd1, 0x09, 0x93, 0x0b, 0xf0, 0x97,
+	0x68, 0xda, 0x0c, 0x6c, 0xcf, 0x55, 0xc3, 0xac, 0xb4, 0xc9, 0xfb, 0xb0, 0x8d, 0xdd, 0xbf, 0xdc,
+	0x60, 0xcb, 0x32, 0x5c, 0xde, 0xb7, 0xea, 0xa6, 0xfa, 0x69, 0x33, 0xa6, 0x23, 0x62, 0x16, 0x0a,
+	0x33, 0xa4, 0x46, 0x31, 0xaf, 0xc7, 0x15, 0xd9, 0x70, 0xdd, 0x35, 0x97, 0x1f, 0x3b, 0x31, 0x7e,
+	0x0f, 0x2d, 0x14, 0x50, 0x30, 0xad, 0x22, 0xe6, 0x66, 0xb5, 0xe9, 0x7a, 0x7f, 0xae, 0x6d, 0x56,
+	0x95, 0x96, 0x36, 0x82, 0xa6, 0x8a, 0xbf, 0x4c, 0x85, 0xe2, 0x3e, 0xd9, 0x72, 0xb4, 0x11, 0x6d,
+	0x2b, 0x53, 0xe2, 0x8f, 0xd0, 0x32, 0x1f, 0x24, 0xdc, 0x33, 0xdc, 0xa7, 0xce, 0xf0, 0x77, 0x65,
+	0xb5, 0x90, 0xcf, 0x81, 0x4a, 0x72, 0xc4, 0xd3, 0x0a, 0xc0, 0x16, 0x0b, 0xde, 0x47, 0xaf, 0xa5,
+	0x71, 0x46, 0xe3, 0x3e, 0xdc, 0x4d, 0x4e, 0xec, 0xf1, 0xd0, 0x97, 0x01, 0x8d, 0xd2, 0xd0, 0x88,
+	0x24, 0x14, 0x5c, 0x91, 0x3d, 0x30, 0xd3, 0xa8, 0x40, 0x9f, 0xf3, 0xe1, 0x37, 0x19, 0x70, 0xbf,
+	0xc0, 0xd9, 0xd3, 0x7c, 0x8e, 0xa0, 0xda, 0xa4, 0x5e, 0x8f, 0x9e, 0xb2, 0x4e, 0x9e, 0x41, 0x95,
+	0xad, 0xe4, 0xc2, 0x23, 0x0b, 0xfa, 0x6a, 0xc4, 0x2c, 0xfe, 0x33, 0xba, 0x5b, 0x9e, 0x90, 0xb8,
+	0x48, 0x1e, 0x3f, 0xdc, 0xa2, 0xbc, 0x1f, 0x65, 0xd7, 0xa7, 0x84, 0x29, 0x16, 0x69, 0x72, 0x07,
+	0xe2, 0xf9, 0xe0, 0x67, 0x8e, 0x25, 0x8f, 0x1f, 0x6e, 0xed, 0x7e, 0xbd, 0x0f, 0x77, 0xaa, 0x43,
+	0xe0, 0x7d, 0x7e, 0xa5, 0x75, 0xbb, 0x30, 0xbe, 0x0b, 0xb6, 0x77, 0xfb, 0x51, 0x05, 0x80, 0xff,
+	0x5a, 0x43, 0xf7, 0xce, 0x0c, 0xef, 0x49, 0x1d, 0x49, 0x3d, 0xea, 0x41, 0x03, 0x3c, 0x78, 0xf4,
+	0xf3, 0x1e, 0x34, 0x81, 0x3c, 0xea, 0x44, 0xe3, 0x94, 0x13, 0x67, 0x30, 0x3b, 0x4b, 0x68, 0xf1,
+	0x8c, 0x1b, 0x6e, 0xe4, 0xb5, 0x67, 0x68, 0x3c, 0x3f, 0x0b, 0xda, 0xc3, 0x66, 0x9c, 0x46, 0x0e,
+	0x07, 0x0f, 0x6a, 0x63, 0xad, 0x52, 0x80, 0x1b, 0x68, 0xd2, 0xe7, 0xb1, 0x8c, 0x44, 0x0c, 0xfa,
+	0x57, 0x40, 0x5f, 0x15, 0xad, 0x3d, 0x47, 0x13, 0xe5, 0x3d, 0x7e, 0x1d, 0xcd, 0x78, 0x2c, 0x0c,
+	0x5d, 0x5f, 0xd3, 0xdc, 0x93, 0xb1, 0x0f, 0x36, 0x6b, 0xad, 0x29, 0x90, 0x1f, 0x72, 0x75, 0x04,
+	0x52, 0x3c, 0x87, 0x5e, 0x6d, 0xa7, 0x4a, 0x1b, 0x30, 0x59, 0x6f, 0xb9, 0x8f, 0xb5, 0x6f, 0x50,
+	0x7d, 0xa4, 0x88, 0x6c, 0x23, 0x8e, 0x98, 0xab, 0x44, 0xbb, 0xfd, 0xd4, 0x00, 0x8c, 0x22, 0x06,
+	0x20, 0xe1, 0xae, 0xd1, 0xae, 0x4c, 0x8b, 0x16, 0xe1, 0x7c, 0xac, 0x83, 0x34, 0x6f, 0x0e, 0x6b,
+	0xff, 0xad, 0xa1, 0xd9, 0x73, 0x6e, 0xe8, 0xb0, 0x33, 0x56, 0x6f, 0x0e, 0x2e, 0x40, 0xc2, 0x79,
+	0x3d, 0xd1, 0x9a, 0xad, 0x2a, 0x61, 0x71, 0xf7, 0xec, 0xd3, 0xd8, 0xc2, 0x28, 0xa7, 0xb8, 0xcf,
+	0xba, 0x07, 0xc0, 0xb9, 0x11, 0x52, 0x7e, 0xb1, 0xbd, 0xf8, 0x11, 0xe3, 0xea, 0xff, 0xf1, 0x88,
+	0x31, 0x76, 0xd1, 0x23, 0xc6, 0x9a, 0x87, 0xa6, 0x4f, 0xed, 0xc0, 0x78, 0x19, 0x8d, 0x33, 0x65,
+	0x44, 0x87, 0x79, 0x26, 0x9b, 0x57, 0xf1, 0x8d, 0x17, 0xd1, 0x75, 0xbb, 0xc0, 0x2c, 0xe0, 0xd9,
+	0xc2, 0x5d, 0x8b, 0xd8, 0x60, 0x3b, 0xe0, 0x78, 0x05, 0x4d, 0xb8, 0x2b, 0x71, 0x1a, 0xe7, 0x8f,
+	0x94, 0xe3, 0x70, 0x0b, 0x4e, 0x63, 0xb3, 0xf6, 0x17, 0x34, 0x51, 0x74, 0x4f, 0xbc, 0x84, 0xc6,
+	0x23, 0x1d, 0xc0, 0x1d, 0x32, 0x33, 0x7f, 0x3d, 0xd2, 0x81, 0xbd, 0x2b, 0xda, 0xe8, 0x74, 0x38,
+	0xaf, 0x36, 0x82, 0x57, 0x20, 0x1b, 0xea, 0x1d, 0xce, 0x2b, 0x55, 0xbf, 0x8c, 0xc6, 0x13, 0x25,
+	0x24, 0xdc, 0x16, 0xaf, 0x3a, 0x07, 0xf3, 0x6f, 0x8c, 0xd1, 0x58, 0xc4, 0x23, 0x99, 0xbd, 0x0a,
+	0xc1, 0xef, 0xb5, 0xbf, 0xd7, 0xd0, 0xfc, 0xb9, 0x77, 0x06, 0x3b, 0xe0, 0x09, 0x0b, 0x43, 0x6e,
+	0x8a, 0x0d, 0xcd, 0x79, 0x54, 0x77, 0xd2, 0x7c, 0x3f, 0x5b, 0x44, 0xd7, 0x55, 0xe2, 0xc1, 0xfe,
+	0xe5, 0x62, 0x76, 0x4d, 0x25, 0x9e, 0xdd, 0xb0, 0x5e, 0x43, 0xf5, 0x44, 0x86, 0x61, 0x99, 0x4d,
+	0x6e, 0xe6, 0x37, 0xac, 0xb0, 0x72, 0x5d, 0x98, 0x61, 0x89, 0xad, 0xd6, 0xca, 0x33, 0xee, 0x18,
+	0xe0, 0xa6, 0x73, 0x79, 0x76, 0x90, 0x58, 0x93, 0x68, 0xee, 0xbc, 0x2e, 0x62, 0xd7, 0x6c, 0x24,
+	0xd5, 0xc6, 0x5a, 0xd7, 0xbd, 0x2c, 0xbd, 0x3e, 0x42, 0xcb, 0xee, 0xe5, 0x4d, 0xc4, 0x01, 0x1c,
+	0x76, 0x6d, 0xa5, 0x9e, 0x7a, 0x63, 0x26, 0x05, 0xa2, 0x99, 0x01, 0xb2, 0x99, 0xad, 0xbd, 0x40,
+	0x8b, 0x17, 0x34, 0x8d, 0x33, 0x63, 0x4e, 0x94, 0x63, 0x2e, 0xa0, 0x6b, 0xf6, 0xa6, 0x2b, 0x06,
+	0xf9, 0x72, 0xb8, 0xaf, 0x9d, 0x9d, 0xef, 0xff, 0xb3, 0x7a, 0xe5, 0xfb, 0x1f, 0x57, 0x6b, 0x3f,
+	0xfc, 0xb8, 0x5a, 0xfb, 0xf7, 0x8f, 0xab, 0xb5, 0xbf, 0xfd, 0xb4, 0x7a, 0xe5, 0x87, 0x9f, 0x56,
+	0xaf, 0xfc, 0xf3, 0xa7, 0xd5, 0x2b, 0xbf, 0xbb, 0x17, 0x08, 0xd3, 0x4d, 0xdb, 0x1b, 0x9e, 0x8c,
+	0x36, 0x7d, 0x66, 0x18, 0x58, 0x0b, 0x59, 0xdb, 0xfe, 0x7b, 0xe0, 0x9d, 0x40, 0x6e, 0x42, 0x63,
+	0x6b, 0x5f, 0x83, 0x1b, 0xe3, 0xa3, 0xff, 0x0d, 0x00, 0xae, 0x60, 0xb8, 0x7c, 0x45, 0x18, 0x00,
+	0x00,
 }
 
 func (m *ProverConfig) Marshal() (dAtA []byte, err error) {
@@ -332,29 +953,8 @@ func (m *ProverConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
-	if m.OperatorsEip712Params != nil {
-		{
-			size := m.OperatorsEip712Params.Size()
-			i -= size
-			if _, err := m.OperatorsEip712Params.MarshalTo(dAtA[i:]); err != nil {
-				return 0, err
-			}
-		}
-	}
-	if m.OperatorSigner != nil {
-		{
-			size, err := m.OperatorSigner.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintConfig(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x72
-	}
 	{
-		size, err := m.OperatorsThreshold.MarshalToSizedBuffer(dAtA[:i])
+		size, err := m.RefreshThresholdRate.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
 			return 0, err
 		}
@@ -362,93 +962,242 @@ func (m *ProverConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i = encodeVarintConfig(dAtA, i, uint64(size))
 	}
 	i--
-	dAtA[i] = 0x6a
-	if len(m.Operators) > 0 {
-		for iNdEx := len(m.Operators) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Operators[iNdEx])
-			copy(dAtA[i:], m.Operators[iNdEx])
-			i = encodeVarintConfig(dAtA, i, uint64(len(m.Operators[iNdEx])))
-			i--
-			dAtA[i] = 0x62
-		}
+	dAtA[i] = 0x5
+	i--
+	dAtA[i] = 0x82
+	if m.MetricsPushInterval != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MetricsPushInterval))
+		i--
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xf8
 	}
-	if m.IsDebugEnclave {
+	if len(m.MetricsPushgatewayJob) > 0 {
+		i -= len(m.MetricsPushgatewayJob)
+		copy(dAtA[i:], m.MetricsPushgatewayJob)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MetricsPushgatewayJob)))
 		i--
-		if m.IsDebugEnclave {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
+		dAtA[i] = 0x4
 		i--
-		dAtA[i] = 0x58
+		dAtA[i] = 0xf2
 	}
-	if m.MessageAggregationBatchSize != 0 {
-		i = encodeVarintConfig(dAtA, i, uint64(m.MessageAggregationBatchSize))
+	if len(m.MetricsPushgatewayUrl) > 0 {
+		i -= len(m.MetricsPushgatewayUrl)
+		copy(dAtA[i:], m.MetricsPushgatewayUrl)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MetricsPushgatewayUrl)))
 		i--
-		dAtA[i] = 0x50
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xea
 	}
-	if m.MessageAggregation {
+	if len(m.MetricsAddress) > 0 {
+		i -= len(m.MetricsAddress)
+		copy(dAtA[i:], m.MetricsAddress)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MetricsAddress)))
 		i--
-		if m.MessageAggregation {
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xe2
+	}
+	if len(m.MetricsSink) > 0 {
+		i -= len(m.MetricsSink)
+		copy(dAtA[i:], m.MetricsSink)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MetricsSink)))
+		i--
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xda
+	}
+	if m.FinalizeStuckUnfinalizedKey {
+		i--
+		if m.FinalizeStuckUnfinalizedKey {
 			dAtA[i] = 1
 		} else {
 			dAtA[i] = 0
 		}
 		i--
-		dAtA[i] = 0x48
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xd0
 	}
-	if len(m.ElcClientId) > 0 {
-		i -= len(m.ElcClientId)
-		copy(dAtA[i:], m.ElcClientId)
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.ElcClientId)))
+	if m.UnfinalizedKeyWatchdogMultiplier != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.UnfinalizedKeyWatchdogMultiplier))
 		i--
-		dAtA[i] = 0x42
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xc8
 	}
-	if m.KeyExpiration != 0 {
-		i = encodeVarintConfig(dAtA, i, uint64(m.KeyExpiration))
+	if m.ExpectedFinalizationTime != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ExpectedFinalizationTime))
 		i--
-		dAtA[i] = 0x38
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xc0
 	}
-	if len(m.AllowedAdvisoryIds) > 0 {
-		for iNdEx := len(m.AllowedAdvisoryIds) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.AllowedAdvisoryIds[iNdEx])
-			copy(dAtA[i:], m.AllowedAdvisoryIds[iNdEx])
-			i = encodeVarintConfig(dAtA, i, uint64(len(m.AllowedAdvisoryIds[iNdEx])))
-			i--
-			dAtA[i] = 0x32
-		}
+	if len(m.LcpServiceTlsServerNameOverride) > 0 {
+		i -= len(m.LcpServiceTlsServerNameOverride)
+		copy(dAtA[i:], m.LcpServiceTlsServerNameOverride)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.LcpServiceTlsServerNameOverride)))
+		i--
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xba
 	}
-	if len(m.AllowedQuoteStatuses) > 0 {
-		for iNdEx := len(m.AllowedQuoteStatuses) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.AllowedQuoteStatuses[iNdEx])
-			copy(dAtA[i:], m.AllowedQuoteStatuses[iNdEx])
-			i = encodeVarintConfig(dAtA, i, uint64(len(m.AllowedQuoteStatuses[iNdEx])))
-			i--
-			dAtA[i] = 0x2a
-		}
+	if len(m.LcpServiceClientKey) > 0 {
+		i -= len(m.LcpServiceClientKey)
+		copy(dAtA[i:], m.LcpServiceClientKey)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.LcpServiceClientKey)))
+		i--
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xb2
 	}
-	if len(m.Mrenclave) > 0 {
-		i -= len(m.Mrenclave)
-		copy(dAtA[i:], m.Mrenclave)
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.Mrenclave)))
+	if len(m.LcpServiceClientCert) > 0 {
+		i -= len(m.LcpServiceClientCert)
+		copy(dAtA[i:], m.LcpServiceClientCert)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.LcpServiceClientCert)))
 		i--
-		dAtA[i] = 0x22
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xaa
 	}
-	if m.LcpServiceDialTimeout != 0 {
-		i = encodeVarintConfig(dAtA, i, uint64(m.LcpServiceDialTimeout))
+	if len(m.LcpServiceTlsCaCert) > 0 {
+		i -= len(m.LcpServiceTlsCaCert)
+		copy(dAtA[i:], m.LcpServiceTlsCaCert)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.LcpServiceTlsCaCert)))
 		i--
-		dAtA[i] = 0x18
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0xa2
 	}
-	if len(m.LcpServiceAddress) > 0 {
-		i -= len(m.LcpServiceAddress)
-		copy(dAtA[i:], m.LcpServiceAddress)
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.LcpServiceAddress)))
+	if m.LcpServiceTlsEnabled {
 		i--
-		dAtA[i] = 0x12
+		if m.LcpServiceTlsEnabled {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0x98
 	}
-	if m.OriginProver != nil {
+	if m.ClockSkewCorrection {
+		i--
+		if m.ClockSkewCorrection {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0x90
+	}
+	if m.ClockSkewThreshold != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ClockSkewThreshold))
+		i--
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0x88
+	}
+	if m.ClockSkewCheckInterval != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ClockSkewCheckInterval))
+		i--
+		dAtA[i] = 0x4
+		i--
+		dAtA[i] = 0x80
+	}
+	if m.LcpServiceReconnectTimeout != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.LcpServiceReconnectTimeout))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xf8
+	}
+	if m.SubmissionQueueMaxMsgsPerTx != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.SubmissionQueueMaxMsgsPerTx))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xf0
+	}
+	if m.SubmissionQueue {
+		i--
+		if m.SubmissionQueue {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xe8
+	}
+	if m.GcInterval != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.GcInterval))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xe0
+	}
+	if len(m.RetentionPolicies) > 0 {
+		for iNdEx := len(m.RetentionPolicies) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.RetentionPolicies[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintConfig(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3
+			i--
+			dAtA[i] = 0xda
+		}
+	}
+	if m.PreferOnChainKeyExpiration {
+		i--
+		if m.PreferOnChainKeyExpiration {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xd0
+	}
+	if m.MaxConcurrentUpdates != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxConcurrentUpdates))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xc8
+	}
+	if m.ValidationContextSafetyMargin != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ValidationContextSafetyMargin))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xc0
+	}
+	if m.ValidationContextPreflight {
+		i--
+		if m.ValidationContextPreflight {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xb8
+	}
+	if m.TxRetryPolicy != nil {
 		{
-			size, err := m.OriginProver.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.TxRetryPolicy.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -456,21 +1205,114 @@ func (m *ProverConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			i = encodeVarintConfig(dAtA, i, uint64(size))
 		}
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xb2
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *ProverConfig_OperatorsEip712EvmChainParams) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *ProverConfig_OperatorsEip712EvmChainParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.OperatorsEip712EvmChainParams != nil {
+	if len(m.DcapRootCertPaths) > 0 {
+		for iNdEx := len(m.DcapRootCertPaths) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.DcapRootCertPaths[iNdEx])
+			copy(dAtA[i:], m.DcapRootCertPaths[iNdEx])
+			i = encodeVarintConfig(dAtA, i, uint64(len(m.DcapRootCertPaths[iNdEx])))
+			i--
+			dAtA[i] = 0x3
+			i--
+			dAtA[i] = 0xaa
+		}
+	}
+	if m.HaltOnAttestationPolicyDrift {
+		i--
+		if m.HaltOnAttestationPolicyDrift {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0xa0
+	}
+	if m.AttestationPolicyCheckInterval != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.AttestationPolicyCheckInterval))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0x98
+	}
+	if m.ConfirmationsRequired != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ConfirmationsRequired))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0x90
+	}
+	if len(m.FallbackOriginProvers) > 0 {
+		for iNdEx := len(m.FallbackOriginProvers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.FallbackOriginProvers[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintConfig(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3
+			i--
+			dAtA[i] = 0x8a
+		}
+	}
+	if m.MaxUpdateGap != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxUpdateGap))
+		i--
+		dAtA[i] = 0x3
+		i--
+		dAtA[i] = 0x80
+	}
+	if m.FinalityConfirmations != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.FinalityConfirmations))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xf8
+	}
+	if len(m.FinalityTracker) > 0 {
+		i -= len(m.FinalityTracker)
+		copy(dAtA[i:], m.FinalityTracker)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.FinalityTracker)))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xf2
+	}
+	if len(m.TxOptions) > 0 {
+		for iNdEx := len(m.TxOptions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.TxOptions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintConfig(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2
+			i--
+			dAtA[i] = 0xea
+		}
+	}
+	if len(m.DiagnosticsAddress) > 0 {
+		i -= len(m.DiagnosticsAddress)
+		copy(dAtA[i:], m.DiagnosticsAddress)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.DiagnosticsAddress)))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xe2
+	}
+	if m.OperatorEip1271 != nil {
 		{
-			size, err := m.OperatorsEip712EvmChainParams.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.OperatorEip1271.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -478,22 +1320,47 @@ func (m *ProverConfig_OperatorsEip712EvmChainParams) MarshalToSizedBuffer(dAtA [
 			i = encodeVarintConfig(dAtA, i, uint64(size))
 		}
 		i--
-		dAtA[i] = 0x1
+		dAtA[i] = 0x2
 		i--
-		dAtA[i] = 0xfa
+		dAtA[i] = 0xda
 	}
-	return len(dAtA) - i, nil
-}
-func (m *ProverConfig_OperatorsEip712CosmosChainParams) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *ProverConfig_OperatorsEip712CosmosChainParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	if m.OperatorsEip712CosmosChainParams != nil {
+	if len(m.TimestampRegressionSeverity) > 0 {
+		i -= len(m.TimestampRegressionSeverity)
+		copy(dAtA[i:], m.TimestampRegressionSeverity)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.TimestampRegressionSeverity)))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xd2
+	}
+	if len(m.InstanceId) > 0 {
+		i -= len(m.InstanceId)
+		copy(dAtA[i:], m.InstanceId)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.InstanceId)))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xca
+	}
+	if m.SharedRegistrationTimeout != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.SharedRegistrationTimeout))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xc0
+	}
+	if len(m.SharedRegistrationPath) > 0 {
+		i -= len(m.SharedRegistrationPath)
+		copy(dAtA[i:], m.SharedRegistrationPath)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.SharedRegistrationPath)))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xba
+	}
+	if m.QueryRateLimit != nil {
 		{
-			size, err := m.OperatorsEip712CosmosChainParams.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.QueryRateLimit.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -503,272 +1370,3565 @@ func (m *ProverConfig_OperatorsEip712CosmosChainParams) MarshalToSizedBuffer(dAt
 		i--
 		dAtA[i] = 0x2
 		i--
-		dAtA[i] = 0x82
+		dAtA[i] = 0xb2
 	}
-	return len(dAtA) - i, nil
-}
-func (m *Fraction) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if m.VerifyMembershipRateLimit != nil {
+		{
+			size, err := m.VerifyMembershipRateLimit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xaa
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Fraction) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *Fraction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Denominator != 0 {
-		i = encodeVarintConfig(dAtA, i, uint64(m.Denominator))
+	if m.UpdateClientRateLimit != nil {
+		{
+			size, err := m.UpdateClientRateLimit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
 		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0xa2
 	}
-	if m.Numerator != 0 {
-		i = encodeVarintConfig(dAtA, i, uint64(m.Numerator))
+	if m.UpdateClientTimeout != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.UpdateClientTimeout))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0x98
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *EIP712EVMChainParams) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if m.ProveStateTimeout != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ProveStateTimeout))
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0x90
 	}
-	return dAtA[:n], nil
-}
-
-func (m *EIP712EVMChainParams) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *EIP712EVMChainParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.VerifyingContractAddress) > 0 {
-		i -= len(m.VerifyingContractAddress)
-		copy(dAtA[i:], m.VerifyingContractAddress)
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.VerifyingContractAddress)))
+	if len(m.QuotePolicyOverrides) > 0 {
+		for iNdEx := len(m.QuotePolicyOverrides) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.QuotePolicyOverrides[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintConfig(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2
+			i--
+			dAtA[i] = 0x8a
+		}
+	}
+	if m.OperatorsEip712Params != nil {
+		{
+			size := m.OperatorsEip712Params.Size()
+			i -= size
+			if _, err := m.OperatorsEip712Params.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if m.MinimumIsvSvn != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MinimumIsvSvn))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xf0
 	}
-	if m.ChainId != 0 {
-		i = encodeVarintConfig(dAtA, i, uint64(m.ChainId))
+	if m.AlertValidationContextMargin != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.AlertValidationContextMargin))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xe8
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *EIP712CosmosChainParams) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
-
-func (m *EIP712CosmosChainParams) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *EIP712CosmosChainParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Prefix) > 0 {
-		i -= len(m.Prefix)
-		copy(dAtA[i:], m.Prefix)
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.Prefix)))
+	if m.AlertDedupInterval != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.AlertDedupInterval))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xe0
 	}
-	if len(m.ChainId) > 0 {
-		i -= len(m.ChainId)
-		copy(dAtA[i:], m.ChainId)
-		i = encodeVarintConfig(dAtA, i, uint64(len(m.ChainId)))
+	if len(m.AlertPayloadTemplate) > 0 {
+		i -= len(m.AlertPayloadTemplate)
+		copy(dAtA[i:], m.AlertPayloadTemplate)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.AlertPayloadTemplate)))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xda
 	}
-	return len(dAtA) - i, nil
-}
-
-func encodeVarintConfig(dAtA []byte, offset int, v uint64) int {
-	offset -= sovConfig(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+	if len(m.AlertWebhookUrl) > 0 {
+		i -= len(m.AlertWebhookUrl)
+		copy(dAtA[i:], m.AlertWebhookUrl)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.AlertWebhookUrl)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xd2
 	}
-	dAtA[offset] = uint8(v)
-	return base
-}
-func (m *ProverConfig) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.ProofArchiveFailClosed {
+		i--
+		if m.ProofArchiveFailClosed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc8
 	}
-	var l int
-	_ = l
-	if m.OriginProver != nil {
-		l = m.OriginProver.Size()
-		n += 1 + l + sovConfig(uint64(l))
+	if m.ProofArchiveRetention != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ProofArchiveRetention))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xc0
 	}
-	l = len(m.LcpServiceAddress)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+	if m.ProofArchiveMaxEntries != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ProofArchiveMaxEntries))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb8
 	}
-	if m.LcpServiceDialTimeout != 0 {
-		n += 1 + sovConfig(uint64(m.LcpServiceDialTimeout))
+	if len(m.ProofArchiveDir) > 0 {
+		i -= len(m.ProofArchiveDir)
+		copy(dAtA[i:], m.ProofArchiveDir)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ProofArchiveDir)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb2
 	}
-	l = len(m.Mrenclave)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+	if len(m.CounterpartyMessageVersions) > 0 {
+		dAtA8 := make([]byte, len(m.CounterpartyMessageVersions)*10)
+		var j7 int
+		for _, num := range m.CounterpartyMessageVersions {
+			for num >= 1<<7 {
+				dAtA8[j7] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j7++
+			}
+			dAtA8[j7] = uint8(num)
+			j7++
+		}
+		i -= j7
+		copy(dAtA[i:], dAtA8[:j7])
+		i = encodeVarintConfig(dAtA, i, uint64(j7))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xaa
 	}
-	if len(m.AllowedQuoteStatuses) > 0 {
-		for _, s := range m.AllowedQuoteStatuses {
-			l = len(s)
-			n += 1 + l + sovConfig(uint64(l))
+	if m.BundleRegisterEnclaveKey {
+		i--
+		if m.BundleRegisterEnclaveKey {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa0
 	}
-	if len(m.AllowedAdvisoryIds) > 0 {
-		for _, s := range m.AllowedAdvisoryIds {
-			l = len(s)
-			n += 1 + l + sovConfig(uint64(l))
+	if m.AllowDebugEnclaveKeys {
+		i--
+		if m.AllowDebugEnclaveKeys {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x98
 	}
-	if m.KeyExpiration != 0 {
-		n += 1 + sovConfig(uint64(m.KeyExpiration))
+	if len(m.ElcClientTypeMismatchSeverity) > 0 {
+		i -= len(m.ElcClientTypeMismatchSeverity)
+		copy(dAtA[i:], m.ElcClientTypeMismatchSeverity)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ElcClientTypeMismatchSeverity)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x92
 	}
-	l = len(m.ElcClientId)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+	if m.IasCrlFailOpen {
+		i--
+		if m.IasCrlFailOpen {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x88
 	}
-	if m.MessageAggregation {
-		n += 2
+	if m.IasCrlRefreshInterval != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.IasCrlRefreshInterval))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x80
 	}
-	if m.MessageAggregationBatchSize != 0 {
-		n += 1 + sovConfig(uint64(m.MessageAggregationBatchSize))
+	if len(m.IasCrlUrl) > 0 {
+		i -= len(m.IasCrlUrl)
+		copy(dAtA[i:], m.IasCrlUrl)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.IasCrlUrl)))
+		i--
+		dAtA[i] = 0x7a
 	}
-	if m.IsDebugEnclave {
-		n += 2
+	if m.OperatorSigner != nil {
+		{
+			size, err := m.OperatorSigner.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x72
 	}
-	if len(m.Operators) > 0 {
-		for _, s := range m.Operators {
-			l = len(s)
-			n += 1 + l + sovConfig(uint64(l))
+	{
+		size, err := m.OperatorsThreshold.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
 		}
+		i -= size
+		i = encodeVarintConfig(dAtA, i, uint64(size))
 	}
-	l = m.OperatorsThreshold.Size()
-	n += 1 + l + sovConfig(uint64(l))
-	if m.OperatorSigner != nil {
-		l = m.OperatorSigner.Size()
-		n += 1 + l + sovConfig(uint64(l))
+	i--
+	dAtA[i] = 0x6a
+	if len(m.Operators) > 0 {
+		for iNdEx := len(m.Operators) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Operators[iNdEx])
+			copy(dAtA[i:], m.Operators[iNdEx])
+			i = encodeVarintConfig(dAtA, i, uint64(len(m.Operators[iNdEx])))
+			i--
+			dAtA[i] = 0x62
+		}
 	}
-	if m.OperatorsEip712Params != nil {
-		n += m.OperatorsEip712Params.Size()
+	if m.IsDebugEnclave {
+		i--
+		if m.IsDebugEnclave {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x58
 	}
-	return n
-}
-
-func (m *ProverConfig_OperatorsEip712EvmChainParams) Size() (n int) {
-	if m == nil {
-		return 0
+	if m.MessageAggregationBatchSize != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MessageAggregationBatchSize))
+		i--
+		dAtA[i] = 0x50
 	}
-	var l int
-	_ = l
-	if m.OperatorsEip712EvmChainParams != nil {
-		l = m.OperatorsEip712EvmChainParams.Size()
-		n += 2 + l + sovConfig(uint64(l))
+	if m.MessageAggregation {
+		i--
+		if m.MessageAggregation {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x48
 	}
-	return n
-}
-func (m *ProverConfig_OperatorsEip712CosmosChainParams) Size() (n int) {
-	if m == nil {
-		return 0
+	if len(m.ElcClientId) > 0 {
+		i -= len(m.ElcClientId)
+		copy(dAtA[i:], m.ElcClientId)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ElcClientId)))
+		i--
+		dAtA[i] = 0x42
 	}
-	var l int
-	_ = l
-	if m.OperatorsEip712CosmosChainParams != nil {
-		l = m.OperatorsEip712CosmosChainParams.Size()
-		n += 2 + l + sovConfig(uint64(l))
+	if m.KeyExpiration != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.KeyExpiration))
+		i--
+		dAtA[i] = 0x38
 	}
-	return n
-}
-func (m *Fraction) Size() (n int) {
-	if m == nil {
-		return 0
+	if len(m.AllowedAdvisoryIds) > 0 {
+		for iNdEx := len(m.AllowedAdvisoryIds) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AllowedAdvisoryIds[iNdEx])
+			copy(dAtA[i:], m.AllowedAdvisoryIds[iNdEx])
+			i = encodeVarintConfig(dAtA, i, uint64(len(m.AllowedAdvisoryIds[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
 	}
-	var l int
-	_ = l
-	if m.Numerator != 0 {
-		n += 1 + sovConfig(uint64(m.Numerator))
+	if len(m.AllowedQuoteStatuses) > 0 {
+		for iNdEx := len(m.AllowedQuoteStatuses) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AllowedQuoteStatuses[iNdEx])
+			copy(dAtA[i:], m.AllowedQuoteStatuses[iNdEx])
+			i = encodeVarintConfig(dAtA, i, uint64(len(m.AllowedQuoteStatuses[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
 	}
-	if m.Denominator != 0 {
-		n += 1 + sovConfig(uint64(m.Denominator))
+	if len(m.Mrenclave) > 0 {
+		i -= len(m.Mrenclave)
+		copy(dAtA[i:], m.Mrenclave)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Mrenclave)))
+		i--
+		dAtA[i] = 0x22
 	}
-	return n
+	if m.LcpServiceDialTimeout != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.LcpServiceDialTimeout))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.LcpServiceAddress) > 0 {
+		i -= len(m.LcpServiceAddress)
+		copy(dAtA[i:], m.LcpServiceAddress)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.LcpServiceAddress)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.OriginProver != nil {
+		{
+			size, err := m.OriginProver.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *EIP712EVMChainParams) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *ProverConfig_OperatorsEip712EvmChainParams) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ProverConfig_OperatorsEip712EvmChainParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.OperatorsEip712EvmChainParams != nil {
+		{
+			size, err := m.OperatorsEip712EvmChainParams.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xfa
+	}
+	return len(dAtA) - i, nil
+}
+func (m *ProverConfig_OperatorsEip712CosmosChainParams) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ProverConfig_OperatorsEip712CosmosChainParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.OperatorsEip712CosmosChainParams != nil {
+		{
+			size, err := m.OperatorsEip712CosmosChainParams.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintConfig(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2
+		i--
+		dAtA[i] = 0x82
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Fraction) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *Fraction) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Fraction) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.ChainId != 0 {
-		n += 1 + sovConfig(uint64(m.ChainId))
+	if m.Denominator != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.Denominator))
+		i--
+		dAtA[i] = 0x10
 	}
-	l = len(m.VerifyingContractAddress)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+	if m.Numerator != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.Numerator))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RateLimit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RateLimit) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RateLimit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Burst != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.Burst))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.CallsPerSecond != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.CallsPerSecond))))
+		i--
+		dAtA[i] = 0x9
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TxRetryPolicy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxRetryPolicy) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TxRetryPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.RetryInterval != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.RetryInterval))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.MaxRetries != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxRetries))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QuotePolicyOverride) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QuotePolicyOverride) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QuotePolicyOverride) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.AllowedAdvisoryIds) > 0 {
+		for iNdEx := len(m.AllowedAdvisoryIds) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AllowedAdvisoryIds[iNdEx])
+			copy(dAtA[i:], m.AllowedAdvisoryIds[iNdEx])
+			i = encodeVarintConfig(dAtA, i, uint64(len(m.AllowedAdvisoryIds[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.AllowedQuoteStatuses) > 0 {
+		for iNdEx := len(m.AllowedQuoteStatuses) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.AllowedQuoteStatuses[iNdEx])
+			copy(dAtA[i:], m.AllowedQuoteStatuses[iNdEx])
+			i = encodeVarintConfig(dAtA, i, uint64(len(m.AllowedQuoteStatuses[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.CounterpartyClientId) > 0 {
+		i -= len(m.CounterpartyClientId)
+		copy(dAtA[i:], m.CounterpartyClientId)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.CounterpartyClientId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.CounterpartyChainId) > 0 {
+		i -= len(m.CounterpartyChainId)
+		copy(dAtA[i:], m.CounterpartyChainId)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.CounterpartyChainId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RetentionPolicy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RetentionPolicy) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RetentionPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.MaxCount != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxCount))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.MaxAge != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.MaxAge))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Artifact) > 0 {
+		i -= len(m.Artifact)
+		copy(dAtA[i:], m.Artifact)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Artifact)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TxOptions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxOptions) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TxOptions) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Memo) > 0 {
+		i -= len(m.Memo)
+		copy(dAtA[i:], m.Memo)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Memo)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Priority) > 0 {
+		i -= len(m.Priority)
+		copy(dAtA[i:], m.Priority)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Priority)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.FeeMultiplier != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.FeeMultiplier))))
+		i--
+		dAtA[i] = 0x11
+	}
+	if len(m.MsgType) > 0 {
+		i -= len(m.MsgType)
+		copy(dAtA[i:], m.MsgType)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.MsgType)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *EIP1271OperatorConfig) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EIP1271OperatorConfig) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *EIP1271OperatorConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ApprovalTimeout != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ApprovalTimeout))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.PollInterval != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.PollInterval))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.RpcUrl) > 0 {
+		i -= len(m.RpcUrl)
+		copy(dAtA[i:], m.RpcUrl)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.RpcUrl)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.WalletAddress) > 0 {
+		i -= len(m.WalletAddress)
+		copy(dAtA[i:], m.WalletAddress)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.WalletAddress)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *EIP712EVMChainParams) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EIP712EVMChainParams) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *EIP712EVMChainParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.VerifyingContractAddress) > 0 {
+		i -= len(m.VerifyingContractAddress)
+		copy(dAtA[i:], m.VerifyingContractAddress)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.VerifyingContractAddress)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.ChainId != 0 {
+		i = encodeVarintConfig(dAtA, i, uint64(m.ChainId))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *EIP712CosmosChainParams) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EIP712CosmosChainParams) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *EIP712CosmosChainParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Prefix) > 0 {
+		i -= len(m.Prefix)
+		copy(dAtA[i:], m.Prefix)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Prefix)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.ChainId) > 0 {
+		i -= len(m.ChainId)
+		copy(dAtA[i:], m.ChainId)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ChainId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintConfig(dAtA []byte, offset int, v uint64) int {
+	offset -= sovConfig(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *ProverConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.OriginProver != nil {
+		l = m.OriginProver.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.LcpServiceAddress)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.LcpServiceDialTimeout != 0 {
+		n += 1 + sovConfig(uint64(m.LcpServiceDialTimeout))
+	}
+	l = len(m.Mrenclave)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.AllowedQuoteStatuses) > 0 {
+		for _, s := range m.AllowedQuoteStatuses {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.AllowedAdvisoryIds) > 0 {
+		for _, s := range m.AllowedAdvisoryIds {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.KeyExpiration != 0 {
+		n += 1 + sovConfig(uint64(m.KeyExpiration))
+	}
+	l = len(m.ElcClientId)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.MessageAggregation {
+		n += 2
+	}
+	if m.MessageAggregationBatchSize != 0 {
+		n += 1 + sovConfig(uint64(m.MessageAggregationBatchSize))
+	}
+	if m.IsDebugEnclave {
+		n += 2
+	}
+	if len(m.Operators) > 0 {
+		for _, s := range m.Operators {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	l = m.OperatorsThreshold.Size()
+	n += 1 + l + sovConfig(uint64(l))
+	if m.OperatorSigner != nil {
+		l = m.OperatorSigner.Size()
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.IasCrlUrl)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.IasCrlRefreshInterval != 0 {
+		n += 2 + sovConfig(uint64(m.IasCrlRefreshInterval))
+	}
+	if m.IasCrlFailOpen {
+		n += 3
+	}
+	l = len(m.ElcClientTypeMismatchSeverity)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.AllowDebugEnclaveKeys {
+		n += 3
+	}
+	if m.BundleRegisterEnclaveKey {
+		n += 3
+	}
+	if len(m.CounterpartyMessageVersions) > 0 {
+		l = 0
+		for _, e := range m.CounterpartyMessageVersions {
+			l += sovConfig(uint64(e))
+		}
+		n += 2 + sovConfig(uint64(l)) + l
+	}
+	l = len(m.ProofArchiveDir)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.ProofArchiveMaxEntries != 0 {
+		n += 2 + sovConfig(uint64(m.ProofArchiveMaxEntries))
+	}
+	if m.ProofArchiveRetention != 0 {
+		n += 2 + sovConfig(uint64(m.ProofArchiveRetention))
+	}
+	if m.ProofArchiveFailClosed {
+		n += 3
+	}
+	l = len(m.AlertWebhookUrl)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.AlertPayloadTemplate)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.AlertDedupInterval != 0 {
+		n += 2 + sovConfig(uint64(m.AlertDedupInterval))
+	}
+	if m.AlertValidationContextMargin != 0 {
+		n += 2 + sovConfig(uint64(m.AlertValidationContextMargin))
+	}
+	if m.MinimumIsvSvn != 0 {
+		n += 2 + sovConfig(uint64(m.MinimumIsvSvn))
+	}
+	if m.OperatorsEip712Params != nil {
+		n += m.OperatorsEip712Params.Size()
+	}
+	if len(m.QuotePolicyOverrides) > 0 {
+		for _, e := range m.QuotePolicyOverrides {
+			l = e.Size()
+			n += 2 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.ProveStateTimeout != 0 {
+		n += 2 + sovConfig(uint64(m.ProveStateTimeout))
+	}
+	if m.UpdateClientTimeout != 0 {
+		n += 2 + sovConfig(uint64(m.UpdateClientTimeout))
+	}
+	if m.UpdateClientRateLimit != nil {
+		l = m.UpdateClientRateLimit.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.VerifyMembershipRateLimit != nil {
+		l = m.VerifyMembershipRateLimit.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.QueryRateLimit != nil {
+		l = m.QueryRateLimit.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.SharedRegistrationPath)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.SharedRegistrationTimeout != 0 {
+		n += 2 + sovConfig(uint64(m.SharedRegistrationTimeout))
+	}
+	l = len(m.InstanceId)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.TimestampRegressionSeverity)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.OperatorEip1271 != nil {
+		l = m.OperatorEip1271.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.DiagnosticsAddress)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if len(m.TxOptions) > 0 {
+		for _, e := range m.TxOptions {
+			l = e.Size()
+			n += 2 + l + sovConfig(uint64(l))
+		}
+	}
+	l = len(m.FinalityTracker)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.FinalityConfirmations != 0 {
+		n += 2 + sovConfig(uint64(m.FinalityConfirmations))
+	}
+	if m.MaxUpdateGap != 0 {
+		n += 2 + sovConfig(uint64(m.MaxUpdateGap))
+	}
+	if len(m.FallbackOriginProvers) > 0 {
+		for _, e := range m.FallbackOriginProvers {
+			l = e.Size()
+			n += 2 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.ConfirmationsRequired != 0 {
+		n += 2 + sovConfig(uint64(m.ConfirmationsRequired))
+	}
+	if m.AttestationPolicyCheckInterval != 0 {
+		n += 2 + sovConfig(uint64(m.AttestationPolicyCheckInterval))
+	}
+	if m.HaltOnAttestationPolicyDrift {
+		n += 3
+	}
+	if len(m.DcapRootCertPaths) > 0 {
+		for _, s := range m.DcapRootCertPaths {
+			l = len(s)
+			n += 2 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.TxRetryPolicy != nil {
+		l = m.TxRetryPolicy.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.ValidationContextPreflight {
+		n += 3
+	}
+	if m.ValidationContextSafetyMargin != 0 {
+		n += 2 + sovConfig(uint64(m.ValidationContextSafetyMargin))
+	}
+	if m.MaxConcurrentUpdates != 0 {
+		n += 2 + sovConfig(uint64(m.MaxConcurrentUpdates))
+	}
+	if m.PreferOnChainKeyExpiration {
+		n += 3
+	}
+	if len(m.RetentionPolicies) > 0 {
+		for _, e := range m.RetentionPolicies {
+			l = e.Size()
+			n += 2 + l + sovConfig(uint64(l))
+		}
+	}
+	if m.GcInterval != 0 {
+		n += 2 + sovConfig(uint64(m.GcInterval))
+	}
+	if m.SubmissionQueue {
+		n += 3
+	}
+	if m.SubmissionQueueMaxMsgsPerTx != 0 {
+		n += 2 + sovConfig(uint64(m.SubmissionQueueMaxMsgsPerTx))
+	}
+	if m.LcpServiceReconnectTimeout != 0 {
+		n += 2 + sovConfig(uint64(m.LcpServiceReconnectTimeout))
+	}
+	if m.ClockSkewCheckInterval != 0 {
+		n += 2 + sovConfig(uint64(m.ClockSkewCheckInterval))
+	}
+	if m.ClockSkewThreshold != 0 {
+		n += 2 + sovConfig(uint64(m.ClockSkewThreshold))
+	}
+	if m.ClockSkewCorrection {
+		n += 3
+	}
+	if m.LcpServiceTlsEnabled {
+		n += 3
+	}
+	l = len(m.LcpServiceTlsCaCert)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.LcpServiceClientCert)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.LcpServiceClientKey)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.LcpServiceTlsServerNameOverride)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.ExpectedFinalizationTime != 0 {
+		n += 2 + sovConfig(uint64(m.ExpectedFinalizationTime))
+	}
+	if m.UnfinalizedKeyWatchdogMultiplier != 0 {
+		n += 2 + sovConfig(uint64(m.UnfinalizedKeyWatchdogMultiplier))
+	}
+	if m.FinalizeStuckUnfinalizedKey {
+		n += 3
+	}
+	l = len(m.MetricsSink)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.MetricsAddress)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.MetricsPushgatewayUrl)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	l = len(m.MetricsPushgatewayJob)
+	if l > 0 {
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	if m.MetricsPushInterval != 0 {
+		n += 2 + sovConfig(uint64(m.MetricsPushInterval))
+	}
+	l = m.RefreshThresholdRate.Size()
+	n += 2 + l + sovConfig(uint64(l))
+	return n
+}
+
+func (m *ProverConfig_OperatorsEip712EvmChainParams) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.OperatorsEip712EvmChainParams != nil {
+		l = m.OperatorsEip712EvmChainParams.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+func (m *ProverConfig_OperatorsEip712CosmosChainParams) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.OperatorsEip712CosmosChainParams != nil {
+		l = m.OperatorsEip712CosmosChainParams.Size()
+		n += 2 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+func (m *Fraction) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Numerator != 0 {
+		n += 1 + sovConfig(uint64(m.Numerator))
+	}
+	if m.Denominator != 0 {
+		n += 1 + sovConfig(uint64(m.Denominator))
+	}
+	return n
+}
+
+func (m *RateLimit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CallsPerSecond != 0 {
+		n += 9
+	}
+	if m.Burst != 0 {
+		n += 1 + sovConfig(uint64(m.Burst))
+	}
+	return n
+}
+
+func (m *TxRetryPolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.MaxRetries != 0 {
+		n += 1 + sovConfig(uint64(m.MaxRetries))
+	}
+	if m.RetryInterval != 0 {
+		n += 1 + sovConfig(uint64(m.RetryInterval))
+	}
+	return n
+}
+
+func (m *QuotePolicyOverride) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.CounterpartyChainId)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.CounterpartyClientId)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if len(m.AllowedQuoteStatuses) > 0 {
+		for _, s := range m.AllowedQuoteStatuses {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	if len(m.AllowedAdvisoryIds) > 0 {
+		for _, s := range m.AllowedAdvisoryIds {
+			l = len(s)
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *RetentionPolicy) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Artifact)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.MaxAge != 0 {
+		n += 1 + sovConfig(uint64(m.MaxAge))
+	}
+	if m.MaxCount != 0 {
+		n += 1 + sovConfig(uint64(m.MaxCount))
+	}
+	return n
+}
+
+func (m *TxOptions) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.MsgType)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.FeeMultiplier != 0 {
+		n += 9
+	}
+	l = len(m.Priority)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.Memo)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *EIP1271OperatorConfig) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.WalletAddress)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.RpcUrl)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.PollInterval != 0 {
+		n += 1 + sovConfig(uint64(m.PollInterval))
+	}
+	if m.ApprovalTimeout != 0 {
+		n += 1 + sovConfig(uint64(m.ApprovalTimeout))
+	}
+	return n
+}
+
+func (m *EIP712EVMChainParams) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ChainId != 0 {
+		n += 1 + sovConfig(uint64(m.ChainId))
+	}
+	l = len(m.VerifyingContractAddress)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *EIP712CosmosChainParams) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ChainId)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.Prefix)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func sovConfig(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozConfig(x uint64) (n int) {
+	return sovConfig(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *ProverConfig) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ProverConfig: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ProverConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OriginProver", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.OriginProver == nil {
+				m.OriginProver = &types.Any{}
+			}
+			if err := m.OriginProver.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LcpServiceAddress", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LcpServiceAddress = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LcpServiceDialTimeout", wireType)
+			}
+			m.LcpServiceDialTimeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LcpServiceDialTimeout |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mrenclave", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mrenclave = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedQuoteStatuses", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AllowedQuoteStatuses = append(m.AllowedQuoteStatuses, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedAdvisoryIds", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AllowedAdvisoryIds = append(m.AllowedAdvisoryIds, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyExpiration", wireType)
+			}
+			m.KeyExpiration = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.KeyExpiration |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElcClientId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ElcClientId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageAggregation", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.MessageAggregation = bool(v != 0)
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageAggregationBatchSize", wireType)
+			}
+			m.MessageAggregationBatchSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MessageAggregationBatchSize |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsDebugEnclave", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsDebugEnclave = bool(v != 0)
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operators", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operators = append(m.Operators, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperatorsThreshold", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.OperatorsThreshold.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperatorSigner", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.OperatorSigner == nil {
+				m.OperatorSigner = &types.Any{}
+			}
+			if err := m.OperatorSigner.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IasCrlUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.IasCrlUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IasCrlRefreshInterval", wireType)
+			}
+			m.IasCrlRefreshInterval = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IasCrlRefreshInterval |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IasCrlFailOpen", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IasCrlFailOpen = bool(v != 0)
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElcClientTypeMismatchSeverity", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ElcClientTypeMismatchSeverity = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 19:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowDebugEnclaveKeys", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AllowDebugEnclaveKeys = bool(v != 0)
+		case 20:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BundleRegisterEnclaveKey", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.BundleRegisterEnclaveKey = bool(v != 0)
+		case 21:
+			if wireType == 0 {
+				var v uint32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.CounterpartyMessageVersions = append(m.CounterpartyMessageVersions, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowConfig
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthConfig
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthConfig
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.CounterpartyMessageVersions) == 0 {
+					m.CounterpartyMessageVersions = make([]uint32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowConfig
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.CounterpartyMessageVersions = append(m.CounterpartyMessageVersions, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field CounterpartyMessageVersions", wireType)
+			}
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProofArchiveDir", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ProofArchiveDir = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 23:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProofArchiveMaxEntries", wireType)
+			}
+			m.ProofArchiveMaxEntries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProofArchiveMaxEntries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 24:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProofArchiveRetention", wireType)
+			}
+			m.ProofArchiveRetention = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProofArchiveRetention |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 25:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProofArchiveFailClosed", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ProofArchiveFailClosed = bool(v != 0)
+		case 26:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AlertWebhookUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AlertWebhookUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 27:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AlertPayloadTemplate", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AlertPayloadTemplate = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 28:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AlertDedupInterval", wireType)
+			}
+			m.AlertDedupInterval = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AlertDedupInterval |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 29:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AlertValidationContextMargin", wireType)
+			}
+			m.AlertValidationContextMargin = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AlertValidationContextMargin |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 30:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinimumIsvSvn", wireType)
+			}
+			m.MinimumIsvSvn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinimumIsvSvn |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 31:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperatorsEip712EvmChainParams", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &EIP712EVMChainParams{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.OperatorsEip712Params = &ProverConfig_OperatorsEip712EvmChainParams{v}
+			iNdEx = postIndex
+		case 32:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperatorsEip712CosmosChainParams", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &EIP712CosmosChainParams{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.OperatorsEip712Params = &ProverConfig_OperatorsEip712CosmosChainParams{v}
+			iNdEx = postIndex
+		case 33:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuotePolicyOverrides", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QuotePolicyOverrides = append(m.QuotePolicyOverrides, QuotePolicyOverride{})
+			if err := m.QuotePolicyOverrides[len(m.QuotePolicyOverrides)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 34:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProveStateTimeout", wireType)
+			}
+			m.ProveStateTimeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProveStateTimeout |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 35:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdateClientTimeout", wireType)
+			}
+			m.UpdateClientTimeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UpdateClientTimeout |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 36:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdateClientRateLimit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.UpdateClientRateLimit == nil {
+				m.UpdateClientRateLimit = &RateLimit{}
+			}
+			if err := m.UpdateClientRateLimit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 37:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VerifyMembershipRateLimit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.VerifyMembershipRateLimit == nil {
+				m.VerifyMembershipRateLimit = &RateLimit{}
+			}
+			if err := m.VerifyMembershipRateLimit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 38:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryRateLimit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.QueryRateLimit == nil {
+				m.QueryRateLimit = &RateLimit{}
+			}
+			if err := m.QueryRateLimit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 39:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SharedRegistrationPath", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SharedRegistrationPath = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 40:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SharedRegistrationTimeout", wireType)
+			}
+			m.SharedRegistrationTimeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SharedRegistrationTimeout |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 41:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InstanceId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.InstanceId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 42:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampRegressionSeverity", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TimestampRegressionSeverity = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 43:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperatorEip1271", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.OperatorEip1271 == nil {
+				m.OperatorEip1271 = &EIP1271OperatorConfig{}
+			}
+			if err := m.OperatorEip1271.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 44:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DiagnosticsAddress", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DiagnosticsAddress = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 45:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxOptions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TxOptions = append(m.TxOptions, TxOptions{})
+			if err := m.TxOptions[len(m.TxOptions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 46:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FinalityTracker", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FinalityTracker = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 47:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FinalityConfirmations", wireType)
+			}
+			m.FinalityConfirmations = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FinalityConfirmations |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 48:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxUpdateGap", wireType)
+			}
+			m.MaxUpdateGap = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxUpdateGap |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 49:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FallbackOriginProvers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FallbackOriginProvers = append(m.FallbackOriginProvers, &types.Any{})
+			if err := m.FallbackOriginProvers[len(m.FallbackOriginProvers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 50:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConfirmationsRequired", wireType)
+			}
+			m.ConfirmationsRequired = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ConfirmationsRequired |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 51:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AttestationPolicyCheckInterval", wireType)
+			}
+			m.AttestationPolicyCheckInterval = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AttestationPolicyCheckInterval |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 52:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HaltOnAttestationPolicyDrift", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.HaltOnAttestationPolicyDrift = bool(v != 0)
+		case 53:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DcapRootCertPaths", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DcapRootCertPaths = append(m.DcapRootCertPaths, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 54:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TxRetryPolicy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TxRetryPolicy == nil {
+				m.TxRetryPolicy = &TxRetryPolicy{}
+			}
+			if err := m.TxRetryPolicy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 55:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidationContextPreflight", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ValidationContextPreflight = bool(v != 0)
+		case 56:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidationContextSafetyMargin", wireType)
+			}
+			m.ValidationContextSafetyMargin = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ValidationContextSafetyMargin |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 57:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxConcurrentUpdates", wireType)
+			}
+			m.MaxConcurrentUpdates = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxConcurrentUpdates |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 58:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreferOnChainKeyExpiration", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.PreferOnChainKeyExpiration = bool(v != 0)
+		case 59:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetentionPolicies", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RetentionPolicies = append(m.RetentionPolicies, RetentionPolicy{})
+			if err := m.RetentionPolicies[len(m.RetentionPolicies)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 60:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GcInterval", wireType)
+			}
+			m.GcInterval = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GcInterval |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 61:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SubmissionQueue", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SubmissionQueue = bool(v != 0)
+		case 62:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SubmissionQueueMaxMsgsPerTx", wireType)
+			}
+			m.SubmissionQueueMaxMsgsPerTx = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SubmissionQueueMaxMsgsPerTx |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 63:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LcpServiceReconnectTimeout", wireType)
+			}
+			m.LcpServiceReconnectTimeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LcpServiceReconnectTimeout |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 64:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClockSkewCheckInterval", wireType)
+			}
+			m.ClockSkewCheckInterval = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ClockSkewCheckInterval |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 65:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClockSkewThreshold", wireType)
+			}
+			m.ClockSkewThreshold = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ClockSkewThreshold |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 66:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClockSkewCorrection", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ClockSkewCorrection = bool(v != 0)
+		case 67:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LcpServiceTlsEnabled", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.LcpServiceTlsEnabled = bool(v != 0)
+		case 68:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LcpServiceTlsCaCert", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LcpServiceTlsCaCert = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 69:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LcpServiceClientCert", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LcpServiceClientCert = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 70:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LcpServiceClientKey", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LcpServiceClientKey = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 71:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LcpServiceTlsServerNameOverride", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LcpServiceTlsServerNameOverride = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 72:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpectedFinalizationTime", wireType)
+			}
+			m.ExpectedFinalizationTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpectedFinalizationTime |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 73:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UnfinalizedKeyWatchdogMultiplier", wireType)
+			}
+			m.UnfinalizedKeyWatchdogMultiplier = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UnfinalizedKeyWatchdogMultiplier |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 74:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FinalizeStuckUnfinalizedKey", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.FinalizeStuckUnfinalizedKey = bool(v != 0)
+		case 75:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricsSink", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricsSink = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 76:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricsAddress", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricsAddress = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 77:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricsPushgatewayUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricsPushgatewayUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 78:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricsPushgatewayJob", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricsPushgatewayJob = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 79:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricsPushInterval", wireType)
+			}
+			m.MetricsPushInterval = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MetricsPushInterval |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 80:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RefreshThresholdRate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.RefreshThresholdRate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Fraction) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Fraction: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Fraction: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Numerator", wireType)
+			}
+			m.Numerator = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Numerator |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Denominator", wireType)
+			}
+			m.Denominator = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Denominator |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	return n
-}
 
-func (m *EIP712CosmosChainParams) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.ChainId)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
-	}
-	l = len(m.Prefix)
-	if l > 0 {
-		n += 1 + l + sovConfig(uint64(l))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-
-func sovConfig(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozConfig(x uint64) (n int) {
-	return sovConfig(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *ProverConfig) Unmarshal(dAtA []byte) error {
+func (m *RateLimit) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -791,17 +4951,28 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ProverConfig: wiretype end group for non-group")
+			return fmt.Errorf("proto: RateLimit: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ProverConfig: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RateLimit: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OriginProver", wireType)
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CallsPerSecond", wireType)
 			}
-			var msglen int
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.CallsPerSecond = float64(math.Float64frombits(v))
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Burst", wireType)
+			}
+			m.Burst = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -811,33 +4982,66 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				m.Burst |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthConfig
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthConfig
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.OriginProver == nil {
-				m.OriginProver = &types.Any{}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TxRetryPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
 			}
-			if err := m.OriginProver.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
 			}
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LcpServiceAddress", wireType)
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			var stringLen uint64
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TxRetryPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TxRetryPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRetries", wireType)
+			}
+			m.MaxRetries = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -847,29 +5051,16 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.MaxRetries |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthConfig
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthConfig
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.LcpServiceAddress = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LcpServiceDialTimeout", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RetryInterval", wireType)
 			}
-			m.LcpServiceDialTimeout = 0
+			m.RetryInterval = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -879,14 +5070,64 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LcpServiceDialTimeout |= uint64(b&0x7F) << shift
+				m.RetryInterval |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 4:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QuotePolicyOverride) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QuotePolicyOverride: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QuotePolicyOverride: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Mrenclave", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CounterpartyChainId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -914,11 +5155,11 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Mrenclave = string(dAtA[iNdEx:postIndex])
+			m.CounterpartyChainId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AllowedQuoteStatuses", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CounterpartyClientId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -946,11 +5187,11 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AllowedQuoteStatuses = append(m.AllowedQuoteStatuses, string(dAtA[iNdEx:postIndex]))
+			m.CounterpartyClientId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AllowedAdvisoryIds", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedQuoteStatuses", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -978,13 +5219,13 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AllowedAdvisoryIds = append(m.AllowedAdvisoryIds, string(dAtA[iNdEx:postIndex]))
+			m.AllowedQuoteStatuses = append(m.AllowedQuoteStatuses, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field KeyExpiration", wireType)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AllowedAdvisoryIds", wireType)
 			}
-			m.KeyExpiration = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -994,14 +5235,77 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.KeyExpiration |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AllowedAdvisoryIds = append(m.AllowedAdvisoryIds, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RetentionPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RetentionPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RetentionPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ElcClientId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Artifact", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1029,33 +5333,13 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.ElcClientId = string(dAtA[iNdEx:postIndex])
+			m.Artifact = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 9:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MessageAggregation", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowConfig
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			m.MessageAggregation = bool(v != 0)
-		case 10:
+		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MessageAggregationBatchSize", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxAge", wireType)
 			}
-			m.MessageAggregationBatchSize = 0
+			m.MaxAge = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -1065,36 +5349,16 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.MessageAggregationBatchSize |= uint64(b&0x7F) << shift
+				m.MaxAge |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 11:
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field IsDebugEnclave", wireType)
-			}
-			var v int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowConfig
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				v |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxCount", wireType)
 			}
-			m.IsDebugEnclave = bool(v != 0)
-		case 12:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Operators", wireType)
-			}
-			var stringLen uint64
+			m.MaxCount = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -1104,62 +5368,66 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.MaxCount |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthConfig
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthConfig
 			}
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Operators = append(m.Operators, string(dAtA[iNdEx:postIndex]))
-			iNdEx = postIndex
-		case 13:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OperatorsThreshold", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowConfig
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthConfig
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthConfig
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TxOptions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
 			}
-			if postIndex > l {
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := m.OperatorsThreshold.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
 			}
-			iNdEx = postIndex
-		case 14:
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TxOptions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TxOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OperatorSigner", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MsgType", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -1169,33 +5437,40 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthConfig
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthConfig
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.OperatorSigner == nil {
-				m.OperatorSigner = &types.Any{}
+			m.MsgType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FeeMultiplier", wireType)
 			}
-			if err := m.OperatorSigner.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
 			}
-			iNdEx = postIndex
-		case 31:
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.FeeMultiplier = float64(math.Float64frombits(v))
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OperatorsEip712EvmChainParams", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Priority", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -1205,32 +5480,29 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthConfig
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthConfig
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &EIP712EVMChainParams{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			m.OperatorsEip712Params = &ProverConfig_OperatorsEip712EvmChainParams{v}
+			m.Priority = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 32:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OperatorsEip712CosmosChainParams", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Memo", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -1240,26 +5512,23 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthConfig
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthConfig
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &EIP712CosmosChainParams{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			m.OperatorsEip712Params = &ProverConfig_OperatorsEip712CosmosChainParams{v}
+			m.Memo = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1282,7 +5551,7 @@ func (m *ProverConfig) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Fraction) Unmarshal(dAtA []byte) error {
+func (m *EIP1271OperatorConfig) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1305,17 +5574,17 @@ func (m *Fraction) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Fraction: wiretype end group for non-group")
+			return fmt.Errorf("proto: EIP1271OperatorConfig: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Fraction: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: EIP1271OperatorConfig: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Numerator", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WalletAddress", wireType)
 			}
-			m.Numerator = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -1325,16 +5594,61 @@ func (m *Fraction) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Numerator |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.WalletAddress = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RpcUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RpcUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Denominator", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PollInterval", wireType)
 			}
-			m.Denominator = 0
+			m.PollInterval = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowConfig
@@ -1344,7 +5658,26 @@ func (m *Fraction) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Denominator |= uint64(b&0x7F) << shift
+				m.PollInterval |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ApprovalTimeout", wireType)
+			}
+			m.ApprovalTimeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ApprovalTimeout |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -0,0 +1,173 @@
+package relay
+
+import (
+	"fmt"
+	"testing"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAddresses(t *testing.T) {
+	const (
+		serviceAddress = "localhost:50051"
+		operator       = "0xcb96F8d6C2d543102184d679D7829b39434E4EEc"
+	)
+	evmParams := func(addr string) isProverConfig_OperatorsEip712Params {
+		return &ProverConfig_OperatorsEip712EvmChainParams{
+			OperatorsEip712EvmChainParams: &EIP712EVMChainParams{ChainId: 1, VerifyingContractAddress: addr},
+		}
+	}
+	var cases = []struct {
+		name   string
+		config ProverConfig
+		// expected substring of the error. if empty, the config is valid
+		err string
+	}{
+		{"valid", ProverConfig{LcpServiceAddress: serviceAddress, Operators: []string{operator}, OperatorsEip712Params: evmParams(operator)}, ""},
+		{"lower case operator", ProverConfig{LcpServiceAddress: serviceAddress, Operators: []string{"0xcb96f8d6c2d543102184d679d7829b39434e4eec"}}, ""},
+		{"operator without prefix", ProverConfig{LcpServiceAddress: serviceAddress, Operators: []string{"cb96F8d6C2d543102184d679D7829b39434E4EEc"}}, ""},
+		{"dns scheme", ProverConfig{LcpServiceAddress: "dns:///lcp.example.com:50051"}, ""},
+		{"unix socket", ProverConfig{LcpServiceAddress: "unix:///var/run/lcp.sock"}, ""},
+		{"ipv6", ProverConfig{LcpServiceAddress: "[::1]:50051"}, ""},
+
+		{"empty service address", ProverConfig{}, `LcpServiceAddress is invalid: value=""`},
+		{"service address without port", ProverConfig{LcpServiceAddress: "localhost"}, `LcpServiceAddress is invalid: value="localhost"`},
+		{"service address with invalid port", ProverConfig{LcpServiceAddress: "localhost:70000"}, "invalid port"},
+		{"service address with spaces", ProverConfig{LcpServiceAddress: " localhost:50051"}, "spaces"},
+		{"service address with unsupported scheme", ProverConfig{LcpServiceAddress: "http://localhost:50051"}, "unsupported scheme"},
+		{"short operator", ProverConfig{LcpServiceAddress: serviceAddress, Operators: []string{"0xcb96F8d6"}}, `Operators[0] is invalid: value="0xcb96F8d6"`},
+		{"non-hex operator", ProverConfig{LcpServiceAddress: serviceAddress, Operators: []string{"0xzb96F8d6C2d543102184d679D7829b39434E4EEc"}}, "invalid hex string"},
+		{"bad checksum operator", ProverConfig{LcpServiceAddress: serviceAddress, Operators: []string{"0xCb96F8d6C2d543102184d679D7829b39434E4EEc"}}, "invalid checksum"},
+		{"zero operator", ProverConfig{LcpServiceAddress: serviceAddress, Operators: []string{"0x0000000000000000000000000000000000000000"}}, "Operators[0] must not be the zero address"},
+		{"malformed verifying contract", ProverConfig{LcpServiceAddress: serviceAddress, OperatorsEip712Params: evmParams("0x1234")}, `VerifyingContractAddress is invalid: value="0x1234"`},
+		{"zero verifying contract", ProverConfig{LcpServiceAddress: serviceAddress, OperatorsEip712Params: evmParams("0x0000000000000000000000000000000000000000")}, "VerifyingContractAddress must not be the zero address"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.validateAddresses()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestValidateQuotePolicies(t *testing.T) {
+	var tooManyAdvisoryIDs []string
+	for i := 0; i <= lcptypes.MaxAllowedAdvisoryIDs; i++ {
+		tooManyAdvisoryIDs = append(tooManyAdvisoryIDs, fmt.Sprintf("INTEL-SA-%05d", i))
+	}
+	var cases = []struct {
+		name   string
+		config ProverConfig
+		// expected substring of the error. if empty, the config is valid
+		err string
+	}{
+		{"empty", ProverConfig{}, ""},
+		{"valid", ProverConfig{
+			AllowedQuoteStatuses: []string{"GROUP_OUT_OF_DATE"},
+			AllowedAdvisoryIds:   []string{"INTEL-SA-00219"},
+			QuotePolicyOverrides: []QuotePolicyOverride{
+				{CounterpartyChainId: "ibc-0", AllowedQuoteStatuses: []string{"SW_HARDENING_NEEDED"}},
+				{CounterpartyChainId: "ibc-0", CounterpartyClientId: "lcp-client-0"},
+			},
+		}, ""},
+		{"unknown global status", ProverConfig{AllowedQuoteStatuses: []string{"OUT_OF_DATE"}}, `AllowedQuoteStatuses[0] is invalid: value="OUT_OF_DATE"`},
+		{"empty global advisory ID", ProverConfig{AllowedAdvisoryIds: []string{""}}, "AllowedAdvisoryIds[0] must be non-empty"},
+		{"override without chain ID", ProverConfig{QuotePolicyOverrides: []QuotePolicyOverride{{CounterpartyClientId: "lcp-client-0"}}}, "QuotePolicyOverrides[0]: CounterpartyChainId must be set"},
+		{"duplicate override", ProverConfig{QuotePolicyOverrides: []QuotePolicyOverride{
+			{CounterpartyChainId: "ibc-0"},
+			{CounterpartyChainId: "ibc-0"},
+		}}, "QuotePolicyOverrides[1]: duplicate override"},
+		{"unknown override status", ProverConfig{QuotePolicyOverrides: []QuotePolicyOverride{
+			{CounterpartyChainId: "ibc-0", AllowedQuoteStatuses: []string{"sw_hardening"}},
+		}}, `QuotePolicyOverrides[0]: AllowedQuoteStatuses[0] is invalid`},
+		// the values are normalized in the same way as the LCP client
+		{"mixed-case and padded values", ProverConfig{
+			AllowedQuoteStatuses: []string{" group_out_of_date"},
+			AllowedAdvisoryIds:   []string{"intel-sa-00219 "},
+			QuotePolicyOverrides: []QuotePolicyOverride{
+				{CounterpartyChainId: "ibc-0", AllowedQuoteStatuses: []string{"Sw_Hardening_Needed "}, AllowedAdvisoryIds: []string{" INTEL-SA-00334"}},
+			},
+		}, ""},
+		{"blank override advisory ID", ProverConfig{QuotePolicyOverrides: []QuotePolicyOverride{
+			{CounterpartyChainId: "ibc-0", AllowedAdvisoryIds: []string{"  "}},
+		}}, "QuotePolicyOverrides[0]: AllowedAdvisoryIds[0] must be non-empty"},
+		{"too many advisory IDs", ProverConfig{AllowedAdvisoryIds: tooManyAdvisoryIDs}, "too many allowed advisory IDs"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.validateQuotePolicies()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestValidateOperatorsThreshold(t *testing.T) {
+	const operator = "0xcb96F8d6C2d543102184d679D7829b39434E4EEc"
+	var cases = []struct {
+		name      string
+		operators []string
+		threshold Fraction
+		// expected substring of the error. if empty, the config is valid
+		err string
+		// the threshold of the initial client state
+		effective Fraction
+	}{
+		{"permissionless", nil, Fraction{}, "", Fraction{}},
+		{"permissionless with 1/1", nil, Fraction{Numerator: 1, Denominator: 1}, "must not be set if Operators is empty", Fraction{}},
+		{"permissionless with 0/1", nil, Fraction{Denominator: 1}, "must not be set if Operators is empty", Fraction{}},
+		{"operators with the default", []string{operator}, Fraction{}, "", Fraction{Numerator: 1, Denominator: 1}},
+		{"operators with 1/2", []string{operator}, Fraction{Numerator: 1, Denominator: 2}, "", Fraction{Numerator: 1, Denominator: 2}},
+		{"operators with 0/1", []string{operator}, Fraction{Denominator: 1}, "must satisfy 0 < numerator <= denominator", Fraction{}},
+		{"operators with 1/0", []string{operator}, Fraction{Numerator: 1}, "must satisfy 0 < numerator <= denominator", Fraction{}},
+		{"operators with 2/1", []string{operator}, Fraction{Numerator: 2, Denominator: 1}, "must satisfy 0 < numerator <= denominator", Fraction{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			config := ProverConfig{Operators: c.operators, OperatorsThreshold: c.threshold}
+			err := config.validateOperatorsThreshold()
+			if c.err != "" {
+				require.ErrorContains(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			pr := &Prover{config: config}
+			require.Equal(t, c.effective, pr.GetOperatorsThreshold())
+		})
+	}
+}
+
+func TestValidateRefreshThresholdRate(t *testing.T) {
+	var cases = []struct {
+		rate      Fraction
+		valid     bool
+		effective Fraction
+	}{
+		{Fraction{}, true, Fraction{Numerator: 1, Denominator: 2}},
+		{Fraction{Numerator: 2, Denominator: 3}, true, Fraction{Numerator: 2, Denominator: 3}},
+		{Fraction{Numerator: 0, Denominator: 2}, false, Fraction{}},
+		{Fraction{Numerator: 1, Denominator: 0}, false, Fraction{}},
+		{Fraction{Numerator: 1, Denominator: 1}, false, Fraction{}},
+		{Fraction{Numerator: 3, Denominator: 2}, false, Fraction{}},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%v/%v", c.rate.Numerator, c.rate.Denominator), func(t *testing.T) {
+			config := ProverConfig{RefreshThresholdRate: c.rate}
+			err := config.validateRefreshThresholdRate()
+			if !c.valid {
+				require.ErrorContains(t, err, "RefreshThresholdRate must satisfy")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.effective, config.GetRefreshThresholdRate())
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package relay
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+)
+
+const (
+	DefaultIASCRLRefreshInterval = 24 * 60 * 60 // seconds
+	crlFetchTimeout              = 30 * time.Second
+)
+
+// crlCache caches the CRL of the report signing certificates and refreshes it periodically
+type crlCache struct {
+	mu              sync.Mutex
+	url             string
+	refreshInterval time.Duration
+	crl             *x509.RevocationList
+	fetchedAt       time.Time
+
+	// fetch and issuer are overridable for testing
+	fetch  func(url string) ([]byte, error)
+	issuer func() *x509.Certificate
+}
+
+func newCRLCache(url string, refreshInterval time.Duration) *crlCache {
+	return &crlCache{
+		url:             url,
+		refreshInterval: refreshInterval,
+		fetch:           fetchCRL,
+		issuer:          ias.GetRARootCert,
+	}
+}
+
+// get returns the cached CRL if it is fresh, otherwise it fetches the CRL from the URL.
+// If the refresh fails, the previously cached CRL is returned as long as it is not expired.
+func (c *crlCache) get(now time.Time) (*x509.RevocationList, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.crl != nil && now.Sub(c.fetchedAt) < c.refreshInterval {
+		return c.crl, nil
+	}
+	bz, err := c.fetch(c.url)
+	if err == nil {
+		var crl *x509.RevocationList
+		if crl, err = ias.ParseCRL(bz, c.issuer(), now); err == nil {
+			c.crl, c.fetchedAt = crl, now
+			return crl, nil
+		}
+	}
+	if c.crl != nil && (c.crl.NextUpdate.IsZero() || now.Before(c.crl.NextUpdate)) {
+		return c.crl, nil
+	}
+	return nil, fmt.Errorf("failed to get CRL: url=%v %w", c.url, err)
+}
+
+func fetchCRL(url string) ([]byte, error) {
+	client := http.Client{Timeout: crlFetchTimeout}
+	res, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %v", res.StatusCode)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// checkSigningCertRevocation checks if the report signing certificate of the enclave key is revoked.
+// If the CRL cannot be obtained, it returns an error unless `IasCrlFailOpen` is true.
+func (pr *Prover) checkSigningCertRevocation(eki *enclave.EnclaveKeyInfo, now time.Time) error {
+	if pr.crlCache == nil {
+		return nil
+	}
+	crl, err := pr.crlCache.get(now)
+	if err != nil {
+		if pr.config.IasCrlFailOpen {
+			pr.getLogger().Warn("skip the revocation check because the CRL is not available", "url", pr.config.IasCrlUrl, "error", err)
+			return nil
+		}
+		return err
+	}
+	return ias.CheckRevocation(eki.SigningCert, crl)
+}
@@ -1,6 +1,7 @@
 package relay
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,13 +9,26 @@ import (
 	"os"
 	"path/filepath"
 
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
 	"github.com/datachainlab/lcp-go/relay/enclave"
 	"github.com/hyperledger-labs/yui-relayer/core"
 )
 
+const (
+	elcOriginClientTypesFile      = "elc_origin_client_types"
+	counterpartyClientHeightsFile = "counterparty_client_heights"
+	bootstrapCheckpointFile       = "bootstrap_checkpoint"
+	activateClientCheckpointFile  = "activate_client_checkpoint"
+)
+
+// Deprecated: the enclave key infos and the ELC client ID were stored in these files before EKStore was introduced.
+// They are migrated into ekStoreFile and removed when the store is loaded for the first time.
 const (
 	lastFinalizedEnclaveKeyInfoFile   = "last_finalized_eki"
+	unfinalizedEnclaveKeyInfosFile    = "unfinalized_ekis"
 	lastUnfinalizedEnclaveKeyInfoFile = "last_unfinalized_eki"
+	elcClientIDFile                   = "elc_client_id"
 )
 
 var ErrEnclaveKeyInfoNotFound = errors.New("enclave key info not found")
@@ -22,123 +36,381 @@ var ErrEnclaveKeyInfoNotFound = errors.New("enclave key info not found")
 type unfinalizedEKI struct {
 	Info       *enclave.EnclaveKeyInfo `json:"info"`
 	MsgIDBytes []byte                  `json:"msg_id_bytes"`
+	// the height of the block including the msg. It is omitted if unknown.
+	IncludedHeight *clienttypes.Height `json:"included_height,omitempty"`
+	// the version of lcp-go which submitted the registration. It is omitted in the records saved by older versions.
+	RelayerVersion string `json:"relayer_version,omitempty"`
+	// true if the record was saved in the debug enclave mode. It is omitted in the records saved by older versions.
+	Debug *bool `json:"debug,omitempty"`
+}
+
+// finalizedEKI is the finalized enclave key info with the enclave mode in which it was saved.
+// The older versions saved the enclave key info as it is.
+type finalizedEKI struct {
+	Info  *enclave.EnclaveKeyInfo `json:"info"`
+	Debug *bool                   `json:"debug,omitempty"`
+}
+
+// unfinalizedEnclaveKey is a registration of the enclave key that is not finalized yet.
+// The same key may have multiple records with different msg IDs if the registration has been submitted more than once.
+type unfinalizedEnclaveKey struct {
+	eki            *enclave.EnclaveKeyInfo
+	msgID          core.MsgID
+	includedHeight clienttypes.Height
+	relayerVersion string
+	debug          *bool
+}
+
+// matches returns true if the record is the registration of `eki` by the msg `msgID`
+func (r unfinalizedEnclaveKey) matches(eki *enclave.EnclaveKeyInfo, msgID core.MsgID) bool {
+	return bytes.Equal(r.eki.EnclaveKeyAddress, eki.EnclaveKeyAddress) && r.msgID.String() == msgID.String()
 }
 
 func (pr *Prover) dbPath() string {
 	return filepath.Join(pr.homePath, "lcp", pr.originChain.ChainID())
 }
 
-func (pr *Prover) lastEnclaveKeyInfoFilePath(finalized bool) string {
-	if finalized {
-		return filepath.Join(pr.dbPath(), lastFinalizedEnclaveKeyInfoFile)
-	} else {
-		return filepath.Join(pr.dbPath(), lastUnfinalizedEnclaveKeyInfoFile)
+func (pr *Prover) loadLastFinalizedEnclaveKey(context.Context) (*enclave.EnclaveKeyInfo, error) {
+	feki, err := pr.ekStore().GetFinalized()
+	if err != nil {
+		return nil, err
+	} else if feki == nil {
+		return nil, fmt.Errorf("finalized enclave key info not found: %w", ErrEnclaveKeyInfoNotFound)
+	}
+	if !pr.matchesEnclaveMode(feki.Debug) {
+		pr.getLogger().Warn("skip the finalized enclave key info saved in another enclave mode", "enclave_key", lcptypes.HexBytes(feki.Info.EnclaveKeyAddress), "mode", pr.enclaveMode())
+		return nil, fmt.Errorf("the finalized enclave key info was saved in another enclave mode: %w", ErrEnclaveKeyInfoNotFound)
 	}
+	return feki.Info, nil
 }
 
-func (pr *Prover) loadLastFinalizedEnclaveKey(context.Context) (*enclave.EnclaveKeyInfo, error) {
-	path := pr.lastEnclaveKeyInfoFilePath(true)
-	bz, err := os.ReadFile(path)
+// loadLastUnfinalizedEnclaveKey returns the most recently saved unfinalized enclave key info, the msg ID of the registration and the height of the block including the msg.
+// The height is zero if it is not recorded.
+func (pr *Prover) loadLastUnfinalizedEnclaveKey(ctx context.Context) (*enclave.EnclaveKeyInfo, core.MsgID, clienttypes.Height, error) {
+	records, err := pr.loadUnfinalizedEnclaveKeys(ctx)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("%v not found: %w", path, ErrEnclaveKeyInfoNotFound)
+		return nil, nil, clienttypes.Height{}, err
+	} else if len(records) == 0 {
+		return nil, nil, clienttypes.Height{}, fmt.Errorf("unfinalized enclave key info not found: %w", ErrEnclaveKeyInfoNotFound)
+	}
+	last := records[len(records)-1]
+	return last.eki, last.msgID, last.includedHeight, nil
+}
+
+// loadUnfinalizedEnclaveKeys returns the outstanding registrations in the order they were first saved
+func (pr *Prover) loadUnfinalizedEnclaveKeys(context.Context) ([]unfinalizedEnclaveKey, error) {
+	uekis, err := pr.ekStore().GetUnfinalized()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]unfinalizedEnclaveKey, 0, len(uekis))
+	for _, ueki := range uekis {
+		var msgID core.MsgID
+		if err := pr.codec.UnmarshalInterface(ueki.MsgIDBytes, &msgID); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal msg id: value=%x %w", ueki.MsgIDBytes, err)
+		}
+		var includedHeight clienttypes.Height
+		if ueki.IncludedHeight != nil {
+			includedHeight = *ueki.IncludedHeight
+		}
+		if !pr.matchesEnclaveMode(ueki.Debug) {
+			pr.getLogger().Warn("skip the unfinalized enclave key info saved in another enclave mode", "enclave_key", lcptypes.HexBytes(ueki.Info.EnclaveKeyAddress), "msg_id", msgID.String(), "mode", pr.enclaveMode())
+			continue
+		}
+		records = append(records, unfinalizedEnclaveKey{eki: ueki.Info, msgID: msgID, includedHeight: includedHeight, relayerVersion: ueki.RelayerVersion, debug: ueki.Debug})
+	}
+	return records, nil
+}
+
+// saveUnfinalizedEnclaveKeys replaces the outstanding registrations with `records`
+func (pr *Prover) saveUnfinalizedEnclaveKeys(_ context.Context, records []unfinalizedEnclaveKey) error {
+	if len(records) == 0 {
+		return pr.ekStore().DeleteUnfinalized()
+	}
+	uekis := make([]unfinalizedEKI, 0, len(records))
+	for _, r := range records {
+		msgIDBytes, err := pr.codec.MarshalInterface(r.msgID)
+		if err != nil {
+			return fmt.Errorf("failed to marshal msg id: %w", err)
+		}
+		ueki := unfinalizedEKI{
+			Info:           r.eki,
+			MsgIDBytes:     msgIDBytes,
+			RelayerVersion: r.relayerVersion,
+			Debug:          r.debug,
+		}
+		if !r.includedHeight.IsZero() {
+			includedHeight := r.includedHeight
+			ueki.IncludedHeight = &includedHeight
+		}
+		uekis = append(uekis, ueki)
+	}
+	return pr.ekStore().SetUnfinalized(uekis)
+}
+
+func (pr *Prover) saveFinalizedEnclaveKeyInfo(_ context.Context, eki *enclave.EnclaveKeyInfo) error {
+	pr.getLogger().Info("save finalized enclave key info")
+	debug := pr.config.IsDebugEnclave
+	return pr.ekStore().SetFinalized(&finalizedEKI{Info: eki, Debug: &debug})
+}
+
+// finalizeEnclaveKeyInfo saves the enclave key info as finalized and removes all records of the unfinalized registrations,
+// which are superseded by it, in a single write
+func (pr *Prover) finalizeEnclaveKeyInfo(_ context.Context, eki *enclave.EnclaveKeyInfo) error {
+	pr.getLogger().Info("save finalized enclave key info and remove unfinalized enclave key infos", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress))
+	debug := pr.config.IsDebugEnclave
+	return pr.ekStore().Finalize(&finalizedEKI{Info: eki, Debug: &debug})
+}
+
+// saveUnfinalizedEnclaveKeyInfo saves the enclave key info with the msg ID of the registration.
+// The record is keyed by the enclave key address and the msg ID, so the record of another submission of the same key is kept.
+// `includedHeight` is the height of the block including the msg, and it is not recorded if zero.
+func (pr *Prover) saveUnfinalizedEnclaveKeyInfo(ctx context.Context, eki *enclave.EnclaveKeyInfo, msgID core.MsgID, includedHeight clienttypes.Height) error {
+	pr.getLogger().Info("save unfinalized enclave key info", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "msg_id", msgID.String(), "included_height", includedHeight)
+	records, err := pr.loadUnfinalizedEnclaveKeys(ctx)
+	if err != nil {
+		return err
+	}
+	debug := pr.config.IsDebugEnclave
+	record := unfinalizedEnclaveKey{eki: eki, msgID: msgID, includedHeight: includedHeight, relayerVersion: GetBuildInfo().String(), debug: &debug}
+	found := false
+	for i, r := range records {
+		if r.matches(eki, msgID) {
+			records[i], found = record, true
+		}
+	}
+	if !found {
+		records = append(records, record)
+	}
+	return pr.saveUnfinalizedEnclaveKeys(ctx, records)
+}
+
+func (pr *Prover) removeFinalizedEnclaveKeyInfo(context.Context) error {
+	pr.getLogger().Info("remove finalized enclave key info")
+	return pr.ekStore().SetFinalized(nil)
+}
+
+// removeUnfinalizedEnclaveKeyInfo removes the record of the registration of `eki` by the msg `msgID`
+// and returns the number of the remaining records
+func (pr *Prover) removeUnfinalizedEnclaveKeyInfo(ctx context.Context, eki *enclave.EnclaveKeyInfo, msgID core.MsgID) (int, error) {
+	pr.getLogger().Info("remove unfinalized enclave key info", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "msg_id", msgID.String())
+	records, err := pr.loadUnfinalizedEnclaveKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+	remaining := make([]unfinalizedEnclaveKey, 0, len(records))
+	for _, r := range records {
+		if !r.matches(eki, msgID) {
+			remaining = append(remaining, r)
 		}
-		return nil, fmt.Errorf("failed to stat file: path=%v %w", path, err)
 	}
-	var eki enclave.EnclaveKeyInfo
-	if err := json.Unmarshal(bz, &eki); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal enclave key info: path=%v %w", path, err)
+	if err := pr.saveUnfinalizedEnclaveKeys(ctx, remaining); err != nil {
+		return 0, err
 	}
-	return &eki, nil
+	return len(remaining), nil
 }
 
-func (pr *Prover) loadLastUnfinalizedEnclaveKey(context.Context) (*enclave.EnclaveKeyInfo, core.MsgID, error) {
-	path := pr.lastEnclaveKeyInfoFilePath(false)
+// removeUnfinalizedEnclaveKeyInfos removes all records of the unfinalized registrations
+func (pr *Prover) removeUnfinalizedEnclaveKeyInfos(ctx context.Context) error {
+	pr.getLogger().Info("remove unfinalized enclave key infos")
+	return pr.saveUnfinalizedEnclaveKeys(ctx, nil)
+}
+
+func (pr *Prover) removeEnclaveKeyInfos(ctx context.Context) error {
+	if err := pr.removeFinalizedEnclaveKeyInfo(ctx); err != nil {
+		return err
+	}
+	if err := pr.removeUnfinalizedEnclaveKeyInfos(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// loadELCOriginClientTypes returns the recorded type URLs of the origin client state for each ELC client
+func (pr *Prover) loadELCOriginClientTypes(context.Context) (map[string]string, error) {
+	path := filepath.Join(pr.dbPath(), elcOriginClientTypesFile)
 	bz, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, nil, fmt.Errorf("%v not found: %w", path, ErrEnclaveKeyInfoNotFound)
+			return map[string]string{}, nil
 		}
-		return nil, nil, fmt.Errorf("failed to stat file: path=%v %w", path, err)
+		return nil, fmt.Errorf("failed to read file: path=%v %w", path, err)
 	}
-	var ueki unfinalizedEKI
-	if err := json.Unmarshal(bz, &ueki); err != nil {
-		return nil, nil, fmt.Errorf("failed to unmarshal unfinalized enclave key info: %w", err)
+	types := map[string]string{}
+	if err := json.Unmarshal(bz, &types); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ELC origin client types: path=%v %w", path, err)
 	}
-	var unfinalizedMsgID core.MsgID
-	if err := pr.codec.UnmarshalInterface(ueki.MsgIDBytes, &unfinalizedMsgID); err != nil {
-		return nil, nil, fmt.Errorf("failed to unmarshal msg id: value=%x %w", ueki.MsgIDBytes, err)
+	return types, nil
+}
+
+// ekStoreFilePath returns the path of the file of the store of the current path
+func (pr *Prover) ekStoreFilePath() string {
+	return pr.newEKStore().path()
+}
+
+// sharedEKStoreFilePath returns the path of the file of the store shared by the paths, which holds the ELC client ID
+func (pr *Prover) sharedEKStoreFilePath() string {
+	return filepath.Join(pr.dbPath(), ekStoreFile)
+}
+
+// loadELCClientID returns the ELC client ID generated and persisted by the prover, or empty if it does not exist
+func (pr *Prover) loadELCClientID(context.Context) (string, error) {
+	id, err := pr.ekStore().GetELCClientID()
+	if err != nil || id == "" {
+		return "", err
+	}
+	if err := validateELCClientID(id); err != nil {
+		return "", fmt.Errorf("the persisted ELC client ID is invalid: path=%v %w", pr.sharedEKStoreFilePath(), err)
 	}
-	return ueki.Info, unfinalizedMsgID, nil
+	return id, nil
 }
 
-func (pr *Prover) saveFinalizedEnclaveKeyInfo(_ context.Context, eki *enclave.EnclaveKeyInfo) error {
-	pr.getLogger().Info("save finalized enclave key info")
-	bz, err := json.Marshal(eki)
+// saveELCClientID persists the ELC client ID generated by the prover
+func (pr *Prover) saveELCClientID(_ context.Context, elcClientID string) error {
+	pr.getLogger().Info("save ELC client ID", "elc_client_id", elcClientID)
+	return pr.ekStore().SetELCClientID(elcClientID)
+}
+
+// saveELCOriginClientType records the type URL of the origin client state for the ELC client
+func (pr *Prover) saveELCOriginClientType(ctx context.Context, elcClientID string, typeURL string) error {
+	types, err := pr.loadELCOriginClientTypes(ctx)
+	if err != nil {
+		return err
+	}
+	types[elcClientID] = typeURL
+	bz, err := json.Marshal(types)
 	if err != nil {
-		return fmt.Errorf("failed to marshal enclave key info: %w", err)
+		return fmt.Errorf("failed to marshal ELC origin client types: %w", err)
 	}
-	if err := os.WriteFile(pr.lastEnclaveKeyInfoFilePath(true), bz, 0600); err != nil {
-		return fmt.Errorf("failed to write enclave key info: %w", err)
+	pr.getLogger().Info("save ELC origin client type", "elc_client_id", elcClientID, "type_url", typeURL)
+	if err := os.WriteFile(filepath.Join(pr.dbPath(), elcOriginClientTypesFile), bz, 0600); err != nil {
+		return fmt.Errorf("failed to write ELC origin client types: %w", err)
 	}
 	return nil
 }
 
-func (pr *Prover) saveUnfinalizedEnclaveKeyInfo(_ context.Context, eki *enclave.EnclaveKeyInfo, msgID core.MsgID) error {
-	pr.getLogger().Info("save unfinalized enclave key info")
-	msgIDBytes, err := pr.codec.MarshalInterface(msgID)
+// removeELCOriginClientType removes the recorded type URL of the origin client state for the ELC client
+func (pr *Prover) removeELCOriginClientType(ctx context.Context, elcClientID string) error {
+	types, err := pr.loadELCOriginClientTypes(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to marshal msg id: %w", err)
+		return err
+	} else if _, ok := types[elcClientID]; !ok {
+		return nil
 	}
-	bz, err := json.Marshal(unfinalizedEKI{
-		Info:       eki,
-		MsgIDBytes: msgIDBytes,
-	})
+	delete(types, elcClientID)
+	bz, err := json.Marshal(types)
 	if err != nil {
-		return fmt.Errorf("failed to marshal enclave key info: %w", err)
+		return fmt.Errorf("failed to marshal ELC origin client types: %w", err)
 	}
-	if err := os.WriteFile(pr.lastEnclaveKeyInfoFilePath(false), bz, 0600); err != nil {
-		return fmt.Errorf("failed to write enclave key info: %w", err)
+	pr.getLogger().Info("remove ELC origin client type", "elc_client_id", elcClientID)
+	if err := os.WriteFile(filepath.Join(pr.dbPath(), elcOriginClientTypesFile), bz, 0600); err != nil {
+		return fmt.Errorf("failed to write ELC origin client types: %w", err)
 	}
 	return nil
 }
 
-func (pr *Prover) removeFinalizedEnclaveKeyInfo(context.Context) error {
-	path := pr.lastEnclaveKeyInfoFilePath(true)
-	pr.getLogger().Info("remove finalized enclave key info", "path", path)
-	if _, err := os.Stat(path); err != nil {
+// loadCounterpartyClientHeights returns the last observed latest heights of the counterparty LCP clients keyed by `{chain ID}/{client ID}`
+func (pr *Prover) loadCounterpartyClientHeights(context.Context) (map[string]clienttypes.Height, error) {
+	path := filepath.Join(pr.dbPath(), counterpartyClientHeightsFile)
+	bz, err := os.ReadFile(path)
+	if err != nil {
 		if os.IsNotExist(err) {
-			return nil
+			return map[string]clienttypes.Height{}, nil
 		}
-		return fmt.Errorf("failed to stat file: path=%v %w", path, err)
+		return nil, fmt.Errorf("failed to read file: path=%v %w", path, err)
 	}
-	if err := os.Remove(path); err != nil {
-		return fmt.Errorf("failed to remove file: path=%v %w", path, err)
+	heights := map[string]clienttypes.Height{}
+	if err := json.Unmarshal(bz, &heights); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal counterparty client heights: path=%v %w", path, err)
+	}
+	return heights, nil
+}
+
+// saveCounterpartyClientHeight records the latest height of the counterparty LCP client
+func (pr *Prover) saveCounterpartyClientHeight(ctx context.Context, key string, height clienttypes.Height) error {
+	heights, err := pr.loadCounterpartyClientHeights(ctx)
+	if err != nil {
+		return err
+	}
+	heights[key] = height
+	bz, err := json.Marshal(heights)
+	if err != nil {
+		return fmt.Errorf("failed to marshal counterparty client heights: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(pr.dbPath(), counterpartyClientHeightsFile), bz, 0600); err != nil {
+		return fmt.Errorf("failed to write counterparty client heights: %w", err)
 	}
 	return nil
 }
 
-func (pr *Prover) removeUnfinalizedEnclaveKeyInfo(context.Context) error {
-	path := pr.lastEnclaveKeyInfoFilePath(false)
-	pr.getLogger().Info("remove unfinalized enclave key info", "path", path)
-	if _, err := os.Stat(path); err != nil {
+// loadBootstrapCheckpoint returns the checkpoint of the failed bootstrap or nil if no bootstrap has failed
+func (pr *Prover) loadBootstrapCheckpoint(context.Context) (*bootstrapCheckpoint, error) {
+	path := filepath.Join(pr.dbPath(), bootstrapCheckpointFile)
+	bz, err := os.ReadFile(path)
+	if err != nil {
 		if os.IsNotExist(err) {
-			return nil
+			return nil, nil
 		}
-		return fmt.Errorf("failed to stat file: path=%v %w", path, err)
+		return nil, fmt.Errorf("failed to read file: path=%v %w", path, err)
+	}
+	var checkpoint bootstrapCheckpoint
+	if err := json.Unmarshal(bz, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bootstrap checkpoint: path=%v %w", path, err)
+	}
+	return &checkpoint, nil
+}
+
+func (pr *Prover) saveBootstrapCheckpoint(_ context.Context, checkpoint *bootstrapCheckpoint) error {
+	bz, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bootstrap checkpoint: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(pr.dbPath(), bootstrapCheckpointFile), bz, 0600); err != nil {
+		return fmt.Errorf("failed to write bootstrap checkpoint: %w", err)
 	}
-	if err := os.Remove(path); err != nil {
+	return nil
+}
+
+func (pr *Prover) removeBootstrapCheckpoint(context.Context) error {
+	path := filepath.Join(pr.dbPath(), bootstrapCheckpointFile)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove file: path=%v %w", path, err)
 	}
 	return nil
 }
 
-func (pr *Prover) removeEnclaveKeyInfos(ctx context.Context) error {
-	if err := pr.removeFinalizedEnclaveKeyInfo(ctx); err != nil {
-		return err
+// loadActivateClientCheckpoint returns the checkpoint of the interrupted activation or nil if no activation has been interrupted
+func (pr *Prover) loadActivateClientCheckpoint(context.Context) (*activateClientCheckpoint, error) {
+	path := filepath.Join(pr.dbPath(), activateClientCheckpointFile)
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: path=%v %w", path, err)
 	}
-	if err := pr.removeUnfinalizedEnclaveKeyInfo(ctx); err != nil {
-		return err
+	var checkpoint activateClientCheckpoint
+	if err := json.Unmarshal(bz, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal activate client checkpoint: path=%v %w", path, err)
+	}
+	return &checkpoint, nil
+}
+
+func (pr *Prover) saveActivateClientCheckpoint(_ context.Context, checkpoint *activateClientCheckpoint) error {
+	bz, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal activate client checkpoint: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(pr.dbPath(), activateClientCheckpointFile), bz, 0600); err != nil {
+		return fmt.Errorf("failed to write activate client checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (pr *Prover) removeActivateClientCheckpoint(context.Context) error {
+	path := filepath.Join(pr.dbPath(), activateClientCheckpointFile)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove file: path=%v %w", path, err)
 	}
 	return nil
 }
@@ -0,0 +1,69 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnfinalizedEnclaveKeyInfos(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.homePath = t.TempDir()
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+	eki := loadTestEnclaveKeyInfo(t)
+	msgA := &tendermint.MsgID{TxHash: "0xaa", MsgIndex: 0}
+	msgB := &tendermint.MsgID{TxHash: "0xbb", MsgIndex: 0}
+
+	// the record saved by the previous versions is migrated as the oldest one
+	msgIDBytes, err := pr.codec.MarshalInterface(msgA)
+	require.NoError(err)
+	bz, err := json.Marshal(unfinalizedEKI{Info: eki, MsgIDBytes: msgIDBytes})
+	require.NoError(err)
+	require.NoError(os.WriteFile(filepath.Join(pr.dbPath(), lastUnfinalizedEnclaveKeyInfoFile), bz, 0600))
+	_, msgID, _, err := pr.loadLastUnfinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal(msgA.String(), msgID.String())
+	_, err = os.Stat(filepath.Join(pr.dbPath(), lastUnfinalizedEnclaveKeyInfoFile))
+	require.True(os.IsNotExist(err))
+
+	require.NoError(pr.saveUnfinalizedEnclaveKeyInfo(context.TODO(), eki, msgB, clienttypes.Height{}))
+
+	// the records of the same key are kept for each msg
+	records, err := pr.loadUnfinalizedEnclaveKeys(context.TODO())
+	require.NoError(err)
+	require.Len(records, 2)
+	require.Equal(msgA.String(), records[0].msgID.String())
+	require.Equal(msgB.String(), records[1].msgID.String())
+
+	// saving the same registration updates the record in place
+	height := clienttypes.NewHeight(0, 10)
+	require.NoError(pr.saveUnfinalizedEnclaveKeyInfo(context.TODO(), eki, msgA, height))
+	_, msgID, includedHeight, err := pr.loadLastUnfinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal(msgB.String(), msgID.String())
+	require.True(includedHeight.IsZero())
+	records, err = pr.loadUnfinalizedEnclaveKeys(context.TODO())
+	require.NoError(err)
+	require.Len(records, 2)
+	require.Equal(height, records[0].includedHeight)
+
+	remaining, err := pr.removeUnfinalizedEnclaveKeyInfo(context.TODO(), eki, msgB)
+	require.NoError(err)
+	require.Equal(1, remaining)
+	_, msgID, _, err = pr.loadLastUnfinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal(msgA.String(), msgID.String())
+
+	require.NoError(pr.removeUnfinalizedEnclaveKeyInfos(context.TODO()))
+	_, _, _, err = pr.loadLastUnfinalizedEnclaveKey(context.TODO())
+	require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+}
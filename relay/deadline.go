@@ -0,0 +1,37 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	operationProveState   = "prove_state"
+	operationUpdateClient = "update_client"
+)
+
+// operationDeadline is the deadline of an operation invoked through an entry point of core.Prover.
+// The entry points receive no context or a context without a deadline from the relayer,
+// so a stuck call to the LCP service would block the relayer's main loop without it.
+type operationDeadline struct {
+	operation string
+	timeout   time.Duration
+	deadline  time.Time
+}
+
+// withOperationDeadline returns a context that is canceled when `timeout` elapses or `parent` is done
+func withOperationDeadline(parent context.Context, operation string, timeout time.Duration) (context.Context, context.CancelFunc, operationDeadline) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	deadline, _ := ctx.Deadline()
+	return ctx, cancel, operationDeadline{operation: operation, timeout: timeout, deadline: deadline}
+}
+
+// wrapError annotates `err` with the deadline if the operation is timed out.
+// The check relies on `ctx` because the gRPC clients return their own error types on timeouts.
+func (d operationDeadline) wrapError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	return fmt.Errorf("%v timed out: timeout=%v deadline=%v %w", d.operation, d.timeout, d.deadline.Format(time.RFC3339Nano), err)
+}
@@ -0,0 +1,141 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// sleepingLCPService is a mockLCPService whose calls block for the given durations or until the context is done
+type sleepingLCPService struct {
+	*mockLCPService
+	updateDelay time.Duration
+	verifyDelay time.Duration
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s sleepingLCPService) UpdateClient(ctx context.Context, in *elc.MsgUpdateClient, opts ...grpc.CallOption) (*elc.MsgUpdateClientResponse, error) {
+	if err := sleepContext(ctx, s.updateDelay); err != nil {
+		return nil, err
+	}
+	return s.mockLCPService.UpdateClient(ctx, in, opts...)
+}
+
+func (s sleepingLCPService) VerifyMembership(ctx context.Context, in *elc.MsgVerifyMembership, opts ...grpc.CallOption) (*elc.MsgVerifyMembershipResponse, error) {
+	if err := sleepContext(ctx, s.verifyDelay); err != nil {
+		return nil, err
+	}
+	return s.mockLCPService.VerifyMembership(ctx, in, opts...)
+}
+
+func TestOperationDeadlines(t *testing.T) {
+	const stuck = time.Minute
+
+	var cases = []struct {
+		name        string
+		updateDelay time.Duration
+		verifyDelay time.Duration
+		// the operation expected to time out
+		timedOut string
+	}{
+		{"no delay", 0, 0, ""},
+		{"stuck update", stuck, 0, operationUpdateClient},
+		{"stuck verification", 0, stuck, operationProveState},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			key, err := crypto.GenerateKey()
+			require.NoError(err)
+
+			service := sleepingLCPService{
+				mockLCPService: &mockLCPService{t: t, key: key, clients: map[string]*lcptypes.ClientState{
+					"07-tendermint-0": {LatestHeight: clienttypes.NewHeight(0, 1)},
+				}},
+				updateDelay: c.updateDelay,
+				verifyDelay: c.verifyDelay,
+			}
+			pr := newTestProver(t)
+			pr.codec = newTestCodec()
+			pr.homePath = t.TempDir()
+			pr.originChain = &mockCounterparty{chainID: "origin"}
+			require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+			pr.config.ElcClientId = "07-tendermint-0"
+			pr.config.ProveStateTimeout = 1
+			pr.config.UpdateClientTimeout = 1
+			pr.originProver = mockSelfTestOriginProver{latestHeight: clienttypes.NewHeight(0, 10)}
+			pr.lcpServiceClient = LCPServiceClient{
+				ELCMsgClient:       service,
+				ELCQueryClient:     service,
+				EnclaveQueryClient: mockEnclaveQueryClient{},
+			}
+			pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{
+				EnclaveKeyAddress: crypto.PubkeyToAddress(key.PublicKey).Bytes(),
+				AttestationTime:   uint64(time.Now().Unix()),
+			}
+
+			// each entry point must time out independently of the other one
+			counterparty := newMockCounterparty(clienttypes.NewHeight(0, 1))
+			counterparty.clientState = &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, 1)}
+			start := time.Now()
+			updates, err := pr.SetupHeadersForUpdate(counterparty, mockHeader{height: clienttypes.NewHeight(0, 2)})
+			if c.timedOut == operationUpdateClient {
+				require.ErrorContains(err, "update_client timed out: timeout=1s deadline=")
+				require.ErrorIs(err, context.DeadlineExceeded)
+				require.Less(time.Since(start), stuck)
+			} else {
+				require.NoError(err)
+				require.Len(updates, 1)
+			}
+
+			start = time.Now()
+			proof, _, err := pr.ProveState(core.NewQueryContext(context.TODO(), clienttypes.NewHeight(0, 2)), "clients/07-tendermint-0/clientState", []byte("value"))
+			if c.timedOut == operationProveState {
+				require.ErrorContains(err, "prove_state timed out: timeout=1s deadline=")
+				require.ErrorIs(err, context.DeadlineExceeded)
+				require.Less(time.Since(start), stuck)
+			} else {
+				require.NoError(err)
+				require.NotEmpty(proof)
+			}
+		})
+	}
+}
+
+func TestOperationDeadlineWrapError(t *testing.T) {
+	ctx, cancel, deadline := withOperationDeadline(context.TODO(), operationProveState, time.Minute)
+	defer cancel()
+	// errors before the deadline are not annotated
+	err := errors.New("failed")
+	require.Equal(t, err, deadline.wrapError(ctx, err))
+	require.NoError(t, deadline.wrapError(ctx, nil))
+
+	// the parent's earlier deadline is respected
+	parent, cancelParent := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancelParent()
+	ctx, cancel, deadline = withOperationDeadline(parent, operationProveState, time.Minute)
+	defer cancel()
+	<-ctx.Done()
+	require.ErrorContains(t, deadline.wrapError(ctx, ctx.Err()), "prove_state timed out: timeout=1m0s")
+	d, _ := parent.Deadline()
+	require.Equal(t, d, deadline.deadline)
+}
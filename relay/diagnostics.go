@@ -0,0 +1,207 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	runtimepprof "runtime/pprof"
+	"time"
+)
+
+// diagnosticsShutdownTimeout is the deadline to finish the in-flight requests to the diagnostics server on Close
+const diagnosticsShutdownTimeout = 5 * time.Second
+
+// diagnosticsServer is the HTTP server exposing the runtime diagnostics of the prover
+type diagnosticsServer struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// startDiagnostics starts the diagnostics server if the address is configured and it is not started yet.
+// The server only reads the state of the prover, so it never affects the relay.
+func (pr *Prover) startDiagnostics() error {
+	if pr.config.DiagnosticsAddress == "" || pr.diagnostics != nil {
+		return nil
+	}
+	listener, err := net.Listen("tcp", pr.config.DiagnosticsAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on the diagnostics address: address=%v %w", pr.config.DiagnosticsAddress, err)
+	}
+	server := &http.Server{
+		Handler:           newDiagnosticsHandler(pr.diagnosticsVars()),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	pr.diagnostics = &diagnosticsServer{listener: listener, server: server}
+	logger := pr.getLogger()
+	logger.Info("start the diagnostics server", "address", listener.Addr().String())
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("the diagnostics server stopped", err)
+		}
+	}()
+	return nil
+}
+
+// Close stops the diagnostics server if it is running, writes the queued path stats and exports the remaining metrics
+func (pr *Prover) Close() error {
+	if err := pr.stopStats(); err != nil {
+		return err
+	}
+	if pr.metrics != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+		defer cancel()
+		if err := pr.metrics.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+	if pr.diagnostics == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), diagnosticsShutdownTimeout)
+	defer cancel()
+	err := pr.diagnostics.server.Shutdown(ctx)
+	pr.diagnostics = nil
+	if err != nil {
+		return fmt.Errorf("failed to stop the diagnostics server: %w", err)
+	}
+	return nil
+}
+
+// newDiagnosticsHandler returns the handler of the diagnostics server:
+//   - /debug/pprof/: the profiles of net/http/pprof
+//   - /debug/vars: the variables of the process published by expvar, e.g. memstats
+//   - /debug/lcp/vars: `vars` of the prover
+//   - /debug/lcp/health: the "health" variable of `vars`
+//   - /debug/goroutines: the stack traces of all goroutines
+func newDiagnosticsHandler(vars *expvar.Map) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/lcp/vars", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprintln(w, vars.String())
+	})
+	mux.HandleFunc("/debug/lcp/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprintln(w, vars.Get("health").String())
+	})
+	mux.HandleFunc("/debug/goroutines", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := runtimepprof.Lookup("goroutine").WriteTo(w, 2); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	return mux
+}
+
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+)
+
+// ProverHealth is the health of the prover served by the diagnostics server.
+// The prover keeps relaying in the degraded state, but the operators should look into the reasons.
+type ProverHealth struct {
+	Status  string   `json:"status"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// health returns the health of the prover from the states guarded by locks
+func (pr *Prover) health() ProverHealth {
+	h := ProverHealth{Status: HealthStatusOK}
+	if status, ok := pr.unfinalizedKeyWatchdog.get(); ok && status.Stuck {
+		h.Status = HealthStatusDegraded
+		h.Reasons = append(h.Reasons, fmt.Sprintf("the enclave key registration has been unfinalized for %v: msg_id=%v deadline=%v", status.Pending, status.MsgID, status.Deadline))
+	}
+	return h
+}
+
+// diagnosticsVars returns the variables of the internal caches and queues of the prover.
+// They are not published to the global registry of expvar because multiple provers may run in a process.
+// Only the states guarded by locks are read so that the reads do not race with the relay.
+func (pr *Prover) diagnosticsVars() *expvar.Map {
+	vars := new(expvar.Map).Init()
+	vars.Set("chain_id", expvar.Func(func() any {
+		return pr.originChain.ChainID()
+	}))
+	vars.Set("finalized_header_cache", expvar.Func(func() any {
+		c := pr.counterpartyFinalizedHeaderCache
+		if c == nil {
+			return nil
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return map[string]any{
+			"chain_id":   c.chainID,
+			"cached":     c.header != nil,
+			"fetched_at": c.fetchedAt,
+		}
+	}))
+	vars.Set("crl_cache", expvar.Func(func() any {
+		c := pr.crlCache
+		if c == nil {
+			return nil
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		var entries int
+		if c.crl != nil {
+			entries = len(c.crl.RevokedCertificateEntries)
+		}
+		return map[string]any{
+			"entries":    entries,
+			"fetched_at": c.fetchedAt,
+		}
+	}))
+	vars.Set("alerter", expvar.Func(func() any {
+		a := pr.alerter
+		if a == nil {
+			return nil
+		}
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return map[string]any{
+			"deduplicated_alerts": len(a.lastSent),
+		}
+	}))
+	vars.Set("origin_provers", expvar.Func(func() any {
+		s, ok := pr.originProver.(*originProverSet)
+		if !ok {
+			return nil
+		}
+		return s.Statuses()
+	}))
+	vars.Set("health", expvar.Func(func() any {
+		return pr.health()
+	}))
+	vars.Set("unfinalized_key", expvar.Func(func() any {
+		status, ok := pr.unfinalizedKeyWatchdog.get()
+		if !ok {
+			return nil
+		}
+		return status
+	}))
+	vars.Set("rate_limiter", expvar.Func(func() any {
+		rl := pr.rateLimiter
+		if rl == nil {
+			return nil
+		}
+		tokens := make(map[string]float64)
+		for class, l := range rl.limiters {
+			tokens[class] = l.Tokens()
+		}
+		return map[string]any{
+			"bypass": rl.bypass.Load(),
+			"tokens": tokens,
+		}
+	}))
+	return vars
+}
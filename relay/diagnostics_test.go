@@ -0,0 +1,70 @@
+package relay
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosticsServer(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	pr.counterpartyFinalizedHeaderCache = newFinalizedHeaderCache(DefaultFinalizedHeaderCacheTTL)
+	pr.rateLimiter = newServiceRateLimiter(ProverConfig{QueryRateLimit: &RateLimit{CallsPerSecond: 1, Burst: 3}}, nil)
+
+	// disabled by default
+	require.NoError(pr.startDiagnostics())
+	require.Nil(pr.diagnostics)
+
+	pr.config.DiagnosticsAddress = "127.0.0.1:0"
+	require.NoError(pr.startDiagnostics())
+	t.Cleanup(func() { pr.Close() })
+	require.NotNil(pr.diagnostics)
+	base := "http://" + pr.diagnostics.listener.Addr().String()
+
+	get := func(path string) []byte {
+		res, err := http.Get(base + path)
+		require.NoError(err)
+		defer res.Body.Close()
+		require.Equal(http.StatusOK, res.StatusCode, path)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(err)
+		return body
+	}
+
+	// a heap profile in the gzipped protobuf format
+	profile := get("/debug/pprof/heap")
+	require.Equal([]byte{0x1f, 0x8b}, profile[:2])
+
+	require.Contains(string(get("/debug/goroutines")), "goroutine ")
+
+	var vars struct {
+		ChainID     string `json:"chain_id"`
+		RateLimiter struct {
+			Tokens map[string]float64 `json:"tokens"`
+		} `json:"rate_limiter"`
+		CRLCache any `json:"crl_cache"`
+	}
+	require.NoError(json.Unmarshal(get("/debug/lcp/vars"), &vars))
+	require.Equal("origin", vars.ChainID)
+	require.Equal(float64(3), vars.RateLimiter.Tokens[rpcClassQuery])
+	require.Nil(vars.CRLCache)
+
+	var health ProverHealth
+	require.NoError(json.Unmarshal(get("/debug/lcp/health"), &health))
+	require.Equal(ProverHealth{Status: HealthStatusOK}, health)
+
+	var processVars map[string]json.RawMessage
+	require.NoError(json.Unmarshal(get("/debug/vars"), &processVars))
+	require.Contains(processVars, "memstats")
+
+	// the listener is stopped by Close
+	require.NoError(pr.Close())
+	require.Nil(pr.diagnostics)
+	_, err := http.Get(base + "/debug/pprof/")
+	require.Error(err)
+}
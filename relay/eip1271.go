@@ -0,0 +1,154 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+const (
+	DefaultEIP1271PollInterval    = 15      // seconds
+	DefaultEIP1271ApprovalTimeout = 60 * 60 // seconds
+)
+
+// ErrEIP1271ApprovalTimeout is returned if the contract wallet does not approve the EIP712 digest within the timeout
+var ErrEIP1271ApprovalTimeout = errors.New("the contract wallet did not approve the EIP712 digest")
+
+// eip1271MagicValue is the value returned by `isValidSignature` of EIP-1271 if the signature is valid
+var eip1271MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+
+var eip1271ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(`[{"type":"function","name":"isValidSignature","stateMutability":"view","inputs":[{"name":"hash","type":"bytes32"},{"name":"signature","type":"bytes"}],"outputs":[{"name":"magicValue","type":"bytes4"}]}]`))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// GetPollInterval returns the interval to poll the approval of the contract wallet
+func (c EIP1271OperatorConfig) GetPollInterval() time.Duration {
+	if c.PollInterval == 0 {
+		return DefaultEIP1271PollInterval * time.Second
+	}
+	return time.Duration(c.PollInterval) * time.Second
+}
+
+// GetApprovalTimeout returns the deadline to wait for the approval of the contract wallet
+func (c EIP1271OperatorConfig) GetApprovalTimeout() time.Duration {
+	if c.ApprovalTimeout == 0 {
+		return DefaultEIP1271ApprovalTimeout * time.Second
+	}
+	return time.Duration(c.ApprovalTimeout) * time.Second
+}
+
+func (c EIP1271OperatorConfig) Validate() error {
+	if !common.IsHexAddress(c.WalletAddress) {
+		return fmt.Errorf("WalletAddress must be a hex address: %v", c.WalletAddress)
+	}
+	if c.RpcUrl == "" {
+		return fmt.Errorf("RpcUrl must be set")
+	}
+	return nil
+}
+
+// EIP1271Signer is an OperatorSigner for the operator identity held in a contract wallet, e.g. a Safe, on the EVM host chain.
+// It cannot sign the digests by itself, so it outputs the digest and waits for the wallet to approve it,
+// i.e. `isValidSignature(digest, "")` of the wallet returns the magic value of EIP-1271.
+type EIP1271Signer struct {
+	wallet          common.Address
+	caller          ethereum.ContractCaller
+	pollInterval    time.Duration
+	approvalTimeout time.Duration
+}
+
+var _ OperatorSigner = (*EIP1271Signer)(nil)
+
+func NewEIP1271Signer(wallet common.Address, caller ethereum.ContractCaller, pollInterval, approvalTimeout time.Duration) *EIP1271Signer {
+	return &EIP1271Signer{
+		wallet:          wallet,
+		caller:          caller,
+		pollInterval:    pollInterval,
+		approvalTimeout: approvalTimeout,
+	}
+}
+
+// newEIP1271SignerFromConfig returns the EIP1271Signer querying the approvals through the RPC endpoint in `config`.
+// The connection is established on the first query.
+func newEIP1271SignerFromConfig(config *EIP1271OperatorConfig) (*EIP1271Signer, error) {
+	client, err := ethclient.Dial(config.RpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the EVM RPC: %w", err)
+	}
+	return NewEIP1271Signer(common.HexToAddress(config.WalletAddress), client, config.GetPollInterval(), config.GetApprovalTimeout()), nil
+}
+
+func (s *EIP1271Signer) GetSignerAddress() (common.Address, error) {
+	return s.wallet, nil
+}
+
+// Sign waits for the contract wallet to approve `commitment` and returns the contract signature for the wallet
+func (s *EIP1271Signer) Sign(commitment [32]byte) ([]byte, error) {
+	logger := log.GetLogger().WithModule(ModuleName)
+	ctx, cancel := context.WithTimeout(context.Background(), s.approvalTimeout)
+	defer cancel()
+	digest := common.Hash(commitment).Hex()
+	logger.Info("waiting for the contract wallet to approve the EIP712 digest", "wallet", s.wallet.Hex(), "digest", digest, "timeout", s.approvalTimeout)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		approved, err := s.isApproved(ctx, commitment)
+		if err != nil && ctx.Err() == nil {
+			// the wallets revert the call if the digest is not approved yet
+			logger.Debug("the digest is not approved yet", "wallet", s.wallet.Hex(), "digest", digest, "error", err)
+			lastErr = err
+		} else if approved {
+			logger.Info("the contract wallet approved the EIP712 digest", "wallet", s.wallet.Hex(), "digest", digest)
+			return eip1271ContractSignature(s.wallet), nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: wallet=%v digest=%v timeout=%v last_error=%v", ErrEIP1271ApprovalTimeout, s.wallet.Hex(), digest, s.approvalTimeout, lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+// isApproved returns true if `isValidSignature(commitment, "")` of the wallet returns the magic value
+func (s *EIP1271Signer) isApproved(ctx context.Context, commitment [32]byte) (bool, error) {
+	input, err := eip1271ABI.Pack("isValidSignature", commitment, []byte{})
+	if err != nil {
+		return false, err
+	}
+	output, err := s.caller.CallContract(ctx, ethereum.CallMsg{To: &s.wallet, Data: input}, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to call isValidSignature: %w", err)
+	}
+	values, err := eip1271ABI.Unpack("isValidSignature", output)
+	if err != nil {
+		return false, fmt.Errorf("failed to unpack the result of isValidSignature: output=%x %w", output, err)
+	}
+	magic, ok := values[0].([4]byte)
+	if !ok {
+		return false, fmt.Errorf("unexpected result of isValidSignature: %T", values[0])
+	}
+	return bytes.Equal(magic[:], eip1271MagicValue[:]), nil
+}
+
+// eip1271ContractSignature returns the 65-byte signature that the LCP contract verifies with EIP-1271 of `wallet` instead of ecrecover.
+// It follows the contract signature format of Safe: `r` is the wallet address, `s` is zero and `v` is zero.
+func eip1271ContractSignature(wallet common.Address) []byte {
+	sig := make([]byte, 65)
+	copy(sig[12:32], wallet.Bytes())
+	return sig
+}
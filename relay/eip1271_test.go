@@ -0,0 +1,147 @@
+package relay
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockEVMRPCServer returns an EVM RPC server whose `isValidSignature` returns `magic` after `pending` calls.
+// The calls before it revert as a Safe does for a digest that is not approved yet.
+func newMockEVMRPCServer(t *testing.T, pending int, magic [4]byte) (*httptest.Server, func() []hexutil.Bytes) {
+	var (
+		mu    sync.Mutex
+		calls []hexutil.Bytes
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []json.RawMessage
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "eth_call", req.Method)
+		var call struct {
+			Input hexutil.Bytes `json:"input"`
+			Data  hexutil.Bytes `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(req.Params[0], &call))
+		mu.Lock()
+		calls = append(calls, append(call.Input, call.Data...))
+		n := len(calls)
+		mu.Unlock()
+
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if n <= pending {
+			res["error"] = map[string]interface{}{"code": 3, "message": "execution reverted: Hash not approved"}
+		} else {
+			output := make([]byte, 32)
+			copy(output, magic[:])
+			res["result"] = hexutil.Encode(output)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []hexutil.Bytes {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]hexutil.Bytes(nil), calls...)
+	}
+}
+
+func TestEIP1271Signer(t *testing.T) {
+	require.NoError(t, log.InitLogger("DEBUG", "text", "stdout"))
+	const wallet = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	digest := crypto.Keccak256Hash([]byte("update operators"))
+	newSigner := func(t *testing.T, url string, timeout time.Duration) *EIP1271Signer {
+		s, err := newEIP1271SignerFromConfig(&EIP1271OperatorConfig{WalletAddress: wallet, RpcUrl: url})
+		require.NoError(t, err)
+		s.pollInterval = 10 * time.Millisecond
+		s.approvalTimeout = timeout
+		return s
+	}
+
+	t.Run("approved after polling", func(t *testing.T) {
+		require := require.New(t)
+		srv, calls := newMockEVMRPCServer(t, 2, eip1271MagicValue)
+		s := newSigner(t, srv.URL, time.Second)
+		addr, err := s.GetSignerAddress()
+		require.NoError(err)
+		require.Equal(common.HexToAddress(wallet), addr)
+
+		sig, err := s.Sign(digest)
+		require.NoError(err)
+		require.Len(sig, 65)
+		require.Equal(common.HexToAddress(wallet).Bytes(), sig[12:32])
+		require.Equal(make([]byte, 33), sig[32:])
+		require.Len(calls(), 3)
+		// isValidSignature(digest, "")
+		expected, err := eip1271ABI.Pack("isValidSignature", [32]byte(digest), []byte{})
+		require.NoError(err)
+		require.Equal(hexutil.Bytes(expected), calls()[2])
+	})
+
+	t.Run("never approved", func(t *testing.T) {
+		require := require.New(t)
+		srv, calls := newMockEVMRPCServer(t, 1000, eip1271MagicValue)
+		_, err := newSigner(t, srv.URL, 100*time.Millisecond).Sign(digest)
+		require.True(errors.Is(err, ErrEIP1271ApprovalTimeout), err)
+		require.ErrorContains(err, digest.Hex())
+		require.ErrorContains(err, "Hash not approved")
+		require.Greater(len(calls()), 1)
+	})
+
+	t.Run("invalid magic value", func(t *testing.T) {
+		srv, _ := newMockEVMRPCServer(t, 0, [4]byte{0xff, 0xff, 0xff, 0xff})
+		_, err := newSigner(t, srv.URL, 100*time.Millisecond).Sign(digest)
+		require.ErrorIs(t, err, ErrEIP1271ApprovalTimeout)
+	})
+}
+
+func TestValidateOperatorEIP1271(t *testing.T) {
+	const (
+		operator = "0xcb96F8d6C2d543102184d679D7829b39434E4EEc"
+		other    = "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+	)
+	evmParams := &ProverConfig_OperatorsEip712EvmChainParams{
+		OperatorsEip712EvmChainParams: &EIP712EVMChainParams{ChainId: 1, VerifyingContractAddress: other},
+	}
+	cosmosParams := &ProverConfig_OperatorsEip712CosmosChainParams{
+		OperatorsEip712CosmosChainParams: &EIP712CosmosChainParams{ChainId: "ibc0", Prefix: "ibc"},
+	}
+	var cases = []struct {
+		name   string
+		config ProverConfig
+		// expected substring of the error. if empty, the config is valid
+		err string
+	}{
+		{"evm", ProverConfig{Operators: []string{operator}, OperatorsEip712Params: evmParams, OperatorEip1271: &EIP1271OperatorConfig{WalletAddress: operator, RpcUrl: "http://localhost:8545"}}, ""},
+		{"cosmos", ProverConfig{Operators: []string{operator}, OperatorsEip712Params: cosmosParams, OperatorEip1271: &EIP1271OperatorConfig{WalletAddress: operator, RpcUrl: "http://localhost:8545"}}, "only supported on EVM host chains"},
+		{"no eip712 params", ProverConfig{Operators: []string{operator}, OperatorEip1271: &EIP1271OperatorConfig{WalletAddress: operator, RpcUrl: "http://localhost:8545"}}, "only supported on EVM host chains"},
+		{"with operator signer", ProverConfig{Operators: []string{operator}, OperatorsEip712Params: evmParams, OperatorSigner: &codectypes.Any{}, OperatorEip1271: &EIP1271OperatorConfig{WalletAddress: operator, RpcUrl: "http://localhost:8545"}}, "must not be set at the same time"},
+		{"wallet mismatch", ProverConfig{Operators: []string{operator}, OperatorsEip712Params: evmParams, OperatorEip1271: &EIP1271OperatorConfig{WalletAddress: other, RpcUrl: "http://localhost:8545"}}, "must be equal to the first operator's address"},
+		{"no rpc url", ProverConfig{Operators: []string{operator}, OperatorsEip712Params: evmParams, OperatorEip1271: &EIP1271OperatorConfig{WalletAddress: operator}}, "RpcUrl must be set"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.validateOperatorEIP1271()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
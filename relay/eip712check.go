@@ -0,0 +1,147 @@
+package relay
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// the type strings of the EIP712 structs in the LCP client contract
+const (
+	eip712DomainType                 = "EIP712Domain(string name,string version,uint256 chainId,address verifyingContract,bytes32 salt)"
+	eip712RegisterEnclaveKeyType     = "RegisterEnclaveKey(string avr)"
+	eip712DCAPRegisterEnclaveKeyType = "DCAPRegisterEnclaveKey(bytes quote)"
+	eip712UpdateOperatorsType        = "UpdateOperators(string clientId,uint64 nonce,address[] newOperators,uint64 thresholdNumerator,uint64 thresholdDenominator)"
+	eip712UpdateClientParamsType     = "UpdateClientParams(string clientId,uint64 nonce,string[] allowedQuoteStatuses,string[] allowedAdvisoryIds,uint64 keyExpiration)"
+)
+
+// EIP712DigestMismatchError is returned if the digest signed by the operator differs from the one derived as the LCP client contract does.
+// The contract would reject the signature, so the msg is not submitted.
+type EIP712DigestMismatchError struct {
+	PrimaryType string
+	// the digest computed by lcptypes, which the operator signed
+	Digest common.Hash
+	// the digest derived independently as the contract does
+	ContractDigest common.Hash
+}
+
+func (e *EIP712DigestMismatchError) Error() string {
+	return fmt.Sprintf("the EIP712 digest differs from the one of the LCP client contract: primary_type=%v digest=%v contract_digest=%v", e.PrimaryType, e.Digest.Hex(), e.ContractDigest.Hex())
+}
+
+// checkEIP712Digest compares `digest` with `contractDigest` if the counterparty is an EVM chain, on which the contract verifies the signature.
+// On the other chains, the signature is verified with lcptypes, so the check is skipped.
+func (pr *Prover) checkEIP712Digest(primaryType string, digest, contractDigest common.Hash) error {
+	if pr.config.ChainType() != lcptypes.ChainTypeEVM {
+		return nil
+	} else if digest != contractDigest {
+		return &EIP712DigestMismatchError{PrimaryType: primaryType, Digest: digest, ContractDigest: contractDigest}
+	}
+	return nil
+}
+
+// contractUpdateOperatorsDigest derives the digest of UpdateOperators with the domain of the config as the contract does
+func (pr *Prover) contractUpdateOperatorsDigest(nonce uint64, newOperators []common.Address, thresholdNumerator, thresholdDenominator uint64) common.Hash {
+	params := pr.getDomainParams()
+	return contractEIP712Digest(
+		contractDomainSeparator(params.ChainId, params.VerifyingContractAddr, pr.computeEIP712ChainSalt()),
+		contractUpdateOperatorsHash(pr.path.ClientID, nonce, newOperators, thresholdNumerator, thresholdDenominator),
+	)
+}
+
+// contractUpdateClientParamsDigest derives the digest of UpdateClientParams with the domain of the config as the contract does
+func (pr *Prover) contractUpdateClientParamsDigest(nonce uint64, allowedQuoteStatuses, allowedAdvisoryIDs []string, keyExpiration uint64) common.Hash {
+	params := pr.getDomainParams()
+	return contractEIP712Digest(
+		contractDomainSeparator(params.ChainId, params.VerifyingContractAddr, pr.computeEIP712ChainSalt()),
+		contractUpdateClientParamsHash(pr.path.ClientID, nonce, allowedQuoteStatuses, allowedAdvisoryIDs, keyExpiration),
+	)
+}
+
+// contractRegisterEnclaveKeyDigest derives the digest of the registration of `a`, whose domain is always the zero one, as the contract does
+func contractRegisterEnclaveKeyDigest(a *attestation) common.Hash {
+	domainSeparator := contractDomainSeparator(0, common.Address{}, common.Hash{})
+	if a.Type == AttestationTypeDCAP {
+		return contractEIP712Digest(domainSeparator, crypto.Keccak256Hash(abiWords(keccakString(eip712DCAPRegisterEnclaveKeyType), crypto.Keccak256(a.dcap.Quote))))
+	}
+	return contractEIP712Digest(domainSeparator, crypto.Keccak256Hash(abiWords(keccakString(eip712RegisterEnclaveKeyType), keccakString(a.report))))
+}
+
+// The functions below mirror the encoding of the LCP client contract with abi.encode and keccak256,
+// independently of the typed data of go-ethereum that lcptypes uses.
+
+func contractEIP712Digest(domainSeparator, structHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator.Bytes(), structHash.Bytes())
+}
+
+func contractDomainSeparator(chainID uint64, verifyingContract common.Address, salt common.Hash) common.Hash {
+	return crypto.Keccak256Hash(abiWords(
+		keccakString(eip712DomainType),
+		keccakString("LCPClient"),
+		keccakString("1"),
+		abiUint64(chainID),
+		common.LeftPadBytes(verifyingContract.Bytes(), 32),
+		salt.Bytes(),
+	))
+}
+
+func contractUpdateOperatorsHash(clientID string, nonce uint64, newOperators []common.Address, thresholdNumerator, thresholdDenominator uint64) common.Hash {
+	// address[] is encoded as keccak256(abi.encodePacked(newOperators)), whose elements are padded to 32 bytes
+	var operators []byte
+	for _, op := range newOperators {
+		operators = append(operators, common.LeftPadBytes(op.Bytes(), 32)...)
+	}
+	return crypto.Keccak256Hash(abiWords(
+		keccakString(eip712UpdateOperatorsType),
+		keccakString(clientID),
+		abiUint64(nonce),
+		crypto.Keccak256(operators),
+		abiUint64(thresholdNumerator),
+		abiUint64(thresholdDenominator),
+	))
+}
+
+func contractUpdateClientParamsHash(clientID string, nonce uint64, allowedQuoteStatuses, allowedAdvisoryIDs []string, keyExpiration uint64) common.Hash {
+	return crypto.Keccak256Hash(abiWords(
+		keccakString(eip712UpdateClientParamsType),
+		keccakString(clientID),
+		abiUint64(nonce),
+		keccakStrings(allowedQuoteStatuses),
+		keccakStrings(allowedAdvisoryIDs),
+		abiUint64(keyExpiration),
+	))
+}
+
+func keccakString(s string) []byte {
+	return crypto.Keccak256([]byte(s))
+}
+
+// keccakStrings encodes string[] as the keccak256 of the concatenated hashes of the elements
+func keccakStrings(ss []string) []byte {
+	var bz []byte
+	for _, s := range ss {
+		bz = append(bz, keccakString(s)...)
+	}
+	return crypto.Keccak256(bz)
+}
+
+func abiUint64(v uint64) []byte {
+	var word [32]byte
+	binary.BigEndian.PutUint64(word[24:], v)
+	return word[:]
+}
+
+// abiWords concatenates the 32-byte words as abi.encode does for the static types
+func abiWords(words ...[]byte) []byte {
+	bz := make([]byte, 0, 32*len(words))
+	for _, w := range words {
+		if len(w) != 32 {
+			panic(fmt.Sprintf("invalid word length: %v", len(w)))
+		}
+		bz = append(bz, w...)
+	}
+	return bz
+}
@@ -0,0 +1,117 @@
+package relay
+
+import (
+	"errors"
+	"testing"
+
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/light-clients/lcp/testutil"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+// The digests below are the known answers of the EIP712 encoding of the LCP client contract.
+// They must be kept in sync with the tests of the contract if its encoding changes.
+func TestContractEIP712Digests(t *testing.T) {
+	contract := common.HexToAddress("0x2F5703804E29F4252FA9405B8D357220d11b3bd9")
+	salt := crypto.Keccak256Hash([]byte{0, 1})
+	operators := []common.Address{
+		common.HexToAddress("0xcb96F8d6C2d543102184d679D7829b39434E4EEc"),
+		common.HexToAddress("0x9722414d7E1A2b4D5F7a5F0d5e1e4B3b8f1eDA01"),
+	}
+	statuses, advisories := []string{"GROUP_OUT_OF_DATE"}, []string{"INTEL-SA-00219", "INTEL-SA-00289"}
+	domainSeparator := contractDomainSeparator(1, contract, salt)
+
+	cases := []struct {
+		name     string
+		lcptypes func() (common.Hash, error)
+		contract common.Hash
+		expected string
+	}{
+		{
+			"RegisterEnclaveKey",
+			func() (common.Hash, error) { return lcptypes.ComputeEIP712RegisterEnclaveKeyHash(`{"id":"1"}`) },
+			contractRegisterEnclaveKeyDigest(&attestation{Type: AttestationTypeIAS, report: `{"id":"1"}`}),
+			"0x1c51b31deff259470247cfbbc57e2ece52e185bf85e2c276bdb92de250b7fb09",
+		},
+		{
+			"DCAPRegisterEnclaveKey",
+			func() (common.Hash, error) {
+				return lcptypes.ComputeEIP712DCAPRegisterEnclaveKeyHash([]byte{1, 2, 3, 4})
+			},
+			contractRegisterEnclaveKeyDigest(&attestation{Type: AttestationTypeDCAP, dcap: &lcptypes.DCAPAttestation{Quote: []byte{1, 2, 3, 4}}}),
+			"0xdc61af9f583dafe4a8e04e1f4b96a05f38e0b703ebd84d4c3a9613fe15087e98",
+		},
+		{
+			"UpdateOperators",
+			func() (common.Hash, error) {
+				bz, err := lcptypes.ComputeEIP712UpdateOperators(1, contract, salt, "lcp-client-0", 1, operators, 1, 2)
+				return crypto.Keccak256Hash(bz), err
+			},
+			contractEIP712Digest(domainSeparator, contractUpdateOperatorsHash("lcp-client-0", 1, operators, 1, 2)),
+			"0xef5e903f4532801c92506ba814c7989658d30c22f5120e0fa96440e8361a308b",
+		},
+		{
+			"UpdateClientParams",
+			func() (common.Hash, error) {
+				bz, err := lcptypes.ComputeEIP712UpdateClientParams(1, contract, salt, "lcp-client-0", 2, statuses, advisories, 86400)
+				return crypto.Keccak256Hash(bz), err
+			},
+			contractEIP712Digest(domainSeparator, contractUpdateClientParamsHash("lcp-client-0", 2, statuses, advisories, 86400)),
+			"0xeb644827b901b21d9747388837064edf504fb5d36dfaccc3e97d16295c2f1675",
+		},
+		{
+			"UpdateClientParams with empty arrays",
+			func() (common.Hash, error) {
+				bz, err := lcptypes.ComputeEIP712UpdateClientParams(1, contract, salt, "lcp-client-0", 3, nil, nil, 0)
+				return crypto.Keccak256Hash(bz), err
+			},
+			contractEIP712Digest(domainSeparator, contractUpdateClientParamsHash("lcp-client-0", 3, nil, nil, 0)),
+			"0x183121c2654c8fe3de8edb4ccc962a016f29d4c7c0303061686f4f6da15930e0",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			digest, err := c.lcptypes()
+			require.NoError(t, err)
+			require.Equal(t, c.expected, digest.Hex())
+			require.Equal(t, c.expected, c.contract.Hex())
+		})
+	}
+}
+
+func TestCheckEIP712Digest(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	pr.path = &core.PathEnd{ClientID: testutil.DefaultClientID}
+	pr.config.OperatorsEip712Params = &ProverConfig_OperatorsEip712EvmChainParams{
+		OperatorsEip712EvmChainParams: &EIP712EVMChainParams{ChainId: 1, VerifyingContractAddress: "0x2F5703804E29F4252FA9405B8D357220d11b3bd9"},
+	}
+
+	newOperators := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+	digest, err := pr.ComputeEIP712UpdateOperatorsHash(1, newOperators, 1, 2)
+	require.NoError(err)
+	require.NoError(pr.checkEIP712Digest("UpdateOperators", digest, pr.contractUpdateOperatorsDigest(1, newOperators, 1, 2)))
+	digest, err = pr.ComputeEIP712UpdateClientParamsHash(1, []string{lcptypes.QuoteSwHardeningNeeded}, nil, 60)
+	require.NoError(err)
+	require.NoError(pr.checkEIP712Digest("UpdateClientParams", digest, pr.contractUpdateClientParamsDigest(1, []string{lcptypes.QuoteSwHardeningNeeded}, nil, 60)))
+
+	// the digest of another nonce is rejected before the submission
+	contractDigest := pr.contractUpdateClientParamsDigest(2, []string{lcptypes.QuoteSwHardeningNeeded}, nil, 60)
+	err = pr.checkEIP712Digest("UpdateClientParams", digest, contractDigest)
+	var mismatch *EIP712DigestMismatchError
+	require.True(errors.As(err, &mismatch))
+	require.Equal(digest, mismatch.Digest)
+	require.Equal(contractDigest, mismatch.ContractDigest)
+	require.ErrorContains(err, digest.Hex())
+	require.ErrorContains(err, contractDigest.Hex())
+
+	// the contract does not verify the signatures on Cosmos
+	pr.config.OperatorsEip712Params = &ProverConfig_OperatorsEip712CosmosChainParams{
+		OperatorsEip712CosmosChainParams: &EIP712CosmosChainParams{ChainId: "ibc-0", Prefix: ibcexported.StoreKey},
+	}
+	require.NoError(pr.checkEIP712Digest("UpdateClientParams", digest, contractDigest))
+}
@@ -0,0 +1,333 @@
+package relay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/log"
+)
+
+const (
+	// ekStoreFile holds the enclave key infos and the ELC client ID in a single JSON document
+	ekStoreFile = "enclave_keys"
+	// ekStoreNamespacesDir is the directory of the stores namespaced by the paths sharing the home directory
+	ekStoreNamespacesDir = "paths"
+)
+
+// EKStore persists the enclave key infos of the prover and the ELC client ID.
+// Each method applies its change atomically, so a crash never leaves a partially updated state behind.
+type EKStore interface {
+	// GetFinalized returns the finalized enclave key info, or nil if it does not exist
+	GetFinalized() (*finalizedEKI, error)
+	// SetFinalized replaces the finalized enclave key info. It is removed if `feki` is nil.
+	SetFinalized(feki *finalizedEKI) error
+	// GetUnfinalized returns the outstanding registrations in the order they were first saved
+	GetUnfinalized() ([]unfinalizedEKI, error)
+	// SetUnfinalized replaces the outstanding registrations
+	SetUnfinalized(uekis []unfinalizedEKI) error
+	// DeleteUnfinalized removes all the outstanding registrations
+	DeleteUnfinalized() error
+	// Finalize replaces the finalized enclave key info and removes all the outstanding registrations in a single write
+	Finalize(feki *finalizedEKI) error
+	// GetELCClientID returns the persisted ELC client ID, or empty if it does not exist
+	GetELCClientID() (string, error)
+	// SetELCClientID persists the ELC client ID
+	SetELCClientID(id string) error
+}
+
+// ekStoreDocument is the content of ekStoreFile
+type ekStoreDocument struct {
+	Finalized   *finalizedEKI    `json:"finalized,omitempty"`
+	Unfinalized []unfinalizedEKI `json:"unfinalized,omitempty"`
+	ELCClientID string           `json:"elc_client_id,omitempty"`
+}
+
+// fileEKStore is an EKStore that keeps the whole state in a single file in `dir`.
+// The file is replaced by writing a temporary file and renaming it, so it always contains either the old or the new state.
+// If the file does not exist, the state is migrated from the files saved by the older versions.
+//
+// A namespaced store keeps the enclave key infos of a path in its own file, and the ELC client ID in the shared store.
+// If its file does not exist, the enclave key infos are inherited from the shared store, and they are moved into its file by the first write.
+type fileEKStore struct {
+	dir    string
+	logger *log.RelayLogger
+	// the store shared by the paths. It is nil if this store is the shared one.
+	shared *fileEKStore
+}
+
+var _ EKStore = (*fileEKStore)(nil)
+
+func newFileEKStore(dir string, logger *log.RelayLogger) *fileEKStore {
+	return &fileEKStore{dir: dir, logger: logger}
+}
+
+func newNamespacedFileEKStore(dir string, shared *fileEKStore) *fileEKStore {
+	return &fileEKStore{dir: dir, logger: shared.logger, shared: shared}
+}
+
+// ekStore returns the store of the current path in the db directory, which is switched during the rehearsal
+func (pr *Prover) ekStore() EKStore {
+	return pr.newEKStore()
+}
+
+// newEKStore returns the store namespaced by the current path if it is determined, otherwise the store shared by the paths
+func (pr *Prover) newEKStore() *fileEKStore {
+	shared := newFileEKStore(pr.dbPath(), pr.getLogger())
+	if ns := pr.ekStoreNamespace(); ns != "" {
+		return newNamespacedFileEKStore(filepath.Join(pr.dbPath(), ns), shared)
+	}
+	return shared
+}
+
+// ekStoreNamespace returns the directory of the store of the current path relative to the db directory.
+// The paths against the same LCP service are distinguished by the counterparty chain ID, the counterparty client ID and the ELC client ID.
+// It returns empty if any of them is not determined yet, e.g. the relay info is not set or the ELC client ID is not generated yet.
+func (pr *Prover) ekStoreNamespace() string {
+	elcClientID := pr.GetELCClientID()
+	if pr.counterpartyPath == nil || pr.counterpartyPath.ChainID == "" || pr.counterpartyPath.ClientID == "" || elcClientID == "" {
+		return ""
+	}
+	return filepath.Join(
+		ekStoreNamespacesDir,
+		escapePathComponent(pr.counterpartyPath.ChainID),
+		escapePathComponent(pr.counterpartyPath.ClientID),
+		escapePathComponent(elcClientID),
+	)
+}
+
+// escapePathComponent escapes `s` to be a single component of a file path
+func escapePathComponent(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), ".", "%2E")
+}
+
+func (s *fileEKStore) path() string {
+	return filepath.Join(s.dir, ekStoreFile)
+}
+
+func (s *fileEKStore) GetFinalized() (*finalizedEKI, error) {
+	doc, _, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return doc.Finalized, nil
+}
+
+func (s *fileEKStore) SetFinalized(feki *finalizedEKI) error {
+	return s.update(func(doc *ekStoreDocument) {
+		doc.Finalized = feki
+	})
+}
+
+func (s *fileEKStore) GetUnfinalized() ([]unfinalizedEKI, error) {
+	doc, _, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return doc.Unfinalized, nil
+}
+
+func (s *fileEKStore) SetUnfinalized(uekis []unfinalizedEKI) error {
+	return s.update(func(doc *ekStoreDocument) {
+		doc.Unfinalized = uekis
+	})
+}
+
+func (s *fileEKStore) DeleteUnfinalized() error {
+	return s.SetUnfinalized(nil)
+}
+
+func (s *fileEKStore) Finalize(feki *finalizedEKI) error {
+	return s.update(func(doc *ekStoreDocument) {
+		doc.Finalized, doc.Unfinalized = feki, nil
+	})
+}
+
+func (s *fileEKStore) GetELCClientID() (string, error) {
+	if s.shared != nil {
+		return s.shared.GetELCClientID()
+	}
+	doc, _, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	return doc.ELCClientID, nil
+}
+
+func (s *fileEKStore) SetELCClientID(id string) error {
+	if s.shared != nil {
+		return s.shared.SetELCClientID(id)
+	}
+	return s.update(func(doc *ekStoreDocument) {
+		doc.ELCClientID = id
+	})
+}
+
+// load reads the state from the file. If the file does not exist, the state is inherited from the shared store if this store is namespaced,
+// otherwise it is migrated from the legacy files. It returns true if any enclave key info is inherited.
+func (s *fileEKStore) load() (*ekStoreDocument, bool, error) {
+	path := s.path()
+	bz, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if s.shared != nil {
+			return s.inherit()
+		}
+		doc, err := s.migrate()
+		return doc, false, err
+	} else if err != nil {
+		return nil, false, fmt.Errorf("failed to read file: path=%v %w", path, err)
+	}
+	var doc ekStoreDocument
+	if err := json.Unmarshal(bz, &doc); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal enclave key store: path=%v %w", path, err)
+	}
+	return &doc, false, nil
+}
+
+// inherit returns the enclave key infos in the shared store, which were saved before the state was namespaced by the path
+func (s *fileEKStore) inherit() (*ekStoreDocument, bool, error) {
+	shared, _, err := s.shared.load()
+	if err != nil {
+		return nil, false, err
+	}
+	doc := &ekStoreDocument{Finalized: shared.Finalized, Unfinalized: shared.Unfinalized}
+	return doc, doc.Finalized != nil || len(doc.Unfinalized) > 0, nil
+}
+
+func (s *fileEKStore) update(f func(doc *ekStoreDocument)) error {
+	doc, inherited, err := s.load()
+	if err != nil {
+		return err
+	}
+	f(doc)
+	if err := s.write(doc); err != nil {
+		return err
+	}
+	if inherited {
+		// the inherited enclave key infos are removed from the shared store so that another path does not take them over.
+		// If it fails, they are only inherited again by a path that has no store yet.
+		s.logger.Info("move the enclave key infos from the shared store into the store of the path", "from", s.shared.path(), "to", s.path())
+		if err := s.shared.update(func(doc *ekStoreDocument) {
+			doc.Finalized, doc.Unfinalized = nil, nil
+		}); err != nil {
+			s.logger.Warn("failed to remove the moved enclave key infos from the shared store", "path", s.shared.path(), "error", err)
+		}
+	}
+	return nil
+}
+
+// write replaces the file atomically. If it is interrupted, the temporary file is left and overwritten by the next write.
+func (s *fileEKStore) write(doc *ekStoreDocument) error {
+	bz, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal enclave key store: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create directory: path=%v %w", s.dir, err)
+	}
+	path := s.path()
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary file: path=%v %w", tmp, err)
+	}
+	if _, err := f.Write(bz); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write enclave key store: path=%v %w", tmp, err)
+	} else if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync enclave key store: path=%v %w", tmp, err)
+	} else if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close enclave key store: path=%v %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to rename enclave key store: path=%v %w", path, err)
+	}
+	return nil
+}
+
+// migrate moves the state in the files saved by the older versions into the store file.
+// The legacy files are removed after the store file is written, so an interrupted migration is retried from the start.
+// If no legacy file exists, the empty state is returned without writing the store file.
+func (s *fileEKStore) migrate() (*ekStoreDocument, error) {
+	var (
+		doc    ekStoreDocument
+		legacy []string
+	)
+	read := func(name string) ([]byte, error) {
+		path := filepath.Join(s.dir, name)
+		bz, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read file: path=%v %w", path, err)
+		}
+		legacy = append(legacy, path)
+		return bz, nil
+	}
+
+	if bz, err := read(lastFinalizedEnclaveKeyInfoFile); err != nil {
+		return nil, err
+	} else if bz != nil {
+		var feki finalizedEKI
+		if err := json.Unmarshal(bz, &feki); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal enclave key info: file=%v %w", lastFinalizedEnclaveKeyInfoFile, err)
+		}
+		if feki.Info == nil {
+			// the format of the older versions
+			feki.Info = new(enclave.EnclaveKeyInfo)
+			if err := json.Unmarshal(bz, feki.Info); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal enclave key info: file=%v %w", lastFinalizedEnclaveKeyInfoFile, err)
+			}
+		}
+		doc.Finalized = &feki
+	}
+	// the record of the single unfinalized registration is the oldest one
+	if bz, err := read(lastUnfinalizedEnclaveKeyInfoFile); err != nil {
+		return nil, err
+	} else if bz != nil {
+		var ueki unfinalizedEKI
+		if err := json.Unmarshal(bz, &ueki); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal unfinalized enclave key info: file=%v %w", lastUnfinalizedEnclaveKeyInfoFile, err)
+		}
+		doc.Unfinalized = append(doc.Unfinalized, ueki)
+	}
+	if bz, err := read(unfinalizedEnclaveKeyInfosFile); err != nil {
+		return nil, err
+	} else if bz != nil {
+		var uekis []unfinalizedEKI
+		if err := json.Unmarshal(bz, &uekis); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal unfinalized enclave key infos: file=%v %w", unfinalizedEnclaveKeyInfosFile, err)
+		}
+		doc.Unfinalized = append(doc.Unfinalized, uekis...)
+	}
+	if bz, err := read(elcClientIDFile); err != nil {
+		return nil, err
+	} else if bz != nil {
+		doc.ELCClientID = strings.TrimSpace(string(bz))
+	}
+
+	if len(legacy) == 0 {
+		return &doc, nil
+	}
+	s.logger.Info("migrate the legacy files into the enclave key store", "path", s.path(), "files", legacy)
+	if err := s.write(&doc); err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, path := range legacy {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("failed to remove file: path=%v %w", path, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		// the migrated state is used because the store file takes precedence over the legacy files
+		s.logger.Warn("failed to remove the migrated legacy files", "error", err)
+	}
+	return &doc, nil
+}
@@ -0,0 +1,196 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileEKStoreMigration(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.homePath = t.TempDir()
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+
+	writeJSON := func(name string, v any) {
+		bz, err := json.Marshal(v)
+		require.NoError(err)
+		require.NoError(os.WriteFile(filepath.Join(pr.dbPath(), name), bz, 0600))
+	}
+	msgIDBytes := func(msgID *tendermint.MsgID) []byte {
+		bz, err := pr.codec.MarshalInterface(msgID)
+		require.NoError(err)
+		return bz
+	}
+	msgA := &tendermint.MsgID{TxHash: "0xaa", MsgIndex: 0}
+	msgB := &tendermint.MsgID{TxHash: "0xbb", MsgIndex: 0}
+	// the files saved by the older versions
+	writeJSON(lastFinalizedEnclaveKeyInfoFile, &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}})
+	writeJSON(lastUnfinalizedEnclaveKeyInfoFile, unfinalizedEKI{Info: &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x02}}, MsgIDBytes: msgIDBytes(msgA)})
+	writeJSON(unfinalizedEnclaveKeyInfosFile, []unfinalizedEKI{{Info: &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x03}}, MsgIDBytes: msgIDBytes(msgB)}})
+	require.NoError(os.WriteFile(filepath.Join(pr.dbPath(), elcClientIDFile), []byte("lcp-elc-0"), 0600))
+	// the migration was interrupted while writing the store file
+	require.NoError(os.WriteFile(pr.ekStoreFilePath()+".tmp", []byte(`{"finalized":{"in`), 0600))
+
+	require.NoError(pr.loadPersistedELCClientID(context.TODO()))
+	require.Equal("lcp-elc-0", pr.GetELCClientID())
+	for _, name := range []string{lastFinalizedEnclaveKeyInfoFile, lastUnfinalizedEnclaveKeyInfoFile, unfinalizedEnclaveKeyInfosFile, elcClientIDFile} {
+		_, err := os.Stat(filepath.Join(pr.dbPath(), name))
+		require.True(os.IsNotExist(err), name)
+	}
+
+	finalized, err := pr.loadLastFinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal([]byte{0x01}, finalized.EnclaveKeyAddress)
+	// the record of the single unfinalized registration is the oldest one
+	records, err := pr.loadUnfinalizedEnclaveKeys(context.TODO())
+	require.NoError(err)
+	require.Len(records, 2)
+	require.Equal(msgA.String(), records[0].msgID.String())
+	require.Equal(msgB.String(), records[1].msgID.String())
+
+	// the legacy files written after the migration are ignored
+	writeJSON(lastFinalizedEnclaveKeyInfoFile, &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x04}})
+	finalized, err = pr.loadLastFinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal([]byte{0x01}, finalized.EnclaveKeyAddress)
+}
+
+func TestLoadEKIAfterPartialWrite(t *testing.T) {
+	msgID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	setup := func(t *testing.T) (*Prover, *mockCounterparty) {
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = t.TempDir()
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+		now := uint64(time.Now().Unix())
+		require.NoError(t, pr.saveFinalizedEnclaveKeyInfo(context.TODO(), &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}, AttestationTime: now}))
+		require.NoError(t, pr.saveUnfinalizedEnclaveKeyInfo(context.TODO(), &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x02}, AttestationTime: now}, msgID, clienttypes.Height{}))
+		cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+		cp.msgResults[msgID.String()] = mockMsgResult{height: clienttypes.NewHeight(0, 10), success: true}
+		return pr, cp
+	}
+
+	t.Run("interrupted before rename", func(t *testing.T) {
+		require := require.New(t)
+		pr, cp := setup(t)
+		// the crash left a partially written temporary file, and the store file keeps the previous state
+		tmp := pr.ekStoreFilePath() + ".tmp"
+		require.NoError(os.WriteFile(tmp, []byte(`{"finalized":{"info":{"enclave_key_add`), 0600))
+
+		updateNeeded, err := pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+		require.NoError(err)
+		require.False(updateNeeded)
+		require.Equal([]byte{0x02}, pr.activeEnclaveKey.EnclaveKeyAddress)
+		require.Nil(pr.unfinalizedMsgID)
+
+		// the finalized key and the removal of the unfinalized one are saved together
+		finalized, err := pr.loadLastFinalizedEnclaveKey(context.TODO())
+		require.NoError(err)
+		require.Equal([]byte{0x02}, finalized.EnclaveKeyAddress)
+		_, _, _, err = pr.loadLastUnfinalizedEnclaveKey(context.TODO())
+		require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+		_, err = os.Stat(tmp)
+		require.True(os.IsNotExist(err))
+	})
+
+	t.Run("corrupted store", func(t *testing.T) {
+		require := require.New(t)
+		pr, cp := setup(t)
+		path := pr.ekStoreFilePath()
+		bz, err := os.ReadFile(path)
+		require.NoError(err)
+		require.NoError(os.WriteFile(path, bz[:len(bz)/2], 0600))
+
+		// a new key must not be registered while the outstanding registration is unknown
+		updateNeeded, err := pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+		require.ErrorContains(err, path)
+		require.False(updateNeeded)
+		require.Nil(pr.activeEnclaveKey)
+		require.Equal(0, cp.getMsgResultCalls)
+	})
+}
+
+func TestEKStoreNamespacedByPath(t *testing.T) {
+	require := require.New(t)
+	homePath := t.TempDir()
+	newProver := func(elcClientID string) *Prover {
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = homePath
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.ElcClientId = elcClientID
+		require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr
+	}
+	msgA := &tendermint.MsgID{TxHash: "0xaa", MsgIndex: 0}
+	msgB := &tendermint.MsgID{TxHash: "0xbb", MsgIndex: 0}
+
+	// the state saved before the paths share the home directory
+	legacy := newProver("elc-client-a")
+	require.NoError(legacy.saveFinalizedEnclaveKeyInfo(context.TODO(), &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}}))
+
+	prA, prB := newProver("elc-client-a"), newProver("elc-client-b")
+	require.NoError(prA.SetRelayInfo(&core.PathEnd{ChainID: "origin"}, nil, &core.PathEnd{ChainID: "counterparty", ClientID: "lcp-client-0"}))
+	require.NoError(prB.SetRelayInfo(&core.PathEnd{ChainID: "origin"}, nil, &core.PathEnd{ChainID: "counterparty", ClientID: "lcp-client-1"}))
+	require.NotEqual(prA.ekStoreFilePath(), prB.ekStoreFilePath())
+
+	// the legacy state is inherited and moved into the store of the path by the first write
+	finalized, err := prA.loadLastFinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal([]byte{0x01}, finalized.EnclaveKeyAddress)
+	require.NoError(prA.saveUnfinalizedEnclaveKeyInfo(context.TODO(), &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x02}}, msgA, clienttypes.Height{}))
+	_, err = os.Stat(prA.ekStoreFilePath())
+	require.NoError(err)
+	_, err = legacy.loadLastFinalizedEnclaveKey(context.TODO())
+	require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+
+	// the other path does not see the keys of the first one
+	_, err = prB.loadLastFinalizedEnclaveKey(context.TODO())
+	require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+	_, _, _, err = prB.loadLastUnfinalizedEnclaveKey(context.TODO())
+	require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+	require.NoError(prB.saveUnfinalizedEnclaveKeyInfo(context.TODO(), &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x0b}}, msgB, clienttypes.Height{}))
+	require.NoError(prB.finalizeEnclaveKeyInfo(context.TODO(), &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x0b}}))
+
+	finalized, err = prA.loadLastFinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal([]byte{0x01}, finalized.EnclaveKeyAddress)
+	eki, msgID, _, err := prA.loadLastUnfinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal([]byte{0x02}, eki.EnclaveKeyAddress)
+	require.Equal(msgA.String(), msgID.String())
+	finalized, err = prB.loadLastFinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal([]byte{0x0b}, finalized.EnclaveKeyAddress)
+	_, _, _, err = prB.loadLastUnfinalizedEnclaveKey(context.TODO())
+	require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+
+	// the generated ELC client ID is kept in the shared store because it is loaded before the path is determined
+	prC := newProver("")
+	require.NoError(prC.SetRelayInfo(&core.PathEnd{ChainID: "origin"}, nil, &core.PathEnd{ChainID: "counterparty", ClientID: "lcp-client-2"}))
+	id, err := prC.ensureELCClientID(context.TODO())
+	require.NoError(err)
+	require.NotEqual(prC.sharedEKStoreFilePath(), prC.ekStoreFilePath())
+	restarted := newProver("")
+	require.NoError(restarted.loadPersistedELCClientID(context.TODO()))
+	require.Equal(id, restarted.GetELCClientID())
+}
+
+func TestEscapePathComponent(t *testing.T) {
+	require.Equal(t, "ibc-0", escapePathComponent("ibc-0"))
+	require.Equal(t, "%2E%2E", escapePathComponent(".."))
+	require.Equal(t, "a%2Fb", escapePathComponent("a/b"))
+}
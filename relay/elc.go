@@ -0,0 +1,37 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrELCOriginClientTypeChanged is returned when the type of the ELC's origin client state differs from the recorded one
+var ErrELCOriginClientTypeChanged = errors.New("ELC origin client type changed")
+
+// checkELCOriginClientType compares the type URL of the ELC's origin client state with the recorded one.
+// If no type URL is recorded yet, the given one is recorded.
+// If they differ, it returns ErrELCOriginClientTypeChanged or only emits a warning according to the configured severity.
+func (pr *Prover) checkELCOriginClientType(ctx context.Context, elcClientID string, typeURL string) error {
+	types, err := pr.loadELCOriginClientTypes(ctx)
+	if err != nil {
+		return err
+	}
+	recorded, ok := types[elcClientID]
+	if !ok {
+		return pr.saveELCOriginClientType(ctx, elcClientID, typeURL)
+	} else if recorded == typeURL {
+		return nil
+	}
+	pr.getLogger().Warn(
+		"!!! the origin client type of the ELC has changed unexpectedly !!!",
+		"elc_client_id", elcClientID,
+		"recorded_type_url", recorded,
+		"current_type_url", typeURL,
+		"severity", pr.config.GetELCClientTypeMismatchSeverity(),
+	)
+	if pr.config.GetELCClientTypeMismatchSeverity() == SeverityWarn {
+		return nil
+	}
+	return fmt.Errorf("%w: elc_client_id=%v recorded=%v current=%v", ErrELCOriginClientTypeChanged, elcClientID, recorded, typeURL)
+}
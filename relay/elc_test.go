@@ -0,0 +1,160 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type mockELCQueryClient struct {
+	elc.QueryClient
+	clients map[string]*elc.QueryClientResponse
+}
+
+func (c mockELCQueryClient) Client(ctx context.Context, in *elc.QueryClientRequest, opts ...grpc.CallOption) (*elc.QueryClientResponse, error) {
+	res, ok := c.clients[in.ClientId]
+	if !ok {
+		return &elc.QueryClientResponse{Found: false}, nil
+	}
+	return res, nil
+}
+
+func TestCheckELCOriginClientType(t *testing.T) {
+	const (
+		elcClientID = "07-tendermint-0"
+		typeURL     = "/ibc.lightclients.tendermint.v1.ClientState"
+		newTypeURL  = "/ibc.lightclients.tendermint.v2.ClientState"
+	)
+	require := require.New(t)
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.homePath = t.TempDir()
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+
+	service := mockELCQueryClient{clients: map[string]*elc.QueryClientResponse{
+		elcClientID: {
+			Found:          true,
+			ClientState:    &codectypes.Any{TypeUrl: typeURL},
+			ConsensusState: &codectypes.Any{},
+		},
+	}}
+	pr.lcpServiceClient.ELCQueryClient = service
+
+	// the type URL is recorded at the first query
+	require.NoError(pr.checkELCOriginClientType(context.TODO(), elcClientID, typeURL))
+	res, err := pr.doQueryELC(elcClientID)
+	require.NoError(err)
+	require.Equal(typeURL, res.OriginClientType.Recorded)
+	require.Equal(typeURL, res.OriginClientType.Current)
+
+	// simulate a type change in the service
+	service.clients[elcClientID].ClientState.TypeUrl = newTypeURL
+	_, err = pr.updateELC(elcClientID, false, nil)
+	require.ErrorIs(err, ErrELCOriginClientTypeChanged)
+	res, err = pr.doQueryELC(elcClientID)
+	require.NoError(err)
+	require.Equal(typeURL, res.OriginClientType.Recorded)
+	require.Equal(newTypeURL, res.OriginClientType.Current)
+
+	// only a warning is emitted if the severity is "warn"
+	pr.config.ElcClientTypeMismatchSeverity = SeverityWarn
+	require.NoError(pr.checkELCOriginClientType(context.TODO(), elcClientID, newTypeURL))
+}
+
+// mockCatchUpOriginProver is the prover of an origin chain which returns `headers` headers for the update
+type mockCatchUpOriginProver struct {
+	mockSelfTestOriginProver
+	headers int
+}
+
+func (p mockCatchUpOriginProver) SetupHeadersForUpdate(counterparty core.FinalityAwareChain, latestFinalizedHeader core.Header) ([]core.Header, error) {
+	return newTestOriginHeaders(latestFinalizedHeader.GetHeight(), p.headers)
+}
+
+// mockFailingAtLCPService is the LCP service whose `failAt`-th UpdateClient fails
+type mockFailingAtLCPService struct {
+	*mockLCPService
+	failAt int
+	calls  int
+}
+
+func (s *mockFailingAtLCPService) UpdateClient(ctx context.Context, in *elc.MsgUpdateClient, opts ...grpc.CallOption) (*elc.MsgUpdateClientResponse, error) {
+	s.calls++
+	if s.calls == s.failAt {
+		return nil, errors.New("header verification failed")
+	}
+	return s.mockLCPService.UpdateClient(ctx, in, opts...)
+}
+
+func TestUpdateELCProgress(t *testing.T) {
+	const (
+		elcClientID = "07-tendermint-0"
+		numHeaders  = 5
+	)
+	var cases = []struct {
+		name string
+		// if not zero, the update of this header fails
+		failAt int
+	}{
+		{"all applied", 0},
+		{"interrupted", 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			key, err := crypto.GenerateKey()
+			require.NoError(err)
+			service := &mockFailingAtLCPService{
+				mockLCPService: &mockLCPService{t: t, key: key, clients: map[string]*lcptypes.ClientState{
+					elcClientID: {LatestHeight: clienttypes.NewHeight(0, 1)},
+				}},
+				failAt: c.failAt,
+			}
+			pr := newTestProver(t)
+			pr.codec = newTestCodec()
+			pr.homePath = t.TempDir()
+			pr.originChain = &mockCounterparty{chainID: "origin"}
+			pr.originProver = mockCatchUpOriginProver{mockSelfTestOriginProver: mockSelfTestOriginProver{latestHeight: clienttypes.NewHeight(0, 100)}, headers: numHeaders}
+			pr.lcpServiceClient = LCPServiceClient{ELCMsgClient: service, ELCQueryClient: service}
+			pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: crypto.PubkeyToAddress(key.PublicKey).Bytes()}
+			require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+
+			var reported []UpdateELCProgress
+			res, err := pr.doUpdateELC(elcClientID, func(p UpdateELCProgress) {
+				reported = append(reported, p)
+			})
+			if c.failAt != 0 {
+				require.ErrorContains(err, "header verification failed")
+				// only the applied headers are reported
+				require.Len(reported, c.failAt-1)
+			} else {
+				require.NoError(err)
+				require.Len(reported, numHeaders)
+				require.Equal(numHeaders, res.HeadersApplied)
+				require.Len(res.Messages, numHeaders)
+				require.NotEmpty(res.WallTime)
+			}
+			for i, p := range reported {
+				require.Equal(i+1, p.Applied)
+				require.Equal(numHeaders, p.Total)
+				require.Equal(clienttypes.NewHeight(0, uint64(i+2)), p.Height)
+				require.NotEmpty(p.StateID)
+				if i > 0 {
+					require.GreaterOrEqual(p.Elapsed, reported[i-1].Elapsed)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MinELCClientIDLength and MaxELCClientIDLength are the bounds of the length of an ELC client ID.
+	// They are the same as the bounds of the client identifiers of ICS-24.
+	MinELCClientIDLength = 9
+	MaxELCClientIDLength = 64
+
+	// generatedELCClientIDPrefix is the prefix of the ELC client IDs generated by doCreateELC
+	generatedELCClientIDPrefix = "lcp-elc-"
+)
+
+// reservedELCClientIDPrefixes are the prefixes of the ELC client IDs which the prover assigns by itself.
+// The LCP service provides no API to query its reserved prefixes, so only the prefixes of the prover are checked.
+var reservedELCClientIDPrefixes = []string{
+	selfTestELCClientIDPrefix,
+}
+
+// ErrInvalidELCClientID is returned if an ELC client ID does not follow the grammar
+var ErrInvalidELCClientID = errors.New("invalid ELC client ID")
+
+// InvalidELCClientIDError describes why an ELC client ID is invalid.
+// It matches ErrInvalidELCClientID with errors.Is.
+type InvalidELCClientIDError struct {
+	ClientID string
+	// the byte index of the offending character. It is -1 if the error is not specific to a character.
+	Index  int
+	Reason string
+}
+
+func (e *InvalidELCClientIDError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("%v: %v: elc_client_id=%q", ErrInvalidELCClientID, e.Reason, e.ClientID)
+	}
+	r, _ := utf8.DecodeRuneInString(e.ClientID[e.Index:])
+	return fmt.Sprintf("%v: %v: index=%v character=%q elc_client_id=%q", ErrInvalidELCClientID, e.Reason, e.Index, r, e.ClientID)
+}
+
+func (e *InvalidELCClientIDError) Is(target error) bool {
+	return target == ErrInvalidELCClientID
+}
+
+// isELCClientIDChar returns true if `c` is allowed in an ELC client ID.
+// The charset is the one of the identifiers of ICS-24: [a-zA-Z0-9._+\-#[\]<>]
+func isELCClientIDChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("._+-#[]<>", c) >= 0
+}
+
+// validateELCClientID validates `id` against the grammar of the ELC client IDs accepted by the LCP service
+func validateELCClientID(id string) error {
+	if l := len(id); l < MinELCClientIDLength || l > MaxELCClientIDLength {
+		return &InvalidELCClientIDError{ClientID: id, Index: -1, Reason: fmt.Sprintf("the length must be in the range [%v, %v], but got %v", MinELCClientIDLength, MaxELCClientIDLength, l)}
+	}
+	for i := 0; i < len(id); i++ {
+		if !isELCClientIDChar(id[i]) {
+			return &InvalidELCClientIDError{ClientID: id, Index: i, Reason: "disallowed character"}
+		}
+	}
+	return nil
+}
+
+// validateUserELCClientID validates `id` given by the user, which must not have the reserved prefixes in addition to the grammar
+func validateUserELCClientID(id string) error {
+	if err := validateELCClientID(id); err != nil {
+		return err
+	}
+	for _, prefix := range reservedELCClientIDPrefixes {
+		if strings.HasPrefix(id, prefix) {
+			return &InvalidELCClientIDError{ClientID: id, Index: -1, Reason: fmt.Sprintf("the prefix %q is reserved", prefix)}
+		}
+	}
+	return nil
+}
+
+// validateElcClientID validates ElcClientId if it is set
+func (pc ProverConfig) validateElcClientID() error {
+	if pc.ElcClientId == "" {
+		return nil
+	}
+	if err := validateUserELCClientID(pc.ElcClientId); err != nil {
+		return fmt.Errorf("ElcClientId is invalid: %w", err)
+	}
+	return nil
+}
+
+// newELCClientID generates an ELC client ID which follows the grammar
+func newELCClientID(now time.Time) string {
+	return fmt.Sprintf("%s%d", generatedELCClientIDPrefix, now.UnixNano())
+}
+
+// ErrELCClientIDConflict is returned if ElcClientId in the config differs from the ID persisted in the home directory
+var ErrELCClientIDConflict = errors.New("the ELC client ID in the config conflicts with the persisted one")
+
+// GetELCClientID returns the ID of the ELC client that the prover uses.
+// It is ElcClientId in the config if it is set, otherwise the ID generated and persisted by the prover.
+// It is empty if neither exists, i.e. the ELC client has not been created yet.
+func (pr *Prover) GetELCClientID() string {
+	if pr.config.ElcClientId != "" {
+		return pr.config.ElcClientId
+	}
+	return pr.elcClientID
+}
+
+// loadPersistedELCClientID loads the ELC client ID persisted in the home directory.
+// It returns ErrELCClientIDConflict if ElcClientId in the config is set and differs from it.
+func (pr *Prover) loadPersistedELCClientID(ctx context.Context) error {
+	id, err := pr.loadELCClientID(ctx)
+	if err != nil {
+		return err
+	} else if id != "" && pr.config.ElcClientId != "" && id != pr.config.ElcClientId {
+		return fmt.Errorf("%w: config=%q persisted=%q path=%v; remove elc_client_id from the config or from the persisted store to use the other",
+			ErrELCClientIDConflict, pr.config.ElcClientId, id, pr.sharedEKStoreFilePath())
+	}
+	pr.elcClientID = id
+	return nil
+}
+
+// ensureELCClientID returns the ID of the ELC client that the prover uses.
+// If the ID is not determined yet, a new ID is generated and persisted before the client is created,
+// so that the retries of a failed creation reuse the same ID.
+func (pr *Prover) ensureELCClientID(ctx context.Context) (string, error) {
+	if id := pr.GetELCClientID(); id != "" {
+		return id, nil
+	}
+	id := newELCClientID(time.Now())
+	if err := pr.saveELCClientID(ctx, id); err != nil {
+		return "", err
+	}
+	pr.elcClientID = id
+	return id, nil
+}
@@ -0,0 +1,159 @@
+package relay
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateELCClientID(t *testing.T) {
+	var cases = []struct {
+		id  string
+		err string
+	}{
+		{"07-tendermint-0", ""},
+		{"elc-client-0", ""},
+		{"ethereum.mainnet_1+[a]<b>#c", ""},
+		{strings.Repeat("a", MaxELCClientIDLength), ""},
+		{"", "the length must be in the range [9, 64], but got 0"},
+		{"07-tm-0", "the length must be in the range [9, 64], but got 7"},
+		{strings.Repeat("a", MaxELCClientIDLength+1), "the length must be in the range [9, 64], but got 65"},
+		{"07-tendermint 0", `disallowed character: index=13 character=' '`},
+		{"07-tendermint/0", `disallowed character: index=13 character='/'`},
+		{"07:tendermint-0", `disallowed character: index=2 character=':'`},
+		{"07-tendermint-é", `disallowed character: index=14 character='é'`},
+	}
+	for _, c := range cases {
+		t.Run(c.id, func(t *testing.T) {
+			err := validateELCClientID(c.id)
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrInvalidELCClientID)
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestValidateUserELCClientID(t *testing.T) {
+	require := require.New(t)
+	require.NoError(validateUserELCClientID("07-tendermint-0"))
+	// the self-test IDs follow the grammar, but the users cannot choose them
+	id := newSelfTestELCClientID(time.Now())
+	require.NoError(validateELCClientID(id))
+	err := validateUserELCClientID(id)
+	require.ErrorIs(err, ErrInvalidELCClientID)
+	require.ErrorContains(err, `the prefix "lcp-selftest-" is reserved`)
+
+	require.NoError(ProverConfig{}.validateElcClientID())
+	require.NoError(ProverConfig{ElcClientId: "07-tendermint-0"}.validateElcClientID())
+	require.ErrorContains(ProverConfig{ElcClientId: id}.validateElcClientID(), "ElcClientId is invalid")
+}
+
+func TestNewELCClientID(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	id := newELCClientID(now)
+	require.NoError(validateUserELCClientID(id))
+	require.NotEqual(id, newELCClientID(now.Add(time.Nanosecond)))
+}
+
+func TestCreateELCClientID(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	require := require.New(t)
+	f := newBootstrapFixture(t)
+
+	// an empty ID generates a new one
+	res, err := f.pr.doCreateELC("", LatestHeightSpec)
+	require.NoError(err)
+	require.True(res.Created)
+	require.True(strings.HasPrefix(res.ELCClientID, generatedELCClientIDPrefix))
+	require.Contains(f.service.clients, res.ELCClientID)
+
+	res, err = f.pr.doCreateELC("elc-client-1", LatestHeightSpec)
+	require.NoError(err)
+	require.Equal("elc-client-1", res.ELCClientID)
+
+	// the invalid IDs are rejected before calling the service
+	_, err = f.pr.doCreateELC("elc/client/2", LatestHeightSpec)
+	require.ErrorIs(err, ErrInvalidELCClientID)
+	require.NotContains(f.service.clients, "elc/client/2")
+	_, err = f.pr.doQueryELC("elc client")
+	require.ErrorIs(err, ErrInvalidELCClientID)
+}
+
+func TestPersistedELCClientID(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	require := require.New(t)
+	eki := loadTestEnclaveKeyInfo(t)
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	service := &mockLCPService{t: t, key: key, clients: make(map[string]*lcptypes.ClientState)}
+	homePath := t.TempDir()
+	newProver := func(elcClientID string) *Prover {
+		pr := newTestProver(t)
+		pr.homePath = homePath
+		pr.originProver = mockSelfTestOriginProver{latestHeight: clienttypes.NewHeight(0, 10)}
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.ElcClientId = elcClientID
+		pr.config.AllowedQuoteStatuses = []string{"GROUP_OUT_OF_DATE"}
+		pr.config.AllowedAdvisoryIds = []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-00334", "INTEL-SA-00477", "INTEL-SA-00614", "INTEL-SA-00615", "INTEL-SA-00617", "INTEL-SA-00828"}
+		pr.config.AllowDebugEnclaveKeys = true
+		pr.config.Mrenclave = testMrenclave(t, eki)
+		pr.lcpServiceClient = LCPServiceClient{
+			ELCMsgClient:       service,
+			ELCQueryClient:     service,
+			EnclaveQueryClient: mockEnclaveQueryClient{availableKeys: []*enclave.EnclaveKeyInfo{eki}},
+		}
+		require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr
+	}
+
+	// fresh creation: the ID is generated and persisted
+	pr := newProver("")
+	require.NoError(pr.loadPersistedELCClientID(context.TODO()))
+	require.Empty(pr.GetELCClientID())
+	_, _, err = pr.CreateInitialLightClientState(clienttypes.NewHeight(0, 10))
+	require.NoError(err)
+	id := pr.GetELCClientID()
+	require.True(strings.HasPrefix(id, generatedELCClientIDPrefix))
+	require.Contains(service.clients, id)
+	persisted, err := pr.ekStore().GetELCClientID()
+	require.NoError(err)
+	require.Equal(id, persisted)
+
+	// restart: the persisted ID is used without creating another client
+	pr = newProver("")
+	require.NoError(pr.loadPersistedELCClientID(context.TODO()))
+	require.Equal(id, pr.GetELCClientID())
+	_, _, err = pr.CreateInitialLightClientState(clienttypes.NewHeight(0, 10))
+	require.NoError(err)
+	require.Len(service.clients, 1)
+
+	// the config may set the same ID
+	pr = newProver(id)
+	require.NoError(pr.loadPersistedELCClientID(context.TODO()))
+	require.Equal(id, pr.GetELCClientID())
+
+	// conflicting config
+	pr = newProver("07-tendermint-0")
+	err = pr.loadPersistedELCClientID(context.TODO())
+	require.ErrorIs(err, ErrELCClientIDConflict)
+	require.ErrorContains(err, id)
+
+	// a corrupted ID is rejected
+	require.NoError(pr.ekStore().SetELCClientID("bad id"))
+	require.ErrorIs(newProver("").loadPersistedELCClientID(context.TODO()), ErrInvalidELCClientID)
+}
@@ -0,0 +1,129 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+)
+
+// enclaveModeFile records the enclave mode of the prover that initialized the home directory
+const enclaveModeFile = "enclave_mode"
+
+const (
+	EnclaveModeDebug      = "debug"
+	EnclaveModeProduction = "production"
+)
+
+// ErrEnclaveModeMismatch is returned if the home directory has been used in another enclave mode
+var ErrEnclaveModeMismatch = errors.New("the enclave mode of the home directory differs from the prover config")
+
+func enclaveModeOf(debug bool) string {
+	if debug {
+		return EnclaveModeDebug
+	}
+	return EnclaveModeProduction
+}
+
+// enclaveMode returns the enclave mode of the prover config
+func (pr *Prover) enclaveMode() string {
+	return enclaveModeOf(pr.config.IsDebugEnclave)
+}
+
+func (pr *Prover) enclaveModeFilePath() string {
+	return filepath.Join(pr.dbPath(), enclaveModeFile)
+}
+
+// loadEnclaveMode returns the enclave mode recorded in the home directory, or an empty string if it is not recorded
+func (pr *Prover) loadEnclaveMode() (string, error) {
+	path := pr.enclaveModeFilePath()
+	bz, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to read file: path=%v %w", path, err)
+	}
+	mode := strings.TrimSpace(string(bz))
+	if mode != EnclaveModeDebug && mode != EnclaveModeProduction {
+		return "", fmt.Errorf("unknown enclave mode: path=%v mode=%q", path, mode)
+	}
+	return mode, nil
+}
+
+func (pr *Prover) saveEnclaveMode(mode string) error {
+	path := pr.enclaveModeFilePath()
+	if err := os.WriteFile(path, []byte(mode+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write file: path=%v %w", path, err)
+	}
+	return nil
+}
+
+// checkEnclaveMode returns ErrEnclaveModeMismatch if the home directory has been used in another enclave mode.
+// If the mode is not recorded yet, e.g. the home directory is new or initialized by an older version, the current mode is recorded.
+func (pr *Prover) checkEnclaveMode() error {
+	stored, err := pr.loadEnclaveMode()
+	if err != nil {
+		return err
+	} else if stored == "" {
+		pr.getLogger().Info("record the enclave mode of the home directory", "mode", pr.enclaveMode())
+		return pr.saveEnclaveMode(pr.enclaveMode())
+	} else if stored != pr.enclaveMode() {
+		return fmt.Errorf("%w: stored=%v config=%v path=%v: run `lcp migrate-home` to discard the enclave key infos of the %v mode",
+			ErrEnclaveModeMismatch, stored, pr.enclaveMode(), pr.dbPath(), stored)
+	}
+	return nil
+}
+
+// initEnclaveMode checks the enclave mode of the home directory on the initialization.
+// ErrEnclaveModeMismatch is kept and returned by SetRelayInfo because the prover is initialized before the command can skip the check.
+func (pr *Prover) initEnclaveMode() error {
+	pr.enclaveModeErr = nil
+	if err := pr.checkEnclaveMode(); errors.Is(err, ErrEnclaveModeMismatch) {
+		pr.enclaveModeErr = err
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// matchesEnclaveMode returns true if a record saved in the enclave mode `debug` can be used by the prover.
+// The records saved by older versions have no mode, and they are assumed to match.
+func (pr *Prover) matchesEnclaveMode(debug *bool) bool {
+	return debug == nil || *debug == pr.config.IsDebugEnclave
+}
+
+// MigrateHomeResult is the result of doMigrateHome
+type MigrateHomeResult struct {
+	Path string `json:"path"`
+	// empty if the mode was not recorded
+	From string `json:"from"`
+	To   string `json:"to"`
+	// true if the enclave key infos of the previous mode were removed
+	Migrated bool `json:"migrated"`
+}
+
+// doMigrateHome switches the home directory to the enclave mode of the prover config.
+// The enclave key infos are removed if the home directory has been used in another mode because they cannot be used in the current one.
+func (pr *Prover) doMigrateHome(ctx context.Context) (*MigrateHomeResult, error) {
+	stored, err := pr.loadEnclaveMode()
+	if err != nil {
+		return nil, err
+	}
+	res := &MigrateHomeResult{Path: pr.dbPath(), From: stored, To: pr.enclaveMode()}
+	if stored != "" && stored != pr.enclaveMode() {
+		pr.getLogger().Warn("discard the enclave key infos of the previous enclave mode", "from", stored, "to", pr.enclaveMode())
+		if err := pr.removeEnclaveKeyInfos(ctx); err != nil {
+			return nil, err
+		}
+		res.Migrated = true
+	}
+	if err := pr.saveEnclaveMode(pr.enclaveMode()); err != nil {
+		return nil, err
+	}
+	pr.activeEnclaveKey, pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = nil, nil, clienttypes.Height{}
+	return res, nil
+}
@@ -0,0 +1,135 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnclaveModeSeparation(t *testing.T) {
+	msgID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	eki := &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}, AttestationTime: 1}
+
+	// newModeProver returns a prover in the enclave mode `debug` sharing the home directory `home`
+	newModeProver := func(t *testing.T, home string, debug bool) *Prover {
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = home
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.IsDebugEnclave = debug
+		require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr
+	}
+
+	for _, debug := range []bool{true, false} {
+		from, to := enclaveModeOf(debug), enclaveModeOf(!debug)
+		t.Run(from+" to "+to, func(t *testing.T) {
+			require := require.New(t)
+			home := t.TempDir()
+			prev := newModeProver(t, home, debug)
+			require.NoError(prev.checkEnclaveMode())
+			require.NoError(prev.saveFinalizedEnclaveKeyInfo(context.TODO(), eki))
+			require.NoError(prev.saveUnfinalizedEnclaveKeyInfo(context.TODO(), eki, msgID, clienttypes.NewHeight(0, 10)))
+
+			// the initialization in the other mode is refused
+			next := newModeProver(t, home, !debug)
+			err := next.checkEnclaveMode()
+			require.ErrorIs(err, ErrEnclaveModeMismatch)
+			require.ErrorContains(err, "stored="+from+" config="+to)
+
+			// the key infos saved in the other mode are not loaded even if the check is bypassed
+			_, err = next.loadLastFinalizedEnclaveKey(context.TODO())
+			require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+			_, _, _, err = next.loadLastUnfinalizedEnclaveKey(context.TODO())
+			require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+
+			// the key infos are loaded in the same mode
+			loaded, err := prev.loadLastFinalizedEnclaveKey(context.TODO())
+			require.NoError(err)
+			require.Equal(eki.EnclaveKeyAddress, loaded.EnclaveKeyAddress)
+			_, loadedMsgID, _, err := prev.loadLastUnfinalizedEnclaveKey(context.TODO())
+			require.NoError(err)
+			require.Equal(msgID.String(), loadedMsgID.String())
+
+			res, err := next.doMigrateHome(context.TODO())
+			require.NoError(err)
+			require.Equal(&MigrateHomeResult{Path: next.dbPath(), From: from, To: to, Migrated: true}, res)
+			require.NoError(next.checkEnclaveMode())
+			require.ErrorIs(prev.checkEnclaveMode(), ErrEnclaveModeMismatch)
+			_, err = prev.loadLastFinalizedEnclaveKey(context.TODO())
+			require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+			_, _, _, err = prev.loadLastUnfinalizedEnclaveKey(context.TODO())
+			require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+
+			// the migration in the same mode does nothing
+			res, err = next.doMigrateHome(context.TODO())
+			require.NoError(err)
+			require.False(res.Migrated)
+		})
+	}
+
+	t.Run("legacy home", func(t *testing.T) {
+		require := require.New(t)
+		pr := newModeProver(t, t.TempDir(), false)
+		// the older versions saved the enclave key info as it is and recorded no mode
+		bz, err := json.Marshal(eki)
+		require.NoError(err)
+		require.NoError(os.WriteFile(filepath.Join(pr.dbPath(), lastFinalizedEnclaveKeyInfoFile), bz, 0600))
+
+		require.NoError(pr.checkEnclaveMode())
+		mode, err := pr.loadEnclaveMode()
+		require.NoError(err)
+		require.Equal(EnclaveModeProduction, mode)
+		loaded, err := pr.loadLastFinalizedEnclaveKey(context.TODO())
+		require.NoError(err)
+		require.Equal(eki.EnclaveKeyAddress, loaded.EnclaveKeyAddress)
+	})
+}
+
+func TestEnclaveModeCheckOnRelayInfo(t *testing.T) {
+	home := t.TempDir()
+	newModeProver := func(t *testing.T, debug bool) *Prover {
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = home
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.IsDebugEnclave = debug
+		require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr
+	}
+	pathEnd := &core.PathEnd{ChainID: "origin"}
+	counterpartyPath := &core.PathEnd{ChainID: "counterparty", ClientID: "lcp-client-0"}
+
+	prev := newModeProver(t, false)
+	require.NoError(t, prev.initEnclaveMode())
+	require.NoError(t, prev.SetRelayInfo(pathEnd, nil, counterpartyPath))
+
+	t.Run("mismatch", func(t *testing.T) {
+		// the initialization succeeds, but the prover cannot be used for the relay
+		next := newModeProver(t, true)
+		require.NoError(t, next.initEnclaveMode())
+		require.ErrorIs(t, next.SetRelayInfo(pathEnd, nil, counterpartyPath), ErrEnclaveModeMismatch)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		next := newModeProver(t, true)
+		require.NoError(t, next.initEnclaveMode())
+		next.SetSkipEnclaveModeCheck(true)
+		require.NoError(t, next.SetRelayInfo(pathEnd, nil, counterpartyPath))
+		_, err := next.doMigrateHome(context.TODO())
+		require.NoError(t, err)
+
+		// the home directory is migrated, so the check passes on the next initialization
+		again := newModeProver(t, true)
+		require.NoError(t, again.initEnclaveMode())
+		require.NoError(t, again.SetRelayInfo(pathEnd, nil, counterpartyPath))
+	})
+}
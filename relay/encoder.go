@@ -0,0 +1,67 @@
+package relay
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+)
+
+// MessageEncoder encodes an LCP client message into a msg that the counterparty chain accepts via core.Chain.SendMsgs
+type MessageEncoder interface {
+	// EncodeClientMessage returns a msg that applies `message` to the LCP client `clientID` on the counterparty chain
+	EncodeClientMessage(clientID string, signer sdk.AccAddress, message ibcexported.ClientMessage) (sdk.Msg, error)
+}
+
+// CosmosMessageEncoder encodes an LCP client message into a MsgUpdateClient of ibc-go.
+// It is also used for the chain types that have no registered encoder.
+type CosmosMessageEncoder struct{}
+
+var _ MessageEncoder = CosmosMessageEncoder{}
+
+func (CosmosMessageEncoder) EncodeClientMessage(clientID string, signer sdk.AccAddress, message ibcexported.ClientMessage) (sdk.Msg, error) {
+	return clienttypes.NewMsgUpdateClient(clientID, message, signer.String())
+}
+
+var messageEncoders = map[lcptypes.ChainType]MessageEncoder{
+	lcptypes.ChainTypeCosmos: CosmosMessageEncoder{},
+}
+
+// RegisterMessageEncoder registers the encoder for the counterparty chains of the given chain type.
+// This function must be called before the relayer starts.
+func RegisterMessageEncoder(chainType lcptypes.ChainType, encoder MessageEncoder) {
+	messageEncoders[chainType] = encoder
+}
+
+// counterpartyChainType returns the chain type of the counterparty chain.
+// If the EIP712 params are not configured, the counterparty chain is assumed to be a Cosmos chain.
+func (pr *Prover) counterpartyChainType() lcptypes.ChainType {
+	if pr.config.OperatorsEip712Params == nil {
+		return lcptypes.ChainTypeCosmos
+	}
+	return pr.config.ChainType()
+}
+
+func (pr *Prover) getMessageEncoder() MessageEncoder {
+	if encoder, ok := messageEncoders[pr.counterpartyChainType()]; ok {
+		return encoder
+	}
+	return CosmosMessageEncoder{}
+}
+
+// encodeClientMessages encodes the LCP client messages with the encoder for the counterparty chain
+func (pr *Prover) encodeClientMessages(clientID string, signer sdk.AccAddress, messages ...ibcexported.ClientMessage) ([]sdk.Msg, error) {
+	encoder := pr.getMessageEncoder()
+	var msgs []sdk.Msg
+	for i, message := range messages {
+		msg, err := encoder.EncodeClientMessage(clientID, signer, message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode client message: index=%v chain_type=%v %w", i, pr.counterpartyChainType(), err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
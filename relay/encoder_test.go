@@ -0,0 +1,74 @@
+package relay
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEVMCalldata is a msg that carries a calldata for the LCP client contract
+type fakeEVMCalldata struct {
+	clientID string
+	data     []byte
+}
+
+func (m *fakeEVMCalldata) Reset()         { *m = fakeEVMCalldata{} }
+func (m *fakeEVMCalldata) String() string { return fmt.Sprintf("%v:%x", m.clientID, m.data) }
+func (*fakeEVMCalldata) ProtoMessage()    {}
+
+type fakeEVMMessageEncoder struct{}
+
+func (fakeEVMMessageEncoder) EncodeClientMessage(clientID string, signer sdk.AccAddress, message ibcexported.ClientMessage) (sdk.Msg, error) {
+	switch message := message.(type) {
+	case *lcptypes.RegisterEnclaveKeyMessage:
+		return &fakeEVMCalldata{clientID: clientID, data: append([]byte{0x01}, message.Report...)}, nil
+	case *lcptypes.UpdateClientMessage:
+		return &fakeEVMCalldata{clientID: clientID, data: append([]byte{0x02}, message.ProxyMessage...)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported message: %T", message)
+	}
+}
+
+func TestMessageEncoder(t *testing.T) {
+	require := require.New(t)
+	RegisterMessageEncoder(lcptypes.ChainTypeEVM, fakeEVMMessageEncoder{})
+	defer delete(messageEncoders, lcptypes.ChainTypeEVM)
+
+	update := &lcptypes.UpdateClientMessage{ProxyMessage: []byte{0x01}, Signatures: [][]byte{{0x02}}}
+	signer := sdk.AccAddress(make([]byte, 20))
+
+	// the counterparty is assumed to be a Cosmos chain if the EIP712 params are not configured
+	pr := newTestProver(t)
+	require.Equal(lcptypes.ChainTypeCosmos, pr.counterpartyChainType())
+	msgs, err := pr.encodeClientMessages("lcp-client-0", signer, update)
+	require.NoError(err)
+	require.Len(msgs, 1)
+	require.IsType(&clienttypes.MsgUpdateClient{}, msgs[0])
+
+	pr.config.OperatorsEip712Params = &ProverConfig_OperatorsEip712EvmChainParams{
+		OperatorsEip712EvmChainParams: &EIP712EVMChainParams{ChainId: 1},
+	}
+	require.Equal(lcptypes.ChainTypeEVM, pr.counterpartyChainType())
+	msgs, err = pr.encodeClientMessages("lcp-client-0", signer, update)
+	require.NoError(err)
+	require.Equal([]sdk.Msg{&fakeEVMCalldata{clientID: "lcp-client-0", data: []byte{0x02, 0x01}}}, msgs)
+	_, err = pr.encodeClientMessages("lcp-client-0", signer, &lcptypes.UpdateOperatorsMessage{})
+	require.Error(err)
+
+	// the bundled msgs are encoded with the registered encoder
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+	registerMsg := &fakeEVMCalldata{clientID: "lcp-client-0", data: []byte{0x01}}
+	_, bundled, err := pr.sendRegisterEnclaveKeyMsgWithFirstUpdates(cp, &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}}, registerMsg, func() ([]core.Header, error) {
+		return []core.Header{update}, nil
+	})
+	require.NoError(err)
+	require.True(bundled)
+	require.Equal([]sdk.Msg{registerMsg, &fakeEVMCalldata{clientID: "lcp-client-0", data: []byte{0x02, 0x01}}}, cp.sentMsgs[0])
+}
@@ -0,0 +1,271 @@
+package relay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// DefaultExportBatchSize is the default number of heights queried between the checkpoints of the export
+const DefaultExportBatchSize = 100
+
+// exportCheckpointFileExt is appended to the path of the export file to get the path of its checkpoint
+const exportCheckpointFileExt = ".checkpoint"
+
+// VerifiedStatesExportHeader is the first line of the export of the verified states.
+// It has the parameters of the LCP client on the counterparty chain when the export started.
+type VerifiedStatesExportHeader struct {
+	ChainID  string `json:"chain_id"`
+	ClientID string `json:"client_id"`
+	// the height of the counterparty chain at which the client state was queried
+	QueryHeight clienttypes.Height `json:"query_height"`
+	FromHeight  clienttypes.Height `json:"from_height"`
+	ToHeight    clienttypes.Height `json:"to_height"`
+
+	Mrenclave            hexutil.Bytes `json:"mrenclave"`
+	KeyExpiration        uint64        `json:"key_expiration"`
+	AllowedQuoteStatuses []string      `json:"allowed_quote_statuses"`
+	AllowedAdvisoryIds   []string      `json:"allowed_advisory_ids"`
+	// EIP-55 checksum addresses. Empty if the client is permissionless.
+	Operators          []string          `json:"operators"`
+	OperatorsNonce     uint64            `json:"operators_nonce"`
+	OperatorsThreshold lcptypes.Fraction `json:"operators_threshold"`
+
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// VerifiedState is a line of the export of the verified states, i.e. a consensus state accepted by the LCP client.
+// NOTE: the enclave key which signed the update is not stored in the consensus state, so it is not exported.
+type VerifiedState struct {
+	Height  clienttypes.Height `json:"height"`
+	StateID hexutil.Bytes      `json:"state_id"`
+	// unit: seconds
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// exportCheckpoint is the progress of the export saved after each batch
+type exportCheckpoint struct {
+	Header VerifiedStatesExportHeader `json:"header"`
+	// the revision height to resume the export from
+	NextHeight uint64 `json:"next_height"`
+	// the size of the export file including the lines up to NextHeight-1
+	Offset int64 `json:"offset"`
+	// the number of exported states
+	Exported uint64 `json:"exported"`
+}
+
+// ExportVerifiedStatesResult is the result of doExportVerifiedStates
+type ExportVerifiedStatesResult struct {
+	Output     string             `json:"output"`
+	FromHeight clienttypes.Height `json:"from_height"`
+	ToHeight   clienttypes.Height `json:"to_height"`
+	// the number of exported states including the ones exported before the resume
+	Exported uint64 `json:"exported"`
+	// true if the export was resumed from a checkpoint
+	Resumed bool `json:"resumed"`
+}
+
+// doExportVerifiedStates writes the consensus states that the LCP client on `counterparty` has accepted
+// between the revision heights `fromHeight` and `toHeight` to `output` as JSON Lines, whose first line is the header.
+// If `toHeight` is zero, the latest height of the client is used.
+// The heights are queried in batches of `batchSize`, and the progress is saved in a checkpoint file after each batch.
+// If the checkpoint of `output` exists, the export is resumed from it and the other arguments are ignored.
+// The checkpoint is removed when the export completes.
+func (pr *Prover) doExportVerifiedStates(ctx context.Context, counterparty core.FinalityAwareChain, fromHeight, toHeight uint64, output string, batchSize uint64) (*ExportVerifiedStatesResult, error) {
+	if batchSize == 0 {
+		batchSize = DefaultExportBatchSize
+	}
+	checkpointPath := output + exportCheckpointFileExt
+	cp, err := loadExportCheckpoint(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+	resumed := cp != nil
+	if resumed {
+		pr.getLogger().Info("resume the export of the verified states", "output", output, "next_height", cp.NextHeight, "exported", cp.Exported)
+	} else {
+		header, err := pr.newVerifiedStatesExportHeader(ctx, counterparty, fromHeight, toHeight)
+		if err != nil {
+			return nil, err
+		}
+		cp = &exportCheckpoint{Header: *header, NextHeight: header.FromHeight.RevisionHeight}
+	}
+
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the export file: path=%v %w", output, err)
+	}
+	defer f.Close()
+	// discard the lines written after the last checkpoint
+	if err := f.Truncate(cp.Offset); err != nil {
+		return nil, fmt.Errorf("failed to truncate the export file: path=%v offset=%v %w", output, cp.Offset, err)
+	} else if _, err := f.Seek(cp.Offset, 0); err != nil {
+		return nil, fmt.Errorf("failed to seek the export file: path=%v offset=%v %w", output, cp.Offset, err)
+	}
+	w := bufio.NewWriter(f)
+	if !resumed {
+		if err := writeJSONLine(w, cp.Header); err != nil {
+			return nil, err
+		}
+		if err := cp.save(checkpointPath, f, w); err != nil {
+			return nil, err
+		}
+	}
+
+	header := cp.Header
+	queryCtx := core.NewQueryContext(ctx, header.QueryHeight)
+	for cp.NextHeight <= header.ToHeight.RevisionHeight {
+		end := min(cp.NextHeight+batchSize-1, header.ToHeight.RevisionHeight)
+		for h := cp.NextHeight; h <= end; h++ {
+			height := clienttypes.NewHeight(header.FromHeight.RevisionNumber, h)
+			res, err := counterparty.QueryClientConsensusState(queryCtx, height)
+			if errors.Is(err, clienttypes.ErrConsensusStateNotFound) {
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to query consensus state: height=%v query_height=%v %w", height, header.QueryHeight, err)
+			}
+			var cons ibcexported.ConsensusState
+			if err := pr.codec.UnpackAny(res.ConsensusState, &cons); err != nil {
+				return nil, fmt.Errorf("failed to unpack consensus state: height=%v %w", height, err)
+			}
+			consensusState, ok := cons.(*lcptypes.ConsensusState)
+			if !ok {
+				return nil, fmt.Errorf("unexpected consensus state type: height=%v type=%T", height, cons)
+			}
+			if err := writeJSONLine(w, VerifiedState{
+				Height:    height,
+				StateID:   consensusState.StateId,
+				Timestamp: consensusState.Timestamp,
+			}); err != nil {
+				return nil, err
+			}
+			cp.Exported++
+		}
+		cp.NextHeight = end + 1
+		if err := cp.save(checkpointPath, f, w); err != nil {
+			return nil, err
+		}
+		pr.getLogger().Info("exported the verified states", "output", output, "to_height", end, "exported", cp.Exported)
+	}
+
+	if err := os.Remove(checkpointPath); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove the checkpoint: path=%v %w", checkpointPath, err)
+	}
+	return &ExportVerifiedStatesResult{
+		Output:     output,
+		FromHeight: header.FromHeight,
+		ToHeight:   header.ToHeight,
+		Exported:   cp.Exported,
+		Resumed:    resumed,
+	}, nil
+}
+
+// newVerifiedStatesExportHeader queries the LCP client on `counterparty` and returns the header of the export
+func (pr *Prover) newVerifiedStatesExportHeader(ctx context.Context, counterparty core.FinalityAwareChain, fromHeight, toHeight uint64) (*VerifiedStatesExportHeader, error) {
+	cplatestHeight, err := counterparty.LatestHeight()
+	if err != nil {
+		return nil, err
+	}
+	res, err := counterparty.QueryClientState(core.NewQueryContext(ctx, cplatestHeight))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query client state: height=%v %w", cplatestHeight, err)
+	}
+	var cs ibcexported.ClientState
+	if err := pr.codec.UnpackAny(res.ClientState, &cs); err != nil {
+		return nil, fmt.Errorf("failed to unpack client state: client_state=%v %w", res.ClientState, err)
+	}
+	clientState, ok := cs.(*lcptypes.ClientState)
+	if !ok {
+		return nil, fmt.Errorf("unexpected client state type: type=%T", cs)
+	}
+	latestHeight := clientState.LatestHeight
+	if toHeight == 0 {
+		toHeight = latestHeight.RevisionHeight
+	}
+	if fromHeight == 0 || fromHeight > toHeight {
+		return nil, fmt.Errorf("invalid height range: from=%v to=%v", fromHeight, toHeight)
+	}
+	var operators []string
+	for _, op := range clientState.GetOperators() {
+		operators = append(operators, op.Hex())
+	}
+	header := &VerifiedStatesExportHeader{
+		ChainID:              counterparty.ChainID(),
+		ClientID:             counterparty.Path().ClientID,
+		QueryHeight:          clienttypes.NewHeight(cplatestHeight.GetRevisionNumber(), cplatestHeight.GetRevisionHeight()),
+		FromHeight:           clienttypes.NewHeight(latestHeight.RevisionNumber, fromHeight),
+		ToHeight:             clienttypes.NewHeight(latestHeight.RevisionNumber, toHeight),
+		Mrenclave:            clientState.Mrenclave,
+		KeyExpiration:        clientState.KeyExpiration,
+		AllowedQuoteStatuses: clientState.AllowedQuoteStatuses,
+		AllowedAdvisoryIds:   clientState.AllowedAdvisoryIds,
+		Operators:            operators,
+		OperatorsNonce:       clientState.OperatorsNonce,
+		OperatorsThreshold:   clientState.OperatorsThreshold(),
+		ExportedAt:           time.Now().UTC(),
+	}
+	return header, nil
+}
+
+func writeJSONLine(w *bufio.Writer, v interface{}) error {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the export line: %w", err)
+	}
+	if _, err := w.Write(append(bz, '\n')); err != nil {
+		return fmt.Errorf("failed to write the export line: %w", err)
+	}
+	return nil
+}
+
+// save flushes the lines to the export file and saves the checkpoint with the offset of the end of the file
+func (cp *exportCheckpoint) save(path string, f *os.File, w *bufio.Writer) error {
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush the export file: %w", err)
+	} else if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync the export file: %w", err)
+	}
+	offset, err := f.Seek(0, 1)
+	if err != nil {
+		return fmt.Errorf("failed to get the offset of the export file: %w", err)
+	}
+	cp.Offset = offset
+	bz, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the checkpoint: %w", err)
+	}
+	// write the checkpoint atomically so that an interruption does not leave a broken one
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, bz, 0o644); err != nil {
+		return fmt.Errorf("failed to write the checkpoint: path=%v %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to rename the checkpoint: path=%v %w", path, err)
+	}
+	return nil
+}
+
+// loadExportCheckpoint returns the checkpoint at `path`, or nil if it does not exist
+func loadExportCheckpoint(path string) (*exportCheckpoint, error) {
+	bz, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the checkpoint: path=%v %w", path, err)
+	}
+	var cp exportCheckpoint
+	if err := json.Unmarshal(bz, &cp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the checkpoint: path=%v %w", path, err)
+	}
+	return &cp, nil
+}
@@ -0,0 +1,156 @@
+package relay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	errorsmod "cosmossdk.io/errors"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+// mockConsensusStatesCounterparty is a counterparty chain whose LCP client has `consensusStates`
+type mockConsensusStatesCounterparty struct {
+	*mockCounterparty
+	consensusStates map[uint64]*lcptypes.ConsensusState
+	// if not zero, the query of this height fails once to simulate an interruption
+	failAt  uint64
+	queries []uint64
+}
+
+func (c *mockConsensusStatesCounterparty) QueryClientConsensusState(ctx core.QueryContext, height exported.Height) (*clienttypes.QueryConsensusStateResponse, error) {
+	h := height.GetRevisionHeight()
+	c.queries = append(c.queries, h)
+	if c.failAt != 0 && h == c.failAt {
+		c.failAt = 0
+		return nil, errors.New("connection reset")
+	}
+	cons, ok := c.consensusStates[h]
+	if !ok {
+		return nil, errorsmod.Wrapf(clienttypes.ErrConsensusStateNotFound, "height=%v", height)
+	}
+	anyConsensusState, err := clienttypes.PackConsensusState(cons)
+	if err != nil {
+		return nil, err
+	}
+	return &clienttypes.QueryConsensusStateResponse{ConsensusState: anyConsensusState}, nil
+}
+
+func readExportFile(t *testing.T, path string) (*VerifiedStatesExportHeader, []VerifiedState) {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	require.True(t, scanner.Scan())
+	var header VerifiedStatesExportHeader
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+	var states []VerifiedState
+	for scanner.Scan() {
+		var s VerifiedState
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &s))
+		states = append(states, s)
+	}
+	require.NoError(t, scanner.Err())
+	return &header, states
+}
+
+func TestDoExportVerifiedStates(t *testing.T) {
+	const numStates = 50
+	operator := common.HexToAddress("0xcb96F8d6C2d543102184d679D7829b39434E4EEc")
+	newCounterparty := func() *mockConsensusStatesCounterparty {
+		cp := newMockCounterparty(clienttypes.NewHeight(0, 200))
+		cp.latestHeight = clienttypes.NewHeight(0, 200)
+		cp.clientState = &lcptypes.ClientState{
+			LatestHeight:                  clienttypes.NewHeight(0, 2*numStates),
+			Mrenclave:                     make([]byte, lcptypes.MrenclaveSize),
+			KeyExpiration:                 3600,
+			AllowedQuoteStatuses:          []string{"GROUP_OUT_OF_DATE"},
+			Operators:                     [][]byte{operator.Bytes()},
+			OperatorsNonce:                1,
+			OperatorsThresholdNumerator:   1,
+			OperatorsThresholdDenominator: 1,
+		}
+		// the client has the consensus states at the even heights
+		states := make(map[uint64]*lcptypes.ConsensusState)
+		for i := uint64(1); i <= numStates; i++ {
+			states[2*i] = &lcptypes.ConsensusState{StateId: common.BytesToHash([]byte{byte(i)}).Bytes(), Timestamp: 1000 + i}
+		}
+		return &mockConsensusStatesCounterparty{mockCounterparty: cp, consensusStates: states}
+	}
+	checkExport := func(t *testing.T, output string, cp *mockConsensusStatesCounterparty) {
+		require := require.New(t)
+		header, states := readExportFile(t, output)
+		require.Equal("counterparty", header.ChainID)
+		require.Equal("lcp-client-0", header.ClientID)
+		require.Equal(clienttypes.NewHeight(0, 1), header.FromHeight)
+		require.Equal(clienttypes.NewHeight(0, 2*numStates), header.ToHeight)
+		require.Equal(cp.clientState.Mrenclave, []byte(header.Mrenclave))
+		require.Equal([]string{operator.Hex()}, header.Operators)
+		require.Equal(lcptypes.Fraction{Numerator: 1, Denominator: 1}, header.OperatorsThreshold)
+		require.Len(states, numStates)
+		for i, s := range states {
+			n := uint64(i + 1)
+			require.Equal(clienttypes.NewHeight(0, 2*n), s.Height)
+			require.Equal(cp.consensusStates[2*n].StateId, []byte(s.StateID))
+			require.Equal(1000+n, s.Timestamp)
+		}
+		_, err := os.Stat(output + exportCheckpointFileExt)
+		require.True(os.IsNotExist(err))
+	}
+
+	t.Run("export", func(t *testing.T) {
+		require := require.New(t)
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		cp := newCounterparty()
+		output := filepath.Join(t.TempDir(), "states.jsonl")
+
+		res, err := pr.doExportVerifiedStates(context.TODO(), cp, 1, 0, output, 16)
+		require.NoError(err)
+		require.Equal(uint64(numStates), res.Exported)
+		require.False(res.Resumed)
+		require.Len(cp.queries, 2*numStates)
+		checkExport(t, output, cp)
+	})
+
+	t.Run("resume from the checkpoint", func(t *testing.T) {
+		require := require.New(t)
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		cp := newCounterparty()
+		cp.failAt = 57
+		output := filepath.Join(t.TempDir(), "states.jsonl")
+
+		_, err := pr.doExportVerifiedStates(context.TODO(), cp, 1, 0, output, 16)
+		require.ErrorContains(err, "height=0-57")
+		checkpoint, err := loadExportCheckpoint(output + exportCheckpointFileExt)
+		require.NoError(err)
+		require.Equal(uint64(49), checkpoint.NextHeight)
+		require.Equal(uint64(24), checkpoint.Exported)
+
+		// the arguments are ignored on resume
+		cp.queries = nil
+		res, err := pr.doExportVerifiedStates(context.TODO(), cp, 100, 100, output, 10)
+		require.NoError(err)
+		require.True(res.Resumed)
+		require.Equal(uint64(numStates), res.Exported)
+		require.Equal(uint64(49), cp.queries[0])
+		checkExport(t, output, cp)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		_, err := pr.doExportVerifiedStates(context.TODO(), newCounterparty(), 10, 5, filepath.Join(t.TempDir(), "states.jsonl"), 0)
+		require.ErrorContains(t, err, "invalid height range: from=10 to=5")
+	})
+}
@@ -0,0 +1,234 @@
+package relay
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+const (
+	// FinalityTrackerDefault decides the finality of a msg with the latest finalized header of the counterparty chain
+	FinalityTrackerDefault = "default"
+	// FinalityTrackerConfirmations decides the finality of a msg with the number of blocks on top of the block including it
+	FinalityTrackerConfirmations = "confirmations"
+)
+
+// FinalityTracker tracks the msgs that the prover submitted to the counterparty chain until they are finalized.
+// A tracker is created for each check, so it may cache the results of the queries during its lifetime.
+type FinalityTracker interface {
+	// IsIncluded returns the height of the block including the msg.
+	// It returns an error if the msg is not found, e.g. it is still pending, dropped or reorganized.
+	IsIncluded(msgID core.MsgID) (clienttypes.Height, error)
+	// IsSuccessful returns true if the msg was executed successfully, otherwise the reason of the failure
+	IsSuccessful(msgID core.MsgID) (bool, string, error)
+	// IsFinalized returns true if the block including the msg is finalized
+	IsFinalized(msgID core.MsgID) (bool, error)
+}
+
+// FinalityTrackerFactory creates a tracker of the msgs submitted to `counterparty`.
+// `latestFinalizedHeader` returns the latest finalized header of the counterparty chain shared with the other code paths of the prover.
+type FinalityTrackerFactory func(config ProverConfig, counterparty core.FinalityAwareChain, latestFinalizedHeader func() (core.Header, error)) (FinalityTracker, error)
+
+var finalityTrackers = map[string]FinalityTrackerFactory{
+	FinalityTrackerDefault:       newDefaultFinalityTracker,
+	FinalityTrackerConfirmations: newConfirmationsFinalityTracker,
+}
+
+// RegisterFinalityTracker registers the factory of the tracker selected by `name` in the prover config.
+// This function must be called before the relayer starts.
+func RegisterFinalityTracker(name string, factory FinalityTrackerFactory) {
+	finalityTrackers[name] = factory
+}
+
+func registeredFinalityTrackers() []string {
+	var names []string
+	for name := range finalityTrackers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (pc ProverConfig) GetFinalityTracker() string {
+	if pc.FinalityTracker == "" {
+		return FinalityTrackerDefault
+	}
+	return pc.FinalityTracker
+}
+
+func (pc ProverConfig) validateFinalityTracker() error {
+	name := pc.GetFinalityTracker()
+	if _, ok := finalityTrackers[name]; !ok {
+		return fmt.Errorf("FinalityTracker must be one of %v, but got %q", registeredFinalityTrackers(), name)
+	}
+	if name == FinalityTrackerConfirmations && pc.FinalityConfirmations == 0 {
+		return fmt.Errorf("FinalityConfirmations must be greater than 0 if FinalityTracker is %q", FinalityTrackerConfirmations)
+	}
+	return nil
+}
+
+// newFinalityTracker creates the tracker selected in the prover config
+func (pr *Prover) newFinalityTracker(counterparty core.FinalityAwareChain) (FinalityTracker, error) {
+	name := pr.config.GetFinalityTracker()
+	factory, ok := finalityTrackers[name]
+	if !ok {
+		return nil, fmt.Errorf("finality tracker not registered: name=%v", name)
+	}
+	tracker, err := factory(pr.config, counterparty, func() (core.Header, error) {
+		return pr.getCounterpartyLatestFinalizedHeader(counterparty)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the finality tracker: name=%v %w", name, err)
+	}
+	return tracker, nil
+}
+
+// checkTrackedMsgStatus returns (finalized, success, confirmations, error) of the msg included in a block.
+// If ConfirmationsRequired is set, the msg is considered finalized only if the number of the confirmations also meets it.
+// confirmations is the latest height of the counterparty chain minus the height of the block including the msg,
+// and it is zero if ConfirmationsRequired is not set.
+func (pr *Prover) checkTrackedMsgStatus(counterparty core.FinalityAwareChain, tracker FinalityTracker, msgID core.MsgID) (bool, bool, uint64, error) {
+	if ok, failureReason, err := tracker.IsSuccessful(msgID); err != nil {
+		return false, false, 0, err
+	} else if !ok {
+		pr.getLogger().Warn("msg execution failed", "msg_id", msgID.String(), "reason", failureReason)
+		return false, false, 0, nil
+	}
+	finalized, err := tracker.IsFinalized(msgID)
+	if err != nil {
+		return false, false, 0, err
+	}
+	required := pr.config.ConfirmationsRequired
+	if required == 0 {
+		return finalized, true, 0, nil
+	}
+	includedHeight, err := tracker.IsIncluded(msgID)
+	if err != nil {
+		return false, false, 0, err
+	}
+	latestHeight, err := counterparty.LatestHeight()
+	if err != nil {
+		return false, false, 0, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	confirmations := msgConfirmations(latestHeight, includedHeight)
+	if finalized && confirmations < required {
+		pr.getLogger().Info("the msg is finalized, but waiting for more confirmations", "msg_id", msgID.String(), "confirmations", confirmations, "confirmations_required", required)
+		finalized = false
+	}
+	return finalized, true, confirmations, nil
+}
+
+// msgConfirmations returns the number of the blocks on top of the block at `includedHeight`.
+// The blocks of different revisions are not comparable by the number of blocks,
+// so the block of an older revision is considered to have the maximum confirmations.
+func msgConfirmations(latestHeight, includedHeight ibcexported.Height) uint64 {
+	switch {
+	case latestHeight.GetRevisionNumber() > includedHeight.GetRevisionNumber():
+		return math.MaxUint64
+	case latestHeight.GetRevisionNumber() < includedHeight.GetRevisionNumber(),
+		latestHeight.GetRevisionHeight() < includedHeight.GetRevisionHeight():
+		return 0
+	default:
+		return latestHeight.GetRevisionHeight() - includedHeight.GetRevisionHeight()
+	}
+}
+
+// msgResultTracker queries the results of the msgs from the counterparty chain and caches them
+type msgResultTracker struct {
+	counterparty core.FinalityAwareChain
+	results      map[string]core.MsgResult
+}
+
+func newMsgResultTracker(counterparty core.FinalityAwareChain) msgResultTracker {
+	return msgResultTracker{counterparty: counterparty, results: make(map[string]core.MsgResult)}
+}
+
+func (t msgResultTracker) msgResult(msgID core.MsgID) (core.MsgResult, error) {
+	if res, ok := t.results[msgID.String()]; ok {
+		return res, nil
+	}
+	res, err := t.counterparty.GetMsgResult(msgID)
+	if err != nil {
+		return nil, err
+	}
+	t.results[msgID.String()] = res
+	return res, nil
+}
+
+func (t msgResultTracker) IsIncluded(msgID core.MsgID) (clienttypes.Height, error) {
+	res, err := t.msgResult(msgID)
+	if err != nil {
+		return clienttypes.Height{}, err
+	}
+	return res.BlockHeight(), nil
+}
+
+func (t msgResultTracker) IsSuccessful(msgID core.MsgID) (bool, string, error) {
+	res, err := t.msgResult(msgID)
+	if err != nil {
+		return false, "", err
+	}
+	ok, failureReason := res.Status()
+	return ok, failureReason, nil
+}
+
+// defaultFinalityTracker considers a msg finalized if the block including it is not higher than the latest finalized header
+type defaultFinalityTracker struct {
+	msgResultTracker
+	latestFinalizedHeader func() (core.Header, error)
+}
+
+var _ FinalityTracker = (*defaultFinalityTracker)(nil)
+
+func newDefaultFinalityTracker(_ ProverConfig, counterparty core.FinalityAwareChain, latestFinalizedHeader func() (core.Header, error)) (FinalityTracker, error) {
+	return &defaultFinalityTracker{msgResultTracker: newMsgResultTracker(counterparty), latestFinalizedHeader: latestFinalizedHeader}, nil
+}
+
+func (t *defaultFinalityTracker) IsFinalized(msgID core.MsgID) (bool, error) {
+	includedHeight, err := t.IsIncluded(msgID)
+	if err != nil {
+		return false, err
+	}
+	lfHeader, err := t.latestFinalizedHeader()
+	if err != nil {
+		return false, err
+	}
+	return includedHeight.LTE(lfHeader.GetHeight()), nil
+}
+
+// confirmationsFinalityTracker considers a msg finalized if the latest block of the counterparty chain
+// is at least `confirmations` blocks higher than the block including it.
+// It is for the chains whose finalized header is not available or lags far behind the probabilistic finality.
+type confirmationsFinalityTracker struct {
+	msgResultTracker
+	confirmations uint64
+}
+
+var _ FinalityTracker = (*confirmationsFinalityTracker)(nil)
+
+func newConfirmationsFinalityTracker(config ProverConfig, counterparty core.FinalityAwareChain, _ func() (core.Header, error)) (FinalityTracker, error) {
+	if config.FinalityConfirmations == 0 {
+		return nil, fmt.Errorf("FinalityConfirmations must be greater than 0")
+	}
+	return &confirmationsFinalityTracker{msgResultTracker: newMsgResultTracker(counterparty), confirmations: config.FinalityConfirmations}, nil
+}
+
+func (t *confirmationsFinalityTracker) IsFinalized(msgID core.MsgID) (bool, error) {
+	includedHeight, err := t.IsIncluded(msgID)
+	if err != nil {
+		return false, err
+	}
+	latestHeight, err := t.counterparty.LatestHeight()
+	if err != nil {
+		return false, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	if latestHeight.GetRevisionNumber() != includedHeight.GetRevisionNumber() {
+		// the blocks of different revisions are not comparable by the number of blocks
+		return latestHeight.GetRevisionNumber() > includedHeight.GetRevisionNumber(), nil
+	}
+	return latestHeight.GetRevisionHeight() >= includedHeight.GetRevisionHeight()+t.confirmations, nil
+}
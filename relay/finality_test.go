@@ -0,0 +1,259 @@
+package relay
+
+import (
+	"context"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFinalityTracker is a custom tracker which considers every included msg finalized
+type mockFinalityTracker struct {
+	msgResultTracker
+	finalizedCalls int
+}
+
+func (t *mockFinalityTracker) IsFinalized(msgID core.MsgID) (bool, error) {
+	t.finalizedCalls++
+	_, err := t.IsIncluded(msgID)
+	return err == nil, err
+}
+
+// TestFinalityTrackerConformance checks that the built-in trackers behave in the same way for the same states of the msgs
+func TestFinalityTrackerConformance(t *testing.T) {
+	includedHeight := clienttypes.NewHeight(0, 10)
+	var trackers = []struct {
+		name   string
+		config ProverConfig
+		// setFinalized makes the block at `includedHeight` finalized or not on the counterparty chain
+		setFinalized func(cp *mockCounterparty, finalized bool)
+	}{
+		{
+			FinalityTrackerDefault,
+			ProverConfig{},
+			func(cp *mockCounterparty, finalized bool) {
+				cp.latestHeight = clienttypes.NewHeight(0, 20)
+				if finalized {
+					cp.finalizedHeight = includedHeight
+				} else {
+					cp.finalizedHeight = clienttypes.NewHeight(0, 9)
+				}
+			},
+		},
+		{
+			FinalityTrackerConfirmations,
+			ProverConfig{FinalityTracker: FinalityTrackerConfirmations, FinalityConfirmations: 3},
+			func(cp *mockCounterparty, finalized bool) {
+				// the finalized header is ignored
+				cp.finalizedHeight = clienttypes.NewHeight(0, 1)
+				if finalized {
+					cp.latestHeight = clienttypes.NewHeight(0, 13)
+				} else {
+					cp.latestHeight = clienttypes.NewHeight(0, 12)
+				}
+			},
+		},
+	}
+	msgID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	for _, tr := range trackers {
+		t.Run(tr.name, func(t *testing.T) {
+			newTracker := func(t *testing.T, res core.MsgResult, finalized bool) (FinalityTracker, *mockCounterparty) {
+				pr := newTestProver(t)
+				pr.config = tr.config
+				require.NoError(t, pr.config.validateFinalityTracker())
+				cp := newMockCounterparty(clienttypes.Height{})
+				if res != nil {
+					cp.msgResults[msgID.String()] = res
+				}
+				tr.setFinalized(cp, finalized)
+				tracker, err := pr.newFinalityTracker(cp)
+				require.NoError(t, err)
+				return tracker, cp
+			}
+
+			t.Run("not included", func(t *testing.T) {
+				require := require.New(t)
+				tracker, _ := newTracker(t, nil, true)
+				_, err := tracker.IsIncluded(msgID)
+				require.Error(err)
+				_, _, err = tracker.IsSuccessful(msgID)
+				require.Error(err)
+				_, err = tracker.IsFinalized(msgID)
+				require.Error(err)
+			})
+
+			t.Run("failed", func(t *testing.T) {
+				require := require.New(t)
+				tracker, _ := newTracker(t, mockMsgResult{height: includedHeight, success: false}, true)
+				height, err := tracker.IsIncluded(msgID)
+				require.NoError(err)
+				require.Equal(includedHeight, height)
+				ok, reason, err := tracker.IsSuccessful(msgID)
+				require.NoError(err)
+				require.False(ok)
+				require.NotEmpty(reason)
+			})
+
+			for _, finalized := range []bool{false, true} {
+				name := "unfinalized"
+				if finalized {
+					name = "finalized"
+				}
+				t.Run(name, func(t *testing.T) {
+					require := require.New(t)
+					tracker, cp := newTracker(t, mockMsgResult{height: includedHeight, success: true}, finalized)
+					height, err := tracker.IsIncluded(msgID)
+					require.NoError(err)
+					require.Equal(includedHeight, height)
+					ok, _, err := tracker.IsSuccessful(msgID)
+					require.NoError(err)
+					require.True(ok)
+					f, err := tracker.IsFinalized(msgID)
+					require.NoError(err)
+					require.Equal(finalized, f)
+					// the msg result is queried once during the lifetime of the tracker
+					require.Equal(1, cp.getMsgResultCalls)
+				})
+			}
+		})
+	}
+}
+
+func TestValidateFinalityTracker(t *testing.T) {
+	var cases = []struct {
+		name   string
+		config ProverConfig
+		err    string
+	}{
+		{"default", ProverConfig{}, ""},
+		{"confirmations", ProverConfig{FinalityTracker: FinalityTrackerConfirmations, FinalityConfirmations: 1}, ""},
+		{"confirmations without depth", ProverConfig{FinalityTracker: FinalityTrackerConfirmations}, "FinalityConfirmations must be greater than 0"},
+		{"unknown", ProverConfig{FinalityTracker: "unknown"}, "FinalityTracker must be one of [confirmations default]"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.validateFinalityTracker()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestRegisterFinalityTracker(t *testing.T) {
+	require := require.New(t)
+	const name = "test_always_finalized"
+	var tracker *mockFinalityTracker
+	RegisterFinalityTracker(name, func(_ ProverConfig, counterparty core.FinalityAwareChain, _ func() (core.Header, error)) (FinalityTracker, error) {
+		tracker = &mockFinalityTracker{msgResultTracker: newMsgResultTracker(counterparty)}
+		return tracker, nil
+	})
+	t.Cleanup(func() { delete(finalityTrackers, name) })
+
+	msgID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.homePath = t.TempDir()
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	pr.config.FinalityTracker = name
+	require.NoError(pr.config.validateFinalityTracker())
+	require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+	pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}, AttestationTime: uint64(time.Now().Unix())}
+	pr.unfinalizedMsgID = msgID
+
+	// the finalized header of the counterparty chain is behind the block including the msg
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 1))
+	cp.msgResults[msgID.String()] = mockMsgResult{height: clienttypes.NewHeight(0, 10), success: true}
+
+	_, err := pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+	require.NoError(err)
+	// the prover follows the custom tracker instead of the finalized header
+	require.NotNil(tracker)
+	require.Equal(1, tracker.finalizedCalls)
+	require.Zero(cp.getLatestFinalizedHeaderCalls)
+	require.Nil(pr.unfinalizedMsgID)
+}
+
+func TestConfirmationsRequired(t *testing.T) {
+	require := require.New(t)
+	msgID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	includedHeight := clienttypes.NewHeight(0, 10)
+
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.homePath = t.TempDir()
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	pr.config.ConfirmationsRequired = 3
+	require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+	pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}, AttestationTime: uint64(time.Now().Unix())}
+	pr.unfinalizedMsgID = msgID
+	pr.unfinalizedMsgHeight = includedHeight
+
+	// the block including the msg is finalized immediately
+	cp := newMockCounterparty(includedHeight)
+	cp.msgResults[msgID.String()] = mockMsgResult{height: includedHeight, success: true}
+
+	for confirmations := uint64(0); confirmations <= 3; confirmations++ {
+		cp.latestHeight = clienttypes.NewHeight(0, includedHeight.RevisionHeight+confirmations)
+		finalized, success, actual, err := pr.checkMsgStatus(cp, msgID)
+		require.NoError(err)
+		require.True(success)
+		require.Equal(confirmations, actual)
+		require.Equal(confirmations >= 3, finalized)
+
+		_, err = pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+		require.NoError(err)
+		if confirmations < 3 {
+			// the key is kept unfinalized until the threshold is met
+			require.NotNil(pr.unfinalizedMsgID, "confirmations=%v", confirmations)
+		} else {
+			require.Nil(pr.unfinalizedMsgID)
+		}
+	}
+	eki, err := pr.loadLastFinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal(pr.activeEnclaveKey, eki)
+}
+
+func TestConfirmationsNotRequired(t *testing.T) {
+	require := require.New(t)
+	msgID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	includedHeight := clienttypes.NewHeight(0, 10)
+	pr := newTestProver(t)
+	cp := newMockCounterparty(includedHeight)
+	cp.latestHeight = includedHeight
+	cp.msgResults[msgID.String()] = mockMsgResult{height: includedHeight, success: true}
+
+	// the finality of the tracker is enough
+	finalized, success, confirmations, err := pr.checkMsgStatus(cp, msgID)
+	require.NoError(err)
+	require.True(success)
+	require.True(finalized)
+	require.Zero(confirmations)
+}
+
+func TestMsgConfirmations(t *testing.T) {
+	var cases = []struct {
+		latest, included clienttypes.Height
+		expected         uint64
+	}{
+		{clienttypes.NewHeight(0, 10), clienttypes.NewHeight(0, 10), 0},
+		{clienttypes.NewHeight(0, 15), clienttypes.NewHeight(0, 10), 5},
+		// the latest height is behind, e.g. queried from a lagging node
+		{clienttypes.NewHeight(0, 9), clienttypes.NewHeight(0, 10), 0},
+		{clienttypes.NewHeight(1, 1), clienttypes.NewHeight(0, 10), math.MaxUint64},
+		{clienttypes.NewHeight(0, 20), clienttypes.NewHeight(1, 10), 0},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, msgConfirmations(c.latest, c.included), "latest=%v included=%v", c.latest, c.included)
+	}
+}
@@ -0,0 +1,290 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+)
+
+const (
+	// the proofs archived in ProofArchiveDir
+	GCArtifactProofArchive = "proof_archive"
+	// the records of the enclave key registrations which have not been finalized
+	GCArtifactUnfinalizedEKI = "unfinalized_eki"
+)
+
+// gcArtifacts is the artifacts collected by doGC in this order
+var gcArtifacts = []string{GCArtifactProofArchive, GCArtifactUnfinalizedEKI}
+
+// the reasons why an entry is protected from the garbage collection
+const (
+	gcProtectedActiveEnclaveKey    = "active_enclave_key"
+	gcProtectedFinalizedEnclaveKey = "finalized_enclave_key"
+	gcProtectedPendingRegistration = "pending_registration"
+)
+
+func (p RetentionPolicy) Validate() error {
+	found := false
+	for _, a := range gcArtifacts {
+		found = found || a == p.Artifact
+	}
+	if !found {
+		return fmt.Errorf("Artifact must be one of %v, but got %q", gcArtifacts, p.Artifact)
+	} else if p.MaxAge == 0 && p.MaxCount == 0 {
+		return fmt.Errorf("either MaxAge or MaxCount must be set: artifact=%v", p.Artifact)
+	}
+	return nil
+}
+
+func (pc ProverConfig) validateRetentionPolicies() error {
+	seen := make(map[string]bool)
+	for i, p := range pc.RetentionPolicies {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("RetentionPolicies[%v]: %w", i, err)
+		} else if seen[p.Artifact] {
+			return fmt.Errorf("RetentionPolicies[%v]: duplicate policies: artifact=%q", i, p.Artifact)
+		}
+		seen[p.Artifact] = true
+	}
+	return nil
+}
+
+// FindRetentionPolicy returns the retention policy of `artifact` or nil if the artifact is never removed.
+// The proof archive falls back to ProofArchiveMaxEntries and ProofArchiveRetention.
+func (pc ProverConfig) FindRetentionPolicy(artifact string) *RetentionPolicy {
+	for i, p := range pc.RetentionPolicies {
+		if p.Artifact == artifact {
+			return &pc.RetentionPolicies[i]
+		}
+	}
+	if artifact == GCArtifactProofArchive && (pc.ProofArchiveMaxEntries != 0 || pc.ProofArchiveRetention != 0) {
+		return &RetentionPolicy{Artifact: artifact, MaxAge: pc.ProofArchiveRetention, MaxCount: pc.ProofArchiveMaxEntries}
+	}
+	return nil
+}
+
+// GCEntry is an entry of an artifact in the relayer's home directory
+type GCEntry struct {
+	ID        string    `json:"id"`
+	Timestamp time.Time `json:"timestamp"`
+	// if not empty, the entry is referenced by the current state and never removed
+	ProtectedBy string `json:"protected_by,omitempty"`
+}
+
+// gcStore enumerates and removes the entries of an artifact
+type gcStore interface {
+	// entries returns the entries in chronological order
+	entries(ctx context.Context) ([]GCEntry, error)
+	remove(ctx context.Context, ids []string) error
+}
+
+// selectGarbage returns the entries which `policy` does not retain at `now`.
+// The protected entries are never selected, but they count toward MaxCount.
+func selectGarbage(entries []GCEntry, policy RetentionPolicy, now time.Time) []GCEntry {
+	var garbage []GCEntry
+	retained := len(entries)
+	for _, e := range entries {
+		if e.ProtectedBy != "" {
+			continue
+		}
+		expired := policy.MaxAge != 0 && now.Sub(e.Timestamp) > time.Duration(policy.MaxAge)*time.Second
+		exceeded := policy.MaxCount != 0 && uint64(retained) > policy.MaxCount
+		if expired || exceeded {
+			garbage = append(garbage, e)
+			retained--
+		}
+	}
+	return garbage
+}
+
+// GCArtifactResult is a result of the garbage collection of an artifact
+type GCArtifactResult struct {
+	Artifact string           `json:"artifact"`
+	Policy   *RetentionPolicy `json:"policy"`
+	// the entries which have been removed, or would be removed in the dry run
+	Removed   []GCEntry `json:"removed"`
+	Protected []GCEntry `json:"protected"`
+	Retained  int       `json:"retained"`
+}
+
+// GCResult is a result of doGC
+type GCResult struct {
+	DryRun    bool               `json:"dry_run"`
+	Artifacts []GCArtifactResult `json:"artifacts"`
+}
+
+// doGC removes the entries of the artifacts in the relayer's home directory which the retention policies do not retain at `now`.
+// If `dryRun` is true, the entries are only listed.
+// The artifacts without a retention policy are skipped.
+func (pr *Prover) doGC(ctx context.Context, now time.Time, dryRun bool) (*GCResult, error) {
+	result := &GCResult{DryRun: dryRun, Artifacts: []GCArtifactResult{}}
+	for _, artifact := range gcArtifacts {
+		policy := pr.config.FindRetentionPolicy(artifact)
+		if policy == nil {
+			continue
+		}
+		store, err := pr.gcStore(ctx, artifact)
+		if err != nil {
+			return nil, err
+		} else if store == nil {
+			continue
+		}
+		entries, err := store.entries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to enumerate the entries: artifact=%v %w", artifact, err)
+		}
+		garbage := selectGarbage(entries, *policy, now)
+		res := GCArtifactResult{Artifact: artifact, Policy: policy, Removed: garbage, Protected: []GCEntry{}, Retained: len(entries) - len(garbage)}
+		if res.Removed == nil {
+			res.Removed = []GCEntry{}
+		}
+		for _, e := range entries {
+			if e.ProtectedBy != "" {
+				res.Protected = append(res.Protected, e)
+			}
+		}
+		if !dryRun && len(garbage) > 0 {
+			ids := make([]string, len(garbage))
+			for i, e := range garbage {
+				ids[i] = e.ID
+			}
+			if err := store.remove(ctx, ids); err != nil {
+				return nil, fmt.Errorf("failed to remove the entries: artifact=%v %w", artifact, err)
+			}
+			pr.getLogger().Info("removed the garbage in the home directory", "artifact", artifact, "removed", len(garbage), "retained", res.Retained)
+		}
+		result.Artifacts = append(result.Artifacts, res)
+	}
+	return result, nil
+}
+
+// gcStore returns the store of `artifact` or nil if the artifact is not enabled
+func (pr *Prover) gcStore(ctx context.Context, artifact string) (gcStore, error) {
+	switch artifact {
+	case GCArtifactProofArchive:
+		archive := pr.proofArchive()
+		if archive == nil {
+			return nil, nil
+		}
+		return proofArchiveGCStore{archive}, nil
+	case GCArtifactUnfinalizedEKI:
+		finalized, err := pr.loadLastFinalizedEnclaveKey(ctx)
+		if err != nil && !errors.Is(err, ErrEnclaveKeyInfoNotFound) {
+			return nil, err
+		}
+		return unfinalizedEKIGCStore{pr: pr, finalized: finalized}, nil
+	default:
+		return nil, fmt.Errorf("unknown artifact: %v", artifact)
+	}
+}
+
+// gcState is the state of the periodic garbage collection
+type gcState struct {
+	lastCollected time.Time
+}
+
+// collectGarbagePeriodically applies the retention policies if GCInterval has elapsed since the last collection.
+// A failure is only logged because the garbage does not affect the relay.
+func (pr *Prover) collectGarbagePeriodically(ctx context.Context, now time.Time) {
+	interval := time.Duration(pr.config.GcInterval) * time.Second
+	if interval == 0 || pr.IsRehearsal() {
+		return
+	} else if !pr.gc.lastCollected.IsZero() && now.Sub(pr.gc.lastCollected) < interval {
+		return
+	}
+	pr.gc.lastCollected = now
+	if _, err := pr.doGC(ctx, now, false); err != nil {
+		pr.getLogger().Warn("failed to collect the garbage in the home directory", "error", err)
+	}
+}
+
+type proofArchiveGCStore struct {
+	archive *proofArchive
+}
+
+var _ gcStore = proofArchiveGCStore{}
+
+func (s proofArchiveGCStore) entries(context.Context) ([]GCEntry, error) {
+	names, err := s.archive.list()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]GCEntry, 0, len(names))
+	for _, name := range names {
+		t, err := archivedProofTime(name)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, GCEntry{ID: name, Timestamp: t})
+	}
+	return entries, nil
+}
+
+func (s proofArchiveGCStore) remove(_ context.Context, ids []string) error {
+	return s.archive.remove(ids)
+}
+
+// unfinalizedEKIGCStore is the records of the unfinalized registrations, whose timestamps are the attestation times of the enclave keys.
+// The registrations of the active and the finalized enclave keys and the pending registration are protected.
+type unfinalizedEKIGCStore struct {
+	pr        *Prover
+	finalized *enclave.EnclaveKeyInfo
+}
+
+var _ gcStore = unfinalizedEKIGCStore{}
+
+func unfinalizedEKIGCEntryID(r unfinalizedEnclaveKey) string {
+	return fmt.Sprintf("%v/%v", lcptypes.HexBytes(r.eki.EnclaveKeyAddress), r.msgID.String())
+}
+
+func (s unfinalizedEKIGCStore) entries(ctx context.Context) ([]GCEntry, error) {
+	records, err := s.pr.loadUnfinalizedEnclaveKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]GCEntry, 0, len(records))
+	for i, r := range records {
+		e := GCEntry{ID: unfinalizedEKIGCEntryID(r), Timestamp: time.Unix(int64(r.eki.AttestationTime), 0)}
+		switch {
+		case s.pr.activeEnclaveKey != nil && bytes.Equal(r.eki.EnclaveKeyAddress, s.pr.activeEnclaveKey.EnclaveKeyAddress):
+			e.ProtectedBy = gcProtectedActiveEnclaveKey
+		case s.finalized != nil && bytes.Equal(r.eki.EnclaveKeyAddress, s.finalized.EnclaveKeyAddress):
+			e.ProtectedBy = gcProtectedFinalizedEnclaveKey
+		case s.pr.unfinalizedMsgID != nil && r.msgID.String() == s.pr.unfinalizedMsgID.String():
+			e.ProtectedBy = gcProtectedPendingRegistration
+		case i == len(records)-1:
+			// the last record is resumed as the pending registration after a restart
+			e.ProtectedBy = gcProtectedPendingRegistration
+		}
+		entries = append(entries, e)
+	}
+	// the records are saved in the order of the registrations, which may differ from the order of the attestations
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, nil
+}
+
+func (s unfinalizedEKIGCStore) remove(ctx context.Context, ids []string) error {
+	removed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		removed[id] = true
+	}
+	records, err := s.pr.loadUnfinalizedEnclaveKeys(ctx)
+	if err != nil {
+		return err
+	}
+	remaining := make([]unfinalizedEnclaveKey, 0, len(records))
+	for _, r := range records {
+		if !removed[unfinalizedEKIGCEntryID(r)] {
+			remaining = append(remaining, r)
+		}
+	}
+	return s.pr.saveUnfinalizedEnclaveKeys(ctx, remaining)
+}
@@ -0,0 +1,213 @@
+package relay
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/stretchr/testify/require"
+)
+
+func gcEntryIDs(entries []GCEntry) []string {
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestSelectGarbage(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	entries := []GCEntry{
+		{ID: "a", Timestamp: now.Add(-5 * time.Hour)},
+		{ID: "b", Timestamp: now.Add(-4 * time.Hour), ProtectedBy: gcProtectedActiveEnclaveKey},
+		{ID: "c", Timestamp: now.Add(-3 * time.Hour)},
+		{ID: "d", Timestamp: now.Add(-2 * time.Hour)},
+		{ID: "e", Timestamp: now.Add(-1 * time.Hour)},
+	}
+	cases := []struct {
+		name     string
+		policy   RetentionPolicy
+		expected []string
+	}{
+		{"by age", RetentionPolicy{MaxAge: uint64((150 * time.Minute).Seconds())}, []string{"a", "c"}},
+		{"by count", RetentionPolicy{MaxCount: 3}, []string{"a", "c"}},
+		// the protected entry counts toward the maximum number of entries
+		{"by count exceeding the unprotected entries", RetentionPolicy{MaxCount: 1}, []string{"a", "c", "d", "e"}},
+		{"by age and count", RetentionPolicy{MaxAge: uint64((270 * time.Minute).Seconds()), MaxCount: 3}, []string{"a", "c"}},
+		{"retained", RetentionPolicy{MaxAge: uint64((6 * time.Hour).Seconds()), MaxCount: 5}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, gcEntryIDs(selectGarbage(entries, c.policy, now)))
+		})
+	}
+}
+
+func TestDoGC(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	newAgedHome := func(t *testing.T) (*Prover, []*enclave.EnclaveKeyInfo) {
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = t.TempDir()
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.ProofArchiveDir = "proofs"
+		require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+
+		// a proof archived every day for 10 days
+		archive := pr.proofArchive()
+		for i := 10; i > 0; i-- {
+			_, err := archive.put(&ArchivedProof{Path: "clients/07-tendermint-0/clientState", Timestamp: now.Add(-time.Duration(i) * 24 * time.Hour)})
+			require.NoError(t, err)
+		}
+		// a registration every 5 days
+		var ekis []*enclave.EnclaveKeyInfo
+		for i := 4; i > 0; i-- {
+			_, eki := newTestEnclaveKey(t)
+			eki.AttestationTime = uint64(now.Add(-time.Duration(i) * 5 * 24 * time.Hour).Unix())
+			msgID := &tendermint.MsgID{TxHash: eki.String(), MsgIndex: 0}
+			require.NoError(t, pr.saveUnfinalizedEnclaveKeyInfo(context.TODO(), eki, msgID, clienttypes.Height{}))
+			ekis = append(ekis, eki)
+		}
+		return pr, ekis
+	}
+	weeks := func(n int) uint64 {
+		return uint64((time.Duration(n) * 7 * 24 * time.Hour).Seconds())
+	}
+
+	t.Run("no policies", func(t *testing.T) {
+		require := require.New(t)
+		pr, _ := newAgedHome(t)
+		res, err := pr.doGC(context.TODO(), now, false)
+		require.NoError(err)
+		require.Empty(res.Artifacts)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		require := require.New(t)
+		pr, _ := newAgedHome(t)
+		pr.config.RetentionPolicies = []RetentionPolicy{
+			{Artifact: GCArtifactProofArchive, MaxCount: 3},
+			{Artifact: GCArtifactUnfinalizedEKI, MaxAge: weeks(1)},
+		}
+		res, err := pr.doGC(context.TODO(), now, true)
+		require.NoError(err)
+		require.True(res.DryRun)
+		require.Len(res.Artifacts, 2)
+		require.Len(res.Artifacts[0].Removed, 7)
+		require.Equal(3, res.Artifacts[0].Retained)
+		require.Len(res.Artifacts[1].Removed, 3)
+
+		// nothing is removed
+		names, err := pr.proofArchive().list()
+		require.NoError(err)
+		require.Len(names, 10)
+		records, err := pr.loadUnfinalizedEnclaveKeys(context.TODO())
+		require.NoError(err)
+		require.Len(records, 4)
+	})
+
+	t.Run("proof archive", func(t *testing.T) {
+		require := require.New(t)
+		pr, _ := newAgedHome(t)
+		pr.config.RetentionPolicies = []RetentionPolicy{{Artifact: GCArtifactProofArchive, MaxAge: weeks(1), MaxCount: 5}}
+		res, err := pr.doGC(context.TODO(), now, false)
+		require.NoError(err)
+		require.Len(res.Artifacts, 1)
+		require.Len(res.Artifacts[0].Removed, 5)
+		names, err := pr.proofArchive().list()
+		require.NoError(err)
+		require.Len(names, 5)
+		for _, name := range names {
+			ts, err := archivedProofTime(name)
+			require.NoError(err)
+			require.LessOrEqual(now.Sub(ts), 5*24*time.Hour)
+		}
+	})
+
+	t.Run("proof archive falls back to the archive options", func(t *testing.T) {
+		require := require.New(t)
+		pr, _ := newAgedHome(t)
+		pr.config.ProofArchiveRetention = weeks(1)
+		res, err := pr.doGC(context.TODO(), now, false)
+		require.NoError(err)
+		require.Len(res.Artifacts, 1)
+		require.Len(res.Artifacts[0].Removed, 3)
+		require.Equal(7, res.Artifacts[0].Retained)
+	})
+
+	t.Run("unfinalized registrations referenced by the current state", func(t *testing.T) {
+		require := require.New(t)
+		pr, ekis := newAgedHome(t)
+		pr.config.RetentionPolicies = []RetentionPolicy{{Artifact: GCArtifactUnfinalizedEKI, MaxCount: 1}}
+		// the oldest key is active and the second one is finalized
+		pr.activeEnclaveKey = ekis[0]
+		require.NoError(pr.saveFinalizedEnclaveKeyInfo(context.TODO(), ekis[1]))
+
+		res, err := pr.doGC(context.TODO(), now, false)
+		require.NoError(err)
+		require.Len(res.Artifacts, 1)
+		result := res.Artifacts[0]
+		require.Len(result.Removed, 1)
+		require.Len(result.Protected, 3)
+		require.Equal(gcProtectedActiveEnclaveKey, result.Protected[0].ProtectedBy)
+		require.Equal(gcProtectedFinalizedEnclaveKey, result.Protected[1].ProtectedBy)
+		// the last registration is pending
+		require.Equal(gcProtectedPendingRegistration, result.Protected[2].ProtectedBy)
+
+		records, err := pr.loadUnfinalizedEnclaveKeys(context.TODO())
+		require.NoError(err)
+		require.Len(records, 3)
+		for _, r := range records {
+			require.NotEqual(ekis[2].EnclaveKeyAddress, r.eki.EnclaveKeyAddress)
+		}
+	})
+
+	t.Run("periodic", func(t *testing.T) {
+		require := require.New(t)
+		pr, _ := newAgedHome(t)
+		pr.config.RetentionPolicies = []RetentionPolicy{{Artifact: GCArtifactProofArchive, MaxCount: 8}}
+		pr.config.GcInterval = 3600
+		countProofs := func() int {
+			names, err := pr.proofArchive().list()
+			require.NoError(err)
+			return len(names)
+		}
+		pr.collectGarbagePeriodically(context.TODO(), now)
+		require.Equal(8, countProofs())
+
+		// the interval has not elapsed yet
+		pr.config.RetentionPolicies[0].MaxCount = 6
+		pr.collectGarbagePeriodically(context.TODO(), now.Add(59*time.Minute))
+		require.Equal(8, countProofs())
+		pr.collectGarbagePeriodically(context.TODO(), now.Add(time.Hour))
+		require.Equal(6, countProofs())
+	})
+}
+
+func TestValidateRetentionPolicies(t *testing.T) {
+	cases := []struct {
+		name     string
+		policies []RetentionPolicy
+		err      string
+	}{
+		{"valid", []RetentionPolicy{{Artifact: GCArtifactProofArchive, MaxAge: 1}, {Artifact: GCArtifactUnfinalizedEKI, MaxCount: 1}}, ""},
+		{"unknown artifact", []RetentionPolicy{{Artifact: "audit_log", MaxAge: 1}}, "Artifact must be one of"},
+		{"no limits", []RetentionPolicy{{Artifact: GCArtifactProofArchive}}, "either MaxAge or MaxCount must be set"},
+		{"duplicate", []RetentionPolicy{{Artifact: GCArtifactProofArchive, MaxAge: 1}, {Artifact: GCArtifactProofArchive, MaxCount: 1}}, "duplicate policies"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ProverConfig{RetentionPolicies: c.policies}.validateRetentionPolicies()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
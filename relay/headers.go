@@ -0,0 +1,66 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// filterHeadersForUpdate sorts `headers` by height and drops the headers which the ELC client at `latestHeight` cannot apply,
+// i.e. the headers at or below `latestHeight` and the duplicates of a height.
+// Some origin provers return overlapping header ranges, e.g. the last header of the previous batch is repeated as the first of the next one.
+// It returns the remaining headers and the heights of the dropped ones.
+func filterHeadersForUpdate(headers []core.Header, latestHeight ibcexported.Height) ([]core.Header, []ibcexported.Height) {
+	sorted := make([]core.Header, len(headers))
+	copy(sorted, headers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetHeight().LT(sorted[j].GetHeight())
+	})
+	var (
+		filtered []core.Header
+		skipped  []ibcexported.Height
+	)
+	for _, h := range sorted {
+		height := h.GetHeight()
+		if height.LTE(latestHeight) || (len(filtered) > 0 && height.EQ(filtered[len(filtered)-1].GetHeight())) {
+			skipped = append(skipped, height)
+			continue
+		}
+		filtered = append(filtered, h)
+	}
+	return filtered, skipped
+}
+
+// filterHeadersForELC applies filterHeadersForUpdate to the headers for the ELC client `elcClientID` at `latestHeight` and logs the skipped heights
+func (pr *Prover) filterHeadersForELC(elcClientID string, headers []core.Header, latestHeight ibcexported.Height) []core.Header {
+	filtered, skipped := filterHeadersForUpdate(headers, latestHeight)
+	if len(skipped) > 0 {
+		pr.getLogger().Warn(
+			"skip the headers which the ELC client cannot apply",
+			"elc_client_id", elcClientID,
+			"client_state.latest_height", latestHeight,
+			"skipped_heights", fmt.Sprint(skipped),
+			"num_headers", len(headers),
+		)
+	}
+	return filtered
+}
+
+// queryELCLatestHeight returns the latest height of the ELC client `elcClientID`
+func (pr *Prover) queryELCLatestHeight(ctx context.Context, elcClientID string) (ibcexported.Height, error) {
+	res, err := pr.lcpServiceClient.Client(ctx, &elc.QueryClientRequest{ClientId: elcClientID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the ELC client: elc_client_id=%v %w", elcClientID, err)
+	} else if !res.Found {
+		return nil, fmt.Errorf("client not found: client_id=%v", elcClientID)
+	}
+	var clientState ibcexported.ClientState
+	if err := pr.codec.UnpackAny(res.ClientState, &clientState); err != nil {
+		return nil, fmt.Errorf("failed to unpack the ELC client state: elc_client_id=%v %w", elcClientID, err)
+	}
+	return clientState.GetLatestHeight(), nil
+}
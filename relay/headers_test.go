@@ -0,0 +1,143 @@
+package relay
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func newTestOriginHeader(t *testing.T, height uint64) core.Header {
+	headers, err := newTestOriginHeaders(clienttypes.NewHeight(0, height), 1)
+	require.NoError(t, err)
+	return headers[0]
+}
+
+// mockOverlappingOriginProver is the prover of an origin chain which returns `headers` as they are
+type mockOverlappingOriginProver struct {
+	mockSelfTestOriginProver
+	headers []core.Header
+}
+
+func (p mockOverlappingOriginProver) SetupHeadersForUpdate(counterparty core.FinalityAwareChain, latestFinalizedHeader core.Header) ([]core.Header, error) {
+	return p.headers, nil
+}
+
+// mockRecordingLCPService records the heights of the headers applied to the ELC client
+type mockRecordingLCPService struct {
+	*mockLCPService
+	applied []exported.Height
+}
+
+func (s *mockRecordingLCPService) UpdateClient(ctx context.Context, in *elc.MsgUpdateClient, opts ...grpc.CallOption) (*elc.MsgUpdateClientResponse, error) {
+	var header lcptypes.UpdateClientMessage
+	if err := header.Unmarshal(in.Header.Value); err != nil {
+		return nil, err
+	}
+	s.applied = append(s.applied, header.GetHeight())
+	return s.mockLCPService.UpdateClient(ctx, in, opts...)
+}
+
+func TestFilterHeadersForUpdate(t *testing.T) {
+	var cases = []struct {
+		name     string
+		heights  []uint64
+		latest   uint64
+		expected []uint64
+		skipped  []uint64
+	}{
+		{"no overlap", []uint64{6, 7, 8}, 5, []uint64{6, 7, 8}, nil},
+		{"repeated last header of the previous batch", []uint64{5, 6, 7}, 5, []uint64{6, 7}, []uint64{5}},
+		{"duplicates", []uint64{6, 7, 7, 8, 8}, 5, []uint64{6, 7, 8}, []uint64{7, 8}},
+		{"out of order", []uint64{8, 6, 7}, 5, []uint64{6, 7, 8}, nil},
+		{"overlapping and out of order", []uint64{7, 4, 6, 5, 7, 8}, 5, []uint64{6, 7, 8}, []uint64{4, 5, 7}},
+		{"all applied", []uint64{3, 4, 5}, 5, nil, []uint64{3, 4, 5}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			var headers []core.Header
+			for _, h := range c.heights {
+				headers = append(headers, newTestOriginHeader(t, h))
+			}
+			filtered, skipped := filterHeadersForUpdate(headers, clienttypes.NewHeight(0, c.latest))
+			var filteredHeights, skippedHeights []uint64
+			for _, h := range filtered {
+				filteredHeights = append(filteredHeights, h.GetHeight().GetRevisionHeight())
+			}
+			for _, h := range skipped {
+				skippedHeights = append(skippedHeights, h.GetRevisionHeight())
+			}
+			require.Equal(c.expected, filteredHeights)
+			require.Equal(c.skipped, skippedHeights)
+		})
+	}
+}
+
+func TestSetupHeadersForUpdateWithOverlappingHeaders(t *testing.T) {
+	const elcClientID = "07-tendermint-0"
+	newProver := func(t *testing.T, heights ...uint64) (*Prover, *mockRecordingLCPService) {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		service := &mockRecordingLCPService{mockLCPService: &mockLCPService{t: t, key: key, clients: map[string]*lcptypes.ClientState{
+			elcClientID: {LatestHeight: clienttypes.NewHeight(0, 5)},
+		}}}
+		var headers []core.Header
+		for _, h := range heights {
+			headers = append(headers, newTestOriginHeader(t, h))
+		}
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = t.TempDir()
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.ElcClientId = elcClientID
+		pr.originProver = mockOverlappingOriginProver{mockSelfTestOriginProver: mockSelfTestOriginProver{latestHeight: clienttypes.NewHeight(0, 10)}, headers: headers}
+		pr.lcpServiceClient = LCPServiceClient{ELCMsgClient: service, ELCQueryClient: service}
+		pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: crypto.PubkeyToAddress(key.PublicKey).Bytes()}
+		require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr, service
+	}
+	appliedHeights := func(service *mockRecordingLCPService) []uint64 {
+		var heights []uint64
+		for _, h := range service.applied {
+			heights = append(heights, h.GetRevisionHeight())
+		}
+		return heights
+	}
+
+	t.Run("setupHeadersForUpdate", func(t *testing.T) {
+		require := require.New(t)
+		pr, service := newProver(t, 5, 7, 6, 7, 8)
+		updates, err := pr.setupHeadersForUpdate(context.TODO(), newMockCounterparty(clienttypes.NewHeight(0, 1)), mockHeader{height: clienttypes.NewHeight(0, 8)})
+		require.NoError(err)
+		require.Len(updates, 3)
+		require.Equal([]uint64{6, 7, 8}, appliedHeights(service))
+	})
+
+	t.Run("setupHeadersForUpdate without new headers", func(t *testing.T) {
+		require := require.New(t)
+		pr, service := newProver(t, 4, 5)
+		updates, err := pr.setupHeadersForUpdate(context.TODO(), newMockCounterparty(clienttypes.NewHeight(0, 1)), mockHeader{height: clienttypes.NewHeight(0, 5)})
+		require.NoError(err)
+		require.Nil(updates)
+		require.Empty(service.applied)
+	})
+
+	t.Run("updateELC", func(t *testing.T) {
+		require := require.New(t)
+		pr, service := newProver(t, 5, 6, 6, 9, 7, 8, 10)
+		responses, err := pr.updateELC(elcClientID, false, nil)
+		require.NoError(err)
+		require.Len(responses, 5)
+		require.Equal([]uint64{6, 7, 8, 9, 10}, appliedHeights(service))
+	})
+}
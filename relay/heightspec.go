@@ -0,0 +1,109 @@
+package relay
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+)
+
+// ErrPastRevisionNotSupported is returned if a height of a past revision of the origin chain is given,
+// but the origin prover cannot create the initial light client state at the height
+var ErrPastRevisionNotSupported = errors.New("the origin prover does not support creating the ELC client at a past revision")
+
+// PastRevisionProver is implemented by the origin provers which can create the initial light client states at the heights of the past revisions
+type PastRevisionProver interface {
+	SupportsPastRevisions() bool
+}
+
+// HeightSpec is a height of the origin chain given by the user.
+// If RevisionNumber is nil, the current revision of the origin chain is used.
+// A zero RevisionHeight means the latest height.
+type HeightSpec struct {
+	RevisionNumber *uint64
+	RevisionHeight uint64
+}
+
+// LatestHeightSpec is the latest height of the origin chain
+var LatestHeightSpec = HeightSpec{}
+
+// NewHeightSpec returns the height spec of `height`
+func NewHeightSpec(height clienttypes.Height) HeightSpec {
+	revisionNumber := height.RevisionNumber
+	return HeightSpec{RevisionNumber: &revisionNumber, RevisionHeight: height.RevisionHeight}
+}
+
+// ParseHeightSpec parses a height in one of the following forms:
+//   - "" or "0": the latest height
+//   - "{revision height}": the height at the current revision
+//   - "{revision number}-{revision height}" or "{revision number}/{revision height}": the height at the revision
+func ParseHeightSpec(s string) (HeightSpec, error) {
+	if s == "" {
+		return LatestHeightSpec, nil
+	}
+	sep := strings.IndexAny(s, "-/")
+	if sep < 0 {
+		height, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return HeightSpec{}, fmt.Errorf("invalid height: value=%q %w", s, err)
+		}
+		return HeightSpec{RevisionHeight: height}, nil
+	}
+	revisionNumber, err := strconv.ParseUint(s[:sep], 10, 64)
+	if err != nil {
+		return HeightSpec{}, fmt.Errorf("invalid revision number: value=%q %w", s, err)
+	}
+	height, err := strconv.ParseUint(s[sep+1:], 10, 64)
+	if err != nil {
+		return HeightSpec{}, fmt.Errorf("invalid revision height: value=%q %w", s, err)
+	} else if height == 0 {
+		return HeightSpec{}, fmt.Errorf("revision height must be greater than 0 if the revision number is given: value=%q", s)
+	}
+	return HeightSpec{RevisionNumber: &revisionNumber, RevisionHeight: height}, nil
+}
+
+func (h HeightSpec) IsLatest() bool {
+	return h.RevisionHeight == 0
+}
+
+func (h HeightSpec) String() string {
+	if h.IsLatest() {
+		return "latest"
+	} else if h.RevisionNumber == nil {
+		return strconv.FormatUint(h.RevisionHeight, 10)
+	}
+	return clienttypes.NewHeight(*h.RevisionNumber, h.RevisionHeight).String()
+}
+
+// resolveHeightSpec resolves `h` to a height lower than `latest` of the origin chain.
+// The heights of the past revisions are allowed only if `pastRevisions` is true.
+func resolveHeightSpec(h HeightSpec, latest exported.Height, pastRevisions bool) (clienttypes.Height, error) {
+	if h.IsLatest() {
+		return clienttypes.NewHeight(latest.GetRevisionNumber(), latest.GetRevisionHeight()), nil
+	}
+	revisionNumber := latest.GetRevisionNumber()
+	if h.RevisionNumber != nil {
+		revisionNumber = *h.RevisionNumber
+	}
+	height := clienttypes.NewHeight(revisionNumber, h.RevisionHeight)
+	switch {
+	case revisionNumber > latest.GetRevisionNumber():
+		return clienttypes.Height{}, fmt.Errorf("the revision number is greater than the current revision of the origin chain: height=%v latest=%v", height, latest)
+	case revisionNumber < latest.GetRevisionNumber():
+		if !pastRevisions {
+			return clienttypes.Height{}, fmt.Errorf("%w: height=%v latest=%v", ErrPastRevisionNotSupported, height, latest)
+		}
+	case h.RevisionHeight >= latest.GetRevisionHeight():
+		return clienttypes.Height{}, fmt.Errorf("height %v is greater than or equal to the latest height %v", height, latest)
+	}
+	return height, nil
+}
+
+// supportsPastRevisions returns true if `prover` can create the initial light client states at the past revisions
+func supportsPastRevisions(prover any) bool {
+	p, ok := prover.(PastRevisionProver)
+	return ok && p.SupportsPastRevisions()
+}
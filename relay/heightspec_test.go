@@ -0,0 +1,146 @@
+package relay
+
+import (
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func TestParseHeightSpec(t *testing.T) {
+	var cases = []struct {
+		input    string
+		expected HeightSpec
+		err      string
+	}{
+		{"", LatestHeightSpec, ""},
+		{"0", LatestHeightSpec, ""},
+		{"100", HeightSpec{RevisionHeight: 100}, ""},
+		{"1-100", HeightSpec{RevisionNumber: uint64Ptr(1), RevisionHeight: 100}, ""},
+		{"1/100", HeightSpec{RevisionNumber: uint64Ptr(1), RevisionHeight: 100}, ""},
+		{"0-100", HeightSpec{RevisionNumber: uint64Ptr(0), RevisionHeight: 100}, ""},
+		{"abc", HeightSpec{}, "invalid height"},
+		{"-100", HeightSpec{}, "invalid revision number"},
+		{"1-", HeightSpec{}, "invalid revision height"},
+		{"1-2-3", HeightSpec{}, "invalid revision height"},
+		{"1-0", HeightSpec{}, "revision height must be greater than 0"},
+	}
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			h, err := ParseHeightSpec(c.input)
+			if c.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, c.expected, h)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestResolveHeightSpec(t *testing.T) {
+	latest := clienttypes.NewHeight(2, 100)
+	var cases = []struct {
+		name          string
+		spec          HeightSpec
+		pastRevisions bool
+		expected      clienttypes.Height
+		err           string
+	}{
+		{"latest", LatestHeightSpec, false, latest, ""},
+		{"current revision", HeightSpec{RevisionHeight: 50}, false, clienttypes.NewHeight(2, 50), ""},
+		{"explicit current revision", NewHeightSpec(clienttypes.NewHeight(2, 50)), false, clienttypes.NewHeight(2, 50), ""},
+		{"latest height", HeightSpec{RevisionHeight: 100}, false, clienttypes.Height{}, "greater than or equal to the latest height"},
+		{"future revision", NewHeightSpec(clienttypes.NewHeight(3, 1)), false, clienttypes.Height{}, "greater than the current revision"},
+		{"past revision", NewHeightSpec(clienttypes.NewHeight(1, 500)), false, clienttypes.Height{}, ErrPastRevisionNotSupported.Error()},
+		{"supported past revision", NewHeightSpec(clienttypes.NewHeight(1, 500)), true, clienttypes.NewHeight(1, 500), ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, err := resolveHeightSpec(c.spec, latest, c.pastRevisions)
+			if c.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, c.expected, h)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestHeightSpecFromFlags(t *testing.T) {
+	parse := func(args ...string) (HeightSpec, error) {
+		cmd := heightSpecFlags(&cobra.Command{})
+		require.NoError(t, cmd.ParseFlags(args))
+		return heightSpecFromFlags(cmd)
+	}
+	h, err := parse()
+	require.NoError(t, err)
+	require.Equal(t, LatestHeightSpec, h)
+
+	// a string with the revision number
+	h, err = parse("--height", "1-100")
+	require.NoError(t, err)
+	require.Equal(t, NewHeightSpec(clienttypes.NewHeight(1, 100)), h)
+
+	// two flags
+	h, err = parse("--height", "100", "--revision_number", "1")
+	require.NoError(t, err)
+	require.Equal(t, NewHeightSpec(clienttypes.NewHeight(1, 100)), h)
+
+	_, err = parse("--revision_number", "1")
+	require.ErrorContains(t, err, "--revision_number requires --height")
+	_, err = parse("--height", "1-100", "--revision_number", "1")
+	require.ErrorContains(t, err, "cannot be used with a height which has a revision number")
+}
+
+type mockPastRevisionOriginProver struct {
+	mockSelfTestOriginProver
+}
+
+var _ PastRevisionProver = mockPastRevisionOriginProver{}
+
+func (p mockPastRevisionOriginProver) SupportsPastRevisions() bool {
+	return true
+}
+
+func TestCreateELCHeight(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	require := require.New(t)
+	f := newBootstrapFixture(t)
+	latest := clienttypes.NewHeight(2, 10)
+	f.pr.originProver = mockSelfTestOriginProver{latestHeight: latest}
+
+	res, err := f.pr.doCreateELC("elc-client-1", LatestHeightSpec)
+	require.NoError(err)
+	require.Equal(latest, res.Height)
+	require.Equal(latest, f.service.clients["elc-client-1"].LatestHeight)
+
+	res, err = f.pr.doCreateELC("elc-client-2", HeightSpec{RevisionHeight: 5})
+	require.NoError(err)
+	require.Equal(clienttypes.NewHeight(2, 5), res.Height)
+
+	// the origin prover cannot create the client at a past revision
+	past := clienttypes.NewHeight(1, 20)
+	_, err = f.pr.doCreateELC("elc-client-3", NewHeightSpec(past))
+	require.ErrorIs(err, ErrPastRevisionNotSupported)
+	require.NotContains(f.service.clients, "elc-client-3")
+
+	// the fallback origin provers must support it as well
+	f.pr.originProver = newOriginProverSet(f.pr.originChain, []core.Prover{mockPastRevisionOriginProver{mockSelfTestOriginProver{latestHeight: latest}}, mockSelfTestOriginProver{latestHeight: latest}})
+	_, err = f.pr.doCreateELC("elc-client-3", NewHeightSpec(past))
+	require.ErrorIs(err, ErrPastRevisionNotSupported)
+
+	f.pr.originProver = mockPastRevisionOriginProver{mockSelfTestOriginProver{latestHeight: latest}}
+	res, err = f.pr.doCreateELC("elc-client-3", NewHeightSpec(past))
+	require.NoError(err)
+	require.Equal(past, res.Height)
+}
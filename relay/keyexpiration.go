@@ -0,0 +1,171 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// EnclaveKeyExpiration compares the expiration of an enclave key on the counterparty LCP client
+// with the one assumed by the prover to rotate the key
+type EnclaveKeyExpiration struct {
+	EnclaveKey string `json:"enclave_key"`
+	// OnChainExpiredAt is the expiration that the LCP client records on the registration of the key,
+	// i.e. the timestamp of the AVR plus `KeyExpiration` of the client state
+	OnChainKeyExpiration uint64    `json:"on_chain_key_expiration"`
+	OnChainExpiredAt     time.Time `json:"on_chain_expired_at"`
+	// LocalExpiredAt is the expiration assumed by the prover, i.e. the attestation time plus EffectiveKeyExpiration
+	LocalKeyExpiration uint64    `json:"local_key_expiration"`
+	LocalExpiredAt     time.Time `json:"local_expired_at"`
+	// EffectiveKeyExpiration is the key expiration that the prover uses to rotate the key.
+	// It is OnChainKeyExpiration if `prefer_on_chain_key_expiration` is set, otherwise LocalKeyExpiration.
+	EffectiveKeyExpiration uint64                `json:"effective_key_expiration"`
+	KeyExpirationRelation  KeyExpirationRelation `json:"key_expiration_relation"`
+	// RotationTime is the time after which the prover rotates the key
+	RotationTime time.Time `json:"rotation_time"`
+	// Discrepancy is OnChainExpiredAt minus LocalExpiredAt
+	Discrepancy string `json:"discrepancy"`
+	// Mismatch is true if the discrepancy exceeds the rotation buffer, i.e. the time between RotationTime and LocalExpiredAt.
+	// In particular, the key expires on-chain before the rotation if the discrepancy is negative.
+	Mismatch bool `json:"mismatch"`
+}
+
+// computeEnclaveKeyExpiration computes the expirations of `eki` on `clientState` and in the prover
+func (pr *Prover) computeEnclaveKeyExpiration(eki *enclave.EnclaveKeyInfo, clientState *lcptypes.ClientState) (*EnclaveKeyExpiration, error) {
+	onChainAttestationTime, err := onChainAttestationTime(eki)
+	if err != nil {
+		return nil, err
+	}
+	onChainExpiredAt := clientState.KeyExpiredAt(onChainAttestationTime)
+
+	attestationTime, err := localAttestationTime(eki)
+	if err != nil {
+		return nil, err
+	}
+	localExpiredAt := attestationTime.Add(pr.keyExpiration())
+	rotationTime := pr.keyRotationTime(attestationTime)
+
+	discrepancy := onChainExpiredAt.Sub(localExpiredAt)
+	buffer := localExpiredAt.Sub(rotationTime)
+	return &EnclaveKeyExpiration{
+		EnclaveKey:             common.BytesToAddress(eki.EnclaveKeyAddress).Hex(),
+		OnChainKeyExpiration:   clientState.KeyExpiration,
+		OnChainExpiredAt:       onChainExpiredAt.UTC(),
+		LocalKeyExpiration:     pr.config.KeyExpiration,
+		LocalExpiredAt:         localExpiredAt.UTC(),
+		EffectiveKeyExpiration: uint64(pr.keyExpiration() / time.Second),
+		KeyExpirationRelation:  compareKeyExpiration(pr.config.KeyExpiration, clientState.KeyExpiration),
+		RotationTime:           rotationTime.UTC(),
+		Discrepancy:            discrepancy.String(),
+		Mismatch:               discrepancy > buffer || -discrepancy > buffer,
+	}, nil
+}
+
+// QueryEnclaveKeyExpiration returns the expirations of `eki` on the counterparty LCP client and in the prover.
+// It warns if they differ by more than the rotation buffer, which usually means that `KeyExpiration` of the config
+// does not match the one of the client state.
+func (pr *Prover) QueryEnclaveKeyExpiration(ctx context.Context, counterparty core.FinalityAwareChain, eki *enclave.EnclaveKeyInfo) (*EnclaveKeyExpiration, error) {
+	cpQueryHeight, err := counterparty.LatestHeight()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	res, err := counterparty.QueryClientState(core.NewQueryContext(ctx, cpQueryHeight))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the client state on the counterparty chain: %w", err)
+	}
+	var cs ibcexported.ClientState
+	if err := pr.codec.UnpackAny(res.ClientState, &cs); err != nil {
+		return nil, fmt.Errorf("failed to unpack client state: %w", err)
+	}
+	clientState, ok := cs.(*lcptypes.ClientState)
+	if !ok {
+		return nil, fmt.Errorf("unexpected client state type: expected=%T actual=%T", &lcptypes.ClientState{}, cs)
+	}
+	pr.observeCounterpartyKeyExpiration(clientState)
+	pr.observeCounterpartyMessageVersions(clientState)
+	expiration, err := pr.computeEnclaveKeyExpiration(eki, clientState)
+	if err != nil {
+		return nil, err
+	}
+	if expiration.Mismatch {
+		pr.getLogger().Warn(
+			"the on-chain expiration of the enclave key differs from the local one by more than the rotation buffer",
+			"enclave_key", expiration.EnclaveKey,
+			"on_chain_key_expiration", expiration.OnChainKeyExpiration,
+			"on_chain_expired_at", expiration.OnChainExpiredAt,
+			"local_key_expiration", expiration.LocalKeyExpiration,
+			"local_expired_at", expiration.LocalExpiredAt,
+			"rotation_time", expiration.RotationTime,
+		)
+	}
+	return expiration, nil
+}
+
+// doEnclaveKeyExpiration returns the expirations of the active enclave key.
+// If no key is active in memory, the last registered key is loaded as UpdateEKIfNeeded does.
+func (pr *Prover) doEnclaveKeyExpiration(ctx context.Context, counterparty core.FinalityAwareChain) (*EnclaveKeyExpiration, error) {
+	eki := pr.activeEnclaveKey
+	if eki == nil {
+		var err error
+		eki, _, _, err = pr.loadLastUnfinalizedEnclaveKey(ctx)
+		if errors.Is(err, ErrEnclaveKeyInfoNotFound) {
+			eki, err = pr.loadLastFinalizedEnclaveKey(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the enclave key: %w", err)
+		}
+	}
+	return pr.QueryEnclaveKeyExpiration(ctx, counterparty, eki)
+}
+
+// KeyExpirationRelation is the relation of `KeyExpiration` of the config to the one of the counterparty LCP client
+type KeyExpirationRelation string
+
+const (
+	KeyExpirationEqual KeyExpirationRelation = "equal"
+	// the keys may expire on-chain before the prover rotates them
+	KeyExpirationConfigLarger KeyExpirationRelation = "config_larger"
+	// the prover rotates the keys earlier than necessary
+	KeyExpirationConfigSmaller KeyExpirationRelation = "config_smaller"
+)
+
+// compareKeyExpiration returns the relation of `configured` to `onChain`
+func compareKeyExpiration(configured, onChain uint64) KeyExpirationRelation {
+	switch {
+	case configured > onChain:
+		return KeyExpirationConfigLarger
+	case configured < onChain:
+		return KeyExpirationConfigSmaller
+	default:
+		return KeyExpirationEqual
+	}
+}
+
+// observeCounterpartyKeyExpiration records the key expiration of the counterparty LCP client
+// and alerts if it differs from `KeyExpiration` of the config, e.g. after a governance proposal changed the client parameters.
+func (pr *Prover) observeCounterpartyKeyExpiration(clientState *lcptypes.ClientState) KeyExpirationRelation {
+	pr.counterpartyKeyExpiration = time.Duration(clientState.KeyExpiration) * time.Second
+	relation := compareKeyExpiration(pr.config.KeyExpiration, clientState.KeyExpiration)
+	var message string
+	switch relation {
+	case KeyExpirationConfigLarger:
+		message = "the key expiration of the config is larger than the one of the counterparty LCP client, so the enclave keys may expire on-chain before the rotation"
+	case KeyExpirationConfigSmaller:
+		message = "the key expiration of the config is smaller than the one of the counterparty LCP client, so the enclave keys are rotated earlier than necessary"
+	default:
+		return relation
+	}
+	pr.alert(AlertKeyExpirationMismatch, pr.counterpartyClientID(), message,
+		"config_key_expiration", time.Duration(pr.config.KeyExpiration)*time.Second,
+		"on_chain_key_expiration", pr.counterpartyKeyExpiration,
+		"effective_key_expiration", pr.keyExpiration(),
+	)
+	return relation
+}
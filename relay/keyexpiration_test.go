@@ -0,0 +1,121 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryEnclaveKeyExpiration(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	eki := loadTestEnclaveKeyInfo(t)
+	avr, err := ias.ParseAndValidateAVR([]byte(eki.Report))
+	require.NoError(t, err)
+	attestationTime := avr.GetTimestamp()
+	eki.AttestationTime = uint64(attestationTime.Unix())
+
+	var cases = []struct {
+		name                 string
+		onChainKeyExpiration uint64
+		discrepancy          time.Duration
+		mismatch             bool
+	}{
+		{"same", 3600, 0, false},
+		{"shorter within the buffer", 3000, -10 * time.Minute, false},
+		{"longer within the buffer", 5400, 30 * time.Minute, false},
+		// the key expires on-chain before the rotation
+		{"shorter than the rotation time", 1200, -40 * time.Minute, true},
+		{"longer than the buffer", 7200, time.Hour, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			pr.codec = newTestCodec()
+			counterparty := &mockCounterparty{
+				chainID:      "counterparty",
+				latestHeight: clienttypes.NewHeight(0, 10),
+				clientState:  &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, 1), KeyExpiration: c.onChainKeyExpiration},
+			}
+
+			expiration, err := pr.QueryEnclaveKeyExpiration(context.TODO(), counterparty, eki)
+			require.NoError(err)
+			require.Equal(c.onChainKeyExpiration, expiration.OnChainKeyExpiration)
+			require.Equal(uint64(3600), expiration.LocalKeyExpiration)
+			// the LCP client computes the expiration from the timestamp of the AVR
+			require.Equal(attestationTime.Add(time.Duration(c.onChainKeyExpiration)*time.Second).UTC(), expiration.OnChainExpiredAt)
+			require.Equal(time.Unix(int64(eki.AttestationTime), 0).Add(time.Hour).UTC(), expiration.LocalExpiredAt)
+			require.Equal(time.Unix(int64(eki.AttestationTime), 0).Add(30*time.Minute).UTC(), expiration.RotationTime)
+			// the AVR timestamp has the sub-second precision, but the attestation time does not
+			discrepancy, err := time.ParseDuration(expiration.Discrepancy)
+			require.NoError(err)
+			require.InDelta(c.discrepancy, discrepancy, float64(time.Second))
+			require.Equal(c.mismatch, expiration.Mismatch)
+			require.Equal(uint64(3600), expiration.EffectiveKeyExpiration)
+		})
+	}
+}
+
+func TestObserveCounterpartyKeyExpiration(t *testing.T) {
+	var cases = []struct {
+		name                 string
+		onChainKeyExpiration uint64
+		relation             KeyExpirationRelation
+	}{
+		{"equal", 3600, KeyExpirationEqual},
+		{"config larger", 1800, KeyExpirationConfigLarger},
+		{"config smaller", 7200, KeyExpirationConfigSmaller},
+	}
+	for _, c := range cases {
+		for _, preferOnChain := range []bool{false, true} {
+			t.Run(c.name, func(t *testing.T) {
+				require := require.New(t)
+				srv, payloads := newAlertCaptureServer(t, http.StatusOK)
+				pr := newTestProver(t)
+				pr.originChain = &mockCounterparty{chainID: "origin"}
+				pr.config.AlertWebhookUrl = srv.URL
+				pr.config.PreferOnChainKeyExpiration = preferOnChain
+				alerter, err := newProverAlerter(pr.config)
+				require.NoError(err)
+				pr.alerter = alerter
+				attestationTime := time.Unix(1700000000, 0)
+
+				// the config is used until the client state is queried
+				require.Equal(time.Hour, pr.keyExpiration())
+
+				relation := pr.observeCounterpartyKeyExpiration(&lcptypes.ClientState{KeyExpiration: c.onChainKeyExpiration})
+				require.Equal(c.relation, relation)
+				onChain := time.Duration(c.onChainKeyExpiration) * time.Second
+				if preferOnChain {
+					require.Equal(onChain, pr.keyExpiration())
+					require.Equal(attestationTime.Add(onChain/2), pr.keyRotationTime(attestationTime))
+				} else {
+					require.Equal(time.Hour, pr.keyExpiration())
+					require.Equal(attestationTime.Add(30*time.Minute), pr.keyRotationTime(attestationTime))
+				}
+
+				pr.alerter.wait()
+				if c.relation == KeyExpirationEqual {
+					require.Empty(payloads())
+					return
+				}
+				require.Len(payloads(), 1)
+				var alert Alert
+				require.NoError(json.Unmarshal(payloads()[0], &alert))
+				require.Equal(AlertKeyExpirationMismatch, alert.Condition)
+				require.Equal("1h0m0s", alert.Details["config_key_expiration"])
+				require.Equal(onChain.String(), alert.Details["on_chain_key_expiration"])
+				require.Equal(pr.keyExpiration().String(), alert.Details["effective_key_expiration"])
+			})
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"time"
 
 	"github.com/avast/retry-go"
@@ -18,7 +20,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/hyperledger-labs/yui-relayer/core"
-	oias "github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
 
 	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
 	"github.com/datachainlab/lcp-go/relay/elc"
@@ -33,107 +34,231 @@ type EIP712DomainParams struct {
 
 // UpdateEKIIfNeeded checks if the enclave key needs to be updated
 func (pr *Prover) UpdateEKIfNeeded(ctx context.Context, counterparty core.FinalityAwareChain) error {
+	_, err := pr.updateEKIfNeeded(ctx, counterparty, nil)
+	return err
+}
+
+// firstUpdatesBuilder returns the headers to be submitted to the counterparty chain with the active enclave key
+type firstUpdatesBuilder func() ([]core.Header, error)
+
+// updateEKIfNeeded is the same as UpdateEKIfNeeded, but if `firstUpdates` is not nil and the bundling is enabled,
+// the registration of a new key and the first updates signed by the key are submitted in a single tx.
+// It returns true if the first updates have been submitted with the registration.
+func (pr *Prover) updateEKIfNeeded(ctx context.Context, counterparty core.FinalityAwareChain, firstUpdates firstUpdatesBuilder) (bool, error) {
 	updateNeeded, err := pr.loadEKIAndCheckUpdateNeeded(ctx, counterparty)
 	if err != nil {
-		return fmt.Errorf("failed to call loadEKIAndCheckUpdateNeeded: %w", err)
+		return false, fmt.Errorf("failed to call loadEKIAndCheckUpdateNeeded: %w", err)
 	}
 	pr.getLogger().Info("loadEKIAndCheckUpdateNeeded", "updateNeeded", updateNeeded)
 	if !updateNeeded {
-		return nil
+		return false, nil
+	}
+
+	// another instance for the same LCP client may have registered a new key
+	if adopted, err := pr.adoptSharedRegistration(ctx, counterparty); err != nil {
+		return false, err
+	} else if adopted {
+		pr.countKeyRotation(ctx, keyRotationAdopted)
+		return false, nil
 	}
 
 	// if updateNeeded is true,
 	// query new key and register key and set it to memory and save it to file
 
-	pr.activeEnclaveKey, pr.unfinalizedMsgID = nil, nil
+	pr.activeEnclaveKey, pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = nil, nil, clienttypes.Height{}
 
 	pr.getLogger().Info("need to get a new enclave key")
 
 	eki, err := pr.selectNewEnclaveKey(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to call selectNewEnclaveKey: %w", err)
+		pr.countKeyRotation(ctx, keyRotationFailed)
+		return false, fmt.Errorf("failed to call selectNewEnclaveKey: %w", err)
 	}
 
 	pr.getLogger().Info("try to register a new enclave key", "eki", eki)
 
-	msgID, err := pr.registerEnclaveKey(counterparty, eki)
+	var (
+		msgIDs  []core.MsgID
+		bundled bool
+	)
+	if pr.config.BundleRegisterEnclaveKey && firstUpdates != nil {
+		msgIDs, bundled, err = pr.registerEnclaveKeyWithFirstUpdates(counterparty, eki, firstUpdates)
+	} else {
+		var msgID core.MsgID
+		msgID, err = pr.registerEnclaveKey(counterparty, eki)
+		msgIDs = []core.MsgID{msgID}
+	}
+	if errors.Is(err, errEnclaveKeyRegistered) {
+		pr.recordStats(counterparty, statsEventKeyRotated, 1)
+		pr.countKeyRotation(ctx, keyRotationRegistered)
+		return false, pr.saveAppliedRegistration(ctx, eki)
+	} else if err != nil {
+		pr.activeEnclaveKey = nil
+		pr.countKeyRotation(ctx, keyRotationFailed)
+		return false, fmt.Errorf("failed to call registerEnclaveKey: %w", err)
+	}
+	if pr.IsRehearsal() {
+		// the msg is not submitted, so assume that it is included and finalized immediately
+		pr.getLogger().Info("rehearsal: assume the enclave key registration is finalized", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress))
+		if err := pr.saveFinalizedEnclaveKeyInfo(ctx, eki); err != nil {
+			return false, err
+		}
+		pr.activeEnclaveKey = eki
+		return bundled, nil
+	}
+	// the first msg is always the registration
+	pr.getLogger().Info("registered a new enclave key", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "msg_id", msgIDs[0].String(), "bundled", bundled)
+	pr.recordStats(counterparty, statsEventKeyRotated, 1)
+	pr.countKeyRotation(ctx, keyRotationRegistered)
+	updatesSucceeded, err := pr.saveRegisteredEnclaveKey(ctx, counterparty, eki, msgIDs)
 	if err != nil {
-		return fmt.Errorf("failed to call registerEnclaveKey: %w", err)
+		return false, err
+	}
+	pr.publishSharedRegistration(counterparty, eki, msgIDs[0])
+	if bundled && !updatesSucceeded {
+		// the caller submits the first updates again separately
+		pr.getLogger().Warn("the first updates bundled with the registration failed, so they are submitted separately", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "msg_id", msgIDs[0].String())
+		return false, nil
 	}
-	pr.getLogger().Info("registered a new enclave key", "enclave_key", hex.EncodeToString(eki.EnclaveKeyAddress), "msg_id", msgID.String())
-	finalized, success, err := pr.checkMsgStatus(counterparty, msgID)
+	return bundled, nil
+}
+
+// saveRegisteredEnclaveKey checks the status of the submitted registration msgs
+// and saves the enclave key info as finalized or unfinalized. The first msg must be the registration.
+// The status of the key depends only on the registration, and the msg ID of the registration is tracked until it is finalized
+// even if the following updates are in the same tx. It returns true if all the updates bundled with the registration succeeded.
+func (pr *Prover) saveRegisteredEnclaveKey(ctx context.Context, counterparty core.FinalityAwareChain, eki *enclave.EnclaveKeyInfo, msgIDs []core.MsgID) (bool, error) {
+	// the cached finalized header is older than the block including the msg
+	if pr.counterpartyFinalizedHeaderCache != nil {
+		pr.counterpartyFinalizedHeaderCache.invalidate()
+	}
+	finalized, includedHeight, updatesSucceeded, err := pr.checkMsgsStatus(counterparty, msgIDs)
 	if err != nil {
-		return fmt.Errorf("failed to call checkMsgStatus: %w", err)
-	} else if !success {
-		return fmt.Errorf("msg(id=%v) execution failed", msgID)
+		pr.activeEnclaveKey, pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = nil, nil, clienttypes.Height{}
+		return false, err
 	}
-	pr.getLogger().Info("check the msg status", "msg_id", msgID.String(), "finalized", finalized, "success", success)
 
 	if finalized {
 		// this path is for chans have instant finality
 		// if the msg is finalized, save the enclave key info as finalized
 		if err := pr.saveFinalizedEnclaveKeyInfo(ctx, eki); err != nil {
-			return err
+			return false, err
 		}
-		pr.activeEnclaveKey = eki
+		pr.activeEnclaveKey, pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = eki, nil, clienttypes.Height{}
 	} else {
 		// if the msg is not finalized, save the enclave key info as unfinalized
-		if err := pr.saveUnfinalizedEnclaveKeyInfo(ctx, eki, msgID); err != nil {
-			return err
+		// with the height of the block including the msg to detect a reorg of the block
+		if err := pr.saveUnfinalizedEnclaveKeyInfo(ctx, eki, msgIDs[0], includedHeight); err != nil {
+			return false, err
 		}
-		pr.activeEnclaveKey = eki
-		pr.unfinalizedMsgID = msgID
+		pr.activeEnclaveKey, pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = eki, msgIDs[0], includedHeight
 	}
+	return updatesSucceeded, nil
+}
 
-	return nil
+// checkMsgsStatus checks the status of the registration msg and the updates submitted with it in the same tx.
+// It returns true if the registration is finalized with the height of the block including it, and whether all the updates succeeded.
+// It returns an error only if the registration failed, because the counterparty chain may apply the msgs of a tx partially,
+// e.g. a multicall continuing after a failed call, and a failed update does not revert the registration then.
+func (pr *Prover) checkMsgsStatus(counterparty core.FinalityAwareChain, msgIDs []core.MsgID) (bool, clienttypes.Height, bool, error) {
+	tracker, err := pr.newFinalityTracker(counterparty)
+	if err != nil {
+		return false, clienttypes.Height{}, false, err
+	}
+	var (
+		registrationFinalized bool
+		includedHeight        clienttypes.Height
+	)
+	updatesSucceeded := true
+	for i, msgID := range msgIDs {
+		height, err := tracker.IsIncluded(msgID)
+		if err != nil {
+			return false, clienttypes.Height{}, false, fmt.Errorf("failed to get the msg result: index=%v %w", i, err)
+		}
+		finalized, success, confirmations, err := pr.checkTrackedMsgStatus(counterparty, tracker, msgID)
+		if err != nil {
+			return false, clienttypes.Height{}, false, fmt.Errorf("failed to call checkTrackedMsgStatus: index=%v %w", i, err)
+		}
+		pr.getLogger().Info("check the msg status", "msg_id", msgID.String(), "finalized", finalized, "success", success, "height", height, "confirmations", confirmations)
+		if i > 0 {
+			if !success {
+				pr.getLogger().Warn("the update submitted with the registration failed", "msg_id", msgID.String(), "index", i)
+				updatesSucceeded = false
+			}
+			continue
+		}
+		if !success {
+			pr.alert(AlertRegistrationFailed, msgID.String(), "the tx registering the enclave key failed")
+			return false, clienttypes.Height{}, false, fmt.Errorf("msg(id=%v) execution failed", msgID)
+		}
+		registrationFinalized, includedHeight = finalized, height
+	}
+	return registrationFinalized, includedHeight, updatesSucceeded, nil
 }
 
 // checkEKIUpdateNeeded checks if the enclave key needs to be updated
 // if the enclave key is missing or expired, it returns true
 func (pr *Prover) checkEKIUpdateNeeded(ctx context.Context, timestamp time.Time, eki *enclave.EnclaveKeyInfo) bool {
-	attestationTime := time.Unix(int64(eki.AttestationTime), 0)
+	timestamp = pr.correctClockSkew(timestamp)
+	attestationTime, err := localAttestationTime(eki)
+	if err != nil {
+		pr.getLogger().Warn("checkEKIUpdateNeeded: failed to get the attestation time", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "error", err)
+		return true
+	}
 
 	// TODO consider appropriate buffer time
-	updateTime := attestationTime.Add(time.Duration(pr.config.KeyExpiration) * time.Second / 2)
-	pr.getLogger().Info("checkEKIUpdateNeeded", "enclave_key", hex.EncodeToString(eki.EnclaveKeyAddress), "now", timestamp.Unix(), "attestation_time", attestationTime.Unix(), "expiration", pr.config.KeyExpiration, "update_time", updateTime.Unix())
+	updateTime := pr.keyRotationTime(attestationTime)
+	pr.getLogger().Info("checkEKIUpdateNeeded", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "now", timestamp.Unix(), "attestation_time", attestationTime.Unix(), "expiration", pr.config.KeyExpiration, "update_time", updateTime.Unix())
 
 	// For now, a half of expiration is used as a buffer time
 	if timestamp.After(updateTime) {
-		pr.getLogger().Info("checkEKIUpdateNeeded: enclave key is expired", "enclave_key", hex.EncodeToString(eki.EnclaveKeyAddress))
+		pr.getLogger().Info("checkEKIUpdateNeeded: enclave key is expired", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress))
 		return true
 	}
 	// check if the enclave key is still available in the LCP service
-	_, err := pr.lcpServiceClient.EnclaveKey(ctx, &enclave.QueryEnclaveKeyRequest{EnclaveKeyAddress: eki.EnclaveKeyAddress})
+	_, err = pr.lcpServiceClient.EnclaveKey(ctx, &enclave.QueryEnclaveKeyRequest{EnclaveKeyAddress: eki.EnclaveKeyAddress})
 	if err != nil {
-		pr.getLogger().Warn("checkEKIUpdateNeeded: enclave key not found", "enclave_key", hex.EncodeToString(eki.EnclaveKeyAddress), "error", err)
+		pr.getLogger().Warn("checkEKIUpdateNeeded: enclave key not found", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "error", err)
 		return true
 	}
 	return false
 }
 
-// isFinalizedMsg checks if the given msg is finalized in the origin chain
-// and returns (finalized, success, error)
-// finalized: true if the msg is finalized
+// checkMsgStatus checks if the given msg is finalized in the origin chain
+// and returns (finalized, success, confirmations, error)
+// finalized: true if the msg is finalized and has the confirmations required by the config
 // success: true if the msg is successfully executed in the origin chain
+// confirmations: the number of the blocks on top of the block including the msg if ConfirmationsRequired is set, otherwise zero
 // error: non-nil if the msg may not exist in the origin chain
-func (pr *Prover) checkMsgStatus(counterparty core.FinalityAwareChain, msgID core.MsgID) (bool, bool, error) {
-	lfHeader, err := counterparty.GetLatestFinalizedHeader()
+func (pr *Prover) checkMsgStatus(counterparty core.FinalityAwareChain, msgID core.MsgID) (bool, bool, uint64, error) {
+	tracker, err := pr.newFinalityTracker(counterparty)
 	if err != nil {
-		return false, false, err
+		return false, false, 0, err
 	}
-	msgRes, err := counterparty.GetMsgResult(msgID)
-	if err != nil {
-		return false, false, err
-	} else if ok, failureReason := msgRes.Status(); !ok {
-		pr.getLogger().Warn("msg execution failed", "msg_id", msgID.String(), "reason", failureReason)
-		return false, false, nil
+	if _, err := tracker.IsIncluded(msgID); err != nil {
+		return false, false, 0, err
 	}
-	return msgRes.BlockHeight().LTE(lfHeader.GetHeight()), true, nil
+	return pr.checkTrackedMsgStatus(counterparty, tracker, msgID)
+}
+
+// getCounterpartyLatestFinalizedHeader returns the latest finalized header of the counterparty chain.
+// The header is cached for a short period to share it with other code paths in the same relay iteration.
+func (pr *Prover) getCounterpartyLatestFinalizedHeader(counterparty core.FinalityAwareChain) (core.Header, error) {
+	if pr.counterpartyFinalizedHeaderCache == nil {
+		return counterparty.GetLatestFinalizedHeader()
+	}
+	return pr.counterpartyFinalizedHeaderCache.get(counterparty)
 }
 
 // if returns true, query new key and register key and set it to memory
 func (pr *Prover) loadEKIAndCheckUpdateNeeded(ctx context.Context, counterparty core.FinalityAwareChain) (bool, error) {
 	now := time.Now()
+	defer func() {
+		// the watchdog only watches the active registration
+		if pr.unfinalizedMsgID == nil {
+			pr.unfinalizedKeyWatchdog.clear()
+		}
+	}()
 
 	// no active enclave key in memory
 	if pr.activeEnclaveKey == nil {
@@ -143,10 +268,11 @@ func (pr *Prover) loadEKIAndCheckUpdateNeeded(ctx context.Context, counterparty
 
 		pr.getLogger().Info("no active enclave key in memory")
 
-		if eki, msgID, err := pr.loadLastUnfinalizedEnclaveKey(ctx); err == nil {
-			pr.getLogger().Info("load last unfinalized enclave key into memory")
+		if eki, msgID, includedHeight, err := pr.loadLastUnfinalizedEnclaveKey(ctx); err == nil {
+			pr.getLogger().Info("load last unfinalized enclave key into memory", "included_height", includedHeight)
 			pr.activeEnclaveKey = eki
 			pr.unfinalizedMsgID = msgID
+			pr.unfinalizedMsgHeight = includedHeight
 		} else if errors.Is(err, ErrEnclaveKeyInfoNotFound) {
 			pr.getLogger().Info("no unfinalized enclave key info found")
 			eki, err := pr.loadLastFinalizedEnclaveKey(ctx)
@@ -159,7 +285,7 @@ func (pr *Prover) loadEKIAndCheckUpdateNeeded(ctx context.Context, counterparty
 			}
 			pr.getLogger().Info("load last finalized enclave key into memory")
 			pr.activeEnclaveKey = eki
-			pr.unfinalizedMsgID = nil
+			pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = nil, clienttypes.Height{}
 		} else {
 			return false, err
 		}
@@ -176,127 +302,271 @@ func (pr *Prover) loadEKIAndCheckUpdateNeeded(ctx context.Context, counterparty
 
 	pr.getLogger().Info("active enclave key is unfinalized")
 
-	if _, err := counterparty.GetMsgResult(pr.unfinalizedMsgID); err != nil {
+	// the key may have been registered by other msgs, e.g. the resubmissions after a crash or a reorg
+	if resolved, err := pr.resolveUnfinalizedEnclaveKeys(ctx, counterparty); err != nil {
+		return false, err
+	} else if resolved {
+		return pr.checkEKIUpdateNeeded(ctx, now, pr.activeEnclaveKey), nil
+	}
+
+	tracker, err := pr.newFinalityTracker(counterparty)
+	if err != nil {
+		return false, err
+	}
+	includedHeight, err := tracker.IsIncluded(pr.unfinalizedMsgID)
+	if err != nil && !pr.unfinalizedMsgHeight.IsZero() {
+		// the msg was included in a block, but it is no longer found
+		return pr.handleMissingRegistration(ctx, counterparty, now, err)
+	} else if err != nil {
 		// err means that the msg is not included in the latest block
 		pr.getLogger().Info("the msg is not included in the latest block", "msg_id", pr.unfinalizedMsgID.String(), "error", err)
-		if err := pr.removeUnfinalizedEnclaveKeyInfo(ctx); err != nil {
-			return false, err
-		}
-		return true, nil
+		return pr.dropActiveRegistration(ctx, counterparty)
 	}
 
-	finalized, success, err := pr.checkMsgStatus(counterparty, pr.unfinalizedMsgID)
-	pr.getLogger().Info("check the unfinalized msg status", "msg_id", pr.unfinalizedMsgID.String(), "finalized", finalized, "success", success, "error", err)
+	finalized, success, confirmations, err := pr.checkTrackedMsgStatus(counterparty, tracker, pr.unfinalizedMsgID)
+	pr.getLogger().Info("check the unfinalized msg status", "msg_id", pr.unfinalizedMsgID.String(), "finalized", finalized, "success", success, "confirmations", confirmations, "error", err)
 	if err != nil {
 		return false, err
 	} else if !success {
 		// tx is failed, so remove the unfinalized enclave key info
 		pr.getLogger().Warn("the msg execution failed", "msg_id", pr.unfinalizedMsgID.String())
-		if err := pr.removeUnfinalizedEnclaveKeyInfo(ctx); err != nil {
-			return false, err
-		}
-		return true, nil
-	} else if finalized {
+		return pr.dropActiveRegistration(ctx, counterparty)
+	}
+	if err := pr.trackRegistrationHeight(ctx, includedHeight); err != nil {
+		return false, err
+	}
+	if finalized {
 		// tx is successfully executed and finalized
 		pr.getLogger().Info("the msg is finalized", "msg_id", pr.unfinalizedMsgID.String())
 		if pr.checkEKIUpdateNeeded(ctx, now, pr.activeEnclaveKey) {
 			return true, nil
 		}
-		pr.getLogger().Info("save enclave key info as finalized", "enclave_key", hex.EncodeToString(pr.activeEnclaveKey.EnclaveKeyAddress))
-		if err := pr.saveFinalizedEnclaveKeyInfo(ctx, pr.activeEnclaveKey); err != nil {
-			return false, err
-		}
-		pr.getLogger().Info("remove old unfinalized enclave key info", "enclave_key", hex.EncodeToString(pr.activeEnclaveKey.EnclaveKeyAddress))
-		if err := pr.removeUnfinalizedEnclaveKeyInfo(ctx); err != nil {
+		// the other outstanding registrations are superseded by the finalized one
+		if err := pr.finalizeEnclaveKeyInfo(ctx, pr.activeEnclaveKey); err != nil {
 			return false, err
 		}
-		pr.unfinalizedMsgID = nil
+		pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = nil, clienttypes.Height{}
 		return false, nil
 	} else {
 		// tx is successfully executed but not finalized yet
 		pr.getLogger().Info("the msg is not finalized yet", "msg_id", pr.unfinalizedMsgID.String())
+		if err := pr.watchUnfinalizedKey(ctx, counterparty, now); err != nil {
+			return false, err
+		}
 		return pr.checkEKIUpdateNeeded(ctx, now, pr.activeEnclaveKey), nil
 	}
 }
 
+// resolveUnfinalizedEnclaveKeys checks the outstanding registrations other than the active one.
+// If any of them is finalized, the first one in the order of submission becomes the active key
+// and the records of the other registrations are removed because they are superseded.
+// The records of the failed registrations are removed.
+// It returns true if the active enclave key is replaced with the finalized one.
+func (pr *Prover) resolveUnfinalizedEnclaveKeys(ctx context.Context, counterparty core.FinalityAwareChain) (bool, error) {
+	records, err := pr.loadUnfinalizedEnclaveKeys(ctx)
+	if err != nil {
+		return false, err
+	} else if len(records) <= 1 {
+		return false, nil
+	}
+	tracker, err := pr.newFinalityTracker(counterparty)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range records {
+		if r.matches(pr.activeEnclaveKey, pr.unfinalizedMsgID) {
+			continue
+		}
+		if _, err := tracker.IsIncluded(r.msgID); err != nil {
+			// the msg may be still pending or dropped, so the record is kept until another registration is finalized
+			pr.getLogger().Info("the outstanding registration is not found", "enclave_key", lcptypes.HexBytes(r.eki.EnclaveKeyAddress), "msg_id", r.msgID.String(), "error", err)
+			continue
+		}
+		finalized, success, _, err := pr.checkTrackedMsgStatus(counterparty, tracker, r.msgID)
+		if err != nil {
+			return false, err
+		} else if !success {
+			pr.getLogger().Warn("the outstanding registration failed", "enclave_key", lcptypes.HexBytes(r.eki.EnclaveKeyAddress), "msg_id", r.msgID.String())
+			if _, err := pr.removeUnfinalizedEnclaveKeyInfo(ctx, r.eki, r.msgID); err != nil {
+				return false, err
+			}
+			continue
+		} else if !finalized {
+			continue
+		}
+		pr.getLogger().Info("the outstanding registration is finalized", "enclave_key", lcptypes.HexBytes(r.eki.EnclaveKeyAddress), "msg_id", r.msgID.String(), "superseded", len(records)-1)
+		if err := pr.finalizeEnclaveKeyInfo(ctx, r.eki); err != nil {
+			return false, err
+		}
+		pr.activeEnclaveKey, pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = r.eki, nil, clienttypes.Height{}
+		return true, nil
+	}
+	return false, nil
+}
+
+// dropActiveRegistration removes the record of the active registration that will never be finalized.
+// If other registrations are outstanding, the most recent one becomes active.
+// Otherwise, it returns true because a new key needs to be registered.
+func (pr *Prover) dropActiveRegistration(ctx context.Context, counterparty core.FinalityAwareChain) (bool, error) {
+	remaining, err := pr.removeUnfinalizedEnclaveKeyInfo(ctx, pr.activeEnclaveKey, pr.unfinalizedMsgID)
+	if err != nil {
+		return false, err
+	} else if remaining == 0 {
+		return true, nil
+	}
+	pr.getLogger().Info("fall back to another outstanding registration", "remaining", remaining)
+	pr.activeEnclaveKey, pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = nil, nil, clienttypes.Height{}
+	return pr.loadEKIAndCheckUpdateNeeded(ctx, counterparty)
+}
+
 // selectNewEnclaveKey selects a new enclave key from the LCP service
 func (pr *Prover) selectNewEnclaveKey(ctx context.Context) (*enclave.EnclaveKeyInfo, error) {
 	res, err := pr.lcpServiceClient.AvailableEnclaveKeys(ctx, &enclave.QueryAvailableEnclaveKeysRequest{Mrenclave: pr.config.GetMrenclave()})
 	if err != nil {
 		return nil, err
-	} else if len(res.Keys) == 0 {
-		return nil, fmt.Errorf("no available enclave keys")
+	}
+	pr.observeAvailableEnclaveKeys(len(res.Keys))
+	if len(res.Keys) == 0 {
+		err := pr.noAvailableEnclaveKeysError(ctx)
+		pr.alert(AlertNoEligibleEnclaveKey, hex.EncodeToString(pr.config.GetMrenclave()), "no enclave keys are available in the LCP service", "error", err)
+		return nil, err
 	}
 
 	for _, eki := range res.Keys {
-		if err := ias.VerifyReport([]byte(eki.Report), eki.Signature, eki.SigningCert, time.Now()); err != nil {
-			return nil, err
+		att, err := pr.verifyAttestation(eki, time.Now())
+		if errors.Is(err, ErrDCAPAttestationDisabled) {
+			pr.getLogger().Info("the key is not allowed to use because DCAP attestations are disabled", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress))
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to verify the attestation: enclave_key=%v %w", lcptypes.HexBytes(eki.EnclaveKeyAddress), err)
 		}
-		avr, err := ias.ParseAndValidateAVR([]byte(eki.Report))
-		if err != nil {
-			return nil, err
+		quote := att.Quote
+		if debug := ias.IsDebugEnclave(quote); debug && !pr.config.AllowDebugEnclaveKeys {
+			pr.getLogger().Warn("the key is not allowed to use because it belongs to a debug-mode enclave", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "debug", debug)
+			continue
+		}
+		if err := ias.CheckISVSVN(quote, uint16(pr.config.MinimumIsvSvn)); err != nil {
+			pr.getLogger().Info("the key is not allowed to use because of ISV SVN", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "isv_svn", quote.Report.ISVSVN, "minimum_isv_svn", pr.config.MinimumIsvSvn)
+			continue
 		}
 		if pr.checkEKIUpdateNeeded(ctx, time.Now(), eki) {
-			pr.getLogger().Info("the key is not allowed to use because of expiration", "enclave_key", hex.EncodeToString(eki.EnclaveKeyAddress))
+			pr.getLogger().Info("the key is not allowed to use because of expiration", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress))
 			continue
 		}
-		if !pr.validateISVEnclaveQuoteStatus(avr.ISVEnclaveQuoteStatus) {
-			pr.getLogger().Info("the key is not allowed to use because of ISVEnclaveQuoteStatus", "enclave_key", hex.EncodeToString(eki.EnclaveKeyAddress), "quote_status", avr.ISVEnclaveQuoteStatus)
+		if !pr.validateQuoteStatus(att.QuoteStatus) {
+			pr.getLogger().Info("the key is not allowed to use because of ISVEnclaveQuoteStatus", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "quote_status", att.QuoteStatus)
 			continue
 		}
-		if !pr.validateAdvisoryIDs(avr.AdvisoryIDs) {
-			pr.getLogger().Info("the key is not allowed to use because of advisory IDs", "enclave_key", hex.EncodeToString(eki.EnclaveKeyAddress), "advisory_ids", avr.AdvisoryIDs)
+		if !pr.validateAdvisoryIDs(att.AdvisoryIDs) {
+			pr.getLogger().Info("the key is not allowed to use because of advisory IDs", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "advisory_ids", att.AdvisoryIDs)
 			continue
 		}
 		return eki, nil
 	}
+	pr.alert(AlertNoEligibleEnclaveKey, hex.EncodeToString(pr.config.GetMrenclave()), "all enclave keys in the LCP service are not allowed to use", "num_keys", len(res.Keys))
 	return nil, fmt.Errorf("no available enclave keys: all keys are not allowed to use")
 }
 
-func (pr *Prover) validateISVEnclaveQuoteStatus(s oias.ISVEnclaveQuoteStatus) bool {
-	if s == oias.QuoteOK {
-		return true
+// ErrMrenclaveMismatch is returned if the LCP service has no keys for the configured MRENCLAVE but has keys for other ones
+var ErrMrenclaveMismatch = errors.New("no enclave keys for the configured MRENCLAVE")
+
+// noAvailableEnclaveKeysError returns an error describing why no keys are available for the configured MRENCLAVE.
+// If the service has keys for other MRENCLAVEs, the error includes them because it typically means that the config is stale.
+func (pr *Prover) noAvailableEnclaveKeysError(ctx context.Context) error {
+	res, err := pr.lcpServiceClient.AvailableEnclaveKeys(ctx, &enclave.QueryAvailableEnclaveKeysRequest{})
+	if err != nil {
+		pr.getLogger().Warn("failed to query all available enclave keys", "error", err)
+		return fmt.Errorf("no available enclave keys")
 	}
-	for _, status := range pr.config.AllowedQuoteStatuses {
-		if s.String() == status {
-			return true
+	observed := mapset.NewThreadUnsafeSet[string]()
+	for _, eki := range res.Keys {
+		mrenclave, err := getMrenclave(eki)
+		if err != nil {
+			pr.getLogger().Warn("failed to get MRENCLAVE from the attestation", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "error", err)
+			continue
 		}
+		observed.Add(lcptypes.HexBytes(mrenclave).String())
 	}
-	return false
+	if observed.Cardinality() == 0 {
+		return fmt.Errorf("no available enclave keys")
+	}
+	values := observed.ToSlice()
+	sort.Strings(values)
+	return fmt.Errorf("%w: expected=%v observed=%v", ErrMrenclaveMismatch, lcptypes.HexBytes(pr.config.GetMrenclave()), values)
 }
 
-func (pr *Prover) validateAdvisoryIDs(ids []string) bool {
-	if len(ids) == 0 {
-		return true
+func getMrenclaveFromReport(report string) ([]byte, error) {
+	avr, err := ias.ParseAndValidateAVR([]byte(report))
+	if err != nil {
+		return nil, err
+	}
+	quote, err := avr.Quote()
+	if err != nil {
+		return nil, err
 	}
-	allowedSet := mapset.NewSet(pr.config.AllowedAdvisoryIds...)
-	targetSet := mapset.NewSet(ids...)
-	return targetSet.Difference(allowedSet).Cardinality() == 0
+	return quote.Report.MRENCLAVE[:], nil
+}
+
+// validateQuoteStatus returns true if the LCP client with the allowed quote statuses accepts `s`
+func (pr *Prover) validateQuoteStatus(s string) bool {
+	return lcptypes.IsAllowedQuoteStatus(pr.allowedQuoteStatuses(), s)
+}
+
+// validateAdvisoryIDs returns true if the LCP client with the allowed advisory IDs accepts `ids`
+func (pr *Prover) validateAdvisoryIDs(ids []string) bool {
+	return lcptypes.AreAllowedAdvisoryIDs(pr.allowedAdvisoryIDs(), ids)
+}
+
+// UpdateELCProgress is the progress of the update of the ELC client reported after each header is applied
+type UpdateELCProgress struct {
+	// the number of the headers applied so far
+	Applied int `json:"applied"`
+	Total   int `json:"total"`
+	// the height and the state ID of the ELC client after the header is applied
+	Height  clienttypes.Height `json:"height"`
+	StateID string             `json:"state_id"`
+	// the time elapsed since the update started
+	Elapsed time.Duration `json:"elapsed"`
 }
 
-func (pr *Prover) updateELC(elcClientID string, includeState bool) ([]*elc.MsgUpdateClientResponse, error) {
+// UpdateELCProgressFunc is called after each header is applied to the ELC client and its response is verified
+type UpdateELCProgressFunc func(UpdateELCProgress)
+
+// updateELC updates the ELC client to the latest finalized header of the origin chain.
+// If `progress` is not nil, it is called after each header is applied.
+func (pr *Prover) updateELC(elcClientID string, includeState bool, progress UpdateELCProgressFunc) ([]*elc.MsgUpdateClientResponse, error) {
+	_, responses, err := pr.updateELCWithHeaders(elcClientID, includeState, progress)
+	return responses, err
+}
+
+// updateELCWithHeaders is updateELC that also returns the headers of the origin chain applied to the ELC client.
+func (pr *Prover) updateELCWithHeaders(elcClientID string, includeState bool, progress UpdateELCProgressFunc) ([]core.Header, []*elc.MsgUpdateClientResponse, error) {
+	start := time.Now()
 
 	// 1. check if the latest height of the client is less than the given height
 
 	res, err := pr.lcpServiceClient.Client(context.TODO(), &elc.QueryClientRequest{ClientId: elcClientID})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if !res.Found {
-		return nil, fmt.Errorf("client not found: client_id=%v", elcClientID)
+		return nil, nil, fmt.Errorf("client not found: client_id=%v", elcClientID)
+	}
+	if err := pr.checkELCOriginClientType(context.TODO(), elcClientID, res.ClientState.TypeUrl); err != nil {
+		return nil, nil, err
 	}
 	latestHeader, err := pr.originProver.GetLatestFinalizedHeader()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var clientState ibcexported.ClientState
 	if err := pr.codec.UnpackAny(res.ClientState, &clientState); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if clientState.GetLatestHeight().GTE(latestHeader.GetHeight()) {
 		pr.getLogger().Info("no need to update the client", "elc_client_id", elcClientID, "client_state.latest_height", clientState.GetLatestHeight(), "latest", latestHeader.GetHeight())
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	pr.getLogger().Info("try to setup headers", "elc_client_id", elcClientID, "client_state.latest_height", clientState.GetLatestHeight(), "latest", latestHeader.GetHeight())
@@ -305,52 +575,163 @@ func (pr *Prover) updateELC(elcClientID string, includeState bool) ([]*elc.MsgUp
 
 	headers, err := pr.originProver.SetupHeadersForUpdate(NewLCPQuerier(pr.lcpServiceClient, elcClientID), latestHeader)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if len(headers) == 0 {
-		return nil, nil
+	if headers = pr.filterHeadersForELC(elcClientID, headers, clientState.GetLatestHeight()); len(headers) == 0 {
+		return nil, nil, nil
 	}
 
 	// 3. send a request that contains a header from 2 to update the client in ELC
+	responses, err := pr.applyHeadersToELC(elcClientID, headers, includeState, progress, start)
+	if err != nil {
+		return nil, nil, err
+	}
+	return headers, responses, nil
+}
+
+// applyHeadersToELC applies `headers` to the ELC client and returns the responses signed by the active enclave key.
+// If `progress` is not nil, it is called after each header is applied with the time elapsed since `start`.
+func (pr *Prover) applyHeadersToELC(elcClientID string, headers []core.Header, includeState bool, progress UpdateELCProgressFunc, start time.Time) ([]*elc.MsgUpdateClientResponse, error) {
 	var responses []*elc.MsgUpdateClientResponse
-	for _, header := range headers {
-		anyHeader, err := clienttypes.PackClientMessage(header)
-		if err != nil {
-			return nil, err
-		}
-		res, err := pr.lcpServiceClient.UpdateClient(context.TODO(), &elc.MsgUpdateClient{
-			ClientId:     elcClientID,
-			Header:       anyHeader,
-			IncludeState: includeState,
-			Signer:       pr.activeEnclaveKey.EnclaveKeyAddress,
-		})
-		if err != nil {
-			return nil, err
+	signer := pr.activeEnclaveKey.EnclaveKeyAddress
+	if err := pr.updateELCClient(context.TODO(), elcClientID, headers, includeState, func(i int, res *elc.MsgUpdateClientResponse) error {
+		if err := verifyEnclaveSignature(res.Message, res.Signature, signer); err != nil {
+			return fmt.Errorf("failed to verify the response of ELC's UpdateClient: elc_client_id=%v %w", elcClientID, err)
 		}
 		responses = append(responses, res)
+		if progress != nil {
+			msg, err := decodeUpdateStateProxyMessage(res.Message)
+			if err != nil {
+				return err
+			}
+			progress(UpdateELCProgress{
+				Applied: len(responses),
+				Total:   len(headers),
+				Height:  msg.PostHeight,
+				StateID: msg.PostStateID.String(),
+				Elapsed: time.Since(start),
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
-
 	return responses, nil
 }
 
 func (pr *Prover) registerEnclaveKey(counterparty core.Chain, eki *enclave.EnclaveKeyInfo) (core.MsgID, error) {
-	clientLogger := pr.getClientLogger(pr.originChain.Path().ClientID)
-	if err := ias.VerifyReport([]byte(eki.Report), eki.Signature, eki.SigningCert, time.Now()); err != nil {
-		return nil, fmt.Errorf("failed to verify AVR signature: %w", err)
+	msg, err := pr.buildRegisterEnclaveKeyMsg(counterparty, eki)
+	if err != nil {
+		return nil, err
 	}
-	avr, err := ias.ParseAndValidateAVR([]byte(eki.Report))
+	return pr.sendRegisterEnclaveKeyMsg(counterparty, eki, msg)
+}
+
+// sendRegisterEnclaveKeyMsg submits the registration of `eki` with the retries on transient failures.
+// It returns errEnclaveKeyRegistered if a failed attempt has been applied on the counterparty chain.
+func (pr *Prover) sendRegisterEnclaveKeyMsg(counterparty core.Chain, eki *enclave.EnclaveKeyInfo, msg sdk.Msg) (core.MsgID, error) {
+	ids, applied, err := pr.sendMsgsWithRetry(counterparty, "register_enclave_key", []sdk.Msg{msg}, pr.enclaveKeyRegisteredFunc(counterparty, eki))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse and validate AVR: %w", err)
+		pr.alert(AlertRegistrationFailed, counterparty.Path().ClientID, "failed to submit the tx registering the enclave key", "error", err)
+		return nil, err
+	} else if applied {
+		return nil, errEnclaveKeyRegistered
 	}
-	quote, err := avr.Quote()
+	if pr.IsRehearsal() {
+		return nil, nil
+	} else if len(ids) != 1 {
+		return nil, fmt.Errorf("unexpected number of msgIDs: %v", ids)
+	}
+	return ids[0], nil
+}
+
+// registerEnclaveKeyWithFirstUpdates submits the registration of the key and the first updates signed by the key in a single tx.
+// It falls back to submit the registration only if the counterparty chain cannot process them in order.
+// In the fallback case, the caller is responsible for submitting the first updates separately.
+func (pr *Prover) registerEnclaveKeyWithFirstUpdates(counterparty core.Chain, eki *enclave.EnclaveKeyInfo, firstUpdates firstUpdatesBuilder) ([]core.MsgID, bool, error) {
+	msg, err := pr.buildRegisterEnclaveKeyMsg(counterparty, eki)
+	if err != nil {
+		return nil, false, err
+	}
+	return pr.sendRegisterEnclaveKeyMsgWithFirstUpdates(counterparty, eki, msg, firstUpdates)
+}
+
+func (pr *Prover) sendRegisterEnclaveKeyMsgWithFirstUpdates(counterparty core.Chain, eki *enclave.EnclaveKeyInfo, registerMsg sdk.Msg, firstUpdates firstUpdatesBuilder) ([]core.MsgID, bool, error) {
+	// the first updates must be signed by the new key
+	pr.activeEnclaveKey = eki
+	headers, err := firstUpdates()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to setup the first updates: %w", err)
+	}
+	if len(headers) == 0 {
+		pr.getLogger().Info("no first updates to bundle with the registration")
+		msgID, err := pr.sendRegisterEnclaveKeyMsg(counterparty, eki, registerMsg)
+		return []core.MsgID{msgID}, false, err
+	}
+	signer, err := counterparty.GetAddress()
+	if err != nil {
+		return nil, false, err
+	}
+	var messages []ibcexported.ClientMessage
+	for _, h := range headers {
+		messages = append(messages, h)
+	}
+	updateMsgs, err := pr.encodeClientMessages(counterparty.Path().ClientID, signer, messages...)
+	if err != nil {
+		return nil, false, err
+	}
+	msgs := append([]sdk.Msg{registerMsg}, updateMsgs...)
+	ids, err := pr.sendMsgs(counterparty, "register_enclave_key_and_update_client", msgs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get quote from AVR: %w", err)
+		if !isBundleOrderingError(err) {
+			pr.alert(AlertRegistrationFailed, counterparty.Path().ClientID, "failed to submit the tx registering the enclave key with the first updates", "error", err)
+			return nil, false, err
+		}
+		pr.getLogger().Warn("the counterparty chain cannot process the bundled msgs in order, fall back to submitting the registration separately", "error", err)
+		msgID, err := pr.sendRegisterEnclaveKeyMsg(counterparty, eki, registerMsg)
+		return []core.MsgID{msgID}, false, err
+	}
+	if pr.IsRehearsal() {
+		return nil, true, nil
+	} else if len(ids) != len(msgs) {
+		return nil, false, fmt.Errorf("unexpected number of msgIDs: expected=%v actual=%v", len(msgs), len(ids))
 	}
+	return ids, true, nil
+}
+
+// isBundleOrderingError returns true if the error indicates that the counterparty chain
+// verified the updates before the registration of the key was applied
+func isBundleOrderingError(err error) bool {
+	return bundleOrderingErrorPattern.MatchString(err.Error())
+}
+
+var bundleOrderingErrorPattern = regexp.MustCompile(`enclave key '[^']*' not found`)
+
+func (pr *Prover) buildRegisterEnclaveKeyMsg(counterparty core.Chain, eki *enclave.EnclaveKeyInfo) (sdk.Msg, error) {
+	clientLogger := pr.getClientLogger(pr.originChain.Path().ClientID)
+	att, err := pr.verifyAttestation(eki, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	quote := att.Quote
 	ek, expectedOperator, err := ias.GetEKAndOperator(quote)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get EK and operator: %w", err)
 	}
-	clientLogger.Info("got EK and operator from report data", "ek", ek.String(), "operator", expectedOperator.String())
+	debug := ias.IsDebugEnclave(quote)
+	clientLogger.Info("got EK and operator from report data", "ek", ek.String(), "operator", expectedOperator.String(), "debug", debug)
+	if debug && !pr.config.AllowDebugEnclaveKeys {
+		return nil, fmt.Errorf("the key belongs to a debug-mode enclave, but AllowDebugEnclaveKeys is false: ek=%v", ek.String())
+	}
+	if err := ias.CheckISVSVN(quote, uint16(pr.config.MinimumIsvSvn)); err != nil {
+		return nil, fmt.Errorf("the key is attested by an outdated enclave: ek=%v %w", ek.String(), err)
+	}
+	if !pr.validateQuoteStatus(att.QuoteStatus) {
+		return nil, fmt.Errorf("the quote status is not allowed by the counterparty: ek=%v quote_status=%v allowed=%v", ek.String(), att.QuoteStatus, pr.allowedQuoteStatuses())
+	}
+	if !pr.validateAdvisoryIDs(att.AdvisoryIDs) {
+		return nil, fmt.Errorf("the advisory IDs are not allowed by the counterparty: ek=%v advisory_ids=%v allowed=%v", ek.String(), att.AdvisoryIDs, pr.allowedAdvisoryIDs())
+	}
 
 	cplatestHeight, err := counterparty.LatestHeight()
 	if err != nil {
@@ -369,26 +750,27 @@ func (pr *Prover) registerEnclaveKey(counterparty core.Chain, eki *enclave.Encla
 		return nil, fmt.Errorf("failed to cast client state: %T", cs)
 	}
 	if !bytes.Equal(clientState.Mrenclave, quote.Report.MRENCLAVE[:]) {
-		return nil, fmt.Errorf("MRENCLAVE mismatch: expected 0x%x, but got 0x%x", clientState.Mrenclave, quote.Report.MRENCLAVE[:])
+		return nil, fmt.Errorf("MRENCLAVE mismatch: expected %v, but got %v", lcptypes.HexBytes(clientState.Mrenclave), lcptypes.HexBytes(quote.Report.MRENCLAVE[:]))
 	}
-	message := &lcptypes.RegisterEnclaveKeyMessage{
-		Report:            []byte(eki.Report),
-		Signature:         eki.Signature,
-		SigningCert:       eki.SigningCert,
-		OperatorSignature: nil,
+	if att.Type == AttestationTypeDCAP && len(clientState.DcapRootCerts) == 0 {
+		return nil, fmt.Errorf("the counterparty client does not accept DCAP attestations: ek=%v", ek.String())
 	}
+	if err := ias.CheckISVSVN(quote, uint16(clientState.MinimumIsvSvn)); err != nil {
+		return nil, fmt.Errorf("the counterparty client does not accept the key: ek=%v %w", ek.String(), err)
+	}
+	var operatorSignature []byte
 	if pr.IsOperatorEnabled() {
 		operator, err := pr.eip712Signer.GetSignerAddress()
 		if err != nil {
 			return nil, err
 		}
-		if operators := clientState.GetOperators(); !containsOperator(operators, operator) {
+		if operators := clientState.GetOperators(); !lcptypes.ContainsOperator(operators, operator) {
 			return nil, fmt.Errorf("the operator is not included in the operators: client_state.operators=%v operator=%v", operators, operator)
 		}
 		if expectedOperator != [20]byte{} && operator != expectedOperator {
-			return nil, fmt.Errorf("operator mismatch: expected 0x%x, but got 0x%x", expectedOperator, operator)
+			return nil, fmt.Errorf("operator mismatch: expected %v, but got %v", expectedOperator, operator)
 		}
-		commitment, err := lcptypes.ComputeEIP712RegisterEnclaveKeyHash(eki.Report)
+		commitment, err := att.operatorCommitment()
 		if err != nil {
 			return nil, err
 		}
@@ -396,25 +778,21 @@ func (pr *Prover) registerEnclaveKey(counterparty core.Chain, eki *enclave.Encla
 		if err != nil {
 			return nil, err
 		}
-		message.OperatorSignature = sig
-		clientLogger.Info("operator signature is generated", "operator", operator.String(), "signature", hex.EncodeToString(sig))
+		if err := pr.checkEIP712Digest(att.eip712PrimaryType(), commitment, contractRegisterEnclaveKeyDigest(att)); err != nil {
+			return nil, err
+		}
+		operatorSignature = sig
+		clientLogger.Info("operator signature is generated", "operator", operator.String(), "signature", lcptypes.HexBytes(sig))
 	}
 	signer, err := counterparty.GetAddress()
 	if err != nil {
 		return nil, err
 	}
-	msg, err := clienttypes.NewMsgUpdateClient(counterparty.Path().ClientID, message, signer.String())
+	msgs, err := pr.encodeClientMessages(counterparty.Path().ClientID, signer, att.registerEnclaveKeyMessage(eki, operatorSignature))
 	if err != nil {
 		return nil, err
 	}
-	ids, err := counterparty.SendMsgs([]sdk.Msg{msg})
-	if err != nil {
-		return nil, err
-	}
-	if len(ids) != 1 {
-		return nil, fmt.Errorf("unexpected number of msgIDs: %v", ids)
-	}
-	return ids[0], nil
+	return msgs[0], nil
 }
 
 func (pr *Prover) ComputeEIP712UpdateOperatorsHash(nonce uint64, newOperators []common.Address, thresholdNumerator, thresholdDenominator uint64) (common.Hash, error) {
@@ -426,6 +804,15 @@ func (pr *Prover) ComputeEIP712UpdateOperatorsHash(nonce uint64, newOperators []
 	return crypto.Keccak256Hash(bz), nil
 }
 
+func (pr *Prover) ComputeEIP712UpdateClientParamsHash(nonce uint64, allowedQuoteStatuses, allowedAdvisoryIDs []string, keyExpiration uint64) (common.Hash, error) {
+	params := pr.getDomainParams()
+	bz, err := lcptypes.ComputeEIP712UpdateClientParams(int64(params.ChainId), params.VerifyingContractAddr, pr.computeEIP712ChainSalt(), pr.path.ClientID, nonce, allowedQuoteStatuses, allowedAdvisoryIDs, keyExpiration)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(bz), nil
+}
+
 func (pr *Prover) getDomainParams() EIP712DomainParams {
 	switch pr.config.ChainType() {
 	case lcptypes.ChainTypeEVM:
@@ -512,30 +899,38 @@ func (pr *Prover) doAvailableEnclaveKeys(ctx context.Context) (*AvailableEnclave
 }
 
 type CreateELCResult struct {
+	// the ID of the client, which is generated if the ID is not given
+	ELCClientID string `json:"elc_client_id"`
+	// the height of the origin chain resolved from the given height spec
+	Height  clienttypes.Height                `json:"height"`
 	Created bool                              `json:"created"`
 	Message *lcptypes.UpdateStateProxyMessage `json:"message,omitempty"`
 }
 
-// height: 0 means the latest height
-func (pr *Prover) doCreateELC(elcClientID string, height uint64) (*CreateELCResult, error) {
+// height: the latest height if it is LatestHeightSpec, and the current revision if its revision number is not given
+// elcClientID: empty means that a new ID is generated
+func (pr *Prover) doCreateELC(elcClientID string, height HeightSpec) (*CreateELCResult, error) {
+	if elcClientID == "" {
+		elcClientID = newELCClientID(time.Now())
+		pr.getLogger().Info("generate the ELC client ID", "elc_client_id", elcClientID)
+	} else if err := validateELCClientID(elcClientID); err != nil {
+		return nil, err
+	}
 	header, err := pr.originProver.GetLatestFinalizedHeader()
 	if err != nil {
 		return nil, err
 	}
-	latestHeight := header.GetHeight()
-	if height == 0 {
-		height = latestHeight.GetRevisionHeight()
-	} else if height >= latestHeight.GetRevisionHeight() {
-		return nil, fmt.Errorf("height %v is greater than the latest height %v", height, latestHeight.GetRevisionHeight())
+	h, err := resolveHeightSpec(height, header.GetHeight(), supportsPastRevisions(pr.originProver))
+	if err != nil {
+		return nil, err
 	}
-	h := clienttypes.NewHeight(latestHeight.GetRevisionNumber(), height)
 	pr.getLogger().Info("try to create ELC client", "elc_client_id", elcClientID, "height", h)
 	res, err := pr.createELC(elcClientID, h)
 	if err != nil {
 		return nil, err
 	} else if res == nil {
 		pr.getLogger().Info("no need to create ELC client", "elc_client_id", elcClientID)
-		return &CreateELCResult{Created: false}, nil
+		return &CreateELCResult{ELCClientID: elcClientID, Height: h, Created: false}, nil
 	}
 	pr.getLogger().Info("created ELC client", "elc_client_id", elcClientID, "height", h)
 	// ensure the message is valid
@@ -549,26 +944,58 @@ func (pr *Prover) doCreateELC(elcClientID string, height uint64) (*CreateELCResu
 	}
 	pr.getLogger().Info("created state", "post_height", m.PostHeight, "post_state_id", m.PostStateID.String(), "timestamp", m.Timestamp.String())
 	return &CreateELCResult{
-		Created: true,
-		Message: m,
+		ELCClientID: elcClientID,
+		Height:      h,
+		Created:     true,
+		Message:     m,
 	}, nil
 }
 
 type UpdateELCResult struct {
 	Messages []*lcptypes.UpdateStateProxyMessage `json:"messages"`
+	// the number of the headers applied to the ELC client
+	HeadersApplied int `json:"headers_applied"`
+	// the wall time of the update
+	WallTime string `json:"wall_time"`
 }
 
-func (pr *Prover) doUpdateELC(elcClientID string) (*UpdateELCResult, error) {
-	if pr.activeEnclaveKey == nil {
-		eki, err := pr.selectNewEnclaveKey(context.TODO())
-		if err != nil {
-			return nil, err
-		}
-		pr.getLogger().Info("use a new enclave key", "enclave_key", hex.EncodeToString(eki.EnclaveKeyAddress))
-		pr.activeEnclaveKey = eki
+// decodeUpdateStateProxyMessage decodes the message of the response of ELC's UpdateClient
+func decodeUpdateStateProxyMessage(message []byte) (*lcptypes.UpdateStateProxyMessage, error) {
+	commitment, err := lcptypes.EthABIDecodeHeaderedProxyMessage(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed EthABIDecodeHeaderedProxyMessage: %w", err)
+	}
+	msg, err := commitment.GetUpdateStateProxyMessage()
+	if err != nil {
+		return nil, fmt.Errorf("failed GetUpdateStateProxyMessage: %w", err)
+	}
+	return msg, nil
+}
+
+// ensureActiveEnclaveKey selects a new enclave key if no key is active, e.g. the prover is not set up for the relay.
+// The key is used only to sign the responses of the ELC, and it is not registered on the counterparty chain.
+func (pr *Prover) ensureActiveEnclaveKey(ctx context.Context) error {
+	if pr.activeEnclaveKey != nil {
+		return nil
+	}
+	eki, err := pr.selectNewEnclaveKey(ctx)
+	if err != nil {
+		return err
+	}
+	pr.getLogger().Info("use a new enclave key", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress))
+	pr.activeEnclaveKey = eki
+	return nil
+}
+
+// doUpdateELC updates the ELC client to the latest finalized header of the origin chain.
+// If `progress` is not nil, it is called after each header is applied.
+func (pr *Prover) doUpdateELC(elcClientID string, progress UpdateELCProgressFunc) (*UpdateELCResult, error) {
+	start := time.Now()
+	if err := pr.ensureActiveEnclaveKey(context.TODO()); err != nil {
+		return nil, err
 	}
 	pr.getLogger().Info("try to update the ELC client", "elc_client_id", elcClientID)
-	updates, err := pr.updateELC(elcClientID, false)
+	updates, err := pr.updateELC(elcClientID, false, progress)
 	if err != nil {
 		return nil, err
 	}
@@ -576,35 +1003,25 @@ func (pr *Prover) doUpdateELC(elcClientID string) (*UpdateELCResult, error) {
 		pr.getLogger().Info("no update is needed")
 		return &UpdateELCResult{
 			Messages: []*lcptypes.UpdateStateProxyMessage{},
+			WallTime: time.Since(start).String(),
 		}, nil
 	}
 	var msgs []*lcptypes.UpdateStateProxyMessage
 	for i, update := range updates {
-		commitment, err := lcptypes.EthABIDecodeHeaderedProxyMessage(update.Message)
+		msg, err := decodeUpdateStateProxyMessage(update.Message)
 		if err != nil {
-			return nil, fmt.Errorf("failed EthABIDecodeHeaderedProxyMessage: index=%v %w", i, err)
-		}
-		msg, err := commitment.GetUpdateStateProxyMessage()
-		if err != nil {
-			return nil, fmt.Errorf("failed GetUpdateStateProxyMessage: index=%v %w", i, err)
+			return nil, fmt.Errorf("index=%v %w", i, err)
 		}
 		pr.getLogger().Info("updated state", "prev_height", msg.PrevHeight, "prev_state_id", msg.PrevStateID.String(), "post_height", msg.PostHeight, "post_state_id", msg.PostStateID.String(), "timestamp", msg.Timestamp.String())
 		msgs = append(msgs, msg)
 	}
 	return &UpdateELCResult{
-		Messages: msgs,
+		Messages:       msgs,
+		HeadersApplied: len(msgs),
+		WallTime:       time.Since(start).String(),
 	}, nil
 }
 
-func containsOperator(operators []common.Address, operator common.Address) bool {
-	for _, op := range operators {
-		if op == operator {
-			return true
-		}
-	}
-	return false
-}
-
 type QueryELCResult struct {
 	// if false, `Raw` and `Decoded` are empty
 	Found bool `json:"found"`
@@ -617,6 +1034,11 @@ type QueryELCResult struct {
 		ClientState    ibcexported.ClientState    `json:"client_state"`
 		ConsensusState ibcexported.ConsensusState `json:"consensus_state"`
 	} `json:"decoded,omitempty"`
+	// the type URLs of the origin client state recorded by the prover and reported by the ELC
+	OriginClientType struct {
+		Recorded string `json:"recorded"`
+		Current  string `json:"current"`
+	} `json:"origin_client_type"`
 }
 
 type Any struct {
@@ -625,6 +1047,9 @@ type Any struct {
 }
 
 func (pr *Prover) doQueryELC(elcClientID string) (*QueryELCResult, error) {
+	if err := validateELCClientID(elcClientID); err != nil {
+		return nil, err
+	}
 	r, err := pr.lcpServiceClient.Client(context.TODO(), &elc.QueryClientRequest{ClientId: elcClientID})
 	if err != nil {
 		return nil, err
@@ -635,6 +1060,12 @@ func (pr *Prover) doQueryELC(elcClientID string) (*QueryELCResult, error) {
 	}
 	var result QueryELCResult
 	result.Found = true
+	types, err := pr.loadELCOriginClientTypes(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	result.OriginClientType.Recorded = types[elcClientID]
+	result.OriginClientType.Current = r.ClientState.TypeUrl
 	result.Raw.ClientState = Any{
 		TypeURL: r.ClientState.TypeUrl,
 		Value:   r.ClientState.Value,
@@ -672,7 +1103,7 @@ func (pr *Prover) createELC(elcClientID string, height ibcexported.Height) (*elc
 	if err != nil {
 		return nil, err
 	}
-	originClientState, originConsensusState, err := pr.originProver.CreateInitialLightClientState(height)
+	originClientState, originConsensusState, err := pr.initialLightClientStates.get(pr.originProver, height)
 	if err != nil {
 		return nil, err
 	}
@@ -684,64 +1115,157 @@ func (pr *Prover) createELC(elcClientID string, height ibcexported.Height) (*elc
 	if err != nil {
 		return nil, err
 	}
-	return pr.lcpServiceClient.CreateClient(context.TODO(), &elc.MsgCreateClient{
+	createRes, err := pr.lcpServiceClient.CreateClient(context.TODO(), &elc.MsgCreateClient{
 		ClientId:       elcClientID,
 		ClientState:    anyOriginClientState,
 		ConsensusState: anyOriginConsensusState,
 		Signer:         tmpEKI.EnclaveKeyAddress,
 	})
+	if err != nil {
+		return nil, err
+	}
+	pr.initialLightClientStates.invalidate()
+	if err := pr.saveELCOriginClientType(context.TODO(), elcClientID, anyOriginClientState.TypeUrl); err != nil {
+		return nil, err
+	}
+	return createRes, nil
 }
 
-func activateClient(pathEnd *core.PathEnd, src, dst *core.ProvableChain, retryInterval time.Duration, retryMaxAttempts uint) error {
+// activateClient makes the LCP client on `dst` synchronise with the latest header of the origin chain.
+// If `acknowledgeRegression` is true, the regression of the client's latest height is acknowledged before the check.
+// If a previous activation has been interrupted after some of its updates were applied, the pending updates are submitted instead.
+// The returned result is not nil unless the activation fails before querying the LCP client.
+func activateClient(pathEnd *core.PathEnd, src, dst *core.ProvableChain, retryInterval time.Duration, retryMaxAttempts uint, acknowledgeRegression bool) (*ActivateClientResult, error) {
 	srcProver := src.Prover.(*Prover)
-	if err := srcProver.UpdateEKIfNeeded(context.TODO(), dst); err != nil {
-		return err
+	if acknowledgeRegression {
+		if err := srcProver.acknowledgeCounterpartyClientHeightRegression(context.TODO(), dst); err != nil {
+			return nil, err
+		}
+	}
+	if err := srcProver.checkCounterpartyClientHeight(context.TODO(), dst); err != nil {
+		return nil, err
+	}
+	clientHeight, err := srcProver.queryCounterpartyClientHeight(context.TODO(), dst)
+	if err != nil {
+		return nil, err
+	}
+	result := &ActivateClientResult{
+		ELCClientID:   srcProver.GetELCClientID(),
+		ClientID:      pathEnd.ClientID,
+		InitialHeight: clientHeight,
+		LatestHeight:  clientHeight,
+	}
+	if resumed, err := srcProver.resumeActivateClient(context.TODO(), pathEnd, dst, result); err != nil || resumed {
+		return result, err
+	}
+	// the updates are generated from the latest height of the ELC client, which must be the one of the LCP client
+	if err := srcProver.checkActivateClientFollowable(context.TODO(), clientHeight); err != nil {
+		return result, err
 	}
 
-	srcProver.getLogger().Info("try to activate the LCP client", "elc_client_id", srcProver.config.ElcClientId)
+	var headers, updates []core.Header
+	bundled, err := srcProver.updateEKIfNeeded(context.TODO(), dst, func() ([]core.Header, error) {
+		var err error
+		headers, updates, err = srcProver.setupActivateClientUpdates(dst, retryInterval, retryMaxAttempts)
+		return updates, err
+	})
+	if err != nil {
+		return result, err
+	} else if bundled {
+		srcProver.getLogger().Info("the LCP client is activated with the registration of the enclave key", "elc_client_id", srcProver.GetELCClientID())
+		result.Bundled = true
+		if len(updates) > 0 {
+			lastHeight := updates[len(updates)-1].GetHeight()
+			result.Submitted, result.Applied = len(updates), len(updates)
+			result.LatestHeight = clienttypes.NewHeight(lastHeight.GetRevisionNumber(), lastHeight.GetRevisionHeight())
+		}
+		return result, nil
+	}
+
+	srcProver.getLogger().Info("try to activate the LCP client", "elc_client_id", srcProver.GetELCClientID())
+
+	// the updates may have been already set up for the bundling, and the active enclave key may have been rotated since then
+	if updates == nil {
+		if headers, updates, err = srcProver.setupActivateClientUpdates(dst, retryInterval, retryMaxAttempts); err != nil {
+			return result, err
+		}
+	} else if updates, err = srcProver.regenerateStaleUpdates(updates, func() ([]core.Header, error) {
+		return srcProver.regenerateActivateClientUpdates(headers)
+	}); err != nil {
+		return result, err
+	}
+
+	// 3. Submit the updates to the LCP Client, which are persisted so that an interrupted submission can be resumed
+	checkpoint := &activateClientCheckpoint{
+		ELCClientID: srcProver.GetELCClientID(),
+		ClientID:    pathEnd.ClientID,
+	}
+	for _, update := range updates {
+		checkpoint.Updates = append(checkpoint.Updates, update.(*lcptypes.UpdateClientMessage))
+	}
+	return result, srcProver.submitActivateClientUpdates(context.TODO(), pathEnd, dst, checkpoint, updates, result)
+}
 
+// setupActivateClientUpdates returns the update messages that make the LCP client synchronise with the latest header of the upstream chain
+// and the headers of the upstream chain which they are generated from
+func (pr *Prover) setupActivateClientUpdates(counterparty core.FinalityAwareChain, retryInterval time.Duration, retryMaxAttempts uint) ([]core.Header, []core.Header, error) {
 	// 1. LCP client synchronises with the latest header of the upstream chain
-	var updates []*elc.MsgUpdateClientResponse
+	// the updates predicted to be rejected by the counterparty are generated again from a fresher header
+	var (
+		headers   []core.Header
+		responses []*elc.MsgUpdateClientResponse
+	)
 	if err := retry.Do(func() error {
 		var err error
-		updates, err = srcProver.updateELC(srcProver.config.ElcClientId, true)
+		headers, responses, err = pr.updateELCWithHeaders(pr.GetELCClientID(), true, nil)
 		if err != nil {
 			return err
-		} else if len(updates) == 0 {
-			return fmt.Errorf("no available updates: elc_client_id=%v", srcProver.config.ElcClientId)
+		} else if len(responses) == 0 {
+			return fmt.Errorf("no available updates: elc_client_id=%v", pr.GetELCClientID())
 		}
-		return nil
+		return pr.checkActivateClientValidationContexts(context.TODO(), counterparty, responses)
 	}, retry.Attempts(retryMaxAttempts+1), retry.Delay(retryInterval)); err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	signer, err := dst.Chain.GetAddress()
+	// 2. Create update messages with the results of 1.
+	updates, err := pr.activateClientUpdates(responses)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
+	return headers, updates, nil
+}
 
-	// 2. Create a `MsgUpdateClient`s to apply to the LCP Client with the results of 1.
-	var msgs []sdk.Msg
-	for _, update := range updates {
+// regenerateActivateClientUpdates applies `headers` to the ELC client again and returns the update messages signed by the active enclave key
+func (pr *Prover) regenerateActivateClientUpdates(headers []core.Header) ([]core.Header, error) {
+	responses, err := pr.applyHeadersToELC(pr.GetELCClientID(), headers, true, nil, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return pr.activateClientUpdates(responses)
+}
+
+// activateClientUpdates creates the update messages from the responses of ELC's UpdateClient
+func (pr *Prover) activateClientUpdates(responses []*elc.MsgUpdateClientResponse) ([]core.Header, error) {
+	var updates []core.Header
+	for _, res := range responses {
+		msg, err := lcptypes.EthABIDecodeHeaderedProxyMessage(res.Message)
+		if err != nil {
+			return nil, err
+		}
+		if err := pr.checkMessageVersion(msg); err != nil {
+			return nil, err
+		}
 		message := &lcptypes.UpdateClientMessage{
-			ProxyMessage: update.Message,
-			Signatures:   [][]byte{update.Signature},
+			ProxyMessage: res.Message,
+			Signatures:   [][]byte{res.Signature},
 		}
 		if err := message.ValidateBasic(); err != nil {
-			return err
-		}
-		msg, err := clienttypes.NewMsgUpdateClient(pathEnd.ClientID, message, signer.String())
-		if err != nil {
-			return err
+			return nil, err
 		}
-		msgs = append(msgs, msg)
+		updates = append(updates, message)
 	}
-
-	// 3. Submit the msgs to the LCP Client
-	if _, err := dst.SendMsgs(msgs); err != nil {
-		return err
-	}
-	return nil
+	return updates, nil
 }
 
 type LCPQuerier struct {
@@ -0,0 +1,720 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	errorsmod "cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type mockHeader struct {
+	core.Header
+	height clienttypes.Height
+}
+
+func (h mockHeader) GetHeight() exported.Height {
+	return h.height
+}
+
+type mockMsgResult struct {
+	core.MsgResult
+	height  clienttypes.Height
+	success bool
+}
+
+func (r mockMsgResult) BlockHeight() clienttypes.Height {
+	return r.height
+}
+
+func (r mockMsgResult) Status() (bool, string) {
+	if r.success {
+		return true, ""
+	}
+	return false, "failed"
+}
+
+// mockCounterparty is a counterparty chain that counts RPC invocations
+type mockCounterparty struct {
+	core.FinalityAwareChain
+
+	chainID         string
+	latestHeight    clienttypes.Height
+	finalizedHeight clienttypes.Height
+	msgResults      map[string]core.MsgResult
+	// the LCP client state returned by QueryClientState
+	clientState *lcptypes.ClientState
+	// the LCP consensus state returned by QueryClientConsensusState at any height
+	consensusState *lcptypes.ConsensusState
+	// the block times returned by Timestamp, or blockTime if not found
+	blockTimes       map[uint64]time.Time
+	blockTime        time.Time
+	averageBlockTime time.Duration
+
+	// if not nil, SendMsgs returns the error for the msgs
+	sendMsgsErr func(msgs []sdk.Msg) error
+	sentMsgs    [][]sdk.Msg
+
+	getMsgResultCalls             int
+	getLatestFinalizedHeaderCalls int
+	sendMsgsCalls                 int
+}
+
+func newMockCounterparty(finalizedHeight clienttypes.Height) *mockCounterparty {
+	return &mockCounterparty{
+		chainID:         "counterparty",
+		finalizedHeight: finalizedHeight,
+		msgResults:      make(map[string]core.MsgResult),
+	}
+}
+
+func (c *mockCounterparty) ChainID() string {
+	return c.chainID
+}
+
+func (c *mockCounterparty) LatestHeight() (exported.Height, error) {
+	return c.latestHeight, nil
+}
+
+func (c *mockCounterparty) Timestamp(height exported.Height) (time.Time, error) {
+	if t, ok := c.blockTimes[height.GetRevisionHeight()]; ok {
+		return t, nil
+	}
+	return c.blockTime, nil
+}
+
+func (c *mockCounterparty) AverageBlockTime() time.Duration {
+	return c.averageBlockTime
+}
+
+func (c *mockCounterparty) QueryClientState(core.QueryContext) (*clienttypes.QueryClientStateResponse, error) {
+	if c.clientState == nil {
+		return nil, fmt.Errorf("client state not found")
+	}
+	anyClientState, err := clienttypes.PackClientState(c.clientState)
+	if err != nil {
+		return nil, err
+	}
+	return &clienttypes.QueryClientStateResponse{ClientState: anyClientState}, nil
+}
+
+func (c *mockCounterparty) QueryClientConsensusState(ctx core.QueryContext, height exported.Height) (*clienttypes.QueryConsensusStateResponse, error) {
+	if c.consensusState != nil {
+		anyConsensusState, err := clienttypes.PackConsensusState(c.consensusState)
+		if err != nil {
+			return nil, err
+		}
+		return &clienttypes.QueryConsensusStateResponse{ConsensusState: anyConsensusState}, nil
+	}
+	return nil, errorsmod.Wrapf(clienttypes.ErrConsensusStateNotFound, "height=%v", height)
+}
+
+func (c *mockCounterparty) GetMsgResult(id core.MsgID) (core.MsgResult, error) {
+	c.getMsgResultCalls++
+	res, ok := c.msgResults[id.String()]
+	if !ok {
+		return nil, fmt.Errorf("msg not found: %v", id)
+	}
+	return res, nil
+}
+
+func (c *mockCounterparty) Path() *core.PathEnd {
+	return &core.PathEnd{ChainID: c.chainID, ClientID: "lcp-client-0"}
+}
+
+func (c *mockCounterparty) GetAddress() (sdk.AccAddress, error) {
+	return sdk.AccAddress(make([]byte, 20)), nil
+}
+
+func (c *mockCounterparty) SendMsgs(msgs []sdk.Msg) ([]core.MsgID, error) {
+	c.sendMsgsCalls++
+	if c.sendMsgsErr != nil {
+		if err := c.sendMsgsErr(msgs); err != nil {
+			return nil, err
+		}
+	}
+	c.sentMsgs = append(c.sentMsgs, msgs)
+	var ids []core.MsgID
+	for i := range msgs {
+		ids = append(ids, &tendermint.MsgID{TxHash: "0x01", MsgIndex: uint32(i)})
+	}
+	return ids, nil
+}
+
+func (c *mockCounterparty) GetLatestFinalizedHeader() (core.Header, error) {
+	c.getLatestFinalizedHeaderCalls++
+	return mockHeader{height: c.finalizedHeight}, nil
+}
+
+type mockEnclaveQueryClient struct {
+	enclave.QueryClient
+	availableKeys []*enclave.EnclaveKeyInfo
+}
+
+// AvailableEnclaveKeys returns the keys for the given MRENCLAVE. If it is empty, all keys are returned.
+func (c mockEnclaveQueryClient) AvailableEnclaveKeys(ctx context.Context, in *enclave.QueryAvailableEnclaveKeysRequest, opts ...grpc.CallOption) (*enclave.QueryAvailableEnclaveKeysResponse, error) {
+	if len(in.Mrenclave) == 0 {
+		return &enclave.QueryAvailableEnclaveKeysResponse{Keys: c.availableKeys}, nil
+	}
+	var keys []*enclave.EnclaveKeyInfo
+	for _, eki := range c.availableKeys {
+		mrenclave, err := getMrenclaveFromReport(eki.Report)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(mrenclave, in.Mrenclave) {
+			keys = append(keys, eki)
+		}
+	}
+	return &enclave.QueryAvailableEnclaveKeysResponse{Keys: keys}, nil
+}
+
+func (mockEnclaveQueryClient) EnclaveKey(ctx context.Context, in *enclave.QueryEnclaveKeyRequest, opts ...grpc.CallOption) (*enclave.QueryEnclaveKeyResponse, error) {
+	return &enclave.QueryEnclaveKeyResponse{Key: &enclave.EnclaveKeyInfo{EnclaveKeyAddress: in.EnclaveKeyAddress}}, nil
+}
+
+func newTestProver(t *testing.T) *Prover {
+	require.NoError(t, log.InitLogger("DEBUG", "text", "stdout"))
+	return &Prover{
+		config:                           ProverConfig{KeyExpiration: 3600},
+		lcpServiceClient:                 LCPServiceClient{EnclaveQueryClient: mockEnclaveQueryClient{}},
+		counterpartyFinalizedHeaderCache: newFinalizedHeaderCache(DefaultFinalizedHeaderCacheTTL),
+	}
+}
+
+func TestLoadEKIAndCheckUpdateNeededRPCCalls(t *testing.T) {
+	msgID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	var cases = []struct {
+		name            string
+		msgHeight       clienttypes.Height
+		finalizedHeight clienttypes.Height
+		updateNeeded    bool
+		finalized       bool
+	}{
+		{"unfinalized", clienttypes.NewHeight(0, 10), clienttypes.NewHeight(0, 9), false, false},
+		{"finalized", clienttypes.NewHeight(0, 10), clienttypes.NewHeight(0, 10), false, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			pr.codec = newTestCodec()
+			pr.homePath = t.TempDir()
+			pr.originChain = &mockCounterparty{chainID: "origin"}
+			require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+			pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}, AttestationTime: uint64(time.Now().Unix())}
+			pr.unfinalizedMsgID = msgID
+
+			cp := newMockCounterparty(c.finalizedHeight)
+			cp.msgResults[msgID.String()] = mockMsgResult{height: c.msgHeight, success: true}
+
+			updateNeeded, err := pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+			require.NoError(err)
+			require.Equal(c.updateNeeded, updateNeeded)
+			require.Equal(1, cp.getMsgResultCalls)
+			require.Equal(1, cp.getLatestFinalizedHeaderCalls)
+			require.Equal(c.finalized, pr.unfinalizedMsgID == nil)
+
+			// the finalized header is shared within the cache TTL
+			_, _, _, err = pr.checkMsgStatus(cp, msgID)
+			require.NoError(err)
+			require.Equal(2, cp.getMsgResultCalls)
+			require.Equal(1, cp.getLatestFinalizedHeaderCalls)
+
+			pr.counterpartyFinalizedHeaderCache.invalidate()
+			_, _, _, err = pr.checkMsgStatus(cp, msgID)
+			require.NoError(err)
+			require.Equal(2, cp.getLatestFinalizedHeaderCalls)
+		})
+	}
+}
+
+func TestFinalizedHeaderCacheExpiration(t *testing.T) {
+	require := require.New(t)
+	now := time.Now()
+	cache := newFinalizedHeaderCache(time.Second)
+	cache.now = func() time.Time { return now }
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 1))
+
+	_, err := cache.get(cp)
+	require.NoError(err)
+	_, err = cache.get(cp)
+	require.NoError(err)
+	require.Equal(1, cp.getLatestFinalizedHeaderCalls)
+
+	now = now.Add(time.Second)
+	h, err := cache.get(cp)
+	require.NoError(err)
+	require.Equal(2, cp.getLatestFinalizedHeaderCalls)
+	require.Equal(clienttypes.NewHeight(0, 1), h.GetHeight())
+}
+
+// loadTestEnclaveKeyInfo loads the enclave key info from the fixture generated by a debug-mode enclave
+func loadTestEnclaveKeyInfo(t *testing.T) *enclave.EnclaveKeyInfo {
+	bz, err := os.ReadFile("../testdata/002-avr")
+	require.NoError(t, err)
+	var eavr struct {
+		AVR         string `json:"avr"`
+		Signature   []byte `json:"signature"`
+		SigningCert []byte `json:"signing_cert"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &eavr))
+	return &enclave.EnclaveKeyInfo{
+		EnclaveKeyAddress: common.HexToAddress("0xC9f79d5de52dbe84120055FF286642C5c328466e").Bytes(),
+		AttestationTime:   uint64(time.Now().Unix()),
+		Report:            eavr.AVR,
+		Signature:         eavr.Signature,
+		SigningCert:       eavr.SigningCert,
+	}
+}
+
+func TestSelectNewEnclaveKeyDebugEnclave(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	eki := loadTestEnclaveKeyInfo(t)
+
+	var cases = []struct {
+		name  string
+		allow bool
+	}{
+		{"disallowed", false},
+		{"allowed", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			pr.config.AllowedQuoteStatuses = []string{"GROUP_OUT_OF_DATE"}
+			pr.config.AllowedAdvisoryIds = []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-00334", "INTEL-SA-00477", "INTEL-SA-00614", "INTEL-SA-00615", "INTEL-SA-00617", "INTEL-SA-00828"}
+			pr.config.AllowDebugEnclaveKeys = c.allow
+			pr.config.Mrenclave = testMrenclave(t, eki)
+			pr.lcpServiceClient.EnclaveQueryClient = mockEnclaveQueryClient{availableKeys: []*enclave.EnclaveKeyInfo{eki}}
+
+			selected, err := pr.selectNewEnclaveKey(context.TODO())
+			if c.allow {
+				require.NoError(err)
+				require.Equal(eki.EnclaveKeyAddress, selected.EnclaveKeyAddress)
+			} else {
+				require.ErrorContains(err, "all keys are not allowed to use")
+			}
+		})
+	}
+}
+
+func TestSendRegisterEnclaveKeyMsgWithFirstUpdates(t *testing.T) {
+	var cases = []struct {
+		name        string
+		sendMsgsErr func(msgs []sdk.Msg) error
+		bundled     bool
+	}{
+		{"bundled", nil, true},
+		{"fallback", func(msgs []sdk.Msg) error {
+			if len(msgs) > 1 {
+				return fmt.Errorf("failed to execute message; message index: 1: enclave key '0x0100000000000000000000000000000000000000' not found: invalid header")
+			}
+			return nil
+		}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+			cp.sendMsgsErr = c.sendMsgsErr
+
+			eki := &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}}
+			registerMsg, err := clienttypes.NewMsgUpdateClient("lcp-client-0", &lcptypes.RegisterEnclaveKeyMessage{Report: []byte("report")}, "signer")
+			require.NoError(err)
+			update := &lcptypes.UpdateClientMessage{ProxyMessage: []byte{0x01}, Signatures: [][]byte{{0x02}}}
+
+			ids, bundled, err := pr.sendRegisterEnclaveKeyMsgWithFirstUpdates(cp, eki, registerMsg, func() ([]core.Header, error) {
+				// the first updates are signed by the new key
+				require.Equal(eki, pr.activeEnclaveKey)
+				return []core.Header{update}, nil
+			})
+			require.NoError(err)
+			require.Equal(c.bundled, bundled)
+			require.Len(cp.sentMsgs, 1)
+			if !c.bundled {
+				require.Len(ids, 1)
+				require.Equal(2, cp.sendMsgsCalls)
+				require.Equal([]sdk.Msg{registerMsg}, cp.sentMsgs[0])
+				return
+			}
+			require.Len(ids, 2)
+			require.Len(cp.sentMsgs[0], 2)
+			require.Equal(registerMsg, cp.sentMsgs[0][0])
+
+			for _, id := range ids {
+				cp.msgResults[id.String()] = mockMsgResult{height: clienttypes.NewHeight(0, 10), success: true}
+			}
+			finalized, _, updatesSucceeded, err := pr.checkMsgsStatus(cp, ids)
+			require.NoError(err)
+			require.True(finalized)
+			require.True(updatesSucceeded)
+
+			// the failed update does not affect the key if the counterparty chain applied the registration
+			cp.msgResults[ids[1].String()] = mockMsgResult{height: clienttypes.NewHeight(0, 10), success: false}
+			finalized, _, updatesSucceeded, err = pr.checkMsgsStatus(cp, ids)
+			require.NoError(err)
+			require.True(finalized)
+			require.False(updatesSucceeded)
+
+			// the key is never finalized if the registration failed
+			cp.msgResults[ids[0].String()] = mockMsgResult{height: clienttypes.NewHeight(0, 10), success: false}
+			finalized, _, _, err = pr.checkMsgsStatus(cp, ids)
+			require.ErrorContains(err, "execution failed")
+			require.False(finalized)
+		})
+	}
+}
+
+func TestSaveRegisteredEnclaveKeyWithFailedUpdates(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.homePath = t.TempDir()
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+
+	// the registration and the update are in the same tx, and only the update failed
+	registrationID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	updateID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 1}
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 9))
+	cp.msgResults[registrationID.String()] = mockMsgResult{height: clienttypes.NewHeight(0, 10), success: true}
+	cp.msgResults[updateID.String()] = mockMsgResult{height: clienttypes.NewHeight(0, 10), success: false}
+
+	eki := &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}, AttestationTime: uint64(time.Now().Unix())}
+	updatesSucceeded, err := pr.saveRegisteredEnclaveKey(context.TODO(), cp, eki, []core.MsgID{registrationID, updateID})
+	require.NoError(err)
+	require.False(updatesSucceeded)
+	require.Equal(eki, pr.activeEnclaveKey)
+	// the registration is tracked by its own msg ID until it is finalized
+	require.Equal(registrationID.String(), pr.unfinalizedMsgID.String())
+	_, savedMsgID, _, err := pr.loadLastUnfinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal(registrationID.String(), savedMsgID.String())
+}
+
+func testMrenclave(t *testing.T, eki *enclave.EnclaveKeyInfo) string {
+	mrenclave, err := getMrenclaveFromReport(eki.Report)
+	require.NoError(t, err)
+	return hex.EncodeToString(mrenclave)
+}
+
+func TestSelectNewEnclaveKeyMrenclaveMismatch(t *testing.T) {
+	require := require.New(t)
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	eki := loadTestEnclaveKeyInfo(t)
+
+	pr := newTestProver(t)
+	pr.config.Mrenclave = hex.EncodeToString(bytes.Repeat([]byte{0x01}, lcptypes.MrenclaveSize))
+	pr.lcpServiceClient.EnclaveQueryClient = mockEnclaveQueryClient{availableKeys: []*enclave.EnclaveKeyInfo{eki}}
+	_, err := pr.selectNewEnclaveKey(context.TODO())
+	require.ErrorIs(err, ErrMrenclaveMismatch)
+	require.ErrorContains(err, "0x"+testMrenclave(t, eki))
+
+	// the service has no keys at all
+	pr.lcpServiceClient.EnclaveQueryClient = mockEnclaveQueryClient{}
+	_, err = pr.selectNewEnclaveKey(context.TODO())
+	require.Error(err)
+	require.NotErrorIs(err, ErrMrenclaveMismatch)
+}
+
+func TestMinimumISVSVN(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	// the ISV SVN of the fixture is zero
+	eki := loadTestEnclaveKeyInfo(t)
+
+	var cases = []struct {
+		name    string
+		minimum uint32
+		ok      bool
+	}{
+		{"no constraint", 0, true},
+		{"outdated enclave", 1, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			pr.originChain = &mockCounterparty{chainID: "origin"}
+			pr.config.AllowedQuoteStatuses = []string{"GROUP_OUT_OF_DATE"}
+			pr.config.AllowedAdvisoryIds = []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-00334", "INTEL-SA-00477", "INTEL-SA-00614", "INTEL-SA-00615", "INTEL-SA-00617", "INTEL-SA-00828"}
+			pr.config.AllowDebugEnclaveKeys = true
+			pr.config.Mrenclave = testMrenclave(t, eki)
+			pr.config.MinimumIsvSvn = c.minimum
+			pr.lcpServiceClient.EnclaveQueryClient = mockEnclaveQueryClient{availableKeys: []*enclave.EnclaveKeyInfo{eki}}
+
+			selected, err := pr.selectNewEnclaveKey(context.TODO())
+			if c.ok {
+				require.NoError(err)
+				require.Equal(eki.EnclaveKeyAddress, selected.EnclaveKeyAddress)
+			} else {
+				require.ErrorContains(err, "all keys are not allowed to use")
+			}
+
+			// the registration is also rejected before querying the counterparty chain
+			_, err = pr.buildRegisterEnclaveKeyMsg(newMockCounterparty(clienttypes.NewHeight(0, 1)), eki)
+			if c.ok {
+				// the mock counterparty has no client state
+				require.ErrorContains(err, "client state not found")
+			} else {
+				require.ErrorContains(err, "the ISV SVN is lower than the minimum")
+			}
+		})
+	}
+}
+
+func TestQuotePolicyOverride(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	// the fixture's quote status is GROUP_OUT_OF_DATE
+	eki := loadTestEnclaveKeyInfo(t)
+	advisoryIDs := []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-00334", "INTEL-SA-00477", "INTEL-SA-00614", "INTEL-SA-00615", "INTEL-SA-00617", "INTEL-SA-00828"}
+
+	var cases = []struct {
+		name     string
+		chainID  string
+		clientID string
+		// if true, the fixture key is allowed for the path
+		allowed bool
+	}{
+		// the global policy only allows "OK"
+		{"fallback to the global policy", "ibc-2", "lcp-client-0", false},
+		{"permissive chain", "ibc-0", "lcp-client-0", true},
+		// the override for the client takes precedence over the one for the chain
+		{"strict client on the permissive chain", "ibc-0", "lcp-client-1", false},
+		{"strict chain", "ibc-1", "lcp-client-0", false},
+		{"permissive client on the strict chain", "ibc-1", "lcp-client-1", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			pr.originChain = &mockCounterparty{chainID: "origin"}
+			pr.config.AllowDebugEnclaveKeys = true
+			pr.config.Mrenclave = testMrenclave(t, eki)
+			pr.config.QuotePolicyOverrides = []QuotePolicyOverride{
+				{CounterpartyChainId: "ibc-0", AllowedQuoteStatuses: []string{"GROUP_OUT_OF_DATE"}, AllowedAdvisoryIds: advisoryIDs},
+				{CounterpartyChainId: "ibc-0", CounterpartyClientId: "lcp-client-1", AllowedQuoteStatuses: []string{"GROUP_OUT_OF_DATE"}},
+				{CounterpartyChainId: "ibc-1", AllowedAdvisoryIds: advisoryIDs},
+				{CounterpartyChainId: "ibc-1", CounterpartyClientId: "lcp-client-1", AllowedQuoteStatuses: []string{"GROUP_OUT_OF_DATE"}, AllowedAdvisoryIds: advisoryIDs},
+			}
+			require.NoError(pr.config.validateQuotePolicies())
+			pr.lcpServiceClient.EnclaveQueryClient = mockEnclaveQueryClient{availableKeys: []*enclave.EnclaveKeyInfo{eki}}
+
+			counterparty := newMockCounterparty(clienttypes.NewHeight(0, 1))
+			counterparty.chainID = c.chainID
+			require.NoError(pr.SetRelayInfo(&core.PathEnd{ChainID: "origin"}, &core.ProvableChain{Chain: counterparty}, &core.PathEnd{ChainID: c.chainID, ClientID: c.clientID}))
+
+			selected, err := pr.selectNewEnclaveKey(context.TODO())
+			if c.allowed {
+				require.NoError(err)
+				require.Equal(eki.EnclaveKeyAddress, selected.EnclaveKeyAddress)
+			} else {
+				require.ErrorContains(err, "all keys are not allowed to use")
+			}
+
+			// the registration is rejected before querying the counterparty chain
+			_, err = pr.buildRegisterEnclaveKeyMsg(counterparty, eki)
+			if c.allowed {
+				// the mock counterparty has no client state
+				require.ErrorContains(err, "client state not found")
+			} else {
+				require.ErrorContains(err, "not allowed by the counterparty")
+			}
+
+			// the client state created for the path carries the resolved policy
+			pr.config.ElcClientId = "07-tendermint-0"
+			pr.lcpServiceClient.ELCQueryClient = mockELCQueryClient{clients: map[string]*elc.QueryClientResponse{pr.config.ElcClientId: {Found: true}}}
+			cs, _, err := pr.CreateInitialLightClientState(nil)
+			require.NoError(err)
+			require.Equal(pr.allowedQuoteStatuses(), cs.(*lcptypes.ClientState).AllowedQuoteStatuses)
+			require.Equal(pr.allowedAdvisoryIDs(), cs.(*lcptypes.ClientState).AllowedAdvisoryIds)
+		})
+	}
+}
+
+func TestLoadEKIAndCheckUpdateNeededOutstandingRegistrations(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	// the relayer crashed after submitting the registration A and resubmitted the same key by B after the restart
+	msgA := &tendermint.MsgID{TxHash: "0xaa", MsgIndex: 0}
+	msgB := &tendermint.MsgID{TxHash: "0xbb", MsgIndex: 0}
+	finalizedHeight := clienttypes.NewHeight(0, 10)
+	finalizedResult := &mockMsgResult{height: clienttypes.NewHeight(0, 10), success: true}
+	unfinalizedResult := &mockMsgResult{height: clienttypes.NewHeight(0, 11), success: true}
+	failedResult := &mockMsgResult{height: clienttypes.NewHeight(0, 11), success: false}
+
+	var cases = []struct {
+		name string
+		// the current results of the registrations. If nil, the msg is not found.
+		resultA, resultB *mockMsgResult
+
+		updateNeeded bool
+		finalized    bool
+		// the msg ID of the active registration if not finalized
+		activeMsgID core.MsgID
+		// the msg IDs of the remaining records
+		remaining []core.MsgID
+	}{
+		{"first finalized", finalizedResult, unfinalizedResult, false, true, nil, nil},
+		{"both finalized", finalizedResult, finalizedResult, false, true, nil, nil},
+		{"resubmission finalized", nil, finalizedResult, false, true, nil, nil},
+		{"both unfinalized", unfinalizedResult, unfinalizedResult, false, false, msgB, []core.MsgID{msgA, msgB}},
+		{"first failed", failedResult, unfinalizedResult, false, false, msgB, []core.MsgID{msgB}},
+		{"resubmission failed", unfinalizedResult, failedResult, false, false, msgA, []core.MsgID{msgA}},
+		{"both failed", failedResult, failedResult, true, false, nil, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			eki := loadTestEnclaveKeyInfo(t)
+			pr := newTestProver(t)
+			pr.codec = newTestCodec()
+			pr.homePath = t.TempDir()
+			pr.originChain = &mockCounterparty{chainID: "origin"}
+			pr.config.AllowedQuoteStatuses = []string{"GROUP_OUT_OF_DATE"}
+			pr.config.AllowedAdvisoryIds = []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-00334", "INTEL-SA-00477", "INTEL-SA-00614", "INTEL-SA-00615", "INTEL-SA-00617", "INTEL-SA-00828"}
+			pr.config.AllowDebugEnclaveKeys = true
+			pr.config.Mrenclave = testMrenclave(t, eki)
+			require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+			require.NoError(pr.saveUnfinalizedEnclaveKeyInfo(context.TODO(), eki, msgA, clienttypes.Height{}))
+			require.NoError(pr.saveUnfinalizedEnclaveKeyInfo(context.TODO(), eki, msgB, clienttypes.Height{}))
+
+			cp := newMockCounterparty(finalizedHeight)
+			if c.resultA != nil {
+				cp.msgResults[msgA.String()] = *c.resultA
+			}
+			if c.resultB != nil {
+				cp.msgResults[msgB.String()] = *c.resultB
+			}
+
+			updateNeeded, err := pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+			require.NoError(err)
+			require.Equal(c.updateNeeded, updateNeeded)
+			require.Empty(cp.sentMsgs)
+
+			if c.finalized {
+				require.Equal(eki.EnclaveKeyAddress, pr.activeEnclaveKey.EnclaveKeyAddress)
+				require.Nil(pr.unfinalizedMsgID)
+				finalized, err := pr.loadLastFinalizedEnclaveKey(context.TODO())
+				require.NoError(err)
+				require.Equal(eki.EnclaveKeyAddress, finalized.EnclaveKeyAddress)
+			} else if c.activeMsgID != nil {
+				require.Equal(c.activeMsgID.String(), pr.unfinalizedMsgID.String())
+				_, err := pr.loadLastFinalizedEnclaveKey(context.TODO())
+				require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+			}
+
+			records, err := pr.loadUnfinalizedEnclaveKeys(context.TODO())
+			require.NoError(err)
+			require.Len(records, len(c.remaining))
+			for i, msgID := range c.remaining {
+				require.Equal(msgID.String(), records[i].msgID.String())
+			}
+		})
+	}
+}
+
+// countingOriginProver counts the invocations of CreateInitialLightClientState by the requested revision height
+type countingOriginProver struct {
+	mockSelfTestOriginProver
+	calls map[uint64]int
+}
+
+func (p *countingOriginProver) CreateInitialLightClientState(height exported.Height) (exported.ClientState, exported.ConsensusState, error) {
+	p.calls[height.GetRevisionHeight()]++
+	return p.mockSelfTestOriginProver.CreateInitialLightClientState(height)
+}
+
+// flakyCreateClientService fails the first `failures` calls of CreateClient
+type flakyCreateClientService struct {
+	*mockLCPService
+	failures int
+}
+
+func (s *flakyCreateClientService) CreateClient(ctx context.Context, in *elc.MsgCreateClient, opts ...grpc.CallOption) (*elc.MsgCreateClientResponse, error) {
+	if s.failures > 0 {
+		s.failures--
+		return nil, fmt.Errorf("unavailable")
+	}
+	return s.mockLCPService.CreateClient(ctx, in, opts...)
+}
+
+func TestCreateELCReusesInitialLightClientStates(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	require := require.New(t)
+	eki := loadTestEnclaveKeyInfo(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	service := &flakyCreateClientService{mockLCPService: &mockLCPService{t: t, key: key, clients: make(map[string]*lcptypes.ClientState)}}
+	origin := &countingOriginProver{calls: make(map[uint64]int)}
+	pr := newTestProver(t)
+	pr.homePath = t.TempDir()
+	pr.originProver = origin
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	pr.config.AllowedQuoteStatuses = []string{"GROUP_OUT_OF_DATE"}
+	pr.config.AllowedAdvisoryIds = []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-00334", "INTEL-SA-00477", "INTEL-SA-00614", "INTEL-SA-00615", "INTEL-SA-00617", "INTEL-SA-00828"}
+	pr.config.AllowDebugEnclaveKeys = true
+	pr.config.Mrenclave = testMrenclave(t, eki)
+	pr.lcpServiceClient = LCPServiceClient{
+		ELCMsgClient:       service,
+		ELCQueryClient:     service,
+		EnclaveQueryClient: mockEnclaveQueryClient{availableKeys: []*enclave.EnclaveKeyInfo{eki}},
+	}
+	require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+	h10, h11 := clienttypes.NewHeight(0, 10), clienttypes.NewHeight(0, 11)
+
+	// the retries after the transient failures reuse the states
+	service.failures = 2
+	for i := 0; i < 2; i++ {
+		_, err := pr.createELC("client-0", h10)
+		require.ErrorContains(err, "unavailable")
+	}
+	res, err := pr.createELC("client-0", h10)
+	require.NoError(err)
+	require.NotNil(res)
+	require.Equal(1, origin.calls[10])
+	require.Equal(h10, service.clients["client-0"].LatestHeight)
+
+	// the states are invalidated after the creation
+	_, err = pr.createELC("client-1", h10)
+	require.NoError(err)
+	require.Equal(2, origin.calls[10])
+
+	// the states at another height are created again
+	service.failures = 1
+	_, err = pr.createELC("client-2", h10)
+	require.ErrorContains(err, "unavailable")
+	require.Equal(3, origin.calls[10])
+	_, err = pr.createELC("client-2", h11)
+	require.NoError(err)
+	require.Equal(1, origin.calls[11])
+	require.Equal(h11, service.clients["client-2"].LatestHeight)
+}
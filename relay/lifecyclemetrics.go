@@ -0,0 +1,103 @@
+package relay
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// a new enclave key has been registered in the counterparty LCP client
+	keyRotationRegistered = "registered"
+	// the key registered by another instance for the same LCP client has been adopted
+	keyRotationAdopted = "adopted"
+	// no key could be selected or the registration could not be submitted
+	keyRotationFailed = "failed"
+)
+
+// lifecycleMetricsState is the state of the metrics of the enclave key lifecycle and the latencies of the ELC.
+// The instruments are created with the meter of the metrics sink, which returns the same instrument for the same name,
+// so the metrics are registered once even if multiple provers record them. The provers are distinguished by the attributes.
+// The counter and the gauge have no unit so that they are exported to Prometheus without the "_ratio" suffix,
+// and the names of the histograms end with "_seconds" because the exporter does not append the suffix of their unit.
+type lifecycleMetricsState struct {
+	// the number of the enclave keys returned by the last query of the available keys
+	availableEnclaveKeys atomic.Int64
+	// true if the available keys have been queried at least once
+	availableEnclaveKeysObserved atomic.Bool
+	// registers the gauge of the available keys once
+	registerOnce sync.Once
+}
+
+// lifecycleMetricAttributes returns the attributes identifying the prover in the metrics
+func (pr *Prover) lifecycleMetricAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("elc_client_id", pr.GetELCClientID()),
+		attribute.String("chain_id", pr.counterpartyChainID()),
+	}
+}
+
+// countKeyRotation increments the counter of the enclave key rotations with `result`
+func (pr *Prover) countKeyRotation(ctx context.Context, result string) {
+	counter, err := pr.meter().Int64Counter(
+		"lcp.enclave_key_rotations",
+		metric.WithDescription("number of the rotations of the enclave key registered in the counterparty LCP client"),
+	)
+	if err != nil {
+		pr.getLogger().Warn("failed to create the counter of the enclave key rotations", "error", err)
+		return
+	}
+	counter.Add(ctx, 1, metric.WithAttributes(append(pr.lifecycleMetricAttributes(), attribute.String("result", result))...))
+}
+
+// recordELCUpdateDuration records the time elapsed since `start` to apply the headers to the ELC client
+func (pr *Prover) recordELCUpdateDuration(ctx context.Context, start time.Time, err error) {
+	pr.recordDuration(ctx, "lcp.elc_update_duration_seconds", "time taken to apply the headers of the origin chain to the ELC client", start, err)
+}
+
+// recordVerifyMembershipDuration records the time elapsed since `start` to generate a commitment proof with the ELC client
+func (pr *Prover) recordVerifyMembershipDuration(ctx context.Context, start time.Time, err error) {
+	pr.recordDuration(ctx, "lcp.verify_membership_duration_seconds", "time taken to generate a commitment proof verified by the ELC client", start, err)
+}
+
+func (pr *Prover) recordDuration(ctx context.Context, name, description string, start time.Time, err error) {
+	histogram, herr := pr.meter().Float64Histogram(name, metric.WithUnit("s"), metric.WithDescription(description))
+	if herr != nil {
+		pr.getLogger().Warn("failed to create the histogram", "name", name, "error", herr)
+		return
+	}
+	histogram.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(append(pr.lifecycleMetricAttributes(), attribute.Bool("success", err == nil))...))
+}
+
+// observeAvailableEnclaveKeys records the number of the enclave keys available in the LCP service
+func (pr *Prover) observeAvailableEnclaveKeys(n int) {
+	pr.lifecycleMetrics.registerOnce.Do(pr.registerAvailableEnclaveKeysGauge)
+	pr.lifecycleMetrics.availableEnclaveKeys.Store(int64(n))
+	pr.lifecycleMetrics.availableEnclaveKeysObserved.Store(true)
+}
+
+// registerAvailableEnclaveKeysGauge exports the number of the available enclave keys as a gauge with the metrics sink of the prover
+func (pr *Prover) registerAvailableEnclaveKeysGauge() {
+	meter := pr.meter()
+	gauge, err := meter.Int64ObservableGauge(
+		"lcp.available_enclave_keys",
+		metric.WithDescription("number of the enclave keys for the configured MRENCLAVE available in the LCP service at the last query"),
+	)
+	if err != nil {
+		pr.getLogger().Warn("failed to create the gauge of the available enclave keys", "error", err)
+		return
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		if pr.lifecycleMetrics.availableEnclaveKeysObserved.Load() {
+			o.ObserveInt64(gauge, pr.lifecycleMetrics.availableEnclaveKeys.Load(), metric.WithAttributes(pr.lifecycleMetricAttributes()...))
+		}
+		return nil
+	}, gauge)
+	if err != nil {
+		pr.getLogger().Warn("failed to register the callback of the available enclave keys", "error", err)
+	}
+}
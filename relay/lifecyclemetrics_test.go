@@ -0,0 +1,75 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleMetrics(t *testing.T) {
+	require := require.New(t)
+	sink, err := newMetricsSink(ProverConfig{MetricsSink: MetricsSinkPrometheus, MetricsAddress: "127.0.0.1:0"}, "origin")
+	require.NoError(err)
+
+	// the provers share the sink as the ones in a relayer process share the global meter provider
+	var provers []*Prover
+	for _, elcClientID := range []string{"07-tendermint-0", "07-tendermint-1"} {
+		pr := newTestProver(t)
+		pr.config.ElcClientId = elcClientID
+		pr.metrics = sink
+		provers = append(provers, pr)
+	}
+	ctx := context.TODO()
+	for i, pr := range provers {
+		pr.countKeyRotation(ctx, keyRotationRegistered)
+		pr.countKeyRotation(ctx, keyRotationFailed)
+		pr.recordELCUpdateDuration(ctx, time.Now().Add(-time.Second), nil)
+		pr.recordVerifyMembershipDuration(ctx, time.Now(), errors.New("failed"))
+		pr.observeAvailableEnclaveKeys(i + 1)
+		// the gauge is registered once per prover
+		pr.observeAvailableEnclaveKeys(i + 2)
+	}
+
+	families, err := sink.(*prometheusMetricsSink).registry.Gather()
+	require.NoError(err)
+	series := make(map[string]map[string]float64)
+	for _, f := range families {
+		values := make(map[string]float64)
+		for _, m := range f.GetMetric() {
+			var key string
+			for _, l := range m.GetLabel() {
+				key += l.GetName() + "=" + l.GetValue() + ","
+			}
+			switch {
+			case m.GetCounter() != nil:
+				values[key] = m.GetCounter().GetValue()
+			case m.GetGauge() != nil:
+				values[key] = m.GetGauge().GetValue()
+			case m.GetHistogram() != nil:
+				values[key] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+		series[f.GetName()] = values
+	}
+	require.Equal(map[string]float64{
+		"chain_id=,elc_client_id=07-tendermint-0,result=failed,":     1,
+		"chain_id=,elc_client_id=07-tendermint-0,result=registered,": 1,
+		"chain_id=,elc_client_id=07-tendermint-1,result=failed,":     1,
+		"chain_id=,elc_client_id=07-tendermint-1,result=registered,": 1,
+	}, series["lcp_enclave_key_rotations_total"])
+	require.Equal(map[string]float64{
+		"chain_id=,elc_client_id=07-tendermint-0,success=true,": 1,
+		"chain_id=,elc_client_id=07-tendermint-1,success=true,": 1,
+	}, series["lcp_elc_update_duration_seconds"])
+	require.Equal(map[string]float64{
+		"chain_id=,elc_client_id=07-tendermint-0,success=false,": 1,
+		"chain_id=,elc_client_id=07-tendermint-1,success=false,": 1,
+	}, series["lcp_verify_membership_duration_seconds"])
+	require.Equal(map[string]float64{
+		"chain_id=,elc_client_id=07-tendermint-0,": 2,
+		"chain_id=,elc_client_id=07-tendermint-1,": 3,
+	}, series["lcp_available_enclave_keys"])
+}
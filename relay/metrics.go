@@ -0,0 +1,296 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/common/expfmt"
+	"go.opentelemetry.io/otel"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const (
+	MetricsSinkRelayer     = "relayer"
+	MetricsSinkNoop        = "noop"
+	MetricsSinkPrometheus  = "prometheus"
+	MetricsSinkPushgateway = "pushgateway"
+
+	DefaultMetricsPushInterval   = 15 // seconds
+	DefaultMetricsPushgatewayJob = "lcp-prover"
+
+	// the deadline to push the metrics to the pushgateway or to finish the in-flight scrapes on Shutdown
+	metricsShutdownTimeout = 5 * time.Second
+)
+
+// metricsSink provides the meter with which the prover records the metrics, and exports them to the backend.
+// All the instrumentation of the prover records the metrics with the meter of the sink.
+type metricsSink interface {
+	// Meter returns the meter to record the metrics with
+	Meter() metric.Meter
+	// Start starts exporting the metrics
+	Start(logger *log.RelayLogger) error
+	// Shutdown exports the remaining metrics and stops exporting them
+	Shutdown(ctx context.Context) error
+}
+
+func (pc ProverConfig) GetMetricsSink() string {
+	if pc.MetricsSink == "" {
+		return MetricsSinkRelayer
+	}
+	return pc.MetricsSink
+}
+
+func (pc ProverConfig) GetMetricsPushInterval() time.Duration {
+	if pc.MetricsPushInterval == 0 {
+		return DefaultMetricsPushInterval * time.Second
+	}
+	return time.Duration(pc.MetricsPushInterval) * time.Second
+}
+
+func (pc ProverConfig) GetMetricsPushgatewayJob() string {
+	if pc.MetricsPushgatewayJob == "" {
+		return DefaultMetricsPushgatewayJob
+	}
+	return pc.MetricsPushgatewayJob
+}
+
+func (pc ProverConfig) validateMetricsSink() error {
+	sink := pc.GetMetricsSink()
+	switch sink {
+	case MetricsSinkRelayer, MetricsSinkNoop:
+	case MetricsSinkPrometheus:
+		if _, port, err := net.SplitHostPort(pc.MetricsAddress); err != nil {
+			return fmt.Errorf("MetricsAddress must be in the form of host:port if MetricsSink is %q: value=%q %v", sink, pc.MetricsAddress, err)
+		} else if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("MetricsAddress has an invalid port: value=%q", pc.MetricsAddress)
+		}
+	case MetricsSinkPushgateway:
+		if u, err := url.Parse(pc.MetricsPushgatewayUrl); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("MetricsPushgatewayUrl must be a valid http(s) URL if MetricsSink is %q: %v", sink, pc.MetricsPushgatewayUrl)
+		}
+	default:
+		return fmt.Errorf("MetricsSink must be one of %q, %q, %q or %q, but got %q", MetricsSinkRelayer, MetricsSinkNoop, MetricsSinkPrometheus, MetricsSinkPushgateway, sink)
+	}
+	if sink != MetricsSinkPrometheus && pc.MetricsAddress != "" {
+		return fmt.Errorf("MetricsAddress must be set only if MetricsSink is %q", MetricsSinkPrometheus)
+	}
+	if sink != MetricsSinkPushgateway && (pc.MetricsPushgatewayUrl != "" || pc.MetricsPushgatewayJob != "" || pc.MetricsPushInterval != 0) {
+		return fmt.Errorf("the pushgateway options must be set only if MetricsSink is %q", MetricsSinkPushgateway)
+	}
+	return nil
+}
+
+// newMetricsSink returns the sink selected by the config.
+// The metrics pushed to the pushgateway are grouped by `chainID` as the instance so that the provers of the chains do not overwrite each other.
+func newMetricsSink(config ProverConfig, chainID string) (metricsSink, error) {
+	switch sink := config.GetMetricsSink(); sink {
+	case MetricsSinkRelayer:
+		return relayerMetricsSink{}, nil
+	case MetricsSinkNoop:
+		return noopMetricsSink{}, nil
+	case MetricsSinkPrometheus:
+		registry, err := newMetricsRegistry()
+		if err != nil {
+			return nil, err
+		}
+		return &prometheusMetricsSink{metricsRegistry: registry, address: config.MetricsAddress}, nil
+	case MetricsSinkPushgateway:
+		registry, err := newMetricsRegistry()
+		if err != nil {
+			return nil, err
+		}
+		pusher := push.New(config.MetricsPushgatewayUrl, config.GetMetricsPushgatewayJob()).
+			Gatherer(registry.registry).
+			Grouping("instance", chainID).
+			Format(expfmt.FmtText)
+		return &pushgatewayMetricsSink{metricsRegistry: registry, pusher: pusher, interval: config.GetMetricsPushInterval()}, nil
+	default:
+		return nil, fmt.Errorf("unknown metrics sink: %v", sink)
+	}
+}
+
+// meterOf returns the meter of the sink.
+// If the sink is not set, e.g. the prover is not built from the config, the meter of the global meter provider is returned.
+func meterOf(sink metricsSink) metric.Meter {
+	if sink == nil {
+		return relayerMetricsSink{}.Meter()
+	}
+	return sink.Meter()
+}
+
+// meter returns the meter with which the instrumentation of the prover records the metrics
+func (pr *Prover) meter() metric.Meter {
+	return meterOf(pr.metrics)
+}
+
+// relayerMetricsSink records the metrics with the global meter provider, which the relayer sets up by its telemetry config.
+// The metrics are not exported if the telemetry of the relayer is disabled.
+type relayerMetricsSink struct{}
+
+func (relayerMetricsSink) Meter() metric.Meter {
+	// the global meter provider may be replaced after the prover is built
+	return otel.Meter(meterName)
+}
+
+func (relayerMetricsSink) Start(*log.RelayLogger) error { return nil }
+
+func (relayerMetricsSink) Shutdown(context.Context) error { return nil }
+
+// noopMetricsSink discards the metrics
+type noopMetricsSink struct{}
+
+func (noopMetricsSink) Meter() metric.Meter {
+	return noop.NewMeterProvider().Meter(meterName)
+}
+
+func (noopMetricsSink) Start(*log.RelayLogger) error { return nil }
+
+func (noopMetricsSink) Shutdown(context.Context) error { return nil }
+
+// metricsRegistry is a Prometheus registry local to the prover, into which a meter provider exports the metrics.
+// It is not the default registry of Prometheus because multiple provers may run in a process.
+type metricsRegistry struct {
+	registry *prometheus.Registry
+	provider *sdkmetric.MeterProvider
+}
+
+func newMetricsRegistry() (metricsRegistry, error) {
+	registry := prometheus.NewRegistry()
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry), otelprometheus.WithoutScopeInfo())
+	if err != nil {
+		return metricsRegistry{}, fmt.Errorf("failed to create the Prometheus exporter: %w", err)
+	}
+	return metricsRegistry{registry: registry, provider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(exporter))}, nil
+}
+
+func (r metricsRegistry) Meter() metric.Meter {
+	return r.provider.Meter(meterName)
+}
+
+// prometheusMetricsSink serves the metrics in the local registry at /metrics of the address
+type prometheusMetricsSink struct {
+	metricsRegistry
+	address string
+
+	mu       sync.Mutex
+	listener net.Listener
+	server   *http.Server
+}
+
+func (s *prometheusMetricsSink) Start(logger *log.RelayLogger) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.server != nil {
+		return nil
+	}
+	listener, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on the metrics address: address=%v %w", s.address, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{}))
+	s.listener = listener
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	logger.Info("start serving the metrics", "address", listener.Addr().String())
+	server := s.server
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("the metrics server stopped", err)
+		}
+	}()
+	return nil
+}
+
+func (s *prometheusMetricsSink) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	server := s.server
+	s.server = nil
+	s.mu.Unlock()
+	if server == nil {
+		return nil
+	}
+	if err := server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to stop the metrics server: %w", err)
+	}
+	return nil
+}
+
+// pushgatewayMetricsSink pushes the metrics in the local registry to the pushgateway periodically.
+// The pushed metrics replace the ones previously pushed with the same job and grouping key.
+type pushgatewayMetricsSink struct {
+	metricsRegistry
+	pusher   *push.Pusher
+	interval time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (s *pushgatewayMetricsSink) Start(logger *log.RelayLogger) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return nil
+	}
+	s.stop, s.done = make(chan struct{}), make(chan struct{})
+	logger.Info("start pushing the metrics to the pushgateway", "interval", s.interval)
+	go func(stop <-chan struct{}, done chan<- struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				// the failures are only logged and the next push retries it
+				if err := s.push(context.Background()); err != nil {
+					logger.Warn("failed to push the metrics to the pushgateway", "error", err)
+				}
+			}
+		}
+	}(s.stop, s.done)
+	return nil
+}
+
+// push pushes the current metrics to the pushgateway
+func (s *pushgatewayMetricsSink) push(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.interval)
+	defer cancel()
+	return s.pusher.PushContext(ctx)
+}
+
+// Shutdown stops the periodic push and pushes the final metrics
+func (s *pushgatewayMetricsSink) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop == nil {
+		return nil
+	}
+	close(stop)
+	<-done
+	if err := s.push(ctx); err != nil {
+		return fmt.Errorf("failed to push the metrics to the pushgateway: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package relay
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/require"
+)
+
+type pushRequest struct {
+	method      string
+	path        string
+	contentType string
+	body        string
+}
+
+func newPushgatewayServer(t *testing.T, statuses ...int) (*httptest.Server, func() []pushRequest) {
+	var (
+		mu       sync.Mutex
+		requests []pushRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, pushRequest{method: r.Method, path: r.URL.Path, contentType: r.Header.Get("Content-Type"), body: string(body)})
+		if i := len(requests) - 1; i < len(statuses) {
+			w.WriteHeader(statuses[i])
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []pushRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]pushRequest(nil), requests...)
+	}
+}
+
+func TestPushgatewayMetricsSink(t *testing.T) {
+	require := require.New(t)
+	// the first push fails, which is retried by the next one
+	srv, requests := newPushgatewayServer(t, http.StatusInternalServerError)
+	config := ProverConfig{MetricsSink: MetricsSinkPushgateway, MetricsPushgatewayUrl: srv.URL}
+	require.NoError(config.validateMetricsSink())
+	sink, err := newMetricsSink(config, "origin")
+	require.NoError(err)
+	sink.(*pushgatewayMetricsSink).interval = 10 * time.Millisecond
+
+	pr := newTestProver(t)
+	pr.metrics = sink
+	pr.countTimestampRegression(context.TODO(), "ibc-1", timestampRegressionSourceBatch)
+	const sample = `lcp_timestamp_regressions_ratio_total{chain_id="ibc-1",source="batch"} 1`
+
+	require.NoError(sink.Start(log.GetLogger()))
+	require.Eventually(func() bool { return len(requests()) >= 2 }, time.Second, 10*time.Millisecond)
+	pr.countTimestampRegression(context.TODO(), "ibc-1", timestampRegressionSourceBatch)
+	require.NoError(sink.Shutdown(context.TODO()))
+	reqs := requests()
+	for _, req := range reqs {
+		require.Equal(http.MethodPut, req.method)
+		require.Equal("/metrics/job/"+DefaultMetricsPushgatewayJob+"/instance/origin", req.path)
+		require.True(strings.HasPrefix(req.contentType, "text/plain; version=0.0.4"), req.contentType)
+		require.Contains(req.body, "# TYPE lcp_timestamp_regressions_ratio_total counter")
+	}
+	require.Contains(reqs[0].body, sample)
+	// the final metrics are pushed on Shutdown
+	require.Contains(reqs[len(reqs)-1].body, `lcp_timestamp_regressions_ratio_total{chain_id="ibc-1",source="batch"} 2`)
+
+	// no more push after Shutdown
+	require.NoError(sink.Shutdown(context.TODO()))
+	time.Sleep(30 * time.Millisecond)
+	require.Len(requests(), len(reqs))
+}
+
+func TestPrometheusMetricsSink(t *testing.T) {
+	require := require.New(t)
+	sink, err := newMetricsSink(ProverConfig{MetricsSink: MetricsSinkPrometheus, MetricsAddress: "127.0.0.1:0"}, "origin")
+	require.NoError(err)
+	pr := newTestProver(t)
+	pr.metrics = sink
+	pr.countRegistrationReorg(context.TODO(), registrationReorgDropped)
+
+	require.NoError(sink.Start(log.GetLogger()))
+	t.Cleanup(func() { sink.Shutdown(context.TODO()) })
+	res, err := http.Get("http://" + sink.(*prometheusMetricsSink).listener.Addr().String() + "/metrics")
+	require.NoError(err)
+	defer res.Body.Close()
+	require.Equal(http.StatusOK, res.StatusCode)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(err)
+	require.Contains(string(body), `lcp_enclave_key_registration_reorgs_ratio_total{chain_id="",kind="dropped"} 1`)
+}
+
+func TestValidateMetricsSink(t *testing.T) {
+	var cases = []struct {
+		name   string
+		config ProverConfig
+		// expected substring of the error. if empty, the config is valid
+		err string
+	}{
+		{"default", ProverConfig{}, ""},
+		{"noop", ProverConfig{MetricsSink: MetricsSinkNoop}, ""},
+		{"prometheus", ProverConfig{MetricsSink: MetricsSinkPrometheus, MetricsAddress: "127.0.0.1:9464"}, ""},
+		{"pushgateway", ProverConfig{MetricsSink: MetricsSinkPushgateway, MetricsPushgatewayUrl: "http://pushgateway:9091", MetricsPushInterval: 30}, ""},
+
+		{"unknown sink", ProverConfig{MetricsSink: "statsd"}, `but got "statsd"`},
+		{"prometheus without address", ProverConfig{MetricsSink: MetricsSinkPrometheus}, "MetricsAddress must be in the form of host:port"},
+		{"prometheus with invalid port", ProverConfig{MetricsSink: MetricsSinkPrometheus, MetricsAddress: "127.0.0.1:70000"}, "invalid port"},
+		{"pushgateway without url", ProverConfig{MetricsSink: MetricsSinkPushgateway}, "MetricsPushgatewayUrl must be a valid http(s) URL"},
+		{"address without prometheus", ProverConfig{MetricsAddress: "127.0.0.1:9464"}, "MetricsAddress must be set only"},
+		{"push interval without pushgateway", ProverConfig{MetricsSink: MetricsSinkNoop, MetricsPushInterval: 30}, "pushgateway options"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.validateMetricsSink()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package relay
+
+import (
+	"fmt"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// doSubmitMisbehaviour forwards the misbehaviour detected by ELC to the counterparty client, which freezes the client.
+// `res` is the response of ELC's UpdateClient with the misbehaviour of the origin chain, i.e. it carries a MisbehaviourProxyMessage signed by the enclave key.
+// The frozen client can be unfrozen only by substituting another client for it.
+func (pr *Prover) doSubmitMisbehaviour(counterparty core.Chain, res *elc.MsgUpdateClientResponse) ([]core.MsgID, error) {
+	msg, err := lcptypes.EthABIDecodeHeaderedProxyMessage(res.Message)
+	if err != nil {
+		return nil, err
+	}
+	if err := pr.checkMessageVersion(msg); err != nil {
+		return nil, err
+	}
+	if msg.Type != lcptypes.LCPMessageTypeMisbehaviour {
+		return nil, fmt.Errorf("the message is not a misbehaviour: type=%v", msg.Type)
+	}
+	pmsg, err := msg.GetMisbehaviourProxyMessage()
+	if err != nil {
+		return nil, err
+	}
+	enclaveKey, err := lcptypes.VerifySignature(res.Message, res.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover the signer of the misbehaviour: %w", err)
+	}
+	message := &lcptypes.UpdateClientMessage{
+		ProxyMessage: res.Message,
+		Signatures:   [][]byte{res.Signature},
+	}
+	if err := message.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	signer, err := counterparty.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := pr.encodeClientMessages(counterparty.Path().ClientID, signer, message)
+	if err != nil {
+		return nil, err
+	}
+	pr.getLogger().Warn("submit the misbehaviour to freeze the counterparty client", "client_id", counterparty.Path().ClientID, "enclave_key", enclaveKey, "prev_states", len(pmsg.PrevStates))
+	return pr.sendMsgs(counterparty, "submit_misbehaviour", msgs)
+}
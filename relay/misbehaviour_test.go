@@ -0,0 +1,51 @@
+package relay
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/light-clients/lcp/testutil"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoSubmitMisbehaviour(t *testing.T) {
+	require := require.New(t)
+	key := testutil.TestEnclaveKey(t)
+	height := clienttypes.NewHeight(0, 1)
+
+	h := testutil.NewHarness(t)
+	h.Initialize(&lcptypes.ClientState{LatestHeight: height, KeyExpiration: 3600}, testutil.NewConsensusState(height, testutil.DefaultBlockTime))
+	h.SetEnclaveKey(crypto.PubkeyToAddress(key.PublicKey), h.Ctx.BlockTime().Add(time.Hour), common.Address{})
+
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	cp := newMockCounterparty(height)
+	responseOf := func(msg *lcptypes.UpdateClientMessage) *elc.MsgUpdateClientResponse {
+		return &elc.MsgUpdateClientResponse{Message: msg.ProxyMessage, Signature: msg.Signatures[0]}
+	}
+
+	// an update of the states is not forwarded as a misbehaviour
+	_, err := pr.doSubmitMisbehaviour(cp, responseOf(testutil.NewUpdateClientMessage(t, height, clienttypes.NewHeight(0, 2), h.Ctx.BlockTime(), key)))
+	require.ErrorContains(err, "not a misbehaviour")
+	require.Equal(0, cp.sendMsgsCalls)
+
+	_, err = pr.doSubmitMisbehaviour(cp, responseOf(testutil.NewMisbehaviourMessage(t, []clienttypes.Height{height}, key)))
+	require.NoError(err)
+	require.Len(cp.sentMsgs, 1)
+	require.Len(cp.sentMsgs[0], 1)
+	msg, ok := cp.sentMsgs[0][0].(*clienttypes.MsgUpdateClient)
+	require.True(ok)
+	require.Equal(cp.Path().ClientID, msg.ClientId)
+	var message ibcexported.ClientMessage
+	require.NoError(pr.codec.UnpackAny(msg.ClientMessage, &message))
+
+	// the light client freezes the client with the forwarded message
+	require.NoError(h.Update(message))
+	require.True(h.ClientState().Frozen)
+}
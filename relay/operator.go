@@ -4,10 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"strings"
+	"slices"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
 	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
 	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -32,14 +30,170 @@ func (pr *Prover) GetOperators() ([]common.Address, error) {
 	return operators, nil
 }
 
+// GetOperatorsThreshold returns the threshold of the operators.
+// It is 0/0 if the operators are empty(=permissionless), and 1/1 by default otherwise.
 func (pr *Prover) GetOperatorsThreshold() Fraction {
-	if pr.config.OperatorsThreshold.Denominator == 0 && pr.config.OperatorsThreshold.Numerator == 0 {
+	if len(pr.config.Operators) == 0 {
+		return Fraction{}
+	} else if pr.config.OperatorsThreshold.Denominator == 0 && pr.config.OperatorsThreshold.Numerator == 0 {
 		return Fraction{Numerator: 1, Denominator: 1}
 	}
 	return pr.config.OperatorsThreshold
 }
 
-func (pr *Prover) updateOperators(counterparty core.Chain, nonce uint64, newOperators []common.Address, threshold Fraction) error {
+// UpdateOperatorsResult is a result of doUpdateOperators
+type UpdateOperatorsResult struct {
+	Nonce                            uint64           `json:"nonce"`
+	NewOperators                     []common.Address `json:"new_operators"`
+	NewOperatorsThresholdNumerator   uint64           `json:"new_operators_threshold_numerator"`
+	NewOperatorsThresholdDenominator uint64           `json:"new_operators_threshold_denominator"`
+	// the current operators whose signatures are included in the message
+	Signers []common.Address `json:"signers"`
+	// empty in the rehearsal mode
+	MsgID string `json:"msg_id,omitempty"`
+	// "finalized", "included" or "failed". empty if the msg is not submitted or its status is unknown
+	Status string `json:"status,omitempty"`
+}
+
+// validateNewOperators returns an error if the light client never accepts `newOperators` and the threshold.
+// An empty `newOperators` makes the client permissionless.
+func validateNewOperators(newOperators []common.Address, threshold Fraction) error {
+	if threshold.Numerator == 0 || threshold.Denominator == 0 {
+		return fmt.Errorf("invalid threshold: %s", threshold.String())
+	} else if threshold.Numerator > threshold.Denominator {
+		return fmt.Errorf("new operators threshold numerator cannot be greater than denominator: %s", threshold.String())
+	}
+	for i, op := range newOperators {
+		if op == (common.Address{}) {
+			return fmt.Errorf("new operator address must not be zero: index=%v", i)
+		} else if i > 0 && bytes.Compare(newOperators[i-1].Bytes(), op.Bytes()) >= 0 {
+			return fmt.Errorf("new operators must be ordered in ascending order without duplicates: index=%v op0=%v op1=%v", i, newOperators[i-1], op)
+		}
+	}
+	return nil
+}
+
+// doUpdateOperators submits the message to rotate the operators of the counterparty client to `newOperators` with the threshold.
+// The nonce is the next one of the client. `signatures` are the signatures of the current operators collected externally,
+// which must be ordered as the operators of the client with empty ones for the operators who did not sign.
+// If the local operator signer is one of the current operators without a signature in `signatures`, it signs the message.
+func (pr *Prover) doUpdateOperators(counterparty core.FinalityAwareChain, newOperators []common.Address, thresholdNum, thresholdDenom uint64, signatures [][]byte) (*UpdateOperatorsResult, error) {
+	threshold := Fraction{Numerator: thresholdNum, Denominator: thresholdDenom}
+	if pr.config.OperatorsEip712Params == nil {
+		return nil, fmt.Errorf("operator EIP712 parameters are not set")
+	} else if err := validateNewOperators(newOperators, threshold); err != nil {
+		return nil, err
+	}
+	cplatestHeight, err := counterparty.LatestHeight()
+	if err != nil {
+		return nil, err
+	}
+	counterpartyClientRes, err := counterparty.QueryClientState(core.NewQueryContext(context.TODO(), cplatestHeight))
+	if err != nil {
+		return nil, err
+	}
+	var cs ibcexported.ClientState
+	if err := pr.codec.UnpackAny(counterpartyClientRes.ClientState, &cs); err != nil {
+		return nil, fmt.Errorf("failed to unpack client state: client_state=%v %w", counterpartyClientRes.ClientState, err)
+	}
+	clientState, ok := cs.(*lcptypes.ClientState)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast client state: %T", cs)
+	}
+	if !clientState.HasOperators() {
+		return nil, fmt.Errorf("updateOperators is not supported in permissionless operator mode")
+	}
+	operators := clientState.GetOperators()
+	if len(signatures) == 0 {
+		signatures = make([][]byte, len(operators))
+	} else if len(signatures) != len(operators) {
+		return nil, fmt.Errorf("the signatures must be ordered as the operators of the client: expected=%v actual=%v", len(operators), len(signatures))
+	}
+	nonce := clientState.NextOperatorsNonce()
+	commitment, err := pr.ComputeEIP712UpdateOperatorsHash(nonce, newOperators, threshold.Numerator, threshold.Denominator)
+	if err != nil {
+		return nil, err
+	}
+	if pr.eip712Signer != nil {
+		opSigner, err := pr.eip712Signer.GetSignerAddress()
+		if err != nil {
+			return nil, err
+		}
+		if i := slices.Index(operators, opSigner); i < 0 {
+			pr.getLogger().Warn("the local operator signer is not an operator of the client", "signer", opSigner)
+		} else if len(signatures[i]) == 0 {
+			if signatures[i], err = pr.eip712Signer.Sign(commitment); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := pr.checkEIP712Digest("UpdateOperators", commitment, pr.contractUpdateOperatorsDigest(nonce, newOperators, threshold.Numerator, threshold.Denominator)); err != nil {
+		return nil, err
+	}
+	result := &UpdateOperatorsResult{
+		Nonce:                            nonce,
+		NewOperators:                     newOperators,
+		NewOperatorsThresholdNumerator:   threshold.Numerator,
+		NewOperatorsThresholdDenominator: threshold.Denominator,
+		Signers:                          []common.Address{},
+	}
+	for i, sig := range signatures {
+		if len(sig) > 0 {
+			result.Signers = append(result.Signers, operators[i])
+		}
+	}
+	if uint64(len(result.Signers))*clientState.OperatorsThresholdDenominator < clientState.OperatorsThresholdNumerator*uint64(len(operators)) {
+		return nil, fmt.Errorf("insufficient signatures: threshold=%v/%v operators=%v signers=%v", clientState.OperatorsThresholdNumerator, clientState.OperatorsThresholdDenominator, len(operators), result.Signers)
+	}
+	var ops [][]byte
+	for _, operator := range newOperators {
+		ops = append(ops, operator.Bytes())
+	}
+	message := &lcptypes.UpdateOperatorsMessage{
+		Nonce:                            nonce,
+		NewOperators:                     ops,
+		NewOperatorsThresholdNumerator:   threshold.Numerator,
+		NewOperatorsThresholdDenominator: threshold.Denominator,
+		Signatures:                       signatures,
+	}
+	if err := message.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	signer, err := counterparty.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := pr.encodeClientMessages(counterparty.Path().ClientID, signer, message)
+	if err != nil {
+		return nil, err
+	}
+	msgIDs, err := pr.sendMsgs(counterparty, "update_operators", msgs)
+	if err != nil {
+		return nil, err
+	} else if len(msgIDs) == 0 {
+		// rehearsal
+		return result, nil
+	}
+	result.MsgID = msgIDs[0].String()
+	finalized, success, _, err := pr.checkMsgStatus(counterparty, msgIDs[0])
+	if err != nil {
+		pr.getLogger().Warn("failed to check the status of the msg", "msg_id", result.MsgID, "error", err)
+		return result, nil
+	}
+	switch {
+	case !success:
+		result.Status = "failed"
+	case finalized:
+		result.Status = "finalized"
+	default:
+		result.Status = "included"
+	}
+	return result, nil
+}
+
+// doUpdateClientParams submits the message to replace the parameters to verify the AVR of the enclave keys of the counterparty client.
+// The enclave keys already registered in the client are not affected, and the new key expiration applies to the keys registered afterwards.
+func (pr *Prover) doUpdateClientParams(counterparty core.Chain, nonce uint64, allowedQuoteStatuses, allowedAdvisoryIDs []string, keyExpiration uint64) error {
 	if !pr.IsOperatorEnabled() {
 		return fmt.Errorf("operator is not enabled")
 	} else if pr.config.OperatorsEip712Params == nil {
@@ -48,11 +202,11 @@ func (pr *Prover) updateOperators(counterparty core.Chain, nonce uint64, newOper
 	if nonce == 0 {
 		return fmt.Errorf("invalid nonce: %v", nonce)
 	}
-	if threshold.Numerator == 0 || threshold.Denominator == 0 {
-		return fmt.Errorf("invalid threshold: %s", threshold.String())
+	if keyExpiration == 0 {
+		return fmt.Errorf("invalid key expiration: %v", keyExpiration)
 	}
-	if threshold.Numerator > threshold.Denominator {
-		return fmt.Errorf("new operators threshold numerator cannot be greater than denominator: %s", threshold.String())
+	if err := validateQuotePolicy(allowedQuoteStatuses, allowedAdvisoryIDs); err != nil {
+		return err
 	}
 	cplatestHeight, err := counterparty.LatestHeight()
 	if err != nil {
@@ -70,23 +224,26 @@ func (pr *Prover) updateOperators(counterparty core.Chain, nonce uint64, newOper
 	if !ok {
 		return fmt.Errorf("failed to cast client state: %T", cs)
 	}
-	if l := len(clientState.Operators); l == 0 {
-		return fmt.Errorf("updateOperators is not supported in permissionless operator mode")
-	} else if l > 1 {
+	if !clientState.HasOperators() {
+		return fmt.Errorf("updateClientParams is not supported in permissionless operator mode")
+	} else if l := len(clientState.Operators); l > 1 {
 		return fmt.Errorf("currently only one operator is supported, but got %v", l)
 	}
+	if next := clientState.NextOperatorsNonce(); nonce != next {
+		return fmt.Errorf("invalid nonce: expected=%v actual=%v", next, nonce)
+	}
 	opSigner, err := pr.eip712Signer.GetSignerAddress()
 	if err != nil {
 		return err
 	}
 	if !bytes.Equal(clientState.Operators[0], opSigner.Bytes()) {
-		return fmt.Errorf("operator mismatch: expected 0x%x, but got 0x%x", clientState.Operators[0], opSigner)
+		return fmt.Errorf("operator mismatch: expected %v, but got %v", common.BytesToAddress(clientState.Operators[0]), opSigner)
 	}
-	commitment, err := pr.ComputeEIP712UpdateOperatorsHash(
+	commitment, err := pr.ComputeEIP712UpdateClientParamsHash(
 		nonce,
-		newOperators,
-		threshold.Numerator,
-		threshold.Denominator,
+		allowedQuoteStatuses,
+		allowedAdvisoryIDs,
+		keyExpiration,
 	)
 	if err != nil {
 		return err
@@ -95,31 +252,105 @@ func (pr *Prover) updateOperators(counterparty core.Chain, nonce uint64, newOper
 	if err != nil {
 		return err
 	}
-	var ops [][]byte
-	for _, operator := range newOperators {
-		ops = append(ops, operator.Bytes())
+	if err := pr.checkEIP712Digest("UpdateClientParams", commitment, pr.contractUpdateClientParamsDigest(nonce, allowedQuoteStatuses, allowedAdvisoryIDs, keyExpiration)); err != nil {
+		return err
 	}
-	message := &lcptypes.UpdateOperatorsMessage{
-		Nonce:                            nonce,
-		NewOperators:                     ops,
-		NewOperatorsThresholdNumerator:   threshold.Numerator,
-		NewOperatorsThresholdDenominator: threshold.Denominator,
-		Signatures:                       [][]byte{sig},
+	message := &lcptypes.UpdateClientParamsMessage{
+		Nonce:                   nonce,
+		NewAllowedQuoteStatuses: allowedQuoteStatuses,
+		NewAllowedAdvisoryIds:   allowedAdvisoryIDs,
+		NewKeyExpiration:        keyExpiration,
+		Signatures:              [][]byte{sig},
+	}
+	if err := message.ValidateBasic(); err != nil {
+		return err
 	}
 	signer, err := counterparty.GetAddress()
 	if err != nil {
 		return err
 	}
-	msg, err := clienttypes.NewMsgUpdateClient(counterparty.Path().ClientID, message, signer.String())
+	msgs, err := pr.encodeClientMessages(counterparty.Path().ClientID, signer, message)
 	if err != nil {
 		return err
 	}
-	if _, err := counterparty.SendMsgs([]sdk.Msg{msg}); err != nil {
+	if _, err := pr.sendMsgs(counterparty, "update_client_params", msgs); err != nil {
 		return err
 	}
 	return nil
 }
 
+// OperatorsNonceQuerierChain is implemented by the counterparty chains that can query the nonce of the operators updates of the LCP client.
+// On Cosmos chains, the nonce is queried with the OperatorsNonce query of lcptypes.QueryClient.
+type OperatorsNonceQuerierChain interface {
+	// QueryOperatorsNonce returns the nonce of the latest operators update of the LCP client `clientID` at the height of `ctx`
+	QueryOperatorsNonce(ctx core.QueryContext, clientID string) (uint64, error)
+}
+
+// QueryOperatorsResult is the result of doQueryOperators
+type QueryOperatorsResult struct {
+	ClientID string `json:"client_id"`
+	// the latest height of the counterparty chain at which the operators are queried
+	Height string `json:"height"`
+	// empty if the client is permissionless
+	Operators                     []common.Address `json:"operators"`
+	OperatorsThresholdNumerator   uint64           `json:"operators_threshold_numerator"`
+	OperatorsThresholdDenominator uint64           `json:"operators_threshold_denominator"`
+	// the nonce of the latest operators update, and the one that the next UpdateOperators or UpdateClientParams message must have
+	Nonce     uint64 `json:"nonce"`
+	NextNonce uint64 `json:"next_nonce"`
+}
+
+// doQueryOperators returns the operators of the LCP client on `counterparty` and the nonce of their updates.
+// The nonce is queried from the chain if it implements OperatorsNonceQuerierChain, otherwise it is read from the client state.
+func (pr *Prover) doQueryOperators(counterparty core.Chain) (*QueryOperatorsResult, error) {
+	height, err := counterparty.LatestHeight()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	ctx := core.NewQueryContext(context.TODO(), height)
+	counterpartyClientRes, err := counterparty.QueryClientState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var cs ibcexported.ClientState
+	if err := pr.codec.UnpackAny(counterpartyClientRes.ClientState, &cs); err != nil {
+		return nil, fmt.Errorf("failed to unpack client state: client_state=%v %w", counterpartyClientRes.ClientState, err)
+	}
+	clientState, ok := cs.(*lcptypes.ClientState)
+	if !ok {
+		return nil, fmt.Errorf("failed to cast client state: %T", cs)
+	}
+	clientID := counterparty.Path().ClientID
+	nonce := clientState.OperatorsNonce
+	chain := counterparty
+	// the query is implemented by the chain module rather than the provable chain wrapping it
+	if pc, ok := counterparty.(*core.ProvableChain); ok {
+		chain = pc.Chain
+	}
+	if querier, ok := chain.(OperatorsNonceQuerierChain); ok {
+		if nonce, err = querier.QueryOperatorsNonce(ctx, clientID); err != nil {
+			return nil, fmt.Errorf("failed to query the operators nonce: client_id=%v height=%v %w", clientID, height, err)
+		}
+	}
+	return &QueryOperatorsResult{
+		ClientID:                      clientID,
+		Height:                        height.String(),
+		Operators:                     append([]common.Address{}, clientState.GetOperators()...),
+		OperatorsThresholdNumerator:   clientState.OperatorsThresholdNumerator,
+		OperatorsThresholdDenominator: clientState.OperatorsThresholdDenominator,
+		Nonce:                         nonce,
+		NextNonce:                     nonce + 1,
+	}, nil
+}
+
+// OperatorSigner produces the operator signatures over the EIP712 digests
+type OperatorSigner interface {
+	GetSignerAddress() (common.Address, error)
+	Sign(commitment [32]byte) ([]byte, error)
+}
+
+var _ OperatorSigner = (*EIP712Signer)(nil)
+
 type EIP712Signer struct {
 	signer signer.Signer
 }
@@ -144,10 +375,24 @@ func (s EIP712Signer) GetSignerAddress() (common.Address, error) {
 	return crypto.PubkeyToAddress(*pubKey), nil
 }
 
-func decodeOperatorAddress(s string) (common.Address, error) {
-	addrStr := strings.TrimPrefix(s, "0x")
-	if len(addrStr) != 40 {
-		return common.Address{}, fmt.Errorf("invalid operator address length %v", len(addrStr))
+// signerAddressProvider is implemented by the signer configs that can resolve the signer address without building the signer,
+// e.g. without decrypting the key
+type signerAddressProvider interface {
+	GetSignerAddress() (common.Address, error)
+}
+
+// getSignerConfigAddress returns the address of the signer built from `config`
+func getSignerConfigAddress(config signer.SignerConfig) (common.Address, error) {
+	if p, ok := config.(signerAddressProvider); ok {
+		return p.GetSignerAddress()
+	}
+	s, err := config.Build()
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to build the signer: %w", err)
 	}
-	return common.HexToAddress(s), nil
+	return NewEIP712Signer(s).GetSignerAddress()
+}
+
+func decodeOperatorAddress(s string) (common.Address, error) {
+	return parseHexAddress(s)
 }
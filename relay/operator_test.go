@@ -0,0 +1,201 @@
+package relay
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/light-clients/lcp/testutil"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/signers/raw"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoUpdateClientParams(t *testing.T) {
+	require := require.New(t)
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	operator := crypto.PubkeyToAddress(key.PublicKey)
+
+	h := testutil.NewHarness(t)
+	h.Ctx = h.Ctx.WithChainID("ibc-0")
+	clientState := &lcptypes.ClientState{
+		KeyExpiration:                 3600,
+		Operators:                     [][]byte{operator.Bytes()},
+		OperatorsThresholdNumerator:   1,
+		OperatorsThresholdDenominator: 1,
+	}
+	h.SetClientState(clientState)
+
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.path = &core.PathEnd{ClientID: testutil.DefaultClientID}
+	pr.config.Operators = []string{operator.Hex()}
+	pr.config.OperatorsEip712Params = &ProverConfig_OperatorsEip712CosmosChainParams{
+		OperatorsEip712CosmosChainParams: &EIP712CosmosChainParams{ChainId: "ibc-0", Prefix: ibcexported.StoreKey},
+	}
+	pr.eip712Signer = NewEIP712Signer(raw.NewSigner(key))
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 1))
+	cp.clientState = clientState
+
+	statuses, advisories := []string{lcptypes.QuoteSwHardeningNeeded}, []string{"INTEL-SA-00334"}
+	require.ErrorContains(pr.doUpdateClientParams(cp, 2, statuses, advisories, 60), "invalid nonce")
+	require.ErrorContains(pr.doUpdateClientParams(cp, 1, statuses, advisories, 0), "invalid key expiration")
+	require.ErrorContains(pr.doUpdateClientParams(cp, 1, []string{"OUT_OF_DATE"}, advisories, 60), "AllowedQuoteStatuses[0] is invalid")
+	require.Equal(0, cp.sendMsgsCalls)
+
+	require.NoError(pr.doUpdateClientParams(cp, 1, statuses, advisories, 60))
+	require.Len(cp.sentMsgs, 1)
+	require.Len(cp.sentMsgs[0], 1)
+	msg, ok := cp.sentMsgs[0][0].(*clienttypes.MsgUpdateClient)
+	require.True(ok)
+	require.Equal(cp.Path().ClientID, msg.ClientId)
+	var message ibcexported.ClientMessage
+	require.NoError(pr.codec.UnpackAny(msg.ClientMessage, &message))
+
+	// the light client accepts the message signed by the operator
+	require.NoError(h.Update(message))
+	updated := h.ClientState()
+	require.Equal(statuses, updated.AllowedQuoteStatuses)
+	require.Equal(advisories, updated.AllowedAdvisoryIds)
+	require.Equal(uint64(60), updated.KeyExpiration)
+	require.Equal(uint64(1), updated.OperatorsNonce)
+}
+
+func TestDoUpdateOperators(t *testing.T) {
+	require := require.New(t)
+	var (
+		keys      []*ecdsa.PrivateKey
+		operators [][]byte
+	)
+	for i := 0; i < 2; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(err)
+		keys = append(keys, key)
+		operators = append(operators, crypto.PubkeyToAddress(key.PublicKey).Bytes())
+	}
+
+	h := testutil.NewHarness(t)
+	h.Ctx = h.Ctx.WithChainID("ibc-0")
+	clientState := &lcptypes.ClientState{
+		KeyExpiration:                 3600,
+		Operators:                     operators,
+		OperatorsThresholdNumerator:   2,
+		OperatorsThresholdDenominator: 2,
+	}
+	h.SetClientState(clientState)
+
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.path = &core.PathEnd{ClientID: testutil.DefaultClientID}
+	pr.config.OperatorsEip712Params = &ProverConfig_OperatorsEip712CosmosChainParams{
+		OperatorsEip712CosmosChainParams: &EIP712CosmosChainParams{ChainId: "ibc-0", Prefix: ibcexported.StoreKey},
+	}
+	// the local signer is the second operator
+	pr.eip712Signer = NewEIP712Signer(raw.NewSigner(keys[1]))
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+	cp.clientState = clientState
+	cp.msgResults[(&tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}).String()] = mockMsgResult{height: clienttypes.NewHeight(0, 5), success: true}
+
+	newOperators := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03")}
+	commitment, err := pr.ComputeEIP712UpdateOperatorsHash(1, newOperators, 2, 3)
+	require.NoError(err)
+	sig, err := crypto.Sign(commitment[:], keys[0])
+	require.NoError(err)
+
+	_, err = pr.doUpdateOperators(cp, newOperators, 0, 3, nil)
+	require.ErrorContains(err, "invalid threshold")
+	_, err = pr.doUpdateOperators(cp, newOperators, 4, 3, nil)
+	require.ErrorContains(err, "cannot be greater than denominator")
+	_, err = pr.doUpdateOperators(cp, []common.Address{newOperators[1], newOperators[0]}, 2, 3, nil)
+	require.ErrorContains(err, "ascending order without duplicates")
+	_, err = pr.doUpdateOperators(cp, []common.Address{{}}, 2, 3, nil)
+	require.ErrorContains(err, "must not be zero")
+	_, err = pr.doUpdateOperators(cp, newOperators, 2, 3, [][]byte{sig})
+	require.ErrorContains(err, "the signatures must be ordered as the operators of the client")
+	// the signature of the local signer does not satisfy the threshold
+	_, err = pr.doUpdateOperators(cp, newOperators, 2, 3, nil)
+	require.ErrorContains(err, "insufficient signatures")
+	require.Equal(0, cp.sendMsgsCalls)
+
+	res, err := pr.doUpdateOperators(cp, newOperators, 2, 3, [][]byte{sig, nil})
+	require.NoError(err)
+	require.Equal(uint64(1), res.Nonce)
+	require.Equal([]common.Address{common.BytesToAddress(operators[0]), common.BytesToAddress(operators[1])}, res.Signers)
+	require.Equal("finalized", res.Status)
+	require.NotEmpty(res.MsgID)
+
+	require.Len(cp.sentMsgs, 1)
+	msg, ok := cp.sentMsgs[0][0].(*clienttypes.MsgUpdateClient)
+	require.True(ok)
+	var message ibcexported.ClientMessage
+	require.NoError(pr.codec.UnpackAny(msg.ClientMessage, &message))
+	// the light client accepts the message signed by the quorum of the operators
+	require.NoError(h.Update(message))
+	updated := h.ClientState()
+	require.Equal(newOperators, updated.GetOperators())
+	require.Equal(uint64(2), updated.OperatorsThresholdNumerator)
+	require.Equal(uint64(3), updated.OperatorsThresholdDenominator)
+	require.Equal(uint64(1), updated.OperatorsNonce)
+}
+
+// mockOperatorsNonceQuerierCounterparty is a counterparty chain that can query the nonce of the operators updates of the LCP client
+type mockOperatorsNonceQuerierCounterparty struct {
+	*mockCounterparty
+	nonce uint64
+
+	queriedHeight ibcexported.Height
+}
+
+func (c *mockOperatorsNonceQuerierCounterparty) QueryOperatorsNonce(ctx core.QueryContext, clientID string) (uint64, error) {
+	c.queriedHeight = ctx.Height()
+	return c.nonce, nil
+}
+
+func TestDoQueryOperators(t *testing.T) {
+	require := require.New(t)
+	operator := common.HexToAddress("0x01")
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+	cp.latestHeight = clienttypes.NewHeight(0, 12)
+	cp.clientState = &lcptypes.ClientState{
+		KeyExpiration:                 3600,
+		Operators:                     [][]byte{operator.Bytes()},
+		OperatorsThresholdNumerator:   1,
+		OperatorsThresholdDenominator: 1,
+		OperatorsNonce:                2,
+	}
+
+	// the nonce is read from the client state
+	res, err := pr.doQueryOperators(cp)
+	require.NoError(err)
+	require.Equal("lcp-client-0", res.ClientID)
+	require.Equal("0-12", res.Height)
+	require.Equal([]common.Address{operator}, res.Operators)
+	require.Equal(uint64(1), res.OperatorsThresholdNumerator)
+	require.Equal(uint64(1), res.OperatorsThresholdDenominator)
+	require.Equal(uint64(2), res.Nonce)
+	require.Equal(uint64(3), res.NextNonce)
+
+	// the nonce is queried from the chain if it supports the query
+	querier := &mockOperatorsNonceQuerierCounterparty{mockCounterparty: cp, nonce: 3}
+	res, err = pr.doQueryOperators(querier)
+	require.NoError(err)
+	require.Equal(cp.latestHeight, querier.queriedHeight)
+	require.Equal(uint64(3), res.Nonce)
+	require.Equal(uint64(4), res.NextNonce)
+
+	// permissionless
+	cp.clientState = &lcptypes.ClientState{KeyExpiration: 3600}
+	res, err = pr.doQueryOperators(cp)
+	require.NoError(err)
+	require.Empty(res.Operators)
+	require.Equal(uint64(0), res.Nonce)
+	require.Equal(uint64(1), res.NextNonce)
+}
@@ -0,0 +1,393 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// the call to the origin prover succeeded
+	originProverCallSucceeded = "succeeded"
+	// the call to the origin prover returned an error
+	originProverCallFailed = "failed"
+	// the call to the origin prover returned a result inconsistent with the other origin provers
+	originProverCallInconsistent = "inconsistent"
+)
+
+// originProverSet is the origin prover which fails over the calls to multiple origin provers of the same origin chain.
+// The calls are tried with the prover that succeeded most recently first, then the others in the configured order.
+type originProverSet struct {
+	chain   core.Chain
+	provers []core.Prover
+
+	mu     sync.Mutex
+	health []originProverHealth
+	// the index of the prover that succeeded most recently
+	preferred int
+	// the highest finalized height returned by the provers
+	latestFinalizedHeight exported.Height
+
+	// the sink of the metrics of the calls
+	metrics metricsSink
+}
+
+var _ core.Prover = (*originProverSet)(nil)
+
+// originProverHealth is the result of the recent calls to an origin prover
+type originProverHealth struct {
+	LastSucceededAt     time.Time `json:"last_succeeded_at"`
+	LastFailedAt        time.Time `json:"last_failed_at"`
+	LastError           string    `json:"last_error,omitempty"`
+	ConsecutiveFailures uint64    `json:"consecutive_failures"`
+}
+
+// OriginProverStatus is the health of an origin prover reported by `lcp origin-prover-status`
+type OriginProverStatus struct {
+	Index     int  `json:"index"`
+	Primary   bool `json:"primary"`
+	Preferred bool `json:"preferred"`
+	Healthy   bool `json:"healthy"`
+	originProverHealth
+}
+
+func newOriginProverSet(chain core.Chain, provers []core.Prover) *originProverSet {
+	return &originProverSet{
+		chain:   chain,
+		provers: provers,
+		health:  make([]originProverHealth, len(provers)),
+	}
+}
+
+// buildOriginProvers builds the primary origin prover and the fallbacks.
+// If no fallback is configured, the primary one is returned as it is.
+func (pc ProverConfig) buildOriginProvers(chain core.Chain) (core.Prover, error) {
+	primary, err := pc.OriginProver.GetCachedValue().(core.ProverConfig).Build(chain)
+	if err != nil {
+		return nil, err
+	}
+	if len(pc.FallbackOriginProvers) == 0 {
+		return primary, nil
+	}
+	provers := []core.Prover{primary}
+	for i, fallback := range pc.FallbackOriginProvers {
+		prover, err := fallback.GetCachedValue().(core.ProverConfig).Build(chain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the fallback origin prover: index=%v %w", i, err)
+		}
+		provers = append(provers, prover)
+	}
+	return newOriginProverSet(chain, provers), nil
+}
+
+func (pc ProverConfig) validateFallbackOriginProvers() error {
+	for i, fallback := range pc.FallbackOriginProvers {
+		config, ok := fallback.GetCachedValue().(core.ProverConfig)
+		if !ok {
+			return fmt.Errorf("failed to unpack the fallback origin prover's config: index=%v type_url=%v", i, fallback.GetTypeUrl())
+		}
+		if fallback.TypeUrl != pc.OriginProver.TypeUrl {
+			return fmt.Errorf("the fallback origin prover must be the same type as the origin prover: index=%v expected=%v actual=%v", i, pc.OriginProver.TypeUrl, fallback.TypeUrl)
+		}
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("failed to validate the fallback origin prover's config: index=%v %v", i, err)
+		}
+	}
+	return nil
+}
+
+func (s *originProverSet) getLogger() *log.RelayLogger {
+	return log.GetLogger().WithModule(ModuleName).WithChain(s.chain.ChainID())
+}
+
+// order returns the indexes of the provers in the order to try
+func (s *originProverSet) order() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := []int{s.preferred}
+	for i := range s.provers {
+		if i != s.preferred {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+// recordSuccess records the success of the prover `i`. If `prefer` is true, the prover is tried first in the next calls.
+func (s *originProverSet) recordSuccess(i int, prefer bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health[i].LastSucceededAt = time.Now()
+	s.health[i].ConsecutiveFailures = 0
+	if prefer {
+		s.preferred = i
+	}
+}
+
+func (s *originProverSet) recordFailure(i int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health[i].LastFailedAt = time.Now()
+	s.health[i].LastError = err.Error()
+	s.health[i].ConsecutiveFailures++
+}
+
+// errInconsistentOriginProver is returned if an origin prover returns a result inconsistent with the others
+var errInconsistentOriginProver = errors.New("inconsistent result of the origin prover")
+
+// call calls `fn` with the provers in order until it succeeds
+func (s *originProverSet) call(method string, fn func(prover core.Prover) error) error {
+	var errs []error
+	for _, i := range s.order() {
+		err := fn(s.provers[i])
+		if err == nil {
+			s.recordSuccess(i, true)
+			s.countCall(method, i, originProverCallSucceeded)
+			return nil
+		}
+		s.recordFailure(i, err)
+		if errors.Is(err, errInconsistentOriginProver) {
+			s.countCall(method, i, originProverCallInconsistent)
+		} else {
+			s.countCall(method, i, originProverCallFailed)
+		}
+		s.getLogger().Warn("the origin prover failed, fall back to the next one", "method", method, "index", i, "error", err)
+		errs = append(errs, fmt.Errorf("index=%v %w", i, err))
+	}
+	return fmt.Errorf("all the origin provers failed: method=%v %w", method, errors.Join(errs...))
+}
+
+// forEach calls `fn` with all the provers. It fails only if `fn` fails with all the provers.
+func (s *originProverSet) forEach(method string, fn func(prover core.Prover) error) error {
+	var errs []error
+	for i, prover := range s.provers {
+		if err := fn(prover); err != nil {
+			s.recordFailure(i, err)
+			s.getLogger().Warn("the origin prover failed", "method", method, "index", i, "error", err)
+			errs = append(errs, fmt.Errorf("index=%v %w", i, err))
+		}
+	}
+	if len(errs) == len(s.provers) {
+		return fmt.Errorf("all the origin provers failed: method=%v %w", method, errors.Join(errs...))
+	}
+	return nil
+}
+
+// countCall increments the counter of the calls to the origin provers.
+// The counter is recorded with the metrics sink of the prover.
+func (s *originProverSet) countCall(method string, index int, result string) {
+	counter, err := meterOf(s.metrics).Int64Counter(
+		"lcp.origin_prover_calls",
+		metric.WithUnit("1"),
+		metric.WithDescription("number of calls to the origin provers by the result"),
+	)
+	if err != nil {
+		s.getLogger().Warn("failed to create the counter of the origin prover calls", "error", err)
+		return
+	}
+	counter.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("chain_id", s.chain.ChainID()),
+		attribute.String("method", method),
+		attribute.String("index", strconv.Itoa(index)),
+		attribute.String("result", result),
+	))
+}
+
+// checkChainID checks that the client state created by an origin prover is for the origin chain if the client state has a chain ID
+func (s *originProverSet) checkChainID(clientState exported.ClientState) error {
+	cs, ok := clientState.(interface{ GetChainID() string })
+	if !ok {
+		return nil
+	}
+	if chainID := cs.GetChainID(); chainID != s.chain.ChainID() {
+		return fmt.Errorf("%w: unexpected chain ID: expected=%v actual=%v", errInconsistentOriginProver, s.chain.ChainID(), chainID)
+	}
+	return nil
+}
+
+// checkFinalizedHeight checks that the finalized height does not regress from the highest one returned by the provers
+func (s *originProverSet) checkFinalizedHeight(height exported.Height) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.latestFinalizedHeight != nil && height.LT(s.latestFinalizedHeight) {
+		return fmt.Errorf("%w: the finalized height regressed: latest=%v actual=%v", errInconsistentOriginProver, s.latestFinalizedHeight, height)
+	}
+	s.latestFinalizedHeight = height
+	return nil
+}
+
+// Statuses returns the health of the origin provers
+func (s *originProverSet) Statuses() []OriginProverStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var statuses []OriginProverStatus
+	for i, h := range s.health {
+		statuses = append(statuses, OriginProverStatus{
+			Index:              i,
+			Primary:            i == 0,
+			Preferred:          i == s.preferred,
+			Healthy:            h.ConsecutiveFailures == 0,
+			originProverHealth: h,
+		})
+	}
+	return statuses
+}
+
+// Probe gets the latest finalized header from each origin prover to update the health of them.
+// It does not change the prover tried first.
+func (s *originProverSet) Probe() {
+	for i, prover := range s.provers {
+		header, err := prover.GetLatestFinalizedHeader()
+		if err == nil {
+			err = s.checkFinalizedHeight(header.GetHeight())
+		}
+		if err != nil {
+			s.recordFailure(i, err)
+		} else {
+			s.recordSuccess(i, false)
+		}
+	}
+}
+
+func (s *originProverSet) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, debug bool) error {
+	for i, prover := range s.provers {
+		if err := prover.Init(homePath, timeout, codec, debug); err != nil {
+			return fmt.Errorf("failed to initialize the origin prover: index=%v %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s *originProverSet) SetRelayInfo(path *core.PathEnd, counterparty *core.ProvableChain, counterpartyPath *core.PathEnd) error {
+	for i, prover := range s.provers {
+		if err := prover.SetRelayInfo(path, counterparty, counterpartyPath); err != nil {
+			return fmt.Errorf("failed to set the relay info to the origin prover: index=%v %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s *originProverSet) SetupForRelay(ctx context.Context) error {
+	return s.forEach("SetupForRelay", func(prover core.Prover) error {
+		return prover.SetupForRelay(ctx)
+	})
+}
+
+func (s *originProverSet) GetLatestFinalizedHeader() (core.Header, error) {
+	var header core.Header
+	err := s.call("GetLatestFinalizedHeader", func(prover core.Prover) error {
+		h, err := prover.GetLatestFinalizedHeader()
+		if err != nil {
+			return err
+		}
+		if err := s.checkFinalizedHeight(h.GetHeight()); err != nil {
+			return err
+		}
+		header = h
+		return nil
+	})
+	return header, err
+}
+
+func (s *originProverSet) CreateInitialLightClientState(height exported.Height) (exported.ClientState, exported.ConsensusState, error) {
+	var (
+		clientState    exported.ClientState
+		consensusState exported.ConsensusState
+	)
+	err := s.call("CreateInitialLightClientState", func(prover core.Prover) error {
+		cs, cons, err := prover.CreateInitialLightClientState(height)
+		if err != nil {
+			return err
+		}
+		if err := s.checkChainID(cs); err != nil {
+			return err
+		}
+		clientState, consensusState = cs, cons
+		return nil
+	})
+	return clientState, consensusState, err
+}
+
+func (s *originProverSet) SetupHeadersForUpdate(counterparty core.FinalityAwareChain, latestFinalizedHeader core.Header) ([]core.Header, error) {
+	var headers []core.Header
+	err := s.call("SetupHeadersForUpdate", func(prover core.Prover) error {
+		hs, err := prover.SetupHeadersForUpdate(counterparty, latestFinalizedHeader)
+		if err != nil {
+			return err
+		}
+		for _, h := range hs {
+			if h.GetHeight().GT(latestFinalizedHeader.GetHeight()) {
+				return fmt.Errorf("%w: the header is higher than the latest finalized header: latest_finalized_height=%v height=%v", errInconsistentOriginProver, latestFinalizedHeader.GetHeight(), h.GetHeight())
+			}
+		}
+		headers = hs
+		return nil
+	})
+	return headers, err
+}
+
+func (s *originProverSet) CheckRefreshRequired(counterparty core.ChainInfoICS02Querier) (bool, error) {
+	var required bool
+	err := s.call("CheckRefreshRequired", func(prover core.Prover) error {
+		r, err := prover.CheckRefreshRequired(counterparty)
+		required = r
+		return err
+	})
+	return required, err
+}
+
+func (s *originProverSet) ProveState(ctx core.QueryContext, path string, value []byte) ([]byte, clienttypes.Height, error) {
+	var (
+		proof       []byte
+		proofHeight clienttypes.Height
+	)
+	err := s.call("ProveState", func(prover core.Prover) error {
+		p, h, err := prover.ProveState(ctx, path, value)
+		proof, proofHeight = p, h
+		return err
+	})
+	return proof, proofHeight, err
+}
+
+func (s *originProverSet) ProveHostConsensusState(ctx core.QueryContext, height exported.Height, consensusState exported.ConsensusState) ([]byte, error) {
+	var proof []byte
+	err := s.call("ProveHostConsensusState", func(prover core.Prover) error {
+		p, err := prover.ProveHostConsensusState(ctx, height, consensusState)
+		proof = p
+		return err
+	})
+	return proof, err
+}
+
+// originProvers returns the set of the origin provers of the prover.
+// If the prover has a single origin prover, it returns a set of it.
+func (pr *Prover) originProvers() *originProverSet {
+	if s, ok := pr.originProver.(*originProverSet); ok {
+		return s
+	}
+	s := newOriginProverSet(pr.originChain, []core.Prover{pr.originProver})
+	s.metrics = pr.metrics
+	return s
+}
+
+// SupportsPastRevisions returns true if all the origin provers can create the initial light client states at the past revisions,
+// because the call may fail over to any of them
+func (s *originProverSet) SupportsPastRevisions() bool {
+	for _, prover := range s.provers {
+		if !supportsPastRevisions(prover) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,162 @@
+package relay
+
+import (
+	"errors"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	ibctm "github.com/cosmos/ibc-go/v8/modules/light-clients/07-tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFailoverOriginProver is an origin prover whose endpoint fails the calls for which `fail` returns true
+type mockFailoverOriginProver struct {
+	core.Prover
+	chainID      string
+	latestHeight clienttypes.Height
+	// fail decides whether the n-th call (0-origin) fails
+	fail  func(n int) bool
+	calls int
+}
+
+func (p *mockFailoverOriginProver) do() error {
+	n := p.calls
+	p.calls++
+	if p.fail != nil && p.fail(n) {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (p *mockFailoverOriginProver) GetLatestFinalizedHeader() (core.Header, error) {
+	if err := p.do(); err != nil {
+		return nil, err
+	}
+	return mockHeader{height: p.latestHeight}, nil
+}
+
+func (p *mockFailoverOriginProver) CreateInitialLightClientState(height exported.Height) (exported.ClientState, exported.ConsensusState, error) {
+	if err := p.do(); err != nil {
+		return nil, nil, err
+	}
+	return &ibctm.ClientState{ChainId: p.chainID, LatestHeight: p.latestHeight}, &ibctm.ConsensusState{}, nil
+}
+
+func (p *mockFailoverOriginProver) SetupHeadersForUpdate(counterparty core.FinalityAwareChain, latestFinalizedHeader core.Header) ([]core.Header, error) {
+	if err := p.do(); err != nil {
+		return nil, err
+	}
+	return newTestOriginHeaders(latestFinalizedHeader.GetHeight(), 2)
+}
+
+func TestOriginProverSetFailover(t *testing.T) {
+	require.NoError(t, log.InitLogger("DEBUG", "text", "stdout"))
+	require := require.New(t)
+	height := clienttypes.NewHeight(0, 10)
+	// the primary fails every other call
+	primary := &mockFailoverOriginProver{chainID: "origin", latestHeight: height, fail: func(n int) bool { return n%2 == 1 }}
+	fallback := &mockFailoverOriginProver{chainID: "origin", latestHeight: height}
+	s := newOriginProverSet(&mockCounterparty{chainID: "origin"}, []core.Prover{primary, fallback})
+
+	// the primary is tried first
+	header, err := s.GetLatestFinalizedHeader()
+	require.NoError(err)
+	require.Equal(height, header.GetHeight())
+	require.Equal(1, primary.calls)
+	require.Zero(fallback.calls)
+
+	// the primary fails and the fallback succeeds
+	headers, err := s.SetupHeadersForUpdate(nil, header)
+	require.NoError(err)
+	require.Len(headers, 2)
+	require.Equal(2, primary.calls)
+	require.Equal(1, fallback.calls)
+	statuses := s.Statuses()
+	require.False(statuses[0].Healthy)
+	require.Equal(uint64(1), statuses[0].ConsecutiveFailures)
+	require.Contains(statuses[0].LastError, "connection refused")
+	require.True(statuses[1].Healthy)
+	require.True(statuses[1].Preferred)
+
+	// the fallback succeeded most recently, so it is tried first
+	_, _, err = s.CreateInitialLightClientState(height)
+	require.NoError(err)
+	require.Equal(2, primary.calls)
+	require.Equal(2, fallback.calls)
+
+	// the primary is used again after the fallback fails
+	fallback.fail = func(n int) bool { return true }
+	_, err = s.GetLatestFinalizedHeader()
+	require.NoError(err)
+	require.Equal(3, primary.calls)
+	require.Equal(3, fallback.calls)
+	statuses = s.Statuses()
+	require.True(statuses[0].Preferred)
+	require.True(statuses[0].Healthy)
+	require.False(statuses[1].Healthy)
+
+	// all the provers fail
+	primary.fail = func(n int) bool { return true }
+	_, err = s.GetLatestFinalizedHeader()
+	require.ErrorContains(err, "all the origin provers failed")
+	require.ErrorContains(err, "connection refused")
+}
+
+func TestOriginProverSetConsistency(t *testing.T) {
+	require.NoError(t, log.InitLogger("DEBUG", "text", "stdout"))
+	t.Run("regressing height", func(t *testing.T) {
+		require := require.New(t)
+		primary := &mockFailoverOriginProver{chainID: "origin", latestHeight: clienttypes.NewHeight(0, 10)}
+		// the fallback lags behind the primary
+		fallback := &mockFailoverOriginProver{chainID: "origin", latestHeight: clienttypes.NewHeight(0, 8)}
+		s := newOriginProverSet(&mockCounterparty{chainID: "origin"}, []core.Prover{primary, fallback})
+
+		_, err := s.GetLatestFinalizedHeader()
+		require.NoError(err)
+		primary.fail = func(n int) bool { return true }
+		_, err = s.GetLatestFinalizedHeader()
+		require.ErrorIs(err, errInconsistentOriginProver)
+		require.ErrorContains(err, "the finalized height regressed")
+
+		// the fallback catches up
+		fallback.latestHeight = clienttypes.NewHeight(0, 11)
+		header, err := s.GetLatestFinalizedHeader()
+		require.NoError(err)
+		require.Equal(fallback.latestHeight, header.GetHeight())
+	})
+
+	t.Run("different chain ID", func(t *testing.T) {
+		require := require.New(t)
+		height := clienttypes.NewHeight(0, 10)
+		primary := &mockFailoverOriginProver{chainID: "other", latestHeight: height}
+		fallback := &mockFailoverOriginProver{chainID: "origin", latestHeight: height}
+		s := newOriginProverSet(&mockCounterparty{chainID: "origin"}, []core.Prover{primary, fallback})
+
+		cs, _, err := s.CreateInitialLightClientState(height)
+		require.NoError(err)
+		require.Equal("origin", cs.(*ibctm.ClientState).ChainId)
+		require.False(s.Statuses()[0].Healthy)
+		require.Contains(s.Statuses()[0].LastError, "unexpected chain ID")
+	})
+}
+
+func TestOriginProverSetProbe(t *testing.T) {
+	require.NoError(t, log.InitLogger("DEBUG", "text", "stdout"))
+	require := require.New(t)
+	height := clienttypes.NewHeight(0, 10)
+	primary := &mockFailoverOriginProver{chainID: "origin", latestHeight: height, fail: func(n int) bool { return true }}
+	fallback := &mockFailoverOriginProver{chainID: "origin", latestHeight: height}
+	s := newOriginProverSet(&mockCounterparty{chainID: "origin"}, []core.Prover{primary, fallback})
+
+	s.Probe()
+	statuses := s.Statuses()
+	require.Len(statuses, 2)
+	require.True(statuses[0].Primary)
+	require.False(statuses[0].Healthy)
+	require.True(statuses[1].Healthy)
+	// the probe does not change the prover tried first
+	require.True(statuses[0].Preferred)
+}
@@ -0,0 +1,68 @@
+package relay
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxCommitmentPathLength is the maximum length in bytes of a commitment path passed to the LCP service
+const MaxCommitmentPathLength = 1024
+
+// ErrInvalidCommitmentPath is returned if a commitment path cannot be proven by the ELC
+var ErrInvalidCommitmentPath = errors.New("invalid commitment path")
+
+// InvalidCommitmentPathError describes why a commitment path is invalid.
+// It matches ErrInvalidCommitmentPath with errors.Is.
+type InvalidCommitmentPathError struct {
+	Path string
+	// the index of the offending segment. It is -1 if the error is not specific to a segment.
+	SegmentIndex int
+	Segment      string
+	Reason       string
+}
+
+func (e *InvalidCommitmentPathError) Error() string {
+	if e.SegmentIndex < 0 {
+		return fmt.Sprintf("%v: %v: path=%q", ErrInvalidCommitmentPath, e.Reason, e.Path)
+	}
+	return fmt.Sprintf("%v: %v: segment_index=%v segment=%q path=%q", ErrInvalidCommitmentPath, e.Reason, e.SegmentIndex, e.Segment, e.Path)
+}
+
+func (e *InvalidCommitmentPathError) Is(target error) bool {
+	return target == ErrInvalidCommitmentPath
+}
+
+// canonicalCommitmentPath validates `path` and returns its canonical form, which is the form used by ibc-go's host package.
+// The leading and trailing slashes are removed, and the other bytes are kept as they are because they are a part of the store key.
+// The path must be valid UTF-8 and consist of non-empty segments of printable characters other than "." and "..".
+func canonicalCommitmentPath(path string) (string, error) {
+	// quote only the prefix of the path in the error as it may be very long
+	quoted := path
+	if len(quoted) > 64 {
+		quoted = quoted[:64] + "..."
+	}
+	if len(path) > MaxCommitmentPathLength {
+		return "", &InvalidCommitmentPathError{Path: quoted, SegmentIndex: -1, Reason: fmt.Sprintf("the length %v exceeds the maximum %v", len(path), MaxCommitmentPathLength)}
+	}
+	if !utf8.ValidString(path) {
+		return "", &InvalidCommitmentPathError{Path: quoted, SegmentIndex: -1, Reason: "not valid UTF-8"}
+	}
+	canonical := strings.Trim(path, "/")
+	if canonical == "" {
+		return "", &InvalidCommitmentPathError{Path: quoted, SegmentIndex: -1, Reason: "empty path"}
+	}
+	for i, segment := range strings.Split(canonical, "/") {
+		switch {
+		case segment == "":
+			return "", &InvalidCommitmentPathError{Path: quoted, SegmentIndex: i, Segment: segment, Reason: "empty segment"}
+		case segment == "." || segment == "..":
+			return "", &InvalidCommitmentPathError{Path: quoted, SegmentIndex: i, Segment: segment, Reason: "relative segment"}
+		case strings.IndexFunc(segment, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0:
+			return "", &InvalidCommitmentPathError{Path: quoted, SegmentIndex: i, Segment: segment, Reason: "non-printable character"}
+		}
+	}
+	return canonical, nil
+}
@@ -0,0 +1,82 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalCommitmentPath(t *testing.T) {
+	var cases = []struct {
+		name      string
+		path      string
+		canonical string
+		// expected substring of the error. if empty, the path is valid
+		err string
+	}{
+		{"client state", host.FullClientStatePath("07-tendermint-0"), "clients/07-tendermint-0/clientState", ""},
+		{"packet commitment", host.PacketCommitmentPath("transfer", "channel-0", 1), "commitments/ports/transfer/channels/channel-0/sequences/1", ""},
+		{"leading and trailing slashes", "/connections/connection-0/", "connections/connection-0", ""},
+		{"non-ASCII segment", "custom/ポート/1", "custom/ポート/1", ""},
+		{"maximum length", strings.Repeat("a", MaxCommitmentPathLength), strings.Repeat("a", MaxCommitmentPathLength), ""},
+		{"too long", strings.Repeat("a", MaxCommitmentPathLength+1), "", "exceeds the maximum"},
+		{"invalid UTF-8", "custom/\xff/1", "", "not valid UTF-8"},
+		{"empty", "", "", "empty path"},
+		{"only slashes", "//", "", "empty path"},
+		{"empty segment", "clients//clientState", "", "empty segment: segment_index=1"},
+		{"relative segment", "clients/../clientState", "", `relative segment: segment_index=1 segment=".."`},
+		{"control character", "clients/07-tendermint-0\n/clientState", "", "non-printable character: segment_index=1"},
+		{"NUL", "clients/\x00", "", "non-printable character: segment_index=1"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			canonical, err := canonicalCommitmentPath(c.path)
+			if c.err != "" {
+				require.ErrorIs(t, err, ErrInvalidCommitmentPath)
+				require.ErrorContains(t, err, c.err)
+				var pathErr *InvalidCommitmentPathError
+				require.True(t, errors.As(err, &pathErr))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.canonical, canonical)
+		})
+	}
+}
+
+func TestProveStateRejectsInvalidPath(t *testing.T) {
+	pr := newTestProver(t)
+	// the origin prover and the LCP service must not be called
+	_, _, err := pr.ProveState(core.NewQueryContext(context.TODO(), clienttypes.NewHeight(0, 1)), "clients//clientState", []byte("value"))
+	require.ErrorIs(t, err, ErrInvalidCommitmentPath)
+}
+
+func FuzzCanonicalCommitmentPath(f *testing.F) {
+	f.Add(host.FullClientStatePath("07-tendermint-0"))
+	f.Add("/connections/connection-0/")
+	f.Add("custom/ポート/1")
+	f.Add("clients/../clientState")
+	f.Add("custom/\xff")
+	f.Add("")
+	f.Fuzz(func(t *testing.T, path string) {
+		canonical, err := canonicalCommitmentPath(path)
+		if err != nil {
+			require.ErrorIs(t, err, ErrInvalidCommitmentPath)
+			return
+		}
+		require.True(t, utf8.ValidString(canonical))
+		require.LessOrEqual(t, len(canonical), MaxCommitmentPathLength)
+		require.False(t, strings.HasPrefix(canonical, "/") || strings.HasSuffix(canonical, "/"))
+		// the canonicalization is stable
+		again, err := canonicalCommitmentPath(canonical)
+		require.NoError(t, err)
+		require.Equal(t, canonical, again)
+	})
+}
@@ -0,0 +1,278 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+)
+
+const policyDriftFile = "attestation_policy_drift"
+
+// ErrAttestationPolicyDrift is returned by ProveState while a drift of the attestation policy is not acknowledged
+var ErrAttestationPolicyDrift = errors.New("proof generation is halted by a drift of the attestation policy")
+
+// AttestationPolicyDriftError describes the drift halting the proof generation.
+// It matches ErrAttestationPolicyDrift with errors.Is.
+type AttestationPolicyDriftError struct {
+	Drift *PolicyDrift
+}
+
+func (e *AttestationPolicyDriftError) Error() string {
+	return fmt.Sprintf("%v: active_enclave_key=%v observed_enclave_key=%v baseline=%v observed=%v detected_at=%v: acknowledge it with the ack-policy-drift command",
+		ErrAttestationPolicyDrift, e.Drift.ActiveEnclaveKey, e.Drift.ObservedEnclaveKey, e.Drift.Baseline, e.Drift.Observed, e.Drift.DetectedAt)
+}
+
+func (e *AttestationPolicyDriftError) Is(target error) bool {
+	return target == ErrAttestationPolicyDrift
+}
+
+// AttestationPolicy is the quote status and the advisory IDs of an attestation
+type AttestationPolicy struct {
+	QuoteStatus string `json:"quote_status"`
+	// sorted in ascending order
+	AdvisoryIDs []string `json:"advisory_ids"`
+}
+
+func newAttestationPolicy(att *attestation) AttestationPolicy {
+	return AttestationPolicy{QuoteStatus: att.QuoteStatus, AdvisoryIDs: att.AdvisoryIDs}
+}
+
+func (p AttestationPolicy) String() string {
+	return fmt.Sprintf("{quote_status=%v advisory_ids=%v}", p.QuoteStatus, p.AdvisoryIDs)
+}
+
+func (p AttestationPolicy) Equal(other AttestationPolicy) bool {
+	return p.QuoteStatus == other.QuoteStatus && slices.Equal(p.AdvisoryIDs, other.AdvisoryIDs)
+}
+
+// newAdvisoryIDs returns the advisory IDs of `p` which `baseline` does not have
+func (p AttestationPolicy) newAdvisoryIDs(baseline AttestationPolicy) []string {
+	var ids []string
+	for _, id := range p.AdvisoryIDs {
+		if !slices.Contains(baseline.AdvisoryIDs, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// driftsFrom returns true if the quote status of `p` differs from the one of `baseline` or `p` has a new advisory ID.
+// The advisory IDs resolved since the baseline are not a drift.
+func (p AttestationPolicy) driftsFrom(baseline AttestationPolicy) bool {
+	return p.QuoteStatus != baseline.QuoteStatus || len(p.newAdvisoryIDs(baseline)) > 0
+}
+
+// PolicyDrift is a drift of the attestation policy of a fresh attestation from the one of the active enclave key.
+// It is persisted in the prover home so that the acknowledgement survives restarts.
+type PolicyDrift struct {
+	// the active enclave key whose attestation is the baseline
+	ActiveEnclaveKey lcptypes.HexBytes `json:"active_enclave_key"`
+	Baseline         AttestationPolicy `json:"baseline"`
+	// the enclave key of the fresh attestation
+	ObservedEnclaveKey lcptypes.HexBytes `json:"observed_enclave_key"`
+	Observed           AttestationPolicy `json:"observed"`
+	NewAdvisoryIDs     []string          `json:"new_advisory_ids,omitempty"`
+	DetectedAt         time.Time         `json:"detected_at"`
+	// nil if the drift is not acknowledged yet
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+}
+
+func (d *PolicyDrift) Acknowledged() bool {
+	return d.AcknowledgedAt != nil
+}
+
+func loadPolicyDrift(path string) (*PolicyDrift, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read file: path=%v %w", path, err)
+	}
+	var drift PolicyDrift
+	if err := json.Unmarshal(bz, &drift); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the attestation policy drift: path=%v %w", path, err)
+	}
+	return &drift, nil
+}
+
+func savePolicyDrift(path string, drift *PolicyDrift) error {
+	bz, err := json.Marshal(drift)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the attestation policy drift: %w", err)
+	}
+	if err := os.WriteFile(path, bz, 0600); err != nil {
+		return fmt.Errorf("failed to write the attestation policy drift: path=%v %w", path, err)
+	}
+	return nil
+}
+
+// policyWatchState is the state of the attestation policy watch of the prover
+type policyWatchState struct {
+	// the time of the last sampling. zero if the attestations have never been sampled
+	lastChecked time.Time
+	// the drift loaded from or saved to the file
+	drift  *PolicyDrift
+	loaded bool
+}
+
+func (pr *Prover) policyDriftFilePath() string {
+	return filepath.Join(pr.dbPath(), policyDriftFile)
+}
+
+// loadPolicyDrift returns the persisted drift of the active enclave key.
+// The drift of a previous active key is ignored because the baseline changes with the key.
+func (pr *Prover) loadPolicyDrift(reload bool) (*PolicyDrift, error) {
+	if !pr.policyWatch.loaded || reload {
+		drift, err := loadPolicyDrift(pr.policyDriftFilePath())
+		if err != nil {
+			return nil, err
+		}
+		pr.policyWatch.drift, pr.policyWatch.loaded = drift, true
+	}
+	drift := pr.policyWatch.drift
+	if drift == nil || pr.activeEnclaveKey == nil || !bytes.Equal(drift.ActiveEnclaveKey, pr.activeEnclaveKey.EnclaveKeyAddress) {
+		return nil, nil
+	}
+	return drift, nil
+}
+
+// watchAttestationPolicy samples the fresh attestations of the enclave keys in the LCP service if the check interval has elapsed since the last sampling,
+// and records a drift from the attestation of the active enclave key.
+// A failure of the sampling is only logged because the registered key remains valid on the counterparty chain.
+func (pr *Prover) watchAttestationPolicy(ctx context.Context, now time.Time) {
+	interval := time.Duration(pr.config.AttestationPolicyCheckInterval) * time.Second
+	if interval == 0 || pr.activeEnclaveKey == nil || pr.IsRehearsal() {
+		return
+	} else if !pr.policyWatch.lastChecked.IsZero() && now.Sub(pr.policyWatch.lastChecked) < interval {
+		return
+	}
+	pr.policyWatch.lastChecked = now
+	if err := pr.checkAttestationPolicy(ctx, now); err != nil {
+		pr.getLogger().Warn("failed to check the attestation policy", "error", err)
+	}
+}
+
+// checkAttestationPolicy compares the fresh attestations with the one of the active enclave key and records a new drift
+func (pr *Prover) checkAttestationPolicy(ctx context.Context, now time.Time) error {
+	active, err := pr.verifyAttestationAtAttestationTime(pr.activeEnclaveKey)
+	if err != nil {
+		return fmt.Errorf("failed to parse the attestation of the active enclave key: %w", err)
+	}
+	res, err := pr.lcpServiceClient.AvailableEnclaveKeys(ctx, &enclave.QueryAvailableEnclaveKeysRequest{Mrenclave: pr.config.GetMrenclave()})
+	if err != nil {
+		return fmt.Errorf("failed to query the available enclave keys: %w", err)
+	}
+	drift := detectPolicyDrift(pr.activeEnclaveKey, newAttestationPolicy(active), pr.sampleAttestations(res.Keys, now), now)
+	if drift == nil {
+		return nil
+	}
+	return pr.recordPolicyDrift(drift)
+}
+
+// sampledAttestation is the attestation policy of an enclave key in the LCP service
+type sampledAttestation struct {
+	enclaveKey      []byte
+	attestationTime uint64
+	policy          AttestationPolicy
+}
+
+// sampleAttestations returns the policies of the attestations of `keys` which are verified
+func (pr *Prover) sampleAttestations(keys []*enclave.EnclaveKeyInfo, now time.Time) []sampledAttestation {
+	var attestations []sampledAttestation
+	for _, eki := range keys {
+		att, err := pr.verifyAttestation(eki, now)
+		if err != nil {
+			pr.getLogger().Warn("skip the attestation which cannot be verified", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "error", err)
+			continue
+		}
+		attestations = append(attestations, sampledAttestation{enclaveKey: eki.EnclaveKeyAddress, attestationTime: eki.AttestationTime, policy: newAttestationPolicy(att)})
+	}
+	return attestations
+}
+
+// detectPolicyDrift returns the drift of the first attestation newer than the one of `active` from `baseline`, or nil if no attestation drifts.
+// The older attestations are ignored because the policy of `active` supersedes them.
+func detectPolicyDrift(active *enclave.EnclaveKeyInfo, baseline AttestationPolicy, attestations []sampledAttestation, now time.Time) *PolicyDrift {
+	for _, a := range attestations {
+		if a.attestationTime <= active.AttestationTime || !a.policy.driftsFrom(baseline) {
+			continue
+		}
+		return &PolicyDrift{
+			ActiveEnclaveKey:   active.EnclaveKeyAddress,
+			Baseline:           baseline,
+			ObservedEnclaveKey: a.enclaveKey,
+			Observed:           a.policy,
+			NewAdvisoryIDs:     a.policy.newAdvisoryIDs(baseline),
+			DetectedAt:         now,
+		}
+	}
+	return nil
+}
+
+// recordPolicyDrift persists the drift and emits an alert unless the drift to the same policy has already been recorded
+func (pr *Prover) recordPolicyDrift(drift *PolicyDrift) error {
+	current, err := pr.loadPolicyDrift(true)
+	if err != nil {
+		return err
+	} else if current != nil && current.Observed.Equal(drift.Observed) {
+		return nil
+	}
+	if err := savePolicyDrift(pr.policyDriftFilePath(), drift); err != nil {
+		return err
+	}
+	pr.policyWatch.drift = drift
+	details := []interface{}{"baseline", drift.Baseline, "observed", drift.Observed, "observed_enclave_key", drift.ObservedEnclaveKey, "new_advisory_ids", drift.NewAdvisoryIDs}
+	if pr.config.HaltOnAttestationPolicyDrift {
+		details = append(details, "halted", true)
+	}
+	pr.alert(AlertAttestationPolicyDrift, drift.ActiveEnclaveKey.String(), "the attestation policy of the enclave drifted from the one of the active enclave key", details...)
+	return nil
+}
+
+// checkPolicyDriftHalt returns an AttestationPolicyDriftError if the proof generation is halted by an unacknowledged drift.
+// The drift is reloaded from the file so that the acknowledgement by the command takes effect without a restart.
+func (pr *Prover) checkPolicyDriftHalt() error {
+	if !pr.config.HaltOnAttestationPolicyDrift {
+		return nil
+	}
+	drift, err := pr.loadPolicyDrift(false)
+	if err != nil {
+		return err
+	} else if drift == nil || drift.Acknowledged() {
+		return nil
+	}
+	if drift, err = pr.loadPolicyDrift(true); err != nil {
+		return err
+	} else if drift == nil || drift.Acknowledged() {
+		return nil
+	}
+	return &AttestationPolicyDriftError{Drift: drift}
+}
+
+// doAcknowledgePolicyDrift acknowledges the persisted drift so that the proof generation resumes
+func (pr *Prover) doAcknowledgePolicyDrift(now time.Time) (*PolicyDrift, error) {
+	path := pr.policyDriftFilePath()
+	drift, err := loadPolicyDrift(path)
+	if err != nil {
+		return nil, err
+	} else if drift == nil {
+		return nil, fmt.Errorf("no attestation policy drift is recorded: path=%v", path)
+	} else if drift.Acknowledged() {
+		return drift, nil
+	}
+	drift.AcknowledgedAt = &now
+	if err := savePolicyDrift(path, drift); err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
@@ -0,0 +1,139 @@
+package relay
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttestationPolicyDriftsFrom(t *testing.T) {
+	baseline := AttestationPolicy{QuoteStatus: "GROUP_OUT_OF_DATE", AdvisoryIDs: []string{"INTEL-SA-00219", "INTEL-SA-00289"}}
+	var cases = []struct {
+		name     string
+		observed AttestationPolicy
+		drift    bool
+	}{
+		{"same", baseline, false},
+		{"advisory resolved", AttestationPolicy{QuoteStatus: "GROUP_OUT_OF_DATE", AdvisoryIDs: []string{"INTEL-SA-00219"}}, false},
+		{"new advisory", AttestationPolicy{QuoteStatus: "GROUP_OUT_OF_DATE", AdvisoryIDs: []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-01000"}}, true},
+		{"quote status changed", AttestationPolicy{QuoteStatus: "CONFIGURATION_NEEDED", AdvisoryIDs: baseline.AdvisoryIDs}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.drift, c.observed.driftsFrom(baseline))
+		})
+	}
+}
+
+func TestPolicyDriftHaltAndAcknowledge(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	require := require.New(t)
+	homePath := t.TempDir()
+	eki := loadTestEnclaveKeyInfo(t)
+	newProver := func() *Prover {
+		pr := newTestProver(t)
+		pr.homePath = homePath
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.AttestationPolicyCheckInterval = 60
+		pr.config.HaltOnAttestationPolicyDrift = true
+		pr.activeEnclaveKey = eki
+		pr.originProver = mockOriginProver{}
+		pr.lcpServiceClient.EnclaveQueryClient = mockEnclaveQueryClient{availableKeys: []*enclave.EnclaveKeyInfo{eki}}
+		require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr
+	}
+	pr := newProver()
+	baseline := AttestationPolicy{QuoteStatus: "GROUP_OUT_OF_DATE", AdvisoryIDs: []string{"INTEL-SA-00219"}}
+	newAttestation := func(advisoryIDs ...string) sampledAttestation {
+		return sampledAttestation{
+			enclaveKey:      common.HexToAddress("0x01").Bytes(),
+			attestationTime: eki.AttestationTime + 1,
+			policy:          AttestationPolicy{QuoteStatus: "GROUP_OUT_OF_DATE", AdvisoryIDs: advisoryIDs},
+		}
+	}
+
+	// the attestations not newer than the active key are ignored
+	older := newAttestation("INTEL-SA-00219", "INTEL-SA-01000")
+	older.attestationTime = eki.AttestationTime
+	require.Nil(detectPolicyDrift(eki, baseline, []sampledAttestation{older, newAttestation("INTEL-SA-00219")}, time.Now()))
+
+	drift := detectPolicyDrift(eki, baseline, []sampledAttestation{newAttestation("INTEL-SA-00219", "INTEL-SA-01000")}, time.Now())
+	require.NotNil(drift)
+	require.Equal([]string{"INTEL-SA-01000"}, drift.NewAdvisoryIDs)
+	require.NoError(pr.recordPolicyDrift(drift))
+
+	// the proof generation is halted
+	require.ErrorIs(pr.checkPolicyDriftHalt(), ErrAttestationPolicyDrift)
+	_, _, err := pr.ProveState(core.NewQueryContext(context.TODO(), clienttypes.NewHeight(0, 1)), "clients/07-tendermint-0/clientState", []byte("value"))
+	require.ErrorIs(err, ErrAttestationPolicyDrift)
+	require.ErrorContains(err, "ack-policy-drift")
+
+	// the drift survives a restart
+	restarted := newProver()
+	require.ErrorIs(restarted.checkPolicyDriftHalt(), ErrAttestationPolicyDrift)
+
+	// the acknowledgement by the command resumes the running prover
+	acked, err := restarted.doAcknowledgePolicyDrift(time.Now())
+	require.NoError(err)
+	require.True(acked.Acknowledged())
+	require.NoError(pr.checkPolicyDriftHalt())
+
+	// the same drift does not halt again, but a further drift does
+	require.NoError(pr.recordPolicyDrift(detectPolicyDrift(eki, baseline, []sampledAttestation{newAttestation("INTEL-SA-00219", "INTEL-SA-01000")}, time.Now())))
+	require.NoError(pr.checkPolicyDriftHalt())
+	require.NoError(pr.recordPolicyDrift(detectPolicyDrift(eki, baseline, []sampledAttestation{newAttestation("INTEL-SA-00219", "INTEL-SA-01000", "INTEL-SA-01001")}, time.Now())))
+	require.ErrorIs(pr.checkPolicyDriftHalt(), ErrAttestationPolicyDrift)
+
+	// the drift is not halting if it is disabled
+	pr.config.HaltOnAttestationPolicyDrift = false
+	require.NoError(pr.checkPolicyDriftHalt())
+	pr.config.HaltOnAttestationPolicyDrift = true
+
+	// the drift of the previous active key is ignored
+	rotated := *eki
+	rotated.EnclaveKeyAddress = common.HexToAddress("0x02").Bytes()
+	pr.activeEnclaveKey = &rotated
+	require.NoError(pr.checkPolicyDriftHalt())
+}
+
+func TestWatchAttestationPolicyInterval(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	require := require.New(t)
+	eki := loadTestEnclaveKeyInfo(t)
+	fresh := *eki
+	fresh.AttestationTime++
+	pr := newTestProver(t)
+	pr.homePath = t.TempDir()
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	pr.activeEnclaveKey = eki
+	pr.lcpServiceClient.EnclaveQueryClient = mockEnclaveQueryClient{availableKeys: []*enclave.EnclaveKeyInfo{eki, &fresh}}
+	require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+
+	// disabled
+	now := time.Now()
+	pr.watchAttestationPolicy(context.TODO(), now)
+	require.True(pr.policyWatch.lastChecked.IsZero())
+
+	pr.config.AttestationPolicyCheckInterval = 60
+	pr.watchAttestationPolicy(context.TODO(), now)
+	require.Equal(now, pr.policyWatch.lastChecked)
+	pr.watchAttestationPolicy(context.TODO(), now.Add(59*time.Second))
+	require.Equal(now, pr.policyWatch.lastChecked)
+	pr.watchAttestationPolicy(context.TODO(), now.Add(time.Minute))
+	require.Equal(now.Add(time.Minute), pr.policyWatch.lastChecked)
+
+	// the fresh attestation has the same policy as the active key
+	drift, err := pr.loadPolicyDrift(true)
+	require.NoError(err)
+	require.Nil(drift)
+}
@@ -0,0 +1,145 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// ValidationContextPredictionError is returned if an update is predicted to be rejected by its validation context on the counterparty chain.
+// The update is not submitted, and it is generated again from a fresher origin header by the next attempt.
+type ValidationContextPredictionError struct {
+	ChainID            string
+	Height             clienttypes.Height
+	EstimatedBlockTime time.Time
+	Err                error
+}
+
+func (e *ValidationContextPredictionError) Error() string {
+	return fmt.Sprintf("the update is predicted to be rejected by the validation context: chain_id=%v height=%v estimated_block_time=%v %v", e.ChainID, e.Height, e.EstimatedBlockTime, e.Err)
+}
+
+func (e *ValidationContextPredictionError) Unwrap() error {
+	return e.Err
+}
+
+// validationContextPrediction is the prediction that the updates pass the validation on the counterparty chain.
+// It is compared with the actual outcome after the next block of the counterparty chain.
+type validationContextPrediction struct {
+	// the latest height of the counterparty chain at the estimation
+	height             clienttypes.Height
+	estimatedBlockTime time.Time
+	// the highest post height of the predicted updates
+	postHeight clienttypes.Height
+}
+
+// estimateCounterpartyBlockTime returns the latest height of the counterparty chain and the estimated time of the next block,
+// which is the timestamp of the latest block plus the average block time.
+func estimateCounterpartyBlockTime(counterparty core.Chain) (clienttypes.Height, time.Time, error) {
+	height, err := counterparty.LatestHeight()
+	if err != nil {
+		return clienttypes.Height{}, time.Time{}, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	timestamp, err := counterparty.Timestamp(height)
+	if err != nil {
+		return clienttypes.Height{}, time.Time{}, fmt.Errorf("failed to get the block time of the counterparty chain: height=%v %w", height, err)
+	}
+	return clienttypes.NewHeight(height.GetRevisionNumber(), height.GetRevisionHeight()), timestamp.Add(counterparty.AverageBlockTime()), nil
+}
+
+// checkValidationContexts returns ValidationContextPredictionError if any of `msgs` is predicted to be rejected by its validation context on the counterparty chain.
+// The contexts are evaluated at the estimated next block time of the counterparty chain and at the time after the safety margin.
+// It does nothing unless the preflight is enabled in the config.
+func (pr *Prover) checkValidationContexts(ctx context.Context, counterparty core.FinalityAwareChain, msgs []*lcptypes.UpdateStateProxyMessage) error {
+	if !pr.config.ValidationContextPreflight || len(msgs) == 0 {
+		return nil
+	}
+	pr.reportValidationContextPrediction(ctx, counterparty)
+	height, estimated, err := estimateCounterpartyBlockTime(counterparty)
+	if err != nil {
+		// the submission fails anyway if the counterparty chain is unreachable
+		pr.getLogger().Warn("skip the preflight of the validation contexts", "error", err)
+		return nil
+	}
+	margin := pr.config.GetValidationContextSafetyMargin()
+	for _, msg := range msgs {
+		// the validation fails either after the end of the trusting period or before the header timestamp,
+		// so the update passes at any time between the two if it passes at both
+		for _, t := range []time.Time{estimated, estimated.Add(margin)} {
+			if err := msg.Context.Validate(t); err != nil {
+				pr.getLogger().Warn("the update is predicted to be rejected by the validation context, so it is not submitted",
+					"post_height", msg.PostHeight, "counterparty_height", height, "estimated_block_time", estimated, "safety_margin", margin, "error", err)
+				return &ValidationContextPredictionError{
+					ChainID:            counterparty.ChainID(),
+					Height:             msg.PostHeight,
+					EstimatedBlockTime: estimated,
+					Err:                err,
+				}
+			}
+		}
+	}
+	prediction := &validationContextPrediction{height: height, estimatedBlockTime: estimated}
+	for _, msg := range msgs {
+		if msg.PostHeight.GT(prediction.postHeight) {
+			prediction.postHeight = msg.PostHeight
+		}
+	}
+	pr.validationContextPrediction = prediction
+	return nil
+}
+
+// reportValidationContextPrediction logs the outcome of the last prediction once the counterparty chain has produced the next block.
+// The difference between the estimated and the actual block time helps to tune the safety margin.
+func (pr *Prover) reportValidationContextPrediction(ctx context.Context, counterparty core.FinalityAwareChain) {
+	p := pr.validationContextPrediction
+	if p == nil {
+		return
+	}
+	latest, err := counterparty.LatestHeight()
+	if err != nil || !latest.GT(p.height) {
+		return
+	}
+	pr.validationContextPrediction = nil
+	logger := pr.getLogger()
+	actual, err := counterparty.Timestamp(p.height.Increment())
+	if err != nil {
+		logger.Warn("failed to get the block time to report the outcome of the validation context prediction", "height", p.height.Increment(), "error", err)
+		return
+	}
+	clientHeight, err := pr.queryCounterpartyClientHeight(ctx, counterparty)
+	if err != nil {
+		logger.Warn("failed to query the LCP client to report the outcome of the validation context prediction", "error", err)
+		return
+	}
+	logger.Info("the outcome of the validation context prediction",
+		"predicted", "pass",
+		"applied", clientHeight.GTE(p.postHeight),
+		"post_height", p.postHeight,
+		"estimated_block_time", p.estimatedBlockTime,
+		"actual_block_time", actual,
+		"estimation_error", actual.Sub(p.estimatedBlockTime),
+	)
+}
+
+// checkActivateClientValidationContexts checks the validation contexts of the updates generated to activate the client.
+// The messages that cannot be decoded are left to the later validation.
+func (pr *Prover) checkActivateClientValidationContexts(ctx context.Context, counterparty core.FinalityAwareChain, responses []*elc.MsgUpdateClientResponse) error {
+	var msgs []*lcptypes.UpdateStateProxyMessage
+	for _, res := range responses {
+		msg, err := lcptypes.EthABIDecodeHeaderedProxyMessage(res.Message)
+		if err != nil {
+			return nil
+		}
+		usm, err := msg.GetUpdateStateProxyMessage()
+		if err != nil {
+			return nil
+		}
+		msgs = append(msgs, usm)
+	}
+	return pr.checkValidationContexts(ctx, counterparty, msgs)
+}
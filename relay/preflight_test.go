@@ -0,0 +1,107 @@
+package relay
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newTrustingPeriodUpdate(postHeight uint64, untrustedHeaderTimestamp, trustingPeriodEnd time.Time, clockDrift time.Duration) *lcptypes.UpdateStateProxyMessage {
+	trustedStateTimestamp := trustingPeriodEnd.Add(-time.Hour)
+	return &lcptypes.UpdateStateProxyMessage{
+		PostHeight: clienttypes.NewHeight(0, postHeight),
+		Context: &lcptypes.TrustingPeriodValidationContext{
+			UntrustedHeaderTimestamp: untrustedHeaderTimestamp,
+			TrustedStateTimestamp:    trustedStateTimestamp,
+			TrustingPeriod:           *big.NewInt(int64(time.Hour)),
+			ClockDrift:               *big.NewInt(int64(clockDrift)),
+		},
+	}
+}
+
+func TestCheckValidationContexts(t *testing.T) {
+	latestBlockTime := time.Unix(1700000000, 0)
+	const averageBlockTime = 5 * time.Second
+	const margin = 10 * time.Second
+	estimated := latestBlockTime.Add(averageBlockTime)
+	header := latestBlockTime.Add(-time.Minute)
+
+	var cases = []struct {
+		name      string
+		preflight bool
+		update    *lcptypes.UpdateStateProxyMessage
+		rejected  bool
+	}{
+		{"trusting period ends after the margin", true, newTrustingPeriodUpdate(11, header, estimated.Add(margin+time.Second), 0), false},
+		{"trusting period ends at the end of the margin", true, newTrustingPeriodUpdate(11, header, estimated.Add(margin), 0), false},
+		{"trusting period ends within the margin", true, newTrustingPeriodUpdate(11, header, estimated.Add(margin-time.Second), 0), true},
+		{"trusting period ends before the estimated block time", true, newTrustingPeriodUpdate(11, header, estimated.Add(-time.Second), 0), true},
+		{"header within the clock drift", true, newTrustingPeriodUpdate(11, estimated.Add(3*time.Second), estimated.Add(time.Hour), 3*time.Second), false},
+		{"header after the clock drift", true, newTrustingPeriodUpdate(11, estimated.Add(3*time.Second+1), estimated.Add(time.Hour), 3*time.Second), true},
+		{"preflight disabled", false, newTrustingPeriodUpdate(11, header, estimated.Add(-time.Second), 0), false},
+		{"empty context", true, &lcptypes.UpdateStateProxyMessage{PostHeight: clienttypes.NewHeight(0, 11), Context: lcptypes.EmptyValidationContext{}}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			pr.config.ValidationContextPreflight = c.preflight
+			pr.config.ValidationContextSafetyMargin = uint64(margin / time.Second)
+			cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+			cp.latestHeight = clienttypes.NewHeight(0, 10)
+			cp.blockTime = latestBlockTime
+			cp.averageBlockTime = averageBlockTime
+
+			err := pr.checkValidationContexts(context.TODO(), cp, []*lcptypes.UpdateStateProxyMessage{c.update})
+			if c.rejected {
+				var perr *ValidationContextPredictionError
+				require.ErrorAs(err, &perr)
+				require.Equal(clienttypes.NewHeight(0, 11), perr.Height)
+				require.True(estimated.Equal(perr.EstimatedBlockTime))
+				require.Nil(pr.validationContextPrediction)
+			} else {
+				require.NoError(err)
+				require.Equal(c.preflight, pr.validationContextPrediction != nil)
+			}
+		})
+	}
+}
+
+func TestReportValidationContextPrediction(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.config.ValidationContextPreflight = true
+	latestBlockTime := time.Unix(1700000000, 0)
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+	cp.latestHeight = clienttypes.NewHeight(0, 10)
+	cp.blockTime = latestBlockTime
+	cp.averageBlockTime = 5 * time.Second
+	cp.clientState = &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, 1)}
+
+	updates := []*lcptypes.UpdateStateProxyMessage{
+		newTrustingPeriodUpdate(3, latestBlockTime, latestBlockTime.Add(time.Hour), 0),
+		newTrustingPeriodUpdate(5, latestBlockTime, latestBlockTime.Add(time.Hour), 0),
+	}
+	require.NoError(pr.checkValidationContexts(context.TODO(), cp, updates))
+	require.Equal(&validationContextPrediction{
+		height:             clienttypes.NewHeight(0, 10),
+		estimatedBlockTime: latestBlockTime.Add(5 * time.Second),
+		postHeight:         clienttypes.NewHeight(0, 5),
+	}, pr.validationContextPrediction)
+
+	// the prediction is kept until the next block
+	pr.reportValidationContextPrediction(context.TODO(), cp)
+	require.NotNil(pr.validationContextPrediction)
+
+	cp.latestHeight = clienttypes.NewHeight(0, 11)
+	cp.blockTimes = map[uint64]time.Time{11: latestBlockTime.Add(7 * time.Second)}
+	cp.clientState.LatestHeight = clienttypes.NewHeight(0, 5)
+	pr.reportValidationContextPrediction(context.TODO(), cp)
+	require.Nil(pr.validationContextPrediction)
+}
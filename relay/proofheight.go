@@ -0,0 +1,103 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// tendermintClientStateTypeURL is the type URL of the origin client state of a tendermint chain.
+// A state of a tendermint chain at height H is proven with the app hash committed in the block at H+1.
+const tendermintClientStateTypeURL = "/ibc.lightclients.tendermint.v1.ClientState"
+
+// PinnedProver generates commitment proofs which are all verified by the ELC at the same height.
+// It is used to prove multiple paths consistently even if the origin chain advances in the middle of the batch.
+type PinnedProver struct {
+	pr          *Prover
+	elcClientID string
+	// the height of the proofs verified by the ELC
+	proofHeight clienttypes.Height
+	// the height of the origin chain to query the states at
+	queryHeight clienttypes.Height
+}
+
+// StateToProve is a pair of a path and its value to be proven by PinnedProver
+type StateToProve struct {
+	Path  string
+	Value []byte
+}
+
+// WithProofHeight returns a PinnedProver which generates the proofs at `height`.
+// The height must have been verified by the ELC, i.e. it must not be greater than the latest height of the ELC client.
+// Note that the ELC only exposes its latest client state, so a consensus state pruned from the ELC cannot be detected here.
+func (pr *Prover) WithProofHeight(ctx context.Context, height exported.Height) (*PinnedProver, error) {
+	proofHeight, ok := height.(clienttypes.Height)
+	if !ok {
+		return nil, fmt.Errorf("unexpected height type: %T", height)
+	} else if proofHeight.IsZero() {
+		return nil, fmt.Errorf("the pinned height must not be zero")
+	}
+	elcClientID := pr.GetELCClientID()
+	res, err := pr.lcpServiceClient.Client(ctx, &elc.QueryClientRequest{ClientId: elcClientID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ELC's client: elc_client_id=%v pinned_height=%v %w", elcClientID, proofHeight, err)
+	} else if !res.Found {
+		return nil, fmt.Errorf("ELC's client not found: elc_client_id=%v pinned_height=%v", elcClientID, proofHeight)
+	}
+	var clientState exported.ClientState
+	if err := pr.codec.UnpackAny(res.ClientState, &clientState); err != nil {
+		return nil, fmt.Errorf("failed to unpack ELC's client state: elc_client_id=%v %w", elcClientID, err)
+	}
+	latestHeight := clientState.GetLatestHeight()
+	if proofHeight.GetRevisionNumber() != latestHeight.GetRevisionNumber() || proofHeight.GT(latestHeight) {
+		return nil, fmt.Errorf("the pinned height is not verified by the ELC: elc_client_id=%v pinned_height=%v latest_height=%v", elcClientID, proofHeight, latestHeight)
+	}
+	queryHeight := proofHeight
+	if res.ClientState.TypeUrl == tendermintClientStateTypeURL {
+		if proofHeight.RevisionHeight <= 1 {
+			return nil, fmt.Errorf("the pinned height has no state to prove: elc_client_id=%v pinned_height=%v", elcClientID, proofHeight)
+		}
+		queryHeight = clienttypes.NewHeight(proofHeight.RevisionNumber, proofHeight.RevisionHeight-1)
+	}
+	return &PinnedProver{
+		pr:          pr,
+		elcClientID: elcClientID,
+		proofHeight: proofHeight,
+		queryHeight: queryHeight,
+	}, nil
+}
+
+// ProofHeight returns the pinned height of the proofs
+func (pp *PinnedProver) ProofHeight() clienttypes.Height {
+	return pp.proofHeight
+}
+
+// ProveState returns a commitment proof of `value` at `path` verified by the ELC at the pinned height
+func (pp *PinnedProver) ProveState(ctx context.Context, path string, value []byte) ([]byte, error) {
+	proof, proofHeight, err := pp.pr.ProveState(core.NewQueryContext(ctx, pp.queryHeight), path, value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove the state at the pinned height: pinned_height=%v query_height=%v path=%v %w", pp.proofHeight, pp.queryHeight, path, err)
+	}
+	if !proofHeight.EQ(pp.proofHeight) {
+		return nil, fmt.Errorf("the origin prover cannot produce a proof at the pinned height: pinned_height=%v proof_height=%v path=%v", pp.proofHeight, proofHeight, path)
+	}
+	return proof, nil
+}
+
+// ProveStates returns the commitment proofs of `states` in the same order, all of which are verified by the ELC at the pinned height.
+// It fails at the first state which cannot be proven.
+func (pp *PinnedProver) ProveStates(ctx context.Context, states []StateToProve) ([][]byte, error) {
+	proofs := make([][]byte, 0, len(states))
+	for _, s := range states {
+		proof, err := pp.ProveState(ctx, s.Path, s.Value)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, proof)
+	}
+	return proofs, nil
+}
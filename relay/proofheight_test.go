@@ -0,0 +1,96 @@
+package relay
+
+import (
+	"context"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAdvancingOriginProver is the prover of an origin chain which always proves the states at its latest height regardless of the query height
+type mockAdvancingOriginProver struct {
+	mockSelfTestOriginProver
+}
+
+func (p mockAdvancingOriginProver) ProveState(ctx core.QueryContext, path string, value []byte) ([]byte, clienttypes.Height, error) {
+	return []byte("proof"), p.latestHeight, nil
+}
+
+func TestPinnedProver(t *testing.T) {
+	const elcClientID = "07-tendermint-0"
+	var (
+		latestHeight = clienttypes.NewHeight(0, 10)
+		pinnedHeight = clienttypes.NewHeight(0, 8)
+	)
+	newProver := func(t *testing.T, originProver core.Prover) *Prover {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		service := &mockLCPService{t: t, key: key, clients: map[string]*lcptypes.ClientState{
+			elcClientID: {LatestHeight: latestHeight},
+		}}
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.config.ElcClientId = elcClientID
+		pr.originProver = originProver
+		pr.lcpServiceClient.ELCMsgClient = service
+		pr.lcpServiceClient.ELCQueryClient = service
+		pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: crypto.PubkeyToAddress(key.PublicKey).Bytes()}
+		return pr
+	}
+	states := []StateToProve{
+		{Path: host.FullClientStatePath("07-tendermint-1"), Value: []byte("client-state")},
+		{Path: host.ConnectionPath("connection-0"), Value: []byte("connection")},
+		{Path: host.ChannelPath("transfer", "channel-0"), Value: []byte("channel")},
+	}
+
+	t.Run("three paths at the pinned height", func(t *testing.T) {
+		require := require.New(t)
+		pr := newProver(t, mockSelfTestOriginProver{latestHeight: latestHeight})
+		pp, err := pr.WithProofHeight(context.TODO(), pinnedHeight)
+		require.NoError(err)
+		require.Equal(pinnedHeight, pp.ProofHeight())
+
+		proofs, err := pp.ProveStates(context.TODO(), states)
+		require.NoError(err)
+		require.Len(proofs, len(states))
+		for i, proof := range proofs {
+			cp, err := lcptypes.EthABIDecodeCommitmentProofs(proof)
+			require.NoError(err)
+			message, err := lcptypes.EthABIDecodeHeaderedProxyMessage(cp.Message)
+			require.NoError(err)
+			vm, err := message.GetVerifyMembershipProxyMessage()
+			require.NoError(err)
+			require.Equal(pinnedHeight, vm.Height)
+			require.Equal(states[i].Path, string(vm.Path))
+		}
+	})
+
+	t.Run("height not verified by the ELC", func(t *testing.T) {
+		pr := newProver(t, mockSelfTestOriginProver{latestHeight: latestHeight})
+		_, err := pr.WithProofHeight(context.TODO(), clienttypes.NewHeight(0, 11))
+		require.ErrorContains(t, err, "the pinned height is not verified by the ELC: elc_client_id=07-tendermint-0 pinned_height=0-11 latest_height=0-10")
+		_, err = pr.WithProofHeight(context.TODO(), clienttypes.NewHeight(1, 1))
+		require.ErrorContains(t, err, "pinned_height=1-1")
+	})
+
+	t.Run("ELC client not found", func(t *testing.T) {
+		pr := newProver(t, mockSelfTestOriginProver{latestHeight: latestHeight})
+		pr.config.ElcClientId = "07-tendermint-1"
+		_, err := pr.WithProofHeight(context.TODO(), pinnedHeight)
+		require.ErrorContains(t, err, "ELC's client not found: elc_client_id=07-tendermint-1 pinned_height=0-8")
+	})
+
+	t.Run("origin cannot prove at the pinned height", func(t *testing.T) {
+		pr := newProver(t, mockAdvancingOriginProver{mockSelfTestOriginProver{latestHeight: latestHeight}})
+		pp, err := pr.WithProofHeight(context.TODO(), pinnedHeight)
+		require.NoError(t, err)
+		_, err = pp.ProveStates(context.TODO(), states)
+		require.ErrorContains(t, err, "the origin prover cannot produce a proof at the pinned height: pinned_height=0-8 proof_height=0-10")
+	})
+}
@@ -0,0 +1,213 @@
+package relay
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/proofservice"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ProofServerConfig is the config of ProofServer.
+// Either Token or ClientCACert must be set so that every request is authenticated.
+type ProofServerConfig struct {
+	// if not empty, the requests must have the metadata "authorization: Bearer <Token>"
+	// the token is sent in plaintext unless TLS is enabled
+	Token string
+	// the PEM files of the certificate and the key of the server. if empty, TLS is disabled
+	TLSCert string
+	TLSKey  string
+	// the PEM file of the CA certificates. if not empty, the clients must present a certificate signed by them (mTLS)
+	ClientCACert string
+}
+
+// Validate validates the config
+func (c ProofServerConfig) Validate() error {
+	if c.Token == "" && c.ClientCACert == "" {
+		return fmt.Errorf("either Token or ClientCACert must be set to authenticate the requests")
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return fmt.Errorf("TLSCert and TLSKey must be set together: cert=%q key=%q", c.TLSCert, c.TLSKey)
+	}
+	if c.ClientCACert != "" && c.TLSCert == "" {
+		return fmt.Errorf("TLSCert and TLSKey must be set if ClientCACert is set")
+	}
+	return nil
+}
+
+// serverOptions returns the options of the gRPC server with the transport credentials and the authentication of the config
+func (c ProofServerConfig) serverOptions() ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+	if c.TLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCert, c.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the server certificate: cert=%v key=%v %w", c.TLSCert, c.TLSKey, err)
+		}
+		cfg := &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		}
+		if path := c.ClientCACert; path != "" {
+			bz, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read ClientCACert: path=%v %w", path, err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(bz) {
+				return nil, fmt.Errorf("ClientCACert contains no PEM certificate: path=%v", path)
+			}
+			cfg.ClientCAs = pool
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(cfg)))
+	}
+	if c.Token != "" {
+		opts = append(opts, grpc.UnaryInterceptor(tokenAuthInterceptor(c.Token)))
+	}
+	return opts, nil
+}
+
+// tokenAuthInterceptor rejects the requests without the bearer token
+func tokenAuthInterceptor(token string) grpc.UnaryServerInterceptor {
+	expected := []byte("Bearer " + token)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) != 1 || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(values[0])), expected) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// ProofServer serves the proofs of a configured Prover over gRPC to the processes other than the relayer, e.g. an API server.
+// The relayer never starts it, so the relay is unaffected unless a process starts it explicitly.
+// The prover must be initialized with Init before it is passed to NewProofServer.
+type ProofServer struct {
+	proofservice.UnimplementedProofServiceServer
+
+	// the prover is not safe for concurrent use, so the requests are processed one by one
+	mu     sync.Mutex
+	pr     *Prover
+	server *grpc.Server
+}
+
+var _ proofservice.ProofServiceServer = (*ProofServer)(nil)
+
+// NewProofServer returns a server of the proofs generated by `pr`
+func NewProofServer(pr *Prover, config ProofServerConfig) (*ProofServer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	opts, err := config.serverOptions()
+	if err != nil {
+		return nil, err
+	}
+	s := &ProofServer{pr: pr, server: grpc.NewServer(opts...)}
+	proofservice.RegisterProofServiceServer(s.server, s)
+	return s, nil
+}
+
+// Serve accepts the connections on `listener` until Stop is called
+func (s *ProofServer) Serve(listener net.Listener) error {
+	s.pr.getLogger().Info("start the proof server", "address", listener.Addr().String())
+	return s.server.Serve(listener)
+}
+
+// Stop stops the server after the in-flight requests are finished
+func (s *ProofServer) Stop() {
+	s.server.GracefulStop()
+}
+
+// GenerateMembershipProof implements proofservice.ProofServiceServer
+func (s *ProofServer) GenerateMembershipProof(ctx context.Context, req *proofservice.GenerateMembershipProofRequest) (*proofservice.GenerateMembershipProofResponse, error) {
+	if req.Path == "" {
+		return nil, status.Error(codes.InvalidArgument, "path must not be empty")
+	} else if req.Height.IsZero() {
+		return nil, status.Error(codes.InvalidArgument, "height must not be zero")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.pr.ensureActiveEnclaveKey(ctx); err != nil {
+		return nil, proofServerError(ctx, err)
+	}
+	proof, proofHeight, err := s.pr.ProveState(core.NewQueryContext(ctx, req.Height), req.Path, req.Value)
+	if err != nil {
+		return nil, proofServerError(ctx, err)
+	}
+	return &proofservice.GenerateMembershipProofResponse{Proof: proof, ProofHeight: proofHeight}, nil
+}
+
+// GetELCStatus implements proofservice.ProofServiceServer
+func (s *ProofServer) GetELCStatus(ctx context.Context, req *proofservice.GetELCStatusRequest) (*proofservice.GetELCStatusResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	found, latestHeight, err := s.queryELCLatestHeight(ctx)
+	if err != nil {
+		return nil, proofServerError(ctx, err)
+	}
+	res := &proofservice.GetELCStatusResponse{
+		ElcClientId:  s.pr.GetELCClientID(),
+		Found:        found,
+		LatestHeight: latestHeight,
+	}
+	if eki := s.pr.activeEnclaveKey; eki != nil {
+		res.ActiveEnclaveKey = eki.EnclaveKeyAddress
+		res.ActiveEnclaveKeyAttestationTime = eki.AttestationTime
+	}
+	return res, nil
+}
+
+// TriggerUpdate implements proofservice.ProofServiceServer
+func (s *ProofServer) TriggerUpdate(ctx context.Context, req *proofservice.TriggerUpdateRequest) (*proofservice.TriggerUpdateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, err := s.pr.doUpdateELC(s.pr.GetELCClientID(), nil)
+	if err != nil {
+		return nil, proofServerError(ctx, err)
+	}
+	_, latestHeight, err := s.queryELCLatestHeight(ctx)
+	if err != nil {
+		return nil, proofServerError(ctx, err)
+	}
+	return &proofservice.TriggerUpdateResponse{HeadersApplied: uint32(result.HeadersApplied), LatestHeight: latestHeight}, nil
+}
+
+// queryELCLatestHeight returns the latest height of the ELC client of the prover if it exists
+func (s *ProofServer) queryELCLatestHeight(ctx context.Context) (bool, clienttypes.Height, error) {
+	res, err := s.pr.lcpServiceClient.Client(ctx, &elc.QueryClientRequest{ClientId: s.pr.GetELCClientID()})
+	if err != nil {
+		return false, clienttypes.Height{}, fmt.Errorf("failed to query the ELC client: elc_client_id=%v %w", s.pr.GetELCClientID(), err)
+	} else if !res.Found {
+		return false, clienttypes.Height{}, nil
+	}
+	var clientState ibcexported.ClientState
+	if err := s.pr.codec.UnpackAny(res.ClientState, &clientState); err != nil {
+		return false, clienttypes.Height{}, fmt.Errorf("failed to unpack the client state of the ELC client: %w", err)
+	}
+	return true, clienttypes.NewHeight(clientState.GetLatestHeight().GetRevisionNumber(), clientState.GetLatestHeight().GetRevisionHeight()), nil
+}
+
+// proofServerError converts `err` to the status of gRPC.
+// The cancellation and the deadline of the request are reported as they are, and the other errors are reported as internal.
+func proofServerError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
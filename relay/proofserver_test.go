@@ -0,0 +1,156 @@
+package relay
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/datachainlab/lcp-go/relay/proofservice"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// newTestProofServer starts a proof server of a prover backed by the mock LCP service and returns its address
+func newTestProofServer(t *testing.T, config ProofServerConfig) (*mockLCPService, *Prover, string) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	service := &mockLCPService{t: t, key: key, clients: make(map[string]*lcptypes.ClientState)}
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.homePath = t.TempDir()
+	pr.config.ElcClientId = "07-tendermint-0"
+	pr.originProver = mockSelfTestOriginProver{latestHeight: clienttypes.NewHeight(0, 10)}
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	pr.lcpServiceClient = LCPServiceClient{ELCMsgClient: service, ELCQueryClient: service, EnclaveQueryClient: mockEnclaveQueryClient{}}
+	pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: crypto.PubkeyToAddress(key.PublicKey).Bytes(), AttestationTime: 1700000000}
+	require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+
+	server, err := NewProofServer(pr, config)
+	require.NoError(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return service, pr, lis.Addr().String()
+}
+
+func dialTestProofServer(t *testing.T, addr string, creds credentials.TransportCredentials) proofservice.ProofServiceClient {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return proofservice.NewProofServiceClient(conn)
+}
+
+func withTestToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestProofServer(t *testing.T) {
+	require := require.New(t)
+	service, pr, addr := newTestProofServer(t, ProofServerConfig{Token: "secret"})
+	client := dialTestProofServer(t, addr, insecure.NewCredentials())
+	ctx := withTestToken("secret")
+
+	// the ELC client is not created yet
+	st, err := client.GetELCStatus(ctx, &proofservice.GetELCStatusRequest{})
+	require.NoError(err)
+	require.Equal("07-tendermint-0", st.ElcClientId)
+	require.False(st.Found)
+	require.Equal(pr.activeEnclaveKey.EnclaveKeyAddress, st.ActiveEnclaveKey)
+	require.Equal(uint64(1700000000), st.ActiveEnclaveKeyAttestationTime)
+
+	service.clients["07-tendermint-0"] = &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, 5)}
+	st, err = client.GetELCStatus(ctx, &proofservice.GetELCStatusRequest{})
+	require.NoError(err)
+	require.True(st.Found)
+	require.Equal(clienttypes.NewHeight(0, 5), st.LatestHeight)
+
+	up, err := client.TriggerUpdate(ctx, &proofservice.TriggerUpdateRequest{})
+	require.NoError(err)
+	require.Equal(uint32(1), up.HeadersApplied)
+	require.Equal(service.clients["07-tendermint-0"].LatestHeight, up.LatestHeight)
+
+	path := "commitments/ports/transfer/channels/channel-0/sequences/1"
+	res, err := client.GenerateMembershipProof(ctx, &proofservice.GenerateMembershipProofRequest{Height: clienttypes.NewHeight(0, 6), Path: path, Value: []byte("value")})
+	require.NoError(err)
+	require.Equal(clienttypes.NewHeight(0, 6), res.ProofHeight)
+	proof, err := lcptypes.EthABIDecodeCommitmentProofs(res.Proof)
+	require.NoError(err)
+	m, err := proof.GetMessage()
+	require.NoError(err)
+	vm, err := m.GetVerifyMembershipProxyMessage()
+	require.NoError(err)
+	require.Equal(path, string(vm.Path))
+
+	_, err = client.GenerateMembershipProof(ctx, &proofservice.GenerateMembershipProofRequest{Height: clienttypes.NewHeight(0, 6)})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := client.GetELCStatus(context.Background(), &proofservice.GetELCStatusRequest{})
+		require.Equal(codes.Unauthenticated, status.Code(err))
+		_, err = client.TriggerUpdate(withTestToken("wrong"), &proofservice.TriggerUpdateRequest{})
+		require.Equal(codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestProofServerMTLS(t *testing.T) {
+	ca := newTestCertificate(t, "ca", nil)
+	serverCert := newTestCertificate(t, "proof-server", ca)
+	clientCert := newTestCertificate(t, "api-server", ca)
+	otherClientCert := newTestCertificate(t, "api-server", newTestCertificate(t, "other-ca", nil))
+	_, _, addr := newTestProofServer(t, ProofServerConfig{TLSCert: serverCert.certPath, TLSKey: serverCert.keyPath, ClientCACert: ca.certPath})
+
+	getStatus := func(cert *testCertificate) error {
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		cfg := &tls.Config{RootCAs: roots, ServerName: "proof-server", MinVersion: tls.VersionTLS12}
+		if cert != nil {
+			cfg.Certificates = []tls.Certificate{cert.tlsCertificate()}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := dialTestProofServer(t, addr, credentials.NewTLS(cfg)).GetELCStatus(ctx, &proofservice.GetELCStatusRequest{})
+		return err
+	}
+	require.NoError(t, getStatus(clientCert))
+	require.Error(t, getStatus(nil))
+	require.Error(t, getStatus(otherClientCert))
+}
+
+func TestProofServerConfigValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config ProofServerConfig
+		err    string
+	}{
+		{"token", ProofServerConfig{Token: "secret"}, ""},
+		{"token with TLS", ProofServerConfig{Token: "secret", TLSCert: "server.crt", TLSKey: "server.key"}, ""},
+		{"mTLS", ProofServerConfig{TLSCert: "server.crt", TLSKey: "server.key", ClientCACert: "ca.crt"}, ""},
+		{"unauthenticated", ProofServerConfig{TLSCert: "server.crt", TLSKey: "server.key"}, "either Token or ClientCACert must be set"},
+		{"cert without key", ProofServerConfig{Token: "secret", TLSCert: "server.crt"}, "must be set together"},
+		{"mTLS without TLS", ProofServerConfig{ClientCACert: "ca.crt"}, "must be set if ClientCACert is set"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
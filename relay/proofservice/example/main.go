@@ -0,0 +1,92 @@
+// This example serves the proofs of the LCP prover of a chain in the relayer config over gRPC
+// to the processes other than the relayer. The relayer itself is not started.
+//
+//	PROOF_SERVER_TOKEN=secret go run ./relay/proofservice/example --home ~/.yui-relayer --chain ibc0 --address 127.0.0.1:50061
+//
+// The clients call the service with the metadata "authorization: Bearer secret".
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	lcp "github.com/datachainlab/lcp-go/relay"
+	"github.com/datachainlab/lcp-go/relay/signers/raw"
+	lcptm "github.com/datachainlab/lcp-go/relay/tendermint"
+	tendermint "github.com/hyperledger-labs/yui-relayer/chains/tendermint/module"
+	"github.com/hyperledger-labs/yui-relayer/config"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	rlog "github.com/hyperledger-labs/yui-relayer/log"
+)
+
+func main() {
+	var (
+		home         = flag.String("home", os.ExpandEnv("$HOME/.yui-relayer"), "the home directory of the relayer")
+		chainID      = flag.String("chain", "", "the chain ID whose prover is the LCP prover")
+		address      = flag.String("address", "127.0.0.1:50061", "the address to listen on")
+		tlsCert      = flag.String("tls-cert", "", "the certificate of the server")
+		tlsKey       = flag.String("tls-key", "", "the key of the server")
+		clientCACert = flag.String("client-ca-cert", "", "the CA certificates of the clients for mTLS")
+	)
+	flag.Parse()
+	if err := run(*home, *chainID, *address, lcp.ProofServerConfig{
+		Token:        os.Getenv("PROOF_SERVER_TOKEN"),
+		TLSCert:      *tlsCert,
+		TLSKey:       *tlsKey,
+		ClientCACert: *clientCACert,
+	}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(home, chainID, address string, serverConfig lcp.ProofServerConfig) error {
+	// load the chains in the same way as the relayer
+	modules := []config.ModuleI{tendermint.Module{}, lcp.Module{}, lcptm.Module{}, raw.Module{}}
+	codec := core.MakeCodec()
+	for _, module := range modules {
+		module.RegisterInterfaces(codec.InterfaceRegistry())
+	}
+	ctx := &config.Context{Modules: modules, Config: &config.Config{}, Codec: codec}
+	if err := ctx.Config.UnmarshalConfig(home, "config/config.json"); err != nil {
+		return err
+	}
+	c := ctx.Config.Global.LoggerConfig
+	if err := rlog.InitLogger(c.Level, c.Format, c.Output); err != nil {
+		return err
+	}
+	if err := ctx.InitConfig(home, false); err != nil {
+		return err
+	}
+	chain, err := ctx.Config.GetChain(chainID)
+	if err != nil {
+		return err
+	}
+	prover, ok := chain.Prover.(*lcp.Prover)
+	if !ok {
+		return fmt.Errorf("the prover of the chain is not the LCP prover: chain_id=%v prover=%T", chainID, chain.Prover)
+	}
+
+	server, err := lcp.NewProofServer(prover, serverConfig)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		<-sig
+		server.Stop()
+	}()
+	if err := server.Serve(listener); err != nil {
+		return err
+	}
+	return prover.Close()
+}
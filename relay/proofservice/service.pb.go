@@ -0,0 +1,1563 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: relayer/provers/lcp/proofservice/service.proto
+
+package proofservice
+
+import (
+	context "context"
+	fmt "fmt"
+	_ "github.com/cosmos/gogoproto/gogoproto"
+	grpc1 "github.com/cosmos/gogoproto/grpc"
+	proto "github.com/cosmos/gogoproto/proto"
+	types "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type GenerateMembershipProofRequest struct {
+	// the height of the origin chain at which the value is committed
+	Height types.Height `protobuf:"bytes,1,opt,name=height,proto3" json:"height"`
+	// the commitment path, e.g. "commitments/ports/transfer/channels/channel-0/sequences/1"
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// the committed value. if empty, the absence of the value is proven
+	Value []byte `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *GenerateMembershipProofRequest) Reset()         { *m = GenerateMembershipProofRequest{} }
+func (m *GenerateMembershipProofRequest) String() string { return proto.CompactTextString(m) }
+func (*GenerateMembershipProofRequest) ProtoMessage()    {}
+func (*GenerateMembershipProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ac67f9832efdb308, []int{0}
+}
+func (m *GenerateMembershipProofRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GenerateMembershipProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GenerateMembershipProofRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GenerateMembershipProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GenerateMembershipProofRequest.Merge(m, src)
+}
+func (m *GenerateMembershipProofRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GenerateMembershipProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GenerateMembershipProofRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GenerateMembershipProofRequest proto.InternalMessageInfo
+
+type GenerateMembershipProofResponse struct {
+	Proof       []byte       `protobuf:"bytes,1,opt,name=proof,proto3" json:"proof,omitempty"`
+	ProofHeight types.Height `protobuf:"bytes,2,opt,name=proof_height,json=proofHeight,proto3" json:"proof_height"`
+}
+
+func (m *GenerateMembershipProofResponse) Reset()         { *m = GenerateMembershipProofResponse{} }
+func (m *GenerateMembershipProofResponse) String() string { return proto.CompactTextString(m) }
+func (*GenerateMembershipProofResponse) ProtoMessage()    {}
+func (*GenerateMembershipProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ac67f9832efdb308, []int{1}
+}
+func (m *GenerateMembershipProofResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GenerateMembershipProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GenerateMembershipProofResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GenerateMembershipProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GenerateMembershipProofResponse.Merge(m, src)
+}
+func (m *GenerateMembershipProofResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *GenerateMembershipProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GenerateMembershipProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GenerateMembershipProofResponse proto.InternalMessageInfo
+
+type GetELCStatusRequest struct {
+}
+
+func (m *GetELCStatusRequest) Reset()         { *m = GetELCStatusRequest{} }
+func (m *GetELCStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetELCStatusRequest) ProtoMessage()    {}
+func (*GetELCStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ac67f9832efdb308, []int{2}
+}
+func (m *GetELCStatusRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetELCStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetELCStatusRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetELCStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetELCStatusRequest.Merge(m, src)
+}
+func (m *GetELCStatusRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetELCStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetELCStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetELCStatusRequest proto.InternalMessageInfo
+
+type GetELCStatusResponse struct {
+	ElcClientId string `protobuf:"bytes,1,opt,name=elc_client_id,json=elcClientId,proto3" json:"elc_client_id,omitempty"`
+	// if false, the ELC client is not created yet and `latest_height` is zero
+	Found        bool         `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	LatestHeight types.Height `protobuf:"bytes,3,opt,name=latest_height,json=latestHeight,proto3" json:"latest_height"`
+	// the address of the enclave key signing the proofs. empty if no key is active yet
+	ActiveEnclaveKey []byte `protobuf:"bytes,4,opt,name=active_enclave_key,json=activeEnclaveKey,proto3" json:"active_enclave_key,omitempty"`
+	// unix seconds
+	ActiveEnclaveKeyAttestationTime uint64 `protobuf:"varint,5,opt,name=active_enclave_key_attestation_time,json=activeEnclaveKeyAttestationTime,proto3" json:"active_enclave_key_attestation_time,omitempty"`
+}
+
+func (m *GetELCStatusResponse) Reset()         { *m = GetELCStatusResponse{} }
+func (m *GetELCStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetELCStatusResponse) ProtoMessage()    {}
+func (*GetELCStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ac67f9832efdb308, []int{3}
+}
+func (m *GetELCStatusResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *GetELCStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_GetELCStatusResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *GetELCStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetELCStatusResponse.Merge(m, src)
+}
+func (m *GetELCStatusResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *GetELCStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetELCStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetELCStatusResponse proto.InternalMessageInfo
+
+type TriggerUpdateRequest struct {
+}
+
+func (m *TriggerUpdateRequest) Reset()         { *m = TriggerUpdateRequest{} }
+func (m *TriggerUpdateRequest) String() string { return proto.CompactTextString(m) }
+func (*TriggerUpdateRequest) ProtoMessage()    {}
+func (*TriggerUpdateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ac67f9832efdb308, []int{4}
+}
+func (m *TriggerUpdateRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TriggerUpdateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TriggerUpdateRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TriggerUpdateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TriggerUpdateRequest.Merge(m, src)
+}
+func (m *TriggerUpdateRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TriggerUpdateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TriggerUpdateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TriggerUpdateRequest proto.InternalMessageInfo
+
+type TriggerUpdateResponse struct {
+	// the number of the headers of the origin chain applied to the ELC client
+	HeadersApplied uint32 `protobuf:"varint,1,opt,name=headers_applied,json=headersApplied,proto3" json:"headers_applied,omitempty"`
+	// the latest height of the ELC client after the update
+	LatestHeight types.Height `protobuf:"bytes,2,opt,name=latest_height,json=latestHeight,proto3" json:"latest_height"`
+}
+
+func (m *TriggerUpdateResponse) Reset()         { *m = TriggerUpdateResponse{} }
+func (m *TriggerUpdateResponse) String() string { return proto.CompactTextString(m) }
+func (*TriggerUpdateResponse) ProtoMessage()    {}
+func (*TriggerUpdateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ac67f9832efdb308, []int{5}
+}
+func (m *TriggerUpdateResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TriggerUpdateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TriggerUpdateResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TriggerUpdateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TriggerUpdateResponse.Merge(m, src)
+}
+func (m *TriggerUpdateResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TriggerUpdateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TriggerUpdateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TriggerUpdateResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*GenerateMembershipProofRequest)(nil), "relayer.provers.lcp.proofservice.GenerateMembershipProofRequest")
+	proto.RegisterType((*GenerateMembershipProofResponse)(nil), "relayer.provers.lcp.proofservice.GenerateMembershipProofResponse")
+	proto.RegisterType((*GetELCStatusRequest)(nil), "relayer.provers.lcp.proofservice.GetELCStatusRequest")
+	proto.RegisterType((*GetELCStatusResponse)(nil), "relayer.provers.lcp.proofservice.GetELCStatusResponse")
+	proto.RegisterType((*TriggerUpdateRequest)(nil), "relayer.provers.lcp.proofservice.TriggerUpdateRequest")
+	proto.RegisterType((*TriggerUpdateResponse)(nil), "relayer.provers.lcp.proofservice.TriggerUpdateResponse")
+}
+
+func init() {
+	proto.RegisterFile("relayer/provers/lcp/proofservice/service.proto", fileDescriptor_ac67f9832efdb308)
+}
+
+var fileDescriptor_ac67f9832efdb308 = []byte{
+	// 594 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xcd, 0x6e, 0x13, 0x3d,
+	0x14, 0x8d, 0xd3, 0xb4, 0xfa, 0xea, 0x26, 0x1f, 0xc8, 0xa4, 0x10, 0x65, 0x31, 0x89, 0xc2, 0x82,
+	0x2c, 0xc0, 0xa3, 0x16, 0xf1, 0xb3, 0xa4, 0xad, 0xaa, 0x82, 0x28, 0x02, 0x4d, 0xcb, 0x86, 0xcd,
+	0xc8, 0xf1, 0xdc, 0xce, 0x58, 0x4c, 0xc6, 0x83, 0xc7, 0x19, 0xa9, 0x12, 0x48, 0x2c, 0x90, 0xd8,
+	0x22, 0xb1, 0xe7, 0x1d, 0x78, 0x8b, 0x2e, 0xbb, 0x64, 0x85, 0xa0, 0x7d, 0x11, 0x34, 0xb6, 0x2b,
+	0xfa, 0x43, 0x21, 0x85, 0x55, 0xae, 0xaf, 0xcf, 0x39, 0x73, 0x7c, 0x72, 0x6d, 0x4c, 0x15, 0xa4,
+	0x6c, 0x17, 0x94, 0x9f, 0x2b, 0x59, 0x82, 0x2a, 0xfc, 0x94, 0xe7, 0x55, 0x2d, 0x77, 0x0a, 0x50,
+	0xa5, 0xe0, 0xe0, 0xbb, 0x5f, 0x9a, 0x2b, 0xa9, 0x25, 0xe9, 0x3b, 0x3c, 0x75, 0x78, 0x9a, 0xf2,
+	0x9c, 0x1e, 0xc7, 0x77, 0xdb, 0xb1, 0x8c, 0xa5, 0x01, 0xfb, 0x55, 0x65, 0x79, 0xdd, 0x9e, 0x18,
+	0x71, 0x9f, 0x4b, 0x05, 0x3e, 0x4f, 0x05, 0x64, 0xda, 0x2f, 0x97, 0x5c, 0x65, 0x01, 0x83, 0x77,
+	0x08, 0x7b, 0x1b, 0x90, 0x81, 0x62, 0x1a, 0x9e, 0xc0, 0x78, 0x04, 0xaa, 0x48, 0x44, 0xfe, 0xac,
+	0x52, 0x0e, 0xe0, 0xd5, 0x04, 0x0a, 0x4d, 0xee, 0xe3, 0xb9, 0x04, 0x44, 0x9c, 0xe8, 0x0e, 0xea,
+	0xa3, 0xe1, 0xc2, 0x72, 0x97, 0x8a, 0x11, 0xa7, 0x95, 0x28, 0x75, 0x52, 0xe5, 0x12, 0x7d, 0x68,
+	0x10, 0xab, 0x8d, 0xbd, 0xaf, 0xbd, 0x5a, 0xe0, 0xf0, 0x84, 0xe0, 0x46, 0xce, 0x74, 0xd2, 0xa9,
+	0xf7, 0xd1, 0x70, 0x3e, 0x30, 0x35, 0x69, 0xe3, 0xd9, 0x92, 0xa5, 0x13, 0xe8, 0xcc, 0xf4, 0xd1,
+	0xb0, 0x19, 0xd8, 0xc5, 0xe0, 0x35, 0xee, 0x9d, 0xeb, 0xa2, 0xc8, 0x65, 0x56, 0x40, 0x45, 0x34,
+	0x07, 0x36, 0x2e, 0x9a, 0x81, 0x5d, 0x90, 0x35, 0xdc, 0x34, 0x45, 0xe8, 0x2c, 0xd6, 0xa7, 0xb4,
+	0xb8, 0x60, 0x58, 0xb6, 0x35, 0x58, 0xc4, 0x57, 0x36, 0x40, 0xaf, 0x6f, 0xae, 0x6d, 0x69, 0xa6,
+	0x27, 0x85, 0x3b, 0xf8, 0xe0, 0x63, 0x1d, 0xb7, 0x4f, 0xf6, 0x9d, 0x95, 0x01, 0x6e, 0x41, 0xca,
+	0x43, 0x2b, 0x1d, 0x8a, 0xc8, 0x58, 0x9a, 0x0f, 0x16, 0x20, 0xe5, 0x6b, 0xa6, 0xf7, 0x28, 0xaa,
+	0xec, 0xee, 0xc8, 0x49, 0x16, 0x19, 0x47, 0xff, 0x05, 0x76, 0x41, 0xd6, 0x71, 0x2b, 0x65, 0x1a,
+	0x0a, 0x7d, 0xe4, 0x77, 0x66, 0x4a, 0xbf, 0x4d, 0x4b, 0xb3, 0x3d, 0x72, 0x13, 0x13, 0xc6, 0xb5,
+	0x28, 0x21, 0x84, 0x8c, 0xa7, 0xac, 0x84, 0xf0, 0x25, 0xec, 0x76, 0x1a, 0x26, 0x98, 0xcb, 0x76,
+	0x67, 0xdd, 0x6e, 0x3c, 0x86, 0x5d, 0xb2, 0x89, 0xaf, 0x9f, 0x45, 0x87, 0x4c, 0x57, 0x82, 0x4c,
+	0x0b, 0x99, 0x85, 0x5a, 0x8c, 0xa1, 0x33, 0xdb, 0x47, 0xc3, 0x46, 0xd0, 0x3b, 0x4d, 0x5f, 0xf9,
+	0x89, 0xdb, 0x16, 0x63, 0x18, 0x5c, 0xc5, 0xed, 0x6d, 0x25, 0xe2, 0x18, 0xd4, 0xf3, 0x3c, 0x62,
+	0x1a, 0x8e, 0xd2, 0x7a, 0x8f, 0xf0, 0xe2, 0xa9, 0x0d, 0x17, 0xd7, 0x0d, 0x7c, 0x29, 0x01, 0x16,
+	0x81, 0x2a, 0x42, 0x96, 0xe7, 0xa9, 0x00, 0x1b, 0x58, 0x2b, 0xf8, 0xdf, 0xb5, 0x57, 0x6c, 0xf7,
+	0x6c, 0x3a, 0xf5, 0xbf, 0x49, 0x67, 0xf9, 0xf3, 0x0c, 0x6e, 0x9a, 0xd9, 0xd9, 0xb2, 0x77, 0x83,
+	0x7c, 0x42, 0xf8, 0xda, 0x39, 0xe3, 0x45, 0x1e, 0xd0, 0x3f, 0x5d, 0x2d, 0xfa, 0xfb, 0xfb, 0xd1,
+	0x5d, 0xf9, 0x07, 0x05, 0x97, 0xd0, 0x1b, 0xdc, 0x3c, 0x3e, 0x68, 0xe4, 0xce, 0x34, 0x92, 0x67,
+	0x06, 0xb6, 0x7b, 0xf7, 0xa2, 0x34, 0xf7, 0xf9, 0xb7, 0x08, 0xb7, 0x4e, 0xfc, 0x75, 0x64, 0x0a,
+	0xa5, 0x5f, 0x0d, 0x41, 0xf7, 0xde, 0x85, 0x79, 0xd6, 0xc2, 0xea, 0xd3, 0xbd, 0xef, 0x5e, 0x6d,
+	0xef, 0xc0, 0x43, 0xfb, 0x07, 0x1e, 0xfa, 0x76, 0xe0, 0xa1, 0x0f, 0x87, 0x5e, 0x6d, 0xff, 0xd0,
+	0xab, 0x7d, 0x39, 0xf4, 0x6a, 0x2f, 0x96, 0x62, 0xa1, 0x93, 0xc9, 0x88, 0x72, 0x39, 0xf6, 0x23,
+	0xa6, 0x19, 0x4f, 0x98, 0xc8, 0x52, 0x36, 0xaa, 0x9e, 0xcd, 0x5b, 0xb1, 0xf4, 0xcd, 0x47, 0x4f,
+	0xbc, 0x9f, 0xa3, 0x39, 0xf3, 0xbe, 0xdd, 0xfe, 0x31, 0x00, 0x66, 0xaf, 0x8f, 0xe2, 0x6a, 0x05,
+	0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ProofServiceClient is the client API for ProofService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ProofServiceClient interface {
+	// GenerateMembershipProof returns the commitment proof of the value at the path of the origin chain verified by the ELC client.
+	// If the value is empty, the proof of the absence of the value is returned.
+	GenerateMembershipProof(ctx context.Context, in *GenerateMembershipProofRequest, opts ...grpc.CallOption) (*GenerateMembershipProofResponse, error)
+	// GetELCStatus returns the latest height of the ELC client and the active enclave key of the prover
+	GetELCStatus(ctx context.Context, in *GetELCStatusRequest, opts ...grpc.CallOption) (*GetELCStatusResponse, error)
+	// TriggerUpdate updates the ELC client to the latest finalized header of the origin chain
+	TriggerUpdate(ctx context.Context, in *TriggerUpdateRequest, opts ...grpc.CallOption) (*TriggerUpdateResponse, error)
+}
+
+type proofServiceClient struct {
+	cc grpc1.ClientConn
+}
+
+func NewProofServiceClient(cc grpc1.ClientConn) ProofServiceClient {
+	return &proofServiceClient{cc}
+}
+
+func (c *proofServiceClient) GenerateMembershipProof(ctx context.Context, in *GenerateMembershipProofRequest, opts ...grpc.CallOption) (*GenerateMembershipProofResponse, error) {
+	out := new(GenerateMembershipProofResponse)
+	err := c.cc.Invoke(ctx, "/relayer.provers.lcp.proofservice.ProofService/GenerateMembershipProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proofServiceClient) GetELCStatus(ctx context.Context, in *GetELCStatusRequest, opts ...grpc.CallOption) (*GetELCStatusResponse, error) {
+	out := new(GetELCStatusResponse)
+	err := c.cc.Invoke(ctx, "/relayer.provers.lcp.proofservice.ProofService/GetELCStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proofServiceClient) TriggerUpdate(ctx context.Context, in *TriggerUpdateRequest, opts ...grpc.CallOption) (*TriggerUpdateResponse, error) {
+	out := new(TriggerUpdateResponse)
+	err := c.cc.Invoke(ctx, "/relayer.provers.lcp.proofservice.ProofService/TriggerUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProofServiceServer is the server API for ProofService service.
+type ProofServiceServer interface {
+	// GenerateMembershipProof returns the commitment proof of the value at the path of the origin chain verified by the ELC client.
+	// If the value is empty, the proof of the absence of the value is returned.
+	GenerateMembershipProof(context.Context, *GenerateMembershipProofRequest) (*GenerateMembershipProofResponse, error)
+	// GetELCStatus returns the latest height of the ELC client and the active enclave key of the prover
+	GetELCStatus(context.Context, *GetELCStatusRequest) (*GetELCStatusResponse, error)
+	// TriggerUpdate updates the ELC client to the latest finalized header of the origin chain
+	TriggerUpdate(context.Context, *TriggerUpdateRequest) (*TriggerUpdateResponse, error)
+}
+
+// UnimplementedProofServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedProofServiceServer struct {
+}
+
+func (*UnimplementedProofServiceServer) GenerateMembershipProof(ctx context.Context, req *GenerateMembershipProofRequest) (*GenerateMembershipProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateMembershipProof not implemented")
+}
+func (*UnimplementedProofServiceServer) GetELCStatus(ctx context.Context, req *GetELCStatusRequest) (*GetELCStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetELCStatus not implemented")
+}
+func (*UnimplementedProofServiceServer) TriggerUpdate(ctx context.Context, req *TriggerUpdateRequest) (*TriggerUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerUpdate not implemented")
+}
+
+func RegisterProofServiceServer(s grpc1.Server, srv ProofServiceServer) {
+	s.RegisterService(&_ProofService_serviceDesc, srv)
+}
+
+func _ProofService_GenerateMembershipProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateMembershipProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProofServiceServer).GenerateMembershipProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/relayer.provers.lcp.proofservice.ProofService/GenerateMembershipProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProofServiceServer).GenerateMembershipProof(ctx, req.(*GenerateMembershipProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProofService_GetELCStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetELCStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProofServiceServer).GetELCStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/relayer.provers.lcp.proofservice.ProofService/GetELCStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProofServiceServer).GetELCStatus(ctx, req.(*GetELCStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProofService_TriggerUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProofServiceServer).TriggerUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/relayer.provers.lcp.proofservice.ProofService/TriggerUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProofServiceServer).TriggerUpdate(ctx, req.(*TriggerUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ProofService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "relayer.provers.lcp.proofservice.ProofService",
+	HandlerType: (*ProofServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GenerateMembershipProof",
+			Handler:    _ProofService_GenerateMembershipProof_Handler,
+		},
+		{
+			MethodName: "GetELCStatus",
+			Handler:    _ProofService_GetELCStatus_Handler,
+		},
+		{
+			MethodName: "TriggerUpdate",
+			Handler:    _ProofService_TriggerUpdate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "relayer/provers/lcp/proofservice/service.proto",
+}
+
+func (m *GenerateMembershipProofRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GenerateMembershipProofRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GenerateMembershipProofRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintService(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Path) > 0 {
+		i -= len(m.Path)
+		copy(dAtA[i:], m.Path)
+		i = encodeVarintService(dAtA, i, uint64(len(m.Path)))
+		i--
+		dAtA[i] = 0x12
+	}
+	{
+		size, err := m.Height.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintService(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *GenerateMembershipProofResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GenerateMembershipProofResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GenerateMembershipProofResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.ProofHeight.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintService(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if len(m.Proof) > 0 {
+		i -= len(m.Proof)
+		copy(dAtA[i:], m.Proof)
+		i = encodeVarintService(dAtA, i, uint64(len(m.Proof)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *GetELCStatusRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetELCStatusRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetELCStatusRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *GetELCStatusResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetELCStatusResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetELCStatusResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ActiveEnclaveKeyAttestationTime != 0 {
+		i = encodeVarintService(dAtA, i, uint64(m.ActiveEnclaveKeyAttestationTime))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.ActiveEnclaveKey) > 0 {
+		i -= len(m.ActiveEnclaveKey)
+		copy(dAtA[i:], m.ActiveEnclaveKey)
+		i = encodeVarintService(dAtA, i, uint64(len(m.ActiveEnclaveKey)))
+		i--
+		dAtA[i] = 0x22
+	}
+	{
+		size, err := m.LatestHeight.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintService(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	if m.Found {
+		i--
+		if m.Found {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.ElcClientId) > 0 {
+		i -= len(m.ElcClientId)
+		copy(dAtA[i:], m.ElcClientId)
+		i = encodeVarintService(dAtA, i, uint64(len(m.ElcClientId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TriggerUpdateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TriggerUpdateRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TriggerUpdateRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func (m *TriggerUpdateResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TriggerUpdateResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TriggerUpdateResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.LatestHeight.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintService(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if m.HeadersApplied != 0 {
+		i = encodeVarintService(dAtA, i, uint64(m.HeadersApplied))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintService(dAtA []byte, offset int, v uint64) int {
+	offset -= sovService(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *GenerateMembershipProofRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = m.Height.Size()
+	n += 1 + l + sovService(uint64(l))
+	l = len(m.Path)
+	if l > 0 {
+		n += 1 + l + sovService(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovService(uint64(l))
+	}
+	return n
+}
+
+func (m *GenerateMembershipProofResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Proof)
+	if l > 0 {
+		n += 1 + l + sovService(uint64(l))
+	}
+	l = m.ProofHeight.Size()
+	n += 1 + l + sovService(uint64(l))
+	return n
+}
+
+func (m *GetELCStatusRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *GetELCStatusResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ElcClientId)
+	if l > 0 {
+		n += 1 + l + sovService(uint64(l))
+	}
+	if m.Found {
+		n += 2
+	}
+	l = m.LatestHeight.Size()
+	n += 1 + l + sovService(uint64(l))
+	l = len(m.ActiveEnclaveKey)
+	if l > 0 {
+		n += 1 + l + sovService(uint64(l))
+	}
+	if m.ActiveEnclaveKeyAttestationTime != 0 {
+		n += 1 + sovService(uint64(m.ActiveEnclaveKeyAttestationTime))
+	}
+	return n
+}
+
+func (m *TriggerUpdateRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *TriggerUpdateResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.HeadersApplied != 0 {
+		n += 1 + sovService(uint64(m.HeadersApplied))
+	}
+	l = m.LatestHeight.Size()
+	n += 1 + l + sovService(uint64(l))
+	return n
+}
+
+func sovService(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozService(x uint64) (n int) {
+	return sovService(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *GenerateMembershipProofRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GenerateMembershipProofRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GenerateMembershipProofRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Height.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Path = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GenerateMembershipProofResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GenerateMembershipProofResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GenerateMembershipProofResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Proof", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Proof = append(m.Proof[:0], dAtA[iNdEx:postIndex]...)
+			if m.Proof == nil {
+				m.Proof = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProofHeight", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.ProofHeight.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetELCStatusRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetELCStatusRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetELCStatusRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetELCStatusResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetELCStatusResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetELCStatusResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElcClientId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ElcClientId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Found", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Found = bool(v != 0)
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LatestHeight", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.LatestHeight.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ActiveEnclaveKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ActiveEnclaveKey = append(m.ActiveEnclaveKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.ActiveEnclaveKey == nil {
+				m.ActiveEnclaveKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ActiveEnclaveKeyAttestationTime", wireType)
+			}
+			m.ActiveEnclaveKeyAttestationTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ActiveEnclaveKeyAttestationTime |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TriggerUpdateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TriggerUpdateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TriggerUpdateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TriggerUpdateResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TriggerUpdateResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TriggerUpdateResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeadersApplied", wireType)
+			}
+			m.HeadersApplied = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HeadersApplied |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LatestHeight", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthService
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthService
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.LatestHeight.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipService(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthService
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipService(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowService
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowService
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthService
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupService
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthService
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthService        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowService          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupService = fmt.Errorf("proto: unexpected end of group")
+)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/codec"
@@ -17,7 +18,6 @@ import (
 	"github.com/hyperledger-labs/yui-relayer/log"
 	"github.com/hyperledger-labs/yui-relayer/signer"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type Prover struct {
@@ -30,15 +30,101 @@ type Prover struct {
 	path     *core.PathEnd
 
 	lcpServiceClient LCPServiceClient
+	// if not nil, the calls of lcpServiceClient are rate limited by this
+	rateLimiter *serviceRateLimiter
 
-	eip712Signer *EIP712Signer
+	eip712Signer OperatorSigner
 
 	// state
+	// the ELC client ID generated and persisted by the prover, which is used if ElcClientId is not set in the config
+	elcClientID string
 	// registered key info for requesting lcp to generate proof.
 	activeEnclaveKey *enclave.EnclaveKeyInfo
 	// if not nil, the key is finalized.
 	// if nil, the key is not finalized yet.
 	unfinalizedMsgID core.MsgID
+	// the height of the block including the unfinalized msg. It is zero if unknown.
+	unfinalizedMsgHeight clienttypes.Height
+
+	// cache for the latest finalized header of the counterparty chain
+	counterpartyFinalizedHeaderCache *finalizedHeaderCache
+	// the initial states of the origin light client reused by the retries to create an ELC
+	initialLightClientStates initialLightClientStateCache
+
+	// if not nil, the prover is in the rehearsal mode
+	rehearsal *rehearsal
+
+	// if not nil, the report signing certificate is checked against the CRL
+	crlCache *crlCache
+
+	// the counterparty chain set by SetRelayInfo
+	counterparty *core.ProvableChain
+	// the path end of the counterparty chain set by SetRelayInfo
+	counterpartyPath *core.PathEnd
+	// the override of the quote policy for the counterparty resolved by SetRelayInfo
+	// if nil, the global values in the config are used
+	quotePolicyOverride *QuotePolicyOverride
+	// the estimated finality lag of the counterparty chain
+	counterpartyFinalityLag time.Duration
+	// the maximum gap between updates that the counterparty LCP client allows, or zero if it is not bounded
+	counterpartyMaxUpdateGap time.Duration
+	// the key expiration of the counterparty LCP client, or zero if the client state has not been queried yet
+	counterpartyKeyExpiration time.Duration
+	// the proxy message versions that the counterparty LCP client accepts, or nil if the client state has not been queried yet
+	counterpartyMessageVersions []uint16
+
+	// notifies the operators of critical conditions
+	// if nil, the alerts are only logged
+	alerter *alerter
+
+	// the proxy message version observed from the LCP service
+	// zero means that no message has been observed yet
+	observedMessageVersion uint16
+
+	// if not nil, the diagnostics server is running
+	diagnostics *diagnosticsServer
+
+	// the sink of the metrics selected by the config
+	// if nil, the metrics are recorded with the global meter provider
+	metrics metricsSink
+
+	// if not nil, the path stats are recorded
+	stats   *statsRecorder
+	statsMu sync.Mutex
+
+	// the state of the attestation policy watch
+	policyWatch policyWatchState
+
+	// the state of the periodic garbage collection of the home directory
+	gc gcState
+
+	// ErrEnclaveModeMismatch found by Init, which is returned by SetRelayInfo unless skipEnclaveModeCheck is set
+	enclaveModeErr error
+	// if true, the home directory used in another enclave mode is accepted, e.g. to migrate it
+	skipEnclaveModeCheck bool
+
+	// the clock skews measured periodically
+	clockSkew clockSkewState
+
+	// the state of the metrics of the enclave key lifecycle and the latencies of the ELC
+	lifecycleMetrics lifecycleMetricsState
+
+	// the watchdog of the registration of the active enclave key that is not finalized yet
+	unfinalizedKeyWatchdog unfinalizedKeyWatchdogState
+
+	// the AVRs whose signatures have been verified
+	// if nil, the signatures are always verified
+	avrCache *avrCache
+
+	// serializes the access to the submission queue
+	submissionQueueMu sync.Mutex
+
+	// the last prediction that the updates pass the validation contexts, which is reported after the next block of the counterparty chain
+	validationContextPrediction *validationContextPrediction
+
+	// the capabilities of the LCP service discovered by SetupForRelay
+	// if nil, all the capabilities are assumed to be supported
+	serviceCapabilities *ServiceCapabilities
 }
 
 var (
@@ -46,24 +132,66 @@ var (
 )
 
 func NewProver(config ProverConfig, originChain core.Chain, originProver core.Prover) (*Prover, error) {
-	conn, err := grpc.Dial(
+	creds, err := config.lcpServiceTransportCredentials()
+	if err != nil {
+		return nil, err
+	}
+	// the connection is established by the first call, so the relayer can start before the LCP service is up
+	conn, err := dialReconnecting(
 		config.LcpServiceAddress,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-		grpc.WithTimeout(config.GetDialTimeout()),
+		config.GetDialTimeout(),
+		config.GetReconnectTimeout(),
+		append([]grpc.DialOption{
+			grpc.WithTransportCredentials(creds),
+		}, lcpServiceDialOptions(GetBuildInfo())...)...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to LCP service: %w", err)
 	}
-	var eip712Signer *EIP712Signer
-	if config.OperatorSigner != nil {
+	var eip712Signer OperatorSigner
+	if config.OperatorEip1271 != nil {
+		eip712Signer, err = newEIP1271SignerFromConfig(config.OperatorEip1271)
+		if err != nil {
+			return nil, err
+		}
+	} else if config.OperatorSigner != nil {
 		signer, err := config.OperatorSigner.GetCachedValue().(signer.SignerConfig).Build()
 		if err != nil {
 			return nil, err
 		}
 		eip712Signer = NewEIP712Signer(signer)
 	}
-	return &Prover{config: config, originChain: originChain, originProver: originProver, lcpServiceClient: NewLCPServiceClient(conn), eip712Signer: eip712Signer}, nil
+	var crl *crlCache
+	if config.IasCrlUrl != "" {
+		crl = newCRLCache(config.IasCrlUrl, config.GetIASCRLRefreshInterval())
+	}
+	alerter, err := newProverAlerter(config)
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := newMetricsSink(config, originChain.ChainID())
+	if err != nil {
+		return nil, err
+	}
+	rateLimiter := newServiceRateLimiter(config, metrics)
+	if s, ok := originProver.(*originProverSet); ok {
+		s.metrics = metrics
+	}
+	return &Prover{
+		config:                           config,
+		originChain:                      originChain,
+		originProver:                     originProver,
+		lcpServiceClient:                 NewLCPServiceClient(conn).withRateLimiter(rateLimiter),
+		rateLimiter:                      rateLimiter,
+		eip712Signer:                     eip712Signer,
+		counterpartyFinalizedHeaderCache: newFinalizedHeaderCache(DefaultFinalizedHeaderCacheTTL),
+		crlCache:                         crl,
+		avrCache:                         sharedAVRCache,
+		alerter:                          alerter,
+		metrics:                          metrics,
+		// the configured bound is assumed until the counterparty LCP client is queried
+		counterpartyMaxUpdateGap: time.Duration(config.MaxUpdateGap) * time.Second,
+	}, nil
 }
 
 func (pr *Prover) GetOriginProver() core.Prover {
@@ -73,7 +201,16 @@ func (pr *Prover) GetOriginProver() core.Prover {
 // Init initializes the chain
 func (pr *Prover) Init(homePath string, timeout time.Duration, codec codec.ProtoCodecMarshaler, debug bool) error {
 	pr.homePath = homePath
+	// the relayer may be built without the module of the LCP prover registering the interfaces
+	if err := ensureInterfacesRegistered(codec.InterfaceRegistry()); err != nil {
+		return err
+	}
+	if err := checkCodec(codec); err != nil {
+		return err
+	}
 	pr.codec = codec
+	bi := GetBuildInfo()
+	pr.getLogger().Info("initialize the LCP prover", "lcp_go_version", bi.Version, "commit", bi.Commit, "modified", bi.Modified, "go_version", bi.GoVersion)
 	if pr.config.IsDebugEnclave {
 		ias.SetAllowDebugEnclaves()
 	}
@@ -86,17 +223,84 @@ func (pr *Prover) Init(homePath string, timeout time.Duration, codec codec.Proto
 	if err := os.MkdirAll(pr.dbPath(), os.ModePerm); err != nil {
 		return err
 	}
-	return nil
+	if err := pr.loadPersistedELCClientID(context.TODO()); err != nil {
+		return err
+	}
+	return pr.initEnclaveMode()
+}
+
+// SetSkipEnclaveModeCheck sets whether the prover accepts the home directory used in another enclave mode.
+// It must be called before SetRelayInfo, e.g. by `lcp migrate-home` which switches the enclave mode of the home directory by itself.
+func (pr *Prover) SetSkipEnclaveModeCheck(skip bool) {
+	pr.skipEnclaveModeCheck = skip
 }
 
 // SetRelayInfo sets source's path and counterparty's info to the chain
 func (pr *Prover) SetRelayInfo(path *core.PathEnd, counterparty *core.ProvableChain, counterpartyPath *core.PathEnd) error {
+	if pr.enclaveModeErr != nil && !pr.skipEnclaveModeCheck {
+		return pr.enclaveModeErr
+	}
 	pr.path = path
+	pr.counterparty = counterparty
+	pr.counterpartyPath = counterpartyPath
+	pr.quotePolicyOverride = nil
+	if counterparty != nil && counterpartyPath != nil {
+		pr.quotePolicyOverride = pr.config.FindQuotePolicyOverride(counterparty.ChainID(), counterpartyPath.ClientID)
+	}
+	if o := pr.quotePolicyOverride; o != nil {
+		pr.getLogger().Info("use the quote policy override for the counterparty", "counterparty_chain_id", o.CounterpartyChainId, "counterparty_client_id", o.CounterpartyClientId, "allowed_quote_statuses", o.AllowedQuoteStatuses, "allowed_advisory_ids", o.AllowedAdvisoryIds)
+	}
 	return nil
 }
 
+// allowedQuoteStatuses returns the quote statuses allowed by the LCP client on the counterparty chain
+func (pr *Prover) allowedQuoteStatuses() []string {
+	if pr.quotePolicyOverride != nil {
+		return pr.quotePolicyOverride.AllowedQuoteStatuses
+	}
+	return pr.config.AllowedQuoteStatuses
+}
+
+// allowedAdvisoryIDs returns the advisory IDs allowed by the LCP client on the counterparty chain
+func (pr *Prover) allowedAdvisoryIDs() []string {
+	if pr.quotePolicyOverride != nil {
+		return pr.quotePolicyOverride.AllowedAdvisoryIds
+	}
+	return pr.config.AllowedAdvisoryIds
+}
+
 // SetupForRelay performs chain-specific setup before starting the relay
 func (pr *Prover) SetupForRelay(ctx context.Context) error {
+	if _, err := pr.discoverServiceCapabilities(ctx); err != nil {
+		return err
+	}
+	if pr.counterparty != nil {
+		lag, err := estimateFinalityLag(pr.counterparty)
+		if err != nil {
+			pr.getLogger().Warn("failed to estimate the finality lag of the counterparty chain", "error", err)
+		} else {
+			pr.counterpartyFinalityLag = lag
+		}
+		if clientState, err := pr.queryBootstrapClientState(ctx, pr.counterparty); err != nil {
+			pr.getLogger().Warn("failed to query the key expiration of the counterparty LCP client", "error", err)
+		} else if clientState != nil {
+			pr.observeCounterpartyKeyExpiration(clientState)
+			pr.observeCounterpartyMessageVersions(clientState)
+		}
+	}
+	pr.getLogger().Info("recommended update interval", "key_expiration", pr.keyExpiration(), "counterparty_finality_lag", pr.counterpartyFinalityLag, "interval", pr.RecommendedUpdateInterval())
+	// the relay works without the diagnostics
+	if err := pr.startDiagnostics(); err != nil {
+		pr.getLogger().Warn("failed to start the diagnostics server", "error", err)
+	}
+	if err := pr.startStats(); err != nil {
+		pr.getLogger().Warn("failed to start recording the path stats", "error", err)
+	}
+	if pr.metrics != nil {
+		if err := pr.metrics.Start(pr.getLogger()); err != nil {
+			pr.getLogger().Warn("failed to start exporting the metrics", "error", err)
+		}
+	}
 	return nil
 }
 
@@ -118,23 +322,39 @@ func (pr *Prover) CreateInitialLightClientState(height exported.Height) (exporte
 		operators = append(operators, op.Bytes())
 	}
 
+	dcapRootCerts, err := pr.dcapRootCerts()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	clientState := &lcptypes.ClientState{
 		LatestHeight:                  clienttypes.Height{},
 		Mrenclave:                     pr.config.GetMrenclave(),
 		KeyExpiration:                 pr.config.KeyExpiration,
-		AllowedQuoteStatuses:          pr.config.AllowedQuoteStatuses,
-		AllowedAdvisoryIds:            pr.config.AllowedAdvisoryIds,
+		MaxUpdateGap:                  pr.config.MaxUpdateGap,
+		AllowedQuoteStatuses:          pr.allowedQuoteStatuses(),
+		AllowedAdvisoryIds:            pr.allowedAdvisoryIDs(),
 		Operators:                     operators,
 		OperatorsNonce:                0,
 		OperatorsThresholdNumerator:   pr.GetOperatorsThreshold().Numerator,
 		OperatorsThresholdDenominator: pr.GetOperatorsThreshold().Denominator,
+		DcapRootCerts:                 dcapRootCerts,
+		AllowedMessageVersions:        pr.config.CounterpartyMessageVersions,
+		MinimumIsvSvn:                 pr.config.MinimumIsvSvn,
 	}
 	consensusState := &lcptypes.ConsensusState{}
+	if err := clientState.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid initial client state: %w", err)
+	}
 
-	if res, err := pr.createELC(pr.config.ElcClientId, height); err != nil {
+	elcClientID, err := pr.ensureELCClientID(context.TODO())
+	if err != nil {
+		return nil, nil, err
+	}
+	if res, err := pr.createELC(elcClientID, height); err != nil {
 		return nil, nil, fmt.Errorf("failed to create ELC: %w", err)
 	} else if res == nil {
-		pr.getLogger().Info("no need to create ELC", "elc_client_id", pr.config.ElcClientId)
+		pr.getLogger().Info("no need to create ELC", "elc_client_id", elcClientID)
 	}
 
 	// NOTE after creates client, register an enclave key into the client state
@@ -150,11 +370,64 @@ func (pr *Prover) GetLatestFinalizedHeader() (core.Header, error) {
 // SetupHeadersForUpdate returns the finalized header and any intermediate headers needed to apply it to the client on the counterpaty chain
 // The order of the returned header slice should be as: [<intermediate headers>..., <update header>]
 // if the header slice's length == nil and err == nil, the relayer should skips the update-client
+// The calls to the LCP service are bounded by the deadline derived from `update_client_timeout`.
+// If the latest height of the LCP client on the counterparty chain has regressed, it returns CounterpartyClientHeightRegressionError.
 func (pr *Prover) SetupHeadersForUpdate(dstChain core.FinalityAwareChain, latestFinalizedHeader core.Header) ([]core.Header, error) {
-	if err := pr.UpdateEKIfNeeded(context.TODO(), dstChain); err != nil {
+	ctx, cancel, deadline := withOperationDeadline(context.TODO(), operationUpdateClient, pr.config.GetUpdateClientTimeout())
+	defer cancel()
+	pr.watchAttestationPolicy(ctx, time.Now())
+	pr.collectGarbagePeriodically(ctx, time.Now())
+	pr.checkClockSkewPeriodically(ctx, dstChain, time.Now())
+	pr.resumeSubmissionQueue(dstChain)
+	if err := pr.checkCounterpartyClientHeight(ctx, dstChain); err != nil {
+		return nil, deadline.wrapError(ctx, err)
+	}
+	updates, err := pr.setupHeadersForUpdateWithEK(ctx, dstChain, latestFinalizedHeader)
+	if err != nil {
+		return nil, deadline.wrapError(ctx, err)
+	}
+	if len(updates) > 0 {
+		pr.recordStats(dstChain, statsEventUpdatesSubmitted, uint64(len(updates)))
+	}
+	return updates, nil
+}
+
+func (pr *Prover) setupHeadersForUpdateWithEK(ctx context.Context, dstChain core.FinalityAwareChain, latestFinalizedHeader core.Header) ([]core.Header, error) {
+	var headers, updates []core.Header
+	bundled, err := pr.updateEKIfNeeded(ctx, dstChain, func() ([]core.Header, error) {
+		var err error
+		if headers, err = pr.setupOriginHeadersForUpdate(ctx, dstChain, latestFinalizedHeader); err != nil || len(headers) == 0 {
+			return nil, err
+		}
+		updates, err = pr.generateUpdates(ctx, dstChain, headers)
+		return updates, err
+	})
+	if err != nil {
 		return nil, err
+	} else if bundled {
+		// the updates have been submitted with the registration of the enclave key
+		pr.recordStats(dstChain, statsEventUpdatesSubmitted, uint64(len(updates)))
+		return nil, nil
+	} else if updates != nil {
+		// the active enclave key may have been rotated since the updates were prepared for the bundling
+		return pr.regenerateStaleUpdates(updates, func() ([]core.Header, error) {
+			return pr.generateUpdates(ctx, dstChain, headers)
+		})
 	}
+	return pr.setupHeadersForUpdate(ctx, dstChain, latestFinalizedHeader)
+}
 
+// setupHeadersForUpdate returns the update messages generated by the ELC with the active enclave key
+func (pr *Prover) setupHeadersForUpdate(ctx context.Context, dstChain core.FinalityAwareChain, latestFinalizedHeader core.Header) ([]core.Header, error) {
+	headers, err := pr.setupOriginHeadersForUpdate(ctx, dstChain, latestFinalizedHeader)
+	if err != nil || len(headers) == 0 {
+		return nil, err
+	}
+	return pr.generateUpdates(ctx, dstChain, headers)
+}
+
+// setupOriginHeadersForUpdate returns the headers of the origin chain which the ELC client can apply to reach `latestFinalizedHeader`
+func (pr *Prover) setupOriginHeadersForUpdate(ctx context.Context, dstChain core.FinalityAwareChain, latestFinalizedHeader core.Header) ([]core.Header, error) {
 	headers, err := pr.originProver.SetupHeadersForUpdate(dstChain, latestFinalizedHeader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup headers for update: header=%v %w", latestFinalizedHeader, err)
@@ -162,41 +435,69 @@ func (pr *Prover) SetupHeadersForUpdate(dstChain core.FinalityAwareChain, latest
 	if len(headers) == 0 {
 		return nil, nil
 	}
+	elcClientID := pr.GetELCClientID()
+	elcLatestHeight, err := pr.queryELCLatestHeight(ctx, elcClientID)
+	if err != nil {
+		return nil, err
+	}
+	return pr.filterHeadersForELC(elcClientID, headers, elcLatestHeight), nil
+}
+
+// generateUpdates applies `headers` to the ELC client and returns the update messages signed by the active enclave key
+func (pr *Prover) generateUpdates(ctx context.Context, dstChain core.FinalityAwareChain, headers []core.Header) ([]core.Header, error) {
+	elcClientID := pr.GetELCClientID()
 	var (
 		messages   [][]byte
 		signatures [][]byte
+		// the decoded messages to check the timestamps
+		updateStates []*lcptypes.UpdateStateProxyMessage
 	)
-	for i, h := range headers {
-		anyHeader, err := clienttypes.PackClientMessage(h)
-		if err != nil {
-			return nil, fmt.Errorf("failed to pack header: i=%v header=%v %w", i, h, err)
+	signer := pr.activeEnclaveKey.EnclaveKeyAddress
+	if err := pr.updateELCClient(ctx, elcClientID, headers, false, func(i int, res *elc.MsgUpdateClientResponse) error {
+		if err := verifyEnclaveSignature(res.Message, res.Signature, signer); err != nil {
+			return fmt.Errorf("failed to verify the response of ELC's UpdateClient: i=%v elc_client_id=%v %w", i, elcClientID, err)
 		}
-		m := elc.MsgUpdateClient{
-			ClientId:     pr.config.ElcClientId,
-			Header:       anyHeader,
-			IncludeState: false,
-			Signer:       pr.activeEnclaveKey.EnclaveKeyAddress,
+		if err := lcptypes.ValidateProxyMessageSize(res.Message); err != nil {
+			return fmt.Errorf("the LCP client rejects the message of ELC's UpdateClient; the ELC emitted an unexpectedly large message: i=%v elc_client_id=%v %w", i, elcClientID, err)
 		}
-		res, err := pr.lcpServiceClient.UpdateClient(context.TODO(), &m)
+		// ensure the message is valid
+		msg, err := lcptypes.EthABIDecodeHeaderedProxyMessage(res.Message)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update ELC: i=%v elc_client_id=%v msg=%v %w", i, pr.config.ElcClientId, m, err)
+			return fmt.Errorf("failed to decode headered proxy message: i=%v message=%x %w", i, res.Message, err)
 		}
-		// ensure the message is valid
-		if _, err := lcptypes.EthABIDecodeHeaderedProxyMessage(res.Message); err != nil {
-			return nil, fmt.Errorf("failed to decode headered proxy message: i=%v message=%x %w", i, res.Message, err)
+		if err := pr.checkMessageVersion(msg); err != nil {
+			return fmt.Errorf("failed to check the message version: i=%v %w", i, err)
+		}
+		pr.checkValidationContextExpiry(msg, time.Now())
+		updateState, err := msg.GetUpdateStateProxyMessage()
+		if err != nil {
+			return fmt.Errorf("failed to get update state proxy message: i=%v %w", i, err)
 		}
 		messages = append(messages, res.Message)
 		signatures = append(signatures, res.Signature)
+		updateStates = append(updateStates, updateState)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := pr.checkTimestampMonotonicity(ctx, dstChain, updateStates); err != nil {
+		return nil, err
+	}
+	if err := pr.checkValidationContexts(ctx, dstChain, updateStates); err != nil {
+		return nil, err
 	}
 
 	var updates []core.Header
 	// NOTE: assume that the messages length and the signatures length are the same
 	if pr.config.MessageAggregation {
 		pr.getLogger().Info("aggregate messages", "num_messages", len(messages))
-		update, err := aggregateMessages(pr.getLogger(), pr.config.GetMessageAggregationBatchSize(), pr.lcpServiceClient.AggregateMessages, messages, signatures, pr.activeEnclaveKey.EnclaveKeyAddress)
+		update, err := aggregateMessages(ctx, pr.getLogger(), pr.config.GetMessageAggregationBatchSize(), pr.lcpServiceClient.AggregateMessages, messages, signatures, pr.activeEnclaveKey.EnclaveKeyAddress)
 		if err != nil {
 			return nil, err
 		}
+		if err := lcptypes.ValidateProxyMessageSize(update.ProxyMessage); err != nil {
+			return nil, fmt.Errorf("the LCP client rejects the aggregated message: num_messages=%v %w", len(messages), err)
+		}
 		updates = append(updates, update)
 	} else {
 		pr.getLogger().Info("updateClient", "num_messages", len(messages))
@@ -213,6 +514,7 @@ func (pr *Prover) SetupHeadersForUpdate(dstChain core.FinalityAwareChain, latest
 type MessageAggregator func(ctx context.Context, in *elc.MsgAggregateMessages, opts ...grpc.CallOption) (*elc.MsgAggregateMessagesResponse, error)
 
 func aggregateMessages(
+	ctx context.Context,
 	logger *log.RelayLogger,
 	batchSize uint64,
 	messageAggregator MessageAggregator,
@@ -244,7 +546,7 @@ func aggregateMessages(
 					Messages:   batches[0].Messages,
 					Signatures: batches[0].Signatures,
 				}
-				resp, err := messageAggregator(context.TODO(), &m)
+				resp, err := messageAggregator(ctx, &m)
 				if err != nil {
 					return nil, fmt.Errorf("failed to aggregate messages: msg=%v %w", m, err)
 				}
@@ -271,7 +573,7 @@ func aggregateMessages(
 					Messages:   b.Messages,
 					Signatures: b.Signatures,
 				}
-				resp, err := messageAggregator(context.TODO(), &m)
+				resp, err := messageAggregator(ctx, &m)
 				if err != nil {
 					return nil, fmt.Errorf("failed to aggregate messages: batch_index=%v msg=%v %w", i, m, err)
 				}
@@ -311,17 +613,68 @@ func splitIntoMultiBatch(messages [][]byte, signatures [][]byte, signer []byte,
 	return res, nil
 }
 
+// CheckRefreshRequired returns true if the origin prover requires a refresh or
+// the LCP client on the counterparty chain requires an update to keep its latest consensus state and the active enclave key alive
 func (pr *Prover) CheckRefreshRequired(counterparty core.ChainInfoICS02Querier) (bool, error) {
-	return pr.originProver.CheckRefreshRequired(counterparty)
+	if required, err := pr.originProver.CheckRefreshRequired(counterparty); err != nil || required {
+		return required, err
+	}
+	return pr.checkCounterpartyClientRefreshRequired(counterparty)
 }
 
+// ProveState returns a commitment proof of `value` at `path` verified by the ELC.
+// The call to the LCP service is bounded by the deadline derived from `prove_state_timeout`.
 func (pr *Prover) ProveState(ctx core.QueryContext, path string, value []byte) ([]byte, clienttypes.Height, error) {
+	path, err := canonicalCommitmentPath(path)
+	if err != nil {
+		return nil, clienttypes.Height{}, err
+	}
+	pr.watchAttestationPolicy(ctx.Context(), time.Now())
+	if err := pr.checkPolicyDriftHalt(); err != nil {
+		return nil, clienttypes.Height{}, err
+	}
+	opCtx, cancel, deadline := withOperationDeadline(ctx.Context(), operationProveState, pr.config.GetProveStateTimeout())
+	defer cancel()
+	start := time.Now()
+	proof, proofHeight, err := pr.proveStateWithELC(core.NewQueryContext(opCtx, ctx.Height()), pr.GetELCClientID(), path, value)
+	pr.recordVerifyMembershipDuration(ctx.Context(), start, err)
+	if err != nil {
+		return nil, clienttypes.Height{}, deadline.wrapError(opCtx, err)
+	}
+	if err := pr.archiveProof(&ArchivedProof{
+		ELCClientID:    pr.GetELCClientID(),
+		Path:           path,
+		Value:          value,
+		ProofHeight:    proofHeight,
+		Proof:          proof,
+		Signer:         pr.activeEnclaveKey.EnclaveKeyAddress,
+		Timestamp:      time.Now(),
+		RelayerVersion: GetBuildInfo().String(),
+	}); err != nil {
+		return nil, clienttypes.Height{}, fmt.Errorf("failed to archive the proof: path=%v %w", path, err)
+	}
+	if pr.counterparty != nil {
+		pr.recordStats(pr.counterparty, statsEventProofGenerated, 1)
+	}
+	return proof, proofHeight, nil
+}
+
+// proveStateWithELC returns a commitment proof of `value` at `path` verified by the ELC client `elcClientID`
+func (pr *Prover) proveStateWithELC(ctx core.QueryContext, elcClientID string, path string, value []byte) ([]byte, clienttypes.Height, error) {
+	path, err := canonicalCommitmentPath(path)
+	if err != nil {
+		return nil, clienttypes.Height{}, err
+	}
 	proof, proofHeight, err := pr.originProver.ProveState(ctx, path, value)
 	if err != nil {
 		return nil, clienttypes.Height{}, fmt.Errorf("failed originProver.ProveState: path=%v value=%x %w", path, value, err)
 	}
+	// an empty value means that the relayer requests a proof of the absence of the value, e.g. a packet receipt
+	if len(value) == 0 {
+		return pr.proveNonMembershipWithELC(ctx, elcClientID, path, proof, proofHeight)
+	}
 	m := elc.MsgVerifyMembership{
-		ClientId:    pr.config.ElcClientId,
+		ClientId:    elcClientID,
 		Prefix:      []byte(exported.StoreKey),
 		Path:        path,
 		Value:       value,
@@ -331,16 +684,26 @@ func (pr *Prover) ProveState(ctx core.QueryContext, path string, value []byte) (
 	}
 	res, err := pr.lcpServiceClient.VerifyMembership(ctx.Context(), &m)
 	if err != nil {
-		return nil, clienttypes.Height{}, fmt.Errorf("failed ELC's VerifyMembership: elc_client_id=%v msg=%v %w", pr.config.ElcClientId, m, err)
+		return nil, clienttypes.Height{}, fmt.Errorf("failed ELC's VerifyMembership: elc_client_id=%v msg=%v %w", elcClientID, m, err)
+	}
+	if err := verifyEnclaveSignature(res.Message, res.Signature, m.Signer); err != nil {
+		return nil, clienttypes.Height{}, fmt.Errorf("failed to verify the response of ELC's VerifyMembership: elc_client_id=%v %w", elcClientID, err)
 	}
 	message, err := lcptypes.EthABIDecodeHeaderedProxyMessage(res.Message)
 	if err != nil {
 		return nil, clienttypes.Height{}, fmt.Errorf("failed to decode headered proxy message: message=%x %w", res.Message, err)
 	}
+	if err := pr.checkMessageVersion(message); err != nil {
+		return nil, clienttypes.Height{}, fmt.Errorf("failed to check the message version: %w", err)
+	}
 	sc, err := message.GetVerifyMembershipProxyMessage()
 	if err != nil {
 		return nil, clienttypes.Height{}, fmt.Errorf("failed GetVerifyMembershipProxyMessage: message=%x %w", res.Message, err)
 	}
+	// the counterparty client compares the path in the message with the canonical one built by ibc-go
+	if string(sc.Path) != path {
+		return nil, clienttypes.Height{}, fmt.Errorf("unexpected path in the message of ELC's VerifyMembership: expected=%q got=%q", path, sc.Path)
+	}
 	cp, err := lcptypes.EthABIEncodeCommitmentProofs(&lcptypes.CommitmentProofs{
 		Message:    res.Message,
 		Signatures: [][]byte{res.Signature},
@@ -351,6 +714,55 @@ func (pr *Prover) ProveState(ctx core.QueryContext, path string, value []byte) (
 	return cp, sc.Height, nil
 }
 
+// proveNonMembershipWithELC returns a commitment proof of the absence of a value at `path` verified by the ELC client `elcClientID`.
+// `proof` and `proofHeight` are the absence proof of the origin chain, and `proofHeight` is returned as it is.
+func (pr *Prover) proveNonMembershipWithELC(ctx core.QueryContext, elcClientID string, path string, proof []byte, proofHeight clienttypes.Height) ([]byte, clienttypes.Height, error) {
+	m := elc.MsgVerifyNonMembership{
+		ClientId:    elcClientID,
+		Prefix:      []byte(exported.StoreKey),
+		Path:        path,
+		ProofHeight: proofHeight,
+		Proof:       proof,
+		Signer:      pr.activeEnclaveKey.EnclaveKeyAddress,
+	}
+	if err := pr.checkServiceCapability(CapabilityVerifyNonMembership); err != nil {
+		return nil, clienttypes.Height{}, err
+	}
+	res, err := pr.lcpServiceClient.VerifyNonMembership(ctx.Context(), &m)
+	if err != nil {
+		return nil, clienttypes.Height{}, fmt.Errorf("failed ELC's VerifyNonMembership: elc_client_id=%v msg=%v %w", elcClientID, m, err)
+	}
+	if err := verifyEnclaveSignature(res.Message, res.Signature, m.Signer); err != nil {
+		return nil, clienttypes.Height{}, fmt.Errorf("failed to verify the response of ELC's VerifyNonMembership: elc_client_id=%v %w", elcClientID, err)
+	}
+	message, err := lcptypes.EthABIDecodeHeaderedProxyMessage(res.Message)
+	if err != nil {
+		return nil, clienttypes.Height{}, fmt.Errorf("failed to decode headered proxy message: message=%x %w", res.Message, err)
+	}
+	if err := pr.checkMessageVersion(message); err != nil {
+		return nil, clienttypes.Height{}, fmt.Errorf("failed to check the message version: %w", err)
+	}
+	sc, err := message.GetVerifyNonMembershipProxyMessage()
+	if err != nil {
+		return nil, clienttypes.Height{}, fmt.Errorf("failed GetVerifyNonMembershipProxyMessage: message=%x %w", res.Message, err)
+	}
+	if string(sc.Path) != path {
+		return nil, clienttypes.Height{}, fmt.Errorf("unexpected path in the message of ELC's VerifyNonMembership: expected=%q got=%q", path, sc.Path)
+	}
+	// the counterparty client verifies the proof at the proof height of the origin chain
+	if !sc.Height.EQ(proofHeight) {
+		return nil, clienttypes.Height{}, fmt.Errorf("unexpected height in the message of ELC's VerifyNonMembership: expected=%v got=%v", proofHeight, sc.Height)
+	}
+	cp, err := lcptypes.EthABIEncodeCommitmentProofs(&lcptypes.CommitmentProofs{
+		Message:    res.Message,
+		Signatures: [][]byte{res.Signature},
+	})
+	if err != nil {
+		return nil, clienttypes.Height{}, fmt.Errorf("failed to encode commitment proof: %w", err)
+	}
+	return cp, proofHeight, nil
+}
+
 // ProveHostConsensusState returns an existence proof of the consensus state at `height`
 // This proof would be ignored in ibc-go, but it is required to `getSelfConsensusState` of ibc-solidity.
 func (pr *Prover) ProveHostConsensusState(ctx core.QueryContext, height exported.Height, consensusState exported.ConsensusState) (proof []byte, err error) {
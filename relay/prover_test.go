@@ -5,7 +5,12 @@ import (
 	"fmt"
 	"testing"
 
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
 	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
 	"github.com/hyperledger-labs/yui-relayer/log"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -177,7 +182,7 @@ func TestAggregateMessages(t *testing.T) {
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
 			require := require.New(t)
-			res, err := aggregateMessages(logger, c.BatchSize, mockMessageAggregator, c.Messages, c.Signatures, c.Signer)
+			res, err := aggregateMessages(context.TODO(), logger, c.BatchSize, mockMessageAggregator, c.Messages, c.Signatures, c.Signer)
 			if c.Error {
 				require.Error(err)
 				return
@@ -214,3 +219,43 @@ func mockMessageAggregator(_ context.Context, in *elc.MsgAggregateMessages, _ ..
 	}
 	return &res, nil
 }
+
+func TestProveNonMembership(t *testing.T) {
+	require := require.New(t)
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	pr := newTestProver(t)
+	pr.originProver = mockOriginProver{}
+	pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: crypto.PubkeyToAddress(key.PublicKey).Bytes()}
+	pr.lcpServiceClient.ELCMsgClient = &mockLCPService{t: t, key: key}
+
+	path := "receipts/ports/transfer/channels/channel-0/sequences/1"
+	ctx := core.NewQueryContext(context.TODO(), clienttypes.NewHeight(0, 5))
+	proof, proofHeight, err := pr.ProveState(ctx, path, nil)
+	require.NoError(err)
+	// the proof height of the origin prover is returned as it is
+	require.Equal(clienttypes.NewHeight(0, 1), proofHeight)
+	cp, err := lcptypes.EthABIDecodeCommitmentProofs(proof)
+	require.NoError(err)
+	m, err := cp.GetMessage()
+	require.NoError(err)
+	msg, err := m.GetVerifyNonMembershipProxyMessage()
+	require.NoError(err)
+	require.Equal(path, string(msg.Path))
+	require.Equal(proofHeight, msg.Height)
+
+	cannedResponse := func(message []byte) mockELCMsgClient {
+		signature, err := crypto.Sign(crypto.Keccak256(message), key)
+		require.NoError(err)
+		return mockELCMsgClient{message: message, signature: signature}
+	}
+	// the ELC commits to the absence at a different height
+	pr.lcpServiceClient.ELCMsgClient = cannedResponse(newTestVerifyNonMembershipMessage(t, path, clienttypes.NewHeight(0, 2)))
+	_, _, err = pr.ProveState(ctx, path, nil)
+	require.ErrorContains(err, "unexpected height in the message of ELC's VerifyNonMembership")
+
+	// the ELC commits to the existence
+	pr.lcpServiceClient.ELCMsgClient = cannedResponse(newTestVerifyMembershipMessage(t, path, []byte("value"), clienttypes.NewHeight(0, 1)))
+	_, _, err = pr.ProveState(ctx, path, nil)
+	require.ErrorContains(err, "unexpected value of non-membership message")
+}
@@ -0,0 +1,212 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+)
+
+// the classes of the calls to the LCP service which are rate limited separately
+const (
+	rpcClassUpdateClient     = "update_client"
+	rpcClassVerifyMembership = "verify_membership"
+	rpcClassQuery            = "query"
+)
+
+// the results of the throttled calls recorded in the metrics
+const (
+	throttleDelayed  = "delayed"
+	throttleRejected = "rejected"
+)
+
+// ErrRateLimited is returned when a call to the LCP service would be delayed by the rate limit beyond its deadline
+var ErrRateLimited = errors.New("LCP service call rate limited")
+
+// serviceRateLimiter limits the rate of the calls to the LCP service per RPC class with token buckets
+type serviceRateLimiter struct {
+	limiters map[string]*rate.Limiter
+	// if true, the calls are not limited
+	bypass atomic.Bool
+	// the sink of the metrics of the throttled calls
+	metrics metricsSink
+}
+
+// newServiceRateLimiter returns a limiter with the rate limits of the config.
+// It returns nil if no rate limit is configured.
+func newServiceRateLimiter(config ProverConfig, metrics metricsSink) *serviceRateLimiter {
+	limiters := make(map[string]*rate.Limiter)
+	for class, rl := range map[string]*RateLimit{
+		rpcClassUpdateClient:     config.UpdateClientRateLimit,
+		rpcClassVerifyMembership: config.VerifyMembershipRateLimit,
+		rpcClassQuery:            config.QueryRateLimit,
+	} {
+		if rl != nil {
+			limiters[class] = rate.NewLimiter(rate.Limit(rl.CallsPerSecond), rl.GetBurst())
+		}
+	}
+	if len(limiters) == 0 {
+		return nil
+	}
+	return &serviceRateLimiter{limiters: limiters, metrics: metrics}
+}
+
+func (rl *RateLimit) GetBurst() int {
+	if rl.Burst == 0 {
+		return 1
+	}
+	return int(rl.Burst)
+}
+
+func (rl *RateLimit) Validate() error {
+	if math.IsNaN(rl.CallsPerSecond) || math.IsInf(rl.CallsPerSecond, 0) || rl.CallsPerSecond <= 0 {
+		return fmt.Errorf("CallsPerSecond must be a positive number, but got %v", rl.CallsPerSecond)
+	}
+	return nil
+}
+
+// wait blocks until the call of `method` in `class` is allowed by the rate limit.
+// If the call would be delayed beyond the deadline of `ctx`, it returns ErrRateLimited immediately.
+func (l *serviceRateLimiter) wait(ctx context.Context, class string, method string) error {
+	if l == nil || l.bypass.Load() {
+		return nil
+	}
+	limiter, ok := l.limiters[class]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	r := limiter.ReserveN(now, 1)
+	delay := r.DelayFrom(now)
+	if delay == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		r.CancelAt(now)
+		l.countThrottledCall(ctx, class, method, throttleRejected)
+		return fmt.Errorf("%w: class=%v method=%v delay=%v deadline=%v", ErrRateLimited, class, method, delay, deadline)
+	}
+	l.countThrottledCall(ctx, class, method, throttleDelayed)
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// countThrottledCall increments the counter of the calls to the LCP service throttled by the rate limit.
+// The counter is recorded with the metrics sink of the prover.
+func (l *serviceRateLimiter) countThrottledCall(ctx context.Context, class string, method string, result string) {
+	counter, err := meterOf(l.metrics).Int64Counter(
+		"lcp.service_throttled_calls",
+		metric.WithUnit("1"),
+		metric.WithDescription("number of calls to the LCP service throttled by the client-side rate limit"),
+	)
+	if err != nil {
+		log.GetLogger().WithModule(ModuleName).Warn("failed to create the counter of the throttled calls", "error", err)
+		return
+	}
+	counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("class", class),
+		attribute.String("method", method),
+		attribute.String("result", result),
+	))
+}
+
+// withRateLimiter returns the client whose calls are limited by `limiter`
+func (c LCPServiceClient) withRateLimiter(limiter *serviceRateLimiter) LCPServiceClient {
+	if limiter == nil {
+		return c
+	}
+	return LCPServiceClient{
+		ELCMsgClient:       rateLimitedELCMsgClient{MsgClient: c.ELCMsgClient, limiter: limiter},
+		ELCQueryClient:     rateLimitedELCQueryClient{QueryClient: c.ELCQueryClient, limiter: limiter},
+		EnclaveQueryClient: rateLimitedEnclaveQueryClient{QueryClient: c.EnclaveQueryClient, limiter: limiter},
+	}
+}
+
+// bypassRateLimit disables the rate limit of the calls to the LCP service.
+// This is used by the interactive CLI commands which issue only a few calls.
+func (pr *Prover) bypassRateLimit() {
+	if pr.rateLimiter != nil {
+		pr.rateLimiter.bypass.Store(true)
+	}
+}
+
+type rateLimitedELCMsgClient struct {
+	elc.MsgClient
+	limiter *serviceRateLimiter
+}
+
+func (c rateLimitedELCMsgClient) UpdateClient(ctx context.Context, in *elc.MsgUpdateClient, opts ...grpc.CallOption) (*elc.MsgUpdateClientResponse, error) {
+	if err := c.limiter.wait(ctx, rpcClassUpdateClient, "UpdateClient"); err != nil {
+		return nil, err
+	}
+	return c.MsgClient.UpdateClient(ctx, in, opts...)
+}
+
+func (c rateLimitedELCMsgClient) AggregateMessages(ctx context.Context, in *elc.MsgAggregateMessages, opts ...grpc.CallOption) (*elc.MsgAggregateMessagesResponse, error) {
+	if err := c.limiter.wait(ctx, rpcClassUpdateClient, "AggregateMessages"); err != nil {
+		return nil, err
+	}
+	return c.MsgClient.AggregateMessages(ctx, in, opts...)
+}
+
+func (c rateLimitedELCMsgClient) VerifyMembership(ctx context.Context, in *elc.MsgVerifyMembership, opts ...grpc.CallOption) (*elc.MsgVerifyMembershipResponse, error) {
+	if err := c.limiter.wait(ctx, rpcClassVerifyMembership, "VerifyMembership"); err != nil {
+		return nil, err
+	}
+	return c.MsgClient.VerifyMembership(ctx, in, opts...)
+}
+
+func (c rateLimitedELCMsgClient) VerifyNonMembership(ctx context.Context, in *elc.MsgVerifyNonMembership, opts ...grpc.CallOption) (*elc.MsgVerifyNonMembershipResponse, error) {
+	if err := c.limiter.wait(ctx, rpcClassVerifyMembership, "VerifyNonMembership"); err != nil {
+		return nil, err
+	}
+	return c.MsgClient.VerifyNonMembership(ctx, in, opts...)
+}
+
+type rateLimitedELCQueryClient struct {
+	elc.QueryClient
+	limiter *serviceRateLimiter
+}
+
+func (c rateLimitedELCQueryClient) Client(ctx context.Context, in *elc.QueryClientRequest, opts ...grpc.CallOption) (*elc.QueryClientResponse, error) {
+	if err := c.limiter.wait(ctx, rpcClassQuery, "Client"); err != nil {
+		return nil, err
+	}
+	return c.QueryClient.Client(ctx, in, opts...)
+}
+
+type rateLimitedEnclaveQueryClient struct {
+	enclave.QueryClient
+	limiter *serviceRateLimiter
+}
+
+func (c rateLimitedEnclaveQueryClient) AvailableEnclaveKeys(ctx context.Context, in *enclave.QueryAvailableEnclaveKeysRequest, opts ...grpc.CallOption) (*enclave.QueryAvailableEnclaveKeysResponse, error) {
+	if err := c.limiter.wait(ctx, rpcClassQuery, "AvailableEnclaveKeys"); err != nil {
+		return nil, err
+	}
+	return c.QueryClient.AvailableEnclaveKeys(ctx, in, opts...)
+}
+
+func (c rateLimitedEnclaveQueryClient) EnclaveKey(ctx context.Context, in *enclave.QueryEnclaveKeyRequest, opts ...grpc.CallOption) (*enclave.QueryEnclaveKeyResponse, error) {
+	if err := c.limiter.wait(ctx, rpcClassQuery, "EnclaveKey"); err != nil {
+		return nil, err
+	}
+	return c.QueryClient.EnclaveKey(ctx, in, opts...)
+}
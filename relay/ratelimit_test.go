@@ -0,0 +1,141 @@
+package relay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// throttledCallCount returns the value of the throttled calls counter for the class and the result
+func throttledCallCount(t *testing.T, reader sdkmetric.Reader, class string, result string) int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var count int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "lcp.service_throttled_calls" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				c, _ := dp.Attributes.Value(attribute.Key("class"))
+				r, _ := dp.Attributes.Value(attribute.Key("result"))
+				if c.AsString() == class && r.AsString() == result {
+					count += dp.Value
+				}
+			}
+		}
+	}
+	return count
+}
+
+// mockBurstOriginProver is the prover of an origin chain which requires `numHeaders` headers for an update
+type mockBurstOriginProver struct {
+	mockSelfTestOriginProver
+	numHeaders int
+}
+
+func (p mockBurstOriginProver) SetupHeadersForUpdate(counterparty core.FinalityAwareChain, latestFinalizedHeader core.Header) ([]core.Header, error) {
+	return newTestOriginHeaders(latestFinalizedHeader.GetHeight(), p.numHeaders)
+}
+
+func TestServiceRateLimit(t *testing.T) {
+	const (
+		elcClientID = "07-tendermint-0"
+		numHeaders  = 5
+	)
+	newProver := func(t *testing.T, rateLimit *RateLimit) *Prover {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		service := &mockLCPService{t: t, key: key, clients: map[string]*lcptypes.ClientState{
+			elcClientID: {LatestHeight: clienttypes.NewHeight(0, 1)},
+		}}
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.config.ElcClientId = elcClientID
+		pr.config.UpdateClientRateLimit = rateLimit
+		pr.originProver = mockBurstOriginProver{numHeaders: numHeaders}
+		pr.rateLimiter = newServiceRateLimiter(pr.config, nil)
+		pr.lcpServiceClient = LCPServiceClient{
+			ELCMsgClient:       service,
+			ELCQueryClient:     service,
+			EnclaveQueryClient: mockEnclaveQueryClient{},
+		}.withRateLimiter(pr.rateLimiter)
+		pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: crypto.PubkeyToAddress(key.PublicKey).Bytes()}
+		return pr
+	}
+	counterparty := newMockCounterparty(clienttypes.NewHeight(0, 1))
+	header := mockHeader{height: clienttypes.NewHeight(0, 1+numHeaders)}
+
+	t.Run("burst is delayed", func(t *testing.T) {
+		require := require.New(t)
+		reader := sdkmetric.NewManualReader()
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+		pr := newProver(t, &RateLimit{CallsPerSecond: 20, Burst: 2})
+
+		start := time.Now()
+		updates, err := pr.setupHeadersForUpdate(context.TODO(), counterparty, header)
+		require.NoError(err)
+		require.Len(updates, numHeaders)
+		// the calls exceeding the burst wait for the tokens
+		require.GreaterOrEqual(time.Since(start), 100*time.Millisecond)
+		require.Equal(int64(numHeaders-2), throttledCallCount(t, reader, rpcClassUpdateClient, throttleDelayed))
+		require.Zero(throttledCallCount(t, reader, rpcClassUpdateClient, throttleRejected))
+	})
+
+	t.Run("delay beyond the deadline", func(t *testing.T) {
+		require := require.New(t)
+		reader := sdkmetric.NewManualReader()
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+		pr := newProver(t, &RateLimit{CallsPerSecond: 0.1, Burst: 2})
+
+		ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+		defer cancel()
+		start := time.Now()
+		_, err := pr.setupHeadersForUpdate(ctx, counterparty, header)
+		require.ErrorIs(err, ErrRateLimited)
+		require.ErrorContains(err, "class=update_client method=UpdateClient")
+		// fail fast without waiting for the deadline
+		require.Less(time.Since(start), 500*time.Millisecond)
+		require.Equal(int64(1), throttledCallCount(t, reader, rpcClassUpdateClient, throttleRejected))
+	})
+
+	t.Run("bypass", func(t *testing.T) {
+		require := require.New(t)
+		reader := sdkmetric.NewManualReader()
+		otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+		pr := newProver(t, &RateLimit{CallsPerSecond: 0.1, Burst: 1})
+		pr.bypassRateLimit()
+
+		updates, err := pr.setupHeadersForUpdate(context.TODO(), counterparty, header)
+		require.NoError(err)
+		require.Len(updates, numHeaders)
+		require.Zero(throttledCallCount(t, reader, rpcClassUpdateClient, throttleDelayed))
+	})
+
+	t.Run("not limited", func(t *testing.T) {
+		pr := newProver(t, nil)
+		require.Nil(t, pr.rateLimiter)
+		updates, err := pr.setupHeadersForUpdate(context.TODO(), counterparty, header)
+		require.NoError(t, err)
+		require.Len(t, updates, numHeaders)
+	})
+}
+
+func TestRateLimitValidate(t *testing.T) {
+	require.NoError(t, (&RateLimit{CallsPerSecond: 0.5}).Validate())
+	require.Error(t, (&RateLimit{}).Validate())
+	require.Error(t, (&RateLimit{CallsPerSecond: -1}).Validate())
+	require.Equal(t, 1, (&RateLimit{CallsPerSecond: 1}).GetBurst())
+	require.Equal(t, 3, (&RateLimit{CallsPerSecond: 1, Burst: 3}).GetBurst())
+}
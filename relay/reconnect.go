@@ -0,0 +1,146 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// the backoff between the attempts to re-dial the LCP service
+const (
+	reconnectInitialBackoff = 100 * time.Millisecond
+	reconnectMaxBackoff     = 5 * time.Second
+)
+
+// reconnectingConn is a connection to the LCP service which is re-dialed when the service becomes unavailable, e.g. it is restarted.
+// The dial options, including the transport credentials, are preserved across the re-dials.
+type reconnectingConn struct {
+	target string
+	opts   []grpc.DialOption
+	// the deadline of each attempt to connect
+	dialTimeout time.Duration
+	// the deadline of re-dialing after the connection is lost
+	reconnectTimeout time.Duration
+
+	mu   sync.Mutex
+	conn *grpc.ClientConn
+	// serializes the re-dials
+	reconnectMu sync.Mutex
+}
+
+var _ grpc.ClientConnInterface = (*reconnectingConn)(nil)
+
+// dialReconnecting returns a connection to `target` without waiting for it to be established,
+// so the errors of the connection are deferred to the first call.
+func dialReconnecting(target string, dialTimeout, reconnectTimeout time.Duration, opts ...grpc.DialOption) (*reconnectingConn, error) {
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &reconnectingConn{
+		target:           target,
+		opts:             opts,
+		dialTimeout:      dialTimeout,
+		reconnectTimeout: reconnectTimeout,
+		conn:             conn,
+	}, nil
+}
+
+// isConnectionLost returns true if `err` indicates that the LCP service cannot be reached, e.g. the connection is refused
+func isConnectionLost(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+func (c *reconnectingConn) current() *grpc.ClientConn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn
+}
+
+// Invoke performs a unary call. If the service is unavailable, it re-dials the service and retries the call once.
+func (c *reconnectingConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	conn := c.current()
+	err := conn.Invoke(ctx, method, args, reply, opts...)
+	if !isConnectionLost(err) || ctx.Err() != nil {
+		return err
+	}
+	log.GetLogger().WithModule(ModuleName).Warn("the connection to the LCP service is lost, re-dialing", "target", c.target, "method", method, "error", err)
+	if rerr := c.reconnect(ctx, conn); rerr != nil {
+		return fmt.Errorf("failed to reconnect to LCP service: target=%v %v: %w", c.target, rerr, err)
+	}
+	return c.current().Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream opens a stream on the current connection. The streams are not retried.
+func (c *reconnectingConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.current().NewStream(ctx, desc, method, opts...)
+}
+
+// Close closes the current connection
+func (c *reconnectingConn) Close() error {
+	return c.current().Close()
+}
+
+// reconnect replaces `stale` with a new connection which is ready, re-dialing with backoff until reconnectTimeout elapses.
+// If another call has already replaced `stale`, it returns immediately.
+func (c *reconnectingConn) reconnect(ctx context.Context, stale *grpc.ClientConn) error {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+	if c.current() != stale {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.reconnectTimeout)
+	defer cancel()
+	backoff := reconnectInitialBackoff
+	for attempt := 1; ; attempt++ {
+		conn, err := c.dial(ctx)
+		if err == nil {
+			c.mu.Lock()
+			c.conn = conn
+			c.mu.Unlock()
+			stale.Close()
+			log.GetLogger().WithModule(ModuleName).Info("reconnected to the LCP service", "target", c.target, "attempts", attempt)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up after %v attempts: %w", attempt, err)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > reconnectMaxBackoff {
+			backoff = reconnectMaxBackoff
+		}
+	}
+}
+
+// dial returns a new connection after it becomes ready within dialTimeout
+func (c *reconnectingConn) dial(ctx context.Context) (*grpc.ClientConn, error) {
+	conn, err := grpc.Dial(c.target, c.opts...)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.dialTimeout)
+	defer cancel()
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return conn, nil
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			conn.Close()
+			return nil, fmt.Errorf("failed to connect: state=%v", state)
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			conn.Close()
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package relay
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// testLCPServer is an LCP service listening on a local address, which can be stopped and restarted on the same address
+type testLCPServer struct {
+	t        *testing.T
+	addr     string
+	server   *grpc.Server
+	received chan metadata.MD
+}
+
+func newTestLCPServer(t *testing.T) *testLCPServer {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &testLCPServer{t: t, addr: lis.Addr().String(), received: make(chan metadata.MD, 16)}
+	// the address is reserved until the server is started
+	require.NoError(t, lis.Close())
+	t.Cleanup(s.stop)
+	return s
+}
+
+func (s *testLCPServer) start() {
+	lis, err := net.Listen("tcp", s.addr)
+	require.NoError(s.t, err)
+	s.server = grpc.NewServer()
+	elc.RegisterQueryServer(s.server, &metadataRecordingELCQueryServer{received: s.received})
+	go s.server.Serve(lis)
+}
+
+func (s *testLCPServer) stop() {
+	if s.server != nil {
+		s.server.Stop()
+		s.server = nil
+	}
+}
+
+func TestReconnectingConn(t *testing.T) {
+	require.NoError(t, log.InitLogger("DEBUG", "text", "stdout"))
+	bi := BuildInfo{Version: "v1.2.3"}
+	dial := func(t *testing.T, addr string, reconnectTimeout time.Duration) LCPServiceClient {
+		conn, err := dialReconnecting(addr, time.Second, reconnectTimeout, append([]grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		}, lcpServiceDialOptions(bi)...)...)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return NewLCPServiceClient(conn)
+	}
+	query := func(client LCPServiceClient) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := client.Client(ctx, &elc.QueryClientRequest{ClientId: "07-tendermint-0"})
+		return err
+	}
+
+	t.Run("the service starts after the relayer", func(t *testing.T) {
+		require := require.New(t)
+		srv := newTestLCPServer(t)
+		client := dial(t, srv.addr, 5*time.Second)
+		time.AfterFunc(300*time.Millisecond, srv.start)
+		require.NoError(query(client))
+	})
+
+	t.Run("the service is restarted", func(t *testing.T) {
+		require := require.New(t)
+		srv := newTestLCPServer(t)
+		srv.start()
+		client := dial(t, srv.addr, 5*time.Second)
+		require.NoError(query(client))
+		<-srv.received
+
+		srv.stop()
+		time.AfterFunc(300*time.Millisecond, srv.start)
+		require.NoError(query(client))
+		// the dial options are preserved across the re-dials
+		md := <-srv.received
+		require.Equal([]string{"v1.2.3"}, md.Get(metadataKeyRelayerVersion))
+	})
+
+	t.Run("the service does not come back", func(t *testing.T) {
+		require := require.New(t)
+		srv := newTestLCPServer(t)
+		srv.start()
+		client := dial(t, srv.addr, 500*time.Millisecond)
+		require.NoError(query(client))
+
+		srv.stop()
+		err := query(client)
+		require.ErrorContains(err, "failed to reconnect to LCP service")
+		// the original error is still classified
+		require.Equal(ServiceErrorUnavailable, ServiceErrorCodeOf(err))
+		require.True(isConnectionLost(err))
+	})
+}
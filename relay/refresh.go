@@ -0,0 +1,166 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// MinRecommendedUpdateInterval is the lower bound of the recommended update interval
+const MinRecommendedUpdateInterval = time.Minute
+
+// keyRotationTime returns the time after which the enclave key attested at `attestationTime` should be rotated.
+// For now, a half of the key expiration is used as a buffer time.
+func (pr *Prover) keyRotationTime(attestationTime time.Time) time.Time {
+	return attestationTime.Add(pr.keyExpiration() / 2)
+}
+
+// keyExpiration returns the key expiration used to rotate the enclave keys.
+// If PreferOnChainKeyExpiration is set, the one of the counterparty LCP client is used once it has been queried.
+func (pr *Prover) keyExpiration() time.Duration {
+	if pr.config.PreferOnChainKeyExpiration && pr.counterpartyKeyExpiration != 0 {
+		return pr.counterpartyKeyExpiration
+	}
+	return time.Duration(pr.config.KeyExpiration) * time.Second
+}
+
+// RecommendedUpdateInterval returns the maximum interval between updates of the LCP client.
+// A key is rotated when a half of its expiration has elapsed, so an update at least once per the rest of the window
+// keeps a registered key available. The estimated finality lag of the counterparty chain is subtracted
+// because the registration of a new key is not effective until it is finalized.
+// If the counterparty LCP client bounds the gap between updates, the interval is at most a half of the bound
+// so that a failed update can be retried before the gap is exceeded.
+func (pr *Prover) RecommendedUpdateInterval() time.Duration {
+	return recommendedUpdateInterval(pr.keyExpiration(), pr.counterpartyFinalityLag, pr.counterpartyMaxUpdateGap)
+}
+
+func recommendedUpdateInterval(keyExpiration, finalityLag, maxUpdateGap time.Duration) time.Duration {
+	interval := keyExpiration - keyExpiration/2 - finalityLag
+	if maxUpdateGap != 0 && maxUpdateGap/2 < interval {
+		interval = maxUpdateGap / 2
+	}
+	if interval < MinRecommendedUpdateInterval {
+		return MinRecommendedUpdateInterval
+	}
+	return interval
+}
+
+// estimateFinalityLag estimates the time between the inclusion of a tx and its finalization on the given chain
+func estimateFinalityLag(chain *core.ProvableChain) (time.Duration, error) {
+	latestHeight, err := chain.LatestHeight()
+	if err != nil {
+		return 0, err
+	}
+	finalizedHeader, err := chain.GetLatestFinalizedHeader()
+	if err != nil {
+		return 0, err
+	}
+	finalizedHeight := finalizedHeader.GetHeight()
+	if finalizedHeight.GTE(latestHeight) {
+		return 0, nil
+	}
+	lag := latestHeight.GetRevisionHeight() - finalizedHeight.GetRevisionHeight()
+	return time.Duration(lag) * chain.AverageBlockTime(), nil
+}
+
+// checkCounterpartyClientRefreshRequired returns true if the LCP client on the counterparty chain should be updated to keep it alive, i.e. either
+//   - the recommended update interval has elapsed since the latest update of the client,
+//   - the latest consensus state has exceeded the refresh threshold of its lifetime, or
+//   - the active enclave key has exceeded the refresh threshold of its lifetime on the client, so that the update rotates it before it expires
+func (pr *Prover) checkCounterpartyClientRefreshRequired(counterparty core.ChainInfoICS02Querier) (bool, error) {
+	cpQueryHeight, err := counterparty.LatestHeight()
+	if err != nil {
+		return false, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	cpQueryCtx := core.NewQueryContext(context.TODO(), cpQueryHeight)
+	resCs, err := counterparty.QueryClientState(cpQueryCtx)
+	if err != nil {
+		return false, fmt.Errorf("failed to query the client state on the counterparty chain: %w", err)
+	}
+	var cs ibcexported.ClientState
+	if err := pr.codec.UnpackAny(resCs.ClientState, &cs); err != nil {
+		return false, fmt.Errorf("failed to unpack client state: %w", err)
+	}
+	lcpCs, isLCP := cs.(*lcptypes.ClientState)
+	if isLCP {
+		if lcpCs.Frozen {
+			pr.alert(AlertCounterpartyClientInactive, pr.counterpartyClientID(), "the counterparty LCP client is frozen")
+		}
+		// the bound may be changed by a migration of the client
+		pr.counterpartyMaxUpdateGap = lcpCs.GetMaxUpdateGap()
+		// so may the key expiration, e.g. by a governance proposal
+		pr.observeCounterpartyKeyExpiration(lcpCs)
+		// and the accepted message versions, e.g. by an upgrade of the client
+		pr.observeCounterpartyMessageVersions(lcpCs)
+	}
+	resCons, err := counterparty.QueryClientConsensusState(cpQueryCtx, cs.GetLatestHeight())
+	if err != nil {
+		return false, fmt.Errorf("failed to query the consensus state on the counterparty chain: %w", err)
+	}
+	var cons ibcexported.ConsensusState
+	if err := pr.codec.UnpackAny(resCons.ConsensusState, &cons); err != nil {
+		return false, fmt.Errorf("failed to unpack consensus state: %w", err)
+	}
+	lastUpdated := time.Unix(0, int64(cons.GetTimestamp()))
+
+	selfHeight, err := pr.originChain.LatestHeight()
+	if err != nil {
+		return false, fmt.Errorf("failed to get the latest height of the origin chain: %w", err)
+	}
+	selfTimestamp, err := pr.originChain.Timestamp(selfHeight)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the timestamp of the origin chain: %w", err)
+	}
+	elapsed := selfTimestamp.Sub(lastUpdated)
+	if expiration := pr.keyExpiration(); elapsed > expiration {
+		pr.alert(AlertCounterpartyClientInactive, pr.counterpartyClientID(), "the counterparty LCP client has not been updated within the key expiration", "last_updated", lastUpdated, "key_expiration", expiration)
+	}
+	if maxGap := pr.counterpartyMaxUpdateGap; maxGap != 0 && elapsed > maxGap {
+		pr.alert(AlertCounterpartyClientInactive, pr.counterpartyClientID(), "the counterparty LCP client has not been updated within the max update gap", "last_updated", lastUpdated, "max_update_gap", maxGap)
+	}
+	interval := pr.RecommendedUpdateInterval()
+	pr.getLogger().Debug("checkCounterpartyClientRefreshRequired", "elapsed", elapsed, "recommended_update_interval", interval)
+	if elapsed >= interval {
+		return true, nil
+	}
+
+	rate := pr.config.GetRefreshThresholdRate()
+	consensusLifetime := pr.counterpartyMaxUpdateGap
+	if consensusLifetime == 0 {
+		consensusLifetime = pr.keyExpiration()
+	}
+	if exceedsRefreshThreshold(lastUpdated, selfTimestamp, consensusLifetime, rate) {
+		pr.getLogger().Info("the latest consensus state of the counterparty LCP client exceeds the refresh threshold", "last_updated", lastUpdated, "lifetime", consensusLifetime, "refresh_threshold_rate", rate)
+		return true, nil
+	}
+	// the key is not loaded yet, or the client is not LCP, e.g. in the tests of the origin prover
+	if !isLCP || pr.activeEnclaveKey == nil {
+		return false, nil
+	}
+	// the counterparty LCP client checks the expiration of the key with its own block time
+	cpTimestamp, err := counterparty.Timestamp(cpQueryHeight)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the timestamp of the counterparty chain: %w", err)
+	}
+	attestationTime := time.Unix(int64(pr.activeEnclaveKey.AttestationTime), 0)
+	keyLifetime := time.Duration(lcpCs.KeyExpiration) * time.Second
+	if exceedsRefreshThreshold(attestationTime, cpTimestamp, keyLifetime, rate) {
+		pr.getLogger().Info("the active enclave key exceeds the refresh threshold on the counterparty LCP client", "enclave_key", pr.activeEnclaveKey.EnclaveKeyAddress, "attestation_time", attestationTime, "key_expiration", keyLifetime, "refresh_threshold_rate", rate)
+		return true, nil
+	}
+	return false, nil
+}
+
+// exceedsRefreshThreshold returns true if more than `rate` of `lifetime` beginning at `start` has elapsed at `now`.
+// A zero lifetime is regarded as unbounded.
+func exceedsRefreshThreshold(start, now time.Time, lifetime time.Duration, rate Fraction) bool {
+	if lifetime == 0 || rate.Denominator == 0 {
+		return false
+	}
+	threshold := time.Duration(float64(lifetime) * float64(rate.Numerator) / float64(rate.Denominator))
+	return now.Sub(start) > threshold
+}
@@ -0,0 +1,116 @@
+package relay
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendedUpdateInterval(t *testing.T) {
+	var cases = []struct {
+		name          string
+		keyExpiration uint64
+		finalityLag   time.Duration
+		maxUpdateGap  time.Duration
+		expected      time.Duration
+	}{
+		{"instant finality", 3600, 0, 0, 30 * time.Minute},
+		{"finality lag", 3600, 5 * time.Minute, 0, 25 * time.Minute},
+		{"odd expiration", 3601, 0, 0, 1800*time.Second + 500*time.Millisecond},
+		{"one week", 604800, 13 * time.Minute, 0, 84*time.Hour - 13*time.Minute},
+		{"lag exceeds the window", 3600, time.Hour, 0, MinRecommendedUpdateInterval},
+		{"short expiration", 60, 0, 0, MinRecommendedUpdateInterval},
+		{"max update gap", 604800, 13 * time.Minute, 6 * time.Hour, 3 * time.Hour},
+		{"loose max update gap", 3600, 0, 2 * time.Hour, 30 * time.Minute},
+		{"short max update gap", 604800, 0, time.Minute, MinRecommendedUpdateInterval},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pr := newTestProver(t)
+			pr.config.KeyExpiration = c.keyExpiration
+			pr.counterpartyFinalityLag = c.finalityLag
+			pr.counterpartyMaxUpdateGap = c.maxUpdateGap
+			require.Equal(t, c.expected, pr.RecommendedUpdateInterval())
+		})
+	}
+}
+
+func TestExceedsRefreshThreshold(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	half := Fraction{Numerator: 1, Denominator: 2}
+	var cases = []struct {
+		name     string
+		elapsed  time.Duration
+		lifetime time.Duration
+		rate     Fraction
+		expected bool
+	}{
+		{"below", 29 * time.Minute, time.Hour, half, false},
+		{"at the threshold", 30 * time.Minute, time.Hour, half, false},
+		{"above", 30*time.Minute + time.Second, time.Hour, half, true},
+		{"larger rate", 40 * time.Minute, time.Hour, Fraction{Numerator: 3, Denominator: 4}, false},
+		{"unbounded lifetime", 100 * time.Hour, 0, half, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, exceedsRefreshThreshold(start, start.Add(c.elapsed), c.lifetime, c.rate))
+		})
+	}
+}
+
+// mockRefreshOriginProver is the prover of an origin chain that never requires a refresh by itself
+type mockRefreshOriginProver struct {
+	core.Prover
+}
+
+func (mockRefreshOriginProver) CheckRefreshRequired(counterparty core.ChainInfoICS02Querier) (bool, error) {
+	return false, nil
+}
+
+func TestCheckRefreshRequired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var cases = []struct {
+		name         string
+		rate         Fraction
+		maxUpdateGap uint64
+		// the elapsed times of the latest consensus state and the active enclave key
+		consensusAge time.Duration
+		keyAge       time.Duration
+		expected     bool
+	}{
+		{"fresh", Fraction{}, 0, time.Minute, 10 * time.Minute, false},
+		{"recommended update interval", Fraction{}, 0, 30 * time.Minute, 10 * time.Minute, true},
+		{"consensus state near expiry", Fraction{Numerator: 1, Denominator: 4}, 0, 16 * time.Minute, 10 * time.Minute, true},
+		{"consensus state near the max update gap", Fraction{Numerator: 1, Denominator: 4}, 1800, 8 * time.Minute, 10 * time.Minute, true},
+		// the consensus state is updated just before, but the key is about to expire on the client
+		{"only the key near expiry", Fraction{}, 0, time.Minute, 31 * time.Minute, true},
+		{"key at the threshold", Fraction{}, 0, time.Minute, 30 * time.Minute, false},
+		{"key near expiry with a larger rate", Fraction{Numerator: 9, Denominator: 10}, 0, time.Minute, 31 * time.Minute, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			pr.codec = newTestCodec()
+			pr.config.RefreshThresholdRate = c.rate
+			pr.originProver = mockRefreshOriginProver{}
+			pr.originChain = &mockCounterparty{chainID: "origin", latestHeight: clienttypes.NewHeight(0, 100), blockTime: now}
+			pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}, AttestationTime: uint64(now.Add(-c.keyAge).Unix())}
+
+			cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+			cp.latestHeight = clienttypes.NewHeight(0, 10)
+			cp.blockTime = now
+			cp.clientState = &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, 100), KeyExpiration: 3600, MaxUpdateGap: c.maxUpdateGap}
+			cp.consensusState = &lcptypes.ConsensusState{Timestamp: uint64(now.Add(-c.consensusAge).UnixNano())}
+
+			required, err := pr.CheckRefreshRequired(cp)
+			require.NoError(err)
+			require.Equal(c.expected, required)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package relay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// EnclaveKeyListerChain is implemented by the counterparty chains that can list the enclave keys registered in the LCP client.
+// On Cosmos chains, the entries are queried from the client store with lcptypes.EnclaveKeyPrefix and decoded with lcptypes.ParseEnclaveKeyEntry.
+type EnclaveKeyListerChain interface {
+	// QueryEnclaveKeys returns the enclave keys registered in the LCP client `clientID` at the height of `ctx`
+	QueryEnclaveKeys(ctx core.QueryContext, clientID string) (map[common.Address]lcptypes.EKInfo, error)
+}
+
+// RegisteredEnclaveKey is an enclave key registered in the counterparty LCP client
+type RegisteredEnclaveKey struct {
+	Address   common.Address `json:"address"`
+	Operator  common.Address `json:"operator"`
+	ExpiredAt time.Time      `json:"expired_at"`
+	// true if the key has not expired at the latest block time of the counterparty chain
+	Active bool `json:"active"`
+}
+
+// QueryRegisteredEnclaveKeysResult is the result of doQueryRegisteredEnclaveKeys
+type QueryRegisteredEnclaveKeysResult struct {
+	ClientID string `json:"client_id"`
+	// the latest height of the counterparty chain at which the keys are queried
+	Height string `json:"height"`
+	// the block time at `Height`, which the expirations are compared with
+	BlockTime time.Time              `json:"block_time"`
+	Keys      []RegisteredEnclaveKey `json:"keys"`
+}
+
+// doQueryRegisteredEnclaveKeys lists the enclave keys registered in the LCP client on `counterparty` in the order of the expiration.
+// The keys are marked as active or expired relative to the latest block time of the counterparty chain rather than the local clock.
+func (pr *Prover) doQueryRegisteredEnclaveKeys(counterparty core.Chain) (*QueryRegisteredEnclaveKeysResult, error) {
+	chain := counterparty
+	// the query is implemented by the chain module rather than the provable chain wrapping it
+	if pc, ok := counterparty.(*core.ProvableChain); ok {
+		chain = pc.Chain
+	}
+	lister, ok := chain.(EnclaveKeyListerChain)
+	if !ok {
+		return nil, fmt.Errorf("the counterparty chain does not support listing the enclave keys: chain_id=%v chain_type=%T", counterparty.ChainID(), chain)
+	}
+	height, err := counterparty.LatestHeight()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	blockTime, err := counterparty.Timestamp(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the block time of the counterparty chain: height=%v %w", height, err)
+	}
+	clientID := counterparty.Path().ClientID
+	ekis, err := lister.QueryEnclaveKeys(core.NewQueryContext(context.TODO(), height), clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the enclave keys: client_id=%v height=%v %w", clientID, height, err)
+	}
+	result := QueryRegisteredEnclaveKeysResult{
+		ClientID:  clientID,
+		Height:    height.String(),
+		BlockTime: blockTime,
+		Keys:      []RegisteredEnclaveKey{},
+	}
+	for addr, info := range ekis {
+		result.Keys = append(result.Keys, RegisteredEnclaveKey{
+			Address:   addr,
+			Operator:  info.Operator,
+			ExpiredAt: time.Unix(int64(info.ExpiredAt), 0),
+			Active:    !info.IsExpired(blockTime),
+		})
+	}
+	sort.Slice(result.Keys, func(i, j int) bool {
+		if !result.Keys[i].ExpiredAt.Equal(result.Keys[j].ExpiredAt) {
+			return result.Keys[i].ExpiredAt.Before(result.Keys[j].ExpiredAt)
+		}
+		return bytes.Compare(result.Keys[i].Address.Bytes(), result.Keys[j].Address.Bytes()) < 0
+	})
+	return &result, nil
+}
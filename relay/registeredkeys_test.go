@@ -0,0 +1,66 @@
+package relay
+
+import (
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+// mockKeyListerCounterparty is a counterparty chain that can list the enclave keys registered in the LCP client
+type mockKeyListerCounterparty struct {
+	*mockCounterparty
+	keys map[common.Address]lcptypes.EKInfo
+
+	queriedHeight exported.Height
+}
+
+func (c *mockKeyListerCounterparty) QueryEnclaveKeys(ctx core.QueryContext, clientID string) (map[common.Address]lcptypes.EKInfo, error) {
+	c.queriedHeight = ctx.Height()
+	return c.keys, nil
+}
+
+func TestDoQueryRegisteredEnclaveKeys(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	blockTime := time.Unix(1700000000, 0).UTC()
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+	cp.latestHeight = clienttypes.NewHeight(0, 12)
+	cp.blockTime = blockTime
+	ek1, ek2, ek3 := common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03")
+	operator := common.HexToAddress("0x10")
+	lister := &mockKeyListerCounterparty{
+		mockCounterparty: cp,
+		keys: map[common.Address]lcptypes.EKInfo{
+			ek1: {ExpiredAt: uint64(blockTime.Add(time.Hour).Unix()), Operator: operator},
+			ek2: {ExpiredAt: uint64(blockTime.Add(-time.Hour).Unix())},
+			// expires exactly at the latest block time
+			ek3: {ExpiredAt: uint64(blockTime.Unix())},
+		},
+	}
+
+	res, err := pr.doQueryRegisteredEnclaveKeys(lister)
+	require.NoError(err)
+	require.Equal(cp.latestHeight, lister.queriedHeight)
+	require.Equal("lcp-client-0", res.ClientID)
+	require.Equal("0-12", res.Height)
+	require.True(blockTime.Equal(res.BlockTime))
+	require.Len(res.Keys, 3)
+	// ordered by the expiration
+	require.Equal(ek2, res.Keys[0].Address)
+	require.False(res.Keys[0].Active)
+	require.Equal(ek3, res.Keys[1].Address)
+	require.True(res.Keys[1].Active)
+	require.Equal(ek1, res.Keys[2].Address)
+	require.True(res.Keys[2].Active)
+	require.Equal(operator, res.Keys[2].Operator)
+
+	// the chain module must support listing the keys
+	_, err = pr.doQueryRegisteredEnclaveKeys(cp)
+	require.ErrorContains(err, "does not support listing the enclave keys")
+}
@@ -0,0 +1,113 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// CounterpartyClientHeightRegressionError is returned if the latest height of the counterparty LCP client is lower than the last observed one.
+// The updates generated from the ELC's higher height can never be applied to the client, so the relay halts
+// until the operator acknowledges the regression.
+type CounterpartyClientHeightRegressionError struct {
+	ChainID      string
+	ClientID     string
+	LastObserved clienttypes.Height
+	Current      clienttypes.Height
+}
+
+func (e *CounterpartyClientHeightRegressionError) Error() string {
+	return fmt.Sprintf(
+		"the latest height of the counterparty LCP client regressed: chain_id=%v client_id=%v last_observed=%v current=%v: verify the state of the counterparty chain and acknowledge the regression to continue",
+		e.ChainID, e.ClientID, e.LastObserved, e.Current,
+	)
+}
+
+func counterpartyClientKey(counterparty core.FinalityAwareChain) string {
+	return fmt.Sprintf("%v/%v", counterparty.ChainID(), counterparty.Path().ClientID)
+}
+
+// queryCounterpartyClientHeight returns the latest height of the LCP client at the latest height of the counterparty chain
+func (pr *Prover) queryCounterpartyClientHeight(ctx context.Context, counterparty core.FinalityAwareChain) (clienttypes.Height, error) {
+	cpQueryHeight, err := counterparty.LatestHeight()
+	if err != nil {
+		return clienttypes.Height{}, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	res, err := counterparty.QueryClientState(core.NewQueryContext(ctx, cpQueryHeight))
+	if err != nil {
+		return clienttypes.Height{}, fmt.Errorf("failed to query the client state on the counterparty chain: %w", err)
+	}
+	var cs ibcexported.ClientState
+	if err := pr.codec.UnpackAny(res.ClientState, &cs); err != nil {
+		return clienttypes.Height{}, fmt.Errorf("failed to unpack client state: %w", err)
+	}
+	return clienttypes.NewHeight(cs.GetLatestHeight().GetRevisionNumber(), cs.GetLatestHeight().GetRevisionHeight()), nil
+}
+
+// checkCounterpartyClientHeight returns CounterpartyClientHeightRegressionError if the latest height of the counterparty LCP client
+// is lower than the last observed one. Otherwise, the height is recorded as the last observed one.
+func (pr *Prover) checkCounterpartyClientHeight(ctx context.Context, counterparty core.FinalityAwareChain) error {
+	current, err := pr.queryCounterpartyClientHeight(ctx, counterparty)
+	if err != nil {
+		return err
+	}
+	heights, err := pr.loadCounterpartyClientHeights(ctx)
+	if err != nil {
+		return err
+	}
+	key := counterpartyClientKey(counterparty)
+	lastObserved, found := heights[key]
+	if found && current.LT(lastObserved) {
+		regression := &CounterpartyClientHeightRegressionError{
+			ChainID:      counterparty.ChainID(),
+			ClientID:     counterparty.Path().ClientID,
+			LastObserved: lastObserved,
+			Current:      current,
+		}
+		pr.countCounterpartyClientHeightRegression(ctx, counterparty.ChainID())
+		pr.alert(AlertCounterpartyClientHeightRegressed, regression.ClientID, "the latest height of the counterparty LCP client regressed", "counterparty_chain_id", regression.ChainID, "last_observed", lastObserved, "current", current)
+		return regression
+	}
+	if found && current.EQ(lastObserved) {
+		return nil
+	}
+	return pr.saveCounterpartyClientHeight(ctx, key, current)
+}
+
+// acknowledgeCounterpartyClientHeightRegression records the current latest height of the counterparty LCP client
+// as the last observed one regardless of the regression, so the relay continues from the height.
+func (pr *Prover) acknowledgeCounterpartyClientHeightRegression(ctx context.Context, counterparty core.FinalityAwareChain) error {
+	current, err := pr.queryCounterpartyClientHeight(ctx, counterparty)
+	if err != nil {
+		return err
+	}
+	key := counterpartyClientKey(counterparty)
+	heights, err := pr.loadCounterpartyClientHeights(ctx)
+	if err != nil {
+		return err
+	}
+	pr.getLogger().Warn("acknowledge the regression of the counterparty LCP client height", "counterparty_chain_id", counterparty.ChainID(), "client_id", counterparty.Path().ClientID, "last_observed", heights[key], "current", current)
+	return pr.saveCounterpartyClientHeight(ctx, key, current)
+}
+
+// countCounterpartyClientHeightRegression increments the counter of the detected regressions of the counterparty LCP client height.
+// The counter is recorded with the metrics sink of the prover.
+func (pr *Prover) countCounterpartyClientHeightRegression(ctx context.Context, chainID string) {
+	counter, err := pr.meter().Int64Counter(
+		"lcp.counterparty_client_height_regressions",
+		metric.WithUnit("1"),
+		metric.WithDescription("number of detected regressions of the latest height of the counterparty LCP client"),
+	)
+	if err != nil {
+		pr.getLogger().Warn("failed to create the counter of the counterparty client height regressions", "error", err)
+		return
+	}
+	counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("chain_id", chainID),
+	))
+}
@@ -0,0 +1,89 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounterpartyClientHeightRegression(t *testing.T) {
+	require := require.New(t)
+	srv, payloads := newAlertCaptureServer(t, http.StatusOK)
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.homePath = t.TempDir()
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	pr.config.AlertWebhookUrl = srv.URL
+	alerter, err := newProverAlerter(pr.config)
+	require.NoError(err)
+	pr.alerter = alerter
+	require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+
+	counterparty := newMockCounterparty(clienttypes.NewHeight(0, 1))
+	setClientHeight := func(height uint64) {
+		counterparty.clientState = &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, height)}
+	}
+	lastObserved := func() clienttypes.Height {
+		heights, err := pr.loadCounterpartyClientHeights(context.TODO())
+		require.NoError(err)
+		return heights[counterpartyClientKey(counterparty)]
+	}
+
+	// the heights are recorded as the client is updated
+	for _, h := range []uint64{10, 10, 20} {
+		setClientHeight(h)
+		require.NoError(pr.checkCounterpartyClientHeight(context.TODO(), counterparty))
+		require.Equal(clienttypes.NewHeight(0, h), lastObserved())
+	}
+
+	// the counterparty chain is rolled back
+	setClientHeight(15)
+	for i := 0; i < 2; i++ {
+		err = pr.checkCounterpartyClientHeight(context.TODO(), counterparty)
+		var regression *CounterpartyClientHeightRegressionError
+		require.True(errors.As(err, &regression), err)
+		require.Equal(clienttypes.NewHeight(0, 20), regression.LastObserved)
+		require.Equal(clienttypes.NewHeight(0, 15), regression.Current)
+		require.Equal("lcp-client-0", regression.ClientID)
+		require.ErrorContains(err, "last_observed=0-20 current=0-15")
+		// the expectation is kept until the regression is acknowledged
+		require.Equal(clienttypes.NewHeight(0, 20), lastObserved())
+	}
+
+	// the relay halts before generating the updates
+	_, err = pr.SetupHeadersForUpdate(counterparty, mockHeader{height: clienttypes.NewHeight(0, 30)})
+	var regression *CounterpartyClientHeightRegressionError
+	require.True(errors.As(err, &regression), err)
+
+	pr.alerter.wait()
+	// the same condition is notified once within the dedup interval
+	require.Len(payloads(), 1)
+	var alert Alert
+	require.NoError(json.Unmarshal(payloads()[0], &alert))
+	require.Equal(AlertCounterpartyClientHeightRegressed, alert.Condition)
+	require.Equal("lcp-client-0", alert.Subject)
+	require.Equal("0-20", alert.Details["last_observed"])
+	require.Equal("0-15", alert.Details["current"])
+
+	// the acknowledgement re-syncs the expectation with the current height
+	require.NoError(pr.acknowledgeCounterpartyClientHeightRegression(context.TODO(), counterparty))
+	require.Equal(clienttypes.NewHeight(0, 15), lastObserved())
+	require.NoError(pr.checkCounterpartyClientHeight(context.TODO(), counterparty))
+	setClientHeight(16)
+	require.NoError(pr.checkCounterpartyClientHeight(context.TODO(), counterparty))
+	require.Equal(clienttypes.NewHeight(0, 16), lastObserved())
+
+	// the heights are recorded for each counterparty client
+	other := newMockCounterparty(clienttypes.NewHeight(0, 1))
+	other.chainID = "other"
+	other.clientState = &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, 1)}
+	require.NoError(pr.checkCounterpartyClientHeight(context.TODO(), other))
+	require.Equal(clienttypes.NewHeight(0, 16), lastObserved())
+}
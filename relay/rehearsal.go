@@ -0,0 +1,199 @@
+package relay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// rehearsal holds the state of the rehearsal mode.
+// In the rehearsal mode, the prover runs the same decision tree, key selection and message construction as usual,
+// but it writes the messages into files instead of submitting them to the counterparty chain.
+// The persistence layer is redirected to a temporary home directory that is initialized with a copy of the current state.
+type rehearsal struct {
+	outputDir    string
+	originalHome string
+	tmpHome      string
+	snapshot     map[string][]byte
+	report       *RehearsalReport
+}
+
+// RehearsalReport is a report of the rehearsal
+type RehearsalReport struct {
+	// OutputDir is the directory where the messages are written
+	OutputDir string `json:"output_dir"`
+	// Artifacts are the files that contain the messages that would have been submitted
+	Artifacts []string `json:"artifacts"`
+	// StateChanges are the changes that would have been made to the prover's persisted state
+	StateChanges []RehearsalStateChange `json:"state_changes"`
+}
+
+// RehearsalStateChange represents a change of a file in the prover's persisted state
+type RehearsalStateChange struct {
+	// File is the path relative to the prover's db directory
+	File string `json:"file"`
+	// Action is one of "create", "update" and "remove"
+	Action string `json:"action"`
+}
+
+// StartRehearsal enables the rehearsal mode of the prover.
+// `outputDir` is the directory where the messages that would have been submitted are written.
+func (pr *Prover) StartRehearsal(outputDir string) error {
+	if pr.rehearsal != nil {
+		return fmt.Errorf("rehearsal is already started")
+	}
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create the output directory: path=%v %w", outputDir, err)
+	}
+	snapshot, err := snapshotDir(pr.dbPath())
+	if err != nil {
+		return err
+	}
+	tmpHome, err := os.MkdirTemp("", "lcp-rehearsal-")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary home directory: %w", err)
+	}
+	r := &rehearsal{
+		outputDir:    outputDir,
+		originalHome: pr.homePath,
+		tmpHome:      tmpHome,
+		snapshot:     snapshot,
+		report:       &RehearsalReport{OutputDir: outputDir},
+	}
+	pr.homePath = tmpHome
+	if err := os.MkdirAll(pr.dbPath(), os.ModePerm); err != nil {
+		return err
+	}
+	for name, bz := range snapshot {
+		path := filepath.Join(pr.dbPath(), name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return fmt.Errorf("failed to copy the state into the temporary home directory: file=%v %w", name, err)
+		}
+		if err := os.WriteFile(path, bz, 0600); err != nil {
+			return fmt.Errorf("failed to copy the state into the temporary home directory: file=%v %w", name, err)
+		}
+	}
+	pr.rehearsal = r
+	pr.getLogger().Info("rehearsal started", "output_dir", outputDir, "tmp_home", tmpHome)
+	return nil
+}
+
+// FinishRehearsal disables the rehearsal mode and returns the report.
+// The in-memory state of the prover is reset so that it is reloaded from the original home directory.
+func (pr *Prover) FinishRehearsal() (*RehearsalReport, error) {
+	r := pr.rehearsal
+	if r == nil {
+		return nil, fmt.Errorf("rehearsal is not started")
+	}
+	after, err := snapshotDir(pr.dbPath())
+	if err != nil {
+		return nil, err
+	}
+	r.report.StateChanges = diffSnapshots(r.snapshot, after)
+	pr.homePath = r.originalHome
+	pr.rehearsal = nil
+	pr.activeEnclaveKey, pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = nil, nil, clienttypes.Height{}
+	if err := os.RemoveAll(r.tmpHome); err != nil {
+		return nil, fmt.Errorf("failed to remove the temporary home directory: path=%v %w", r.tmpHome, err)
+	}
+	return r.report, nil
+}
+
+// IsRehearsal returns true if the prover is in the rehearsal mode
+func (pr *Prover) IsRehearsal() bool {
+	return pr.rehearsal != nil
+}
+
+// sendMsgs submits the msgs to the counterparty chain.
+// If the submission queue is enabled, the msgs are submitted through the queue.
+// In the rehearsal mode, it writes the msgs into a file instead and returns no msg IDs.
+func (pr *Prover) sendMsgs(counterparty core.Chain, label string, msgs []sdk.Msg) ([]core.MsgID, error) {
+	if pr.rehearsal == nil {
+		if pr.config.SubmissionQueue {
+			return pr.submitQueuedMsgs(counterparty, label, msgs)
+		}
+		return pr.sendMsgsWithTxOptions(counterparty, label, msgs)
+	}
+	path, err := pr.rehearsal.writeMsgs(pr.codec, label, msgs)
+	if err != nil {
+		return nil, err
+	}
+	pr.getLogger().Info("rehearsal: the msgs are not submitted", "label", label, "num_msgs", len(msgs), "artifact", path)
+	return nil, nil
+}
+
+func (r *rehearsal) writeMsgs(cdc codec.JSONCodec, label string, msgs []sdk.Msg) (string, error) {
+	var jsonMsgs []json.RawMessage
+	for i, msg := range msgs {
+		bz, err := cdc.MarshalInterfaceJSON(msg)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal msg: index=%v %w", i, err)
+		}
+		jsonMsgs = append(jsonMsgs, bz)
+	}
+	bz, err := json.MarshalIndent(jsonMsgs, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(r.outputDir, fmt.Sprintf("%03d-%s.json", len(r.report.Artifacts), label))
+	if err := os.WriteFile(path, bz, 0600); err != nil {
+		return "", fmt.Errorf("failed to write msgs: path=%v %w", path, err)
+	}
+	r.report.Artifacts = append(r.report.Artifacts, path)
+	return path, nil
+}
+
+// snapshotDir returns the contents of the files in `dir` and its subdirectories keyed by the paths relative to `dir`
+func snapshotDir(dir string) (map[string][]byte, error) {
+	snapshot := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if d.IsDir() {
+			return nil
+		}
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read file: path=%v %w", path, err)
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		snapshot[name] = bz
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return snapshot, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read directory: path=%v %w", dir, err)
+	}
+	return snapshot, nil
+}
+
+func diffSnapshots(before, after map[string][]byte) []RehearsalStateChange {
+	changes := []RehearsalStateChange{}
+	for name, bz := range after {
+		if prev, ok := before[name]; !ok {
+			changes = append(changes, RehearsalStateChange{File: name, Action: "create"})
+		} else if !bytes.Equal(prev, bz) {
+			changes = append(changes, RehearsalStateChange{File: name, Action: "update"})
+		}
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changes = append(changes, RehearsalStateChange{File: name, Action: "remove"})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].File < changes[j].File })
+	return changes
+}
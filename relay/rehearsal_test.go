@@ -0,0 +1,79 @@
+package relay
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCodec() codec.ProtoCodecMarshaler {
+	registry := codectypes.NewInterfaceRegistry()
+	clienttypes.RegisterInterfaces(registry)
+	lcptypes.RegisterInterfaces(registry)
+	tendermint.RegisterInterfaces(registry)
+	return codec.NewProtoCodec(registry)
+}
+
+func TestRehearsal(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	pr.codec = newTestCodec()
+	pr.homePath = t.TempDir()
+	pr.originChain = &mockCounterparty{chainID: "origin"}
+	require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+
+	oldEKI := &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}}
+	require.NoError(pr.saveFinalizedEnclaveKeyInfo(context.TODO(), oldEKI))
+	originalPath := pr.ekStoreFilePath()
+	original, err := os.ReadFile(originalPath)
+	require.NoError(err)
+
+	outputDir := filepath.Join(t.TempDir(), "out")
+	require.NoError(pr.StartRehearsal(outputDir))
+	require.True(pr.IsRehearsal())
+
+	// the persisted state is copied into the temporary home directory
+	eki, err := pr.loadLastFinalizedEnclaveKey(context.TODO())
+	require.NoError(err)
+	require.Equal(oldEKI.EnclaveKeyAddress, eki.EnclaveKeyAddress)
+
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 1))
+	msg, err := clienttypes.NewMsgUpdateClient("lcp-client-0", &lcptypes.RegisterEnclaveKeyMessage{Report: []byte("report")}, "signer")
+	require.NoError(err)
+	ids, err := pr.sendMsgs(cp, "register_enclave_key", []sdk.Msg{msg})
+	require.NoError(err)
+	require.Nil(ids)
+	require.NoError(pr.saveFinalizedEnclaveKeyInfo(context.TODO(), &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x02}}))
+	require.NoError(pr.removeUnfinalizedEnclaveKeyInfos(context.TODO()))
+
+	report, err := pr.FinishRehearsal()
+	require.NoError(err)
+	require.False(pr.IsRehearsal())
+	require.Equal(0, cp.sendMsgsCalls)
+
+	require.Len(report.Artifacts, 1)
+	bz, err := os.ReadFile(report.Artifacts[0])
+	require.NoError(err)
+	require.Contains(string(bz), "/ibc.lightclients.lcp.v1.RegisterEnclaveKeyMessage")
+	require.Equal([]RehearsalStateChange{{File: ekStoreFile, Action: "update"}}, report.StateChanges)
+
+	// the original state is not modified
+	current, err := os.ReadFile(originalPath)
+	require.NoError(err)
+	require.Equal(original, current)
+
+	// msgs are submitted after the rehearsal
+	_, err = pr.sendMsgs(cp, "register_enclave_key", []sdk.Msg{msg})
+	require.NoError(err)
+	require.Equal(1, cp.sendMsgsCalls)
+}
@@ -0,0 +1,102 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	meterName = "github.com/datachainlab/lcp-go/relay"
+
+	// the block including the registration was replaced with a block at another height including it
+	registrationReorgReincluded = "reincluded"
+	// the block including the registration was replaced with blocks not including it
+	registrationReorgDropped = "dropped"
+)
+
+// countRegistrationReorg increments the counter of the reorgs affecting the enclave key registrations.
+// The counter is recorded with the metrics sink of the prover.
+func (pr *Prover) countRegistrationReorg(ctx context.Context, kind string) {
+	counter, err := pr.meter().Int64Counter(
+		"lcp.enclave_key_registration_reorgs",
+		metric.WithUnit("1"),
+		metric.WithDescription("number of reorgs of the counterparty chain affecting the enclave key registrations"),
+	)
+	if err != nil {
+		pr.getLogger().Warn("failed to create the counter of the registration reorgs", "error", err)
+		return
+	}
+	counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("chain_id", pr.counterpartyChainID()),
+		attribute.String("kind", kind),
+	))
+}
+
+func (pr *Prover) counterpartyChainID() string {
+	if pr.counterparty == nil {
+		return ""
+	}
+	return pr.counterparty.ChainID()
+}
+
+// trackRegistrationHeight records the height of the block including the unfinalized registration.
+// If the height differs from the recorded one, the block has been reorganized and the msg has been re-included at the height.
+func (pr *Prover) trackRegistrationHeight(ctx context.Context, height clienttypes.Height) error {
+	if pr.unfinalizedMsgHeight.EQ(height) {
+		return nil
+	}
+	if !pr.unfinalizedMsgHeight.IsZero() {
+		pr.getLogger().Warn("reorg detected: the enclave key registration has been re-included at another height", "msg_id", pr.unfinalizedMsgID.String(), "included_height", pr.unfinalizedMsgHeight, "new_height", height)
+		pr.countRegistrationReorg(ctx, registrationReorgReincluded)
+	}
+	if err := pr.saveUnfinalizedEnclaveKeyInfo(ctx, pr.activeEnclaveKey, pr.unfinalizedMsgID, height); err != nil {
+		return err
+	}
+	pr.unfinalizedMsgHeight = height
+	return nil
+}
+
+// handleMissingRegistration handles the case that the unfinalized registration included at the recorded height is no longer found.
+// If the counterparty chain has reached the height, the block including the msg has been reorganized.
+// In that case, the same registration is resubmitted instead of selecting a new key because the AVR is still valid.
+// The record of the dropped msg is kept because the msg may be re-included later.
+// `queryErr` is the error returned by the query of the msg result.
+func (pr *Prover) handleMissingRegistration(ctx context.Context, counterparty core.FinalityAwareChain, now time.Time, queryErr error) (bool, error) {
+	latestHeight, err := counterparty.LatestHeight()
+	if err != nil {
+		return false, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	if latestHeight.LT(pr.unfinalizedMsgHeight) {
+		// the queried node may lag behind the one that returned the msg result
+		pr.getLogger().Info("the counterparty chain has not reached the height including the msg yet", "msg_id", pr.unfinalizedMsgID.String(), "included_height", pr.unfinalizedMsgHeight, "latest_height", latestHeight)
+		return false, nil
+	}
+	pr.getLogger().Warn("reorg detected: the block including the enclave key registration has been reorganized", "msg_id", pr.unfinalizedMsgID.String(), "included_height", pr.unfinalizedMsgHeight, "latest_height", latestHeight, "error", queryErr)
+	pr.countRegistrationReorg(ctx, registrationReorgDropped)
+
+	if pr.checkEKIUpdateNeeded(ctx, now, pr.activeEnclaveKey) {
+		return pr.dropActiveRegistration(ctx, counterparty)
+	}
+	eki := pr.activeEnclaveKey
+	msgID, err := pr.registerEnclaveKey(counterparty, eki)
+	if errors.Is(err, errEnclaveKeyRegistered) {
+		return false, pr.saveAppliedRegistration(ctx, eki)
+	} else if err != nil {
+		return false, fmt.Errorf("failed to resubmit the enclave key registration: %w", err)
+	} else if pr.IsRehearsal() {
+		return false, nil
+	}
+	pr.getLogger().Info("resubmitted the enclave key registration", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "msg_id", msgID.String())
+	if _, err := pr.saveRegisteredEnclaveKey(ctx, counterparty, eki, []core.MsgID{msgID}); err != nil {
+		return false, err
+	}
+	return false, nil
+}
@@ -0,0 +1,188 @@
+package relay
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// registrationReorgCount returns the value of the reorg counter for the kind
+func registrationReorgCount(t *testing.T, reader sdkmetric.Reader, kind string) int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "lcp.enclave_key_registration_reorgs" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				if v, ok := dp.Attributes.Value(attribute.Key("kind")); ok && v.AsString() == kind {
+					return dp.Value
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestLoadEKIAndCheckUpdateNeededReorg(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	registeredMsgID := &tendermint.MsgID{TxHash: "0xaa", MsgIndex: 0}
+	// the mock counterparty always returns this ID for the submitted msg
+	resubmittedMsgID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+
+	var cases = []struct {
+		name string
+		// the height recorded on the registration
+		includedHeight clienttypes.Height
+		// the current result of the registration. If nil, the msg is not found.
+		msgResult    *mockMsgResult
+		latestHeight clienttypes.Height
+		expired      bool
+
+		updateNeeded   bool
+		resubmitted    bool
+		expectedHeight clienttypes.Height
+		reincluded     int64
+		dropped        int64
+	}{
+		{
+			name:           "dropped by reorg",
+			includedHeight: clienttypes.NewHeight(0, 10),
+			latestHeight:   clienttypes.NewHeight(0, 12),
+			resubmitted:    true,
+			expectedHeight: clienttypes.NewHeight(0, 12),
+			dropped:        1,
+		},
+		{
+			name:           "dropped by reorg at the same height",
+			includedHeight: clienttypes.NewHeight(0, 10),
+			latestHeight:   clienttypes.NewHeight(0, 10),
+			resubmitted:    true,
+			expectedHeight: clienttypes.NewHeight(0, 12),
+			dropped:        1,
+		},
+		{
+			name:           "dropped by reorg with expired key",
+			includedHeight: clienttypes.NewHeight(0, 10),
+			latestHeight:   clienttypes.NewHeight(0, 12),
+			expired:        true,
+			updateNeeded:   true,
+			dropped:        1,
+		},
+		{
+			name:           "lagging node",
+			includedHeight: clienttypes.NewHeight(0, 10),
+			latestHeight:   clienttypes.NewHeight(0, 9),
+			expectedHeight: clienttypes.NewHeight(0, 10),
+		},
+		{
+			name:           "re-included at another height",
+			includedHeight: clienttypes.NewHeight(0, 10),
+			msgResult:      &mockMsgResult{height: clienttypes.NewHeight(0, 11), success: true},
+			latestHeight:   clienttypes.NewHeight(0, 12),
+			expectedHeight: clienttypes.NewHeight(0, 11),
+			reincluded:     1,
+		},
+		{
+			name:           "included at the same height",
+			includedHeight: clienttypes.NewHeight(0, 10),
+			msgResult:      &mockMsgResult{height: clienttypes.NewHeight(0, 10), success: true},
+			latestHeight:   clienttypes.NewHeight(0, 12),
+			expectedHeight: clienttypes.NewHeight(0, 10),
+		},
+		{
+			// the height is not recorded by the previous versions
+			name:           "not found without the included height",
+			includedHeight: clienttypes.Height{},
+			latestHeight:   clienttypes.NewHeight(0, 12),
+			updateNeeded:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			reader := sdkmetric.NewManualReader()
+			otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+			eki := loadTestEnclaveKeyInfo(t)
+			if c.expired {
+				eki.AttestationTime = uint64(time.Now().Add(-time.Hour).Unix())
+			}
+			mrenclave, err := hex.DecodeString(testMrenclave(t, eki))
+			require.NoError(err)
+
+			pr := newTestProver(t)
+			pr.codec = newTestCodec()
+			pr.homePath = t.TempDir()
+			pr.originChain = &mockCounterparty{chainID: "origin"}
+			pr.config.AllowedQuoteStatuses = []string{"GROUP_OUT_OF_DATE"}
+			pr.config.AllowedAdvisoryIds = []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-00334", "INTEL-SA-00477", "INTEL-SA-00614", "INTEL-SA-00615", "INTEL-SA-00617", "INTEL-SA-00828"}
+			pr.config.AllowDebugEnclaveKeys = true
+			pr.config.Mrenclave = hex.EncodeToString(mrenclave)
+			require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+			require.NoError(pr.saveUnfinalizedEnclaveKeyInfo(context.TODO(), eki, registeredMsgID, c.includedHeight))
+
+			cp := newMockCounterparty(clienttypes.NewHeight(0, 9))
+			cp.latestHeight = c.latestHeight
+			cp.clientState = &lcptypes.ClientState{Mrenclave: mrenclave}
+			if c.msgResult != nil {
+				cp.msgResults[registeredMsgID.String()] = *c.msgResult
+			}
+			// the resubmitted msg is included in a new block
+			cp.msgResults[resubmittedMsgID.String()] = mockMsgResult{height: clienttypes.NewHeight(0, 12), success: true}
+
+			updateNeeded, err := pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+			require.NoError(err)
+			require.Equal(c.updateNeeded, updateNeeded)
+			require.Equal(c.reincluded, registrationReorgCount(t, reader, registrationReorgReincluded))
+			require.Equal(c.dropped, registrationReorgCount(t, reader, registrationReorgDropped))
+
+			if !c.resubmitted {
+				require.Empty(cp.sentMsgs)
+			} else {
+				// the same registration is resubmitted without selecting a new key
+				require.Len(cp.sentMsgs, 1)
+				require.Len(cp.sentMsgs[0], 1)
+				msg, ok := cp.sentMsgs[0][0].(*clienttypes.MsgUpdateClient)
+				require.True(ok)
+				var clientMessage exported.ClientMessage
+				require.NoError(pr.codec.UnpackAny(msg.ClientMessage, &clientMessage))
+				message, ok := clientMessage.(*lcptypes.RegisterEnclaveKeyMessage)
+				require.True(ok)
+				require.Equal([]byte(eki.Report), message.Report)
+				require.Equal(eki.Signature, message.Signature)
+				require.Equal(eki.SigningCert, message.SigningCert)
+				require.Equal(resubmittedMsgID.String(), pr.unfinalizedMsgID.String())
+			}
+			if c.updateNeeded {
+				_, _, _, err := pr.loadLastUnfinalizedEnclaveKey(context.TODO())
+				require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+				return
+			}
+
+			require.Equal(eki.EnclaveKeyAddress, pr.activeEnclaveKey.EnclaveKeyAddress)
+			require.Equal(c.expectedHeight, pr.unfinalizedMsgHeight)
+			// the tracked height is persisted
+			_, msgID, includedHeight, err := pr.loadLastUnfinalizedEnclaveKey(context.TODO())
+			require.NoError(err)
+			require.Equal(pr.unfinalizedMsgID.String(), msgID.String())
+			require.Equal(c.expectedHeight, includedHeight)
+		})
+	}
+}
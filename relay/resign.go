@@ -0,0 +1,64 @@
+package relay
+
+import (
+	"errors"
+	"fmt"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// maxUpdateRegenerations is the maximum number of times that the prepared updates are regenerated
+// because they are not signed by the active enclave key
+const maxUpdateRegenerations = 3
+
+// ErrStaleUpdateSigner is returned if the updates are still not signed by the active enclave key after the regenerations
+var ErrStaleUpdateSigner = errors.New("the updates are not signed by the active enclave key")
+
+// findStaleUpdateSigner returns the signer of the first update in `updates` that is not signed by the active enclave key.
+// It returns false if all updates are signed by the active enclave key.
+func (pr *Prover) findStaleUpdateSigner(updates []core.Header) (common.Address, bool, error) {
+	if pr.activeEnclaveKey == nil {
+		return common.Address{}, false, fmt.Errorf("no active enclave key")
+	}
+	active := common.BytesToAddress(pr.activeEnclaveKey.EnclaveKeyAddress)
+	for i, update := range updates {
+		msg, ok := update.(*lcptypes.UpdateClientMessage)
+		if !ok {
+			return common.Address{}, false, fmt.Errorf("unexpected update type: i=%v type=%T", i, update)
+		} else if len(msg.Signatures) != 1 {
+			return common.Address{}, false, fmt.Errorf("the update must have exactly one signature: i=%v num_signatures=%v", i, len(msg.Signatures))
+		}
+		signer, err := lcptypes.VerifySignature(msg.ProxyMessage, msg.Signatures[0])
+		if err != nil {
+			return common.Address{}, false, fmt.Errorf("%w: i=%v %v", ErrInvalidEnclaveSignature, i, err)
+		}
+		if signer != active {
+			return signer, true, nil
+		}
+	}
+	return common.Address{}, false, nil
+}
+
+// regenerateStaleUpdates returns `updates` as they are if they are signed by the active enclave key.
+// Otherwise, e.g. the key has been rotated since they were prepared, they are regenerated with `regenerate`,
+// which must apply the same headers to the ELC client again with the active enclave key.
+// The active key may be rotated again during the regeneration, so it is repeated up to maxUpdateRegenerations times.
+func (pr *Prover) regenerateStaleUpdates(updates []core.Header, regenerate func() ([]core.Header, error)) ([]core.Header, error) {
+	for i := 0; ; i++ {
+		signer, stale, err := pr.findStaleUpdateSigner(updates)
+		if err != nil {
+			return nil, err
+		} else if !stale {
+			return updates, nil
+		} else if i == maxUpdateRegenerations {
+			return nil, fmt.Errorf("%w: signer=%v active=%v regenerations=%v", ErrStaleUpdateSigner, signer.Hex(), common.BytesToAddress(pr.activeEnclaveKey.EnclaveKeyAddress).Hex(), i)
+		}
+		pr.getLogger().Warn("regenerate the updates since they are not signed by the active enclave key",
+			"signer", signer.Hex(), "active_enclave_key", common.BytesToAddress(pr.activeEnclaveKey.EnclaveKeyAddress).Hex(), "regenerations", i+1)
+		if updates, err = regenerate(); err != nil {
+			return nil, fmt.Errorf("failed to regenerate the updates: %w", err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package relay
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"os"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// mockMultiKeyLCPService signs the responses of UpdateClient with the enclave key requested as the signer
+type mockMultiKeyLCPService struct {
+	*mockLCPService
+	keys map[common.Address]*ecdsa.PrivateKey
+	// called after each UpdateClient, e.g. to rotate the active enclave key during the generation
+	afterUpdate func()
+}
+
+func (s *mockMultiKeyLCPService) UpdateClient(ctx context.Context, in *elc.MsgUpdateClient, opts ...grpc.CallOption) (*elc.MsgUpdateClientResponse, error) {
+	s.key = s.keys[common.BytesToAddress(in.Signer)]
+	res, err := s.mockLCPService.UpdateClient(ctx, in, opts...)
+	if s.afterUpdate != nil {
+		s.afterUpdate()
+	}
+	return res, err
+}
+
+func newTestEnclaveKey(t *testing.T) (*ecdsa.PrivateKey, *enclave.EnclaveKeyInfo) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return key, &enclave.EnclaveKeyInfo{EnclaveKeyAddress: crypto.PubkeyToAddress(key.PublicKey).Bytes()}
+}
+
+func updateSigners(t *testing.T, updates []core.Header) []common.Address {
+	var signers []common.Address
+	for _, update := range updates {
+		msg := update.(*lcptypes.UpdateClientMessage)
+		signer, err := lcptypes.VerifySignature(msg.ProxyMessage, msg.Signatures[0])
+		require.NoError(t, err)
+		signers = append(signers, signer)
+	}
+	return signers
+}
+
+func TestRegenerateStaleUpdates(t *testing.T) {
+	const elcClientID = "07-tendermint-0"
+	newProver := func(t *testing.T) (*Prover, *mockMultiKeyLCPService, []*enclave.EnclaveKeyInfo) {
+		service := &mockMultiKeyLCPService{
+			mockLCPService: &mockLCPService{t: t, clients: map[string]*lcptypes.ClientState{
+				elcClientID: {LatestHeight: clienttypes.NewHeight(0, 5)},
+			}},
+			keys: make(map[common.Address]*ecdsa.PrivateKey),
+		}
+		var ekis []*enclave.EnclaveKeyInfo
+		for i := 0; i < 2; i++ {
+			key, eki := newTestEnclaveKey(t)
+			service.keys[common.BytesToAddress(eki.EnclaveKeyAddress)] = key
+			ekis = append(ekis, eki)
+		}
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = t.TempDir()
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.ElcClientId = elcClientID
+		pr.originProver = mockOverlappingOriginProver{
+			mockSelfTestOriginProver: mockSelfTestOriginProver{latestHeight: clienttypes.NewHeight(0, 8)},
+			headers:                  []core.Header{newTestOriginHeader(t, 6), newTestOriginHeader(t, 7), newTestOriginHeader(t, 8)},
+		}
+		pr.lcpServiceClient = LCPServiceClient{ELCMsgClient: service, ELCQueryClient: service}
+		pr.activeEnclaveKey = ekis[0]
+		require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr, service, ekis
+	}
+
+	t.Run("rotation between the preparation and the submission", func(t *testing.T) {
+		require := require.New(t)
+		pr, _, ekis := newProver(t)
+		counterparty := newMockCounterparty(clienttypes.NewHeight(0, 5))
+		headers, err := pr.setupOriginHeadersForUpdate(context.TODO(), counterparty, mockHeader{height: clienttypes.NewHeight(0, 8)})
+		require.NoError(err)
+		updates, err := pr.generateUpdates(context.TODO(), counterparty, headers)
+		require.NoError(err)
+		require.Len(updates, 3)
+
+		pr.activeEnclaveKey = ekis[1]
+		var regenerations int
+		updates, err = pr.regenerateStaleUpdates(updates, func() ([]core.Header, error) {
+			regenerations++
+			return pr.generateUpdates(context.TODO(), counterparty, headers)
+		})
+		require.NoError(err)
+		require.Equal(1, regenerations)
+		require.Len(updates, 3)
+		active := common.BytesToAddress(ekis[1].EnclaveKeyAddress)
+		require.Equal([]common.Address{active, active, active}, updateSigners(t, updates))
+	})
+
+	t.Run("updates signed by the active key", func(t *testing.T) {
+		require := require.New(t)
+		pr, _, _ := newProver(t)
+		updates, err := pr.setupHeadersForUpdate(context.TODO(), newMockCounterparty(clienttypes.NewHeight(0, 5)), mockHeader{height: clienttypes.NewHeight(0, 8)})
+		require.NoError(err)
+		res, err := pr.regenerateStaleUpdates(updates, func() ([]core.Header, error) {
+			t.Fatal("the updates must not be regenerated")
+			return nil, nil
+		})
+		require.NoError(err)
+		require.Equal(updates, res)
+	})
+
+	t.Run("rotation during every regeneration", func(t *testing.T) {
+		require := require.New(t)
+		pr, service, ekis := newProver(t)
+		counterparty := newMockCounterparty(clienttypes.NewHeight(0, 5))
+		headers, err := pr.setupOriginHeadersForUpdate(context.TODO(), counterparty, mockHeader{height: clienttypes.NewHeight(0, 8)})
+		require.NoError(err)
+		updates, err := pr.generateUpdates(context.TODO(), counterparty, headers)
+		require.NoError(err)
+
+		// the active key is switched after each message is generated
+		rotate := func() {
+			if common.BytesToAddress(pr.activeEnclaveKey.EnclaveKeyAddress) == common.BytesToAddress(ekis[0].EnclaveKeyAddress) {
+				pr.activeEnclaveKey = ekis[1]
+			} else {
+				pr.activeEnclaveKey = ekis[0]
+			}
+		}
+		rotate()
+		service.afterUpdate = rotate
+		var regenerations int
+		_, err = pr.regenerateStaleUpdates(updates, func() ([]core.Header, error) {
+			regenerations++
+			// the last message is generated with the other key
+			return pr.generateUpdates(context.TODO(), counterparty, headers[len(headers)-1:])
+		})
+		require.ErrorIs(err, ErrStaleUpdateSigner)
+		require.Equal(maxUpdateRegenerations, regenerations)
+	})
+
+	t.Run("activate client", func(t *testing.T) {
+		require := require.New(t)
+		pr, _, ekis := newProver(t)
+		headers, updates, err := pr.setupActivateClientUpdates(newMockCounterparty(clienttypes.NewHeight(0, 5)), 0, 0)
+		require.NoError(err)
+		require.Len(headers, 3)
+		require.Len(updates, 3)
+
+		pr.activeEnclaveKey = ekis[1]
+		updates, err = pr.regenerateStaleUpdates(updates, func() ([]core.Header, error) {
+			return pr.regenerateActivateClientUpdates(headers)
+		})
+		require.NoError(err)
+		require.Len(updates, 3)
+		active := common.BytesToAddress(ekis[1].EnclaveKeyAddress)
+		require.Equal([]common.Address{active, active, active}, updateSigners(t, updates))
+	})
+}
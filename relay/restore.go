@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"reflect"
 
 	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
 	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
@@ -60,22 +59,19 @@ func (pr *Prover) restoreELC(ctx context.Context, counterparty core.FinalityAwar
 	// Validate the prover config matches the counterparty's client state
 
 	if !bytes.Equal(pr.config.GetMrenclave(), clientState.Mrenclave) {
-		return fmt.Errorf("mrenclave mismatch: expected %v, but got %v", pr.config.GetMrenclave(), clientState.Mrenclave)
+		return fmt.Errorf("mrenclave mismatch: expected %v, but got %v", lcptypes.HexBytes(pr.config.GetMrenclave()), lcptypes.HexBytes(clientState.Mrenclave))
 	}
 	if pr.config.KeyExpiration != clientState.KeyExpiration {
 		return fmt.Errorf("key expiration mismatch: expected %v, but got %v", pr.config.KeyExpiration, clientState.KeyExpiration)
 	}
-	if len(pr.config.AllowedQuoteStatuses) != len(clientState.AllowedQuoteStatuses) {
-		return fmt.Errorf("allowed quote statuses mismatch: expected %v, but got %v", pr.config.AllowedQuoteStatuses, clientState.AllowedQuoteStatuses)
+	if !equalNormalizedValues(pr.allowedQuoteStatuses(), clientState.AllowedQuoteStatuses, lcptypes.NormalizeQuoteStatus) {
+		return fmt.Errorf("allowed quote statuses mismatch: expected %v, but got %v", pr.allowedQuoteStatuses(), clientState.AllowedQuoteStatuses)
 	}
-	if !reflect.DeepEqual(pr.config.AllowedQuoteStatuses, clientState.AllowedQuoteStatuses) {
-		return fmt.Errorf("allowed advisory ids mismatch: expected %v, but got %v", pr.config.AllowedAdvisoryIds, clientState.AllowedAdvisoryIds)
-	}
-	if !reflect.DeepEqual(pr.config.AllowedAdvisoryIds, clientState.AllowedAdvisoryIds) {
-		return fmt.Errorf("allowed advisory ids mismatch: expected %v, but got %v", pr.config.AllowedAdvisoryIds, clientState.AllowedAdvisoryIds)
+	if !equalNormalizedValues(pr.allowedAdvisoryIDs(), clientState.AllowedAdvisoryIds, lcptypes.NormalizeAdvisoryID) {
+		return fmt.Errorf("allowed advisory ids mismatch: expected %v, but got %v", pr.allowedAdvisoryIDs(), clientState.AllowedAdvisoryIds)
 	}
 
-	originClientState, originConsensusState, err := pr.originProver.CreateInitialLightClientState(clientState.LatestHeight)
+	originClientState, originConsensusState, err := pr.initialLightClientStates.get(pr.originProver, clientState.LatestHeight)
 	if err != nil {
 		return fmt.Errorf("failed to create initial light client state: height=%v %w", clientState.LatestHeight, err)
 	}
@@ -100,6 +96,10 @@ func (pr *Prover) restoreELC(ctx context.Context, counterparty core.FinalityAwar
 	if err != nil {
 		return fmt.Errorf("failed to create ELC client: elc_client_id=%v %w", elcClientID, err)
 	}
+	pr.initialLightClientStates.invalidate()
+	if err := pr.saveELCOriginClientType(ctx, elcClientID, originAnyClientState.TypeUrl); err != nil {
+		return err
+	}
 
 	// Ensure the restored state is correct
 
@@ -112,7 +112,8 @@ func (pr *Prover) restoreELC(ctx context.Context, counterparty core.FinalityAwar
 		return err
 	}
 	if !usm.PostStateID.EqualBytes(consensusState.StateId) {
-		return fmt.Errorf("unexpected state id: expected %v, but got %v", usm.PostStateID, consensusState.StateId)
+		pr.alert(AlertStateDivergence, elcClientID, "the restored ELC state diverges from the counterparty LCP client", "elc_state_id", usm.PostStateID.String(), "counterparty_state_id", lcptypes.HexBytes(consensusState.StateId).String(), "height", restoreHeight)
+		return fmt.Errorf("unexpected state id: expected %v, but got %v", usm.PostStateID, lcptypes.HexBytes(consensusState.StateId))
 	}
 	if !usm.PostHeight.EQ(restoreHeight) {
 		return fmt.Errorf("unexpected height: expected %v, but got %v", restoreHeight, usm.PostHeight)
@@ -122,3 +123,16 @@ func (pr *Prover) restoreELC(ctx context.Context, counterparty core.FinalityAwar
 
 	return nil
 }
+
+// equalNormalizedValues returns true if `a` and `b` are equal after normalizing each value with `normalize`
+func equalNormalizedValues(a, b []string, normalize func(string) string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if normalize(a[i]) != normalize(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	host "github.com/cosmos/ibc-go/v8/modules/core/24-host"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// SelfTestStatus is the status of a step of the self-test
+type SelfTestStatus string
+
+const (
+	SelfTestPassed SelfTestStatus = "passed"
+	SelfTestFailed SelfTestStatus = "failed"
+	// the step is not executed because a previous step failed
+	SelfTestSkipped SelfTestStatus = "skipped"
+)
+
+const (
+	SelfTestStepCreateELC   = "create_elc"
+	SelfTestStepUpdateELC   = "update_elc"
+	SelfTestStepProveState  = "prove_state"
+	SelfTestStepVerifyProof = "verify_proof"
+	SelfTestStepCleanup     = "cleanup"
+)
+
+// selfTestELCClientIDPrefix is the prefix of the temporary ELC client IDs to identify them in the LCP service
+const selfTestELCClientIDPrefix = "lcp-selftest-"
+
+// SelfTestStep is the result of a step of the self-test
+type SelfTestStep struct {
+	Name    string            `json:"name"`
+	Status  SelfTestStatus    `json:"status"`
+	Error   string            `json:"error,omitempty"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// SelfTestResult is the report of the self-test
+type SelfTestResult struct {
+	ELCClientID string          `json:"elc_client_id"`
+	Passed      bool            `json:"passed"`
+	Steps       []*SelfTestStep `json:"steps"`
+}
+
+// Step returns the result of the step with the given name or nil
+func (r *SelfTestResult) Step(name string) *SelfTestStep {
+	for _, step := range r.Steps {
+		if step.Name == name {
+			return step
+		}
+	}
+	return nil
+}
+
+func newSelfTestELCClientID(now time.Time) string {
+	return fmt.Sprintf("%s%d", selfTestELCClientIDPrefix, now.UnixNano())
+}
+
+// doSelfTest exercises the pipeline of the prover against a temporary ELC client `elcClientID` without touching the counterparty chain:
+// it creates the client for the origin chain, updates it, proves the client state of the origin chain and verifies the proof locally.
+// The local records of the temporary client are removed even if a step fails.
+// NOTE: the client itself remains in the LCP service because the service provides no API to delete ELC clients.
+func (pr *Prover) doSelfTest(ctx context.Context, elcClientID string) *SelfTestResult {
+	result := &SelfTestResult{ELCClientID: elcClientID, Passed: true}
+	run := func(name string, fn func(details map[string]string) error) {
+		step := &SelfTestStep{Name: name, Details: make(map[string]string)}
+		result.Steps = append(result.Steps, step)
+		if !result.Passed && name != SelfTestStepCleanup {
+			step.Status = SelfTestSkipped
+			return
+		}
+		if err := fn(step.Details); err != nil {
+			pr.getLogger().Error("self-test step failed", err, "step", name, "elc_client_id", elcClientID)
+			step.Status, step.Error, result.Passed = SelfTestFailed, err.Error(), false
+			return
+		}
+		pr.getLogger().Info("self-test step passed", "step", name, "elc_client_id", elcClientID)
+		step.Status = SelfTestPassed
+	}
+
+	// the enclave key selected for the temporary client must not replace the active one
+	activeEnclaveKey := pr.activeEnclaveKey
+	defer func() { pr.activeEnclaveKey = activeEnclaveKey }()
+
+	var (
+		// the client must not be cleaned up if it exists before the self-test
+		preexisting bool
+		// the latest height of the temporary client
+		elcHeight   clienttypes.Height
+		path        string
+		value       []byte
+		proof       []byte
+		proofHeight clienttypes.Height
+	)
+	run(SelfTestStepCreateELC, func(details map[string]string) error {
+		res, err := pr.lcpServiceClient.Client(ctx, &elc.QueryClientRequest{ClientId: elcClientID})
+		if err != nil {
+			return err
+		} else if res.Found {
+			preexisting = true
+			return fmt.Errorf("the ELC client already exists: elc_client_id=%v", elcClientID)
+		}
+		header, err := pr.originProver.GetLatestFinalizedHeader()
+		if err != nil {
+			return err
+		}
+		// create the client at the previous height to exercise an update
+		height := LatestHeightSpec
+		if latest := header.GetHeight(); latest.GetRevisionHeight() > 1 {
+			height = NewHeightSpec(clienttypes.NewHeight(latest.GetRevisionNumber(), latest.GetRevisionHeight()-1))
+		}
+		created, err := pr.doCreateELC(elcClientID, height)
+		if err != nil {
+			return err
+		} else if !created.Created {
+			return fmt.Errorf("the ELC client is not created: elc_client_id=%v", elcClientID)
+		}
+		elcHeight = created.Message.PostHeight
+		details["height"] = elcHeight.String()
+		details["state_id"] = created.Message.PostStateID.String()
+		return nil
+	})
+	run(SelfTestStepUpdateELC, func(details map[string]string) error {
+		updated, err := pr.doUpdateELC(elcClientID, nil)
+		if err != nil {
+			return err
+		}
+		for _, msg := range updated.Messages {
+			elcHeight = msg.PostHeight
+		}
+		details["updates"] = fmt.Sprint(len(updated.Messages))
+		details["height"] = elcHeight.String()
+		return nil
+	})
+	run(SelfTestStepProveState, func(details map[string]string) error {
+		queryCtx := core.NewQueryContext(ctx, elcHeight)
+		// the client state of the origin chain's path end is a known store path
+		path = host.FullClientStatePath(pr.originChain.Path().ClientID)
+		res, err := pr.originChain.QueryClientState(queryCtx)
+		if err != nil {
+			return fmt.Errorf("failed to query the client state: path=%v %w", path, err)
+		}
+		value, err = pr.codec.Marshal(res.ClientState)
+		if err != nil {
+			return err
+		}
+		proof, proofHeight, err = pr.proveStateWithELC(queryCtx, elcClientID, path, value)
+		if err != nil {
+			return err
+		}
+		details["path"] = path
+		details["proof_height"] = proofHeight.String()
+		return nil
+	})
+	run(SelfTestStepVerifyProof, func(details map[string]string) error {
+		replayed, err := ReplayArchivedProof(&ArchivedProof{
+			ELCClientID: elcClientID,
+			Path:        path,
+			Value:       value,
+			ProofHeight: proofHeight,
+			Proof:       proof,
+			Signer:      pr.activeEnclaveKey.EnclaveKeyAddress,
+			Timestamp:   time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		details["signer"] = replayed.Signers[0].Hex()
+		details["state_id"] = replayed.StateID
+		return nil
+	})
+	run(SelfTestStepCleanup, func(details map[string]string) error {
+		if preexisting {
+			details["note"] = "the existing client is kept"
+			return nil
+		}
+		details["note"] = "the client remains in the LCP service because the service provides no API to delete ELC clients"
+		return pr.removeELCOriginClientType(ctx, elcClientID)
+	})
+	return result
+}
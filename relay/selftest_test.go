@@ -0,0 +1,320 @@
+package relay
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// newTestUpdateStateMessage returns a headered UpdateState proxy message updating the client from `prev` to `post`
+func newTestUpdateStateMessage(t *testing.T, prev, post clienttypes.Height) []byte {
+	bz, err := encodeTestUpdateStateMessage(prev, post)
+	require.NoError(t, err)
+	return bz
+}
+
+func encodeTestUpdateStateMessage(prev, post clienttypes.Height) ([]byte, error) {
+	heightComponents := []abi.ArgumentMarshaling{
+		{Name: "revision_number", Type: "uint64"},
+		{Name: "revision_height", Type: "uint64"},
+	}
+	contextABI, err := abi.NewType("tuple", "struct HeaderedMessageContext", []abi.ArgumentMarshaling{
+		{Name: "header", Type: "bytes32"},
+		{Name: "context_bytes", Type: "bytes"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var contextHeader [32]byte
+	binary.BigEndian.PutUint16(contextHeader[:2], lcptypes.LCPMessageContextTypeEmpty)
+	context, err := abi.Arguments{{Type: contextABI}}.Pack(struct {
+		Header       [32]byte `json:"header"`
+		ContextBytes []byte   `json:"context_bytes"`
+	}{Header: contextHeader, ContextBytes: []byte{}})
+	if err != nil {
+		return nil, err
+	}
+
+	messageABI, err := abi.NewType("tuple", "struct UpdateStateProxyMessage", []abi.ArgumentMarshaling{
+		{Name: "prev_height", Type: "tuple", Components: heightComponents},
+		{Name: "prev_state_id", Type: "bytes32"},
+		{Name: "post_height", Type: "tuple", Components: heightComponents},
+		{Name: "post_state_id", Type: "bytes32"},
+		{Name: "timestamp", Type: "uint128"},
+		{Name: "context", Type: "bytes"},
+		{Name: "emitted_states", Type: "tuple[]", Components: []abi.ArgumentMarshaling{
+			{Name: "height", Type: "tuple", Components: heightComponents},
+			{Name: "state", Type: "bytes"},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	message, err := abi.Arguments{{Type: messageABI}}.Pack(struct {
+		PrevHeight    testABIHeight `json:"prev_height"`
+		PrevStateId   [32]byte      `json:"prev_state_id"`
+		PostHeight    testABIHeight `json:"post_height"`
+		PostStateId   [32]byte      `json:"post_state_id"`
+		Timestamp     *big.Int      `json:"timestamp"`
+		Context       []byte        `json:"context"`
+		EmittedStates []struct {
+			Height testABIHeight `json:"height"`
+			State  []byte        `json:"state"`
+		} `json:"emitted_states"`
+	}{
+		PrevHeight:  testABIHeight{RevisionNumber: prev.RevisionNumber, RevisionHeight: prev.RevisionHeight},
+		PrevStateId: [32]byte{byte(prev.RevisionHeight)},
+		PostHeight:  testABIHeight{RevisionNumber: post.RevisionNumber, RevisionHeight: post.RevisionHeight},
+		PostStateId: [32]byte{byte(post.RevisionHeight)},
+		Timestamp:   big.NewInt(1),
+		Context:     context,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	headeredABI, err := abi.NewType("tuple", "struct HeaderedMessage", []abi.ArgumentMarshaling{
+		{Name: "header", Type: "bytes32"},
+		{Name: "message", Type: "bytes"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var header [32]byte
+	binary.BigEndian.PutUint16(header[:2], lcptypes.LCPMessageVersion)
+	binary.BigEndian.PutUint16(header[2:4], lcptypes.LCPMessageTypeUpdateState)
+	bz, err := abi.Arguments{{Type: headeredABI}}.Pack(struct {
+		Header  [32]byte `json:"header"`
+		Message []byte   `json:"message"`
+	}{Header: header, Message: message})
+	if err != nil {
+		return nil, err
+	}
+	return bz, nil
+}
+
+// newTestOriginHeaders returns `n` consecutive headers of an origin chain ending at `latest`
+func newTestOriginHeaders(latest exported.Height, n int) ([]core.Header, error) {
+	var headers []core.Header
+	for i := n - 1; i >= 0; i-- {
+		post := clienttypes.NewHeight(latest.GetRevisionNumber(), latest.GetRevisionHeight()-uint64(i))
+		message, err := encodeTestUpdateStateMessage(clienttypes.NewHeight(post.RevisionNumber, post.RevisionHeight-1), post)
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, &lcptypes.UpdateClientMessage{ProxyMessage: message})
+	}
+	return headers, nil
+}
+
+// mockLCPService is an in-memory LCP service signing the responses with `key`
+type mockLCPService struct {
+	elc.MsgClient
+	elc.QueryClient
+	t         *testing.T
+	key       *ecdsa.PrivateKey
+	clients   map[string]*lcptypes.ClientState
+	updateErr error
+}
+
+func (s *mockLCPService) sign(message []byte) []byte {
+	signature, err := crypto.Sign(crypto.Keccak256(message), s.key)
+	require.NoError(s.t, err)
+	return signature
+}
+
+func (s *mockLCPService) CreateClient(ctx context.Context, in *elc.MsgCreateClient, opts ...grpc.CallOption) (*elc.MsgCreateClientResponse, error) {
+	var cs lcptypes.ClientState
+	if err := cs.Unmarshal(in.ClientState.Value); err != nil {
+		return nil, err
+	}
+	s.clients[in.ClientId] = &cs
+	message := newTestUpdateStateMessage(s.t, clienttypes.Height{}, cs.LatestHeight)
+	return &elc.MsgCreateClientResponse{Message: message, Signature: s.sign(message)}, nil
+}
+
+func (s *mockLCPService) UpdateClient(ctx context.Context, in *elc.MsgUpdateClient, opts ...grpc.CallOption) (*elc.MsgUpdateClientResponse, error) {
+	if s.updateErr != nil {
+		return nil, s.updateErr
+	}
+	cs := s.clients[in.ClientId]
+	var header lcptypes.UpdateClientMessage
+	if err := header.Unmarshal(in.Header.Value); err != nil {
+		return nil, err
+	}
+	prev := cs.LatestHeight
+	cs.LatestHeight = clienttypes.NewHeight(prev.RevisionNumber, prev.RevisionHeight+1)
+	message := newTestUpdateStateMessage(s.t, prev, cs.LatestHeight)
+	return &elc.MsgUpdateClientResponse{Message: message, Signature: s.sign(message)}, nil
+}
+
+func (s *mockLCPService) VerifyMembership(ctx context.Context, in *elc.MsgVerifyMembership, opts ...grpc.CallOption) (*elc.MsgVerifyMembershipResponse, error) {
+	message := newTestVerifyMembershipMessage(s.t, in.Path, in.Value, in.ProofHeight)
+	return &elc.MsgVerifyMembershipResponse{Message: message, Signature: s.sign(message)}, nil
+}
+
+func (s *mockLCPService) VerifyNonMembership(ctx context.Context, in *elc.MsgVerifyNonMembership, opts ...grpc.CallOption) (*elc.MsgVerifyNonMembershipResponse, error) {
+	message := newTestVerifyNonMembershipMessage(s.t, in.Path, in.ProofHeight)
+	return &elc.MsgVerifyNonMembershipResponse{Message: message, Signature: s.sign(message)}, nil
+}
+
+func (s *mockLCPService) Client(ctx context.Context, in *elc.QueryClientRequest, opts ...grpc.CallOption) (*elc.QueryClientResponse, error) {
+	cs, ok := s.clients[in.ClientId]
+	if !ok {
+		return &elc.QueryClientResponse{Found: false}, nil
+	}
+	anyClientState, err := clienttypes.PackClientState(cs)
+	if err != nil {
+		return nil, err
+	}
+	return &elc.QueryClientResponse{Found: true, ClientState: anyClientState}, nil
+}
+
+// mockSelfTestOriginProver is the prover of an origin chain whose latest finalized height is `latestHeight`
+type mockSelfTestOriginProver struct {
+	core.Prover
+	latestHeight clienttypes.Height
+}
+
+func (p mockSelfTestOriginProver) GetLatestFinalizedHeader() (core.Header, error) {
+	return mockHeader{height: p.latestHeight}, nil
+}
+
+func (p mockSelfTestOriginProver) CreateInitialLightClientState(height exported.Height) (exported.ClientState, exported.ConsensusState, error) {
+	return &lcptypes.ClientState{LatestHeight: height.(clienttypes.Height)}, &lcptypes.ConsensusState{}, nil
+}
+
+func (p mockSelfTestOriginProver) SetupHeadersForUpdate(counterparty core.FinalityAwareChain, latestFinalizedHeader core.Header) ([]core.Header, error) {
+	return newTestOriginHeaders(latestFinalizedHeader.GetHeight(), 1)
+}
+
+func (p mockSelfTestOriginProver) ProveState(ctx core.QueryContext, path string, value []byte) ([]byte, clienttypes.Height, error) {
+	return []byte("proof"), ctx.Height().(clienttypes.Height), nil
+}
+
+func TestSelfTest(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	eki := loadTestEnclaveKeyInfo(t)
+
+	var cases = []struct {
+		name      string
+		updateErr error
+		// the client exists before the self-test
+		preexisting bool
+		statuses    map[string]SelfTestStatus
+	}{
+		{
+			name: "passed",
+			statuses: map[string]SelfTestStatus{
+				SelfTestStepCreateELC:   SelfTestPassed,
+				SelfTestStepUpdateELC:   SelfTestPassed,
+				SelfTestStepProveState:  SelfTestPassed,
+				SelfTestStepVerifyProof: SelfTestPassed,
+				SelfTestStepCleanup:     SelfTestPassed,
+			},
+		},
+		{
+			name:      "update failed",
+			updateErr: errors.New("update failed"),
+			statuses: map[string]SelfTestStatus{
+				SelfTestStepCreateELC:   SelfTestPassed,
+				SelfTestStepUpdateELC:   SelfTestFailed,
+				SelfTestStepProveState:  SelfTestSkipped,
+				SelfTestStepVerifyProof: SelfTestSkipped,
+				SelfTestStepCleanup:     SelfTestPassed,
+			},
+		},
+		{
+			name:        "client already exists",
+			preexisting: true,
+			statuses: map[string]SelfTestStatus{
+				SelfTestStepCreateELC:   SelfTestFailed,
+				SelfTestStepUpdateELC:   SelfTestSkipped,
+				SelfTestStepProveState:  SelfTestSkipped,
+				SelfTestStepVerifyProof: SelfTestSkipped,
+				SelfTestStepCleanup:     SelfTestPassed,
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			key, err := crypto.GenerateKey()
+			require.NoError(err)
+			elcClientID := newSelfTestELCClientID(time.Now())
+
+			service := &mockLCPService{t: t, key: key, clients: make(map[string]*lcptypes.ClientState), updateErr: c.updateErr}
+			if c.preexisting {
+				service.clients[elcClientID] = &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, 1)}
+			}
+			pr := newTestProver(t)
+			pr.codec = newTestCodec()
+			pr.homePath = t.TempDir()
+			pr.originProver = mockSelfTestOriginProver{latestHeight: clienttypes.NewHeight(0, 10)}
+			pr.originChain = &mockCounterparty{chainID: "origin", clientState: &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, 5)}}
+			pr.config.AllowedQuoteStatuses = []string{"GROUP_OUT_OF_DATE"}
+			pr.config.AllowedAdvisoryIds = []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-00334", "INTEL-SA-00477", "INTEL-SA-00614", "INTEL-SA-00615", "INTEL-SA-00617", "INTEL-SA-00828"}
+			pr.config.AllowDebugEnclaveKeys = true
+			pr.config.Mrenclave = testMrenclave(t, eki)
+			pr.lcpServiceClient = LCPServiceClient{
+				ELCMsgClient:       service,
+				ELCQueryClient:     service,
+				EnclaveQueryClient: mockEnclaveQueryClient{availableKeys: []*enclave.EnclaveKeyInfo{eki}},
+			}
+			activeEnclaveKey := &enclave.EnclaveKeyInfo{EnclaveKeyAddress: crypto.PubkeyToAddress(key.PublicKey).Bytes()}
+			pr.activeEnclaveKey = activeEnclaveKey
+			require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+			if c.preexisting {
+				require.NoError(pr.saveELCOriginClientType(context.TODO(), elcClientID, "/ibc.lightclients.lcp.v1.ClientState"))
+			}
+
+			result := pr.doSelfTest(context.TODO(), elcClientID)
+			require.Equal(elcClientID, result.ELCClientID)
+			require.Len(result.Steps, len(c.statuses))
+			passed := true
+			for name, status := range c.statuses {
+				step := result.Step(name)
+				require.NotNil(step, name)
+				require.Equal(status, step.Status, name)
+				if status == SelfTestFailed {
+					require.NotEmpty(step.Error, name)
+					passed = false
+				}
+			}
+			require.Equal(passed, result.Passed)
+			// the active key is not replaced by the self-test
+			require.Equal(activeEnclaveKey, pr.activeEnclaveKey)
+
+			types, err := pr.loadELCOriginClientTypes(context.TODO())
+			require.NoError(err)
+			_, recorded := types[elcClientID]
+			// the records of the preexisting client are kept
+			require.Equal(c.preexisting, recorded)
+
+			if !passed {
+				return
+			}
+			require.Equal("0-10", result.Step(SelfTestStepUpdateELC).Details["height"])
+			require.Equal("0-10", result.Step(SelfTestStepProveState).Details["proof_height"])
+			require.Equal(crypto.PubkeyToAddress(key.PublicKey).Hex(), result.Step(SelfTestStepVerifyProof).Details["signer"])
+		})
+	}
+}
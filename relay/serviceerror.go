@@ -0,0 +1,182 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceErrorCode is a stable code of the errors returned by the LCP service
+type ServiceErrorCode string
+
+const (
+	ServiceErrorUnavailable        ServiceErrorCode = "LCP_SERVICE_UNAVAILABLE"
+	ServiceErrorOutOfMemory        ServiceErrorCode = "LCP_ENCLAVE_OUT_OF_MEMORY"
+	ServiceErrorSealedStateMissing ServiceErrorCode = "LCP_SEALED_STATE_MISSING"
+	ServiceErrorClientNotFound     ServiceErrorCode = "LCP_ELC_CLIENT_NOT_FOUND"
+	ServiceErrorHeaderVerification ServiceErrorCode = "LCP_HEADER_VERIFICATION_FAILED"
+	ServiceErrorSignature          ServiceErrorCode = "LCP_SIGNATURE_FAILED"
+	// the code of the errors that match no known pattern
+	ServiceErrorUnknown ServiceErrorCode = "LCP_UNKNOWN"
+)
+
+// ServiceError is an error returned by the LCP service with a stable code and a remediation hint.
+// The original error is kept as it is, so the gRPC status can be retrieved from it.
+type ServiceError struct {
+	Code   ServiceErrorCode
+	Hint   string
+	Method string
+	Err    error
+}
+
+func (e *ServiceError) Error() string {
+	if e.Code == ServiceErrorUnknown {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v: method=%v hint=%q %v", e.Code, e.Method, e.Hint, e.Err)
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// ServiceErrorCodeOf returns the code of the LCP service error in the chain of `err`, or an empty string if there is none
+func ServiceErrorCodeOf(err error) ServiceErrorCode {
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr.Code
+	}
+	return ""
+}
+
+// serviceErrorPattern maps the errors whose messages match `pattern` to `code`
+type serviceErrorPattern struct {
+	code    ServiceErrorCode
+	pattern *regexp.Regexp
+	hint    string
+}
+
+// serviceErrorPatterns is the mapping table from the error messages of the LCP service to the codes.
+// The patterns are tested in order, so a more specific pattern must precede a general one.
+// When the LCP service changes its error messages, add the new message to TestClassifyServiceError.
+var serviceErrorPatterns = []serviceErrorPattern{
+	{
+		code:    ServiceErrorOutOfMemory,
+		pattern: regexp.MustCompile(`(?i)SGX_ERROR_OUT_OF_MEMORY|out of (enclave )?memory|memory allocation failed`),
+		hint:    "the enclave ran out of memory: increase the heap size of the enclave or reduce the message aggregation batch size, and restart the LCP service",
+	},
+	{
+		code:    ServiceErrorSealedStateMissing,
+		pattern: regexp.MustCompile(`(?i)SGX_ERROR_MAC_MISMATCH|failed to unseal|sealed (enclave )?key[a-z ]* not found|enclave key[a-z ]* not found`),
+		hint:    "the sealed enclave key is missing or cannot be unsealed by this enclave: check the LCP service's home directory and MRENCLAVE, then run `lcp update-enclave-key` or `lcp remove-eki` to register a new key",
+	},
+	{
+		code:    ServiceErrorClientNotFound,
+		pattern: regexp.MustCompile(`(?i)client ?(state)? ?not ?found|client_not_found|consensus ?state ?not ?found`),
+		hint:    "the ELC client does not exist in the LCP service: check elc_client_id, or run `lcp create-elc` or `lcp restore-elc` if the LCP service's state was lost",
+	},
+	{
+		code:    ServiceErrorHeaderVerification,
+		pattern: regexp.MustCompile(`(?i)header verification|verify(ing)? (the )?header|invalid header|not ?enough ?trust|insufficient voting power|trusting period|header.*expired`),
+		hint:    "the ELC rejected the header of the origin chain: check that the origin RPC is synced and honest, and run `lcp restore-elc` if the ELC client is outside the trusting period",
+	},
+	{
+		code:    ServiceErrorSignature,
+		pattern: regexp.MustCompile(`(?i)signature|failed to sign|SGX_ERROR_INVALID_SIGNATURE`),
+		hint:    "the enclave failed to sign or verify a signature: check that the enclave key is registered and not expired, and restart the LCP service if the error persists",
+	},
+}
+
+// classifyServiceError returns the error returned by the LCP service method `method` as a ServiceError.
+// The errors that are not returned by the LCP service, e.g. the cancellation of the context, are returned unchanged.
+func classifyServiceError(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.Canceled || st.Code() == codes.DeadlineExceeded {
+		return err
+	}
+	if st.Code() == codes.Unavailable {
+		return &ServiceError{
+			Code:   ServiceErrorUnavailable,
+			Hint:   "the LCP service is unreachable: check that it is running and lcp_service_address is correct",
+			Method: method,
+			Err:    err,
+		}
+	}
+	for _, p := range serviceErrorPatterns {
+		if p.pattern.MatchString(st.Message()) {
+			return &ServiceError{Code: p.code, Hint: p.hint, Method: method, Err: err}
+		}
+	}
+	return &ServiceError{Code: ServiceErrorUnknown, Method: method, Err: err}
+}
+
+// withErrorClassification returns the client whose errors are classified by classifyServiceError
+func (c LCPServiceClient) withErrorClassification() LCPServiceClient {
+	return LCPServiceClient{
+		ELCMsgClient:       classifiedELCMsgClient{MsgClient: c.ELCMsgClient},
+		ELCQueryClient:     classifiedELCQueryClient{QueryClient: c.ELCQueryClient},
+		EnclaveQueryClient: classifiedEnclaveQueryClient{QueryClient: c.EnclaveQueryClient},
+	}
+}
+
+type classifiedELCMsgClient struct {
+	elc.MsgClient
+}
+
+func (c classifiedELCMsgClient) CreateClient(ctx context.Context, in *elc.MsgCreateClient, opts ...grpc.CallOption) (*elc.MsgCreateClientResponse, error) {
+	res, err := c.MsgClient.CreateClient(ctx, in, opts...)
+	return res, classifyServiceError("CreateClient", err)
+}
+
+func (c classifiedELCMsgClient) UpdateClient(ctx context.Context, in *elc.MsgUpdateClient, opts ...grpc.CallOption) (*elc.MsgUpdateClientResponse, error) {
+	res, err := c.MsgClient.UpdateClient(ctx, in, opts...)
+	return res, classifyServiceError("UpdateClient", err)
+}
+
+func (c classifiedELCMsgClient) AggregateMessages(ctx context.Context, in *elc.MsgAggregateMessages, opts ...grpc.CallOption) (*elc.MsgAggregateMessagesResponse, error) {
+	res, err := c.MsgClient.AggregateMessages(ctx, in, opts...)
+	return res, classifyServiceError("AggregateMessages", err)
+}
+
+func (c classifiedELCMsgClient) VerifyMembership(ctx context.Context, in *elc.MsgVerifyMembership, opts ...grpc.CallOption) (*elc.MsgVerifyMembershipResponse, error) {
+	res, err := c.MsgClient.VerifyMembership(ctx, in, opts...)
+	return res, classifyServiceError("VerifyMembership", err)
+}
+
+func (c classifiedELCMsgClient) VerifyNonMembership(ctx context.Context, in *elc.MsgVerifyNonMembership, opts ...grpc.CallOption) (*elc.MsgVerifyNonMembershipResponse, error) {
+	res, err := c.MsgClient.VerifyNonMembership(ctx, in, opts...)
+	return res, classifyServiceError("VerifyNonMembership", err)
+}
+
+type classifiedELCQueryClient struct {
+	elc.QueryClient
+}
+
+func (c classifiedELCQueryClient) Client(ctx context.Context, in *elc.QueryClientRequest, opts ...grpc.CallOption) (*elc.QueryClientResponse, error) {
+	res, err := c.QueryClient.Client(ctx, in, opts...)
+	return res, classifyServiceError("Client", err)
+}
+
+type classifiedEnclaveQueryClient struct {
+	enclave.QueryClient
+}
+
+func (c classifiedEnclaveQueryClient) AvailableEnclaveKeys(ctx context.Context, in *enclave.QueryAvailableEnclaveKeysRequest, opts ...grpc.CallOption) (*enclave.QueryAvailableEnclaveKeysResponse, error) {
+	res, err := c.QueryClient.AvailableEnclaveKeys(ctx, in, opts...)
+	return res, classifyServiceError("AvailableEnclaveKeys", err)
+}
+
+func (c classifiedEnclaveQueryClient) EnclaveKey(ctx context.Context, in *enclave.QueryEnclaveKeyRequest, opts ...grpc.CallOption) (*enclave.QueryEnclaveKeyResponse, error) {
+	res, err := c.QueryClient.EnclaveKey(ctx, in, opts...)
+	return res, classifyServiceError("EnclaveKey", err)
+}
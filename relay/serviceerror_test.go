@@ -0,0 +1,85 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyServiceError(t *testing.T) {
+	// the messages of the errors returned by the LCP service
+	var cases = []struct {
+		name string
+		err  error
+		// empty if the error is returned unchanged
+		code ServiceErrorCode
+	}{
+		{"unavailable", status.Error(codes.Unavailable, "connection error: desc = \"transport: Error while dialing: dial tcp 127.0.0.1:50051: connect: connection refused\""), ServiceErrorUnavailable},
+		{"ecall out of memory", status.Error(codes.Unknown, "ecall failed: command=UpdateClient status=SGX_ERROR_OUT_OF_MEMORY"), ServiceErrorOutOfMemory},
+		{"allocation failure", status.Error(codes.Unknown, "enclave command error: memory allocation failed: size=67108864"), ServiceErrorOutOfMemory},
+		{"unseal failure", status.Error(codes.Unknown, "SealingError: failed to unseal the enclave key: SGX_ERROR_MAC_MISMATCH"), ServiceErrorSealedStateMissing},
+		{"enclave key not found", status.Error(codes.NotFound, "enclave key not found: address=0xcb96f8d6c2d543102184d679d7829b39434e4eec"), ServiceErrorSealedStateMissing},
+		{"sealed key not found", status.Error(codes.Unknown, "sealed enclave key file not found: path=/root/.lcp/keys/cb96f8d6"), ServiceErrorSealedStateMissing},
+		{"client not found", status.Error(codes.Unknown, "light client error: client not found: client_id=07-tendermint-0"), ServiceErrorClientNotFound},
+		{"client state not found", status.Error(codes.Unknown, "ClientStateNotFound { client_id: ClientId(\"07-tendermint-3\") }"), ServiceErrorClientNotFound},
+		{"consensus state not found", status.Error(codes.Unknown, "ConsensusStateNotFound { client_id: 07-tendermint-0, height: 0-120 }"), ServiceErrorClientNotFound},
+		{"not enough trust", status.Error(codes.Unknown, "header verification failed: NotEnoughTrust(VotingPowerTally { total: 100, tallied: 10, trust_threshold: 1/3 })"), ServiceErrorHeaderVerification},
+		{"insufficient voting power", status.Error(codes.Unknown, "tendermint error: invalid commit: insufficient voting power: tallied=10 total=100"), ServiceErrorHeaderVerification},
+		{"trusting period", status.Error(codes.Unknown, "header timestamp is outside the trusting period: trusted=2024-01-01T00:00:00Z"), ServiceErrorHeaderVerification},
+		{"invalid signature", status.Error(codes.Unknown, "SignatureError: invalid signature: ek=0xcb96f8d6c2d543102184d679d7829b39434e4eec"), ServiceErrorSignature},
+		{"signing failure", status.Error(codes.Unknown, "failed to sign the message: SGX_ERROR_INVALID_SIGNATURE"), ServiceErrorSignature},
+		{"unknown", status.Error(codes.Internal, "unexpected panic in the command handler"), ServiceErrorUnknown},
+		{"deadline exceeded", status.Error(codes.DeadlineExceeded, "context deadline exceeded"), ""},
+		{"canceled", status.Error(codes.Canceled, "context canceled"), ""},
+		{"not a status", errors.New("rate limited"), ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			err := classifyServiceError("UpdateClient", c.err)
+			require.ErrorIs(err, c.err)
+			require.Equal(c.code, ServiceErrorCodeOf(err))
+			switch c.code {
+			case "":
+				require.Equal(c.err, err)
+			case ServiceErrorUnknown:
+				require.Equal(c.err.Error(), err.Error())
+			default:
+				require.ErrorContains(err, string(c.code))
+				require.ErrorContains(err, "hint=")
+				require.ErrorContains(err, c.err.Error())
+			}
+		})
+	}
+	require.NoError(t, classifyServiceError("UpdateClient", nil))
+}
+
+type mockFailingELCMsgClient struct {
+	elc.MsgClient
+	err error
+}
+
+func (c mockFailingELCMsgClient) UpdateClient(ctx context.Context, in *elc.MsgUpdateClient, opts ...grpc.CallOption) (*elc.MsgUpdateClientResponse, error) {
+	return nil, c.err
+}
+
+func TestServiceErrorClassificationWrapper(t *testing.T) {
+	require := require.New(t)
+	client := LCPServiceClient{
+		ELCMsgClient: mockFailingELCMsgClient{err: status.Error(codes.Unknown, "ecall failed: status=SGX_ERROR_OUT_OF_MEMORY")},
+	}.withErrorClassification()
+	_, err := client.UpdateClient(context.TODO(), &elc.MsgUpdateClient{})
+	// the call sites wrap the error
+	err = fmt.Errorf("failed to update ELC: %w", err)
+	require.Equal(ServiceErrorOutOfMemory, ServiceErrorCodeOf(err))
+	require.ErrorContains(err, "method=UpdateClient")
+	// the gRPC status is kept
+	require.Equal(codes.Unknown, status.Code(err))
+}
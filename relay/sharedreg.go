@@ -0,0 +1,186 @@
+package relay
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+const DefaultSharedRegistrationTimeout = 10 * 60 // seconds
+
+// ErrSharedRegistrationPending is returned if a new enclave key is needed, but a registration by another instance is pending
+var ErrSharedRegistrationPending = errors.New("the enclave key registration by another instance is pending")
+
+// SharedRegistration is the last enclave key registration recorded in the file shared between the relayer instances.
+// The other instances for the same LCP client wait for the registration instead of registering another key,
+// and take over the key if it is also available in their LCP service.
+type SharedRegistration struct {
+	InstanceID string `json:"instance_id"`
+	// the chain ID of the counterparty chain and the client ID of the LCP client on it
+	ChainID  string `json:"chain_id"`
+	ClientID string `json:"client_id"`
+	// hex string without the 0x prefix
+	EnclaveKey     string                  `json:"enclave_key"`
+	EnclaveKeyInfo *enclave.EnclaveKeyInfo `json:"enclave_key_info"`
+	MsgID          string                  `json:"msg_id"`
+	MsgIDBytes     []byte                  `json:"msg_id_bytes"`
+	RegisteredAt   time.Time               `json:"registered_at"`
+	RelayerVersion string                  `json:"relayer_version"`
+}
+
+// sharedRegistrationPath returns the path of the shared registration file, or an empty string if the sharing is disabled
+func (pr *Prover) sharedRegistrationPath() string {
+	path := pr.config.SharedRegistrationPath
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(pr.homePath, path)
+}
+
+// instanceID returns the identifier of this relayer instance in the shared registration file
+func (pr *Prover) instanceID() string {
+	if pr.config.InstanceId != "" {
+		return pr.config.InstanceId
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return hostname + ":" + pr.homePath
+}
+
+// publishSharedRegistration records the registration of `eki` by the msg `msgID` in the shared registration file.
+// The sharing is best-effort, so an error is logged but not returned.
+func (pr *Prover) publishSharedRegistration(counterparty core.FinalityAwareChain, eki *enclave.EnclaveKeyInfo, msgID core.MsgID) {
+	path := pr.sharedRegistrationPath()
+	if path == "" {
+		return
+	}
+	if err := pr.writeSharedRegistration(path, counterparty, eki, msgID); err != nil {
+		pr.getLogger().Warn("failed to publish the enclave key registration", "path", path, "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "error", err)
+		return
+	}
+	pr.getLogger().Info("published the enclave key registration", "path", path, "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress), "msg_id", msgID.String())
+}
+
+func (pr *Prover) writeSharedRegistration(path string, counterparty core.FinalityAwareChain, eki *enclave.EnclaveKeyInfo, msgID core.MsgID) error {
+	msgIDBytes, err := pr.codec.MarshalInterface(msgID)
+	if err != nil {
+		return fmt.Errorf("failed to marshal msg id: %w", err)
+	}
+	bz, err := json.Marshal(SharedRegistration{
+		InstanceID:     pr.instanceID(),
+		ChainID:        counterparty.ChainID(),
+		ClientID:       counterparty.Path().ClientID,
+		EnclaveKey:     hex.EncodeToString(eki.EnclaveKeyAddress),
+		EnclaveKeyInfo: eki,
+		MsgID:          msgID.String(),
+		MsgIDBytes:     msgIDBytes,
+		RegisteredAt:   time.Now().UTC(),
+		RelayerVersion: GetBuildInfo().String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the shared registration: %w", err)
+	}
+	// replace the file atomically so that the other instances never read a partially written one
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create a temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(bz); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write the shared registration: path=%v %w", f.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close the shared registration: path=%v %w", f.Name(), err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to rename the shared registration: path=%v %w", path, err)
+	}
+	return nil
+}
+
+// loadSharedRegistration returns the registration recorded in the file at `path`, or nil if it does not exist
+func loadSharedRegistration(path string) (*SharedRegistration, error) {
+	bz, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the shared registration: path=%v %w", path, err)
+	}
+	var reg SharedRegistration
+	if err := json.Unmarshal(bz, &reg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the shared registration: path=%v %w", path, err)
+	} else if reg.EnclaveKeyInfo == nil {
+		return nil, fmt.Errorf("the shared registration has no enclave key info: path=%v", path)
+	}
+	return &reg, nil
+}
+
+// adoptSharedRegistration checks the registration by another instance before a new enclave key is registered.
+// If the registration is fresh and its key is also available in the LCP service of this instance,
+// the key becomes the active one as if this instance had registered it, and true is returned.
+// If the registration is fresh but cannot be adopted, ErrSharedRegistrationPending is returned until it finalizes or times out.
+// Any failure to read the shared registration is ignored so that the instance can register a new key by itself.
+func (pr *Prover) adoptSharedRegistration(ctx context.Context, counterparty core.FinalityAwareChain) (bool, error) {
+	path := pr.sharedRegistrationPath()
+	if path == "" {
+		return false, nil
+	}
+	reg, err := loadSharedRegistration(path)
+	if err != nil {
+		pr.getLogger().Warn("failed to load the shared registration", "path", path, "error", err)
+		return false, nil
+	} else if reg == nil || reg.InstanceID == pr.instanceID() || reg.ChainID != counterparty.ChainID() || reg.ClientID != counterparty.Path().ClientID {
+		return false, nil
+	}
+	logger := pr.getLogger().With("instance_id", reg.InstanceID, "enclave_key", reg.EnclaveKey, "msg_id", reg.MsgID, "registered_at", reg.RegisteredAt)
+	if age := time.Since(reg.RegisteredAt); age > pr.config.GetSharedRegistrationTimeout() {
+		logger.Info("the registration by another instance has timed out", "age", age)
+		return false, nil
+	}
+	var msgID core.MsgID
+	if err := pr.codec.UnmarshalInterface(reg.MsgIDBytes, &msgID); err != nil {
+		logger.Warn("failed to unmarshal the msg id of the shared registration", "error", err)
+		return false, nil
+	}
+	tracker, err := pr.newFinalityTracker(counterparty)
+	if err != nil {
+		return false, err
+	}
+	if _, err := tracker.IsIncluded(msgID); err != nil {
+		// the msg may not be included in a block yet
+		logger.Info("the registration by another instance is not included yet", "error", err)
+		return false, fmt.Errorf("%w: instance_id=%v enclave_key=%v msg_id=%v", ErrSharedRegistrationPending, reg.InstanceID, reg.EnclaveKey, reg.MsgID)
+	}
+	finalized, success, _, err := pr.checkTrackedMsgStatus(counterparty, tracker, msgID)
+	if err != nil {
+		return false, err
+	} else if !success {
+		logger.Info("the registration by another instance failed")
+		return false, nil
+	}
+	if pr.checkEKIUpdateNeeded(ctx, time.Now(), reg.EnclaveKeyInfo) {
+		// this instance needs its own key, but it waits for the registration to avoid racing with it
+		if finalized {
+			return false, nil
+		}
+		logger.Info("the key registered by another instance cannot be adopted")
+		return false, fmt.Errorf("%w: instance_id=%v enclave_key=%v msg_id=%v", ErrSharedRegistrationPending, reg.InstanceID, reg.EnclaveKey, reg.MsgID)
+	}
+	logger.Info("adopt the enclave key registered by another instance", "finalized", finalized)
+	if _, err := pr.saveRegisteredEnclaveKey(ctx, counterparty, reg.EnclaveKeyInfo, []core.MsgID{msgID}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
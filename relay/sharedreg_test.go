@@ -0,0 +1,129 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedRegistration(t *testing.T) {
+	msgID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	eki := &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}, AttestationTime: uint64(time.Now().Unix())}
+
+	// newInstance returns a prover of a relayer instance sharing the registration file at `shared`
+	newInstance := func(t *testing.T, shared string, instanceID string) *Prover {
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = t.TempDir()
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.SharedRegistrationPath = shared
+		pr.config.InstanceId = instanceID
+		require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr
+	}
+
+	t.Run("handoff", func(t *testing.T) {
+		require := require.New(t)
+		shared := filepath.Join(t.TempDir(), "registration.json")
+		a, b := newInstance(t, shared, "a"), newInstance(t, shared, "b")
+		cp := newMockCounterparty(clienttypes.NewHeight(0, 9))
+
+		// the instance a has submitted a registration
+		a.publishSharedRegistration(cp, eki, msgID)
+		reg, err := loadSharedRegistration(shared)
+		require.NoError(err)
+		require.Equal("a", reg.InstanceID)
+		require.Equal("counterparty", reg.ChainID)
+		require.Equal("lcp-client-0", reg.ClientID)
+		require.Equal("01", reg.EnclaveKey)
+		require.Equal(msgID.String(), reg.MsgID)
+
+		// the instance b waits for the registration until it is included
+		_, err = b.updateEKIfNeeded(context.TODO(), cp, nil)
+		require.ErrorIs(err, ErrSharedRegistrationPending)
+		require.Nil(b.activeEnclaveKey)
+
+		// the instance b adopts the included registration
+		cp.msgResults[msgID.String()] = mockMsgResult{height: clienttypes.NewHeight(0, 10), success: true}
+		_, err = b.updateEKIfNeeded(context.TODO(), cp, nil)
+		require.NoError(err)
+		require.Equal(eki.EnclaveKeyAddress, b.activeEnclaveKey.EnclaveKeyAddress)
+		require.Equal(msgID.String(), b.unfinalizedMsgID.String())
+		require.Equal(clienttypes.NewHeight(0, 10), b.unfinalizedMsgHeight)
+		_, savedMsgID, _, err := b.loadLastUnfinalizedEnclaveKey(context.TODO())
+		require.NoError(err)
+		require.Equal(msgID.String(), savedMsgID.String())
+
+		// the adopted registration is finalized as if the instance b had registered it
+		cp.finalizedHeight = clienttypes.NewHeight(0, 10)
+		b.counterpartyFinalizedHeaderCache.invalidate()
+		_, err = b.updateEKIfNeeded(context.TODO(), cp, nil)
+		require.NoError(err)
+		require.Nil(b.unfinalizedMsgID)
+		finalized, err := b.loadLastFinalizedEnclaveKey(context.TODO())
+		require.NoError(err)
+		require.Equal(eki.EnclaveKeyAddress, finalized.EnclaveKeyAddress)
+	})
+
+	// the instance b ignores the shared registration and tries to select a new key by itself
+	var cases = []struct {
+		name string
+		// modifies the shared registration written by the instance a
+		modify    func(reg *SharedRegistration)
+		msgResult *mockMsgResult
+	}{
+		{"timed out", func(reg *SharedRegistration) { reg.RegisteredAt = time.Now().Add(-time.Hour) }, nil},
+		{"own registration", func(reg *SharedRegistration) { reg.InstanceID = "b" }, nil},
+		{"another client", func(reg *SharedRegistration) { reg.ClientID = "lcp-client-1" }, nil},
+		{"failed registration", nil, &mockMsgResult{height: clienttypes.NewHeight(0, 10), success: false}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			shared := filepath.Join(t.TempDir(), "registration.json")
+			a, b := newInstance(t, shared, "a"), newInstance(t, shared, "b")
+			cp := newMockCounterparty(clienttypes.NewHeight(0, 9))
+			if c.msgResult != nil {
+				cp.msgResults[msgID.String()] = *c.msgResult
+			}
+
+			a.publishSharedRegistration(cp, eki, msgID)
+			if c.modify != nil {
+				reg, err := loadSharedRegistration(shared)
+				require.NoError(err)
+				c.modify(reg)
+				bz, err := json.Marshal(reg)
+				require.NoError(err)
+				require.NoError(os.WriteFile(shared, bz, 0o644))
+			}
+
+			// no keys are available in the LCP service of the instance b
+			_, err := b.updateEKIfNeeded(context.TODO(), cp, nil)
+			require.ErrorContains(err, "failed to call selectNewEnclaveKey")
+			require.NotErrorIs(err, ErrSharedRegistrationPending)
+		})
+	}
+
+	t.Run("broken file", func(t *testing.T) {
+		require := require.New(t)
+		shared := filepath.Join(t.TempDir(), "registration.json")
+		require.NoError(os.WriteFile(shared, []byte("{"), 0o644))
+		b := newInstance(t, shared, "b")
+		_, err := b.updateEKIfNeeded(context.TODO(), newMockCounterparty(clienttypes.NewHeight(0, 9)), nil)
+		require.ErrorContains(err, "failed to call selectNewEnclaveKey")
+	})
+
+	t.Run("unwritable file", func(t *testing.T) {
+		// the failure of the publication does not affect the instance
+		a := newInstance(t, filepath.Join(t.TempDir(), "missing", "registration.json"), "a")
+		a.publishSharedRegistration(newMockCounterparty(clienttypes.NewHeight(0, 9)), eki, msgID)
+	})
+}
@@ -0,0 +1,320 @@
+package relay
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/signer"
+)
+
+// redactedValue replaces the values which may contain credentials in the output of show-config
+const redactedValue = "<redacted>"
+
+// redactedConfigFields are the fields of ProverConfig which may contain credentials.
+// ShowConfigResult never copies them as they are, and a field of ProverConfig must be either copied by doShowConfig or listed here.
+var redactedConfigFields = []string{
+	// the config of the origin prover may have credentials in its RPC addresses, so only its type is shown
+	"origin_prover",
+	"fallback_origin_provers",
+	// the signer config has the private key or the passphrase, so only its type and address are shown
+	"operator_signer",
+	// the RPC URL of the contract wallet may have an API key, so it is shown as the other URLs
+	"operator_eip1271",
+	// the URLs may have credentials in the userinfo, the path or the query, so only the scheme and the host are shown
+	"ias_crl_url",
+	"alert_webhook_url",
+	// the payload template may embed a token of the webhook
+	"alert_payload_template",
+}
+
+// ShowConfigResult is the effective prover config after applying the defaults.
+// It is built from an explicit allow-list of the fields of ProverConfig so that a new field is not shown until it is added here.
+// The durations are shown in the format of time.Duration.
+type ShowConfigResult struct {
+	OriginProver ShowConfigAny `json:"origin_prover"`
+	// the types of the fallback origin provers in the configured order
+	FallbackOriginProvers           []ShowConfigAny `json:"fallback_origin_provers,omitempty"`
+	LcpServiceAddress               string          `json:"lcp_service_address"`
+	LcpServiceDialTimeout           string          `json:"lcp_service_dial_timeout"`
+	LcpServiceReconnectTimeout      string          `json:"lcp_service_reconnect_timeout"`
+	LcpServiceTlsEnabled            bool            `json:"lcp_service_tls_enabled"`
+	LcpServiceTlsCaCert             string          `json:"lcp_service_tls_ca_cert,omitempty"`
+	LcpServiceClientCert            string          `json:"lcp_service_client_cert,omitempty"`
+	LcpServiceClientKey             string          `json:"lcp_service_client_key,omitempty"`
+	LcpServiceTlsServerNameOverride string          `json:"lcp_service_tls_server_name_override,omitempty"`
+	ProveStateTimeout               string          `json:"prove_state_timeout"`
+	UpdateClientTimeout             string          `json:"update_client_timeout"`
+	// nil if the calls of the class are not limited
+	UpdateClientRateLimit     *RateLimit `json:"update_client_rate_limit,omitempty"`
+	VerifyMembershipRateLimit *RateLimit `json:"verify_membership_rate_limit,omitempty"`
+	QueryRateLimit            *RateLimit `json:"query_rate_limit,omitempty"`
+	// hex string without the 0x prefix
+	Mrenclave                     string                `json:"mrenclave"`
+	AllowedQuoteStatuses          []string              `json:"allowed_quote_statuses"`
+	AllowedAdvisoryIds            []string              `json:"allowed_advisory_ids"`
+	QuotePolicyOverrides          []QuotePolicyOverride `json:"quote_policy_overrides"`
+	KeyExpiration                 string                `json:"key_expiration"`
+	PreferOnChainKeyExpiration    bool                  `json:"prefer_on_chain_key_expiration"`
+	MaxUpdateGap                  string                `json:"max_update_gap"`
+	RefreshThresholdRate          Fraction              `json:"refresh_threshold_rate"`
+	ElcClientId                   string                `json:"elc_client_id"`
+	MessageAggregation            bool                  `json:"message_aggregation"`
+	MessageAggregationBatchSize   uint64                `json:"message_aggregation_batch_size"`
+	MaxConcurrentUpdates          int                   `json:"max_concurrent_updates"`
+	IsDebugEnclave                bool                  `json:"is_debug_enclave"`
+	AllowDebugEnclaveKeys         bool                  `json:"allow_debug_enclave_keys"`
+	MinimumIsvSvn                 uint32                `json:"minimum_isv_svn"`
+	DcapRootCertPaths             []string              `json:"dcap_root_cert_paths"`
+	ElcClientTypeMismatchSeverity string                `json:"elc_client_type_mismatch_severity"`
+	TimestampRegressionSeverity   string                `json:"timestamp_regression_severity"`
+	BundleRegisterEnclaveKey      bool                  `json:"bundle_register_enclave_key"`
+	CounterpartyMessageVersions   []uint16              `json:"counterparty_message_versions"`
+	ValidationContextPreflight    bool                  `json:"validation_context_preflight"`
+	ValidationContextSafetyMargin string                `json:"validation_context_safety_margin"`
+
+	// EIP-55 checksum addresses
+	Operators          []string          `json:"operators"`
+	OperatorsThreshold Fraction          `json:"operators_threshold"`
+	OperatorSigner     *ShowConfigSigner `json:"operator_signer,omitempty"`
+	// nil if the operator signatures are not approved by a contract wallet
+	OperatorEip1271 *ShowConfigEIP1271Operator `json:"operator_eip1271,omitempty"`
+	// nil if the params are not set
+	OperatorsEip712Params *ShowConfigEIP712Params `json:"operators_eip712_params,omitempty"`
+
+	IasCrlUrl             string `json:"ias_crl_url"`
+	IasCrlRefreshInterval string `json:"ias_crl_refresh_interval"`
+	IasCrlFailOpen        bool   `json:"ias_crl_fail_open"`
+
+	ProofArchiveDir        string `json:"proof_archive_dir"`
+	ProofArchiveMaxEntries uint64 `json:"proof_archive_max_entries"`
+	ProofArchiveRetention  string `json:"proof_archive_retention"`
+	ProofArchiveFailClosed bool   `json:"proof_archive_fail_closed"`
+
+	SharedRegistrationPath    string `json:"shared_registration_path"`
+	SharedRegistrationTimeout string `json:"shared_registration_timeout"`
+	InstanceId                string `json:"instance_id"`
+
+	DiagnosticsAddress string `json:"diagnostics_address"`
+
+	MetricsSink           string `json:"metrics_sink"`
+	MetricsAddress        string `json:"metrics_address"`
+	MetricsPushgatewayUrl string `json:"metrics_pushgateway_url"`
+	MetricsPushgatewayJob string `json:"metrics_pushgateway_job"`
+	MetricsPushInterval   string `json:"metrics_push_interval"`
+
+	TxOptions     []TxOptions    `json:"tx_options"`
+	TxRetryPolicy *TxRetryPolicy `json:"tx_retry_policy"`
+
+	FinalityTracker       string `json:"finality_tracker"`
+	FinalityConfirmations uint64 `json:"finality_confirmations"`
+	ConfirmationsRequired uint64 `json:"confirmations_required"`
+	// zero if it is estimated for the "confirmations" tracker
+	ExpectedFinalizationTime         string `json:"expected_finalization_time"`
+	UnfinalizedKeyWatchdogMultiplier uint64 `json:"unfinalized_key_watchdog_multiplier"`
+	FinalizeStuckUnfinalizedKey      bool   `json:"finalize_stuck_unfinalized_key"`
+
+	AlertWebhookUrl      string `json:"alert_webhook_url"`
+	AlertPayloadTemplate string `json:"alert_payload_template"`
+	AlertDedupInterval   string `json:"alert_dedup_interval"`
+	// empty if the margin depends on the trusting period of the origin chain
+	AlertValidationContextMargin string `json:"alert_validation_context_margin"`
+
+	// zero if the attestation policy is not watched
+	AttestationPolicyCheckInterval string `json:"attestation_policy_check_interval"`
+	HaltOnAttestationPolicyDrift   bool   `json:"halt_on_attestation_policy_drift"`
+
+	// zero if the clock skew is not measured
+	ClockSkewCheckInterval string `json:"clock_skew_check_interval"`
+	ClockSkewThreshold     string `json:"clock_skew_threshold"`
+	ClockSkewCorrection    bool   `json:"clock_skew_correction"`
+
+	RetentionPolicies []RetentionPolicy `json:"retention_policies"`
+
+	SubmissionQueue             bool   `json:"submission_queue"`
+	SubmissionQueueMaxMsgsPerTx uint32 `json:"submission_queue_max_msgs_per_tx"`
+	// zero if the garbage is collected only by the gc command
+	GcInterval string `json:"gc_interval"`
+
+	// the time after the attestation when an enclave key is rotated
+	KeyRotationBuffer string `json:"key_rotation_buffer"`
+	// the maximum interval between updates to keep a registered key available
+	RecommendedUpdateInterval string `json:"recommended_update_interval"`
+}
+
+// ShowConfigAny is a packed config whose content is redacted
+type ShowConfigAny struct {
+	TypeURL string `json:"type_url"`
+}
+
+// ShowConfigSigner is the operator signer whose key is redacted
+type ShowConfigSigner struct {
+	TypeURL string `json:"type_url"`
+	Address string `json:"address"`
+}
+
+// ShowConfigEIP1271Operator is the contract wallet approving the operator signatures
+type ShowConfigEIP1271Operator struct {
+	// EIP-55 checksum address
+	WalletAddress   string `json:"wallet_address"`
+	RpcUrl          string `json:"rpc_url"`
+	PollInterval    string `json:"poll_interval"`
+	ApprovalTimeout string `json:"approval_timeout"`
+}
+
+// ShowConfigEIP712Params is the EIP712 params of the operators and the domain params resolved from them
+type ShowConfigEIP712Params struct {
+	ChainType         string                   `json:"chain_type"`
+	EVMChainParams    *EIP712EVMChainParams    `json:"evm_chain_params,omitempty"`
+	CosmosChainParams *EIP712CosmosChainParams `json:"cosmos_chain_params,omitempty"`
+	DomainChainID     uint64                   `json:"domain_chain_id"`
+	// EIP-55 checksum address
+	DomainVerifyingContract string `json:"domain_verifying_contract"`
+	DomainSalt              string `json:"domain_salt"`
+}
+
+// doShowConfig validates the prover config and returns the effective one with the credentials redacted
+func (pr *Prover) doShowConfig() (*ShowConfigResult, error) {
+	c := pr.config
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid prover config: %w", err)
+	}
+	res := &ShowConfigResult{
+		LcpServiceAddress:                c.LcpServiceAddress,
+		LcpServiceDialTimeout:            c.GetDialTimeout().String(),
+		LcpServiceReconnectTimeout:       c.GetReconnectTimeout().String(),
+		LcpServiceTlsEnabled:             c.LcpServiceTlsEnabled,
+		LcpServiceTlsCaCert:              c.LcpServiceTlsCaCert,
+		LcpServiceClientCert:             c.LcpServiceClientCert,
+		LcpServiceClientKey:              c.LcpServiceClientKey,
+		LcpServiceTlsServerNameOverride:  c.LcpServiceTlsServerNameOverride,
+		ProveStateTimeout:                c.GetProveStateTimeout().String(),
+		UpdateClientTimeout:              c.GetUpdateClientTimeout().String(),
+		UpdateClientRateLimit:            c.UpdateClientRateLimit,
+		VerifyMembershipRateLimit:        c.VerifyMembershipRateLimit,
+		QueryRateLimit:                   c.QueryRateLimit,
+		Mrenclave:                        fmt.Sprintf("%x", c.GetMrenclave()),
+		AllowedQuoteStatuses:             c.AllowedQuoteStatuses,
+		AllowedAdvisoryIds:               c.AllowedAdvisoryIds,
+		QuotePolicyOverrides:             c.QuotePolicyOverrides,
+		KeyExpiration:                    pr.keyExpiration().String(),
+		PreferOnChainKeyExpiration:       c.PreferOnChainKeyExpiration,
+		MaxUpdateGap:                     (time.Duration(c.MaxUpdateGap) * time.Second).String(),
+		RefreshThresholdRate:             c.GetRefreshThresholdRate(),
+		ElcClientId:                      c.ElcClientId,
+		MessageAggregation:               c.MessageAggregation,
+		MessageAggregationBatchSize:      c.GetMessageAggregationBatchSize(),
+		MaxConcurrentUpdates:             c.GetMaxConcurrentUpdates(),
+		IsDebugEnclave:                   c.IsDebugEnclave,
+		AllowDebugEnclaveKeys:            c.AllowDebugEnclaveKeys,
+		MinimumIsvSvn:                    c.MinimumIsvSvn,
+		DcapRootCertPaths:                c.DcapRootCertPaths,
+		ElcClientTypeMismatchSeverity:    c.GetELCClientTypeMismatchSeverity(),
+		TimestampRegressionSeverity:      c.GetTimestampRegressionSeverity(),
+		BundleRegisterEnclaveKey:         c.BundleRegisterEnclaveKey,
+		CounterpartyMessageVersions:      c.GetCounterpartyMessageVersions(),
+		ValidationContextPreflight:       c.ValidationContextPreflight,
+		ValidationContextSafetyMargin:    c.GetValidationContextSafetyMargin().String(),
+		OperatorsThreshold:               pr.GetOperatorsThreshold(),
+		IasCrlUrl:                        redactURL(c.IasCrlUrl),
+		IasCrlRefreshInterval:            c.GetIASCRLRefreshInterval().String(),
+		IasCrlFailOpen:                   c.IasCrlFailOpen,
+		ProofArchiveDir:                  c.ProofArchiveDir,
+		ProofArchiveMaxEntries:           c.ProofArchiveMaxEntries,
+		ProofArchiveRetention:            (time.Duration(c.ProofArchiveRetention) * time.Second).String(),
+		ProofArchiveFailClosed:           c.ProofArchiveFailClosed,
+		SharedRegistrationPath:           c.SharedRegistrationPath,
+		SharedRegistrationTimeout:        c.GetSharedRegistrationTimeout().String(),
+		InstanceId:                       pr.instanceID(),
+		DiagnosticsAddress:               c.DiagnosticsAddress,
+		MetricsSink:                      c.GetMetricsSink(),
+		MetricsAddress:                   c.MetricsAddress,
+		MetricsPushgatewayUrl:            redactURL(c.MetricsPushgatewayUrl),
+		MetricsPushgatewayJob:            c.GetMetricsPushgatewayJob(),
+		MetricsPushInterval:              c.GetMetricsPushInterval().String(),
+		TxOptions:                        c.TxOptions,
+		TxRetryPolicy:                    c.TxRetryPolicy,
+		FinalityTracker:                  c.GetFinalityTracker(),
+		FinalityConfirmations:            c.FinalityConfirmations,
+		ConfirmationsRequired:            c.ConfirmationsRequired,
+		ExpectedFinalizationTime:         (time.Duration(c.ExpectedFinalizationTime) * time.Second).String(),
+		UnfinalizedKeyWatchdogMultiplier: c.GetUnfinalizedKeyWatchdogMultiplier(),
+		FinalizeStuckUnfinalizedKey:      c.FinalizeStuckUnfinalizedKey,
+		AlertWebhookUrl:                  redactURL(c.AlertWebhookUrl),
+		AlertDedupInterval:               c.GetAlertDedupInterval().String(),
+		AttestationPolicyCheckInterval:   (time.Duration(c.AttestationPolicyCheckInterval) * time.Second).String(),
+		HaltOnAttestationPolicyDrift:     c.HaltOnAttestationPolicyDrift,
+		ClockSkewCheckInterval:           (time.Duration(c.ClockSkewCheckInterval) * time.Second).String(),
+		ClockSkewThreshold:               c.GetClockSkewThreshold().String(),
+		ClockSkewCorrection:              c.ClockSkewCorrection,
+		RetentionPolicies:                c.RetentionPolicies,
+		SubmissionQueue:                  c.SubmissionQueue,
+		SubmissionQueueMaxMsgsPerTx:      c.SubmissionQueueMaxMsgsPerTx,
+		GcInterval:                       (time.Duration(c.GcInterval) * time.Second).String(),
+		KeyRotationBuffer:                (pr.keyExpiration() / 2).String(),
+		RecommendedUpdateInterval:        pr.RecommendedUpdateInterval().String(),
+	}
+	if c.OriginProver != nil {
+		res.OriginProver.TypeURL = c.OriginProver.TypeUrl
+	}
+	for _, fallback := range c.FallbackOriginProvers {
+		res.FallbackOriginProvers = append(res.FallbackOriginProvers, ShowConfigAny{TypeURL: fallback.TypeUrl})
+	}
+	if c.AlertPayloadTemplate != "" {
+		res.AlertPayloadTemplate = redactedValue
+	}
+	if c.AlertValidationContextMargin != 0 {
+		res.AlertValidationContextMargin = c.GetAlertValidationContextMargin(0).String()
+	}
+	ops, err := pr.GetOperators()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		res.Operators = append(res.Operators, op.Hex())
+	}
+	if c.OperatorSigner != nil {
+		addr, err := getSignerConfigAddress(c.OperatorSigner.GetCachedValue().(signer.SignerConfig))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the OperatorSigner's address: %w", err)
+		}
+		res.OperatorSigner = &ShowConfigSigner{TypeURL: c.OperatorSigner.TypeUrl, Address: addr.Hex()}
+	}
+	if w := c.OperatorEip1271; w != nil {
+		res.OperatorEip1271 = &ShowConfigEIP1271Operator{
+			WalletAddress:   common.HexToAddress(w.WalletAddress).Hex(),
+			RpcUrl:          redactURL(w.RpcUrl),
+			PollInterval:    w.GetPollInterval().String(),
+			ApprovalTimeout: w.GetApprovalTimeout().String(),
+		}
+	}
+	if c.OperatorsEip712Params != nil {
+		domain := pr.getDomainParams()
+		res.OperatorsEip712Params = &ShowConfigEIP712Params{
+			ChainType:               c.ChainType().String(),
+			EVMChainParams:          c.GetOperatorsEip712EvmChainParams(),
+			CosmosChainParams:       c.GetOperatorsEip712CosmosChainParams(),
+			DomainChainID:           domain.ChainId,
+			DomainVerifyingContract: domain.VerifyingContractAddr.Hex(),
+			DomainSalt:              pr.computeEIP712ChainSalt().Hex(),
+		}
+	}
+	return res, nil
+}
+
+// redactURL returns the scheme and the host of the URL, and the other parts are redacted
+func redactURL(s string) string {
+	if s == "" {
+		return ""
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return redactedValue
+	}
+	redacted := u.Scheme + "://" + u.Host
+	if u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		redacted += "/" + redactedValue
+	}
+	return redacted
+}
@@ -0,0 +1,134 @@
+package relay
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/datachainlab/lcp-go/relay/signers/raw"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoShowConfigRedaction(t *testing.T) {
+	require := require.New(t)
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	privateKey := hex.EncodeToString(crypto.FromECDSA(key))
+	operator := crypto.PubkeyToAddress(key.PublicKey)
+
+	// the secrets which must not appear in the output
+	const (
+		originProverSecret = "1234h"
+		webhookToken       = "T000-webhook-token"
+		crlCredential      = "crl-password"
+		crlQuerySecret     = "crl-api-key"
+		templateSecret     = "template-bearer-token"
+		webhookUserinfo    = "webhook-user"
+		expectedWebhook    = "https://hooks.example.com/" + redactedValue
+		expectedCRL        = "https://crl.example.com/" + redactedValue
+		expectedTemplate   = redactedValue
+	)
+	originProver, err := codectypes.NewAnyWithValue(&tendermint.ProverConfig{
+		TrustingPeriod:       originProverSecret,
+		RefreshThresholdRate: &tendermint.Fraction{Numerator: 1, Denominator: 2},
+	})
+	require.NoError(err)
+	operatorSigner, err := codectypes.NewAnyWithValue(&raw.SignerConfig{PrivateKey: privateKey})
+	require.NoError(err)
+
+	pr := newTestProver(t)
+	pr.config = ProverConfig{
+		OriginProver:         originProver,
+		LcpServiceAddress:    "localhost:50051",
+		Mrenclave:            "0x" + strings.ToUpper(strings.Repeat("ab", 32)),
+		AllowedQuoteStatuses: []string{"GROUP_OUT_OF_DATE"},
+		AllowedAdvisoryIds:   []string{"INTEL-SA-00219"},
+		KeyExpiration:        3600,
+		ElcClientId:          "07-tendermint-0",
+		Operators:            []string{strings.ToLower(operator.Hex())},
+		OperatorSigner:       operatorSigner,
+		OperatorsEip712Params: &ProverConfig_OperatorsEip712CosmosChainParams{
+			OperatorsEip712CosmosChainParams: &EIP712CosmosChainParams{ChainId: "ibc0", Prefix: "ibc"},
+		},
+		IasCrlUrl:            "https://user:" + crlCredential + "@crl.example.com/crl?key=" + crlQuerySecret,
+		AlertWebhookUrl:      "https://" + webhookUserinfo + "@hooks.example.com/services/" + webhookToken,
+		AlertPayloadTemplate: `{"token": "` + templateSecret + `", "text": {{ printf "%q" .Message }}}`,
+	}
+
+	res, err := pr.doShowConfig()
+	require.NoError(err)
+	bz, err := json.Marshal(res)
+	require.NoError(err)
+	out := string(bz)
+	for _, secret := range []string{privateKey, originProverSecret, webhookToken, webhookUserinfo, crlCredential, crlQuerySecret, templateSecret} {
+		require.NotContains(out, secret)
+	}
+	require.Equal(expectedWebhook, res.AlertWebhookUrl)
+	require.Equal(expectedCRL, res.IasCrlUrl)
+	require.Equal(expectedTemplate, res.AlertPayloadTemplate)
+	require.Equal(originProver.TypeUrl, res.OriginProver.TypeURL)
+	require.Equal(&ShowConfigSigner{TypeURL: operatorSigner.TypeUrl, Address: operator.Hex()}, res.OperatorSigner)
+
+	// the effective values
+	require.Equal(strings.Repeat("ab", 32), res.Mrenclave)
+	require.Equal([]string{operator.Hex()}, res.Operators)
+	require.Equal(Fraction{Numerator: 1, Denominator: 1}, res.OperatorsThreshold)
+	require.Equal("20s", res.LcpServiceDialTimeout)
+	require.Equal("1h0m0s", res.KeyExpiration)
+	require.Equal("30m0s", res.KeyRotationBuffer)
+	require.Equal(SeverityError, res.ElcClientTypeMismatchSeverity)
+	require.Equal(SeverityError, res.TimestampRegressionSeverity)
+	require.Equal(uint64(DefaultMessageAggregationBatchSize), res.MessageAggregationBatchSize)
+	require.NotNil(res.OperatorsEip712Params)
+	require.Equal(pr.computeEIP712CosmosChainSalt().Hex(), res.OperatorsEip712Params.DomainSalt)
+
+	// the output is stable
+	bz2, err := json.Marshal(res)
+	require.NoError(err)
+	require.Equal(out, string(bz2))
+}
+
+func TestDoShowConfigInvalid(t *testing.T) {
+	pr := newTestProver(t)
+	pr.config.LcpServiceAddress = "localhost"
+	pr.config.OriginProver, _ = codectypes.NewAnyWithValue(&tendermint.ProverConfig{
+		TrustingPeriod:       "336h",
+		RefreshThresholdRate: &tendermint.Fraction{Numerator: 1, Denominator: 2},
+	})
+	_, err := pr.doShowConfig()
+	require.ErrorContains(t, err, "invalid prover config")
+}
+
+// TestShowConfigCoversAllFields ensures that every field of ProverConfig is either shown or redacted explicitly,
+// so that a new field needs a decision on whether it may contain credentials
+func TestShowConfigCoversAllFields(t *testing.T) {
+	shown := make(map[string]bool)
+	rt := reflect.TypeOf(ShowConfigResult{})
+	for i := 0; i < rt.NumField(); i++ {
+		shown[strings.Split(rt.Field(i).Tag.Get("json"), ",")[0]] = true
+	}
+	redacted := make(map[string]bool)
+	for _, name := range redactedConfigFields {
+		redacted[name] = true
+	}
+	ct := reflect.TypeOf(ProverConfig{})
+	for i := 0; i < ct.NumField(); i++ {
+		f := ct.Field(i)
+		name := f.Tag.Get("protobuf_oneof")
+		for _, opt := range strings.Split(f.Tag.Get("protobuf"), ",") {
+			if strings.HasPrefix(opt, "name=") {
+				name = strings.TrimPrefix(opt, "name=")
+			}
+		}
+		require.NotEmpty(t, name, "field %v has no protobuf name", f.Name)
+		require.True(t, shown[name] || redacted[name], "field %v is neither shown nor redacted by show-config", name)
+		if redacted[name] {
+			require.True(t, shown[name], "redacted field %v must be shown with the credentials removed", name)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package relay
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+)
+
+// ErrInvalidEnclaveSignature is returned if a message returned by the LCP service is not signed by the expected enclave key
+var ErrInvalidEnclaveSignature = errors.New("invalid enclave signature")
+
+// verifyEnclaveSignature verifies that `signature` is a signature over `message` by the enclave key `signer`.
+// The counterparty chain performs the same verification, so this detects a corrupted response before submitting it.
+func verifyEnclaveSignature(message []byte, signature []byte, signer []byte) error {
+	addr, err := lcptypes.VerifySignature(message, signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEnclaveSignature, err)
+	}
+	if expected := common.BytesToAddress(signer); addr != expected {
+		return fmt.Errorf("%w: expected=%v actual=%v", ErrInvalidEnclaveSignature, expected.Hex(), addr.Hex())
+	}
+	return nil
+}
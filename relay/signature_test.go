@@ -0,0 +1,80 @@
+package relay
+
+import (
+	"context"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type mockOriginProver struct {
+	core.Prover
+}
+
+func (mockOriginProver) ProveState(ctx core.QueryContext, path string, value []byte) ([]byte, clienttypes.Height, error) {
+	return []byte("proof"), clienttypes.NewHeight(0, 1), nil
+}
+
+type mockELCMsgClient struct {
+	elc.MsgClient
+	message   []byte
+	signature []byte
+}
+
+func (c mockELCMsgClient) VerifyMembership(ctx context.Context, in *elc.MsgVerifyMembership, opts ...grpc.CallOption) (*elc.MsgVerifyMembershipResponse, error) {
+	return &elc.MsgVerifyMembershipResponse{Message: c.message, Signature: c.signature}, nil
+}
+
+func (c mockELCMsgClient) VerifyNonMembership(ctx context.Context, in *elc.MsgVerifyNonMembership, opts ...grpc.CallOption) (*elc.MsgVerifyNonMembershipResponse, error) {
+	return &elc.MsgVerifyNonMembershipResponse{Message: c.message, Signature: c.signature}, nil
+}
+
+func TestVerifyEnclaveSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey).Bytes()
+	message := []byte("message")
+	signature, err := crypto.Sign(crypto.Keccak256(message), key)
+	require.NoError(t, err)
+
+	tamperedSignature := append([]byte{}, signature...)
+	tamperedSignature[0] ^= 0xff
+
+	var cases = []struct {
+		name      string
+		message   []byte
+		signature []byte
+		signer    []byte
+		valid     bool
+	}{
+		{"valid", message, signature, signer, true},
+		{"tampered message", []byte("massage"), signature, signer, false},
+		{"tampered signature", message, tamperedSignature, signer, false},
+		{"truncated signature", message, signature[:64], signer, false},
+		{"unexpected signer", message, signature, make([]byte, 20), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := verifyEnclaveSignature(c.message, c.signature, c.signer)
+			if c.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrInvalidEnclaveSignature)
+			}
+		})
+	}
+
+	// the tampered response is rejected before it is decoded
+	pr := newTestProver(t)
+	pr.originProver = mockOriginProver{}
+	pr.activeEnclaveKey = &enclave.EnclaveKeyInfo{EnclaveKeyAddress: signer}
+	pr.lcpServiceClient.ELCMsgClient = mockELCMsgClient{message: []byte("massage"), signature: signature}
+	_, _, err = pr.ProveState(core.NewQueryContext(context.TODO(), clienttypes.NewHeight(0, 1)), "path", []byte("value"))
+	require.ErrorIs(t, err, ErrInvalidEnclaveSignature)
+}
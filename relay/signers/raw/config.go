@@ -1,31 +1,178 @@
 package raw
 
 import (
+	"crypto/ecdsa"
 	"encoding/hex"
+	"encoding/json"
 	fmt "fmt"
+	"os"
 	"strings"
 
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/hyperledger-labs/yui-relayer/signer"
+	"golang.org/x/term"
 )
 
 var _ signer.SignerConfig = (*SignerConfig)(nil)
 
+// redacted replaces the private key in the string representation of the config
+const redacted = "<redacted>"
+
+// String returns the text representation of the config without the plaintext private key
+// so that the key does not appear in the logs
+func (c *SignerConfig) String() string {
+	if c == nil {
+		return "nil"
+	}
+	cp := *c
+	if cp.PrivateKey != "" {
+		cp.PrivateKey = redacted
+	}
+	return proto.CompactTextString((*plainSignerConfig)(&cp))
+}
+
+// plainSignerConfig has the same fields as SignerConfig and is used to render its text representation
+type plainSignerConfig SignerConfig
+
+func (c *plainSignerConfig) Reset()         { *c = plainSignerConfig{} }
+func (c *plainSignerConfig) ProtoMessage()  {}
+func (c *plainSignerConfig) String() string { return proto.CompactTextString(c) }
+
 func (c *SignerConfig) Validate() error {
-	if _, err := hex.DecodeString(strings.TrimPrefix(c.PrivateKey, "0x")); err != nil {
-		return fmt.Errorf("invalid private key: %w", err)
+	var sources int
+	for _, s := range []string{c.PrivateKey, c.KeystoreFile, c.PrivateKeyEnv} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("exactly one of private_key, keystore_file and private_key_env must be set: actual=%v", sources)
+	}
+	if c.KeystorePassphraseEnv != "" && c.KeystoreFile == "" {
+		return fmt.Errorf("keystore_passphrase_env is set without keystore_file")
+	}
+	switch {
+	case c.PrivateKey != "":
+		if _, err := hex.DecodeString(strings.TrimPrefix(c.PrivateKey, "0x")); err != nil {
+			return fmt.Errorf("invalid private key: %w", err)
+		}
+	case c.KeystoreFile != "":
+		if _, err := c.keystoreAddress(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 func (c *SignerConfig) Build() (signer.Signer, error) {
-	bz, err := hex.DecodeString(strings.TrimPrefix(c.PrivateKey, "0x"))
+	privKey, err := c.loadPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(privKey), nil
+}
+
+// GetSignerAddress returns the address of the configured key.
+// The keystore file is not decrypted because it records the address in plaintext.
+func (c *SignerConfig) GetSignerAddress() (common.Address, error) {
+	if c.KeystoreFile != "" {
+		return c.keystoreAddress()
+	}
+	privKey, err := c.loadPrivateKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(privKey.PublicKey), nil
+}
+
+// loadPrivateKey loads the private key from the configured source
+func (c *SignerConfig) loadPrivateKey() (*ecdsa.PrivateKey, error) {
+	switch {
+	case c.KeystoreFile != "":
+		return c.decryptKeystore()
+	case c.PrivateKeyEnv != "":
+		hexKey, ok := os.LookupEnv(c.PrivateKeyEnv)
+		if !ok {
+			return nil, fmt.Errorf("the environment variable of the private key is not set: name=%v", c.PrivateKeyEnv)
+		}
+		return decodePrivateKey(hexKey)
+	default:
+		return decodePrivateKey(c.PrivateKey)
+	}
+}
+
+// keystoreAddress returns the address recorded in the keystore file
+func (c *SignerConfig) keystoreAddress() (common.Address, error) {
+	bz, err := os.ReadFile(c.KeystoreFile)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to read the keystore file: path=%v %w", c.KeystoreFile, err)
+	}
+	var ks struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(bz, &ks); err != nil {
+		return common.Address{}, fmt.Errorf("failed to unmarshal the keystore file: path=%v %w", c.KeystoreFile, err)
+	} else if !common.IsHexAddress(ks.Address) {
+		return common.Address{}, fmt.Errorf("the keystore file has no valid address: path=%v address=%v", c.KeystoreFile, ks.Address)
+	}
+	return common.HexToAddress(ks.Address), nil
+}
+
+// decryptKeystore decrypts the keystore file with the passphrase from the environment variable or the terminal
+func (c *SignerConfig) decryptKeystore() (*ecdsa.PrivateKey, error) {
+	address, err := c.keystoreAddress()
+	if err != nil {
+		return nil, err
+	}
+	bz, err := os.ReadFile(c.KeystoreFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the keystore file: path=%v %w", c.KeystoreFile, err)
+	}
+	passphrase, err := c.keystorePassphrase()
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(bz, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the keystore file: path=%v %w", c.KeystoreFile, err)
+	} else if key.Address != address {
+		return nil, fmt.Errorf("the decrypted key does not match the address of the keystore file: path=%v expected=%v actual=%v", c.KeystoreFile, address, key.Address)
+	}
+	return key.PrivateKey, nil
+}
+
+func (c *SignerConfig) keystorePassphrase() (string, error) {
+	if c.KeystorePassphraseEnv != "" {
+		if passphrase, ok := os.LookupEnv(c.KeystorePassphraseEnv); ok {
+			return passphrase, nil
+		}
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("the passphrase of the keystore file is neither set in the environment variable nor prompted: path=%v env=%v", c.KeystoreFile, c.KeystorePassphraseEnv)
+	}
+	fmt.Fprintf(os.Stderr, "Enter the passphrase of the keystore file %v: ", c.KeystoreFile)
+	bz, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the passphrase: %w", err)
+	}
+	return string(bz), nil
+}
+
+func decodePrivateKey(hexKey string) (*ecdsa.PrivateKey, error) {
+	bz, err := hex.DecodeString(strings.TrimPrefix(hexKey, "0x"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode private key: %w", err)
 	}
+	// the decoded bytes are copied into the key and are not needed any more
+	defer clear(bz)
 	privKey, err := crypto.ToECDSA(bz)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode to ECDSA: %w", err)
 	}
-	return NewSigner(privKey), nil
+	return privKey, nil
 }
@@ -23,13 +23,22 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// SignerConfig selects the source of the private key. Exactly one of `private_key`, `keystore_file` and `private_key_env` must be set.
 type SignerConfig struct {
+	// hex string of the private key in plaintext
+	// prefer `keystore_file` or `private_key_env` to keep the key out of the config file
 	PrivateKey string `protobuf:"bytes,1,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	// path to an encrypted keystore file of go-ethereum
+	KeystoreFile string `protobuf:"bytes,2,opt,name=keystore_file,json=keystoreFile,proto3" json:"keystore_file,omitempty"`
+	// the name of the environment variable holding the passphrase of the keystore file
+	// if empty or the variable is not set, the passphrase is prompted on the terminal
+	KeystorePassphraseEnv string `protobuf:"bytes,3,opt,name=keystore_passphrase_env,json=keystorePassphraseEnv,proto3" json:"keystore_passphrase_env,omitempty"`
+	// the name of the environment variable holding the hex string of the private key
+	PrivateKeyEnv string `protobuf:"bytes,4,opt,name=private_key_env,json=privateKeyEnv,proto3" json:"private_key_env,omitempty"`
 }
 
-func (m *SignerConfig) Reset()         { *m = SignerConfig{} }
-func (m *SignerConfig) String() string { return proto.CompactTextString(m) }
-func (*SignerConfig) ProtoMessage()    {}
+func (m *SignerConfig) Reset()      { *m = SignerConfig{} }
+func (*SignerConfig) ProtoMessage() {}
 func (*SignerConfig) Descriptor() ([]byte, []int) {
 	return fileDescriptor_92339b430a08f7d7, []int{0}
 }
@@ -69,21 +78,26 @@ func init() {
 }
 
 var fileDescriptor_92339b430a08f7d7 = []byte{
-	// 209 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x29, 0x4a, 0xcd, 0x49,
-	0xac, 0x4c, 0x2d, 0xd2, 0x2f, 0x28, 0xca, 0x2f, 0x4b, 0x2d, 0x2a, 0xd6, 0xcf, 0x49, 0x2e, 0xd0,
-	0x2f, 0xce, 0x4c, 0xcf, 0x03, 0xb1, 0x8b, 0x12, 0xcb, 0xf5, 0x93, 0xf3, 0xf3, 0xd2, 0x32, 0xd3,
-	0xf5, 0x0a, 0x8a, 0xf2, 0x4b, 0xf2, 0x85, 0xe4, 0xa1, 0xaa, 0xf5, 0xa0, 0xaa, 0xf5, 0x72, 0x92,
-	0x0b, 0xf4, 0xa0, 0xaa, 0xf5, 0x8a, 0x12, 0xcb, 0xa5, 0x44, 0xd2, 0xf3, 0xd3, 0xf3, 0xc1, 0x6a,
-	0xf5, 0x41, 0x2c, 0x88, 0x36, 0x25, 0x7d, 0x2e, 0x9e, 0x60, 0xb0, 0x22, 0x67, 0xb0, 0x61, 0x42,
-	0xf2, 0x5c, 0xdc, 0x05, 0x45, 0x99, 0x65, 0x89, 0x25, 0xa9, 0xf1, 0xd9, 0xa9, 0x95, 0x12, 0x8c,
-	0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x5c, 0x50, 0x21, 0xef, 0xd4, 0x4a, 0x27, 0xbf, 0x13, 0x0f, 0xe5,
-	0x18, 0x4e, 0x3c, 0x92, 0x63, 0xbc, 0xf0, 0x48, 0x8e, 0xf1, 0xc1, 0x23, 0x39, 0xc6, 0x09, 0x8f,
-	0xe5, 0x18, 0x2e, 0x3c, 0x96, 0x63, 0xb8, 0xf1, 0x58, 0x8e, 0x21, 0xca, 0x20, 0x3d, 0xb3, 0x24,
-	0xa3, 0x34, 0x49, 0x2f, 0x39, 0x3f, 0x57, 0x3f, 0x25, 0xb1, 0x24, 0x31, 0x39, 0x23, 0x31, 0x33,
-	0x2f, 0x27, 0x31, 0x09, 0xe4, 0x76, 0xdd, 0xf4, 0x7c, 0x7d, 0xb0, 0x23, 0x91, 0x3d, 0x91, 0xc4,
-	0x06, 0x76, 0x87, 0x31, 0x20, 0x00, 0x00, 0xff, 0xff, 0x44, 0xa2, 0x5e, 0xec, 0xee, 0x00, 0x00,
-	0x00,
+	// 291 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0xd0, 0x3f, 0x4b, 0x3b, 0x31,
+	0x18, 0x07, 0xf0, 0xcb, 0xef, 0x57, 0x04, 0x63, 0x8b, 0x70, 0x28, 0x16, 0x87, 0x54, 0x14, 0xc4,
+	0x41, 0x13, 0x41, 0x70, 0x70, 0x54, 0xea, 0x22, 0x88, 0xe8, 0xe6, 0x52, 0x72, 0xe7, 0xd3, 0x5c,
+	0x68, 0x4c, 0x42, 0x72, 0x5e, 0xb9, 0x77, 0xe1, 0xe8, 0xe8, 0xeb, 0xf0, 0x15, 0x74, 0xec, 0xe8,
+	0xa8, 0x77, 0x6f, 0x44, 0x2e, 0x5e, 0x6b, 0xb7, 0x87, 0xe7, 0xfb, 0xc9, 0x1f, 0xbe, 0xf8, 0xd8,
+	0x81, 0xe2, 0x25, 0x38, 0x66, 0x9d, 0x29, 0xc0, 0x79, 0xa6, 0x52, 0xcb, 0xbc, 0x14, 0xba, 0x99,
+	0x1d, 0x9f, 0xb2, 0xd4, 0xe8, 0xb1, 0x14, 0xd4, 0x3a, 0x93, 0x9b, 0x78, 0xd0, 0x6a, 0xda, 0x6a,
+	0xaa, 0x52, 0x4b, 0x5b, 0x4d, 0x1d, 0x9f, 0xee, 0x6e, 0x09, 0x23, 0x4c, 0xb0, 0xac, 0x99, 0x7e,
+	0x8f, 0xed, 0x7f, 0x20, 0xdc, 0x7d, 0x08, 0xea, 0x2a, 0xdc, 0x16, 0x0f, 0xf0, 0x86, 0x75, 0xb2,
+	0xe0, 0x39, 0x8c, 0x26, 0x50, 0xf6, 0xd1, 0x1e, 0x3a, 0x5a, 0xbf, 0xc7, 0xed, 0xea, 0x06, 0xca,
+	0xf8, 0x00, 0xf7, 0x26, 0x50, 0xfa, 0xdc, 0x38, 0x18, 0x8d, 0xa5, 0x82, 0xfe, 0xbf, 0x40, 0xba,
+	0x8b, 0xe5, 0xb5, 0x54, 0x10, 0x9f, 0xe3, 0x9d, 0x25, 0xb2, 0xdc, 0x7b, 0x9b, 0x39, 0xee, 0x61,
+	0x04, 0xba, 0xe8, 0xff, 0x0f, 0x7c, 0x7b, 0x11, 0xdf, 0x2d, 0xd3, 0xa1, 0x2e, 0xe2, 0x43, 0xbc,
+	0xb9, 0xf2, 0x7a, 0xf0, 0x9d, 0xe0, 0x7b, 0x7f, 0x3f, 0x18, 0xea, 0xe2, 0xa2, 0xf3, 0xf6, 0x3e,
+	0x88, 0x2e, 0x6f, 0x67, 0xdf, 0x24, 0x9a, 0x55, 0x04, 0xcd, 0x2b, 0x82, 0xbe, 0x2a, 0x82, 0x5e,
+	0x6b, 0x12, 0xcd, 0x6b, 0x12, 0x7d, 0xd6, 0x24, 0x7a, 0x3c, 0x15, 0x32, 0xcf, 0x5e, 0x12, 0x9a,
+	0x9a, 0x67, 0xf6, 0xc4, 0x73, 0x9e, 0x66, 0x5c, 0x6a, 0xc5, 0x93, 0xa6, 0xc7, 0x13, 0x61, 0x58,
+	0x28, 0x6c, 0xb5, 0xd0, 0x64, 0x2d, 0x74, 0x72, 0xf6, 0x33, 0x00, 0x80, 0x12, 0xe0, 0x9b, 0x7a,
+	0x01, 0x00, 0x00,
 }
 
 func (m *SignerConfig) Marshal() (dAtA []byte, err error) {
@@ -106,6 +120,27 @@ func (m *SignerConfig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.PrivateKeyEnv) > 0 {
+		i -= len(m.PrivateKeyEnv)
+		copy(dAtA[i:], m.PrivateKeyEnv)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.PrivateKeyEnv)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.KeystorePassphraseEnv) > 0 {
+		i -= len(m.KeystorePassphraseEnv)
+		copy(dAtA[i:], m.KeystorePassphraseEnv)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.KeystorePassphraseEnv)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.KeystoreFile) > 0 {
+		i -= len(m.KeystoreFile)
+		copy(dAtA[i:], m.KeystoreFile)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.KeystoreFile)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if len(m.PrivateKey) > 0 {
 		i -= len(m.PrivateKey)
 		copy(dAtA[i:], m.PrivateKey)
@@ -137,6 +172,18 @@ func (m *SignerConfig) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovConfig(uint64(l))
 	}
+	l = len(m.KeystoreFile)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.KeystorePassphraseEnv)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.PrivateKeyEnv)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
 	return n
 }
 
@@ -207,6 +254,102 @@ func (m *SignerConfig) Unmarshal(dAtA []byte) error {
 			}
 			m.PrivateKey = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeystoreFile", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KeystoreFile = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeystorePassphraseEnv", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KeystorePassphraseEnv = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PrivateKeyEnv", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PrivateKeyEnv = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
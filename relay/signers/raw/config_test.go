@@ -0,0 +1,137 @@
+package raw
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestKeystore writes the key encrypted with `passphrase` into a keystore file
+func writeTestKeystore(t *testing.T, privKey *ecdsa.PrivateKey, passphrase string) string {
+	key := &keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(privKey.PublicKey),
+		PrivateKey: privKey,
+	}
+	bz, err := keystore.EncryptKey(key, passphrase, keystore.LightScryptN, keystore.LightScryptP)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	require.NoError(t, os.WriteFile(path, bz, 0600))
+	return path
+}
+
+func requireSignerAddress(t *testing.T, config *SignerConfig, privKey *ecdsa.PrivateKey) {
+	s, err := config.Build()
+	require.NoError(t, err)
+	pub, err := s.GetPublicKey()
+	require.NoError(t, err)
+	require.Equal(t, crypto.CompressPubkey(&privKey.PublicKey), pub)
+	addr, err := config.GetSignerAddress()
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(privKey.PublicKey), addr)
+}
+
+func TestSignerConfigKeystore(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	path := writeTestKeystore(t, privKey, "passphrase")
+
+	t.Run("decryption", func(t *testing.T) {
+		t.Setenv("TEST_KEYSTORE_PASSPHRASE", "passphrase")
+		config := &SignerConfig{KeystoreFile: path, KeystorePassphraseEnv: "TEST_KEYSTORE_PASSPHRASE"}
+		require.NoError(t, config.Validate())
+		requireSignerAddress(t, config, privKey)
+	})
+	t.Run("wrong passphrase", func(t *testing.T) {
+		t.Setenv("TEST_KEYSTORE_PASSPHRASE", "wrong")
+		config := &SignerConfig{KeystoreFile: path, KeystorePassphraseEnv: "TEST_KEYSTORE_PASSPHRASE"}
+		require.NoError(t, config.Validate())
+		_, err := config.Build()
+		require.ErrorIs(t, err, keystore.ErrDecrypt)
+	})
+	t.Run("address without passphrase", func(t *testing.T) {
+		// the address is resolved without decrypting the key
+		config := &SignerConfig{KeystoreFile: path, KeystorePassphraseEnv: "TEST_KEYSTORE_PASSPHRASE_UNSET"}
+		addr, err := config.GetSignerAddress()
+		require.NoError(t, err)
+		require.Equal(t, crypto.PubkeyToAddress(privKey.PublicKey), addr)
+		// stdin is not a terminal in the test
+		_, err = config.Build()
+		require.ErrorContains(t, err, "the passphrase of the keystore file is neither set")
+	})
+	t.Run("missing file", func(t *testing.T) {
+		config := &SignerConfig{KeystoreFile: filepath.Join(t.TempDir(), "missing")}
+		require.Error(t, config.Validate())
+	})
+}
+
+func TestSignerConfigPrivateKeyEnv(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	hexKey := "0x" + hex.EncodeToString(crypto.FromECDSA(privKey))
+
+	t.Setenv("TEST_PRIVATE_KEY", hexKey)
+	config := &SignerConfig{PrivateKeyEnv: "TEST_PRIVATE_KEY"}
+	require.NoError(t, config.Validate())
+	requireSignerAddress(t, config, privKey)
+
+	t.Setenv("TEST_PRIVATE_KEY", "invalid")
+	_, err = config.Build()
+	require.Error(t, err)
+
+	config = &SignerConfig{PrivateKeyEnv: "TEST_PRIVATE_KEY_UNSET"}
+	_, err = config.Build()
+	require.ErrorContains(t, err, "the environment variable of the private key is not set")
+}
+
+func TestSignerConfigValidate(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	hexKey := hex.EncodeToString(crypto.FromECDSA(privKey))
+
+	var cases = []struct {
+		name   string
+		config SignerConfig
+		valid  bool
+	}{
+		{"private key", SignerConfig{PrivateKey: hexKey}, true},
+		{"invalid private key", SignerConfig{PrivateKey: "zz"}, false},
+		{"no source", SignerConfig{}, false},
+		{"multiple sources", SignerConfig{PrivateKey: hexKey, PrivateKeyEnv: "TEST_PRIVATE_KEY"}, false},
+		{"passphrase without keystore", SignerConfig{PrivateKeyEnv: "TEST_PRIVATE_KEY", KeystorePassphraseEnv: "TEST_KEYSTORE_PASSPHRASE"}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSignerConfigString(t *testing.T) {
+	privKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	hexKey := hex.EncodeToString(crypto.FromECDSA(privKey))
+
+	config := &SignerConfig{PrivateKey: hexKey}
+	require.NotContains(t, config.String(), hexKey)
+	require.Contains(t, config.String(), redacted)
+	// the config itself is not modified
+	require.Equal(t, hexKey, config.PrivateKey)
+
+	config = &SignerConfig{KeystoreFile: "keystore.json", KeystorePassphraseEnv: "PASSPHRASE"}
+	require.True(t, strings.Contains(config.String(), "keystore.json"), config.String())
+	require.NotContains(t, config.String(), redacted)
+}
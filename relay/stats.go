@@ -0,0 +1,287 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	pathStatsFile = "path_stats"
+
+	// statsQueueSize is the number of the stats events buffered before they are written.
+	// The events are dropped if the queue is full so that the relay is never blocked by the writes.
+	statsQueueSize = 1024
+)
+
+// PathStats is the counters of a path persisted in the prover home
+type PathStats struct {
+	// the number of the update messages returned to the relayer to be submitted
+	UpdatesSubmitted uint64 `json:"updates_submitted"`
+	// the number of the commitment proofs generated by the ELC
+	ProofsGenerated uint64 `json:"proofs_generated"`
+	// the number of the enclave key registrations submitted
+	KeyRotations uint64 `json:"key_rotations"`
+	// the time when the update messages were returned most recently. It is nil if no update has been submitted.
+	LastUpdateTime *time.Time `json:"last_update_time,omitempty"`
+}
+
+type statsEventKind int
+
+const (
+	statsEventUpdatesSubmitted statsEventKind = iota
+	statsEventProofGenerated
+	statsEventKeyRotated
+)
+
+type statsEvent struct {
+	pathKey string
+	kind    statsEventKind
+	count   uint64
+	time    time.Time
+}
+
+// statsRecorder applies the stats events to the stats in memory and writes them to the file in the background.
+// The events queued while a write is in progress are written together in the next write.
+type statsRecorder struct {
+	path   string
+	events chan statsEvent
+	done   chan struct{}
+	// the number of the events dropped because the queue is full
+	dropped atomic.Uint64
+
+	mu    sync.Mutex
+	stats map[string]PathStats
+
+	registration metric.Registration
+}
+
+// loadPathStats returns the stats of the paths keyed by `{counterparty chain ID}/{counterparty client ID}`
+func loadPathStats(path string) (map[string]PathStats, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]PathStats{}, nil
+		}
+		return nil, fmt.Errorf("failed to read file: path=%v %w", path, err)
+	}
+	stats := map[string]PathStats{}
+	if err := json.Unmarshal(bz, &stats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal path stats: path=%v %w", path, err)
+	}
+	return stats, nil
+}
+
+// newStatsRecorder loads the stats from the file at `path` and starts writing the recorded events to it.
+// The stats are exported as the gauges with `meter`.
+func newStatsRecorder(path string, meter metric.Meter) (*statsRecorder, error) {
+	stats, err := loadPathStats(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &statsRecorder{
+		path:   path,
+		events: make(chan statsEvent, statsQueueSize),
+		done:   make(chan struct{}),
+		stats:  stats,
+	}
+	r.registerGauges(meter)
+	go r.run()
+	return r, nil
+}
+
+func (r *statsRecorder) getLogger() *log.RelayLogger {
+	return log.GetLogger().WithModule(ModuleName)
+}
+
+// record queues the event without blocking
+func (r *statsRecorder) record(ev statsEvent) {
+	select {
+	case r.events <- ev:
+	default:
+		if r.dropped.Add(1) == 1 {
+			r.getLogger().Warn("the stats queue is full, the events are dropped", "queue_size", statsQueueSize)
+		}
+	}
+}
+
+func (r *statsRecorder) run() {
+	defer close(r.done)
+	for ev := range r.events {
+		r.apply(ev)
+		// apply the queued events together to write them at once
+		closed := false
+	drain:
+		for {
+			select {
+			case ev, ok := <-r.events:
+				if !ok {
+					closed = true
+					break drain
+				}
+				r.apply(ev)
+			default:
+				break drain
+			}
+		}
+		if err := r.write(); err != nil {
+			r.getLogger().Error("failed to write the path stats", err, "path", r.path)
+		}
+		if closed {
+			return
+		}
+	}
+}
+
+func (r *statsRecorder) apply(ev statsEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats[ev.pathKey]
+	switch ev.kind {
+	case statsEventUpdatesSubmitted:
+		s.UpdatesSubmitted += ev.count
+		t := ev.time
+		s.LastUpdateTime = &t
+	case statsEventProofGenerated:
+		s.ProofsGenerated += ev.count
+	case statsEventKeyRotated:
+		s.KeyRotations += ev.count
+	}
+	r.stats[ev.pathKey] = s
+}
+
+func (r *statsRecorder) write() error {
+	r.mu.Lock()
+	bz, err := json.Marshal(r.stats)
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal path stats: %w", err)
+	}
+	if err := os.WriteFile(r.path, bz, 0600); err != nil {
+		return fmt.Errorf("failed to write path stats: %w", err)
+	}
+	return nil
+}
+
+// snapshot returns a copy of the stats in memory
+func (r *statsRecorder) snapshot() map[string]PathStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make(map[string]PathStats, len(r.stats))
+	for k, v := range r.stats {
+		stats[k] = v
+	}
+	return stats
+}
+
+// close writes the queued events and stops the recorder
+func (r *statsRecorder) close() error {
+	close(r.events)
+	<-r.done
+	if r.registration != nil {
+		if err := r.registration.Unregister(); err != nil {
+			return fmt.Errorf("failed to unregister the gauges of the path stats: %w", err)
+		}
+	}
+	return nil
+}
+
+// registerGauges exports the stats as the gauges with `meter`.
+// The counters are gauges because they are restored from the file instead of counted from zero by the process.
+func (r *statsRecorder) registerGauges(meter metric.Meter) {
+	updates, err := meter.Int64ObservableGauge("lcp.path_updates_submitted", metric.WithUnit("1"), metric.WithDescription("total number of the update messages submitted for the path"))
+	if err != nil {
+		r.getLogger().Warn("failed to create the gauges of the path stats", "error", err)
+		return
+	}
+	proofs, err := meter.Int64ObservableGauge("lcp.path_proofs_generated", metric.WithUnit("1"), metric.WithDescription("total number of the commitment proofs generated for the path"))
+	if err != nil {
+		r.getLogger().Warn("failed to create the gauges of the path stats", "error", err)
+		return
+	}
+	rotations, err := meter.Int64ObservableGauge("lcp.path_key_rotations", metric.WithUnit("1"), metric.WithDescription("total number of the enclave key registrations submitted for the path"))
+	if err != nil {
+		r.getLogger().Warn("failed to create the gauges of the path stats", "error", err)
+		return
+	}
+	lastUpdate, err := meter.Int64ObservableGauge("lcp.path_last_update_time", metric.WithUnit("s"), metric.WithDescription("unix time of the last update submitted for the path"))
+	if err != nil {
+		r.getLogger().Warn("failed to create the gauges of the path stats", "error", err)
+		return
+	}
+	r.registration, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for key, s := range r.snapshot() {
+			attrs := metric.WithAttributes(attribute.String("path", key))
+			o.ObserveInt64(updates, int64(s.UpdatesSubmitted), attrs)
+			o.ObserveInt64(proofs, int64(s.ProofsGenerated), attrs)
+			o.ObserveInt64(rotations, int64(s.KeyRotations), attrs)
+			if s.LastUpdateTime != nil {
+				o.ObserveInt64(lastUpdate, s.LastUpdateTime.Unix(), attrs)
+			}
+		}
+		return nil
+	}, updates, proofs, rotations, lastUpdate)
+	if err != nil {
+		r.getLogger().Warn("failed to register the callback of the path stats", "error", err)
+	}
+}
+
+func (pr *Prover) pathStatsFilePath() string {
+	return filepath.Join(pr.dbPath(), pathStatsFile)
+}
+
+// startStats starts the recorder of the path stats if it is not started yet.
+// The stats are recorded only while relaying, so the interactive commands do not change them.
+func (pr *Prover) startStats() error {
+	pr.statsMu.Lock()
+	defer pr.statsMu.Unlock()
+	if pr.stats != nil {
+		return nil
+	}
+	r, err := newStatsRecorder(pr.pathStatsFilePath(), pr.meter())
+	if err != nil {
+		return err
+	}
+	pr.stats = r
+	return nil
+}
+
+// stopStats writes the queued events and stops the recorder if it is running
+func (pr *Prover) stopStats() error {
+	pr.statsMu.Lock()
+	defer pr.statsMu.Unlock()
+	if pr.stats == nil {
+		return nil
+	}
+	err := pr.stats.close()
+	pr.stats = nil
+	return err
+}
+
+// recordStats queues the event of the path to the counterparty LCP client if the recorder is running
+func (pr *Prover) recordStats(counterparty core.FinalityAwareChain, kind statsEventKind, count uint64) {
+	if counterparty == nil || pr.IsRehearsal() {
+		return
+	}
+	pr.statsMu.Lock()
+	defer pr.statsMu.Unlock()
+	if pr.stats == nil {
+		return
+	}
+	pr.stats.record(statsEvent{pathKey: counterpartyClientKey(counterparty), kind: kind, count: count, time: time.Now()})
+}
+
+// doQueryStats returns the path stats persisted in the prover home
+func (pr *Prover) doQueryStats() (map[string]PathStats, error) {
+	return loadPathStats(pr.pathStatsFilePath())
+}
@@ -0,0 +1,76 @@
+package relay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathStatsPersistence(t *testing.T) {
+	require := require.New(t)
+	homePath := t.TempDir()
+	newProver := func() *Prover {
+		pr := newTestProver(t)
+		pr.homePath = homePath
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		require.NoError(os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr
+	}
+	cp := &mockCounterparty{chainID: "counterparty"}
+	other := &mockCounterparty{chainID: "other"}
+
+	pr := newProver()
+	// the stats are not recorded until the relay starts
+	pr.recordStats(cp, statsEventProofGenerated, 1)
+	require.NoError(pr.startStats())
+	pr.recordStats(cp, statsEventUpdatesSubmitted, 2)
+	pr.recordStats(cp, statsEventProofGenerated, 1)
+	pr.recordStats(cp, statsEventProofGenerated, 1)
+	pr.recordStats(cp, statsEventKeyRotated, 1)
+	pr.recordStats(other, statsEventProofGenerated, 1)
+	require.NoError(pr.Close())
+
+	// restart the prover
+	pr = newProver()
+	stats, err := pr.doQueryStats()
+	require.NoError(err)
+	require.Len(stats, 2)
+	s := stats["counterparty/lcp-client-0"]
+	require.Equal(uint64(2), s.UpdatesSubmitted)
+	require.Equal(uint64(2), s.ProofsGenerated)
+	require.Equal(uint64(1), s.KeyRotations)
+	require.NotNil(s.LastUpdateTime)
+	lastUpdateTime := *s.LastUpdateTime
+	require.Equal(PathStats{ProofsGenerated: 1}, stats["other/lcp-client-0"])
+
+	// the counters continue from the persisted values
+	require.NoError(pr.startStats())
+	pr.recordStats(cp, statsEventUpdatesSubmitted, 1)
+	require.NoError(pr.Close())
+	stats, err = pr.doQueryStats()
+	require.NoError(err)
+	s = stats["counterparty/lcp-client-0"]
+	require.Equal(uint64(3), s.UpdatesSubmitted)
+	require.Equal(uint64(2), s.ProofsGenerated)
+	require.False(s.LastUpdateTime.Before(lastUpdateTime))
+}
+
+func TestPathStatsQueueFull(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), pathStatsFile)
+	r := &statsRecorder{path: path, events: make(chan statsEvent, 1), done: make(chan struct{}), stats: map[string]PathStats{}}
+	// the writer is not running, so the queue is never consumed
+	r.record(statsEvent{pathKey: "a", kind: statsEventProofGenerated, count: 1})
+	r.record(statsEvent{pathKey: "a", kind: statsEventProofGenerated, count: 1})
+	r.record(statsEvent{pathKey: "a", kind: statsEventProofGenerated, count: 1})
+	require.Equal(uint64(2), r.dropped.Load())
+
+	// the queued event is written on close
+	go r.run()
+	require.NoError(r.close())
+	stats, err := loadPathStats(path)
+	require.NoError(err)
+	require.Equal(uint64(1), stats["a"].ProofsGenerated)
+}
@@ -0,0 +1,346 @@
+package relay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+const submissionQueueFile = "submission_queue"
+
+// submissionDependencies declares the labels of the msgs that must be submitted before the msgs of each label to the same client.
+// e.g. the registrations of the enclave keys may rely on the new operators, and the updates rely on the registered keys.
+var submissionDependencies = map[string][]string{
+	"update_client_params":                   {"update_operators"},
+	"register_enclave_key":                   {"update_operators"},
+	"register_enclave_key_and_update_client": {"update_operators"},
+	"activate_client":                        {"update_operators", "register_enclave_key", "register_enclave_key_and_update_client"},
+}
+
+// unbatchedSubmissionLabels are the labels of the msgs which consume the operators nonce, so each of them is submitted in its own tx
+var unbatchedSubmissionLabels = map[string]bool{
+	"update_operators":     true,
+	"update_client_params": true,
+}
+
+// queuedSubmission is the msgs waiting for the submission in the submission queue
+type queuedSubmission struct {
+	ID                   uint64            `json:"id"`
+	Label                string            `json:"label"`
+	CounterpartyChainID  string            `json:"counterparty_chain_id"`
+	CounterpartyClientID string            `json:"counterparty_client_id"`
+	Msgs                 []json.RawMessage `json:"msgs"`
+	EnqueuedAt           time.Time         `json:"enqueued_at"`
+}
+
+// dependsOn returns true if `s` must be submitted after `other`
+func (s queuedSubmission) dependsOn(other queuedSubmission) bool {
+	if s.CounterpartyChainID != other.CounterpartyChainID || s.CounterpartyClientID != other.CounterpartyClientID {
+		return false
+	}
+	for _, label := range submissionDependencies[s.Label] {
+		if label == other.Label {
+			return true
+		}
+	}
+	return false
+}
+
+// submissionQueue is the persisted state of the submission queue
+type submissionQueue struct {
+	NextID  uint64             `json:"next_id"`
+	Entries []queuedSubmission `json:"entries"`
+}
+
+// remove removes the entries of `ids` from the queue
+func (q *submissionQueue) remove(ids ...uint64) {
+	removed := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		removed[id] = true
+	}
+	entries := q.Entries[:0]
+	for _, e := range q.Entries {
+		if !removed[e.ID] {
+			entries = append(entries, e)
+		}
+	}
+	q.Entries = entries
+}
+
+func (q *submissionQueue) contains(id uint64) bool {
+	for _, e := range q.Entries {
+		if e.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// orderSubmissions returns `entries` in the order of the submission,
+// where each entry follows its dependencies and the entries without dependencies between them are in the order of the enqueue.
+func orderSubmissions(entries []queuedSubmission) []queuedSubmission {
+	pending := make([]queuedSubmission, len(entries))
+	copy(pending, entries)
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].ID < pending[j].ID
+	})
+	ordered := make([]queuedSubmission, 0, len(pending))
+	for len(pending) > 0 {
+		// the dependencies between the labels are acyclic, so there is always an entry whose dependencies have been submitted
+		for i, e := range pending {
+			ready := true
+			for _, other := range pending {
+				if e.dependsOn(other) {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, e)
+				pending = append(pending[:i], pending[i+1:]...)
+				break
+			}
+		}
+	}
+	return ordered
+}
+
+// batchSubmissions splits the ordered entries into the txs.
+// The consecutive entries with the same label for the same client are batched in a tx up to `maxMsgs` msgs, where zero means no limit.
+func batchSubmissions(ordered []queuedSubmission, maxMsgs uint32) [][]queuedSubmission {
+	var (
+		batches [][]queuedSubmission
+		numMsgs int
+	)
+	for _, e := range ordered {
+		if n := len(batches); n > 0 {
+			last := batches[n-1]
+			head := last[0]
+			if !unbatchedSubmissionLabels[e.Label] && head.Label == e.Label &&
+				head.CounterpartyChainID == e.CounterpartyChainID && head.CounterpartyClientID == e.CounterpartyClientID &&
+				(maxMsgs == 0 || numMsgs+len(e.Msgs) <= int(maxMsgs)) {
+				batches[n-1] = append(last, e)
+				numMsgs += len(e.Msgs)
+				continue
+			}
+		}
+		batches = append(batches, []queuedSubmission{e})
+		numMsgs = len(e.Msgs)
+	}
+	return batches
+}
+
+func (pr *Prover) submissionQueueFilePath() string {
+	return filepath.Join(pr.dbPath(), submissionQueueFile)
+}
+
+func (pr *Prover) loadSubmissionQueue() (*submissionQueue, error) {
+	path := pr.submissionQueueFilePath()
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &submissionQueue{}, nil
+		}
+		return nil, fmt.Errorf("failed to read file: path=%v %w", path, err)
+	}
+	var q submissionQueue
+	if err := json.Unmarshal(bz, &q); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the submission queue: path=%v %w", path, err)
+	}
+	return &q, nil
+}
+
+func (pr *Prover) saveSubmissionQueue(q *submissionQueue) error {
+	bz, err := json.Marshal(q)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the submission queue: %w", err)
+	}
+	path := pr.submissionQueueFilePath()
+	if err := os.WriteFile(path, bz, 0600); err != nil {
+		return fmt.Errorf("failed to write the submission queue: path=%v %w", path, err)
+	}
+	return nil
+}
+
+// enqueueSubmission persists `msgs` in the submission queue and returns the ID of the entry
+func (pr *Prover) enqueueSubmission(counterparty core.Chain, label string, msgs []sdk.Msg) (uint64, error) {
+	q, err := pr.loadSubmissionQueue()
+	if err != nil {
+		return 0, err
+	}
+	entry := queuedSubmission{
+		ID:                   q.NextID,
+		Label:                label,
+		CounterpartyChainID:  counterparty.ChainID(),
+		CounterpartyClientID: counterparty.Path().ClientID,
+		EnqueuedAt:           time.Now(),
+	}
+	for i, msg := range msgs {
+		bz, err := pr.codec.MarshalInterfaceJSON(msg)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal the msg: index=%v %w", i, err)
+		}
+		entry.Msgs = append(entry.Msgs, bz)
+	}
+	q.NextID++
+	q.Entries = append(q.Entries, entry)
+	if err := pr.saveSubmissionQueue(q); err != nil {
+		return 0, err
+	}
+	return entry.ID, nil
+}
+
+// flushSubmissionQueue submits the queued msgs to `counterparty` in the order of their dependencies and returns the msg IDs of each submitted entry.
+// Each submitted batch is removed from the queue. If the submission of a batch fails, the batch is removed and the later ones are left in the queue.
+func (pr *Prover) flushSubmissionQueue(counterparty core.Chain) (map[uint64][]core.MsgID, error) {
+	q, err := pr.loadSubmissionQueue()
+	if err != nil {
+		return nil, err
+	}
+	var entries []queuedSubmission
+	for _, e := range q.Entries {
+		if e.CounterpartyChainID == counterparty.ChainID() && e.CounterpartyClientID == counterparty.Path().ClientID {
+			entries = append(entries, e)
+		}
+	}
+	submitted := make(map[uint64][]core.MsgID)
+	for _, batch := range batchSubmissions(orderSubmissions(entries), pr.config.SubmissionQueueMaxMsgsPerTx) {
+		var (
+			ids  []uint64
+			msgs []sdk.Msg
+		)
+		for _, e := range batch {
+			ids = append(ids, e.ID)
+			for i, bz := range e.Msgs {
+				var msg sdk.Msg
+				if err := pr.codec.UnmarshalInterfaceJSON(bz, &msg); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal the queued msg: id=%v index=%v %w", e.ID, i, err)
+				}
+				msgs = append(msgs, msg)
+			}
+		}
+		label := batch[0].Label
+		pr.getLogger().Info("submit the queued msgs", "label", label, "entries", ids, "num_msgs", len(msgs))
+		msgIDs, sendErr := pr.sendMsgsWithTxOptions(counterparty, label, msgs)
+		q.remove(ids...)
+		if err := pr.saveSubmissionQueue(q); err != nil {
+			return nil, err
+		}
+		if sendErr != nil {
+			return submitted, fmt.Errorf("failed to submit the queued msgs: label=%v entries=%v %w", label, ids, sendErr)
+		} else if len(msgIDs) != len(msgs) {
+			return submitted, fmt.Errorf("unexpected number of msgIDs: label=%v entries=%v expected=%v actual=%v", label, ids, len(msgs), len(msgIDs))
+		}
+		for _, e := range batch {
+			submitted[e.ID], msgIDs = msgIDs[:len(e.Msgs)], msgIDs[len(e.Msgs):]
+		}
+	}
+	return submitted, nil
+}
+
+// submitQueuedMsgs enqueues `msgs` and submits the queue including the msgs left by a previous run.
+// If `msgs` are not submitted because of a failure, they are removed from the queue so that the caller can retry them.
+func (pr *Prover) submitQueuedMsgs(counterparty core.Chain, label string, msgs []sdk.Msg) ([]core.MsgID, error) {
+	pr.submissionQueueMu.Lock()
+	defer pr.submissionQueueMu.Unlock()
+	id, err := pr.enqueueSubmission(counterparty, label, msgs)
+	if err != nil {
+		return nil, err
+	}
+	submitted, flushErr := pr.flushSubmissionQueue(counterparty)
+	if ids, ok := submitted[id]; ok {
+		if flushErr != nil {
+			pr.getLogger().Warn("the msgs were submitted, but a later submission in the queue failed", "label", label, "error", flushErr)
+		}
+		return ids, nil
+	}
+	q, err := pr.loadSubmissionQueue()
+	if err != nil {
+		return nil, err
+	} else if q.contains(id) {
+		q.remove(id)
+		if err := pr.saveSubmissionQueue(q); err != nil {
+			return nil, err
+		}
+	}
+	if flushErr == nil {
+		return nil, fmt.Errorf("the queued msgs were not submitted: label=%v id=%v", label, id)
+	}
+	return nil, flushErr
+}
+
+// resumeSubmissionQueue submits the msgs left in the queue by a previous run, e.g. which crashed before the submission.
+// A failure is only logged because the msgs are submitted again by the flows which enqueued them.
+func (pr *Prover) resumeSubmissionQueue(counterparty core.Chain) {
+	if !pr.config.SubmissionQueue || pr.IsRehearsal() {
+		return
+	}
+	pr.submissionQueueMu.Lock()
+	defer pr.submissionQueueMu.Unlock()
+	q, err := pr.loadSubmissionQueue()
+	if err != nil {
+		pr.getLogger().Warn("failed to load the submission queue", "error", err)
+		return
+	} else if len(q.Entries) == 0 {
+		return
+	}
+	if _, err := pr.flushSubmissionQueue(counterparty); err != nil {
+		pr.getLogger().Warn("failed to submit the msgs left in the submission queue", "error", err)
+	}
+}
+
+// QueuedSubmission describes an entry of the submission queue
+type QueuedSubmission struct {
+	ID                   uint64    `json:"id"`
+	Label                string    `json:"label"`
+	CounterpartyChainID  string    `json:"counterparty_chain_id"`
+	CounterpartyClientID string    `json:"counterparty_client_id"`
+	NumMsgs              int       `json:"num_msgs"`
+	EnqueuedAt           time.Time `json:"enqueued_at"`
+	// the IDs of the queued entries which are submitted before this entry
+	DependsOn []uint64 `json:"depends_on"`
+	// the index of the tx in which this entry is submitted
+	Batch int `json:"batch"`
+}
+
+// QueryQueueResult is a result of doQueryQueue
+type QueryQueueResult struct {
+	// in the order of the submission
+	Entries []QueuedSubmission `json:"entries"`
+}
+
+// doQueryQueue returns the entries of the submission queue in the order of the submission
+func (pr *Prover) doQueryQueue() (*QueryQueueResult, error) {
+	q, err := pr.loadSubmissionQueue()
+	if err != nil {
+		return nil, err
+	}
+	result := &QueryQueueResult{Entries: []QueuedSubmission{}}
+	for i, batch := range batchSubmissions(orderSubmissions(q.Entries), pr.config.SubmissionQueueMaxMsgsPerTx) {
+		for _, e := range batch {
+			s := QueuedSubmission{
+				ID:                   e.ID,
+				Label:                e.Label,
+				CounterpartyChainID:  e.CounterpartyChainID,
+				CounterpartyClientID: e.CounterpartyClientID,
+				NumMsgs:              len(e.Msgs),
+				EnqueuedAt:           e.EnqueuedAt,
+				DependsOn:            []uint64{},
+				Batch:                i,
+			}
+			for _, other := range q.Entries {
+				if e.dependsOn(other) {
+					s.DependsOn = append(s.DependsOn, other.ID)
+				}
+			}
+			result.Entries = append(result.Entries, s)
+		}
+	}
+	return result, nil
+}
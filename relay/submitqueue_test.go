@@ -0,0 +1,168 @@
+package relay
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQueuedSubmission(id uint64, label string, numMsgs int) queuedSubmission {
+	return queuedSubmission{ID: id, Label: label, CounterpartyChainID: "counterparty", CounterpartyClientID: "lcp-client-0", Msgs: make([]json.RawMessage, numMsgs)}
+}
+
+func submissionIDs(entries []queuedSubmission) []uint64 {
+	var ids []uint64
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestOrderSubmissions(t *testing.T) {
+	require := require.New(t)
+	entries := []queuedSubmission{
+		newTestQueuedSubmission(0, "activate_client", 3),
+		newTestQueuedSubmission(1, "register_enclave_key", 1),
+		newTestQueuedSubmission(2, "update_operators", 1),
+		newTestQueuedSubmission(3, "register_enclave_key", 1),
+		newTestQueuedSubmission(4, "update_client_params", 1),
+	}
+	// the entries for another client do not depend on the ones above
+	other := newTestQueuedSubmission(5, "register_enclave_key", 1)
+	other.CounterpartyClientID = "lcp-client-1"
+	entries = append(entries, other)
+
+	ordered := orderSubmissions(entries)
+	require.Equal([]uint64{2, 1, 3, 0, 4, 5}, submissionIDs(ordered))
+
+	var batches [][]uint64
+	for _, batch := range batchSubmissions(ordered, 0) {
+		batches = append(batches, submissionIDs(batch))
+	}
+	require.Equal([][]uint64{{2}, {1, 3}, {0}, {4}, {5}}, batches)
+
+	// the batch is limited by the number of the msgs
+	batches = nil
+	for _, batch := range batchSubmissions(ordered, 1) {
+		batches = append(batches, submissionIDs(batch))
+	}
+	require.Equal([][]uint64{{2}, {1}, {3}, {0}, {4}, {5}}, batches)
+
+	// the operators nonce is consumed by each msg, so they are not batched
+	batches = nil
+	for _, batch := range batchSubmissions([]queuedSubmission{newTestQueuedSubmission(0, "update_operators", 1), newTestQueuedSubmission(1, "update_operators", 1)}, 0) {
+		batches = append(batches, submissionIDs(batch))
+	}
+	require.Equal([][]uint64{{0}, {1}}, batches)
+}
+
+func TestSubmissionQueueRestartRecovery(t *testing.T) {
+	newProver := func(t *testing.T, home string) *Prover {
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = home
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.SubmissionQueue = true
+		require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+		return pr
+	}
+	newMsg := func(tag string) sdk.Msg {
+		return &clienttypes.MsgUpdateClient{ClientId: "lcp-client-0", Signer: tag}
+	}
+	sentTags := func(cp *mockCounterparty) [][]string {
+		var txs [][]string
+		for _, msgs := range cp.sentMsgs {
+			var tags []string
+			for _, msg := range msgs {
+				tags = append(tags, msg.(*clienttypes.MsgUpdateClient).Signer)
+			}
+			txs = append(txs, tags)
+		}
+		return txs
+	}
+
+	t.Run("msgs left by a crash", func(t *testing.T) {
+		require := require.New(t)
+		home := t.TempDir()
+		cp := newMockCounterparty(clienttypes.NewHeight(0, 1))
+
+		// the previous run crashed after enqueueing the msgs
+		crashed := newProver(t, home)
+		_, err := crashed.enqueueSubmission(cp, "register_enclave_key", []sdk.Msg{newMsg("register-0")})
+		require.NoError(err)
+		_, err = crashed.enqueueSubmission(cp, "update_operators", []sdk.Msg{newMsg("operators")})
+		require.NoError(err)
+		res, err := crashed.doQueryQueue()
+		require.NoError(err)
+		require.Len(res.Entries, 2)
+		require.Equal("update_operators", res.Entries[0].Label)
+		require.Equal([]uint64{1}, res.Entries[1].DependsOn)
+
+		pr := newProver(t, home)
+		ids, err := pr.sendMsgs(cp, "register_enclave_key", []sdk.Msg{newMsg("register-1")})
+		require.NoError(err)
+		require.Len(ids, 1)
+		// the operator update is submitted first, and the registrations are batched
+		require.Equal([][]string{{"operators"}, {"register-0", "register-1"}}, sentTags(cp))
+		require.Equal(uint32(1), ids[0].(*tendermint.MsgID).MsgIndex)
+
+		res, err = pr.doQueryQueue()
+		require.NoError(err)
+		require.Empty(res.Entries)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		require := require.New(t)
+		home := t.TempDir()
+		cp := newMockCounterparty(clienttypes.NewHeight(0, 1))
+		crashed := newProver(t, home)
+		_, err := crashed.enqueueSubmission(cp, "update_client_params", []sdk.Msg{newMsg("params")})
+		require.NoError(err)
+
+		pr := newProver(t, home)
+		pr.resumeSubmissionQueue(cp)
+		require.Equal([][]string{{"params"}}, sentTags(cp))
+		res, err := pr.doQueryQueue()
+		require.NoError(err)
+		require.Empty(res.Entries)
+	})
+
+	t.Run("failure of a preceding submission", func(t *testing.T) {
+		require := require.New(t)
+		home := t.TempDir()
+		cp := newMockCounterparty(clienttypes.NewHeight(0, 1))
+		errInvalidNonce := errors.New("invalid nonce")
+		cp.sendMsgsErr = func(msgs []sdk.Msg) error {
+			if msgs[0].(*clienttypes.MsgUpdateClient).Signer == "operators" {
+				return errInvalidNonce
+			}
+			return nil
+		}
+		crashed := newProver(t, home)
+		_, err := crashed.enqueueSubmission(cp, "update_operators", []sdk.Msg{newMsg("operators")})
+		require.NoError(err)
+		_, err = crashed.enqueueSubmission(cp, "activate_client", []sdk.Msg{newMsg("activate")})
+		require.NoError(err)
+
+		pr := newProver(t, home)
+		_, err = pr.sendMsgs(cp, "register_enclave_key", []sdk.Msg{newMsg("register")})
+		require.ErrorIs(err, errInvalidNonce)
+		require.Empty(cp.sentMsgs)
+		// the failed entry and the caller's entry are removed, and the other dependent is left
+		res, err := pr.doQueryQueue()
+		require.NoError(err)
+		require.Len(res.Entries, 1)
+		require.Equal("activate_client", res.Entries[0].Label)
+
+		// the caller can retry the msgs
+		_, err = pr.sendMsgs(cp, "register_enclave_key", []sdk.Msg{newMsg("register")})
+		require.NoError(err)
+		require.Equal([][]string{{"register"}, {"activate"}}, sentTags(cp))
+	})
+}
@@ -0,0 +1,146 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// the timestamp is lower than the one of the previous update in the batch
+	timestampRegressionSourceBatch = "batch"
+	// the timestamp is lower than the one of the latest consensus state of the counterparty LCP client
+	timestampRegressionSourceCounterparty = "counterparty"
+)
+
+// TimestampRegressionError is returned if the timestamp of an update emitted by the ELC is lower than the one of a lower height.
+// The counterparty LCP client stores the timestamp as it is, so such an update breaks the timeouts of the packets.
+type TimestampRegressionError struct {
+	ChainID       string
+	ClientID      string
+	Source        string
+	Height        clienttypes.Height
+	Timestamp     uint64
+	PrevHeight    clienttypes.Height
+	PrevTimestamp uint64
+}
+
+func (e *TimestampRegressionError) Error() string {
+	return fmt.Sprintf(
+		"the timestamp of the update regressed: chain_id=%v client_id=%v source=%v height=%v timestamp=%v prev_height=%v prev_timestamp=%v",
+		e.ChainID, e.ClientID, e.Source, e.Height, e.Timestamp, e.PrevHeight, e.PrevTimestamp,
+	)
+}
+
+// checkTimestampMonotonicity returns TimestampRegressionError if the timestamps of the updates `msgs` are not monotonic
+// across the heights, either in the batch or from the latest consensus state of the counterparty LCP client.
+// If the severity is "warn", the regression is only logged and alerted.
+func (pr *Prover) checkTimestampMonotonicity(ctx context.Context, counterparty core.FinalityAwareChain, msgs []*lcptypes.UpdateStateProxyMessage) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	var regressions []*TimestampRegressionError
+	latestHeight, latestTimestamp, err := pr.queryCounterpartyLatestTimestamp(ctx, counterparty)
+	if err != nil {
+		// the check in the batch is still effective, and the submission fails anyway if the counterparty chain is unreachable
+		pr.getLogger().Warn("skip the timestamp check against the counterparty LCP client", "error", err)
+	} else if latestHeight != nil {
+		for _, msg := range msgs {
+			if msg.PostHeight.GT(*latestHeight) && msg.Timestamp.Uint64() < latestTimestamp {
+				regressions = append(regressions, pr.newTimestampRegressionError(counterparty, timestampRegressionSourceCounterparty, msg, *latestHeight, latestTimestamp))
+				break
+			}
+		}
+	}
+	for i := 1; i < len(msgs); i++ {
+		prev, msg := msgs[i-1], msgs[i]
+		if msg.PostHeight.GT(prev.PostHeight) && msg.Timestamp.Cmp(prev.Timestamp) < 0 {
+			regressions = append(regressions, pr.newTimestampRegressionError(counterparty, timestampRegressionSourceBatch, msg, prev.PostHeight, prev.Timestamp.Uint64()))
+			break
+		}
+	}
+	if len(regressions) == 0 {
+		return nil
+	}
+	for _, regression := range regressions {
+		pr.countTimestampRegression(ctx, counterparty.ChainID(), regression.Source)
+		pr.alert(AlertTimestampRegressed, regression.ClientID, "the timestamp of the update regressed",
+			"counterparty_chain_id", regression.ChainID,
+			"source", regression.Source,
+			"height", regression.Height,
+			"timestamp", regression.Timestamp,
+			"prev_height", regression.PrevHeight,
+			"prev_timestamp", regression.PrevTimestamp,
+			"severity", pr.config.GetTimestampRegressionSeverity(),
+		)
+	}
+	if pr.config.GetTimestampRegressionSeverity() == SeverityWarn {
+		return nil
+	}
+	return regressions[0]
+}
+
+func (pr *Prover) newTimestampRegressionError(counterparty core.FinalityAwareChain, source string, msg *lcptypes.UpdateStateProxyMessage, prevHeight clienttypes.Height, prevTimestamp uint64) *TimestampRegressionError {
+	return &TimestampRegressionError{
+		ChainID:       counterparty.ChainID(),
+		ClientID:      counterparty.Path().ClientID,
+		Source:        source,
+		Height:        msg.PostHeight,
+		Timestamp:     msg.Timestamp.Uint64(),
+		PrevHeight:    prevHeight,
+		PrevTimestamp: prevTimestamp,
+	}
+}
+
+// queryCounterpartyLatestTimestamp returns the latest height of the counterparty LCP client and the timestamp of its consensus state.
+// The height is nil if the consensus state does not exist, e.g. the client has not been initialized yet.
+func (pr *Prover) queryCounterpartyLatestTimestamp(ctx context.Context, counterparty core.FinalityAwareChain) (*clienttypes.Height, uint64, error) {
+	latestHeight, err := pr.queryCounterpartyClientHeight(ctx, counterparty)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpQueryHeight, err := counterparty.LatestHeight()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get the latest height of the counterparty chain: %w", err)
+	}
+	res, err := counterparty.QueryClientConsensusState(core.NewQueryContext(ctx, cpQueryHeight), latestHeight)
+	if errors.Is(err, clienttypes.ErrConsensusStateNotFound) {
+		return nil, 0, nil
+	} else if err != nil {
+		return nil, 0, fmt.Errorf("failed to query the consensus state on the counterparty chain: height=%v %w", latestHeight, err)
+	}
+	var cons ibcexported.ConsensusState
+	if err := pr.codec.UnpackAny(res.ConsensusState, &cons); err != nil {
+		return nil, 0, fmt.Errorf("failed to unpack consensus state: %w", err)
+	}
+	lcpCons, ok := cons.(*lcptypes.ConsensusState)
+	if !ok {
+		return nil, 0, fmt.Errorf("unexpected consensus state type: %T", cons)
+	}
+	return &latestHeight, lcpCons.Timestamp, nil
+}
+
+// countTimestampRegression increments the counter of the detected regressions of the update timestamps.
+// The counter is recorded with the metrics sink of the prover.
+func (pr *Prover) countTimestampRegression(ctx context.Context, chainID string, source string) {
+	counter, err := pr.meter().Int64Counter(
+		"lcp.timestamp_regressions",
+		metric.WithUnit("1"),
+		metric.WithDescription("number of detected regressions of the timestamps of the updates emitted by the ELC"),
+	)
+	if err != nil {
+		pr.getLogger().Warn("failed to create the counter of the timestamp regressions", "error", err)
+		return
+	}
+	counter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("chain_id", chainID),
+		attribute.String("source", source),
+	))
+}
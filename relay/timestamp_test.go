@@ -0,0 +1,102 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampMonotonicity(t *testing.T) {
+	// newUpdates returns the updates from height 11 with the timestamps `timestamps`
+	newUpdates := func(timestamps ...int64) []*lcptypes.UpdateStateProxyMessage {
+		var msgs []*lcptypes.UpdateStateProxyMessage
+		for i, ts := range timestamps {
+			msgs = append(msgs, &lcptypes.UpdateStateProxyMessage{
+				PostHeight: clienttypes.NewHeight(0, uint64(11+i)),
+				Timestamp:  big.NewInt(ts),
+			})
+		}
+		return msgs
+	}
+	// the latest consensus state of the counterparty LCP client is at height 10 with timestamp 1000
+	newCounterparty := func() *mockConsensusStatesCounterparty {
+		counterparty := newMockCounterparty(clienttypes.NewHeight(0, 1))
+		counterparty.clientState = &lcptypes.ClientState{LatestHeight: clienttypes.NewHeight(0, 10)}
+		return &mockConsensusStatesCounterparty{
+			mockCounterparty: counterparty,
+			consensusStates:  map[uint64]*lcptypes.ConsensusState{10: {Timestamp: 1000}},
+		}
+	}
+
+	var cases = []struct {
+		name       string
+		timestamps []int64
+		// empty if no regression is expected
+		source     string
+		height     uint64
+		prevHeight uint64
+	}{
+		{"monotonic", []int64{1000, 1001, 1001, 1002}, "", 0, 0},
+		{"regression in the batch", []int64{1001, 1003, 1002}, timestampRegressionSourceBatch, 13, 12},
+		{"regression from the counterparty", []int64{999, 1001}, timestampRegressionSourceCounterparty, 11, 10},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			pr.codec = newTestCodec()
+			counterparty := newCounterparty()
+			err := pr.checkTimestampMonotonicity(context.TODO(), counterparty, newUpdates(c.timestamps...))
+			if c.source == "" {
+				require.NoError(err)
+				return
+			}
+			var regression *TimestampRegressionError
+			require.True(errors.As(err, &regression), err)
+			require.Equal(c.source, regression.Source)
+			require.Equal(clienttypes.NewHeight(0, c.height), regression.Height)
+			require.Equal(clienttypes.NewHeight(0, c.prevHeight), regression.PrevHeight)
+			require.Equal("lcp-client-0", regression.ClientID)
+		})
+	}
+
+	t.Run("warn only", func(t *testing.T) {
+		require := require.New(t)
+		srv, payloads := newAlertCaptureServer(t, http.StatusOK)
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.config.TimestampRegressionSeverity = SeverityWarn
+		pr.config.AlertWebhookUrl = srv.URL
+		alerter, err := newProverAlerter(pr.config)
+		require.NoError(err)
+		pr.alerter = alerter
+
+		require.NoError(pr.checkTimestampMonotonicity(context.TODO(), newCounterparty(), newUpdates(1001, 1000)))
+		pr.alerter.wait()
+		require.Len(payloads(), 1)
+		var alert Alert
+		require.NoError(json.Unmarshal(payloads()[0], &alert))
+		require.Equal(AlertTimestampRegressed, alert.Condition)
+	})
+
+	t.Run("counterparty without consensus state", func(t *testing.T) {
+		require := require.New(t)
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		counterparty := newCounterparty()
+		counterparty.consensusStates = nil
+		require.NoError(pr.checkTimestampMonotonicity(context.TODO(), counterparty, newUpdates(1, 2)))
+		// the check in the batch is still effective
+		var regression *TimestampRegressionError
+		err := pr.checkTimestampMonotonicity(context.TODO(), counterparty, newUpdates(2, 1))
+		require.True(errors.As(err, &regression), err)
+		require.Equal(timestampRegressionSourceBatch, regression.Source)
+	})
+}
@@ -0,0 +1,66 @@
+package relay
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// lcpServiceTransportCredentials returns the transport credentials of the connection to the LCP service.
+// The connection is insecure unless LcpServiceTlsEnabled is set.
+func (pc ProverConfig) lcpServiceTransportCredentials() (credentials.TransportCredentials, error) {
+	if !pc.LcpServiceTlsEnabled {
+		return insecure.NewCredentials(), nil
+	}
+	cfg, err := pc.lcpServiceTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// lcpServiceTLSConfig returns the TLS config with the CA certificates and the client certificate in the files of the config
+func (pc ProverConfig) lcpServiceTLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: pc.LcpServiceTlsServerNameOverride,
+	}
+	if path := pc.LcpServiceTlsCaCert; path != "" {
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read LcpServiceTlsCaCert: path=%v %w", path, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bz) {
+			return nil, fmt.Errorf("LcpServiceTlsCaCert contains no PEM certificate: path=%v", path)
+		}
+		cfg.RootCAs = pool
+	}
+	if pc.LcpServiceClientCert != "" || pc.LcpServiceClientKey != "" {
+		if pc.LcpServiceClientCert == "" || pc.LcpServiceClientKey == "" {
+			return nil, fmt.Errorf("LcpServiceClientCert and LcpServiceClientKey must be set together: cert=%q key=%q", pc.LcpServiceClientCert, pc.LcpServiceClientKey)
+		}
+		cert, err := tls.LoadX509KeyPair(pc.LcpServiceClientCert, pc.LcpServiceClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate for mTLS: cert=%v key=%v %w", pc.LcpServiceClientCert, pc.LcpServiceClientKey, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// validateLcpServiceTLS checks that the TLS files of the config can be loaded if TLS is enabled
+func (pc ProverConfig) validateLcpServiceTLS() error {
+	if !pc.LcpServiceTlsEnabled {
+		if pc.LcpServiceTlsCaCert != "" || pc.LcpServiceClientCert != "" || pc.LcpServiceClientKey != "" || pc.LcpServiceTlsServerNameOverride != "" {
+			return fmt.Errorf("LcpServiceTlsEnabled must be true if the TLS options of the LCP service are set")
+		}
+		return nil
+	}
+	_, err := pc.lcpServiceTLSConfig()
+	return err
+}
@@ -0,0 +1,172 @@
+package relay
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// testCertificate is a certificate and its key which are also written to PEM files
+type testCertificate struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certPath string
+	keyPath  string
+}
+
+func (c *testCertificate) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key}
+}
+
+// newTestCertificate issues a certificate of `name` signed by `issuer`, or a self-signed CA certificate if `issuer` is nil
+func newTestCertificate(t *testing.T, name string, issuer *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	parent, signer := template, key
+	if issuer == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	c := &testCertificate{cert: cert, key: key, certPath: filepath.Join(dir, name+".crt"), keyPath: filepath.Join(dir, name+".key")}
+	require.NoError(t, os.WriteFile(c.certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(c.keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return c
+}
+
+// startTestTLSServer starts an LCP service with the certificate of `server`.
+// If `clientCA` is not nil, the clients must present a certificate signed by it.
+func startTestTLSServer(t *testing.T, server *testCertificate, clientCA *testCertificate) string {
+	cfg := &tls.Config{Certificates: []tls.Certificate{server.tlsCertificate()}, MinVersion: tls.VersionTLS12}
+	if clientCA != nil {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCA.cert)
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(cfg)))
+	elc.RegisterQueryServer(s, &metadataRecordingELCQueryServer{received: make(chan metadata.MD, 16)})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+func TestLCPServiceTLS(t *testing.T) {
+	ca := newTestCertificate(t, "ca", nil)
+	serverCert := newTestCertificate(t, "lcp-service", ca)
+	clientCert := newTestCertificate(t, "relayer", ca)
+	otherCA := newTestCertificate(t, "other-ca", nil)
+
+	query := func(t *testing.T, config ProverConfig, addr string) error {
+		require.NoError(t, config.validateLcpServiceTLS())
+		creds, err := config.lcpServiceTransportCredentials()
+		require.NoError(t, err)
+		conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+		require.NoError(t, err)
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = NewLCPServiceClient(conn).Client(ctx, &elc.QueryClientRequest{ClientId: "07-tendermint-0"})
+		return err
+	}
+
+	t.Run("TLS", func(t *testing.T) {
+		addr := startTestTLSServer(t, serverCert, nil)
+		config := ProverConfig{LcpServiceTlsEnabled: true, LcpServiceTlsCaCert: ca.certPath, LcpServiceTlsServerNameOverride: "lcp-service"}
+		require.NoError(t, query(t, config, addr))
+
+		// the certificate of the server does not chain to the CA
+		config.LcpServiceTlsCaCert = otherCA.certPath
+		require.ErrorContains(t, query(t, config, addr), "certificate signed by unknown authority")
+
+		// the certificate of the server is not issued for the name
+		config.LcpServiceTlsCaCert = ca.certPath
+		config.LcpServiceTlsServerNameOverride = "other-service"
+		require.ErrorContains(t, query(t, config, addr), "certificate is valid for lcp-service")
+	})
+
+	t.Run("mTLS", func(t *testing.T) {
+		addr := startTestTLSServer(t, serverCert, ca)
+		config := ProverConfig{LcpServiceTlsEnabled: true, LcpServiceTlsCaCert: ca.certPath, LcpServiceTlsServerNameOverride: "lcp-service"}
+		// the client certificate is required by the server
+		require.Error(t, query(t, config, addr))
+
+		config.LcpServiceClientCert, config.LcpServiceClientKey = clientCert.certPath, clientCert.keyPath
+		require.NoError(t, query(t, config, addr))
+
+		// the client certificate is not signed by the CA of the server
+		otherClientCert := newTestCertificate(t, "relayer", otherCA)
+		config.LcpServiceClientCert, config.LcpServiceClientKey = otherClientCert.certPath, otherClientCert.keyPath
+		require.Error(t, query(t, config, addr))
+	})
+}
+
+func TestValidateLcpServiceTLS(t *testing.T) {
+	ca := newTestCertificate(t, "ca", nil)
+	clientCert := newTestCertificate(t, "relayer", ca)
+	missing := filepath.Join(t.TempDir(), "missing.crt")
+
+	cases := []struct {
+		name   string
+		config ProverConfig
+		err    string
+	}{
+		{"insecure", ProverConfig{}, ""},
+		{"system roots", ProverConfig{LcpServiceTlsEnabled: true}, ""},
+		{"mTLS", ProverConfig{LcpServiceTlsEnabled: true, LcpServiceTlsCaCert: ca.certPath, LcpServiceClientCert: clientCert.certPath, LcpServiceClientKey: clientCert.keyPath}, ""},
+		{"TLS options without TLS", ProverConfig{LcpServiceTlsCaCert: ca.certPath}, "LcpServiceTlsEnabled must be true"},
+		{"missing CA", ProverConfig{LcpServiceTlsEnabled: true, LcpServiceTlsCaCert: missing}, "path=" + missing},
+		{"not a certificate", ProverConfig{LcpServiceTlsEnabled: true, LcpServiceTlsCaCert: clientCert.keyPath}, "path=" + clientCert.keyPath},
+		{"client cert without key", ProverConfig{LcpServiceTlsEnabled: true, LcpServiceClientCert: clientCert.certPath}, "must be set together"},
+		{"missing client key", ProverConfig{LcpServiceTlsEnabled: true, LcpServiceClientCert: clientCert.certPath, LcpServiceClientKey: missing}, missing},
+		{"mismatched client key", ProverConfig{LcpServiceTlsEnabled: true, LcpServiceClientCert: clientCert.certPath, LcpServiceClientKey: ca.keyPath}, "private key does not match public key"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.validateLcpServiceTLS()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
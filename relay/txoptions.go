@@ -0,0 +1,82 @@
+package relay
+
+import (
+	"fmt"
+	"math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+const (
+	TxMsgTypeRegisterEnclaveKey = "register_enclave_key"
+	TxMsgTypeActivateClient     = "activate_client"
+)
+
+// txOptionsMsgTypes maps the labels of the msgs submitted by sendMsgs to the message types of TxOptions
+var txOptionsMsgTypes = map[string]string{
+	"register_enclave_key":                   TxMsgTypeRegisterEnclaveKey,
+	"register_enclave_key_and_update_client": TxMsgTypeRegisterEnclaveKey,
+	"activate_client":                        TxMsgTypeActivateClient,
+}
+
+// TxOptionsChain is implemented by the counterparty chains that can apply TxOptions to the txs of the msgs.
+// The chain may ignore the options it does not support, e.g. the priority hint on a chain without priority fees.
+type TxOptionsChain interface {
+	SendMsgsWithTxOptions(msgs []sdk.Msg, opts TxOptions) ([]core.MsgID, error)
+}
+
+func (o TxOptions) Validate() error {
+	switch o.MsgType {
+	case TxMsgTypeRegisterEnclaveKey, TxMsgTypeActivateClient:
+	default:
+		return fmt.Errorf("MsgType must be either %q or %q, but got %q", TxMsgTypeRegisterEnclaveKey, TxMsgTypeActivateClient, o.MsgType)
+	}
+	if math.IsNaN(o.FeeMultiplier) || math.IsInf(o.FeeMultiplier, 0) || o.FeeMultiplier < 0 {
+		return fmt.Errorf("FeeMultiplier must be a non-negative finite number, but got %v", o.FeeMultiplier)
+	}
+	return nil
+}
+
+func (pc ProverConfig) validateTxOptions() error {
+	seen := make(map[string]bool)
+	for i, o := range pc.TxOptions {
+		if err := o.Validate(); err != nil {
+			return fmt.Errorf("TxOptions[%v]: %w", i, err)
+		} else if seen[o.MsgType] {
+			return fmt.Errorf("TxOptions[%v]: duplicate options: msg_type=%q", i, o.MsgType)
+		}
+		seen[o.MsgType] = true
+	}
+	return nil
+}
+
+// FindTxOptions returns the options of the txs of `msgType` or nil if they are not configured
+func (pc ProverConfig) FindTxOptions(msgType string) *TxOptions {
+	for i, o := range pc.TxOptions {
+		if o.MsgType == msgType {
+			return &pc.TxOptions[i]
+		}
+	}
+	return nil
+}
+
+// sendMsgsWithTxOptions submits `msgs` with the tx options configured for `label`.
+// If the counterparty chain cannot apply them, the msgs are submitted with the default options of the chain.
+func (pr *Prover) sendMsgsWithTxOptions(counterparty core.Chain, label string, msgs []sdk.Msg) ([]core.MsgID, error) {
+	opts := pr.config.FindTxOptions(txOptionsMsgTypes[label])
+	if opts == nil {
+		return counterparty.SendMsgs(msgs)
+	}
+	chain := counterparty
+	// the options are implemented by the chain module rather than the provable chain wrapping it
+	if pc, ok := counterparty.(*core.ProvableChain); ok {
+		chain = pc.Chain
+	}
+	if c, ok := chain.(TxOptionsChain); ok {
+		pr.getLogger().Info("submit the msgs with the tx options", "label", label, "msg_type", opts.MsgType, "fee_multiplier", opts.FeeMultiplier, "priority", opts.Priority, "memo", opts.Memo)
+		return c.SendMsgsWithTxOptions(msgs, *opts)
+	}
+	pr.getLogger().Warn("the counterparty chain cannot apply the tx options, so the msgs are submitted with its default options", "chain_id", counterparty.ChainID(), "chain_type", fmt.Sprintf("%T", chain), "label", label, "msg_type", opts.MsgType)
+	return counterparty.SendMsgs(msgs)
+}
@@ -0,0 +1,126 @@
+package relay
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTxOptionsCounterparty is a counterparty chain recording the tx options applied to the msgs
+type mockTxOptionsCounterparty struct {
+	*mockCounterparty
+	applied []TxOptions
+}
+
+func (c *mockTxOptionsCounterparty) SendMsgsWithTxOptions(msgs []sdk.Msg, opts TxOptions) ([]core.MsgID, error) {
+	c.applied = append(c.applied, opts)
+	return c.mockCounterparty.SendMsgs(msgs)
+}
+
+func TestValidateTxOptions(t *testing.T) {
+	var cases = []struct {
+		name      string
+		txOptions []TxOptions
+		err       string
+	}{
+		{"empty", nil, ""},
+		{"valid", []TxOptions{{MsgType: TxMsgTypeRegisterEnclaveKey, FeeMultiplier: 1.5, Memo: "lcp"}, {MsgType: TxMsgTypeActivateClient, Priority: "high"}}, ""},
+		{"unknown msg type", []TxOptions{{MsgType: "update_client"}}, "TxOptions[0]: MsgType must be either"},
+		{"negative fee multiplier", []TxOptions{{MsgType: TxMsgTypeActivateClient, FeeMultiplier: -1}}, "TxOptions[0]: FeeMultiplier must be a non-negative finite number"},
+		{"duplicate", []TxOptions{{MsgType: TxMsgTypeActivateClient}, {MsgType: TxMsgTypeActivateClient}}, "TxOptions[1]: duplicate options"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ProverConfig{TxOptions: c.txOptions}.validateTxOptions()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, c.err)
+			}
+		})
+	}
+}
+
+func TestSendMsgsWithTxOptions(t *testing.T) {
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+	eki := loadTestEnclaveKeyInfo(t)
+
+	registerOptions := TxOptions{MsgType: TxMsgTypeRegisterEnclaveKey, FeeMultiplier: 1.5, Memo: "lcp: register enclave key"}
+	activateOptions := TxOptions{MsgType: TxMsgTypeActivateClient, Priority: "high", Memo: "lcp: activate client"}
+	newProver := func(t *testing.T) *Prover {
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.AllowedQuoteStatuses = []string{"GROUP_OUT_OF_DATE"}
+		pr.config.AllowedAdvisoryIds = []string{"INTEL-SA-00219", "INTEL-SA-00289", "INTEL-SA-00334", "INTEL-SA-00477", "INTEL-SA-00614", "INTEL-SA-00615", "INTEL-SA-00617", "INTEL-SA-00828"}
+		pr.config.AllowDebugEnclaveKeys = true
+		pr.config.Mrenclave = testMrenclave(t, eki)
+		pr.config.TxOptions = []TxOptions{registerOptions, activateOptions}
+		return pr
+	}
+	newCounterparty := func(pr *Prover) *mockCounterparty {
+		counterparty := newMockCounterparty(clienttypes.NewHeight(0, 1))
+		counterparty.clientState = &lcptypes.ClientState{
+			Mrenclave:            pr.config.GetMrenclave(),
+			KeyExpiration:        3600,
+			AllowedQuoteStatuses: pr.config.AllowedQuoteStatuses,
+			AllowedAdvisoryIds:   pr.config.AllowedAdvisoryIds,
+		}
+		return counterparty
+	}
+
+	t.Run("applied", func(t *testing.T) {
+		require := require.New(t)
+		pr := newProver(t)
+		counterparty := &mockTxOptionsCounterparty{mockCounterparty: newCounterparty(pr)}
+		// the options are applied by the chain module wrapped by the provable chain
+		provable := &core.ProvableChain{Chain: counterparty}
+
+		_, err := pr.registerEnclaveKey(provable, eki)
+		require.NoError(err)
+		_, err = pr.sendMsgs(provable, "activate_client", []sdk.Msg{&clienttypes.MsgUpdateClient{}})
+		require.NoError(err)
+		// no options are configured for the other msgs
+		_, err = pr.sendMsgs(provable, "update_operators", []sdk.Msg{&clienttypes.MsgUpdateClient{}})
+		require.NoError(err)
+
+		require.Equal([]TxOptions{registerOptions, activateOptions}, counterparty.applied)
+		require.Len(counterparty.sentMsgs, 3)
+	})
+
+	t.Run("bundled registration", func(t *testing.T) {
+		require := require.New(t)
+		pr := newProver(t)
+		counterparty := &mockTxOptionsCounterparty{mockCounterparty: newMockCounterparty(clienttypes.NewHeight(0, 1))}
+		_, err := pr.sendMsgs(counterparty, "register_enclave_key_and_update_client", []sdk.Msg{&clienttypes.MsgUpdateClient{}, &clienttypes.MsgUpdateClient{}})
+		require.NoError(err)
+		require.Equal([]TxOptions{registerOptions}, counterparty.applied)
+	})
+
+	t.Run("unsupported by the chain", func(t *testing.T) {
+		require := require.New(t)
+		pr := newProver(t)
+		counterparty := newCounterparty(pr)
+		_, err := pr.registerEnclaveKey(counterparty, eki)
+		require.NoError(err)
+		// the msgs are submitted with the default options
+		require.Equal(1, counterparty.sendMsgsCalls)
+	})
+
+	t.Run("rehearsal", func(t *testing.T) {
+		require := require.New(t)
+		pr := newProver(t)
+		pr.rehearsal = &rehearsal{outputDir: t.TempDir(), report: &RehearsalReport{}}
+		counterparty := &mockTxOptionsCounterparty{mockCounterparty: newMockCounterparty(clienttypes.NewHeight(0, 1))}
+		_, err := pr.sendMsgs(counterparty, "activate_client", []sdk.Msg{&clienttypes.MsgUpdateClient{}})
+		require.NoError(err)
+		require.Empty(counterparty.applied)
+		require.Zero(counterparty.sendMsgsCalls)
+	})
+}
@@ -0,0 +1,161 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+const (
+	DefaultTxRetryInterval = 1000 // milliseconds
+	// the interval is doubled on each retry, so the number of retries is bounded to keep the interval finite
+	MaxTxRetries = 16
+)
+
+// errEnclaveKeyRegistered is returned if a failed attempt of the enclave key registration has been applied on the counterparty chain
+var errEnclaveKeyRegistered = errors.New("the enclave key has been registered by a previous attempt")
+
+// EnclaveKeyRegistryChain is implemented by the counterparty chains that can query the enclave keys registered in the LCP client.
+// The registration of an enclave key is retried only on such chains because a failed attempt may have been applied.
+type EnclaveKeyRegistryChain interface {
+	// HasEnclaveKey returns true if `enclaveKey` is registered in the LCP client `clientID` at the height of `ctx`
+	HasEnclaveKey(ctx core.QueryContext, clientID string, enclaveKey common.Address) (bool, error)
+}
+
+// permanentTxErrorPattern matches the failures of the msgs that the LCP client rejects regardless of the retries
+var permanentTxErrorPattern = regexp.MustCompile(`(?i)mrenclave mismatch|operator mismatch|invalid operator`)
+
+// isPermanentTxError returns true if the failure of the msgs is not resolved by retrying them
+func isPermanentTxError(err error) bool {
+	return permanentTxErrorPattern.MatchString(err.Error())
+}
+
+func (p *TxRetryPolicy) GetRetryInterval() time.Duration {
+	if p.RetryInterval == 0 {
+		return DefaultTxRetryInterval * time.Millisecond
+	} else {
+		return time.Duration(p.RetryInterval) * time.Millisecond
+	}
+}
+
+// backoff returns the interval before the retry following `retries` retries
+func (p *TxRetryPolicy) backoff(retries uint32) time.Duration {
+	return p.GetRetryInterval() << retries
+}
+
+func (p *TxRetryPolicy) Validate() error {
+	if p.MaxRetries > MaxTxRetries {
+		return fmt.Errorf("MaxRetries must be less than or equal to %v, but got %v", MaxTxRetries, p.MaxRetries)
+	}
+	return nil
+}
+
+// sendMsgsWithRetry submits `msgs` and checks their results, and it retries them on a transient failure with TxRetryPolicy of the config.
+// Before each retry, `applied` is called to check if the previous attempt has been applied on the counterparty chain.
+// If it returns true, the msgs are not submitted again and the second return value is true.
+// If `applied` is nil, the msgs are not retried because it cannot be checked.
+func (pr *Prover) sendMsgsWithRetry(counterparty core.Chain, label string, msgs []sdk.Msg, applied func() (bool, error)) ([]core.MsgID, bool, error) {
+	policy := pr.config.TxRetryPolicy
+	var retries uint32
+	for {
+		ids, err := pr.sendMsgs(counterparty, label, msgs)
+		if err == nil && policy != nil {
+			err = pr.checkSentMsgsResults(counterparty, ids)
+		}
+		if err == nil {
+			return ids, false, nil
+		} else if policy == nil || retries >= policy.MaxRetries || isPermanentTxError(err) {
+			return nil, false, err
+		} else if applied == nil {
+			pr.getLogger().Warn("the msgs are not retried because it cannot be checked whether the failed attempt has been applied", "label", label, "error", err)
+			return nil, false, err
+		}
+		interval := policy.backoff(retries)
+		retries++
+		pr.getLogger().Warn("failed to submit the msgs, retry after the interval", "label", label, "retry", retries, "max_retries", policy.MaxRetries, "interval", interval, "error", err)
+		time.Sleep(interval)
+
+		if ok, qerr := applied(); qerr != nil {
+			return nil, false, fmt.Errorf("failed to check whether the failed attempt has been applied: %w: previous_error=%v", qerr, err)
+		} else if ok {
+			pr.getLogger().Info("the failed attempt has been applied, so the msgs are not submitted again", "label", label, "retry", retries)
+			return nil, true, nil
+		}
+	}
+}
+
+// checkSentMsgsResults returns an error if the execution of any of the submitted msgs failed.
+// A msg whose result is not available yet is left to the finality tracking.
+func (pr *Prover) checkSentMsgsResults(counterparty core.Chain, ids []core.MsgID) error {
+	if pr.IsRehearsal() {
+		return nil
+	}
+	for _, id := range ids {
+		res, err := counterparty.GetMsgResult(id)
+		if err != nil {
+			pr.getLogger().Info("the result of the submitted msg is not available yet", "msg_id", id.String(), "error", err)
+			continue
+		}
+		if ok, reason := res.Status(); !ok {
+			return fmt.Errorf("msg(id=%v) execution failed: %v", id, reason)
+		}
+	}
+	return nil
+}
+
+// enclaveKeyRegisteredFunc returns a function checking if `eki` is registered in the LCP client at the latest finalized height of the counterparty chain.
+// It returns nil if the counterparty chain cannot query the enclave keys.
+func (pr *Prover) enclaveKeyRegisteredFunc(counterparty core.Chain, eki *enclave.EnclaveKeyInfo) func() (bool, error) {
+	chain := counterparty
+	// the query is implemented by the chain module rather than the provable chain wrapping it
+	if pc, ok := counterparty.(*core.ProvableChain); ok {
+		chain = pc.Chain
+	}
+	registry, ok := chain.(EnclaveKeyRegistryChain)
+	if !ok {
+		return nil
+	}
+	finalityAware, ok := counterparty.(core.FinalityAware)
+	if !ok {
+		return nil
+	}
+	return func() (bool, error) {
+		header, err := finalityAware.GetLatestFinalizedHeader()
+		if err != nil {
+			return false, fmt.Errorf("failed to get the latest finalized header of the counterparty chain: %w", err)
+		}
+		return registry.HasEnclaveKey(core.NewQueryContext(context.TODO(), header.GetHeight()), counterparty.Path().ClientID, common.BytesToAddress(eki.EnclaveKeyAddress))
+	}
+}
+
+// clientUpdatedFunc returns a function checking if the LCP client on `counterparty` has been updated to `height` or higher
+func (pr *Prover) clientUpdatedFunc(counterparty core.FinalityAwareChain, height ibcexported.Height) func() (bool, error) {
+	return func() (bool, error) {
+		current, err := pr.queryCounterpartyClientHeight(context.TODO(), counterparty)
+		if err != nil {
+			return false, err
+		}
+		return current.GTE(height), nil
+	}
+}
+
+// saveAppliedRegistration saves `eki` as finalized because a failed attempt of the registration has been applied
+// and the key is registered at the latest finalized height of the counterparty chain
+func (pr *Prover) saveAppliedRegistration(ctx context.Context, eki *enclave.EnclaveKeyInfo) error {
+	pr.getLogger().Info("the enclave key has been registered by a failed attempt", "enclave_key", lcptypes.HexBytes(eki.EnclaveKeyAddress))
+	if err := pr.saveFinalizedEnclaveKeyInfo(ctx, eki); err != nil {
+		return err
+	}
+	pr.activeEnclaveKey, pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = eki, nil, clienttypes.Height{}
+	return nil
+}
@@ -0,0 +1,126 @@
+package relay
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRegistryCounterparty is a counterparty chain that can query the registered enclave keys
+type mockRegistryCounterparty struct {
+	*mockCounterparty
+	registered map[common.Address]bool
+}
+
+func (c *mockRegistryCounterparty) HasEnclaveKey(ctx core.QueryContext, clientID string, enclaveKey common.Address) (bool, error) {
+	return c.registered[enclaveKey], nil
+}
+
+// failNTimes returns a function for mockCounterparty.sendMsgsErr that fails `n` times with `err` before succeeding
+func failNTimes(n int, err error) func(msgs []sdk.Msg) error {
+	var calls int
+	return func(msgs []sdk.Msg) error {
+		calls++
+		if calls <= n {
+			return err
+		}
+		return nil
+	}
+}
+
+func TestSendRegisterEnclaveKeyMsgWithRetry(t *testing.T) {
+	errSequence := fmt.Errorf("account sequence mismatch, expected 10, got 9: incorrect account sequence")
+	errMrenclave := fmt.Errorf("failed to execute message; message index: 0: invalid AVR: mrenclave mismatch: expected=0x01 actual=0x02: invalid header")
+
+	var cases = []struct {
+		name        string
+		policy      *TxRetryPolicy
+		failures    int
+		err         error
+		registry    bool
+		registered  bool
+		calls       int
+		expectedErr string
+	}{
+		{"no policy", nil, 1, errSequence, true, false, 1, "incorrect account sequence"},
+		{"succeed after retries", &TxRetryPolicy{MaxRetries: 3, RetryInterval: 1}, 2, errSequence, true, false, 3, ""},
+		{"retries exhausted", &TxRetryPolicy{MaxRetries: 2, RetryInterval: 1}, 3, errSequence, true, false, 3, "incorrect account sequence"},
+		{"permanent failure", &TxRetryPolicy{MaxRetries: 3, RetryInterval: 1}, 1, errMrenclave, true, false, 1, "mrenclave mismatch"},
+		{"registry unsupported", &TxRetryPolicy{MaxRetries: 3, RetryInterval: 1}, 1, errSequence, false, false, 1, "incorrect account sequence"},
+		{"registered by the failed attempt", &TxRetryPolicy{MaxRetries: 3, RetryInterval: 1}, 1, errSequence, true, true, 1, errEnclaveKeyRegistered.Error()},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			pr := newTestProver(t)
+			pr.config.TxRetryPolicy = c.policy
+			cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+			cp.sendMsgsErr = failNTimes(c.failures, c.err)
+
+			eki := &enclave.EnclaveKeyInfo{EnclaveKeyAddress: common.HexToAddress("0x01").Bytes()}
+			registry := &mockRegistryCounterparty{mockCounterparty: cp, registered: map[common.Address]bool{}}
+			if c.registered {
+				registry.registered[common.BytesToAddress(eki.EnclaveKeyAddress)] = true
+			}
+			var counterparty core.Chain = cp
+			if c.registry {
+				counterparty = registry
+			}
+			registerMsg, err := clienttypes.NewMsgUpdateClient("lcp-client-0", &lcptypes.RegisterEnclaveKeyMessage{Report: []byte("report")}, "signer")
+			require.NoError(err)
+
+			msgID, err := pr.sendRegisterEnclaveKeyMsg(counterparty, eki, registerMsg)
+			if c.expectedErr == "" {
+				require.NoError(err)
+				require.NotNil(msgID)
+				require.Len(cp.sentMsgs, 1)
+			} else {
+				require.ErrorContains(err, c.expectedErr)
+				require.Nil(msgID)
+			}
+			require.Equal(c.calls, cp.sendMsgsCalls)
+		})
+	}
+}
+
+func TestSendMsgsWithRetryExecutionFailure(t *testing.T) {
+	require := require.New(t)
+	pr := newTestProver(t)
+	pr.config.TxRetryPolicy = &TxRetryPolicy{MaxRetries: 3, RetryInterval: 1}
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 10))
+	// the first tx is included, but its execution failed
+	failedID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	cp.msgResults[failedID.String()] = mockMsgResult{height: clienttypes.NewHeight(0, 10), success: false}
+	var applied int
+	ids, ok, err := pr.sendMsgsWithRetry(cp, "activate_client", []sdk.Msg{&clienttypes.MsgUpdateClient{}}, func() (bool, error) {
+		applied++
+		// the result is available only for the first tx
+		delete(cp.msgResults, failedID.String())
+		return false, nil
+	})
+	require.NoError(err)
+	require.False(ok)
+	require.Len(ids, 1)
+	require.Equal(2, cp.sendMsgsCalls)
+	require.Equal(1, applied)
+}
+
+func TestTxRetryPolicyBackoff(t *testing.T) {
+	require := require.New(t)
+	p := &TxRetryPolicy{RetryInterval: 100}
+	require.Equal(100*time.Millisecond, p.backoff(0))
+	require.Equal(200*time.Millisecond, p.backoff(1))
+	require.Equal(400*time.Millisecond, p.backoff(2))
+	require.Equal(DefaultTxRetryInterval*time.Millisecond, (&TxRetryPolicy{}).backoff(0))
+	require.NoError((&TxRetryPolicy{MaxRetries: MaxTxRetries}).Validate())
+	require.Error((&TxRetryPolicy{MaxRetries: MaxTxRetries + 1}).Validate())
+}
@@ -0,0 +1,60 @@
+package relay
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+)
+
+// ErrUnsupportedMessageVersion is returned if the counterparty LCP client does not accept the version of a message returned by the LCP service
+var ErrUnsupportedMessageVersion = errors.New("unsupported proxy message version")
+
+// checkMessageVersion records the version of the message returned by the LCP service,
+// and returns an error if the counterparty LCP client does not accept the version.
+// A message with an unsupported version would be rejected by the counterparty chain, so it is not submitted.
+func (pr *Prover) checkMessageVersion(msg *lcptypes.HeaderedProxyMessage) error {
+	if prev := pr.observedMessageVersion; prev != 0 && prev != msg.Version {
+		pr.getLogger().Warn(
+			"the proxy message version of the LCP service has changed",
+			"prev_version", prev,
+			"version", msg.Version,
+			"guidance", "upgrade the counterparty LCP client to accept the new version",
+		)
+	}
+	pr.observedMessageVersion = msg.Version
+
+	if versions := pr.getCounterpartyMessageVersions(); !slices.Contains(versions, msg.Version) {
+		pr.getLogger().Error(
+			"the counterparty LCP client does not accept the proxy message version of the LCP service", ErrUnsupportedMessageVersion,
+			"version", msg.Version,
+			"counterparty_versions", versions,
+			"guidance", "upgrade the counterparty LCP client to accept the version or downgrade the LCP service",
+		)
+		return fmt.Errorf("%w: counterparty_versions=%v version=%v", ErrUnsupportedMessageVersion, versions, msg.Version)
+	}
+	return nil
+}
+
+// getCounterpartyMessageVersions returns the proxy message versions that the counterparty LCP client accepts.
+// Until the client state of the counterparty is queried, the versions of the config are assumed.
+func (pr *Prover) getCounterpartyMessageVersions() []uint16 {
+	if pr.counterpartyMessageVersions != nil {
+		return pr.counterpartyMessageVersions
+	}
+	return pr.config.GetCounterpartyMessageVersions()
+}
+
+// observeCounterpartyMessageVersions records the proxy message versions that the counterparty LCP client accepts
+func (pr *Prover) observeCounterpartyMessageVersions(clientState *lcptypes.ClientState) {
+	versions := clientState.AcceptedMessageVersions()
+	if configured := pr.config.GetCounterpartyMessageVersions(); !slices.Equal(configured, versions) {
+		pr.getLogger().Warn(
+			"the proxy message versions of the config differ from the ones that the counterparty LCP client accepts",
+			"config_versions", configured,
+			"counterparty_versions", versions,
+		)
+	}
+	pr.counterpartyMessageVersions = versions
+}
@@ -0,0 +1,59 @@
+package relay
+
+import (
+	"testing"
+
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckMessageVersion(t *testing.T) {
+	v1 := &lcptypes.HeaderedProxyMessage{Version: 1, Type: lcptypes.LCPMessageTypeUpdateState}
+	v2 := &lcptypes.HeaderedProxyMessage{Version: 2, Type: lcptypes.LCPMessageTypeUpdateState}
+
+	var cases = []struct {
+		name                 string
+		counterpartyVersions []uint32
+		v1Passes             bool
+		v2Passes             bool
+	}{
+		{"default", nil, true, false},
+		{"v1 and v2", []uint32{1, 2}, true, true},
+		{"v2 only", []uint32{2}, false, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			pr := newTestProver(t)
+			pr.config.CounterpartyMessageVersions = c.counterpartyVersions
+			for _, m := range []struct {
+				msg    *lcptypes.HeaderedProxyMessage
+				passes bool
+			}{{v1, c.v1Passes}, {v2, c.v2Passes}} {
+				err := pr.checkMessageVersion(m.msg)
+				if m.passes {
+					require.NoError(t, err)
+				} else {
+					require.ErrorIs(t, err, ErrUnsupportedMessageVersion)
+				}
+				// the version is recorded even if it is not accepted by the counterparty
+				require.Equal(t, m.msg.Version, pr.observedMessageVersion)
+			}
+		})
+	}
+}
+
+func TestObserveCounterpartyMessageVersions(t *testing.T) {
+	v2 := &lcptypes.HeaderedProxyMessage{Version: 2, Type: lcptypes.LCPMessageTypeUpdateState}
+
+	pr := newTestProver(t)
+	pr.config.CounterpartyMessageVersions = []uint32{1, 2}
+	require.NoError(t, pr.checkMessageVersion(v2))
+
+	// the versions accepted by the counterparty LCP client take precedence over the config
+	pr.observeCounterpartyMessageVersions(&lcptypes.ClientState{})
+	require.Equal(t, []uint16{lcptypes.LCPMessageVersion}, pr.getCounterpartyMessageVersions())
+	require.ErrorIs(t, pr.checkMessageVersion(v2), ErrUnsupportedMessageVersion)
+
+	pr.observeCounterpartyMessageVersions(&lcptypes.ClientState{AllowedMessageVersions: []uint32{2}})
+	require.NoError(t, pr.checkMessageVersion(v2))
+}
@@ -0,0 +1,148 @@
+package relay
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// UnfinalizedKeyStatus is the status of the registration of the active enclave key that is not finalized yet
+type UnfinalizedKeyStatus struct {
+	MsgID          string             `json:"msg_id"`
+	IncludedHeight clienttypes.Height `json:"included_height"`
+	// the timestamp of the block including the registration
+	IncludedAt time.Time `json:"included_at"`
+	// the time elapsed since IncludedAt when the registration was checked last
+	Pending time.Duration `json:"pending"`
+	// the registration is stuck if Pending exceeds this
+	Deadline time.Duration `json:"deadline"`
+	Stuck    bool          `json:"stuck"`
+}
+
+// unfinalizedKeyWatchdogState is the state of the watchdog of the unfinalized registration
+type unfinalizedKeyWatchdogState struct {
+	mu sync.Mutex
+	// nil if no unfinalized registration is watched
+	status *UnfinalizedKeyStatus
+	// registers the gauge of the pending duration once
+	registerOnce sync.Once
+}
+
+func (s *unfinalizedKeyWatchdogState) get() (UnfinalizedKeyStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status == nil {
+		return UnfinalizedKeyStatus{}, false
+	}
+	return *s.status, true
+}
+
+func (s *unfinalizedKeyWatchdogState) set(status UnfinalizedKeyStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = &status
+}
+
+func (s *unfinalizedKeyWatchdogState) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = nil
+}
+
+// expectedFinalizationTime returns the expected time from the inclusion of a registration to its finality.
+// It returns zero if the time is unknown, i.e. ExpectedFinalizationTime is not set and the tracker is not "confirmations".
+func (pr *Prover) expectedFinalizationTime(counterparty core.Chain) time.Duration {
+	if t := pr.config.ExpectedFinalizationTime; t != 0 {
+		return time.Duration(t) * time.Second
+	} else if pr.config.GetFinalityTracker() != FinalityTrackerConfirmations {
+		return 0
+	}
+	blocks := max(pr.config.FinalityConfirmations, pr.config.ConfirmationsRequired)
+	return time.Duration(blocks) * counterparty.AverageBlockTime()
+}
+
+// watchUnfinalizedKey checks how long the active registration, which is executed successfully, has been unfinalized since the block including it.
+// If it exceeds the expected finalization time times UnfinalizedKeyWatchdogMultiplier, the registration is stuck, e.g. the counterparty chain halts:
+// it is warned and alerted, and the health of the prover is degraded until the registration is finalized or dropped.
+// If FinalizeStuckUnfinalizedKey is set, the stuck registration is saved as finalized.
+// The failures to check the registration are only logged because the watchdog is diagnostic.
+func (pr *Prover) watchUnfinalizedKey(ctx context.Context, counterparty core.Chain, now time.Time) error {
+	pr.unfinalizedKeyWatchdog.registerOnce.Do(pr.registerUnfinalizedKeyGauge)
+	expected := pr.expectedFinalizationTime(counterparty)
+	if expected == 0 || pr.unfinalizedMsgHeight.IsZero() {
+		return nil
+	}
+	msgID := pr.unfinalizedMsgID.String()
+	status, ok := pr.unfinalizedKeyWatchdog.get()
+	if !ok || status.MsgID != msgID || status.IncludedHeight != pr.unfinalizedMsgHeight {
+		includedAt, err := counterparty.Timestamp(pr.unfinalizedMsgHeight)
+		if err != nil {
+			pr.getLogger().Warn("failed to get the timestamp of the block including the registration", "msg_id", msgID, "included_height", pr.unfinalizedMsgHeight, "error", err)
+			return nil
+		}
+		status = UnfinalizedKeyStatus{MsgID: msgID, IncludedHeight: pr.unfinalizedMsgHeight, IncludedAt: includedAt}
+	}
+	status.Deadline = expected * time.Duration(pr.config.GetUnfinalizedKeyWatchdogMultiplier())
+	// the timestamp of the block is compared with the clock of the counterparty chain
+	status.Pending = pr.correctClockSkew(now).Sub(status.IncludedAt)
+	status.Stuck = status.Pending > status.Deadline
+	pr.unfinalizedKeyWatchdog.set(status)
+	if !status.Stuck {
+		return nil
+	}
+
+	pr.getLogger().Warn("the enclave key registration has been unfinalized longer than expected", "msg_id", msgID, "included_height", status.IncludedHeight, "pending", status.Pending, "deadline", status.Deadline)
+	pr.alert(AlertUnfinalizedKeyStuck, msgID, "the enclave key registration has been unfinalized longer than expected",
+		"enclave_key", lcptypes.HexBytes(pr.activeEnclaveKey.EnclaveKeyAddress),
+		"included_height", status.IncludedHeight,
+		"included_at", status.IncludedAt.UTC(),
+		"pending", status.Pending,
+		"deadline", status.Deadline,
+	)
+	if !pr.config.FinalizeStuckUnfinalizedKey {
+		return nil
+	}
+	pr.getLogger().Warn("save the stuck registration as finalized", "enclave_key", lcptypes.HexBytes(pr.activeEnclaveKey.EnclaveKeyAddress), "msg_id", msgID)
+	if err := pr.finalizeEnclaveKeyInfo(ctx, pr.activeEnclaveKey); err != nil {
+		return err
+	}
+	pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = nil, clienttypes.Height{}
+	pr.unfinalizedKeyWatchdog.clear()
+	return nil
+}
+
+// registerUnfinalizedKeyGauge exports the pending duration of the unfinalized registration as a gauge with the metrics sink of the prover
+func (pr *Prover) registerUnfinalizedKeyGauge() {
+	meter := pr.meter()
+	gauge, err := meter.Float64ObservableGauge(
+		"lcp.unfinalized_key.pending",
+		metric.WithUnit("s"),
+		metric.WithDescription("time elapsed since the block including the registration of the active enclave key that is not finalized yet"),
+	)
+	if err != nil {
+		pr.getLogger().Warn("failed to create the gauge of the unfinalized registration", "error", err)
+		return
+	}
+	var chainID string
+	if pr.originChain != nil {
+		chainID = pr.originChain.ChainID()
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		if status, ok := pr.unfinalizedKeyWatchdog.get(); ok {
+			o.ObserveFloat64(gauge, status.Pending.Seconds(), metric.WithAttributes(
+				attribute.String("chain_id", chainID),
+				attribute.Bool("stuck", status.Stuck),
+			))
+		}
+		return nil
+	}, gauge)
+	if err != nil {
+		pr.getLogger().Warn("failed to register the callback of the unfinalized registration", "error", err)
+	}
+}
@@ -0,0 +1,143 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchUnfinalizedKey(t *testing.T) {
+	msgID := &tendermint.MsgID{TxHash: "0x01", MsgIndex: 0}
+	includedHeight := clienttypes.NewHeight(0, 10)
+	now := time.Now()
+	// the counterparty chain halts after the registration is included, so it is never finalized
+	setup := func(t *testing.T, includedAt time.Time) (*Prover, *mockCounterparty, func() [][]byte) {
+		srv, payloads := newAlertCaptureServer(t, http.StatusOK)
+		pr := newTestProver(t)
+		pr.codec = newTestCodec()
+		pr.homePath = t.TempDir()
+		pr.originChain = &mockCounterparty{chainID: "origin"}
+		pr.config.AlertWebhookUrl = srv.URL
+		alerter, err := newProverAlerter(pr.config)
+		require.NoError(t, err)
+		pr.alerter = alerter
+		require.NoError(t, os.MkdirAll(pr.dbPath(), os.ModePerm))
+		pr.config.ExpectedFinalizationTime = 60
+
+		eki := &enclave.EnclaveKeyInfo{EnclaveKeyAddress: []byte{0x01}, AttestationTime: uint64(now.Unix())}
+		require.NoError(t, pr.saveUnfinalizedEnclaveKeyInfo(context.TODO(), eki, msgID, includedHeight))
+		pr.activeEnclaveKey, pr.unfinalizedMsgID, pr.unfinalizedMsgHeight = eki, msgID, includedHeight
+
+		cp := newMockCounterparty(clienttypes.NewHeight(0, 9))
+		cp.latestHeight = includedHeight
+		cp.msgResults[msgID.String()] = mockMsgResult{height: includedHeight, success: true}
+		cp.blockTimes = map[uint64]time.Time{includedHeight.RevisionHeight: includedAt}
+		return pr, cp, payloads
+	}
+
+	t.Run("stuck", func(t *testing.T) {
+		require := require.New(t)
+		pr, cp, payloads := setup(t, now.Add(-5*time.Minute))
+
+		updateNeeded, err := pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+		require.NoError(err)
+		require.False(updateNeeded)
+		status, ok := pr.unfinalizedKeyWatchdog.get()
+		require.True(ok)
+		require.Equal(msgID.String(), status.MsgID)
+		require.Equal(10*time.Minute, status.Deadline)
+		require.GreaterOrEqual(status.Pending, 5*time.Minute)
+		require.False(status.Stuck)
+		require.Equal(ProverHealth{Status: HealthStatusOK}, pr.health())
+
+		// the registration is still unfinalized after the deadline
+		require.NoError(pr.watchUnfinalizedKey(context.TODO(), cp, now.Add(10*time.Minute)))
+		status, ok = pr.unfinalizedKeyWatchdog.get()
+		require.True(ok)
+		require.True(status.Stuck)
+		require.NotNil(pr.unfinalizedMsgID)
+		health := pr.health()
+		require.Equal(HealthStatusDegraded, health.Status)
+		require.Len(health.Reasons, 1)
+		require.Contains(health.Reasons[0], msgID.String())
+
+		pr.alerter.wait()
+		require.Len(payloads(), 1)
+		var alert Alert
+		require.NoError(json.Unmarshal(payloads()[0], &alert))
+		require.Equal(AlertUnfinalizedKeyStuck, alert.Condition)
+		require.Equal(msgID.String(), alert.Subject)
+		require.Equal("10m0s", alert.Details["deadline"])
+
+		// the watchdog is reset once the registration is finalized
+		cp.finalizedHeight = includedHeight
+		pr.counterpartyFinalizedHeaderCache.invalidate()
+		updateNeeded, err = pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+		require.NoError(err)
+		require.False(updateNeeded)
+		require.Nil(pr.unfinalizedMsgID)
+		_, ok = pr.unfinalizedKeyWatchdog.get()
+		require.False(ok)
+		require.Equal(HealthStatusOK, pr.health().Status)
+	})
+
+	t.Run("finalize stuck key", func(t *testing.T) {
+		require := require.New(t)
+		pr, cp, _ := setup(t, now.Add(-20*time.Minute))
+		pr.config.FinalizeStuckUnfinalizedKey = true
+
+		updateNeeded, err := pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+		require.NoError(err)
+		require.False(updateNeeded)
+		require.Nil(pr.unfinalizedMsgID)
+		require.Equal(HealthStatusOK, pr.health().Status)
+		finalized, err := pr.loadLastFinalizedEnclaveKey(context.TODO())
+		require.NoError(err)
+		require.Equal([]byte{0x01}, finalized.EnclaveKeyAddress)
+		_, _, _, err = pr.loadLastUnfinalizedEnclaveKey(context.TODO())
+		require.ErrorIs(err, ErrEnclaveKeyInfoNotFound)
+
+		// the finality of the registration is no longer tracked
+		calls := cp.getMsgResultCalls
+		_, err = pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+		require.NoError(err)
+		require.Equal(calls, cp.getMsgResultCalls)
+	})
+
+	t.Run("unknown finalization time", func(t *testing.T) {
+		require := require.New(t)
+		pr, cp, _ := setup(t, now.Add(-20*time.Minute))
+		pr.config.ExpectedFinalizationTime = 0
+
+		_, err := pr.loadEKIAndCheckUpdateNeeded(context.TODO(), cp)
+		require.NoError(err)
+		_, ok := pr.unfinalizedKeyWatchdog.get()
+		require.False(ok)
+		require.NotNil(pr.unfinalizedMsgID)
+	})
+}
+
+func TestExpectedFinalizationTime(t *testing.T) {
+	require := require.New(t)
+	cp := newMockCounterparty(clienttypes.NewHeight(0, 1))
+	cp.averageBlockTime = 6 * time.Second
+	pr := newTestProver(t)
+	require.Zero(pr.expectedFinalizationTime(cp))
+
+	pr.config.FinalityTracker = FinalityTrackerConfirmations
+	pr.config.FinalityConfirmations = 5
+	require.Equal(30*time.Second, pr.expectedFinalizationTime(cp))
+	pr.config.ConfirmationsRequired = 8
+	require.Equal(48*time.Second, pr.expectedFinalizationTime(cp))
+
+	pr.config.ExpectedFinalizationTime = 900
+	require.Equal(15*time.Minute, pr.expectedFinalizationTime(cp))
+}
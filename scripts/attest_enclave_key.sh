@@ -0,0 +1,16 @@
+#!/bin/sh
+set -ex
+
+# Generates a new enclave key and attests it without removing the existing keys
+
+LCP_BIN=./bin/lcp
+ENCLAVE_PATH=./bin/enclave.signed.so
+CERTS_DIR=./lcp/tests/certs
+
+enclave_key=$(${LCP_BIN} --log_level=off enclave generate-key --enclave=${ENCLAVE_PATH})
+
+if [ -z "$SGX_MODE" ] || [ "$SGX_MODE" = "HW" ]; then
+    ${LCP_BIN} attestation ias --enclave=${ENCLAVE_PATH} --enclave_key=${enclave_key}
+else
+    ${LCP_BIN} attestation simulate --enclave=${ENCLAVE_PATH} --enclave_key=${enclave_key} --signing_cert_path=${CERTS_DIR}/signing.crt.der --signing_key=${CERTS_DIR}/signing.key
+fi
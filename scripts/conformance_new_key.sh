@@ -0,0 +1,16 @@
+#!/bin/sh
+set -ex
+
+# Usage: LCP_PID_FILE=<path> conformance_new_key.sh
+# Restarts the LCP service with a new enclave key. It is called by the conformance suite to rotate the key.
+
+LCP_BIN=./bin/lcp
+ENCLAVE_PATH=./bin/enclave.signed.so
+
+kill $(cat ${LCP_PID_FILE}) && while kill -0 $(cat ${LCP_PID_FILE}) 2>/dev/null; do sleep 1; done
+
+./scripts/attest_enclave_key.sh
+
+${LCP_BIN} --log_level=info service start --enclave=${ENCLAVE_PATH} --address=127.0.0.1:50051 --threads=2 &
+echo $! > ${LCP_PID_FILE}
+sleep 3
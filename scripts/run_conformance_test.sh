@@ -0,0 +1,52 @@
+#!/bin/sh
+set -ex
+
+# Usage: run_conformance_test.sh
+# Runs the conformance suite against a local LCP service and the origin chain `ibc0`.
+# The results of the steps are written to ./conformance-report.json.
+
+export LCP_ENCLAVE_DEBUG=1
+
+LCP_BIN=./bin/lcp
+ENCLAVE_PATH=./bin/enclave.signed.so
+CERTS_DIR=./lcp/tests/certs
+LCP_PID_FILE=$(mktemp)
+
+./scripts/init_lcp.sh
+
+if [ "$SGX_MODE" = "SW" ]; then
+    export LCP_RA_ROOT_CERT_HEX=$(cat ${CERTS_DIR}/root.crt | xxd -p -c 1000000)
+fi
+
+start_lcp() {
+    ${LCP_BIN} --log_level=info service start --enclave=${ENCLAVE_PATH} --address=127.0.0.1:50051 --threads=2 &
+    echo $! > ${LCP_PID_FILE}
+}
+
+stop_lcp() {
+    kill $(cat ${LCP_PID_FILE}) && while kill -0 $(cat ${LCP_PID_FILE}) 2>/dev/null; do sleep 1; done
+}
+
+start_lcp
+
+make -C tests/e2e/cases/tm2tm network
+sleep 3
+
+# the suite runs in the package directory, so the hook to attest a new key moves to the repository root.
+# the LCP service is stopped while the key is attested because they share the same home directory.
+export LCP_CONFORMANCE_SERVICE_ADDRESS=localhost:50051
+export LCP_CONFORMANCE_MRENCLAVE=$(${LCP_BIN} enclave metadata --enclave=${ENCLAVE_PATH} | jq -r .mrenclave)
+export LCP_CONFORMANCE_ORIGIN_CHAIN_ID=ibc0
+export LCP_CONFORMANCE_ORIGIN_RPC_ADDRESS=http://localhost:26657
+export LCP_CONFORMANCE_NEW_KEY_COMMAND="cd $(pwd) && LCP_PID_FILE=${LCP_PID_FILE} ./scripts/conformance_new_key.sh"
+export LCP_CONFORMANCE_REPORT=$(pwd)/conformance-report.json
+
+set +e
+(cd simapp && go test -tags conformance -count=1 -v ./conformance/...)
+RESULT=$?
+set -e
+
+make -C tests/e2e/cases/tm2tm network-down
+stop_lcp
+rm -f ${LCP_PID_FILE}
+exit ${RESULT}
@@ -0,0 +1,287 @@
+package dcap
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// TCBInfoVersion is the version of the TCB info which is supported
+	TCBInfoVersion = 3
+	// QEIdentityVersion is the version of the QE identity which is supported
+	QEIdentityVersion = 2
+)
+
+// TCBInfo is the TCB info of a platform's FMSPC issued by Intel PCS
+type TCBInfo struct {
+	ID                      string     `json:"id"`
+	Version                 int        `json:"version"`
+	IssueDate               time.Time  `json:"issueDate"`
+	NextUpdate              time.Time  `json:"nextUpdate"`
+	FMSPC                   HexBytes   `json:"fmspc"`
+	PCEID                   HexBytes   `json:"pceId"`
+	TCBType                 int        `json:"tcbType"`
+	TCBEvaluationDataNumber uint32     `json:"tcbEvaluationDataNumber"`
+	TCBLevels               []TCBLevel `json:"tcbLevels"`
+}
+
+// TCBLevel is a TCB level of the platforms
+type TCBLevel struct {
+	TCB struct {
+		SGXTCBComponents []struct {
+			SVN uint8 `json:"svn"`
+		} `json:"sgxtcbcomponents"`
+		PCESVN uint16 `json:"pcesvn"`
+	} `json:"tcb"`
+	TCBDate     time.Time `json:"tcbDate"`
+	TCBStatus   TCBStatus `json:"tcbStatus"`
+	AdvisoryIDs []string  `json:"advisoryIDs"`
+}
+
+// EnclaveIdentity is the identity of the quoting enclave issued by Intel PCS
+type EnclaveIdentity struct {
+	ID                      string       `json:"id"`
+	Version                 int          `json:"version"`
+	IssueDate               time.Time    `json:"issueDate"`
+	NextUpdate              time.Time    `json:"nextUpdate"`
+	TCBEvaluationDataNumber uint32       `json:"tcbEvaluationDataNumber"`
+	MiscSelect              HexBytes     `json:"miscselect"`
+	MiscSelectMask          HexBytes     `json:"miscselectMask"`
+	Attributes              HexBytes     `json:"attributes"`
+	AttributesMask          HexBytes     `json:"attributesMask"`
+	MRSIGNER                HexBytes     `json:"mrsigner"`
+	ISVProdID               uint16       `json:"isvprodid"`
+	TCBLevels               []QETCBLevel `json:"tcbLevels"`
+}
+
+// QETCBLevel is a TCB level of the quoting enclave
+type QETCBLevel struct {
+	TCB struct {
+		ISVSVN uint16 `json:"isvsvn"`
+	} `json:"tcb"`
+	TCBDate     time.Time `json:"tcbDate"`
+	TCBStatus   TCBStatus `json:"tcbStatus"`
+	AdvisoryIDs []string  `json:"advisoryIDs"`
+}
+
+// HexBytes is a byte slice encoded as a hex string in the collateral
+type HexBytes []byte
+
+func (bz HexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToUpper(hex.EncodeToString(bz)))
+}
+
+func (bz *HexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*bz = b
+	return nil
+}
+
+// ParseTCBInfo verifies the signature of the TCB info `body` with `issuerChain` chaining to one of `roots` at `now`, and returns the TCB info
+func ParseTCBInfo(body, issuerChain []byte, roots *x509.CertPool, now time.Time) (*TCBInfo, error) {
+	var signed struct {
+		TCBInfo   json.RawMessage `json:"tcbInfo"`
+		Signature HexBytes        `json:"signature"`
+	}
+	if err := json.Unmarshal(body, &signed); err != nil {
+		return nil, fmt.Errorf("invalid TCB info: %w", err)
+	}
+	if err := verifyCollateralSignature(signed.TCBInfo, signed.Signature, issuerChain, roots, now); err != nil {
+		return nil, fmt.Errorf("failed to verify the TCB info: %w", err)
+	}
+	info, err := UnsafeDecodeTCBInfo(body)
+	if err != nil {
+		return nil, err
+	}
+	if info.ID != "SGX" || info.Version != TCBInfoVersion {
+		return nil, fmt.Errorf("unsupported TCB info: id=%v version=%v", info.ID, info.Version)
+	}
+	if err := checkValidity(info.IssueDate, info.NextUpdate, now); err != nil {
+		return nil, fmt.Errorf("invalid TCB info: %w", err)
+	}
+	for i, level := range info.TCBLevels {
+		if l := len(level.TCB.SGXTCBComponents); l != TCBComponentsLen {
+			return nil, fmt.Errorf("invalid TCB level: index=%v components=%v", i, l)
+		}
+	}
+	return info, nil
+}
+
+// UnsafeDecodeTCBInfo decodes the TCB info `body` without verifying its signature and validity
+func UnsafeDecodeTCBInfo(body []byte) (*TCBInfo, error) {
+	var signed struct {
+		TCBInfo TCBInfo `json:"tcbInfo"`
+	}
+	if err := json.Unmarshal(body, &signed); err != nil {
+		return nil, fmt.Errorf("invalid TCB info: %w", err)
+	}
+	return &signed.TCBInfo, nil
+}
+
+// ParseQEIdentity verifies the signature of the QE identity `body` with `issuerChain` chaining to one of `roots` at `now`, and returns the QE identity
+func ParseQEIdentity(body, issuerChain []byte, roots *x509.CertPool, now time.Time) (*EnclaveIdentity, error) {
+	var signed struct {
+		EnclaveIdentity json.RawMessage `json:"enclaveIdentity"`
+		Signature       HexBytes        `json:"signature"`
+	}
+	if err := json.Unmarshal(body, &signed); err != nil {
+		return nil, fmt.Errorf("invalid QE identity: %w", err)
+	}
+	if err := verifyCollateralSignature(signed.EnclaveIdentity, signed.Signature, issuerChain, roots, now); err != nil {
+		return nil, fmt.Errorf("failed to verify the QE identity: %w", err)
+	}
+	var identity EnclaveIdentity
+	if err := json.Unmarshal(signed.EnclaveIdentity, &identity); err != nil {
+		return nil, fmt.Errorf("invalid QE identity: %w", err)
+	}
+	if identity.ID != "QE" || identity.Version != QEIdentityVersion {
+		return nil, fmt.Errorf("unsupported QE identity: id=%v version=%v", identity.ID, identity.Version)
+	}
+	if err := checkValidity(identity.IssueDate, identity.NextUpdate, now); err != nil {
+		return nil, fmt.Errorf("invalid QE identity: %w", err)
+	}
+	if len(identity.MiscSelect) != 4 || len(identity.MiscSelectMask) != 4 || len(identity.Attributes) != 16 || len(identity.AttributesMask) != 16 || len(identity.MRSIGNER) != 32 {
+		return nil, fmt.Errorf("invalid QE identity: unexpected lengths of the fields")
+	}
+	return &identity, nil
+}
+
+// match returns an error if the QE report `report` does not match the identity
+func (identity *EnclaveIdentity) match(report ReportBody) error {
+	if !maskedEqual(report.Raw[16:20], identity.MiscSelect, identity.MiscSelectMask) {
+		return fmt.Errorf("MISCSELECT mismatch")
+	}
+	if !maskedEqual(report.Raw[48:64], identity.Attributes, identity.AttributesMask) {
+		return fmt.Errorf("attributes mismatch")
+	}
+	if !bytes.Equal(report.MRSIGNER[:], identity.MRSIGNER) {
+		return fmt.Errorf("MRSIGNER mismatch: expected=%x actual=%x", []byte(identity.MRSIGNER), report.MRSIGNER[:])
+	}
+	if report.ISVProdID != identity.ISVProdID {
+		return fmt.Errorf("ISVPRODID mismatch: expected=%v actual=%v", identity.ISVProdID, report.ISVProdID)
+	}
+	return nil
+}
+
+// tcbLevel returns the highest TCB level of the quoting enclave that `isvsvn` satisfies
+func (identity *EnclaveIdentity) tcbLevel(isvsvn uint16) (*QETCBLevel, error) {
+	for i := range identity.TCBLevels {
+		if level := &identity.TCBLevels[i]; isvsvn >= level.TCB.ISVSVN {
+			return level, nil
+		}
+	}
+	return nil, fmt.Errorf("no TCB level of the quoting enclave matches: isvsvn=%v", isvsvn)
+}
+
+// tcbLevel returns the highest TCB level of the platform that the TCB of the PCK certificate satisfies
+func (info *TCBInfo) tcbLevel(exts *PCKExtensions) (*TCBLevel, error) {
+	for i := range info.TCBLevels {
+		level := &info.TCBLevels[i]
+		matched := exts.PCESVN >= level.TCB.PCESVN
+		for j, c := range level.TCB.SGXTCBComponents {
+			matched = matched && exts.TCBComponents[j] >= c.SVN
+		}
+		if matched {
+			return level, nil
+		}
+	}
+	return nil, fmt.Errorf("no TCB level of the platform matches: components=%v pcesvn=%v", exts.TCBComponents, exts.PCESVN)
+}
+
+func verifyCollateralSignature(signed []byte, signature []byte, issuerChain []byte, roots *x509.CertPool, now time.Time) error {
+	ders, err := decodePEMCertificates(issuerChain)
+	if err != nil {
+		return fmt.Errorf("invalid issuer chain: %w", err)
+	} else if len(ders) == 0 {
+		return fmt.Errorf("empty issuer chain")
+	}
+	signingCert, err := verifyCertChain(ders, roots, now)
+	if err != nil {
+		return fmt.Errorf("invalid issuer chain: %w", err)
+	}
+	if !verifyECDSASignature(signingCert, signed, signature) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// verifyCertChain verifies that the first certificate of `ders` chains to one of `roots` at `now` with the rest as the intermediates
+// and returns the first certificate. The root certificates included in `ders` are not trusted.
+func verifyCertChain(ders [][]byte, roots *x509.CertPool, now time.Time) (*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, len(ders))
+	for i, der := range ders {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: index=%v %w", i, err)
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, err
+	}
+	return certs[0], nil
+}
+
+// verifyECDSASignature verifies the ECDSA signature in the form of r||s of SHA-256 of `data` with the public key of `cert`
+func verifyECDSASignature(cert *x509.Certificate, data []byte, signature []byte) bool {
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return false
+	}
+	return verifyECDSA(pub, data, signature)
+}
+
+func verifyECDSA(pub *ecdsa.PublicKey, data []byte, signature []byte) bool {
+	if len(signature) != signatureLen {
+		return false
+	}
+	h := sha256.Sum256(data)
+	r := new(big.Int).SetBytes(signature[:signatureLen/2])
+	s := new(big.Int).SetBytes(signature[signatureLen/2:])
+	return ecdsa.Verify(pub, h[:], r, s)
+}
+
+func checkValidity(issueDate, nextUpdate, now time.Time) error {
+	if now.Before(issueDate) {
+		return fmt.Errorf("not issued yet: issue_date=%v now=%v", issueDate, now)
+	} else if now.After(nextUpdate) {
+		return fmt.Errorf("expired: next_update=%v now=%v", nextUpdate, now)
+	}
+	return nil
+}
+
+func maskedEqual(actual, expected, mask []byte) bool {
+	if len(actual) != len(mask) || len(expected) != len(mask) {
+		return false
+	}
+	for i := range mask {
+		if actual[i]&mask[i] != expected[i]&mask[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,266 @@
+package dcaptest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/datachainlab/lcp-go/sgx/dcap"
+	lcpias "github.com/datachainlab/lcp-go/sgx/ias"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// FMSPC is the FMSPC of the platform of the fixtures
+	FMSPC = []byte{0x00, 0x90, 0x6e, 0xd5, 0x00, 0x00}
+	// PCEID is the PCE ID of the platform of the fixtures
+	PCEID = []byte{0x00, 0x00}
+	// QEMRSIGNER is the MRSIGNER of the quoting enclave of the fixtures
+	QEMRSIGNER = sgx.MrSigner{0x8c, 0x4f, 0x57, 0x75}
+)
+
+const (
+	qeISVProdID  = 1
+	qeISVSVN     = 8
+	pceSVN       = 13
+	componentSVN = 2
+)
+
+// Params are the parameters of a DCAP attestation fixture
+type Params struct {
+	Mrenclave  sgx.MrEnclave
+	EnclaveKey common.Address
+	Operator   common.Address
+	ISVSVN     uint16
+	Debug      bool
+	// the TCB status and the advisory IDs of the platform's TCB level
+	TCBStatus   dcap.TCBStatus
+	AdvisoryIDs []string
+	// the issue date of the collateral, which is valid for 30 days
+	IssueDate time.Time
+}
+
+// Fixture is a DCAP attestation signed by a test root CA instead of Intel PCS
+type Fixture struct {
+	// the DER-encoded root certificate of the test root CA
+	RootCert   []byte
+	Quote      []byte
+	Collateral dcap.Collateral
+}
+
+// NewFixture generates a DCAP attestation of the enclave key with `params`
+func NewFixture(t testing.TB, params Params) *Fixture {
+	require := require.New(t)
+	if params.TCBStatus == "" {
+		params.TCBStatus = dcap.TCBStatusUpToDate
+	}
+	if params.IssueDate.IsZero() {
+		params.IssueDate = time.Now().Truncate(time.Second).UTC()
+	}
+	notBefore, notAfter := params.IssueDate.AddDate(-1, 0, 0), params.IssueDate.AddDate(10, 0, 0)
+
+	rootKey, rootCert := newCert(t, "Test SGX Root CA", nil, nil, notBefore, notAfter, nil)
+	pckCAKey, pckCACert := newCert(t, "Test SGX PCK Platform CA", rootKey, rootCert, notBefore, notAfter, nil)
+	pckKey, pckCert := newCert(t, "Test SGX PCK Certificate", pckCAKey, pckCACert, notBefore, notAfter, []pkix.Extension{{Id: dcap.OIDSGXExtensions, Value: pckExtensions(t)}})
+	tcbKey, tcbCert := newCert(t, "Test SGX TCB Signing", rootKey, rootCert, notBefore, notAfter, nil)
+
+	attestationKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	var q dcap.Quote
+	q.Header = dcap.QuoteHeader{
+		Version:            dcap.QuoteVersion,
+		AttestationKeyType: dcap.AttestationKeyTypeECDSAP256,
+		QESVN:              qeISVSVN,
+		PCESVN:             pceSVN,
+		QEVendorID:         dcap.IntelQEVendorID,
+	}
+	isvReport := ias.Report{
+		Attributes: sgx.Attributes{Flags: sgx.AttributeInit | sgx.AttributeMode64Bit},
+		MRENCLAVE:  params.Mrenclave,
+		ISVSVN:     params.ISVSVN,
+	}
+	if params.Debug {
+		isvReport.Attributes.Flags |= sgx.AttributeDebug
+	}
+	isvReport.ReportData[0] = lcpias.ReportDataVersion
+	copy(isvReport.ReportData[1:21], params.EnclaveKey[:])
+	copy(isvReport.ReportData[21:41], params.Operator[:])
+	q.ISVReport = reportBody(t, isvReport)
+	copy(q.AttestationKey[:], marshalPublicKey(&attestationKey.PublicKey))
+	q.QEAuthData = []byte("qe auth data")
+	qeReport := ias.Report{
+		Attributes: sgx.Attributes{Flags: sgx.AttributeInit | sgx.AttributeMode64Bit | sgx.AttributeProvisionKey},
+		MRSIGNER:   QEMRSIGNER,
+		ISVProdID:  qeISVProdID,
+		ISVSVN:     qeISVSVN,
+	}
+	h := sha256.Sum256(append(q.AttestationKey[:], q.QEAuthData...))
+	copy(qeReport.ReportData[:32], h[:])
+	q.QEReport = reportBody(t, qeReport)
+	copy(q.QEReportSignature[:], sign(t, pckKey, q.QEReport.Raw))
+	q.PCKCertChain = append(append(encodePEM(pckCert), encodePEM(pckCACert)...), encodePEM(rootCert)...)
+
+	// the ISV report signature is computed over the encoded header and ISV report
+	unsigned := dcap.EncodeQuote(&q)
+	copy(q.ISVReportSignature[:], sign(t, attestationKey, unsigned[:48+384]))
+
+	issuerChain := append(encodePEM(tcbCert), encodePEM(rootCert)...)
+	return &Fixture{
+		RootCert: rootCert.Raw,
+		Quote:    dcap.EncodeQuote(&q),
+		Collateral: dcap.Collateral{
+			TCBInfo:               signCollateral(t, tcbKey, "tcbInfo", newTCBInfo(params)),
+			TCBInfoIssuerChain:    issuerChain,
+			QEIdentity:            signCollateral(t, tcbKey, "enclaveIdentity", newQEIdentity(params.IssueDate)),
+			QEIdentityIssuerChain: issuerChain,
+		},
+	}
+}
+
+func newTCBInfo(params Params) map[string]any {
+	components := make([]map[string]any, dcap.TCBComponentsLen)
+	for i := range components {
+		components[i] = map[string]any{"svn": componentSVN}
+	}
+	advisoryIDs := params.AdvisoryIDs
+	if advisoryIDs == nil {
+		advisoryIDs = []string{}
+	}
+	return map[string]any{
+		"id":                      "SGX",
+		"version":                 dcap.TCBInfoVersion,
+		"issueDate":               params.IssueDate,
+		"nextUpdate":              params.IssueDate.AddDate(0, 0, 30),
+		"fmspc":                   hex.EncodeToString(FMSPC),
+		"pceId":                   hex.EncodeToString(PCEID),
+		"tcbType":                 0,
+		"tcbEvaluationDataNumber": 16,
+		"tcbLevels": []map[string]any{{
+			"tcb":         map[string]any{"sgxtcbcomponents": components, "pcesvn": pceSVN},
+			"tcbDate":     params.IssueDate.AddDate(0, -1, 0),
+			"tcbStatus":   params.TCBStatus,
+			"advisoryIDs": advisoryIDs,
+		}},
+	}
+}
+
+func newQEIdentity(issueDate time.Time) map[string]any {
+	return map[string]any{
+		"id":                      "QE",
+		"version":                 dcap.QEIdentityVersion,
+		"issueDate":               issueDate,
+		"nextUpdate":              issueDate.AddDate(0, 0, 30),
+		"tcbEvaluationDataNumber": 16,
+		"miscselect":              "00000000",
+		"miscselectMask":          "FFFFFFFF",
+		"attributes":              "11000000000000000000000000000000",
+		"attributesMask":          "FBFFFFFFFFFFFFFF0000000000000000",
+		"mrsigner":                hex.EncodeToString(QEMRSIGNER[:]),
+		"isvprodid":               qeISVProdID,
+		"tcbLevels": []map[string]any{{
+			"tcb":         map[string]any{"isvsvn": qeISVSVN},
+			"tcbDate":     issueDate.AddDate(0, -1, 0),
+			"tcbStatus":   dcap.TCBStatusUpToDate,
+			"advisoryIDs": []string{},
+		}},
+	}
+}
+
+// pckExtensions returns the SGX extensions of the PCK certificate of the platform
+func pckExtensions(t testing.TB) []byte {
+	type entry struct {
+		ID    asn1.ObjectIdentifier
+		Value any
+	}
+	var tcb []entry
+	for i := 1; i <= dcap.TCBComponentsLen; i++ {
+		tcb = append(tcb, entry{append(append(asn1.ObjectIdentifier{}, dcap.OIDTCB...), i), componentSVN})
+	}
+	tcb = append(tcb, entry{dcap.OIDPCESVN, pceSVN})
+	tcb = append(tcb, entry{append(append(asn1.ObjectIdentifier{}, dcap.OIDTCB...), 18), make([]byte, 16)})
+	bz, err := asn1.Marshal([]entry{
+		{asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1, 1}, make([]byte, 16)},
+		{dcap.OIDTCB, tcb},
+		{dcap.OIDPCEID, PCEID},
+		{dcap.OIDFMSPC, FMSPC},
+	})
+	require.NoError(t, err)
+	return bz
+}
+
+func newCert(t testing.TB, cn string, parentKey *ecdsa.PrivateKey, parent *x509.Certificate, notBefore, notAfter time.Time, exts []pkix.Extension) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn, Organization: []string{"Intel Corporation"}},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  exts == nil,
+		ExtraExtensions:       exts,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return key, cert
+}
+
+func reportBody(t testing.TB, report ias.Report) dcap.ReportBody {
+	raw, err := report.MarshalBinary()
+	require.NoError(t, err)
+	return dcap.ReportBody{Report: report, Raw: raw}
+}
+
+func signCollateral(t testing.TB, key *ecdsa.PrivateKey, name string, body map[string]any) []byte {
+	signed, err := json.Marshal(body)
+	require.NoError(t, err)
+	bz, err := json.Marshal(map[string]any{
+		name:        json.RawMessage(signed),
+		"signature": hex.EncodeToString(sign(t, key, signed)),
+	})
+	require.NoError(t, err)
+	return bz
+}
+
+// sign returns the ECDSA signature in the form of r||s of SHA-256 of `data`
+func sign(t testing.TB, key *ecdsa.PrivateKey, data []byte) []byte {
+	h := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, key, h[:])
+	require.NoError(t, err)
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return sig
+}
+
+func marshalPublicKey(pub *ecdsa.PublicKey) []byte {
+	bz := make([]byte, 64)
+	pub.X.FillBytes(bz[:32])
+	pub.Y.FillBytes(bz[32:])
+	return bz
+}
+
+func encodePEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
@@ -0,0 +1,4 @@
+package dcap
+
+// ConvergeTCBStatus exports convergeTCBStatus for the tests in the dcap_test package
+var ConvergeTCBStatus = convergeTCBStatus
@@ -0,0 +1,136 @@
+package dcap
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+)
+
+var (
+	// OIDSGXExtensions is the OID of the SGX extensions of the PCK certificates
+	OIDSGXExtensions = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1}
+	// OIDTCB is the OID of the TCB of the platform in the SGX extensions
+	OIDTCB = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1, 2}
+	// OIDPCEID is the OID of the PCE ID in the SGX extensions
+	OIDPCEID = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1, 3}
+	// OIDFMSPC is the OID of the FMSPC in the SGX extensions
+	OIDFMSPC = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1, 4}
+	// OIDPCESVN is the OID of the PCE SVN in the TCB of the SGX extensions
+	OIDPCESVN = asn1.ObjectIdentifier{1, 2, 840, 113741, 1, 13, 1, 2, 17}
+)
+
+// TCBComponentsLen is the number of the SGX TCB components
+const TCBComponentsLen = 16
+
+// sgxExtension is an entry of the SGX extensions of the PCK certificates
+type sgxExtension struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue
+}
+
+// PCKExtensions are the SGX extensions of a PCK certificate which are used to evaluate the TCB of the platform
+type PCKExtensions struct {
+	TCBComponents [TCBComponentsLen]uint8
+	PCESVN        uint16
+	PCEID         [2]byte
+	FMSPC         [6]byte
+}
+
+// ParsePCKExtensions parses the SGX extensions of the PCK certificate `cert`
+func ParsePCKExtensions(cert *x509.Certificate) (*PCKExtensions, error) {
+	var raw []byte
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(OIDSGXExtensions) {
+			raw = ext.Value
+			break
+		}
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("the SGX extensions are not found in the PCK certificate")
+	}
+	entries, err := unmarshalSGXExtensions(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SGX extensions: %w", err)
+	}
+	var (
+		exts                       PCKExtensions
+		hasTCB, hasPCEID, hasFMSPC bool
+	)
+	for _, e := range entries {
+		switch {
+		case e.ID.Equal(OIDTCB):
+			if err := exts.parseTCB(e.Value.FullBytes); err != nil {
+				return nil, err
+			}
+			hasTCB = true
+		case e.ID.Equal(OIDPCEID):
+			if err := unmarshalOctets(e.Value.FullBytes, exts.PCEID[:]); err != nil {
+				return nil, fmt.Errorf("invalid PCE ID: %w", err)
+			}
+			hasPCEID = true
+		case e.ID.Equal(OIDFMSPC):
+			if err := unmarshalOctets(e.Value.FullBytes, exts.FMSPC[:]); err != nil {
+				return nil, fmt.Errorf("invalid FMSPC: %w", err)
+			}
+			hasFMSPC = true
+		}
+	}
+	if !hasTCB || !hasPCEID || !hasFMSPC {
+		return nil, fmt.Errorf("the SGX extensions lack the required entries: tcb=%v pce_id=%v fmspc=%v", hasTCB, hasPCEID, hasFMSPC)
+	}
+	return &exts, nil
+}
+
+func (exts *PCKExtensions) parseTCB(bz []byte) error {
+	entries, err := unmarshalSGXExtensions(bz)
+	if err != nil {
+		return fmt.Errorf("invalid TCB: %w", err)
+	}
+	found := 0
+	for _, e := range entries {
+		if len(e.ID) != len(OIDTCB)+1 || !e.ID[:len(OIDTCB)].Equal(OIDTCB) {
+			continue
+		}
+		switch n := e.ID[len(OIDTCB)]; {
+		case n >= 1 && n <= TCBComponentsLen:
+			var svn int
+			if _, err := asn1.Unmarshal(e.Value.FullBytes, &svn); err != nil || svn < 0 || svn > 0xff {
+				return fmt.Errorf("invalid SVN of the TCB component %v: %v", n, e.Value.FullBytes)
+			}
+			exts.TCBComponents[n-1] = uint8(svn)
+			found++
+		case e.ID.Equal(OIDPCESVN):
+			var svn int
+			if _, err := asn1.Unmarshal(e.Value.FullBytes, &svn); err != nil || svn < 0 || svn > 0xffff {
+				return fmt.Errorf("invalid PCE SVN: %v", e.Value.FullBytes)
+			}
+			exts.PCESVN = uint16(svn)
+			found++
+		}
+	}
+	if found != TCBComponentsLen+1 {
+		return fmt.Errorf("the TCB lacks the SVNs: expected=%v actual=%v", TCBComponentsLen+1, found)
+	}
+	return nil
+}
+
+func unmarshalSGXExtensions(bz []byte) ([]sgxExtension, error) {
+	var entries []sgxExtension
+	if rest, err := asn1.Unmarshal(bz, &entries); err != nil {
+		return nil, err
+	} else if len(rest) != 0 {
+		return nil, fmt.Errorf("unexpected trailing bytes: length=%v", len(rest))
+	}
+	return entries, nil
+}
+
+func unmarshalOctets(bz []byte, out []byte) error {
+	var octets []byte
+	if _, err := asn1.Unmarshal(bz, &octets); err != nil {
+		return err
+	} else if len(octets) != len(out) {
+		return fmt.Errorf("unexpected length: expected=%v actual=%v", len(out), len(octets))
+	}
+	copy(out, octets)
+	return nil
+}
@@ -0,0 +1,217 @@
+package dcap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+)
+
+const (
+	// QuoteVersion is the version of the SGX ECDSA quotes which are supported
+	QuoteVersion uint16 = 3
+	// AttestationKeyTypeECDSAP256 is the type of the attestation key, i.e. ECDSA-256-with-P-256 curve
+	AttestationKeyTypeECDSAP256 uint16 = 2
+	// CertificationDataTypePCKCertChain is the type of the certification data which is the PEM-encoded PCK certificate chain
+	CertificationDataTypePCKCertChain uint16 = 5
+
+	quoteHeaderLen = 48
+	reportBodyLen  = 384
+	signatureLen   = 64
+	publicKeyLen   = 64
+)
+
+// IntelQEVendorID is the vendor ID of the quoting enclave provided by Intel
+var IntelQEVendorID = [16]byte{0x93, 0x9a, 0x72, 0x33, 0xf7, 0x9c, 0x4c, 0xa9, 0x94, 0x0a, 0x0d, 0xb3, 0x95, 0x7f, 0x06, 0x07}
+
+// QuoteHeader is the header of an SGX ECDSA quote
+type QuoteHeader struct {
+	Version            uint16
+	AttestationKeyType uint16
+	QESVN              uint16
+	PCESVN             uint16
+	QEVendorID         [16]byte
+	UserData           [20]byte
+}
+
+// ReportBody is the report body of an enclave and its raw bytes
+type ReportBody struct {
+	ias.Report
+	Raw []byte
+}
+
+// Quote is an SGX ECDSA quote (version 3) whose certification data is the PCK certificate chain
+type Quote struct {
+	Header QuoteHeader
+	// the report of the attested enclave
+	ISVReport ReportBody
+	// the signature of the header and the ISV report by the attestation key
+	ISVReportSignature [signatureLen]byte
+	// the uncompressed public key of the attestation key without the prefix
+	AttestationKey [publicKeyLen]byte
+	// the report of the quoting enclave, whose report data commits to the attestation key
+	QEReport ReportBody
+	// the signature of the QE report by the PCK
+	QEReportSignature [signatureLen]byte
+	QEAuthData        []byte
+	// the PEM-encoded PCK certificate chain, i.e. the PCK certificate, the intermediate CA certificate and the root CA certificate
+	PCKCertChain []byte
+
+	// the signed part of the quote, i.e. the header and the ISV report
+	signedData []byte
+}
+
+// ParseQuote parses an SGX ECDSA quote (version 3)
+func ParseQuote(bz []byte) (*Quote, error) {
+	if len(bz) < quoteHeaderLen+reportBodyLen+4 {
+		return nil, fmt.Errorf("quote is too short: length=%v", len(bz))
+	}
+	var q Quote
+	q.Header.Version = binary.LittleEndian.Uint16(bz[0:])
+	q.Header.AttestationKeyType = binary.LittleEndian.Uint16(bz[2:])
+	q.Header.QESVN = binary.LittleEndian.Uint16(bz[8:])
+	q.Header.PCESVN = binary.LittleEndian.Uint16(bz[10:])
+	copy(q.Header.QEVendorID[:], bz[12:28])
+	copy(q.Header.UserData[:], bz[28:48])
+	if q.Header.Version != QuoteVersion {
+		return nil, fmt.Errorf("unsupported quote version: expected=%v actual=%v", QuoteVersion, q.Header.Version)
+	}
+	if q.Header.AttestationKeyType != AttestationKeyTypeECDSAP256 {
+		return nil, fmt.Errorf("unsupported attestation key type: expected=%v actual=%v", AttestationKeyTypeECDSAP256, q.Header.AttestationKeyType)
+	}
+	if q.Header.QEVendorID != IntelQEVendorID {
+		return nil, fmt.Errorf("unexpected QE vendor ID: %x", q.Header.QEVendorID)
+	}
+	var err error
+	if q.ISVReport, err = parseReportBody(bz[quoteHeaderLen : quoteHeaderLen+reportBodyLen]); err != nil {
+		return nil, err
+	}
+	q.signedData = bz[:quoteHeaderLen+reportBodyLen]
+
+	r := &reader{bz: bz[quoteHeaderLen+reportBodyLen:]}
+	sigDataLen := r.uint32()
+	if r.err == nil && uint64(sigDataLen) != uint64(len(r.bz)) {
+		return nil, fmt.Errorf("unexpected signature data length: expected=%v actual=%v", len(r.bz), sigDataLen)
+	}
+	copy(q.ISVReportSignature[:], r.next(signatureLen))
+	copy(q.AttestationKey[:], r.next(publicKeyLen))
+	qeReport := r.next(reportBodyLen)
+	copy(q.QEReportSignature[:], r.next(signatureLen))
+	q.QEAuthData = r.next(int(r.uint16()))
+	certDataType := r.uint16()
+	q.PCKCertChain = r.next(int(r.uint32()))
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid signature data: %w", r.err)
+	} else if len(r.bz) != 0 {
+		return nil, fmt.Errorf("unexpected trailing bytes of the quote: length=%v", len(r.bz))
+	}
+	if certDataType != CertificationDataTypePCKCertChain {
+		return nil, fmt.Errorf("unsupported certification data type: expected=%v actual=%v", CertificationDataTypePCKCertChain, certDataType)
+	}
+	if q.QEReport, err = parseReportBody(qeReport); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// ISVEnclaveQuote returns the quote of the attested enclave in the form of the EPID quotes,
+// so that the functions for the EPID quotes, e.g. ias.GetEKAndOperator, can be applied to it
+func (q *Quote) ISVEnclaveQuote() *ias.Quote {
+	return &ias.Quote{Report: q.ISVReport.Report}
+}
+
+// pckCertChainPEMs returns the PEM blocks of the PCK certificate chain
+func (q *Quote) pckCertChainPEMs() []byte {
+	// the certification data may be terminated by a null byte
+	return bytes.TrimRight(q.PCKCertChain, "\x00")
+}
+
+func parseReportBody(bz []byte) (ReportBody, error) {
+	var r ias.Report
+	if err := r.UnmarshalBinary(bz); err != nil {
+		return ReportBody{}, err
+	}
+	return ReportBody{Report: r, Raw: bz}, nil
+}
+
+// EncodeQuote encodes `q` in the binary format. The report bodies are encoded from their raw bytes.
+func EncodeQuote(q *Quote) []byte {
+	var buf bytes.Buffer
+	header := make([]byte, quoteHeaderLen)
+	binary.LittleEndian.PutUint16(header[0:], q.Header.Version)
+	binary.LittleEndian.PutUint16(header[2:], q.Header.AttestationKeyType)
+	binary.LittleEndian.PutUint16(header[8:], q.Header.QESVN)
+	binary.LittleEndian.PutUint16(header[10:], q.Header.PCESVN)
+	copy(header[12:28], q.Header.QEVendorID[:])
+	copy(header[28:48], q.Header.UserData[:])
+	buf.Write(header)
+	buf.Write(q.ISVReport.Raw)
+
+	var sigData bytes.Buffer
+	sigData.Write(q.ISVReportSignature[:])
+	sigData.Write(q.AttestationKey[:])
+	sigData.Write(q.QEReport.Raw)
+	sigData.Write(q.QEReportSignature[:])
+	sigData.Write(binary.LittleEndian.AppendUint16(nil, uint16(len(q.QEAuthData))))
+	sigData.Write(q.QEAuthData)
+	sigData.Write(binary.LittleEndian.AppendUint16(nil, CertificationDataTypePCKCertChain))
+	sigData.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(q.PCKCertChain))))
+	sigData.Write(q.PCKCertChain)
+
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(sigData.Len())))
+	buf.Write(sigData.Bytes())
+	return buf.Bytes()
+}
+
+// decodePEMCertificates returns the DER-encoded certificates of the PEM blocks in `bz`
+func decodePEMCertificates(bz []byte) ([][]byte, error) {
+	var ders [][]byte
+	for {
+		var block *pem.Block
+		block, bz = pem.Decode(bz)
+		if block == nil {
+			break
+		} else if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block type: %v", block.Type)
+		}
+		ders = append(ders, block.Bytes)
+	}
+	if len(bytes.TrimSpace(bz)) != 0 {
+		return nil, fmt.Errorf("unexpected trailing bytes of the PEM certificates")
+	}
+	return ders, nil
+}
+
+// reader reads the little-endian fields of the quote. Once a read fails, it returns zero values.
+type reader struct {
+	bz  []byte
+	err error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	} else if len(r.bz) < n {
+		r.err = fmt.Errorf("unexpected end of data: expected=%v actual=%v", n, len(r.bz))
+		return nil
+	}
+	bz := r.bz[:n]
+	r.bz = r.bz[n:]
+	return bz
+}
+
+func (r *reader) uint16() uint16 {
+	if bz := r.next(2); bz != nil {
+		return binary.LittleEndian.Uint16(bz)
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if bz := r.next(4); bz != nil {
+		return binary.LittleEndian.Uint32(bz)
+	}
+	return 0
+}
@@ -0,0 +1,197 @@
+package dcap
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// TCBStatus is the TCB status of a platform or a quoting enclave in the collateral
+type TCBStatus string
+
+const (
+	TCBStatusUpToDate                          TCBStatus = "UpToDate"
+	TCBStatusSWHardeningNeeded                 TCBStatus = "SWHardeningNeeded"
+	TCBStatusConfigurationNeeded               TCBStatus = "ConfigurationNeeded"
+	TCBStatusConfigurationAndSWHardeningNeeded TCBStatus = "ConfigurationAndSWHardeningNeeded"
+	TCBStatusOutOfDate                         TCBStatus = "OutOfDate"
+	TCBStatusOutOfDateConfigurationNeeded      TCBStatus = "OutOfDateConfigurationNeeded"
+	TCBStatusRevoked                           TCBStatus = "Revoked"
+)
+
+// QuoteStatus returns the quote status of `s` in the vocabulary of the ISV enclave quote statuses of IAS,
+// so that the allowed quote statuses of the LCP client apply to both EPID and DCAP attestations:
+//
+//	UpToDate                          -> OK
+//	SWHardeningNeeded                 -> SW_HARDENING_NEEDED
+//	ConfigurationNeeded               -> CONFIGURATION_NEEDED
+//	ConfigurationAndSWHardeningNeeded -> CONFIGURATION_AND_SW_HARDENING_NEEDED
+//	OutOfDate                         -> GROUP_OUT_OF_DATE
+//	OutOfDateConfigurationNeeded      -> OUT_OF_DATE_CONFIGURATION_NEEDED
+//
+// Revoked and the unknown statuses have no quote status and are always rejected.
+func (s TCBStatus) QuoteStatus() (string, error) {
+	switch s {
+	case TCBStatusUpToDate:
+		return "OK", nil
+	case TCBStatusSWHardeningNeeded:
+		return "SW_HARDENING_NEEDED", nil
+	case TCBStatusConfigurationNeeded:
+		return "CONFIGURATION_NEEDED", nil
+	case TCBStatusConfigurationAndSWHardeningNeeded:
+		return "CONFIGURATION_AND_SW_HARDENING_NEEDED", nil
+	case TCBStatusOutOfDate:
+		return "GROUP_OUT_OF_DATE", nil
+	case TCBStatusOutOfDateConfigurationNeeded:
+		return "OUT_OF_DATE_CONFIGURATION_NEEDED", nil
+	default:
+		return "", fmt.Errorf("disallowed TCB status: %v", s)
+	}
+}
+
+// Collateral is the collateral issued by Intel PCS to verify a quote
+type Collateral struct {
+	TCBInfo               []byte
+	TCBInfoIssuerChain    []byte
+	QEIdentity            []byte
+	QEIdentityIssuerChain []byte
+}
+
+// VerifiedQuote is a quote verified with the collateral
+type VerifiedQuote struct {
+	Quote   *Quote
+	TCBInfo *TCBInfo
+	// the TCB status of the platform converged with the one of the quoting enclave
+	TCBStatus TCBStatus
+	// the advisory IDs of the TCB levels of the platform and the quoting enclave in ascending order
+	AdvisoryIDs []string
+}
+
+// NewRootCertPool returns the pool of the DER-encoded root certificates `ders`
+func NewRootCertPool(ders [][]byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for i, der := range ders {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root certificate: index=%v %w", i, err)
+		}
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+// VerifyQuote verifies `quote` with `collateral` at `now` and returns the TCB status of the attested enclave.
+// The PCK certificate chain of the quote and the issuer chains of the collateral must chain to one of `roots`.
+// The revocation of the PCK certificates is not checked because the quote does not carry the CRLs,
+// so the revoked platforms are only rejected by the TCB levels of the collateral.
+func VerifyQuote(quote []byte, collateral Collateral, roots *x509.CertPool, now time.Time) (*VerifiedQuote, error) {
+	q, err := ParseQuote(quote)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quote: %w", err)
+	}
+	ders, err := decodePEMCertificates(q.pckCertChainPEMs())
+	if err != nil {
+		return nil, fmt.Errorf("invalid PCK certificate chain: %w", err)
+	} else if len(ders) == 0 {
+		return nil, fmt.Errorf("empty PCK certificate chain")
+	}
+	pckCert, err := verifyCertChain(ders, roots, now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PCK certificate chain: %w", err)
+	}
+	exts, err := ParsePCKExtensions(pckCert)
+	if err != nil {
+		return nil, err
+	}
+
+	// the PCK signs the QE report, which commits to the attestation key that signs the ISV report
+	if !verifyECDSASignature(pckCert, q.QEReport.Raw, q.QEReportSignature[:]) {
+		return nil, fmt.Errorf("invalid signature of the QE report")
+	}
+	expectedReportData := sha256.Sum256(append(q.AttestationKey[:], q.QEAuthData...))
+	if !bytes.Equal(q.QEReport.ReportData[:32], expectedReportData[:]) || !bytes.Equal(q.QEReport.ReportData[32:], make([]byte, 32)) {
+		return nil, fmt.Errorf("the QE report data does not commit to the attestation key")
+	}
+	attestationKey := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(q.AttestationKey[:publicKeyLen/2]),
+		Y:     new(big.Int).SetBytes(q.AttestationKey[publicKeyLen/2:]),
+	}
+	if !verifyECDSA(attestationKey, q.signedData, q.ISVReportSignature[:]) {
+		return nil, fmt.Errorf("invalid signature of the ISV report")
+	}
+
+	tcbInfo, err := ParseTCBInfo(collateral.TCBInfo, collateral.TCBInfoIssuerChain, roots, now)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(tcbInfo.FMSPC, exts.FMSPC[:]) {
+		return nil, fmt.Errorf("FMSPC mismatch: tcb_info=%x pck=%x", []byte(tcbInfo.FMSPC), exts.FMSPC[:])
+	} else if !bytes.Equal(tcbInfo.PCEID, exts.PCEID[:]) {
+		return nil, fmt.Errorf("PCE ID mismatch: tcb_info=%x pck=%x", []byte(tcbInfo.PCEID), exts.PCEID[:])
+	}
+	platformLevel, err := tcbInfo.tcbLevel(exts)
+	if err != nil {
+		return nil, err
+	}
+	qeIdentity, err := ParseQEIdentity(collateral.QEIdentity, collateral.QEIdentityIssuerChain, roots, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := qeIdentity.match(q.QEReport); err != nil {
+		return nil, fmt.Errorf("the QE report does not match the QE identity: %w", err)
+	}
+	qeLevel, err := qeIdentity.tcbLevel(q.QEReport.ISVSVN)
+	if err != nil {
+		return nil, err
+	}
+
+	status := convergeTCBStatus(platformLevel.TCBStatus, qeLevel.TCBStatus)
+	if _, err := status.QuoteStatus(); err != nil {
+		return nil, err
+	}
+	return &VerifiedQuote{
+		Quote:       q,
+		TCBInfo:     tcbInfo,
+		TCBStatus:   status,
+		AdvisoryIDs: mergeAdvisoryIDs(platformLevel.AdvisoryIDs, qeLevel.AdvisoryIDs),
+	}, nil
+}
+
+// convergeTCBStatus returns the TCB status of the platform with `platform` on which the quoting enclave with `qe` runs
+func convergeTCBStatus(platform, qe TCBStatus) TCBStatus {
+	switch qe {
+	case TCBStatusUpToDate:
+		return platform
+	case TCBStatusOutOfDate:
+		switch platform {
+		case TCBStatusUpToDate, TCBStatusSWHardeningNeeded:
+			return TCBStatusOutOfDate
+		case TCBStatusConfigurationNeeded, TCBStatusConfigurationAndSWHardeningNeeded:
+			return TCBStatusOutOfDateConfigurationNeeded
+		}
+		return platform
+	default:
+		// the revoked or unknown status of the quoting enclave
+		return qe
+	}
+}
+
+func mergeAdvisoryIDs(a, b []string) []string {
+	seen := make(map[string]struct{}, len(a)+len(b))
+	var ids []string
+	for _, id := range append(append([]string{}, a...), b...) {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,114 @@
+package dcap_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/datachainlab/lcp-go/sgx/dcap"
+	"github.com/datachainlab/lcp-go/sgx/dcap/dcaptest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyQuote(t *testing.T) {
+	require := require.New(t)
+	issueDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := dcaptest.NewFixture(t, dcaptest.Params{
+		Mrenclave:   [32]byte{0x01},
+		EnclaveKey:  common.HexToAddress("0x01"),
+		Operator:    common.HexToAddress("0x02"),
+		ISVSVN:      3,
+		TCBStatus:   dcap.TCBStatusSWHardeningNeeded,
+		AdvisoryIDs: []string{"INTEL-SA-00615", "INTEL-SA-00334"},
+		IssueDate:   issueDate,
+	})
+	roots, err := dcap.NewRootCertPool([][]byte{f.RootCert})
+	require.NoError(err)
+	now := issueDate.Add(time.Hour)
+
+	vq, err := dcap.VerifyQuote(f.Quote, f.Collateral, roots, now)
+	require.NoError(err)
+	require.Equal(dcap.TCBStatusSWHardeningNeeded, vq.TCBStatus)
+	require.Equal([]string{"INTEL-SA-00334", "INTEL-SA-00615"}, vq.AdvisoryIDs)
+	require.Equal(issueDate, vq.TCBInfo.IssueDate)
+	require.Equal([32]byte{0x01}, [32]byte(vq.Quote.ISVReport.MRENCLAVE))
+	require.Equal(uint16(3), vq.Quote.ISVEnclaveQuote().Report.ISVSVN)
+
+	// the root of the chains must be trusted
+	other := dcaptest.NewFixture(t, dcaptest.Params{IssueDate: issueDate})
+	otherRoots, err := dcap.NewRootCertPool([][]byte{other.RootCert})
+	require.NoError(err)
+	_, err = dcap.VerifyQuote(f.Quote, f.Collateral, otherRoots, now)
+	require.ErrorContains(err, "invalid PCK certificate chain")
+	_, err = dcap.VerifyQuote(f.Quote, other.Collateral, roots, now)
+	require.ErrorContains(err, "failed to verify the TCB info")
+
+	// the collateral must be valid at `now`
+	_, err = dcap.VerifyQuote(f.Quote, f.Collateral, roots, issueDate.Add(-time.Second))
+	require.ErrorContains(err, "not issued yet")
+	_, err = dcap.VerifyQuote(f.Quote, f.Collateral, roots, issueDate.AddDate(0, 0, 31))
+	require.ErrorContains(err, "expired")
+
+	// the ISV report is signed by the attestation key
+	tampered := bytes.Clone(f.Quote)
+	tampered[48+64] ^= 0xff
+	_, err = dcap.VerifyQuote(tampered, f.Collateral, roots, now)
+	require.ErrorContains(err, "invalid signature of the ISV report")
+
+	// the collateral is signed
+	collateral := f.Collateral
+	collateral.TCBInfo = bytes.Replace(collateral.TCBInfo, []byte("SWHardeningNeeded"), []byte("UpToDate"), 1)
+	_, err = dcap.VerifyQuote(f.Quote, collateral, roots, now)
+	require.ErrorContains(err, "invalid signature")
+}
+
+func TestVerifyQuoteTCBStatus(t *testing.T) {
+	issueDate := time.Now().Truncate(time.Second).UTC()
+	var cases = []struct {
+		status      dcap.TCBStatus
+		quoteStatus string
+	}{
+		{dcap.TCBStatusUpToDate, "OK"},
+		{dcap.TCBStatusSWHardeningNeeded, "SW_HARDENING_NEEDED"},
+		{dcap.TCBStatusConfigurationNeeded, "CONFIGURATION_NEEDED"},
+		{dcap.TCBStatusConfigurationAndSWHardeningNeeded, "CONFIGURATION_AND_SW_HARDENING_NEEDED"},
+		{dcap.TCBStatusOutOfDate, "GROUP_OUT_OF_DATE"},
+		{dcap.TCBStatusOutOfDateConfigurationNeeded, "OUT_OF_DATE_CONFIGURATION_NEEDED"},
+		{dcap.TCBStatusRevoked, ""},
+		{"Unknown", ""},
+	}
+	for _, c := range cases {
+		t.Run(string(c.status), func(t *testing.T) {
+			require := require.New(t)
+			f := dcaptest.NewFixture(t, dcaptest.Params{TCBStatus: c.status, IssueDate: issueDate})
+			roots, err := dcap.NewRootCertPool([][]byte{f.RootCert})
+			require.NoError(err)
+			vq, err := dcap.VerifyQuote(f.Quote, f.Collateral, roots, issueDate)
+			if c.quoteStatus == "" {
+				require.ErrorContains(err, "disallowed TCB status")
+				return
+			}
+			require.NoError(err)
+			quoteStatus, err := vq.TCBStatus.QuoteStatus()
+			require.NoError(err)
+			require.Equal(c.quoteStatus, quoteStatus)
+		})
+	}
+}
+
+func TestConvergeTCBStatus(t *testing.T) {
+	var cases = []struct {
+		platform, qe, expected dcap.TCBStatus
+	}{
+		{dcap.TCBStatusSWHardeningNeeded, dcap.TCBStatusUpToDate, dcap.TCBStatusSWHardeningNeeded},
+		{dcap.TCBStatusUpToDate, dcap.TCBStatusOutOfDate, dcap.TCBStatusOutOfDate},
+		{dcap.TCBStatusSWHardeningNeeded, dcap.TCBStatusOutOfDate, dcap.TCBStatusOutOfDate},
+		{dcap.TCBStatusConfigurationNeeded, dcap.TCBStatusOutOfDate, dcap.TCBStatusOutOfDateConfigurationNeeded},
+		{dcap.TCBStatusConfigurationAndSWHardeningNeeded, dcap.TCBStatusOutOfDate, dcap.TCBStatusOutOfDateConfigurationNeeded},
+		{dcap.TCBStatusUpToDate, dcap.TCBStatusRevoked, dcap.TCBStatusRevoked},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.expected, dcap.ConvergeTCBStatus(c.platform, c.qe), "platform=%v qe=%v", c.platform, c.qe)
+	}
+}
@@ -2,14 +2,24 @@ package ias
 
 import "github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
 
+// allowDebugEnclaves mirrors the flag of the oasis-core's ias package, which is not exported
+var allowDebugEnclaves bool
+
 // SetAllowDebugEnclave will enable running and communicating with enclaves
 // with debug flag enabled in AVR for the remainder of the process' lifetime.
 func SetAllowDebugEnclaves() {
+	allowDebugEnclaves = true
 	ias.SetAllowDebugEnclaves()
 }
 
 // UnsetAllowDebugEnclave will disable running and communicating with enclaves
 // with debug flag enabled in AVR for the remainder of the process' lifetime.
 func UnsetAllowDebugEnclaves() {
+	allowDebugEnclaves = false
 	ias.UnsetAllowDebugEnclaves()
 }
+
+// IsAllowDebugEnclaves returns true if the enclaves with debug flag enabled are allowed
+func IsAllowDebugEnclaves() bool {
+	return allowDebugEnclaves
+}
@@ -0,0 +1,72 @@
+package ias
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+var (
+	revokedSerialsMu sync.RWMutex
+	// revokedSerials is a set of serial numbers of the report signing certificates that are pinned as revoked
+	revokedSerials = map[string]struct{}{}
+)
+
+// SetRevokedSigningCertSerials pins the given serial numbers of the report signing certificates as revoked
+// for the remainder of the process' lifetime.
+// The on-chain verifier cannot fetch CRLs, so this allows a governance-driven revocation by the host application.
+func SetRevokedSigningCertSerials(serials []*big.Int) {
+	revokedSerialsMu.Lock()
+	defer revokedSerialsMu.Unlock()
+	revokedSerials = make(map[string]struct{}, len(serials))
+	for _, s := range serials {
+		revokedSerials[s.String()] = struct{}{}
+	}
+}
+
+// IsRevokedSigningCertSerial returns true if the given serial number is pinned as revoked
+func IsRevokedSigningCertSerial(serial *big.Int) bool {
+	revokedSerialsMu.RLock()
+	defer revokedSerialsMu.RUnlock()
+	_, ok := revokedSerials[serial.String()]
+	return ok
+}
+
+// ParseCRL parses a DER or PEM encoded CRL and verifies that it is signed by the issuer and not expired at `currentTime`.
+func ParseCRL(bz []byte, issuer *x509.Certificate, currentTime time.Time) (*x509.RevocationList, error) {
+	crl, err := x509.ParseRevocationList(decodePEMIfNeeded(bz))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CRL: %w", err)
+	}
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
+		return nil, fmt.Errorf("failed to verify CRL signature: %w", err)
+	}
+	if !crl.NextUpdate.IsZero() && currentTime.After(crl.NextUpdate) {
+		return nil, fmt.Errorf("CRL is expired: next_update=%v current_time=%v", crl.NextUpdate, currentTime)
+	}
+	return crl, nil
+}
+
+// CheckRevocation returns an error if the signing certificate is revoked by the given CRL
+func CheckRevocation(signingCertDer []byte, crl *x509.RevocationList) error {
+	signingCert, err := x509.ParseCertificate(signingCertDer)
+	if err != nil {
+		return err
+	}
+	for _, entry := range crl.RevokedCertificateEntries {
+		if entry.SerialNumber.Cmp(signingCert.SerialNumber) == 0 {
+			return fmt.Errorf("the signing certificate is revoked: serial=%v revoked_at=%v", signingCert.SerialNumber, entry.RevocationTime)
+		}
+	}
+	return nil
+}
+
+func decodePEMIfNeeded(bz []byte) []byte {
+	if block, _ := pem.Decode(bz); block != nil {
+		return block.Bytes
+	}
+	return bz
+}
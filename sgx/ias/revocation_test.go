@@ -0,0 +1,90 @@
+package ias
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRevocation(t *testing.T) {
+	require := require.New(t)
+	ias.SetAllowDebugEnclaves()
+	defer ias.UnsetAllowDebugEnclaves()
+
+	bz, err := os.ReadFile("../../testdata/001-avr")
+	require.NoError(err)
+	var eavr endorsedAttestationVerificationReport
+	require.NoError(json.Unmarshal(bz, &eavr))
+	signingCert, err := x509.ParseCertificate(eavr.SigningCert)
+	require.NoError(err)
+
+	now := time.Now()
+	issuer, issuerKey := newTestCRLIssuer(t, now)
+
+	revoked := createTestCRL(t, issuer, issuerKey, now, signingCert.SerialNumber)
+	crl, err := ParseCRL(revoked, issuer, now)
+	require.NoError(err)
+	require.Error(CheckRevocation(eavr.SigningCert, crl))
+
+	notRevoked := createTestCRL(t, issuer, issuerKey, now, big.NewInt(1))
+	crl, err = ParseCRL(notRevoked, issuer, now)
+	require.NoError(err)
+	require.NoError(CheckRevocation(eavr.SigningCert, crl))
+
+	// expired CRL
+	_, err = ParseCRL(notRevoked, issuer, now.Add(48*time.Hour))
+	require.Error(err)
+
+	// CRL signed by an unexpected issuer
+	otherIssuer, _ := newTestCRLIssuer(t, now)
+	_, err = ParseCRL(notRevoked, otherIssuer, now)
+	require.Error(err)
+
+	// pinned serials
+	require.NoError(VerifyReport([]byte(eavr.AVR), eavr.Signature, eavr.SigningCert, now))
+	SetRevokedSigningCertSerials([]*big.Int{signingCert.SerialNumber})
+	defer SetRevokedSigningCertSerials(nil)
+	require.Error(VerifyReport([]byte(eavr.AVR), eavr.Signature, eavr.SigningCert, now))
+}
+
+func newTestCRLIssuer(t *testing.T, now time.Time) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CRL issuer"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func createTestCRL(t *testing.T, issuer *x509.Certificate, key *ecdsa.PrivateKey, now time.Time, revokedSerial *big.Int) []byte {
+	bz, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: now.Add(-time.Hour),
+		NextUpdate: now.Add(24 * time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: revokedSerial, RevocationTime: now.Add(-time.Minute)},
+		},
+	}, issuer, key)
+	require.NoError(t, err)
+	return bz
+}
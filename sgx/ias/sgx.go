@@ -2,10 +2,12 @@ package ias
 
 import (
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
 )
 
@@ -50,6 +52,10 @@ func VerifyReport(report []byte, signature []byte, signingCertDer []byte, curren
 		return fmt.Errorf("unexpected root cert: %v", chains[0][1])
 	}
 
+	if IsRevokedSigningCertSerial(signingCert.SerialNumber) {
+		return fmt.Errorf("the signing certificate is revoked: serial=%v", signingCert.SerialNumber)
+	}
+
 	if err = signingCert.CheckSignature(x509.SHA256WithRSA, report, signature); err != nil {
 		return fmt.Errorf("failed to verify AVR signature: %w", err)
 	}
@@ -60,6 +66,9 @@ func VerifyReport(report []byte, signature []byte, signingCertDer []byte, curren
 func ParseAndValidateAVR(report []byte) (*AttestationVerificationReport, error) {
 	avr, err := ias.UnsafeDecodeAVR(report)
 	if err != nil {
+		if !allowDebugEnclaves && isDebugEnclaveReport(report) {
+			return nil, fmt.Errorf("debug enclave is not allowed: debug=true %w", err)
+		}
 		return nil, err
 	}
 	return &AttestationVerificationReport{AttestationVerificationReport: *avr}, nil
@@ -77,3 +86,33 @@ func GetEKAndOperator(quote *ias.Quote) (common.Address, common.Address, error)
 	operator := common.BytesToAddress(quote.Report.ReportData[21:41])
 	return ek, operator, nil
 }
+
+// IsDebugEnclave returns true if the debug flag is set in the attributes of the quote's report
+func IsDebugEnclave(quote *ias.Quote) bool {
+	return quote.Report.Attributes.Flags.Contains(sgx.AttributeDebug)
+}
+
+// CheckISVSVN returns an error if the ISV SVN of the quote's report is lower than `minimum`.
+// TCB recoveries of the enclave are reflected in the ISV SVN. If `minimum` is zero, any ISV SVN is accepted.
+func CheckISVSVN(quote *ias.Quote, minimum uint16) error {
+	if svn := quote.Report.ISVSVN; svn < minimum {
+		return fmt.Errorf("the ISV SVN is lower than the minimum: minimum=%v actual=%v", minimum, svn)
+	}
+	return nil
+}
+
+// isDebugEnclaveReport returns true if the report contains a quote with debug flag enabled.
+// It is used to surface the reason why the report is rejected.
+func isDebugEnclaveReport(report []byte) bool {
+	var avr struct {
+		ISVEnclaveQuoteBody []byte `json:"isvEnclaveQuoteBody"`
+	}
+	if err := json.Unmarshal(report, &avr); err != nil {
+		return false
+	}
+	var quote ias.Quote
+	if err := quote.UnmarshalBinary(avr.ISVEnclaveQuoteBody); err != nil {
+		return false
+	}
+	return IsDebugEnclave(&quote)
+}
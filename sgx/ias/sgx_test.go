@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/oasisprotocol/oasis-core/go/common/sgx"
 	"github.com/oasisprotocol/oasis-core/go/common/sgx/ias"
 	"github.com/stretchr/testify/require"
 )
@@ -58,6 +59,78 @@ func TestReportVerification(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, tc.ek, ek)
 			require.Equal(t, tc.op, operator)
+			require.True(t, IsDebugEnclave(quote))
 		})
 	}
 }
+
+func TestDebugEnclaveFlag(t *testing.T) {
+	var quote ias.Quote
+	require.False(t, IsDebugEnclave(&quote))
+	quote.Report.Attributes.Flags |= sgx.AttributeDebug
+	require.True(t, IsDebugEnclave(&quote))
+
+	bz, err := os.ReadFile("../../testdata/002-avr")
+	require.NoError(t, err)
+	var eavr endorsedAttestationVerificationReport
+	require.NoError(t, json.Unmarshal(bz, &eavr))
+
+	require.False(t, IsAllowDebugEnclaves())
+	_, err = ParseAndValidateAVR([]byte(eavr.AVR))
+	require.ErrorContains(t, err, "debug enclave is not allowed")
+
+	SetAllowDebugEnclaves()
+	defer UnsetAllowDebugEnclaves()
+	require.True(t, IsAllowDebugEnclaves())
+	_, err = ParseAndValidateAVR([]byte(eavr.AVR))
+	require.NoError(t, err)
+}
+
+// loadTestQuoteWithISVSVN returns the quote of the fixture re-encoded with the given ISV SVN
+func loadTestQuoteWithISVSVN(t *testing.T, svn uint16) *ias.Quote {
+	bz, err := os.ReadFile("../../testdata/001-avr")
+	require.NoError(t, err)
+	var eavr endorsedAttestationVerificationReport
+	require.NoError(t, json.Unmarshal(bz, &eavr))
+
+	SetAllowDebugEnclaves()
+	defer UnsetAllowDebugEnclaves()
+	avr, err := ParseAndValidateAVR([]byte(eavr.AVR))
+	require.NoError(t, err)
+	quote, err := avr.Quote()
+	require.NoError(t, err)
+	require.Equal(t, uint16(0), quote.Report.ISVSVN)
+
+	quote.Report.ISVSVN = svn
+	body, err := quote.MarshalBinary()
+	require.NoError(t, err)
+	var q ias.Quote
+	require.NoError(t, q.UnmarshalBinary(body))
+	return &q
+}
+
+func TestCheckISVSVN(t *testing.T) {
+	var cases = []struct {
+		svn     uint16
+		minimum uint16
+		ok      bool
+	}{
+		{0, 0, true},
+		{1, 0, true},
+		{0, 1, false},
+		{1, 1, true},
+		{2, 1, true},
+		{1, 2, false},
+		{65535, 65535, true},
+	}
+	for _, c := range cases {
+		quote := loadTestQuoteWithISVSVN(t, c.svn)
+		require.Equal(t, c.svn, quote.Report.ISVSVN)
+		err := CheckISVSVN(quote, c.minimum)
+		if c.ok {
+			require.NoError(t, err, "svn=%v minimum=%v", c.svn, c.minimum)
+		} else {
+			require.ErrorContains(t, err, "the ISV SVN is lower than the minimum", "svn=%v minimum=%v", c.svn, c.minimum)
+		}
+	}
+}
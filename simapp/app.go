@@ -207,6 +207,10 @@ type SimApp struct {
 	ConsensusParamsKeeper consensusparamkeeper.Keeper
 	CircuitKeeper         circuitkeeper.Keeper
 
+	// LCPLightClientModule is the standalone adapter of the LCP client by the client ID
+	// it is not routed by the IBC keeper, which calls the methods of the client state
+	LCPLightClientModule lcptypes.LightClientModule
+
 	// make scoped keepers public for test purposes
 	ScopedIBCKeeper           capabilitykeeper.ScopedKeeper
 	ScopedTransferKeeper      capabilitykeeper.ScopedKeeper
@@ -416,6 +420,7 @@ func NewSimApp(
 	)
 	// this is a workaround in case the counterparty chain uses mock-client
 	app.IBCKeeper = overrideIBCClientKeeper(*app.IBCKeeper, appCodec, keys[ibcexported.StoreKey], app.GetSubspace(ibcexported.ModuleName))
+	app.LCPLightClientModule = lcptypes.NewLightClientModule(appCodec, app.IBCKeeper.ClientKeeper)
 
 	// Register the proposal types
 	// Deprecated: Avoid adding new handlers, instead use the new proposal flow
@@ -616,7 +621,7 @@ func NewSimApp(
 		solomachine.NewAppModule(),
 		mockModule,
 
-		lcp.NewAppModule(),
+		lcp.NewAppModuleWithQueryServer(appCodec, app.IBCKeeper.ClientKeeper),
 	)
 
 	// BasicModuleManager defines the module BasicManager is in charge of setting up basic,
@@ -0,0 +1,106 @@
+package conformance
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The environment variables to configure the conformance suite
+const (
+	EnvServiceAddress       = "LCP_CONFORMANCE_SERVICE_ADDRESS"
+	EnvMrenclave            = "LCP_CONFORMANCE_MRENCLAVE"
+	EnvOriginChainID        = "LCP_CONFORMANCE_ORIGIN_CHAIN_ID"
+	EnvOriginRPCAddress     = "LCP_CONFORMANCE_ORIGIN_RPC_ADDRESS"
+	EnvELCClientID          = "LCP_CONFORMANCE_ELC_CLIENT_ID"
+	EnvKeyExpiration        = "LCP_CONFORMANCE_KEY_EXPIRATION"
+	EnvAllowedQuoteStatuses = "LCP_CONFORMANCE_ALLOWED_QUOTE_STATUSES"
+	EnvAllowedAdvisoryIDs   = "LCP_CONFORMANCE_ALLOWED_ADVISORY_IDS"
+	EnvNewKeyCommand        = "LCP_CONFORMANCE_NEW_KEY_COMMAND"
+	EnvReport               = "LCP_CONFORMANCE_REPORT"
+)
+
+// Config is the configuration of the conformance suite
+type Config struct {
+	// the address of the LCP service under test
+	ServiceAddress string
+	// the hex-encoded MRENCLAVE of the enclave running in the LCP service
+	Mrenclave string
+
+	// the chain ID of the origin chain
+	OriginChainID string
+	// the RPC address of the origin chain
+	OriginRPCAddress string
+
+	// the client ID of the ELC created by the suite
+	ELCClientID string
+	// the key expiration of the LCP client in seconds
+	KeyExpiration uint64
+	// the quote statuses and the advisory IDs allowed by the LCP client
+	AllowedQuoteStatuses []string
+	AllowedAdvisoryIDs   []string
+
+	// the shell command to make the LCP service attest a new enclave key
+	// if empty, the key rotation step is skipped
+	NewKeyCommand string
+	// the path to write the JSON report
+	// if empty, the report is only logged
+	ReportPath string
+}
+
+// ConfigFromEnv loads the configuration from the environment variables
+func ConfigFromEnv() (*Config, error) {
+	c := &Config{
+		ServiceAddress:       os.Getenv(EnvServiceAddress),
+		Mrenclave:            strings.ToLower(strings.TrimPrefix(os.Getenv(EnvMrenclave), "0x")),
+		OriginChainID:        getEnv(EnvOriginChainID, "ibc0"),
+		OriginRPCAddress:     getEnv(EnvOriginRPCAddress, "http://localhost:26657"),
+		ELCClientID:          getEnv(EnvELCClientID, fmt.Sprintf("07-tendermint-%d", time.Now().Unix())),
+		AllowedQuoteStatuses: splitList(getEnv(EnvAllowedQuoteStatuses, "GROUP_OUT_OF_DATE")),
+		AllowedAdvisoryIDs:   splitList(getEnv(EnvAllowedAdvisoryIDs, "INTEL-SA-00219,INTEL-SA-00289,INTEL-SA-00334,INTEL-SA-00477,INTEL-SA-00614,INTEL-SA-00615,INTEL-SA-00617")),
+		NewKeyCommand:        os.Getenv(EnvNewKeyCommand),
+		ReportPath:           os.Getenv(EnvReport),
+	}
+	keyExpiration, err := strconv.ParseUint(getEnv(EnvKeyExpiration, "604800"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v: %w", EnvKeyExpiration, err)
+	}
+	c.KeyExpiration = keyExpiration
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Config) Validate() error {
+	if c.ServiceAddress == "" {
+		return fmt.Errorf("%v must be set", EnvServiceAddress)
+	}
+	if c.Mrenclave == "" {
+		return fmt.Errorf("%v must be set", EnvMrenclave)
+	}
+	if c.KeyExpiration == 0 {
+		return fmt.Errorf("%v must be greater than 0", EnvKeyExpiration)
+	}
+	return nil
+}
+
+func getEnv(name, defaultValue string) string {
+	if v, ok := os.LookupEnv(name); ok && v != "" {
+		return v
+	}
+	return defaultValue
+}
+
+// splitList splits a comma-separated list, and an empty string is an empty list
+func splitList(s string) []string {
+	var list []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
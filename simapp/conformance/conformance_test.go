@@ -0,0 +1,27 @@
+//go:build conformance
+
+package conformance
+
+import (
+	"testing"
+
+	"github.com/hyperledger-labs/yui-relayer/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConformance runs the conformance suite against the LCP service configured by the environment variables.
+// See scripts/run_conformance_test.sh for the setup with a local LCP service and origin chain.
+func TestConformance(t *testing.T) {
+	require.NoError(t, log.InitLogger("INFO", "text", "stdout"))
+	config, err := ConfigFromEnv()
+	require.NoError(t, err)
+	suite, err := NewSuite(t, config)
+	require.NoError(t, err)
+
+	report := suite.Run()
+	t.Logf("conformance report:\n%v", report)
+	if config.ReportPath != "" {
+		require.NoError(t, report.WriteFile(config.ReportPath))
+	}
+	require.True(t, report.Passed(), "some steps of the conformance suite failed")
+}
@@ -0,0 +1,269 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+	abci "github.com/cometbft/cometbft/abci/types"
+	dbm "github.com/cosmos/cosmos-db"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v8/modules/core/23-commitment/types"
+	ibcexported "github.com/cosmos/ibc-go/v8/modules/core/exported"
+	ibctesting "github.com/cosmos/ibc-go/v8/testing"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/simapp"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+)
+
+// CounterpartyChainID is the chain ID of the in-process counterparty chain
+const CounterpartyChainID = "lcp-conformance-0"
+
+// setupSimApp makes ibctesting run the simapp of this repository, which hosts the LCP client
+func setupSimApp() (ibctesting.TestingApp, map[string]json.RawMessage) {
+	app := simapp.NewSimApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, simtestutil.EmptyAppOptions{})
+	return app, app.DefaultGenesis()
+}
+
+// Counterparty is the counterparty chain of the LCP client, which runs the simapp in-process with ibctesting.
+// It implements the subset of core.FinalityAwareChain that the LCP prover uses, and every block is final as soon as it is committed.
+// The queries always return the latest state regardless of the height in the query context.
+type Counterparty struct {
+	core.FinalityAwareChain
+
+	coord    *ibctesting.Coordinator
+	chain    *ibctesting.TestChain
+	clientID string
+
+	// the results of the msgs sent by SendMsgs, keyed by the ID of the tx
+	results map[string]*msgResult
+	txs     uint64
+}
+
+var _ core.FinalityAwareChain = (*Counterparty)(nil)
+
+// NewCounterparty starts the counterparty chain at the current time.
+// The clock of the chain follows the wall clock so that the AVRs and the messages of the LCP service are valid on the chain.
+func NewCounterparty(t *testing.T) *Counterparty {
+	ibctesting.DefaultTestingAppInit = setupSimApp
+	coord := ibctesting.NewCoordinator(t, 0)
+	coord.CurrentTime = time.Now().UTC()
+	chain := ibctesting.NewTestChain(t, coord, CounterpartyChainID)
+	coord.Chains = map[string]*ibctesting.TestChain{CounterpartyChainID: chain}
+	return &Counterparty{
+		coord:   coord,
+		chain:   chain,
+		results: make(map[string]*msgResult),
+	}
+}
+
+// CreateClient creates the LCP client on the chain, and the client becomes the target of the other methods
+func (c *Counterparty) CreateClient(clientState ibcexported.ClientState, consensusState ibcexported.ConsensusState) (string, error) {
+	msg, err := clienttypes.NewMsgCreateClient(clientState, consensusState, c.chain.SenderAccount.GetAddress().String())
+	if err != nil {
+		return "", err
+	}
+	res, err := c.deliver(msg)
+	if err != nil {
+		return "", err
+	}
+	clientID, err := ibctesting.ParseClientIDFromEvents(res.Events)
+	if err != nil {
+		return "", err
+	}
+	c.clientID = clientID
+	return clientID, nil
+}
+
+func (c *Counterparty) ChainID() string {
+	return c.chain.ChainID
+}
+
+func (c *Counterparty) Path() *core.PathEnd {
+	return &core.PathEnd{ChainID: c.chain.ChainID, ClientID: c.clientID}
+}
+
+func (c *Counterparty) GetAddress() (sdk.AccAddress, error) {
+	return c.chain.SenderAccount.GetAddress(), nil
+}
+
+func (c *Counterparty) LatestHeight() (ibcexported.Height, error) {
+	return c.height(c.chain.App.LastBlockHeight()), nil
+}
+
+func (c *Counterparty) GetLatestFinalizedHeader() (core.Header, error) {
+	return finalizedHeader{height: c.height(c.chain.App.LastBlockHeight())}, nil
+}
+
+func (c *Counterparty) QueryClientState(core.QueryContext) (*clienttypes.QueryClientStateResponse, error) {
+	clientState, found := c.chain.App.GetIBCKeeper().ClientKeeper.GetClientState(c.chain.GetContext(), c.clientID)
+	if !found {
+		return nil, fmt.Errorf("client state not found: client_id=%v", c.clientID)
+	}
+	anyClientState, err := clienttypes.PackClientState(clientState)
+	if err != nil {
+		return nil, err
+	}
+	return &clienttypes.QueryClientStateResponse{ClientState: anyClientState}, nil
+}
+
+func (c *Counterparty) QueryClientConsensusState(_ core.QueryContext, height ibcexported.Height) (*clienttypes.QueryConsensusStateResponse, error) {
+	consensusState, found := c.chain.GetConsensusState(c.clientID, height)
+	if !found {
+		return nil, fmt.Errorf("consensus state not found: client_id=%v height=%v", c.clientID, height)
+	}
+	anyConsensusState, err := clienttypes.PackConsensusState(consensusState)
+	if err != nil {
+		return nil, err
+	}
+	return &clienttypes.QueryConsensusStateResponse{ConsensusState: anyConsensusState}, nil
+}
+
+// SendMsgs delivers the msgs in a tx and commits a block including it
+func (c *Counterparty) SendMsgs(msgs []sdk.Msg) ([]core.MsgID, error) {
+	if _, err := c.deliver(msgs...); err != nil {
+		return nil, err
+	}
+	c.txs++
+	txID := fmt.Sprintf("%X", c.txs)
+	c.results[txID] = &msgResult{height: c.height(c.chain.App.LastBlockHeight())}
+	var ids []core.MsgID
+	for i := range msgs {
+		ids = append(ids, &tendermint.MsgID{TxHash: txID, MsgIndex: uint32(i)})
+	}
+	return ids, nil
+}
+
+func (c *Counterparty) GetMsgResult(id core.MsgID) (core.MsgResult, error) {
+	msgID, ok := id.(*tendermint.MsgID)
+	if !ok {
+		return nil, fmt.Errorf("unexpected msg ID type: %T", id)
+	}
+	res, ok := c.results[msgID.TxHash]
+	if !ok {
+		return nil, fmt.Errorf("msg not found: %v", msgID)
+	}
+	return res, nil
+}
+
+// ClientState returns the state of the LCP client
+func (c *Counterparty) ClientState() (*lcptypes.ClientState, error) {
+	clientState, found := c.chain.App.GetIBCKeeper().ClientKeeper.GetClientState(c.chain.GetContext(), c.clientID)
+	if !found {
+		return nil, fmt.Errorf("client state not found: client_id=%v", c.clientID)
+	}
+	cs, ok := clientState.(*lcptypes.ClientState)
+	if !ok {
+		return nil, fmt.Errorf("unexpected client state type: %T", clientState)
+	}
+	return cs, nil
+}
+
+// HasConsensusState returns true if the LCP client has the consensus state at `height`
+func (c *Counterparty) HasConsensusState(height ibcexported.Height) bool {
+	_, found := c.chain.GetConsensusState(c.clientID, height)
+	return found
+}
+
+// BlockTime returns the time of the current block
+func (c *Counterparty) BlockTime() time.Time {
+	return c.chain.CurrentHeader.Time
+}
+
+// ConsensusSigner returns the enclave key that signed the consensus state at `height`
+func (c *Counterparty) ConsensusSigner(height ibcexported.Height) (common.Address, bool) {
+	return lcptypes.GetConsensusSigner(c.clientStore(), height)
+}
+
+// EnclaveKeyInfo returns the registration of the enclave key, or nil if the key is not registered
+func (c *Counterparty) EnclaveKeyInfo(ek common.Address) (*lcptypes.EKInfo, error) {
+	cs, err := c.ClientState()
+	if err != nil {
+		return nil, err
+	}
+	return cs.GetEKInfo(c.clientStore(), ek)
+}
+
+// ExpireEnclaveKey overwrites the registration of the enclave key so that the key is expired at the next block
+func (c *Counterparty) ExpireEnclaveKey(ek common.Address) error {
+	cs, err := c.ClientState()
+	if err != nil {
+		return err
+	}
+	info, err := cs.GetEKInfo(c.clientStore(), ek)
+	if err != nil {
+		return err
+	} else if info == nil {
+		return fmt.Errorf("enclave key not found: %v", ek)
+	}
+	if err := cs.SetEKInfo(c.clientStore(), ek, info.Operator, c.chain.CurrentHeader.Time.Add(-time.Second)); err != nil {
+		return err
+	}
+	c.chain.NextBlock()
+	c.coord.IncrementTime()
+	return nil
+}
+
+// VerifyMembership verifies the commitment proof of `value` at `path` in the IBC store of the origin chain with the LCP client
+func (c *Counterparty) VerifyMembership(proofHeight ibcexported.Height, path string, value []byte, proof []byte) error {
+	cs, err := c.ClientState()
+	if err != nil {
+		return err
+	}
+	merklePath, err := commitmenttypes.ApplyPrefix(commitmenttypes.NewMerklePrefix([]byte(ibcexported.StoreKey)), commitmenttypes.NewMerklePath(path))
+	if err != nil {
+		return err
+	}
+	return cs.VerifyMembership(c.chain.GetContext(), c.clientStore(), c.chain.Codec, proofHeight, 0, 0, proof, merklePath, value)
+}
+
+// deliver delivers the msgs in a tx after the clock of the chain catches up with the wall clock
+func (c *Counterparty) deliver(msgs ...sdk.Msg) (*abci.ExecTxResult, error) {
+	if now := time.Now().UTC(); now.After(c.coord.CurrentTime) {
+		c.coord.CurrentTime = now
+	}
+	return c.chain.SendMsgs(msgs...)
+}
+
+func (c *Counterparty) clientStore() storetypes.KVStore {
+	return c.chain.App.GetIBCKeeper().ClientKeeper.ClientStore(c.chain.GetContext(), c.clientID)
+}
+
+func (c *Counterparty) height(blockHeight int64) clienttypes.Height {
+	return clienttypes.NewHeight(clienttypes.ParseChainID(c.chain.ChainID), uint64(blockHeight))
+}
+
+type finalizedHeader struct {
+	core.Header
+	height clienttypes.Height
+}
+
+func (h finalizedHeader) GetHeight() ibcexported.Height {
+	return h.height
+}
+
+type msgResult struct {
+	height clienttypes.Height
+}
+
+var _ core.MsgResult = (*msgResult)(nil)
+
+func (r *msgResult) BlockHeight() clienttypes.Height {
+	return r.height
+}
+
+// Status always returns true because SendMsgs fails if the tx fails
+func (r *msgResult) Status() (bool, string) {
+	return true, ""
+}
+
+func (r *msgResult) Events() []core.MsgEventLog {
+	return nil
+}
@@ -0,0 +1,67 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type StepStatus string
+
+const (
+	StepPassed  StepStatus = "pass"
+	StepFailed  StepStatus = "fail"
+	StepSkipped StepStatus = "skip"
+)
+
+// StepResult is the result of a step of the conformance suite
+type StepResult struct {
+	Step     string        `json:"step"`
+	Status   StepStatus    `json:"status"`
+	Duration time.Duration `json:"duration"`
+	// the reason of the failure or the skip
+	Message string `json:"message,omitempty"`
+}
+
+// Report is the result of the conformance suite
+type Report struct {
+	ServiceAddress string       `json:"service_address"`
+	OriginChainID  string       `json:"origin_chain_id"`
+	ELCClientID    string       `json:"elc_client_id"`
+	StartedAt      time.Time    `json:"started_at"`
+	Steps          []StepResult `json:"steps"`
+}
+
+// Passed returns true if no step has failed
+func (r *Report) Passed() bool {
+	for _, s := range r.Steps {
+		if s.Status == StepFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the per-step results as a table
+func (r *Report) String() string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STEP\tSTATUS\tDURATION\tMESSAGE")
+	for _, s := range r.Steps {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", s.Step, s.Status, s.Duration.Round(time.Millisecond), s.Message)
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// WriteFile writes the report as JSON
+func (r *Report) WriteFile(path string) error {
+	bz, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, bz, 0o644)
+}
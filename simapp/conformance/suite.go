@@ -0,0 +1,461 @@
+package conformance
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	connectiontypes "github.com/cosmos/ibc-go/v8/modules/core/03-connection/types"
+	ibcclient "github.com/cosmos/ibc-go/v8/modules/core/client"
+	"github.com/datachainlab/lcp-go/relay"
+	"github.com/datachainlab/lcp-go/relay/elc"
+	"github.com/datachainlab/lcp-go/relay/enclave"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hyperledger-labs/yui-relayer/chains/tendermint"
+	"github.com/hyperledger-labs/yui-relayer/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// the timeout of the RPC calls to the origin chain
+	originTimeout = 30 * time.Second
+	// the maximum time to wait for a new block of the origin chain
+	newOriginBlockTimeout = 30 * time.Second
+)
+
+// Suite runs the lifecycle of the LCP client against a live LCP service.
+// The LCP client is created on the in-process counterparty chain, and the on-chain state is asserted after each step.
+type Suite struct {
+	t      *testing.T
+	config *Config
+	home   string
+	codec  codec.ProtoCodecMarshaler
+
+	service      relay.LCPServiceClient
+	origin       *tendermint.Chain
+	prover       *relay.Prover
+	counterparty *Counterparty
+
+	// the enclave key registered in the LCP client
+	activeKey common.Address
+	// the latest height of the LCP client
+	latestHeight clienttypes.Height
+}
+
+// skipError means that the step is skipped
+type skipError struct {
+	reason string
+}
+
+func (e skipError) Error() string {
+	return e.reason
+}
+
+// NewSuite connects to the LCP service and the origin chain, and starts the counterparty chain
+func NewSuite(t *testing.T, config *Config) (*Suite, error) {
+	conn, err := grpc.Dial(config.ServiceAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the LCP service: %w", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	s := &Suite{
+		t:       t,
+		config:  config,
+		home:    t.TempDir(),
+		service: relay.NewLCPServiceClient(conn),
+	}
+	s.codec = core.MakeCodec()
+	tendermint.RegisterInterfaces(s.codec.InterfaceRegistry())
+	relay.RegisterInterfaces(s.codec.InterfaceRegistry())
+	s.prover, s.origin, err = s.newProver(config.KeyExpiration)
+	if err != nil {
+		return nil, err
+	}
+	s.counterparty = NewCounterparty(t)
+	return s, nil
+}
+
+// newProver returns a prover of the origin chain, which shares the home directory with the other provers of the suite.
+// `keyExpiration` is the key expiration known to the relayer, which determines the rotation time of the enclave key.
+func (s *Suite) newProver(keyExpiration uint64) (*relay.Prover, *tendermint.Chain, error) {
+	originChain, err := tendermint.ChainConfig{
+		Key:                  "testkey",
+		ChainId:              s.config.OriginChainID,
+		RpcAddr:              s.config.OriginRPCAddress,
+		AccountPrefix:        sdk.Bech32MainPrefix,
+		GasAdjustment:        1.5,
+		GasPrices:            "0.025stake",
+		AverageBlockTimeMsec: 1000,
+		MaxRetryForCommit:    5,
+	}.Build()
+	if err != nil {
+		return nil, nil, err
+	}
+	originProverConfig := &tendermint.ProverConfig{
+		TrustingPeriod:       "336h",
+		RefreshThresholdRate: &tendermint.Fraction{Numerator: 1, Denominator: 2},
+	}
+	originProver, err := originProverConfig.Build(originChain)
+	if err != nil {
+		return nil, nil, err
+	}
+	anyOriginProverConfig, err := codectypes.NewAnyWithValue(originProverConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	config := relay.ProverConfig{
+		OriginProver:          anyOriginProverConfig,
+		LcpServiceAddress:     s.config.ServiceAddress,
+		Mrenclave:             s.config.Mrenclave,
+		AllowedQuoteStatuses:  s.config.AllowedQuoteStatuses,
+		AllowedAdvisoryIds:    s.config.AllowedAdvisoryIDs,
+		KeyExpiration:         keyExpiration,
+		ElcClientId:           s.config.ELCClientID,
+		IsDebugEnclave:        true,
+		AllowDebugEnclaveKeys: true,
+	}
+	if err := config.Validate(); err != nil {
+		return nil, nil, err
+	}
+	prover, err := relay.NewProver(config, originChain, originProver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := prover.Init(s.home, originTimeout, s.codec, false); err != nil {
+		return nil, nil, err
+	}
+	if err := prover.SetRelayInfo(&core.PathEnd{ChainID: s.config.OriginChainID, ClientID: s.config.ELCClientID}, nil, nil); err != nil {
+		return nil, nil, err
+	}
+	return prover, originChain.(*tendermint.Chain), nil
+}
+
+// Run runs the steps in order and returns the report.
+// After a step fails, the remaining steps are skipped because they depend on the state made by the previous ones.
+func (s *Suite) Run() *Report {
+	report := &Report{
+		ServiceAddress: s.config.ServiceAddress,
+		OriginChainID:  s.config.OriginChainID,
+		ELCClientID:    s.config.ELCClientID,
+		StartedAt:      time.Now(),
+	}
+	steps := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{"select_enclave_key", s.selectEnclaveKey},
+		{"create_elc", s.createELC},
+		{"register_enclave_key", s.registerEnclaveKey},
+		{"update_client", s.updateClient},
+		{"prove_membership", s.proveMembership},
+		{"rotate_enclave_key", s.rotateEnclaveKey},
+	}
+	failed := false
+	for _, step := range steps {
+		result := StepResult{Step: step.name}
+		if failed {
+			result.Status, result.Message = StepSkipped, "a previous step failed"
+			report.Steps = append(report.Steps, result)
+			continue
+		}
+		s.t.Logf("run the step: %v", step.name)
+		start := time.Now()
+		err := step.run(context.TODO())
+		result.Duration = time.Since(start)
+		var skip skipError
+		switch {
+		case err == nil:
+			result.Status = StepPassed
+		case errors.As(err, &skip):
+			result.Status, result.Message = StepSkipped, skip.reason
+		default:
+			result.Status, result.Message = StepFailed, err.Error()
+			failed = true
+		}
+		report.Steps = append(report.Steps, result)
+	}
+	return report
+}
+
+// selectEnclaveKey checks that the LCP service provides enclave keys for the MRENCLAVE
+func (s *Suite) selectEnclaveKey(ctx context.Context) error {
+	keys, err := s.availableEnclaveKeys(ctx)
+	if err != nil {
+		return err
+	} else if len(keys) == 0 {
+		return fmt.Errorf("no available enclave keys: mrenclave=%v", s.config.Mrenclave)
+	}
+	return nil
+}
+
+// createELC creates the ELC in the LCP service and the LCP client on the counterparty chain
+func (s *Suite) createELC(ctx context.Context) error {
+	clientState, consensusState, err := s.prover.CreateInitialLightClientState(nil)
+	if err != nil {
+		return err
+	}
+	res, err := s.service.Client(ctx, &elc.QueryClientRequest{ClientId: s.config.ELCClientID})
+	if err != nil {
+		return err
+	} else if !res.Found {
+		return fmt.Errorf("ELC not found in the LCP service: elc_client_id=%v", s.config.ELCClientID)
+	}
+	clientID, err := s.counterparty.CreateClient(clientState, consensusState)
+	if err != nil {
+		return fmt.Errorf("failed to create the LCP client: %w", err)
+	}
+	s.t.Logf("the LCP client is created: client_id=%v", clientID)
+	cs, err := s.counterparty.ClientState()
+	if err != nil {
+		return err
+	}
+	if mrenclave := hex.EncodeToString(cs.Mrenclave); mrenclave != s.config.Mrenclave {
+		return fmt.Errorf("unexpected MRENCLAVE: expected=%v actual=%v", s.config.Mrenclave, mrenclave)
+	} else if cs.KeyExpiration != s.config.KeyExpiration {
+		return fmt.Errorf("unexpected key expiration: expected=%v actual=%v", s.config.KeyExpiration, cs.KeyExpiration)
+	}
+	return nil
+}
+
+// registerEnclaveKey registers an enclave key in the LCP client
+func (s *Suite) registerEnclaveKey(ctx context.Context) error {
+	if err := s.prover.UpdateEKIfNeeded(ctx, s.counterparty); err != nil {
+		return err
+	}
+	keys, err := s.registeredEnclaveKeys(ctx)
+	if err != nil {
+		return err
+	} else if len(keys) != 1 {
+		return fmt.Errorf("unexpected number of the registered enclave keys: expected=1 actual=%v", len(keys))
+	}
+	s.activeKey = keys[0]
+	s.t.Logf("the enclave key is registered: enclave_key=%v", s.activeKey)
+	return nil
+}
+
+// updateClient updates the LCP client to the latest height of the origin chain
+func (s *Suite) updateClient(ctx context.Context) error {
+	height, err := s.submitUpdates(s.prover, s.latestHeight)
+	if err != nil {
+		return err
+	}
+	if signer, found := s.counterparty.ConsensusSigner(height); !found {
+		return fmt.Errorf("consensus signer not found: height=%v", height)
+	} else if signer != s.activeKey {
+		return fmt.Errorf("unexpected consensus signer: expected=%v actual=%v", s.activeKey, signer)
+	}
+	s.latestHeight = height
+	return nil
+}
+
+// proveMembership verifies a commitment of the origin chain with the LCP client
+func (s *Suite) proveMembership(ctx context.Context) error {
+	path := connectiontypes.KeyNextConnectionSequence
+	// the proof of the state at `h` is verified with the consensus state at `h+1`
+	queryHeight := int64(s.latestHeight.GetRevisionHeight() - 1)
+	value, _, _, err := ibcclient.QueryTendermintProof(s.origin.CLIContext(queryHeight), []byte(path))
+	if err != nil {
+		return fmt.Errorf("failed to query the origin chain: path=%v %w", path, err)
+	}
+	queryCtx := core.NewQueryContext(ctx, clienttypes.NewHeight(s.latestHeight.GetRevisionNumber(), uint64(queryHeight)))
+	proof, proofHeight, err := s.prover.ProveState(queryCtx, path, value)
+	if err != nil {
+		return err
+	} else if !proofHeight.EQ(s.latestHeight) {
+		return fmt.Errorf("unexpected proof height: expected=%v actual=%v", s.latestHeight, proofHeight)
+	}
+	if err := s.counterparty.VerifyMembership(proofHeight, path, value, proof); err != nil {
+		return fmt.Errorf("failed to verify the membership with the LCP client: %w", err)
+	}
+	return nil
+}
+
+// rotateEnclaveKey expires the active enclave key on the chain, and checks that the prover rotates it to a new one
+func (s *Suite) rotateEnclaveKey(ctx context.Context) error {
+	if s.config.NewKeyCommand == "" {
+		return skipError{reason: fmt.Sprintf("%v is not set", EnvNewKeyCommand)}
+	}
+	if err := s.counterparty.ExpireEnclaveKey(s.activeKey); err != nil {
+		return err
+	}
+	if _, err := s.submitUpdates(s.prover, s.latestHeight); err == nil {
+		return fmt.Errorf("the update signed by the expired enclave key is accepted: enclave_key=%v", s.activeKey)
+	} else if !strings.Contains(err.Error(), "expired") {
+		return fmt.Errorf("the update signed by the expired enclave key failed with an unexpected error: %w", err)
+	}
+
+	// the attestation time of the new key must be later than the one of the active key
+	time.Sleep(2 * time.Second)
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.config.NewKeyCommand)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run the command to attest a new enclave key: %w", err)
+	}
+	keyExpiration, err := s.rotationKeyExpiration(ctx)
+	if err != nil {
+		return err
+	}
+	// the prover with the shorter key expiration regards the active key as expired, but not the new one
+	prover, _, err := s.newProver(keyExpiration)
+	if err != nil {
+		return err
+	}
+	if err := prover.UpdateEKIfNeeded(ctx, s.counterparty); err != nil {
+		return err
+	}
+	keys, err := s.registeredEnclaveKeys(ctx)
+	if err != nil {
+		return err
+	}
+	var newKey *common.Address
+	for _, k := range keys {
+		if k != s.activeKey {
+			newKey = &k
+		}
+	}
+	if newKey == nil {
+		return fmt.Errorf("no new enclave key is registered")
+	}
+	s.activeKey = *newKey
+	s.t.Logf("the enclave key is rotated: enclave_key=%v", s.activeKey)
+
+	height, err := s.submitUpdates(prover, s.latestHeight)
+	if err != nil {
+		return err
+	}
+	if signer, found := s.counterparty.ConsensusSigner(height); !found {
+		return fmt.Errorf("consensus signer not found: height=%v", height)
+	} else if signer != s.activeKey {
+		return fmt.Errorf("unexpected consensus signer: expected=%v actual=%v", s.activeKey, signer)
+	}
+	s.latestHeight = height
+	return nil
+}
+
+// rotationKeyExpiration returns the key expiration with which the rotation time of the active key has passed,
+// but the one of the latest key has not yet.
+func (s *Suite) rotationKeyExpiration(ctx context.Context) (uint64, error) {
+	keys, err := s.availableEnclaveKeys(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var active, latest uint64
+	for _, eki := range keys {
+		if common.BytesToAddress(eki.EnclaveKeyAddress) == s.activeKey {
+			active = eki.AttestationTime
+		}
+		if eki.AttestationTime > latest {
+			latest = eki.AttestationTime
+		}
+	}
+	if active == 0 {
+		return 0, fmt.Errorf("the active enclave key is not available in the LCP service: enclave_key=%v", s.activeKey)
+	} else if latest <= active {
+		return 0, fmt.Errorf("no enclave key is attested after the active one: attestation_time=%v", active)
+	}
+	// a key is rotated when a half of the expiration has elapsed since its attestation,
+	// so the rotation time of the active key is `now - (latest - active) / 2` and the one of the latest key is `now + (latest - active) / 2`
+	now := uint64(time.Now().Unix())
+	return (now - active) + (now - latest), nil
+}
+
+// submitUpdates submits the updates generated by `prover` to the LCP client, and returns the new latest height.
+// It waits for a new block of the origin chain if the LCP client is already updated to the latest one.
+func (s *Suite) submitUpdates(prover *relay.Prover, trusted clienttypes.Height) (clienttypes.Height, error) {
+	header, err := s.waitForOriginHeader(prover, trusted)
+	if err != nil {
+		return clienttypes.Height{}, err
+	}
+	headers, err := prover.SetupHeadersForUpdate(s.counterparty, header)
+	if err != nil {
+		return clienttypes.Height{}, err
+	} else if len(headers) == 0 {
+		return clienttypes.Height{}, fmt.Errorf("no updates are generated: height=%v", header.GetHeight())
+	}
+	signer, err := s.counterparty.GetAddress()
+	if err != nil {
+		return clienttypes.Height{}, err
+	}
+	var msgs []sdk.Msg
+	for _, h := range headers {
+		msg, err := relay.CosmosMessageEncoder{}.EncodeClientMessage(s.counterparty.Path().ClientID, signer, h)
+		if err != nil {
+			return clienttypes.Height{}, err
+		}
+		msgs = append(msgs, msg)
+	}
+	if _, err := s.counterparty.SendMsgs(msgs); err != nil {
+		return clienttypes.Height{}, err
+	}
+	cs, err := s.counterparty.ClientState()
+	if err != nil {
+		return clienttypes.Height{}, err
+	}
+	height := header.GetHeight().(clienttypes.Height)
+	if !cs.LatestHeight.EQ(height) {
+		return clienttypes.Height{}, fmt.Errorf("unexpected latest height of the LCP client: expected=%v actual=%v", height, cs.LatestHeight)
+	}
+	if !s.counterparty.HasConsensusState(height) {
+		return clienttypes.Height{}, fmt.Errorf("consensus state not found: height=%v", height)
+	}
+	return height, nil
+}
+
+// waitForOriginHeader returns the latest finalized header of the origin chain, which is higher than `height`
+func (s *Suite) waitForOriginHeader(prover *relay.Prover, height clienttypes.Height) (core.Header, error) {
+	deadline := time.Now().Add(newOriginBlockTimeout)
+	for {
+		header, err := prover.GetLatestFinalizedHeader()
+		if err != nil {
+			return nil, err
+		} else if header.GetHeight().GT(height) {
+			return header, nil
+		} else if time.Now().After(deadline) {
+			return nil, fmt.Errorf("no new block of the origin chain: height=%v", height)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func (s *Suite) availableEnclaveKeys(ctx context.Context) ([]*enclave.EnclaveKeyInfo, error) {
+	res, err := s.service.AvailableEnclaveKeys(ctx, &enclave.QueryAvailableEnclaveKeysRequest{Mrenclave: s.mrenclave()})
+	if err != nil {
+		return nil, err
+	}
+	return res.Keys, nil
+}
+
+// registeredEnclaveKeys returns the available keys in the LCP service that are registered in the LCP client and not expired
+func (s *Suite) registeredEnclaveKeys(ctx context.Context) ([]common.Address, error) {
+	keys, err := s.availableEnclaveKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var registered []common.Address
+	for _, eki := range keys {
+		ek := common.BytesToAddress(eki.EnclaveKeyAddress)
+		info, err := s.counterparty.EnclaveKeyInfo(ek)
+		if err != nil {
+			return nil, err
+		} else if info != nil && !info.IsExpired(s.counterparty.BlockTime()) {
+			registered = append(registered, ek)
+		}
+	}
+	return registered, nil
+}
+
+func (s *Suite) mrenclave() []byte {
+	bz, _ := hex.DecodeString(s.config.Mrenclave)
+	return bz
+}
@@ -0,0 +1,196 @@
+package simapp_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"cosmossdk.io/log"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dbm "github.com/cosmos/cosmos-db"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	commitmenttypes "github.com/cosmos/ibc-go/v8/modules/core/23-commitment/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	ibctm "github.com/cosmos/ibc-go/v8/modules/light-clients/07-tendermint"
+	"github.com/datachainlab/lcp-go/light-clients/lcp/testutil"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/datachainlab/lcp-go/sgx/dcap/dcaptest"
+	"github.com/datachainlab/lcp-go/simapp"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testLCPClientID   = "lcp-client-0"
+	testKeyExpiration = 3600 * 24
+)
+
+var testMrenclave = [32]byte{0x01}
+
+// setupLCPClient initializes an LCP client through the light client module of the simapp, and registers `key` with a DCAP attestation
+func setupLCPClient(t *testing.T, key *ecdsa.PrivateKey) (*simapp.SimApp, sdk.Context) {
+	app := simapp.NewSimApp(log.NewNopLogger(), dbm.NewMemDB(), nil, true, simtestutil.EmptyAppOptions{})
+	ctx := app.NewUncachedContext(false, cmtproto.Header{Time: time.Now().Truncate(time.Second).UTC()})
+	module := app.LCPLightClientModule
+
+	// the client does not exist yet
+	require.Equal(t, exported.Unknown, module.Status(ctx, testLCPClientID))
+	require.ErrorIs(t, module.VerifyClientMessage(ctx, testLCPClientID, &lcptypes.UpdateClientMessage{}), clienttypes.ErrClientNotFound)
+
+	setupLCPClientWithKey(t, app, ctx, testLCPClientID, key)
+	return app, ctx
+}
+
+// setupLCPClientWithKey initializes the LCP client `clientID` trusting `testMrenclave`, and registers `key` with a DCAP attestation issued before the block time of `ctx`
+func setupLCPClientWithKey(t *testing.T, app *simapp.SimApp, ctx sdk.Context, clientID string, key *ecdsa.PrivateKey) {
+	f := dcaptest.NewFixture(t, dcaptest.Params{Mrenclave: testMrenclave, EnclaveKey: crypto.PubkeyToAddress(key.PublicKey), IssueDate: ctx.BlockTime().Add(-time.Minute)})
+	initializeLCPClient(t, app, ctx, clientID, &lcptypes.ClientState{
+		Mrenclave:     testMrenclave[:],
+		KeyExpiration: testKeyExpiration,
+		DcapRootCerts: [][]byte{f.RootCert},
+	})
+	updateLCPClient(t, app, ctx, clientID, testutil.NewDCAPRegisterEnclaveKeyMessage(f))
+}
+
+// initializeLCPClient initializes the LCP client `clientID` with `clientState` through the light client module of the simapp
+func initializeLCPClient(t *testing.T, app *simapp.SimApp, ctx sdk.Context, clientID string, clientState *lcptypes.ClientState) {
+	require := require.New(t)
+	module := app.LCPLightClientModule
+	clientStateBz, err := app.AppCodec().Marshal(clientState)
+	require.NoError(err)
+	consensusStateBz, err := app.AppCodec().Marshal(&lcptypes.ConsensusState{})
+	require.NoError(err)
+	require.NoError(module.Initialize(ctx, clientID, clientStateBz, consensusStateBz))
+	require.Equal(exported.Active, module.Status(ctx, clientID))
+	require.Equal(clienttypes.ZeroHeight(), module.LatestHeight(ctx, clientID))
+}
+
+// updateLCPClient verifies `msg` and updates the client `clientID` through the light client module of the simapp
+func updateLCPClient(t *testing.T, app *simapp.SimApp, ctx sdk.Context, clientID string, msg exported.ClientMessage) {
+	module := app.LCPLightClientModule
+	require.NoError(t, module.VerifyClientMessage(ctx, clientID, msg))
+	require.False(t, module.CheckForMisbehaviour(ctx, clientID, msg))
+	module.UpdateState(ctx, clientID, msg)
+}
+
+func TestLCPLightClientModule(t *testing.T) {
+	require := require.New(t)
+	key := testutil.TestEnclaveKey(t)
+	app, ctx := setupLCPClient(t, key)
+	module := app.LCPLightClientModule
+	require.Equal(lcptypes.ClientTypeLCP, module.ClientType())
+
+	// update the client with the registered key
+	height := clienttypes.NewHeight(0, 1)
+	updateLCPClient(t, app, ctx, testLCPClientID, testutil.NewInitialUpdateClientMessage(t, height, ctx.BlockTime(), key))
+	require.Equal(height, module.LatestHeight(ctx, testLCPClientID))
+	timestamp, err := module.TimestampAtHeight(ctx, testLCPClientID, height)
+	require.NoError(err)
+	require.Equal(uint64(ctx.BlockTime().UnixNano()), timestamp)
+
+	// verify the membership with the updated client
+	path := "commitments/ports/transfer/channels/channel-0/sequences/1"
+	value := []byte("commitment")
+	merklePath := commitmenttypes.NewMerklePath(exported.StoreKey, path)
+	require.NoError(module.VerifyMembership(ctx, testLCPClientID, height, 0, 0, testutil.NewCommitmentProof(t, height, path, value, key), merklePath, value))
+	require.Error(module.VerifyMembership(ctx, testLCPClientID, height, 0, 0, testutil.NewCommitmentProof(t, height, path, value, key), merklePath, []byte("other")))
+	require.NoError(module.VerifyNonMembership(ctx, testLCPClientID, height, 0, 0, testutil.NewNonMembershipCommitmentProof(t, height, path, key), merklePath))
+}
+
+func TestLCPLightClientModuleRecoverClient(t *testing.T) {
+	const substituteClientID = "lcp-client-1"
+	key := testutil.TestEnclaveKey(t)
+	substituteKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	height, substituteHeight := clienttypes.NewHeight(0, 1), clienttypes.NewHeight(0, 5)
+
+	// freezeLCPClient updates the subject client and freezes it by a misbehaviour signed by `key`
+	freezeLCPClient := func(t *testing.T, app *simapp.SimApp, ctx sdk.Context) {
+		module := app.LCPLightClientModule
+		updateLCPClient(t, app, ctx, testLCPClientID, testutil.NewInitialUpdateClientMessage(t, height, ctx.BlockTime(), key))
+		misbehaviour := testutil.NewMisbehaviourMessage(t, []clienttypes.Height{height}, key)
+		require.NoError(t, module.VerifyClientMessage(ctx, testLCPClientID, misbehaviour))
+		require.True(t, module.CheckForMisbehaviour(ctx, testLCPClientID, misbehaviour))
+		module.UpdateStateOnMisbehaviour(ctx, testLCPClientID, misbehaviour)
+		require.Equal(t, exported.Frozen, module.Status(ctx, testLCPClientID))
+	}
+	setupFrozenLCPClient := func(t *testing.T) (*simapp.SimApp, sdk.Context) {
+		app, ctx := setupLCPClient(t, key)
+		freezeLCPClient(t, app, ctx)
+		return app, ctx
+	}
+
+	t.Run("active substitute", func(t *testing.T) {
+		require := require.New(t)
+		app, ctx := setupLCPClient(t, key)
+		module := app.LCPLightClientModule
+		setupLCPClientWithKey(t, app, ctx, substituteClientID, substituteKey)
+		updateLCPClient(t, app, ctx, substituteClientID, testutil.NewInitialUpdateClientMessage(t, substituteHeight, ctx.BlockTime(), substituteKey))
+
+		// the subject is not frozen
+		require.ErrorIs(module.RecoverClient(ctx, testLCPClientID, substituteClientID), clienttypes.ErrInvalidSubstitute)
+
+		freezeLCPClient(t, app, ctx)
+		require.NoError(module.RecoverClient(ctx, testLCPClientID, substituteClientID))
+		require.Equal(exported.Active, module.Status(ctx, testLCPClientID))
+		require.Equal(substituteHeight, module.LatestHeight(ctx, testLCPClientID))
+		timestamp, err := module.TimestampAtHeight(ctx, testLCPClientID, substituteHeight)
+		require.NoError(err)
+		require.Equal(uint64(ctx.BlockTime().UnixNano()), timestamp)
+
+		// the key of the subject may have signed the misbehaviour, so only the key of the substitute is trusted
+		next := clienttypes.NewHeight(0, 6)
+		require.Error(module.VerifyClientMessage(ctx, testLCPClientID, testutil.NewUpdateClientMessage(t, substituteHeight, next, ctx.BlockTime(), key)))
+		updateLCPClient(t, app, ctx, testLCPClientID, testutil.NewUpdateClientMessage(t, substituteHeight, next, ctx.BlockTime(), substituteKey))
+		require.Equal(next, module.LatestHeight(ctx, testLCPClientID))
+	})
+
+	t.Run("mismatched substitute", func(t *testing.T) {
+		operator, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		for _, c := range []struct {
+			name        string
+			clientState *lcptypes.ClientState
+			err         string
+		}{
+			{"mrenclave", &lcptypes.ClientState{Mrenclave: bytes.Repeat([]byte{0x02}, lcptypes.MrenclaveSize), KeyExpiration: testKeyExpiration}, "mrenclave mismatch"},
+			{"key_expiration", &lcptypes.ClientState{Mrenclave: testMrenclave[:], KeyExpiration: 2 * testKeyExpiration}, "key expiration mismatch"},
+			{"operators", &lcptypes.ClientState{
+				Mrenclave:                     testMrenclave[:],
+				KeyExpiration:                 testKeyExpiration,
+				Operators:                     [][]byte{crypto.PubkeyToAddress(operator.PublicKey).Bytes()},
+				OperatorsThresholdNumerator:   1,
+				OperatorsThresholdDenominator: 1,
+			}, "operators mismatch"},
+		} {
+			t.Run(c.name, func(t *testing.T) {
+				require := require.New(t)
+				app, ctx := setupFrozenLCPClient(t)
+				module := app.LCPLightClientModule
+				initializeLCPClient(t, app, ctx, substituteClientID, c.clientState)
+				err := module.RecoverClient(ctx, testLCPClientID, substituteClientID)
+				require.ErrorIs(err, clienttypes.ErrInvalidSubstitute)
+				require.ErrorContains(err, c.err)
+				require.Equal(exported.Frozen, module.Status(ctx, testLCPClientID))
+			})
+		}
+	})
+
+	t.Run("non-LCP substitute", func(t *testing.T) {
+		require := require.New(t)
+		app, ctx := setupFrozenLCPClient(t)
+		module := app.LCPLightClientModule
+		const tmClientID = "07-tendermint-0"
+		app.IBCKeeper.ClientKeeper.SetClientState(ctx, tmClientID, &ibctm.ClientState{ChainId: "tm-chain", LatestHeight: substituteHeight})
+		err := module.RecoverClient(ctx, testLCPClientID, tmClientID)
+		require.ErrorIs(err, clienttypes.ErrInvalidClient)
+		require.ErrorContains(err, "invalid client type")
+		require.Equal(exported.Frozen, module.Status(ctx, testLCPClientID))
+
+		// the substitute does not exist
+		require.ErrorIs(module.RecoverClient(ctx, testLCPClientID, "lcp-client-9"), clienttypes.ErrClientNotFound)
+	})
+}
@@ -0,0 +1,76 @@
+package simapp_test
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	clienttypes "github.com/cosmos/ibc-go/v8/modules/core/02-client/types"
+	"github.com/cosmos/ibc-go/v8/modules/core/exported"
+	"github.com/datachainlab/lcp-go/light-clients/lcp/testutil"
+	lcptypes "github.com/datachainlab/lcp-go/light-clients/lcp/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLCPQueryServer(t *testing.T) {
+	require := require.New(t)
+	key := testutil.TestEnclaveKey(t)
+	app, ctx := setupLCPClient(t, key)
+	height := clienttypes.NewHeight(0, 1)
+	updateLCPClient(t, app, ctx, testLCPClientID, testutil.NewInitialUpdateClientMessage(t, height, ctx.BlockTime(), key))
+	queryClient := lcptypes.NewQueryClient(&baseapp.QueryServiceTestHelper{GRPCQueryRouter: app.GRPCQueryRouter(), Ctx: ctx})
+	stateID := testutil.StateIDAt(height)
+
+	// ConsensusStateID
+	res, err := queryClient.ConsensusStateID(ctx, &lcptypes.QueryConsensusStateIDRequest{ClientId: testLCPClientID, Height: height})
+	require.NoError(err)
+	require.Equal(stateID[:], res.StateId)
+	require.Equal(uint64(ctx.BlockTime().UnixNano()), res.Timestamp)
+	_, err = queryClient.ConsensusStateID(ctx, &lcptypes.QueryConsensusStateIDRequest{ClientId: testLCPClientID, Height: clienttypes.NewHeight(0, 2)})
+	require.Equal(codes.NotFound, status.Code(err))
+	_, err = queryClient.ConsensusStateID(ctx, &lcptypes.QueryConsensusStateIDRequest{ClientId: "lcp-client-1", Height: height})
+	require.Equal(codes.NotFound, status.Code(err))
+	_, err = queryClient.ConsensusStateID(ctx, &lcptypes.QueryConsensusStateIDRequest{ClientId: "07-tendermint-0", Height: height})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	// VerifyCommitmentProof
+	path := "commitments/ports/transfer/channels/channel-0/sequences/1"
+	value := []byte("commitment")
+	proofRes, err := queryClient.VerifyCommitmentProof(ctx, &lcptypes.QueryVerifyCommitmentProofRequest{ClientId: testLCPClientID, Proof: testutil.NewCommitmentProof(t, height, path, value, key)})
+	require.NoError(err)
+	require.Equal([]byte(exported.StoreKey), proofRes.Prefix)
+	require.Equal([]byte(path), proofRes.Path)
+	require.Equal(crypto.Keccak256(value), proofRes.Value)
+	require.Equal(height, proofRes.Height)
+	require.Equal(stateID[:], proofRes.StateId)
+
+	// the absence of the value
+	proofRes, err = queryClient.VerifyCommitmentProof(ctx, &lcptypes.QueryVerifyCommitmentProofRequest{ClientId: testLCPClientID, Proof: testutil.NewNonMembershipCommitmentProof(t, height, path, key)})
+	require.NoError(err)
+	require.Equal(make([]byte, 32), proofRes.Value)
+
+	// the proof must be signed by the registered key
+	other, err := crypto.GenerateKey()
+	require.NoError(err)
+	_, err = queryClient.VerifyCommitmentProof(ctx, &lcptypes.QueryVerifyCommitmentProofRequest{ClientId: testLCPClientID, Proof: testutil.NewCommitmentProof(t, height, path, value, other)})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	// the consensus state at the height of the proof must exist
+	_, err = queryClient.VerifyCommitmentProof(ctx, &lcptypes.QueryVerifyCommitmentProofRequest{ClientId: testLCPClientID, Proof: testutil.NewCommitmentProof(t, clienttypes.NewHeight(0, 2), path, value, key)})
+	require.Equal(codes.NotFound, status.Code(err))
+
+	_, err = queryClient.VerifyCommitmentProof(ctx, &lcptypes.QueryVerifyCommitmentProofRequest{ClientId: testLCPClientID, Proof: []byte("invalid")})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+
+	// OperatorsNonce
+	nonceRes, err := queryClient.OperatorsNonce(ctx, &lcptypes.QueryOperatorsNonceRequest{ClientId: testLCPClientID})
+	require.NoError(err)
+	require.Equal(uint64(0), nonceRes.Nonce)
+	require.Equal(uint64(1), nonceRes.NextNonce)
+	_, err = queryClient.OperatorsNonce(ctx, &lcptypes.QueryOperatorsNonceRequest{ClientId: "lcp-client-1"})
+	require.Equal(codes.NotFound, status.Code(err))
+	_, err = queryClient.OperatorsNonce(ctx, &lcptypes.QueryOperatorsNonceRequest{ClientId: "07-tendermint-0"})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
@@ -27,6 +27,7 @@
     "key_expiration": 604800,
     "elc_client_id": "07-tendermint-1",
     "is_debug_enclave": true,
+    "allow_debug_enclave_keys": true,
     "operators": [
       "0xcb96F8d6C2d543102184d679D7829b39434E4EEc"
     ],
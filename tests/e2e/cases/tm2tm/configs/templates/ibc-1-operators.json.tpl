@@ -27,6 +27,7 @@
     "key_expiration": 604800,
     "elc_client_id": "07-tendermint-0",
     "is_debug_enclave": true,
+    "allow_debug_enclave_keys": true,
     "operators": [
       "0x9722414d09f43fb02235d739B50F4C027F43e657"
     ],
@@ -26,6 +26,7 @@
     "allowed_advisory_ids": ["INTEL-SA-00219","INTEL-SA-00289","INTEL-SA-00334","INTEL-SA-00477","INTEL-SA-00614","INTEL-SA-00615","INTEL-SA-00617"],
     "key_expiration": 604800,
     "elc_client_id": "07-tendermint-0",
-    "is_debug_enclave": true
+    "is_debug_enclave": true,
+    "allow_debug_enclave_keys": true
   }
 }